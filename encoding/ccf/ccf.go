@@ -0,0 +1,182 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ccf implements the Cadence Compact Format (CCF),
+// a deterministic binary encoding of Cadence values and types based on CBOR (RFC 8949).
+//
+// Unlike JSON-Cadence, which repeats the type information of composite values
+// on every nested value, a CCF message declares every composite and interface type once,
+// and values refer to the declared types by their index:
+//
+//	message     = [type-defs, value]
+//	type-defs   = [* type-def]
+//	type-def    = [kind: uint, type-id: tstr, fields, initializers, raw-type: type]
+//	fields      = [* [identifier: tstr, type]]
+//	initializers = [* [* parameter]]
+//	parameter   = [label: tstr, identifier: tstr, type]
+//
+// Types are either null (no type), a uint code of a simple type (e.g. Int),
+// or one of the tagged type encodings (see the type tags below).
+//
+// Values are always tagged with their kind (see the value tags below).
+// Composite values (structs, resources, events, contracts and enums)
+// are encoded as the index of their type definition and their non-function field values,
+// in the order of the fields of the type definition.
+//
+// The encoding is deterministic: type definitions are emitted in the order they are first
+// encountered, integers use their shortest form, and indefinite-length items are not used.
+// The decoder rejects any message that is not in this canonical form.
+package ccf
+
+import (
+	"github.com/onflow/cadence"
+)
+
+// Value tags.
+//
+// NOTE: only append new tags, never change or remove existing tags
+const (
+	tagVoidValue uint64 = 128 + iota
+	tagOptionalValue
+	tagBoolValue
+	tagCharacterValue
+	tagStringValue
+	tagAddressValue
+	tagIntValue
+	tagInt8Value
+	tagInt16Value
+	tagInt32Value
+	tagInt64Value
+	tagInt128Value
+	tagInt256Value
+	tagUIntValue
+	tagUInt8Value
+	tagUInt16Value
+	tagUInt32Value
+	tagUInt64Value
+	tagUInt128Value
+	tagUInt256Value
+	tagWord8Value
+	tagWord16Value
+	tagWord32Value
+	tagWord64Value
+	tagFix64Value
+	tagUFix64Value
+	tagArrayValue
+	tagDictionaryValue
+	tagCompositeValue
+	tagLinkValue
+	tagPathValue
+	tagTypeValue
+	tagCapabilityValue
+)
+
+// Type tags.
+//
+// NOTE: only append new tags, never change or remove existing tags
+const (
+	tagOptionalType uint64 = 192 + iota
+	tagVariableSizedArrayType
+	tagConstantSizedArrayType
+	tagDictionaryType
+	tagReferenceType
+	tagRestrictedType
+	tagCapabilityType
+	tagFunctionType
+	tagTypeDefRef
+)
+
+// Kinds of type definitions.
+//
+// NOTE: only append new kinds, never change or remove existing kinds
+const (
+	typeDefKindStruct uint64 = iota
+	typeDefKindResource
+	typeDefKindEvent
+	typeDefKindContract
+	typeDefKindEnum
+	typeDefKindStructInterface
+	typeDefKindResourceInterface
+	typeDefKindContractInterface
+)
+
+// simpleTypes are the types which have no type parameters or definitions.
+// They are encoded as their index in this list.
+//
+// NOTE: only append new types, never change the order or remove existing types
+var simpleTypes = []cadence.Type{
+	cadence.AnyType{},
+	cadence.AnyStructType{},
+	cadence.AnyResourceType{},
+	cadence.AddressType{},
+	cadence.MetaType{},
+	cadence.VoidType{},
+	cadence.NeverType{},
+	cadence.BoolType{},
+	cadence.StringType{},
+	cadence.CharacterType{},
+	cadence.BytesType{},
+	cadence.NumberType{},
+	cadence.SignedNumberType{},
+	cadence.IntegerType{},
+	cadence.SignedIntegerType{},
+	cadence.FixedPointType{},
+	cadence.SignedFixedPointType{},
+	cadence.IntType{},
+	cadence.Int8Type{},
+	cadence.Int16Type{},
+	cadence.Int32Type{},
+	cadence.Int64Type{},
+	cadence.Int128Type{},
+	cadence.Int256Type{},
+	cadence.UIntType{},
+	cadence.UInt8Type{},
+	cadence.UInt16Type{},
+	cadence.UInt32Type{},
+	cadence.UInt64Type{},
+	cadence.UInt128Type{},
+	cadence.UInt256Type{},
+	cadence.Word8Type{},
+	cadence.Word16Type{},
+	cadence.Word32Type{},
+	cadence.Word64Type{},
+	cadence.Fix64Type{},
+	cadence.UFix64Type{},
+	cadence.BlockType{},
+	cadence.PathType{},
+	cadence.CapabilityPathType{},
+	cadence.StoragePathType{},
+	cadence.PublicPathType{},
+	cadence.PrivatePathType{},
+	cadence.AccountKeyType{},
+	cadence.AuthAccountContractsType{},
+	cadence.AuthAccountKeysType{},
+	cadence.AuthAccountType{},
+	cadence.PublicAccountContractsType{},
+	cadence.PublicAccountKeysType{},
+	cadence.PublicAccountType{},
+	cadence.DeployedContractType{},
+}
+
+var simpleTypeCodes = func() map[cadence.Type]uint64 {
+	codes := make(map[cadence.Type]uint64, len(simpleTypes))
+	for i, ty := range simpleTypes {
+		codes[ty] = uint64(i)
+	}
+	return codes
+}()
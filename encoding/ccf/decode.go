@@ -0,0 +1,922 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ccf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	goRuntime "runtime"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+var decMode = func() cbor.DecMode {
+	decMode, err := cbor.DecOptions{
+		IntDec:           cbor.IntDecConvertNone,
+		MaxArrayElements: math.MaxInt64,
+		MaxMapPairs:      math.MaxInt64,
+		MaxNestedLevels:  math.MaxInt16,
+		IndefLength:      cbor.IndefLengthForbidden,
+	}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+	return decMode
+}()
+
+// ErrNonCanonicalEncoding is returned when the decoded data is not the canonical encoding of the value
+var ErrNonCanonicalEncoding = errors.New("encoding is not canonical")
+
+var fixedPointMemoryUsage = common.NewCadenceNumberMemoryUsage(8)
+
+// A Decoder decodes CCF-encoded representations of Cadence values.
+type Decoder struct {
+	data     []byte
+	dec      *cbor.StreamDecoder
+	gauge    common.MemoryGauge
+	typeDefs []cadence.Type
+}
+
+// Decode returns a Cadence value decoded from its CCF-encoded representation.
+//
+// This function returns an error if the bytes represent CCF that is malformed,
+// not canonical, or does not conform to the CCF specification.
+func Decode(gauge common.MemoryGauge, b []byte) (cadence.Value, error) {
+	return NewDecoder(gauge, b).Decode()
+}
+
+// NewDecoder initializes a Decoder that will decode CCF-encoded bytes from the
+// given byte slice.
+func NewDecoder(gauge common.MemoryGauge, b []byte) *Decoder {
+	return &Decoder{
+		data:  b,
+		gauge: gauge,
+	}
+}
+
+// Decode decodes the CCF-encoded bytes to a Cadence value.
+//
+// This function returns an error if the bytes represent CCF that is malformed,
+// not canonical, or does not conform to the CCF specification.
+func (d *Decoder) Decode() (value cadence.Value, err error) {
+	// capture panics that occur during decoding
+	defer func() {
+		if r := recover(); r != nil {
+			// don't recover Go errors
+			goErr, ok := r.(goRuntime.Error)
+			if ok {
+				panic(goErr)
+			}
+
+			panicErr, isError := r.(error)
+			if !isError {
+				panic(r)
+			}
+
+			err = fmt.Errorf("failed to decode value: %w", panicErr)
+		}
+	}()
+
+	// Declare all defined types before decoding their definitions,
+	// so that type definitions can refer to each other
+
+	d.typeDefs = d.declareTypeDefs()
+
+	d.dec = decMode.NewByteStreamDecoder(d.data)
+
+	d.decodeArrayHead(2)
+	d.decodeTypeDefs()
+	value = d.decodeValue()
+
+	if d.dec.NumBytesDecoded() != len(d.data) {
+		panic(errors.New("unexpected trailing data"))
+	}
+
+	// Only accept the canonical encoding of the value
+
+	encoded, err := Encode(value)
+	if err != nil {
+		panic(err)
+	}
+	if !bytes.Equal(encoded, d.data) {
+		panic(ErrNonCanonicalEncoding)
+	}
+
+	return value, nil
+}
+
+func (d *Decoder) decodeArrayHead(expectedSize uint64) {
+	size, err := d.dec.DecodeArrayHead()
+	check(err)
+	if size != expectedSize {
+		panic(fmt.Errorf("invalid array size: expected %d, got %d", expectedSize, size))
+	}
+}
+
+func (d *Decoder) decodeArrayHeadAnySize() uint64 {
+	size, err := d.dec.DecodeArrayHead()
+	check(err)
+	return size
+}
+
+func (d *Decoder) decodeString() string {
+	s, err := d.dec.DecodeString()
+	check(err)
+	return s
+}
+
+func (d *Decoder) decodeUint64() uint64 {
+	v, err := d.dec.DecodeUint64()
+	check(err)
+	return v
+}
+
+func (d *Decoder) decodeInt64() int64 {
+	v, err := d.dec.DecodeInt64()
+	check(err)
+	return v
+}
+
+func (d *Decoder) decodeIntInRange(min, max int64) int64 {
+	v := d.decodeInt64()
+	if v < min || v > max {
+		panic(fmt.Errorf("integer out of range: %d", v))
+	}
+	return v
+}
+
+func (d *Decoder) decodeUintInRange(max uint64) uint64 {
+	v := d.decodeUint64()
+	if v > max {
+		panic(fmt.Errorf("integer out of range: %d", v))
+	}
+	return v
+}
+
+func (d *Decoder) decodeBigInt() *big.Int {
+	nextType, err := d.dec.NextType()
+	check(err)
+
+	switch nextType {
+	case cbor.UintType:
+		return new(big.Int).SetUint64(d.decodeUint64())
+
+	case cbor.IntType:
+		return big.NewInt(d.decodeInt64())
+
+	case cbor.BigNumType:
+		v, err := d.dec.DecodeBigInt()
+		check(err)
+		return v
+
+	default:
+		panic(fmt.Errorf("invalid integer encoding: %s", nextType))
+	}
+}
+
+func (d *Decoder) nextIsNil() bool {
+	nextType, err := d.dec.NextType()
+	check(err)
+	if nextType != cbor.NilType {
+		return false
+	}
+	check(d.dec.DecodeNil())
+	return true
+}
+
+// declareTypeDefs decodes the kinds and IDs of the type definitions,
+// and returns the declared types, without their fields and initializers
+func (d *Decoder) declareTypeDefs() []cadence.Type {
+	dec := decMode.NewByteStreamDecoder(d.data)
+
+	size, err := dec.DecodeArrayHead()
+	check(err)
+	if size != 2 {
+		panic(fmt.Errorf("invalid message size: %d", size))
+	}
+
+	count, err := dec.DecodeArrayHead()
+	check(err)
+
+	// Each type definition is encoded in at least one byte
+	if count > uint64(len(d.data)) {
+		panic(fmt.Errorf("invalid type definition count: %d", count))
+	}
+
+	typeDefs := make([]cadence.Type, 0, count)
+	typeIDs := make(map[string]struct{}, count)
+
+	for i := uint64(0); i < count; i++ {
+		size, err := dec.DecodeArrayHead()
+		check(err)
+		if size != 5 {
+			panic(fmt.Errorf("invalid type definition size: %d", size))
+		}
+
+		kind, err := dec.DecodeUint64()
+		check(err)
+
+		typeID, err := dec.DecodeString()
+		check(err)
+
+		if _, ok := typeIDs[typeID]; ok {
+			panic(fmt.Errorf("duplicate type definition: %s", typeID))
+		}
+		typeIDs[typeID] = struct{}{}
+
+		typeDefs = append(typeDefs, d.declareTypeDef(kind, typeID))
+
+		// Skip fields, initializers, and raw type
+		for j := 0; j < 3; j++ {
+			check(dec.Skip())
+		}
+	}
+
+	return typeDefs
+}
+
+func (d *Decoder) declareTypeDef(kind uint64, typeID string) cadence.Type {
+	location, qualifiedIdentifier, err := common.DecodeTypeID(d.gauge, typeID)
+	if err != nil ||
+		location == nil && sema.NativeCompositeTypes[typeID] == nil {
+
+		panic(fmt.Errorf("invalid type ID: `%s`", typeID))
+	}
+
+	switch kind {
+	case typeDefKindStruct:
+		return cadence.NewMeteredStructType(d.gauge, location, qualifiedIdentifier, nil, nil)
+	case typeDefKindResource:
+		return cadence.NewMeteredResourceType(d.gauge, location, qualifiedIdentifier, nil, nil)
+	case typeDefKindEvent:
+		return cadence.NewMeteredEventType(d.gauge, location, qualifiedIdentifier, nil, nil)
+	case typeDefKindContract:
+		return cadence.NewMeteredContractType(d.gauge, location, qualifiedIdentifier, nil, nil)
+	case typeDefKindEnum:
+		return cadence.NewMeteredEnumType(d.gauge, location, qualifiedIdentifier, nil, nil, nil)
+	case typeDefKindStructInterface:
+		return cadence.NewMeteredStructInterfaceType(d.gauge, location, qualifiedIdentifier, nil, nil)
+	case typeDefKindResourceInterface:
+		return cadence.NewMeteredResourceInterfaceType(d.gauge, location, qualifiedIdentifier, nil, nil)
+	case typeDefKindContractInterface:
+		return cadence.NewMeteredContractInterfaceType(d.gauge, location, qualifiedIdentifier, nil, nil)
+	default:
+		panic(fmt.Errorf("invalid type definition kind: %d", kind))
+	}
+}
+
+// decodeTypeDefs decodes the fields, initializers and raw types
+// of the previously declared type definitions
+func (d *Decoder) decodeTypeDefs() {
+	d.decodeArrayHead(uint64(len(d.typeDefs)))
+
+	for _, typeDef := range d.typeDefs {
+		d.decodeArrayHead(5)
+
+		// kind and type ID were already decoded when the type was declared
+		check(d.dec.Skip())
+		check(d.dec.Skip())
+
+		fields := d.decodeFields()
+		initializers := d.decodeInitializers()
+		rawType := d.decodeType()
+
+		if rawType != nil {
+			if _, ok := typeDef.(*cadence.EnumType); !ok {
+				panic(fmt.Errorf("unexpected raw type for %s", typeDef.ID()))
+			}
+		}
+
+		switch typeDef := typeDef.(type) {
+		case *cadence.StructType:
+			typeDef.Fields = fields
+			typeDef.Initializers = initializers
+
+		case *cadence.ResourceType:
+			typeDef.Fields = fields
+			typeDef.Initializers = initializers
+
+		case *cadence.EventType:
+			if len(initializers) != 1 {
+				panic(fmt.Errorf("invalid initializer count for event %s: %d", typeDef.ID(), len(initializers)))
+			}
+			typeDef.Fields = fields
+			typeDef.Initializer = initializers[0]
+
+		case *cadence.ContractType:
+			typeDef.Fields = fields
+			typeDef.Initializers = initializers
+
+		case *cadence.EnumType:
+			typeDef.Fields = fields
+			typeDef.Initializers = initializers
+			typeDef.RawType = rawType
+
+		case *cadence.StructInterfaceType:
+			typeDef.Fields = fields
+			typeDef.Initializers = initializers
+
+		case *cadence.ResourceInterfaceType:
+			typeDef.Fields = fields
+			typeDef.Initializers = initializers
+
+		case *cadence.ContractInterfaceType:
+			typeDef.Fields = fields
+			typeDef.Initializers = initializers
+		}
+	}
+}
+
+func (d *Decoder) decodeFields() []cadence.Field {
+	count := d.decodeArrayHeadAnySize()
+	if count == 0 {
+		return nil
+	}
+
+	common.UseMemory(d.gauge, common.MemoryUsage{
+		Kind:   common.MemoryKindCadenceField,
+		Amount: count,
+	})
+
+	fields := make([]cadence.Field, 0, count)
+	for i := uint64(0); i < count; i++ {
+		d.decodeArrayHead(2)
+		identifier := d.decodeString()
+		fieldType := d.decodeType()
+		fields = append(fields, cadence.NewField(identifier, fieldType))
+	}
+	return fields
+}
+
+func (d *Decoder) decodeInitializers() [][]cadence.Parameter {
+	count := d.decodeArrayHeadAnySize()
+	if count == 0 {
+		return nil
+	}
+
+	initializers := make([][]cadence.Parameter, 0, count)
+	for i := uint64(0); i < count; i++ {
+		initializers = append(initializers, d.decodeParameters())
+	}
+	return initializers
+}
+
+func (d *Decoder) decodeParameters() []cadence.Parameter {
+	count := d.decodeArrayHeadAnySize()
+	if count == 0 {
+		return nil
+	}
+
+	common.UseMemory(d.gauge, common.MemoryUsage{
+		Kind:   common.MemoryKindCadenceParameter,
+		Amount: count,
+	})
+
+	parameters := make([]cadence.Parameter, 0, count)
+	for i := uint64(0); i < count; i++ {
+		d.decodeArrayHead(3)
+		label := d.decodeString()
+		identifier := d.decodeString()
+		parameterType := d.decodeType()
+		parameters = append(parameters, cadence.NewParameter(label, identifier, parameterType))
+	}
+	return parameters
+}
+
+func (d *Decoder) decodeNonNilType() cadence.Type {
+	typ := d.decodeType()
+	if typ == nil {
+		panic(errors.New("missing type"))
+	}
+	return typ
+}
+
+func (d *Decoder) decodeType() cadence.Type {
+	nextType, err := d.dec.NextType()
+	check(err)
+
+	switch nextType {
+	case cbor.NilType:
+		check(d.dec.DecodeNil())
+		return nil
+
+	case cbor.UintType:
+		code := d.decodeUint64()
+		if code >= uint64(len(simpleTypes)) {
+			panic(fmt.Errorf("invalid simple type code: %d", code))
+		}
+		common.UseMemory(d.gauge, common.CadenceSimpleTypeMemoryUsage)
+		return simpleTypes[code]
+
+	case cbor.TagType:
+		// handled below
+
+	default:
+		panic(fmt.Errorf("invalid type encoding: %s", nextType))
+	}
+
+	tag, err := d.dec.DecodeTagNumber()
+	check(err)
+
+	switch tag {
+	case tagOptionalType:
+		return cadence.NewMeteredOptionalType(d.gauge, d.decodeNonNilType())
+
+	case tagVariableSizedArrayType:
+		return cadence.NewMeteredVariableSizedArrayType(d.gauge, d.decodeNonNilType())
+
+	case tagConstantSizedArrayType:
+		d.decodeArrayHead(2)
+		size := d.decodeUintInRange(math.MaxInt64)
+		return cadence.NewMeteredConstantSizedArrayType(d.gauge, uint(size), d.decodeNonNilType())
+
+	case tagDictionaryType:
+		d.decodeArrayHead(2)
+		keyType := d.decodeNonNilType()
+		elementType := d.decodeNonNilType()
+		return cadence.NewMeteredDictionaryType(d.gauge, keyType, elementType)
+
+	case tagReferenceType:
+		d.decodeArrayHead(2)
+		authorized, err := d.dec.DecodeBool()
+		check(err)
+		return cadence.NewMeteredReferenceType(d.gauge, authorized, d.decodeNonNilType())
+
+	case tagRestrictedType:
+		d.decodeArrayHead(3)
+		typeID := d.decodeString()
+		restrictedType := d.decodeType()
+		count := d.decodeArrayHeadAnySize()
+		var restrictions []cadence.Type
+		if count > 0 {
+			restrictions = make([]cadence.Type, 0, count)
+			for i := uint64(0); i < count; i++ {
+				restrictions = append(restrictions, d.decodeNonNilType())
+			}
+		}
+		return cadence.NewMeteredRestrictedType(d.gauge, typeID, restrictedType, restrictions)
+
+	case tagCapabilityType:
+		return cadence.NewMeteredCapabilityType(d.gauge, d.decodeType())
+
+	case tagFunctionType:
+		d.decodeArrayHead(3)
+		typeID := d.decodeString()
+		parameters := d.decodeParameters()
+		returnType := d.decodeType()
+		return cadence.NewMeteredFunctionType(d.gauge, typeID, parameters, returnType)
+
+	case tagTypeDefRef:
+		return d.decodeTypeDefRef()
+
+	default:
+		panic(fmt.Errorf("unsupported type tag: %d", tag))
+	}
+}
+
+func (d *Decoder) decodeTypeDefRef() cadence.Type {
+	index := d.decodeUint64()
+	if index >= uint64(len(d.typeDefs)) {
+		panic(fmt.Errorf("invalid type definition index: %d", index))
+	}
+	return d.typeDefs[index]
+}
+
+func (d *Decoder) decodeValue() cadence.Value {
+	tag, err := d.dec.DecodeTagNumber()
+	check(err)
+
+	switch tag {
+	case tagVoidValue:
+		check(d.dec.DecodeNil())
+		return cadence.NewMeteredVoid(d.gauge)
+
+	case tagOptionalValue:
+		if d.nextIsNil() {
+			return cadence.NewMeteredOptional(d.gauge, nil)
+		}
+		return cadence.NewMeteredOptional(d.gauge, d.decodeValue())
+
+	case tagBoolValue:
+		v, err := d.dec.DecodeBool()
+		check(err)
+		return cadence.NewMeteredBool(d.gauge, v)
+
+	case tagCharacterValue:
+		s := d.decodeString()
+		v, err := cadence.NewMeteredCharacter(
+			d.gauge,
+			common.NewCadenceCharacterMemoryUsage(len(s)),
+			func() string {
+				return s
+			},
+		)
+		check(err)
+		return v
+
+	case tagStringValue:
+		s := d.decodeString()
+		v, err := cadence.NewMeteredString(
+			d.gauge,
+			common.NewCadenceStringMemoryUsage(len(s)),
+			func() string {
+				return s
+			},
+		)
+		check(err)
+		return v
+
+	case tagAddressValue:
+		return d.decodeAddress()
+
+	case tagIntValue:
+		v := d.decodeBigInt()
+		return cadence.NewMeteredIntFromBig(
+			d.gauge,
+			common.NewCadenceIntMemoryUsage(common.BigIntByteLength(v)),
+			func() *big.Int {
+				return v
+			},
+		)
+
+	case tagInt8Value:
+		return cadence.NewMeteredInt8(d.gauge, int8(d.decodeIntInRange(math.MinInt8, math.MaxInt8)))
+
+	case tagInt16Value:
+		return cadence.NewMeteredInt16(d.gauge, int16(d.decodeIntInRange(math.MinInt16, math.MaxInt16)))
+
+	case tagInt32Value:
+		return cadence.NewMeteredInt32(d.gauge, int32(d.decodeIntInRange(math.MinInt32, math.MaxInt32)))
+
+	case tagInt64Value:
+		return cadence.NewMeteredInt64(d.gauge, d.decodeInt64())
+
+	case tagInt128Value:
+		v := d.decodeBigInt()
+		result, err := cadence.NewMeteredInt128FromBig(d.gauge, func() *big.Int {
+			return v
+		})
+		check(err)
+		return result
+
+	case tagInt256Value:
+		v := d.decodeBigInt()
+		result, err := cadence.NewMeteredInt256FromBig(d.gauge, func() *big.Int {
+			return v
+		})
+		check(err)
+		return result
+
+	case tagUIntValue:
+		v := d.decodeBigInt()
+		result, err := cadence.NewMeteredUIntFromBig(
+			d.gauge,
+			common.NewCadenceIntMemoryUsage(common.BigIntByteLength(v)),
+			func() *big.Int {
+				return v
+			},
+		)
+		check(err)
+		return result
+
+	case tagUInt8Value:
+		return cadence.NewMeteredUInt8(d.gauge, uint8(d.decodeUintInRange(math.MaxUint8)))
+
+	case tagUInt16Value:
+		return cadence.NewMeteredUInt16(d.gauge, uint16(d.decodeUintInRange(math.MaxUint16)))
+
+	case tagUInt32Value:
+		return cadence.NewMeteredUInt32(d.gauge, uint32(d.decodeUintInRange(math.MaxUint32)))
+
+	case tagUInt64Value:
+		return cadence.NewMeteredUInt64(d.gauge, d.decodeUint64())
+
+	case tagUInt128Value:
+		v := d.decodeBigInt()
+		result, err := cadence.NewMeteredUInt128FromBig(d.gauge, func() *big.Int {
+			return v
+		})
+		check(err)
+		return result
+
+	case tagUInt256Value:
+		v := d.decodeBigInt()
+		result, err := cadence.NewMeteredUInt256FromBig(d.gauge, func() *big.Int {
+			return v
+		})
+		check(err)
+		return result
+
+	case tagWord8Value:
+		return cadence.NewMeteredWord8(d.gauge, uint8(d.decodeUintInRange(math.MaxUint8)))
+
+	case tagWord16Value:
+		return cadence.NewMeteredWord16(d.gauge, uint16(d.decodeUintInRange(math.MaxUint16)))
+
+	case tagWord32Value:
+		return cadence.NewMeteredWord32(d.gauge, uint32(d.decodeUintInRange(math.MaxUint32)))
+
+	case tagWord64Value:
+		return cadence.NewMeteredWord64(d.gauge, d.decodeUint64())
+
+	case tagFix64Value:
+		common.UseMemory(d.gauge, fixedPointMemoryUsage)
+		return cadence.Fix64(d.decodeInt64())
+
+	case tagUFix64Value:
+		common.UseMemory(d.gauge, fixedPointMemoryUsage)
+		return cadence.UFix64(d.decodeUint64())
+
+	case tagArrayValue:
+		return d.decodeArray()
+
+	case tagDictionaryValue:
+		return d.decodeDictionary()
+
+	case tagCompositeValue:
+		return d.decodeComposite()
+
+	case tagLinkValue:
+		d.decodeArrayHead(2)
+		targetPath := d.decodePath()
+		borrowType := d.decodeString()
+		return cadence.NewMeteredLink(d.gauge, targetPath, borrowType)
+
+	case tagPathValue:
+		return d.decodePath()
+
+	case tagTypeValue:
+		return cadence.NewMeteredTypeValue(d.gauge, d.decodeType())
+
+	case tagCapabilityValue:
+		d.decodeArrayHead(3)
+		path := d.decodePath()
+		address := d.decodeAddress()
+		borrowType := d.decodeType()
+		return cadence.NewMeteredCapability(d.gauge, path, address, borrowType)
+
+	default:
+		panic(fmt.Errorf("unsupported value tag: %d", tag))
+	}
+}
+
+func (d *Decoder) decodeAddress() cadence.Address {
+	b, err := d.dec.DecodeBytes()
+	check(err)
+	if len(b) != cadence.AddressLength {
+		panic(fmt.Errorf("invalid address length: %d", len(b)))
+	}
+	return cadence.BytesToMeteredAddress(d.gauge, b)
+}
+
+func (d *Decoder) decodePath() cadence.Path {
+	d.decodeArrayHead(2)
+
+	domain := d.decodeString()
+	if common.PathDomainFromIdentifier(domain) == common.PathDomainUnknown {
+		panic(fmt.Errorf("invalid path domain: %s", domain))
+	}
+
+	identifier := d.decodeString()
+
+	return cadence.NewMeteredPath(d.gauge, domain, identifier)
+}
+
+// decodeArray decodes an array value:
+//
+//	[array-type, [* element-value]]
+func (d *Decoder) decodeArray() cadence.Value {
+	d.decodeArrayHead(2)
+
+	var arrayType cadence.ArrayType
+	if typ := d.decodeType(); typ != nil {
+		var ok bool
+		arrayType, ok = typ.(cadence.ArrayType)
+		if !ok {
+			panic(fmt.Errorf("invalid array type: %s", typ.ID()))
+		}
+	}
+
+	count := d.decodeArrayHeadAnySize()
+
+	if constantSizedArrayType, ok := arrayType.(cadence.ConstantSizedArrayType); ok &&
+		uint64(constantSizedArrayType.Size) != count {
+
+		panic(fmt.Errorf(
+			"invalid element count for %s: %d",
+			constantSizedArrayType.ID(),
+			count,
+		))
+	}
+
+	array, err := cadence.NewMeteredArray(
+		d.gauge,
+		int(count),
+		func() ([]cadence.Value, error) {
+			values := make([]cadence.Value, 0, count)
+			for i := uint64(0); i < count; i++ {
+				value := d.decodeValue()
+				if arrayType != nil {
+					checkValueType(value, arrayType.Element())
+				}
+				values = append(values, value)
+			}
+			return values, nil
+		},
+	)
+	check(err)
+
+	return array.WithType(arrayType)
+}
+
+// decodeDictionary decodes a dictionary value:
+//
+//	[dictionary-type, [* key-value, element-value]]
+func (d *Decoder) decodeDictionary() cadence.Value {
+	d.decodeArrayHead(2)
+
+	var dictionaryType *cadence.DictionaryType
+	if typ := d.decodeType(); typ != nil {
+		t, ok := typ.(cadence.DictionaryType)
+		if !ok {
+			panic(fmt.Errorf("invalid dictionary type: %s", typ.ID()))
+		}
+		dictionaryType = &t
+	}
+
+	count := d.decodeArrayHeadAnySize()
+	if count%2 != 0 {
+		panic(fmt.Errorf("invalid dictionary element count: %d", count))
+	}
+
+	dictionary, err := cadence.NewMeteredDictionary(
+		d.gauge,
+		int(count/2),
+		func() ([]cadence.KeyValuePair, error) {
+			pairs := make([]cadence.KeyValuePair, 0, count/2)
+			for i := uint64(0); i < count; i += 2 {
+				key := d.decodeValue()
+				value := d.decodeValue()
+				if dictionaryType != nil {
+					checkValueType(key, dictionaryType.KeyType)
+					checkValueType(value, dictionaryType.ElementType)
+				}
+				pairs = append(pairs, cadence.NewMeteredKeyValuePair(d.gauge, key, value))
+			}
+			return pairs, nil
+		},
+	)
+	check(err)
+
+	if dictionaryType != nil {
+		dictionary = dictionary.WithType(*dictionaryType)
+	}
+
+	return dictionary
+}
+
+// decodeComposite decodes a composite value:
+//
+//	[type-def-index, [* field-value]]
+func (d *Decoder) decodeComposite() cadence.Value {
+	d.decodeArrayHead(2)
+
+	typ := d.decodeTypeDefRef()
+	compositeType, ok := typ.(cadence.CompositeType)
+	if !ok {
+		panic(fmt.Errorf("invalid composite type: %s", typ.ID()))
+	}
+
+	fieldTypes := nonFunctionFields(compositeType.CompositeFields())
+
+	d.decodeArrayHead(uint64(len(fieldTypes)))
+
+	constructor := func() ([]cadence.Value, error) {
+		fields := make([]cadence.Value, 0, len(fieldTypes))
+		for _, fieldType := range fieldTypes {
+			value := d.decodeValue()
+			checkValueType(value, fieldType.Type)
+			fields = append(fields, value)
+		}
+		return fields, nil
+	}
+
+	switch compositeType := compositeType.(type) {
+	case *cadence.StructType:
+		value, err := cadence.NewMeteredStruct(d.gauge, len(fieldTypes), constructor)
+		check(err)
+		return value.WithType(compositeType)
+
+	case *cadence.ResourceType:
+		value, err := cadence.NewMeteredResource(d.gauge, len(fieldTypes), constructor)
+		check(err)
+		return value.WithType(compositeType)
+
+	case *cadence.EventType:
+		value, err := cadence.NewMeteredEvent(d.gauge, len(fieldTypes), constructor)
+		check(err)
+		return value.WithType(compositeType)
+
+	case *cadence.ContractType:
+		value, err := cadence.NewMeteredContract(d.gauge, len(fieldTypes), constructor)
+		check(err)
+		return value.WithType(compositeType)
+
+	case *cadence.EnumType:
+		value, err := cadence.NewMeteredEnum(d.gauge, len(fieldTypes), constructor)
+		check(err)
+		return value.WithType(compositeType)
+
+	default:
+		panic(fmt.Errorf("unsupported composite type: %s", typ.ID()))
+	}
+}
+
+// checkValueType checks if the given value has the expected type.
+//
+// Only types which can be checked without any type information of the program
+// are checked, i.e. concrete simple types, optional types, and defined types.
+func checkValueType(value cadence.Value, expectedType cadence.Type) {
+	if !valueHasType(value, expectedType) {
+		panic(fmt.Errorf(
+			"invalid value: expected value of type %s, got %s",
+			expectedType.ID(),
+			value,
+		))
+	}
+}
+
+func valueHasType(value cadence.Value, expectedType cadence.Type) bool {
+	switch expectedType := expectedType.(type) {
+	case nil:
+		return true
+
+	case cadence.OptionalType:
+		optional, ok := value.(cadence.Optional)
+		if !ok {
+			return false
+		}
+		if optional.Value == nil {
+			return true
+		}
+		return valueHasType(optional.Value, expectedType.Type)
+
+	case cadence.CompositeType:
+		valueType, ok := value.Type().(cadence.CompositeType)
+		return ok && valueType.ID() == expectedType.ID()
+
+	case cadence.VoidType,
+		cadence.BoolType,
+		cadence.StringType,
+		cadence.CharacterType,
+		cadence.AddressType,
+		cadence.IntType,
+		cadence.Int8Type,
+		cadence.Int16Type,
+		cadence.Int32Type,
+		cadence.Int64Type,
+		cadence.Int128Type,
+		cadence.Int256Type,
+		cadence.UIntType,
+		cadence.UInt8Type,
+		cadence.UInt16Type,
+		cadence.UInt32Type,
+		cadence.UInt64Type,
+		cadence.UInt128Type,
+		cadence.UInt256Type,
+		cadence.Word8Type,
+		cadence.Word16Type,
+		cadence.Word32Type,
+		cadence.Word64Type,
+		cadence.Fix64Type,
+		cadence.UFix64Type:
+
+		return value.Type() == expectedType
+
+	default:
+		return true
+	}
+}
@@ -0,0 +1,663 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ccf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	goRuntime "runtime"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/onflow/cadence"
+)
+
+var encMode = func() cbor.EncMode {
+	encMode, err := cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return encMode
+}()
+
+// An Encoder converts Cadence values into CCF-encoded bytes.
+type Encoder struct {
+	w io.Writer
+}
+
+// Encode returns the CCF-encoded representation of the given value.
+//
+// This function returns an error if the Cadence value cannot be represented in CCF.
+func Encode(value cadence.Value) ([]byte, error) {
+	var w bytes.Buffer
+	enc := NewEncoder(&w)
+
+	err := enc.Encode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.Bytes(), nil
+}
+
+// MustEncode returns the CCF-encoded representation of the given value, or panics
+// if the value cannot be represented in CCF.
+func MustEncode(value cadence.Value) []byte {
+	b, err := Encode(value)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// NewEncoder initializes an Encoder that will write CCF-encoded bytes to the
+// given io.Writer.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the CCF-encoded representation of the given value to this
+// encoder's io.Writer.
+//
+// This function returns an error if the given value's type is not supported
+// by this encoder.
+func (e *Encoder) Encode(value cadence.Value) (err error) {
+	// capture panics that occur during encoding
+	defer func() {
+		if r := recover(); r != nil {
+			// don't recover Go errors
+			goErr, ok := r.(goRuntime.Error)
+			if ok {
+				panic(goErr)
+			}
+
+			panicErr, isError := r.(error)
+			if !isError {
+				panic(r)
+			}
+
+			err = fmt.Errorf("failed to encode value: %w", panicErr)
+		}
+	}()
+
+	enc := &encoder{
+		enc:            encMode.NewStreamEncoder(e.w),
+		typeDefIndices: map[string]uint64{},
+	}
+
+	enc.collectValueTypes(value)
+	enc.encodeMessage(value)
+
+	return enc.enc.Flush()
+}
+
+// encoder holds the state of the encoding of a single message
+type encoder struct {
+	enc *cbor.StreamEncoder
+	// typeDefs are the type definitions of the message, in the order they are encoded
+	typeDefs []cadence.Type
+	// typeDefIndices maps the IDs of defined types to their index in typeDefs
+	typeDefIndices map[string]uint64
+}
+
+func check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// collectValueTypes collects the type definitions required to encode the given value,
+// in the order they are first encountered
+func (e *encoder) collectValueTypes(v cadence.Value) {
+	switch v := v.(type) {
+	case cadence.Optional:
+		if v.Value != nil {
+			e.collectValueTypes(v.Value)
+		}
+
+	case cadence.Array:
+		if v.ArrayType != nil {
+			e.collectTypes(v.ArrayType)
+		}
+		for _, element := range v.Values {
+			e.collectValueTypes(element)
+		}
+
+	case cadence.Dictionary:
+		e.collectTypes(v.DictionaryType)
+		for _, pair := range v.Pairs {
+			e.collectValueTypes(pair.Key)
+			e.collectValueTypes(pair.Value)
+		}
+
+	case cadence.Struct:
+		e.collectCompositeValueTypes(v.StructType, v.Fields)
+
+	case cadence.Resource:
+		e.collectCompositeValueTypes(v.ResourceType, v.Fields)
+
+	case cadence.Event:
+		e.collectCompositeValueTypes(v.EventType, v.Fields)
+
+	case cadence.Contract:
+		e.collectCompositeValueTypes(v.ContractType, v.Fields)
+
+	case cadence.Enum:
+		e.collectCompositeValueTypes(v.EnumType, v.Fields)
+
+	case cadence.TypeValue:
+		e.collectTypes(v.StaticType)
+
+	case cadence.Capability:
+		e.collectTypes(v.BorrowType)
+	}
+}
+
+func (e *encoder) collectCompositeValueTypes(typ cadence.CompositeType, fields []cadence.Value) {
+	e.collectTypes(typ)
+	for _, field := range fields {
+		e.collectValueTypes(field)
+	}
+}
+
+// collectTypes collects the type definitions required to encode the given type,
+// in the order they are first encountered
+func (e *encoder) collectTypes(typ cadence.Type) {
+	switch typ := typ.(type) {
+	case nil:
+		return
+
+	case cadence.OptionalType:
+		e.collectTypes(typ.Type)
+
+	case cadence.VariableSizedArrayType:
+		e.collectTypes(typ.ElementType)
+
+	case cadence.ConstantSizedArrayType:
+		e.collectTypes(typ.ElementType)
+
+	case cadence.DictionaryType:
+		e.collectTypes(typ.KeyType)
+		e.collectTypes(typ.ElementType)
+
+	case cadence.ReferenceType:
+		e.collectTypes(typ.Type)
+
+	case *cadence.RestrictedType:
+		e.collectTypes(typ.Type)
+		for _, restriction := range typ.Restrictions {
+			e.collectTypes(restriction)
+		}
+
+	case cadence.CapabilityType:
+		e.collectTypes(typ.BorrowType)
+
+	case *cadence.FunctionType:
+		e.collectParameterTypes(typ.Parameters)
+		e.collectTypes(typ.ReturnType)
+
+	case cadence.CompositeType:
+		if !e.addTypeDef(typ) {
+			return
+		}
+		e.collectFieldTypes(typ.CompositeFields())
+		for _, parameters := range typ.CompositeInitializers() {
+			e.collectParameterTypes(parameters)
+		}
+		if enumType, ok := typ.(*cadence.EnumType); ok {
+			e.collectTypes(enumType.RawType)
+		}
+
+	case cadence.InterfaceType:
+		if !e.addTypeDef(typ) {
+			return
+		}
+		e.collectFieldTypes(typ.InterfaceFields())
+		for _, parameters := range typ.InterfaceInitializers() {
+			e.collectParameterTypes(parameters)
+		}
+	}
+}
+
+func (e *encoder) collectFieldTypes(fields []cadence.Field) {
+	for _, field := range fields {
+		e.collectTypes(field.Type)
+	}
+}
+
+func (e *encoder) collectParameterTypes(parameters []cadence.Parameter) {
+	for _, parameter := range parameters {
+		e.collectTypes(parameter.Type)
+	}
+}
+
+// addTypeDef adds the given type to the type definitions of the message.
+// It returns false if a type with the same ID was already added
+func (e *encoder) addTypeDef(typ cadence.Type) bool {
+	if isNilPointer(typ) {
+		panic(fmt.Errorf("missing composite type"))
+	}
+
+	typeID := typ.ID()
+	if _, ok := e.typeDefIndices[typeID]; ok {
+		return false
+	}
+
+	e.typeDefIndices[typeID] = uint64(len(e.typeDefs))
+	e.typeDefs = append(e.typeDefs, typ)
+	return true
+}
+
+func isNilPointer(typ cadence.Type) bool {
+	switch typ := typ.(type) {
+	case *cadence.StructType:
+		return typ == nil
+	case *cadence.ResourceType:
+		return typ == nil
+	case *cadence.EventType:
+		return typ == nil
+	case *cadence.ContractType:
+		return typ == nil
+	case *cadence.EnumType:
+		return typ == nil
+	case *cadence.StructInterfaceType:
+		return typ == nil
+	case *cadence.ResourceInterfaceType:
+		return typ == nil
+	case *cadence.ContractInterfaceType:
+		return typ == nil
+	}
+	return false
+}
+
+// encodeMessage encodes the message:
+//
+//	[type-defs, value]
+func (e *encoder) encodeMessage(value cadence.Value) {
+	check(e.enc.EncodeArrayHead(2))
+
+	check(e.enc.EncodeArrayHead(uint64(len(e.typeDefs))))
+	for _, typeDef := range e.typeDefs {
+		e.encodeTypeDef(typeDef)
+	}
+
+	e.encodeValue(value)
+}
+
+// encodeTypeDef encodes a type definition:
+//
+//	[kind, type-id, fields, initializers, raw-type]
+func (e *encoder) encodeTypeDef(typ cadence.Type) {
+	switch typ := typ.(type) {
+	case *cadence.StructType:
+		e.encodeTypeDefEntry(typeDefKindStruct, typ, typ.Fields, typ.Initializers, nil)
+	case *cadence.ResourceType:
+		e.encodeTypeDefEntry(typeDefKindResource, typ, typ.Fields, typ.Initializers, nil)
+	case *cadence.EventType:
+		e.encodeTypeDefEntry(typeDefKindEvent, typ, typ.Fields, [][]cadence.Parameter{typ.Initializer}, nil)
+	case *cadence.ContractType:
+		e.encodeTypeDefEntry(typeDefKindContract, typ, typ.Fields, typ.Initializers, nil)
+	case *cadence.EnumType:
+		e.encodeTypeDefEntry(typeDefKindEnum, typ, typ.Fields, typ.Initializers, typ.RawType)
+	case *cadence.StructInterfaceType:
+		e.encodeTypeDefEntry(typeDefKindStructInterface, typ, typ.Fields, typ.Initializers, nil)
+	case *cadence.ResourceInterfaceType:
+		e.encodeTypeDefEntry(typeDefKindResourceInterface, typ, typ.Fields, typ.Initializers, nil)
+	case *cadence.ContractInterfaceType:
+		e.encodeTypeDefEntry(typeDefKindContractInterface, typ, typ.Fields, typ.Initializers, nil)
+	default:
+		panic(fmt.Errorf("unsupported type definition: %T, %v", typ, typ))
+	}
+}
+
+func (e *encoder) encodeTypeDefEntry(
+	kind uint64,
+	typ cadence.Type,
+	fields []cadence.Field,
+	initializers [][]cadence.Parameter,
+	rawType cadence.Type,
+) {
+	check(e.enc.EncodeArrayHead(5))
+	check(e.enc.EncodeUint64(kind))
+	check(e.enc.EncodeString(typ.ID()))
+
+	check(e.enc.EncodeArrayHead(uint64(len(fields))))
+	for _, field := range fields {
+		check(e.enc.EncodeArrayHead(2))
+		check(e.enc.EncodeString(field.Identifier))
+		e.encodeType(field.Type)
+	}
+
+	check(e.enc.EncodeArrayHead(uint64(len(initializers))))
+	for _, parameters := range initializers {
+		e.encodeParameters(parameters)
+	}
+
+	e.encodeType(rawType)
+}
+
+func (e *encoder) encodeParameters(parameters []cadence.Parameter) {
+	check(e.enc.EncodeArrayHead(uint64(len(parameters))))
+	for _, parameter := range parameters {
+		check(e.enc.EncodeArrayHead(3))
+		check(e.enc.EncodeString(parameter.Label))
+		check(e.enc.EncodeString(parameter.Identifier))
+		e.encodeType(parameter.Type)
+	}
+}
+
+func (e *encoder) encodeType(typ cadence.Type) {
+	switch typ := typ.(type) {
+	case nil:
+		check(e.enc.EncodeNil())
+
+	case cadence.OptionalType:
+		check(e.enc.EncodeTagHead(tagOptionalType))
+		e.encodeType(typ.Type)
+
+	case cadence.VariableSizedArrayType:
+		check(e.enc.EncodeTagHead(tagVariableSizedArrayType))
+		e.encodeType(typ.ElementType)
+
+	case cadence.ConstantSizedArrayType:
+		check(e.enc.EncodeTagHead(tagConstantSizedArrayType))
+		check(e.enc.EncodeArrayHead(2))
+		check(e.enc.EncodeUint64(uint64(typ.Size)))
+		e.encodeType(typ.ElementType)
+
+	case cadence.DictionaryType:
+		check(e.enc.EncodeTagHead(tagDictionaryType))
+		check(e.enc.EncodeArrayHead(2))
+		e.encodeType(typ.KeyType)
+		e.encodeType(typ.ElementType)
+
+	case cadence.ReferenceType:
+		check(e.enc.EncodeTagHead(tagReferenceType))
+		check(e.enc.EncodeArrayHead(2))
+		check(e.enc.EncodeBool(typ.Authorized))
+		e.encodeType(typ.Type)
+
+	case *cadence.RestrictedType:
+		check(e.enc.EncodeTagHead(tagRestrictedType))
+		check(e.enc.EncodeArrayHead(3))
+		check(e.enc.EncodeString(typ.ID()))
+		e.encodeType(typ.Type)
+		check(e.enc.EncodeArrayHead(uint64(len(typ.Restrictions))))
+		for _, restriction := range typ.Restrictions {
+			e.encodeType(restriction)
+		}
+
+	case cadence.CapabilityType:
+		check(e.enc.EncodeTagHead(tagCapabilityType))
+		e.encodeType(typ.BorrowType)
+
+	case *cadence.FunctionType:
+		check(e.enc.EncodeTagHead(tagFunctionType))
+		check(e.enc.EncodeArrayHead(3))
+		check(e.enc.EncodeString(typ.ID()))
+		e.encodeParameters(typ.Parameters)
+		e.encodeType(typ.ReturnType)
+
+	case cadence.CompositeType, cadence.InterfaceType:
+		check(e.enc.EncodeTagHead(tagTypeDefRef))
+		check(e.enc.EncodeUint64(e.typeDefIndex(typ)))
+
+	default:
+		code, ok := simpleTypeCodes[typ]
+		if !ok {
+			panic(fmt.Errorf("unsupported type: %T, %v", typ, typ))
+		}
+		check(e.enc.EncodeUint64(code))
+	}
+}
+
+func (e *encoder) typeDefIndex(typ cadence.Type) uint64 {
+	index, ok := e.typeDefIndices[typ.ID()]
+	if !ok {
+		panic(fmt.Errorf("missing type definition: %s", typ.ID()))
+	}
+	return index
+}
+
+func (e *encoder) encodeValue(v cadence.Value) {
+	switch v := v.(type) {
+	case cadence.Void:
+		check(e.enc.EncodeTagHead(tagVoidValue))
+		check(e.enc.EncodeNil())
+
+	case cadence.Optional:
+		check(e.enc.EncodeTagHead(tagOptionalValue))
+		if v.Value == nil {
+			check(e.enc.EncodeNil())
+		} else {
+			e.encodeValue(v.Value)
+		}
+
+	case cadence.Bool:
+		check(e.enc.EncodeTagHead(tagBoolValue))
+		check(e.enc.EncodeBool(bool(v)))
+
+	case cadence.Character:
+		check(e.enc.EncodeTagHead(tagCharacterValue))
+		check(e.enc.EncodeString(string(v)))
+
+	case cadence.String:
+		check(e.enc.EncodeTagHead(tagStringValue))
+		check(e.enc.EncodeString(string(v)))
+
+	case cadence.Address:
+		check(e.enc.EncodeTagHead(tagAddressValue))
+		check(e.enc.EncodeBytes(v.Bytes()))
+
+	case cadence.Int:
+		check(e.enc.EncodeTagHead(tagIntValue))
+		check(e.enc.EncodeBigInt(v.Big()))
+
+	case cadence.Int8:
+		check(e.enc.EncodeTagHead(tagInt8Value))
+		check(e.enc.EncodeInt64(int64(v)))
+
+	case cadence.Int16:
+		check(e.enc.EncodeTagHead(tagInt16Value))
+		check(e.enc.EncodeInt64(int64(v)))
+
+	case cadence.Int32:
+		check(e.enc.EncodeTagHead(tagInt32Value))
+		check(e.enc.EncodeInt64(int64(v)))
+
+	case cadence.Int64:
+		check(e.enc.EncodeTagHead(tagInt64Value))
+		check(e.enc.EncodeInt64(int64(v)))
+
+	case cadence.Int128:
+		check(e.enc.EncodeTagHead(tagInt128Value))
+		check(e.enc.EncodeBigInt(v.Big()))
+
+	case cadence.Int256:
+		check(e.enc.EncodeTagHead(tagInt256Value))
+		check(e.enc.EncodeBigInt(v.Big()))
+
+	case cadence.UInt:
+		check(e.enc.EncodeTagHead(tagUIntValue))
+		check(e.enc.EncodeBigInt(v.Big()))
+
+	case cadence.UInt8:
+		check(e.enc.EncodeTagHead(tagUInt8Value))
+		check(e.enc.EncodeUint64(uint64(v)))
+
+	case cadence.UInt16:
+		check(e.enc.EncodeTagHead(tagUInt16Value))
+		check(e.enc.EncodeUint64(uint64(v)))
+
+	case cadence.UInt32:
+		check(e.enc.EncodeTagHead(tagUInt32Value))
+		check(e.enc.EncodeUint64(uint64(v)))
+
+	case cadence.UInt64:
+		check(e.enc.EncodeTagHead(tagUInt64Value))
+		check(e.enc.EncodeUint64(uint64(v)))
+
+	case cadence.UInt128:
+		check(e.enc.EncodeTagHead(tagUInt128Value))
+		check(e.enc.EncodeBigInt(v.Big()))
+
+	case cadence.UInt256:
+		check(e.enc.EncodeTagHead(tagUInt256Value))
+		check(e.enc.EncodeBigInt(v.Big()))
+
+	case cadence.Word8:
+		check(e.enc.EncodeTagHead(tagWord8Value))
+		check(e.enc.EncodeUint64(uint64(v)))
+
+	case cadence.Word16:
+		check(e.enc.EncodeTagHead(tagWord16Value))
+		check(e.enc.EncodeUint64(uint64(v)))
+
+	case cadence.Word32:
+		check(e.enc.EncodeTagHead(tagWord32Value))
+		check(e.enc.EncodeUint64(uint64(v)))
+
+	case cadence.Word64:
+		check(e.enc.EncodeTagHead(tagWord64Value))
+		check(e.enc.EncodeUint64(uint64(v)))
+
+	case cadence.Fix64:
+		check(e.enc.EncodeTagHead(tagFix64Value))
+		check(e.enc.EncodeInt64(int64(v)))
+
+	case cadence.UFix64:
+		check(e.enc.EncodeTagHead(tagUFix64Value))
+		check(e.enc.EncodeUint64(uint64(v)))
+
+	case cadence.Array:
+		check(e.enc.EncodeTagHead(tagArrayValue))
+		check(e.enc.EncodeArrayHead(2))
+		if v.ArrayType == nil {
+			check(e.enc.EncodeNil())
+		} else {
+			e.encodeType(v.ArrayType)
+		}
+		check(e.enc.EncodeArrayHead(uint64(len(v.Values))))
+		for _, element := range v.Values {
+			e.encodeValue(element)
+		}
+
+	case cadence.Dictionary:
+		check(e.enc.EncodeTagHead(tagDictionaryValue))
+		check(e.enc.EncodeArrayHead(2))
+		e.encodeType(v.DictionaryType)
+		check(e.enc.EncodeArrayHead(uint64(len(v.Pairs) * 2)))
+		for _, pair := range v.Pairs {
+			e.encodeValue(pair.Key)
+			e.encodeValue(pair.Value)
+		}
+
+	case cadence.Struct:
+		e.encodeComposite(v.StructType, v.Fields)
+
+	case cadence.Resource:
+		e.encodeComposite(v.ResourceType, v.Fields)
+
+	case cadence.Event:
+		e.encodeComposite(v.EventType, v.Fields)
+
+	case cadence.Contract:
+		e.encodeComposite(v.ContractType, v.Fields)
+
+	case cadence.Enum:
+		e.encodeComposite(v.EnumType, v.Fields)
+
+	case cadence.Link:
+		check(e.enc.EncodeTagHead(tagLinkValue))
+		check(e.enc.EncodeArrayHead(2))
+		e.encodePath(v.TargetPath)
+		check(e.enc.EncodeString(v.BorrowType))
+
+	case cadence.Path:
+		check(e.enc.EncodeTagHead(tagPathValue))
+		e.encodePath(v)
+
+	case cadence.TypeValue:
+		check(e.enc.EncodeTagHead(tagTypeValue))
+		e.encodeType(v.StaticType)
+
+	case cadence.Capability:
+		check(e.enc.EncodeTagHead(tagCapabilityValue))
+		check(e.enc.EncodeArrayHead(3))
+		e.encodePath(v.Path)
+		check(e.enc.EncodeBytes(v.Address.Bytes()))
+		e.encodeType(v.BorrowType)
+
+	default:
+		panic(fmt.Errorf("unsupported value: %T, %v", v, v))
+	}
+}
+
+// encodeComposite encodes a composite value:
+//
+//	[type-def-index, [* field-value]]
+func (e *encoder) encodeComposite(typ cadence.CompositeType, fields []cadence.Value) {
+	index := e.typeDefIndex(typ)
+
+	// Values of the same type are encoded using the first definition of the type,
+	// so the fields must be checked against it
+
+	compositeType, ok := e.typeDefs[index].(cadence.CompositeType)
+	if !ok {
+		panic(fmt.Errorf("type definition of %s is not a composite type", typ.ID()))
+	}
+
+	fieldTypes := nonFunctionFields(compositeType.CompositeFields())
+
+	if len(fieldTypes) != len(fields) {
+		panic(fmt.Errorf(
+			"%s field count (%d) does not match declared type (%d)",
+			typ.ID(),
+			len(fields),
+			len(fieldTypes),
+		))
+	}
+
+	check(e.enc.EncodeTagHead(tagCompositeValue))
+	check(e.enc.EncodeArrayHead(2))
+	check(e.enc.EncodeUint64(index))
+	check(e.enc.EncodeArrayHead(uint64(len(fields))))
+	for _, field := range fields {
+		e.encodeValue(field)
+	}
+}
+
+func (e *encoder) encodePath(path cadence.Path) {
+	check(e.enc.EncodeArrayHead(2))
+	check(e.enc.EncodeString(path.Domain))
+	check(e.enc.EncodeString(path.Identifier))
+}
+
+// nonFunctionFields returns the fields which have a value,
+// i.e. all fields which are not functions
+func nonFunctionFields(fields []cadence.Field) []cadence.Field {
+	result := make([]cadence.Field, 0, len(fields))
+	for _, field := range fields {
+		if _, ok := field.Type.(*cadence.FunctionType); !ok {
+			result = append(result, field)
+		}
+	}
+	return result
+}
@@ -0,0 +1,803 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ccf_test
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/encoding/ccf"
+	"github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/checker"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+type encodeTest struct {
+	name string
+	val  cadence.Value
+}
+
+func testAllEncodeAndDecode(t *testing.T, tests ...encodeTest) {
+
+	test := func(testCase encodeTest) {
+
+		t.Run(testCase.name, func(t *testing.T) {
+
+			t.Parallel()
+
+			testEncodeAndDecode(t, testCase.val)
+		})
+	}
+
+	for _, testCase := range tests {
+		test(testCase)
+	}
+}
+
+func testEncodeAndDecode(t *testing.T, val cadence.Value) []byte {
+	encoded, err := ccf.Encode(val)
+	require.NoError(t, err)
+
+	decoded, err := ccf.Decode(nil, encoded)
+	require.NoError(t, err)
+
+	assert.Equal(t, val, decoded)
+
+	// The encoding is deterministic
+
+	reencoded, err := ccf.Encode(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, encoded, reencoded)
+
+	return encoded
+}
+
+func bigIntFromString(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid big int")
+	}
+	return v
+}
+
+var fooResourceType = &cadence.ResourceType{
+	Location:            utils.TestLocation,
+	QualifiedIdentifier: "Foo",
+	Fields: []cadence.Field{
+		{
+			Identifier: "bar",
+			Type:       cadence.IntType{},
+		},
+	},
+}
+
+var simpleStructType = &cadence.StructType{
+	Location:            utils.TestLocation,
+	QualifiedIdentifier: "FooStruct",
+	Fields: []cadence.Field{
+		{
+			Identifier: "a",
+			Type:       cadence.IntType{},
+		},
+		{
+			Identifier: "b",
+			Type:       cadence.StringType{},
+		},
+	},
+	Initializers: [][]cadence.Parameter{
+		{
+			{
+				Label:      "a",
+				Identifier: "a",
+				Type:       cadence.IntType{},
+			},
+			{
+				Label:      "_",
+				Identifier: "b",
+				Type:       cadence.StringType{},
+			},
+		},
+	},
+}
+
+func TestEncodeSimpleValues(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t,
+		encodeTest{"Void", cadence.NewVoid()},
+		encodeTest{"Nil", cadence.NewOptional(nil)},
+		encodeTest{"Optional", cadence.NewOptional(cadence.NewInt(42))},
+		encodeTest{"Nested optional", cadence.NewOptional(cadence.NewOptional(nil))},
+		encodeTest{"True", cadence.NewBool(true)},
+		encodeTest{"False", cadence.NewBool(false)},
+		encodeTest{"Character", cadence.Character("é")},
+		encodeTest{"Empty string", cadence.String("")},
+		encodeTest{"String", cadence.String("foo é \U0001F600")},
+		encodeTest{"Address", cadence.BytesToAddress([]byte{1, 2, 3, 4, 5, 6, 7, 8})},
+		encodeTest{"Path", cadence.NewPath("storage", "foo")},
+		encodeTest{
+			"Link",
+			cadence.NewLink(cadence.NewPath("private", "foo"), "&Int"),
+		},
+		encodeTest{
+			"Capability",
+			cadence.NewCapability(
+				cadence.NewPath("public", "foo"),
+				cadence.BytesToAddress([]byte{1}),
+				cadence.ReferenceType{Type: cadence.IntType{}},
+			),
+		},
+		encodeTest{
+			"Capability without borrow type",
+			cadence.NewCapability(
+				cadence.NewPath("public", "foo"),
+				cadence.BytesToAddress([]byte{1}),
+				nil,
+			),
+		},
+	)
+}
+
+func TestEncodeNumbers(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t,
+		encodeTest{"Int zero", cadence.NewInt(0)},
+		encodeTest{"Int negative", cadence.NewInt(-42)},
+		encodeTest{"Int big", cadence.NewIntFromBig(bigIntFromString("1267650600228229401496703205376"))},
+		encodeTest{"Int big negative", cadence.NewIntFromBig(bigIntFromString("-1267650600228229401496703205376"))},
+		encodeTest{"Int8 min", cadence.NewInt8(math.MinInt8)},
+		encodeTest{"Int8 max", cadence.NewInt8(math.MaxInt8)},
+		encodeTest{"Int16 min", cadence.NewInt16(math.MinInt16)},
+		encodeTest{"Int16 max", cadence.NewInt16(math.MaxInt16)},
+		encodeTest{"Int32 min", cadence.NewInt32(math.MinInt32)},
+		encodeTest{"Int32 max", cadence.NewInt32(math.MaxInt32)},
+		encodeTest{"Int64 min", cadence.NewInt64(math.MinInt64)},
+		encodeTest{"Int64 max", cadence.NewInt64(math.MaxInt64)},
+		encodeTest{"Int128 min", cadence.Int128{Value: sema.Int128TypeMinIntBig}},
+		encodeTest{"Int128 max", cadence.Int128{Value: sema.Int128TypeMaxIntBig}},
+		encodeTest{"Int256 min", cadence.Int256{Value: sema.Int256TypeMinIntBig}},
+		encodeTest{"Int256 max", cadence.Int256{Value: sema.Int256TypeMaxIntBig}},
+		encodeTest{"UInt", cadence.NewUInt(42)},
+		encodeTest{"UInt big", cadence.UInt{Value: bigIntFromString("1267650600228229401496703205376")}},
+		encodeTest{"UInt8 max", cadence.NewUInt8(math.MaxUint8)},
+		encodeTest{"UInt16 max", cadence.NewUInt16(math.MaxUint16)},
+		encodeTest{"UInt32 max", cadence.NewUInt32(math.MaxUint32)},
+		encodeTest{"UInt64 max", cadence.NewUInt64(math.MaxUint64)},
+		encodeTest{"UInt128 max", cadence.UInt128{Value: sema.UInt128TypeMaxIntBig}},
+		encodeTest{"UInt256 max", cadence.UInt256{Value: sema.UInt256TypeMaxIntBig}},
+		encodeTest{"Word8 max", cadence.NewWord8(math.MaxUint8)},
+		encodeTest{"Word16 max", cadence.NewWord16(math.MaxUint16)},
+		encodeTest{"Word32 max", cadence.NewWord32(math.MaxUint32)},
+		encodeTest{"Word64 max", cadence.NewWord64(math.MaxUint64)},
+		encodeTest{"Fix64 min", cadence.Fix64(math.MinInt64)},
+		encodeTest{"Fix64 negative", cadence.Fix64(-12_300_000_000)},
+		encodeTest{"Fix64 max", cadence.Fix64(math.MaxInt64)},
+		encodeTest{"UFix64 max", cadence.UFix64(math.MaxUint64)},
+	)
+}
+
+func TestEncodeArray(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t,
+		encodeTest{
+			"Empty",
+			cadence.NewArray([]cadence.Value{}),
+		},
+		encodeTest{
+			"Integers",
+			cadence.NewArray([]cadence.Value{
+				cadence.NewInt(1),
+				cadence.NewInt(2),
+				cadence.NewInt(3),
+			}),
+		},
+		encodeTest{
+			"Typed",
+			cadence.NewArray([]cadence.Value{
+				cadence.NewOptional(cadence.NewInt(1)),
+				cadence.NewOptional(nil),
+			}).WithType(cadence.VariableSizedArrayType{
+				ElementType: cadence.OptionalType{Type: cadence.IntType{}},
+			}),
+		},
+		encodeTest{
+			"Constant sized",
+			cadence.NewArray([]cadence.Value{
+				cadence.String("a"),
+				cadence.String("b"),
+			}).WithType(cadence.ConstantSizedArrayType{
+				ElementType: cadence.StringType{},
+				Size:        2,
+			}),
+		},
+		encodeTest{
+			"Resources",
+			cadence.NewArray([]cadence.Value{
+				cadence.NewResource([]cadence.Value{
+					cadence.NewInt(1),
+				}).WithType(fooResourceType),
+				cadence.NewResource([]cadence.Value{
+					cadence.NewInt(2),
+				}).WithType(fooResourceType),
+			}).WithType(cadence.VariableSizedArrayType{
+				ElementType: fooResourceType,
+			}),
+		},
+	)
+}
+
+func TestEncodeDictionary(t *testing.T) {
+
+	t.Parallel()
+
+	testAllEncodeAndDecode(t,
+		encodeTest{
+			"Simple",
+			cadence.NewDictionary([]cadence.KeyValuePair{
+				{
+					Key:   cadence.String("a"),
+					Value: cadence.NewInt(1),
+				},
+				{
+					Key:   cadence.String("b"),
+					Value: cadence.NewInt(2),
+				},
+			}),
+		},
+		encodeTest{
+			"Typed",
+			cadence.NewDictionary([]cadence.KeyValuePair{
+				{
+					Key: cadence.String("a"),
+					Value: cadence.NewDictionary([]cadence.KeyValuePair{
+						{
+							Key:   cadence.NewUInt8(1),
+							Value: cadence.NewResource([]cadence.Value{cadence.NewInt(1)}).WithType(fooResourceType),
+						},
+					}),
+				},
+			}).WithType(cadence.DictionaryType{
+				KeyType: cadence.StringType{},
+				ElementType: cadence.DictionaryType{
+					KeyType:     cadence.UInt8Type{},
+					ElementType: fooResourceType,
+				},
+			}),
+		},
+	)
+}
+
+func TestEncodeComposites(t *testing.T) {
+
+	t.Parallel()
+
+	eventType := &cadence.EventType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "FooEvent",
+		Fields: []cadence.Field{
+			{
+				Identifier: "a",
+				Type:       cadence.IntType{},
+			},
+		},
+		Initializer: []cadence.Parameter{
+			{
+				Label:      "a",
+				Identifier: "a",
+				Type:       cadence.IntType{},
+			},
+		},
+	}
+
+	contractType := &cadence.ContractType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "FooContract",
+		Fields: []cadence.Field{
+			{
+				Identifier: "x",
+				Type:       cadence.AnyStructType{},
+			},
+			{
+				Identifier: "f",
+				Type:       &cadence.FunctionType{ReturnType: cadence.VoidType{}},
+			},
+		},
+	}
+
+	enumType := &cadence.EnumType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "FooEnum",
+		RawType:             cadence.UInt8Type{},
+		Fields: []cadence.Field{
+			{
+				Identifier: sema.EnumRawValueFieldName,
+				Type:       cadence.UInt8Type{},
+			},
+		},
+	}
+
+	testAllEncodeAndDecode(t,
+		encodeTest{
+			"Struct",
+			cadence.NewStruct([]cadence.Value{
+				cadence.NewInt(1),
+				cadence.String("foo"),
+			}).WithType(simpleStructType),
+		},
+		encodeTest{
+			"Resource",
+			cadence.NewResource([]cadence.Value{
+				cadence.NewInt(1),
+			}).WithType(fooResourceType),
+		},
+		encodeTest{
+			"Event",
+			cadence.NewEvent([]cadence.Value{
+				cadence.NewInt(1),
+			}).WithType(eventType),
+		},
+		encodeTest{
+			"Contract with function field",
+			cadence.NewContract([]cadence.Value{
+				cadence.NewStruct([]cadence.Value{
+					cadence.NewInt(1),
+					cadence.String("foo"),
+				}).WithType(simpleStructType),
+			}).WithType(contractType),
+		},
+		encodeTest{
+			"Enum",
+			cadence.NewEnum([]cadence.Value{
+				cadence.NewUInt8(1),
+			}).WithType(enumType),
+		},
+	)
+}
+
+func TestEncodeRecursiveType(t *testing.T) {
+
+	t.Parallel()
+
+	ty := &cadence.ResourceType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Foo",
+		Fields: []cadence.Field{
+			{
+				Identifier: "foo",
+			},
+		},
+	}
+
+	ty.Fields[0].Type = cadence.OptionalType{
+		Type: ty,
+	}
+
+	testEncodeAndDecode(
+		t,
+		cadence.NewResource([]cadence.Value{
+			cadence.NewOptional(
+				cadence.NewResource([]cadence.Value{
+					cadence.NewOptional(nil),
+				}).WithType(ty),
+			),
+		}).WithType(ty),
+	)
+}
+
+func TestEncodeType(t *testing.T) {
+
+	t.Parallel()
+
+	interfaceType := &cadence.ResourceInterfaceType{
+		Location:            utils.TestLocation,
+		QualifiedIdentifier: "Bar",
+		Fields: []cadence.Field{
+			{
+				Identifier: "foo",
+				Type:       cadence.IntType{},
+			},
+		},
+	}
+
+	testAllEncodeAndDecode(t,
+		encodeTest{"Nil", cadence.NewTypeValue(nil)},
+		encodeTest{"Simple", cadence.NewTypeValue(cadence.PublicAccountType{})},
+		encodeTest{"Optional", cadence.NewTypeValue(cadence.OptionalType{Type: cadence.IntType{}})},
+		encodeTest{
+			"Variable sized array",
+			cadence.NewTypeValue(cadence.VariableSizedArrayType{ElementType: cadence.IntType{}}),
+		},
+		encodeTest{
+			"Constant sized array",
+			cadence.NewTypeValue(cadence.ConstantSizedArrayType{ElementType: cadence.IntType{}, Size: 3}),
+		},
+		encodeTest{
+			"Dictionary",
+			cadence.NewTypeValue(cadence.DictionaryType{KeyType: cadence.IntType{}, ElementType: cadence.StringType{}}),
+		},
+		encodeTest{
+			"Reference",
+			cadence.NewTypeValue(cadence.ReferenceType{Authorized: true, Type: fooResourceType}),
+		},
+		encodeTest{
+			"Restricted",
+			cadence.NewTypeValue(
+				cadence.NewRestrictedType(
+					"S.test.Foo{S.test.Bar}",
+					fooResourceType,
+					[]cadence.Type{interfaceType},
+				),
+			),
+		},
+		encodeTest{
+			"Capability",
+			cadence.NewTypeValue(cadence.CapabilityType{BorrowType: cadence.ReferenceType{Type: interfaceType}}),
+		},
+		encodeTest{
+			"Function",
+			cadence.NewTypeValue(
+				cadence.NewFunctionType(
+					"((String):Int)",
+					[]cadence.Parameter{
+						{
+							Label:      "_",
+							Identifier: "x",
+							Type:       cadence.StringType{},
+						},
+					},
+					cadence.IntType{},
+				),
+			),
+		},
+		encodeTest{"Composite", cadence.NewTypeValue(simpleStructType)},
+		encodeTest{"Interface", cadence.NewTypeValue(interfaceType)},
+	)
+}
+
+func TestEncodeTypeDefinitionsOnce(t *testing.T) {
+
+	t.Parallel()
+
+	values := make([]cadence.Value, 10)
+	for i := range values {
+		values[i] = cadence.NewStruct([]cadence.Value{
+			cadence.NewInt(i),
+			cadence.String("foo"),
+		}).WithType(simpleStructType)
+	}
+
+	value := cadence.NewArray(values).
+		WithType(cadence.VariableSizedArrayType{ElementType: simpleStructType})
+
+	encoded := testEncodeAndDecode(t, value)
+
+	assert.Equal(t, 1, bytes.Count(encoded, []byte(simpleStructType.ID())))
+
+	jsonEncoded, err := json.Encode(value)
+	require.NoError(t, err)
+
+	assert.Less(t, len(encoded)*4, len(jsonEncoded))
+}
+
+func TestEncodeUnsupported(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("missing type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ccf.Encode(cadence.NewStruct([]cadence.Value{}))
+		require.Error(t, err)
+	})
+
+	t.Run("field count mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ccf.Encode(
+			cadence.NewStruct([]cadence.Value{cadence.NewInt(1)}).
+				WithType(simpleStructType),
+		)
+		require.Error(t, err)
+	})
+}
+
+func exportFromScript(t *testing.T, code string) cadence.Value {
+	checker, err := checker.ParseAndCheck(t, code)
+	require.NoError(t, err)
+
+	var uuid uint64 = 0
+
+	inter, err := interpreter.NewInterpreter(
+		interpreter.ProgramFromChecker(checker),
+		checker.Location,
+		interpreter.WithUUIDHandler(
+			func() (uint64, error) {
+				uuid++
+				return uuid, nil
+			},
+		),
+		interpreter.WithStorage(
+			interpreter.NewInMemoryStorage(nil),
+		),
+	)
+	require.NoError(t, err)
+
+	err = inter.Interpret()
+	require.NoError(t, err)
+
+	result, err := inter.Invoke("main")
+	require.NoError(t, err)
+
+	exported, err := runtime.ExportValue(result, inter)
+	require.NoError(t, err)
+
+	return exported
+}
+
+func TestEncodeExportedValue(t *testing.T) {
+
+	t.Parallel()
+
+	value := exportFromScript(t, `
+      pub enum E: UInt8 {
+          pub case a
+          pub case b
+      }
+
+      pub struct S {
+          pub let e: E
+          pub let tags: {String: [Int?]}
+
+          init(e: E) {
+              self.e = e
+              self.tags = {"a": [1, nil]}
+          }
+
+          pub fun foo() {}
+      }
+
+      pub resource R {
+          pub let s: S
+          pub var children: @[R]
+
+          init(s: S) {
+              self.s = s
+              self.children <- []
+          }
+
+          pub fun add(_ child: @R) {
+              self.children.append(<-child)
+          }
+
+          destroy() {
+              destroy self.children
+          }
+      }
+
+      pub fun main(): @[R] {
+          let r <- create R(s: S(e: E.a))
+          r.add(<-create R(s: S(e: E.b)))
+          return <-[<-r]
+      }
+    `)
+
+	encoded, err := ccf.Encode(value)
+	require.NoError(t, err)
+
+	decoded, err := ccf.Decode(nil, encoded)
+	require.NoError(t, err)
+
+	// The decoded value encodes to the same JSON-Cadence as the exported value
+
+	expectedJSON, err := json.Encode(value)
+	require.NoError(t, err)
+
+	actualJSON, err := json.Encode(decoded)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, string(expectedJSON), string(actualJSON))
+
+	assert.Less(t, len(encoded), len(expectedJSON))
+}
+
+func mustMarshal(t *testing.T, v any) []byte {
+	data, err := cbor.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
+
+func TestDecodeInvalid(t *testing.T) {
+
+	t.Parallel()
+
+	const (
+		tagVoidValue      = 128
+		tagStringValue    = 132
+		tagInt8Value      = 135
+		tagUInt8Value     = 142
+		tagCompositeValue = 156
+		tagPathValue      = 158
+		tagTypeValue      = 159
+
+		intTypeCode = 17
+	)
+
+	fooTypeDef := []any{0, "S.test.Foo", []any{[]any{"a", intTypeCode}}, []any{}, nil}
+
+	tests := map[string][]byte{
+		"empty":         {},
+		"not a message": mustMarshal(t, []any{}),
+		"trailing data": append(
+			mustMarshal(t, []any{[]any{}, cbor.Tag{Number: tagVoidValue, Content: nil}}),
+			0,
+		),
+		// [[], 142(1)], with the integer in a non-canonical two byte encoding
+		"non-canonical integer": {0x82, 0x80, 0xd8, tagUInt8Value, 0x18, 0x01},
+		// indefinite-length array
+		"indefinite length": {0x9f, 0x80, 0xd8, tagVoidValue, 0xf6, 0xff},
+		"out of range": mustMarshal(t, []any{
+			[]any{},
+			cbor.Tag{Number: tagInt8Value, Content: 200},
+		}),
+		"unknown value tag": mustMarshal(t, []any{
+			[]any{},
+			cbor.Tag{Number: 1000, Content: nil},
+		}),
+		"unknown simple type": mustMarshal(t, []any{
+			[]any{},
+			cbor.Tag{Number: tagTypeValue, Content: 1000},
+		}),
+		"invalid path domain": mustMarshal(t, []any{
+			[]any{},
+			cbor.Tag{Number: tagPathValue, Content: []any{"foo", "bar"}},
+		}),
+		"invalid type definition index": mustMarshal(t, []any{
+			[]any{},
+			cbor.Tag{Number: tagCompositeValue, Content: []any{0, []any{}}},
+		}),
+		"invalid type ID": mustMarshal(t, []any{
+			[]any{[]any{0, "Foo", []any{}, []any{}, nil}},
+			cbor.Tag{Number: tagCompositeValue, Content: []any{0, []any{}}},
+		}),
+		"invalid type definition kind": mustMarshal(t, []any{
+			[]any{[]any{100, "S.test.Foo", []any{}, []any{}, nil}},
+			cbor.Tag{Number: tagCompositeValue, Content: []any{0, []any{}}},
+		}),
+		"duplicate type definition": mustMarshal(t, []any{
+			[]any{fooTypeDef, fooTypeDef},
+			cbor.Tag{Number: tagCompositeValue, Content: []any{0, []any{}}},
+		}),
+		"unused type definition": mustMarshal(t, []any{
+			[]any{fooTypeDef},
+			cbor.Tag{Number: tagVoidValue, Content: nil},
+		}),
+		"field count mismatch": mustMarshal(t, []any{
+			[]any{fooTypeDef},
+			cbor.Tag{Number: tagCompositeValue, Content: []any{0, []any{}}},
+		}),
+		"field type mismatch": mustMarshal(t, []any{
+			[]any{fooTypeDef},
+			cbor.Tag{
+				Number: tagCompositeValue,
+				Content: []any{
+					0,
+					[]any{cbor.Tag{Number: tagStringValue, Content: "foo"}},
+				},
+			},
+		}),
+	}
+
+	for name, data := range tests {
+		data := data
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			_, err := ccf.Decode(nil, data)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestDecodeValid(t *testing.T) {
+
+	t.Parallel()
+
+	const (
+		tagIntValue       = 134
+		tagCompositeValue = 156
+		intTypeCode       = 17
+	)
+
+	data := mustMarshal(t, []any{
+		[]any{
+			[]any{1, "S.test.Foo", []any{[]any{"bar", intTypeCode}}, []any{}, nil},
+		},
+		cbor.Tag{
+			Number: tagCompositeValue,
+			Content: []any{
+				0,
+				[]any{cbor.Tag{Number: tagIntValue, Content: 42}},
+			},
+		},
+	})
+
+	value, err := ccf.Decode(nil, data)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		cadence.NewResource([]cadence.Value{
+			cadence.NewInt(42),
+		}).WithType(fooResourceType),
+		value,
+	)
+}
+
+func FuzzDecode(f *testing.F) {
+
+	seeds := []cadence.Value{
+		cadence.NewVoid(),
+		cadence.NewOptional(cadence.String("foo")),
+		cadence.NewIntFromBig(bigIntFromString("-1267650600228229401496703205376")),
+		cadence.NewArray([]cadence.Value{
+			cadence.NewResource([]cadence.Value{
+				cadence.NewInt(1),
+			}).WithType(fooResourceType),
+		}).WithType(cadence.VariableSizedArrayType{
+			ElementType: fooResourceType,
+		}),
+		cadence.NewDictionary([]cadence.KeyValuePair{
+			{
+				Key:   cadence.String("a"),
+				Value: cadence.NewTypeValue(cadence.ReferenceType{Type: simpleStructType}),
+			},
+		}),
+		cadence.NewCapability(
+			cadence.NewPath("public", "foo"),
+			cadence.BytesToAddress([]byte{1}),
+			cadence.ReferenceType{Type: cadence.IntType{}},
+		),
+	}
+
+	for _, seed := range seeds {
+		f.Add(ccf.MustEncode(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ccf.Fuzz(data)
+	})
+}
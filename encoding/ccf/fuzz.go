@@ -0,0 +1,46 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ccf
+
+import (
+	"bytes"
+)
+
+// Fuzz is the fuzzing entry point for go-fuzz.
+//
+// It decodes the given data, and ensures that valid messages round-trip,
+// i.e. that re-encoding the decoded value produces the same data.
+func Fuzz(data []byte) int {
+
+	value, err := Decode(nil, data)
+	if err != nil {
+		return 0
+	}
+
+	encoded, err := Encode(value)
+	if err != nil {
+		panic(err)
+	}
+
+	if !bytes.Equal(encoded, data) {
+		panic("decoded value does not re-encode to the same data")
+	}
+
+	return 1
+}
@@ -34,13 +34,40 @@ import (
 )
 
 // A Decoder decodes JSON-encoded representations of Cadence values.
+//
+// The input is read as a stream of JSON tokens,
+// and the configured limits are enforced while reading,
+// so that malicious input is rejected without reading all of it.
 type Decoder struct {
 	dec   *json.Decoder
 	gauge common.MemoryGauge
 	// allowUnstructuredStaticTypes controls if the decoding
 	// of a static type as a type ID (cadence.TypeID) is allowed
 	allowUnstructuredStaticTypes bool
-}
+	// maxInputSize is the maximum number of bytes read from the input.
+	// Zero means unlimited
+	maxInputSize int64
+	// maxDepth is the maximum nesting depth of JSON arrays and objects.
+	// Zero means unlimited
+	maxDepth int
+	// maxArrayLength is the maximum number of elements of a JSON array,
+	// or members of a JSON object. Zero means unlimited
+	maxArrayLength int
+	// maxStringLength is the maximum length of a JSON string, in bytes.
+	// Zero means unlimited
+	maxStringLength int
+}
+
+// DefaultMaxDepth is the default maximum nesting depth of JSON arrays and objects.
+// It is the same as the maximum nesting depth supported by the standard library's JSON decoder.
+const DefaultMaxDepth = 10_000
+
+var (
+	ErrMaxInputSizeExceeded    = errors.New("maximum input size exceeded")
+	ErrMaxDepthExceeded        = errors.New("maximum nesting depth exceeded")
+	ErrMaxArrayLengthExceeded  = errors.New("maximum array length exceeded")
+	ErrMaxStringLengthExceeded = errors.New("maximum string length exceeded")
+)
 
 type Option func(*Decoder)
 
@@ -54,17 +81,58 @@ func WithAllowUnstructuredStaticTypes(allow bool) Option {
 	}
 }
 
+// WithMaxInputSize returns a new Decoder Option
+// which limits the number of bytes read from the input.
+// Zero means unlimited
+//
+func WithMaxInputSize(size int64) Option {
+	return func(decoder *Decoder) {
+		decoder.maxInputSize = size
+	}
+}
+
+// WithMaxDepth returns a new Decoder Option
+// which limits the nesting depth of JSON arrays and objects.
+// Zero means unlimited. The default is DefaultMaxDepth
+//
+func WithMaxDepth(depth int) Option {
+	return func(decoder *Decoder) {
+		decoder.maxDepth = depth
+	}
+}
+
+// WithMaxArrayLength returns a new Decoder Option
+// which limits the number of elements of JSON arrays,
+// and the number of members of JSON objects.
+// Zero means unlimited
+//
+func WithMaxArrayLength(length int) Option {
+	return func(decoder *Decoder) {
+		decoder.maxArrayLength = length
+	}
+}
+
+// WithMaxStringLength returns a new Decoder Option
+// which limits the length of JSON strings, in bytes.
+// Zero means unlimited
+//
+func WithMaxStringLength(length int) Option {
+	return func(decoder *Decoder) {
+		decoder.maxStringLength = length
+	}
+}
+
 // Decode returns a Cadence value decoded from its JSON-encoded representation.
 //
-// This function returns an error if the bytes represent JSON that is malformed
-// or does not conform to the JSON Cadence specification.
+// This function returns an error if the bytes represent JSON that is malformed,
+// does not conform to the JSON Cadence specification, or exceeds the limits
+// configured through the given options.
+//
+// By default, only the nesting depth is limited.
+// Untrusted input should be decoded with explicit limits.
 func Decode(gauge common.MemoryGauge, b []byte, options ...Option) (cadence.Value, error) {
 	r := bytes.NewReader(b)
-	dec := NewDecoder(gauge, r)
-
-	for _, option := range options {
-		option(dec)
-	}
+	dec := NewDecoder(gauge, r, options...)
 
 	v, err := dec.Decode()
 	if err != nil {
@@ -76,26 +144,35 @@ func Decode(gauge common.MemoryGauge, b []byte, options ...Option) (cadence.Valu
 
 // NewDecoder initializes a Decoder that will decode JSON-encoded bytes from the
 // given io.Reader.
-func NewDecoder(gauge common.MemoryGauge, r io.Reader) *Decoder {
-	return &Decoder{
-		dec:   json.NewDecoder(r),
-		gauge: gauge,
+func NewDecoder(gauge common.MemoryGauge, r io.Reader, options ...Option) *Decoder {
+	decoder := &Decoder{
+		gauge:    gauge,
+		maxDepth: DefaultMaxDepth,
+	}
+
+	for _, option := range options {
+		option(decoder)
 	}
+
+	if decoder.maxInputSize > 0 {
+		r = &limitedReader{
+			r:         r,
+			remaining: decoder.maxInputSize,
+		}
+	}
+
+	decoder.dec = json.NewDecoder(r)
+
+	return decoder
 }
 
 // Decode reads JSON-encoded bytes from the io.Reader and decodes them to a
 // Cadence value.
 //
-// This function returns an error if the bytes represent JSON that is malformed
-// or does not conform to the JSON Cadence specification.
+// This function returns an error if the bytes represent JSON that is malformed,
+// does not conform to the JSON Cadence specification, or exceeds the limits
+// configured for this decoder.
 func (d *Decoder) Decode() (value cadence.Value, err error) {
-	jsonMap := make(map[string]any)
-
-	err = d.dec.Decode(&jsonMap)
-	if err != nil {
-		return nil, fmt.Errorf("json-cdc: failed to decode valid JSON structure: %w", err)
-	}
-
 	// capture panics that occur during decoding
 	defer func() {
 		if r := recover(); r != nil {
@@ -108,10 +185,168 @@ func (d *Decoder) Decode() (value cadence.Value, err error) {
 		}
 	}()
 
+	jsonValue, err := d.readValue(0)
+	if err != nil {
+		return nil, fmt.Errorf("json-cdc: failed to decode valid JSON structure: %w", err)
+	}
+
+	jsonMap, ok := jsonValue.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("json-cdc: failed to decode valid JSON structure: %w", ErrInvalidJSONCadence)
+	}
+
 	value = d.decodeJSON(jsonMap)
 	return value, nil
 }
 
+// readValue reads the next JSON value from the token stream,
+// and returns it in the same representation as json.Unmarshal,
+// i.e. as a map[string]any, []any, string, float64, bool, or nil
+func (d *Decoder) readValue(depth int) (any, error) {
+	token, err := d.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		switch token {
+		case '{':
+			return d.readObject(depth + 1)
+		case '[':
+			return d.readArray(depth + 1)
+		default:
+			return nil, fmt.Errorf("unexpected delimiter: %s", token)
+		}
+
+	case string:
+		err := d.checkString(token)
+		if err != nil {
+			return nil, err
+		}
+		return token, nil
+
+	default:
+		// float64, bool, or nil
+		common.UseMemory(d.gauge, common.NewJSONValueMemoryUsage(0))
+		return token, nil
+	}
+}
+
+func (d *Decoder) readObject(depth int) (any, error) {
+	if d.maxDepth > 0 && depth > d.maxDepth {
+		return nil, ErrMaxDepthExceeded
+	}
+
+	common.UseMemory(d.gauge, common.NewJSONValueMemoryUsage(0))
+
+	result := map[string]any{}
+
+	for count := 1; d.dec.More(); count++ {
+		if d.maxArrayLength > 0 && count > d.maxArrayLength {
+			return nil, ErrMaxArrayLengthExceeded
+		}
+
+		token, err := d.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("unexpected object key: %v", token)
+		}
+
+		err = d.checkString(key)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := d.readValue(depth)
+		if err != nil {
+			return nil, err
+		}
+
+		result[key] = value
+	}
+
+	// consume the closing delimiter
+	_, err := d.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (d *Decoder) readArray(depth int) (any, error) {
+	if d.maxDepth > 0 && depth > d.maxDepth {
+		return nil, ErrMaxDepthExceeded
+	}
+
+	common.UseMemory(d.gauge, common.NewJSONValueMemoryUsage(0))
+
+	result := make([]any, 0)
+
+	for count := 1; d.dec.More(); count++ {
+		if d.maxArrayLength > 0 && count > d.maxArrayLength {
+			return nil, ErrMaxArrayLengthExceeded
+		}
+
+		value, err := d.readValue(depth)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, value)
+	}
+
+	// consume the closing delimiter
+	_, err := d.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (d *Decoder) checkString(s string) error {
+	if d.maxStringLength > 0 && len(s) > d.maxStringLength {
+		return ErrMaxStringLengthExceeded
+	}
+
+	common.UseMemory(d.gauge, common.NewJSONValueMemoryUsage(len(s)))
+
+	return nil
+}
+
+// limitedReader reads from the underlying reader,
+// and fails with ErrMaxInputSizeExceeded when more than the given number of bytes are available
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		// Check if there is more input
+		var b [1]byte
+		n, err := r.r.Read(b[:])
+		if n > 0 {
+			return 0, ErrMaxInputSizeExceeded
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+
+	n, err := r.r.Read(p)
+	r.remaining -= int64(n)
+	return n, err
+}
+
 const (
 	typeKey         = "type"
 	kindKey         = "kind"
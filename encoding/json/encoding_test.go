@@ -19,9 +19,12 @@
 package json_test
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
+	"strings"
 	"testing"
 	"unicode/utf8"
 
@@ -34,6 +37,7 @@ import (
 
 	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/sema"
 	"github.com/onflow/cadence/runtime/tests/utils"
 )
@@ -2054,3 +2058,169 @@ func TestDecodeBackwardsCompatibilityTypeID(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+// repeatingReader is an io.Reader which endlessly repeats the given data
+type repeatingReader struct {
+	data   []byte
+	offset int
+}
+
+func (r *repeatingReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = r.data[r.offset]
+		r.offset = (r.offset + 1) % len(r.data)
+	}
+	return len(p), nil
+}
+
+type testMemoryGauge struct {
+	meter map[common.MemoryKind]uint64
+	limit uint64
+}
+
+func (g *testMemoryGauge) MeterMemory(usage common.MemoryUsage) error {
+	g.meter[usage.Kind] += usage.Amount
+	if g.limit > 0 && g.meter[usage.Kind] > g.limit {
+		return errors.New("memory limit exceeded")
+	}
+	return nil
+}
+
+func TestDecodeLimits(t *testing.T) {
+
+	t.Parallel()
+
+	nestedArray := func(depth int) string {
+		return strings.Repeat(`{"type":"Array","value":[`, depth) +
+			strings.Repeat(`]}`, depth)
+	}
+
+	t.Run("depth", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := json.Decode(nil, []byte(nestedArray(5)), json.WithMaxDepth(10))
+		require.NoError(t, err)
+
+		_, err = json.Decode(nil, []byte(nestedArray(6)), json.WithMaxDepth(10))
+		require.ErrorIs(t, err, json.ErrMaxDepthExceeded)
+	})
+
+	t.Run("endless input", func(t *testing.T) {
+
+		t.Parallel()
+
+		// The input never ends, the limit must be enforced while reading
+
+		dec := json.NewDecoder(
+			nil,
+			&repeatingReader{data: []byte(`{"value":[`)},
+			json.WithMaxDepth(100),
+		)
+
+		_, err := dec.Decode()
+		require.ErrorIs(t, err, json.ErrMaxDepthExceeded)
+	})
+
+	t.Run("array length", func(t *testing.T) {
+
+		t.Parallel()
+
+		const encoded = `{"type":"Array","value":[{"type":"Int","value":"1"},{"type":"Int","value":"2"},{"type":"Int","value":"3"}]}`
+
+		_, err := json.Decode(nil, []byte(encoded), json.WithMaxArrayLength(3))
+		require.NoError(t, err)
+
+		_, err = json.Decode(nil, []byte(encoded), json.WithMaxArrayLength(2))
+		require.ErrorIs(t, err, json.ErrMaxArrayLengthExceeded)
+	})
+
+	t.Run("object members", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := json.Decode(
+			nil,
+			[]byte(`{"type":"Int","value":"1","a":1,"b":2}`),
+			json.WithMaxArrayLength(2),
+		)
+		require.ErrorIs(t, err, json.ErrMaxArrayLengthExceeded)
+	})
+
+	t.Run("string length", func(t *testing.T) {
+
+		t.Parallel()
+
+		const encoded = `{"type":"String","value":"abcdef"}`
+
+		_, err := json.Decode(nil, []byte(encoded), json.WithMaxStringLength(6))
+		require.NoError(t, err)
+
+		_, err = json.Decode(nil, []byte(encoded), json.WithMaxStringLength(5))
+		require.ErrorIs(t, err, json.ErrMaxStringLengthExceeded)
+	})
+
+	t.Run("input size", func(t *testing.T) {
+
+		t.Parallel()
+
+		const encoded = `{"type":"String","value":"abcdef"}`
+
+		_, err := json.Decode(nil, []byte(encoded), json.WithMaxInputSize(int64(len(encoded))))
+		require.NoError(t, err)
+
+		_, err = json.Decode(nil, []byte(encoded), json.WithMaxInputSize(int64(len(encoded)-1)))
+		require.ErrorIs(t, err, json.ErrMaxInputSizeExceeded)
+
+		// The input never ends, the limit must be enforced while reading
+
+		dec := json.NewDecoder(
+			nil,
+			io.MultiReader(
+				strings.NewReader(`{"type":"String","value":"`),
+				&repeatingReader{data: []byte("a")},
+			),
+			json.WithMaxInputSize(1000),
+		)
+
+		_, err = dec.Decode()
+		require.ErrorIs(t, err, json.ErrMaxInputSizeExceeded)
+	})
+
+	t.Run("memory metering", func(t *testing.T) {
+
+		t.Parallel()
+
+		const encoded = `{"type":"String","value":"abcdef"}`
+
+		gauge := &testMemoryGauge{
+			meter: map[common.MemoryKind]uint64{},
+		}
+
+		_, err := json.Decode(gauge, []byte(encoded))
+		require.NoError(t, err)
+
+		// object, keys (4 + 5 bytes), and values (6 + 6 bytes), each +1
+		assert.Equal(t, uint64(26), gauge.meter[common.MemoryKindJSONValue])
+	})
+
+	t.Run("memory limit", func(t *testing.T) {
+
+		t.Parallel()
+
+		gauge := &testMemoryGauge{
+			meter: map[common.MemoryKind]uint64{},
+			limit: 1000,
+		}
+
+		dec := json.NewDecoder(
+			gauge,
+			&repeatingReader{data: []byte(`{"type":"Array","value":[{"type":"Int","value":"1"},`)},
+			json.WithMaxDepth(0),
+		)
+
+		_, err := dec.Decode()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "memory limit exceeded")
+	})
+}
@@ -0,0 +1,238 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package schema generates JSON Schemas and TypeScript declarations
+// for the JSON-Cadence encoding of composite values,
+// as produced by the encoder in package json.
+//
+// The generated definitions are derived from the composite types of checked programs,
+// so that clients which consume exported script results and emitted events
+// can keep their types in sync with the Cadence declarations.
+package schema
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// A Generator collects composite types and generates
+// JSON Schemas and TypeScript declarations for their JSON-Cadence encoding.
+//
+// Composite types which are referenced by fields of added types
+// are added automatically.
+type Generator struct {
+	types     []*sema.CompositeType
+	names     map[sema.TypeID]string
+	usedNames map[string]struct{}
+}
+
+// NewGenerator returns a new, empty generator.
+func NewGenerator() *Generator {
+	return &Generator{
+		names:     map[sema.TypeID]string{},
+		usedNames: map[string]struct{}{},
+	}
+}
+
+// AddElaboration adds all composite types declared in the checked program,
+// ordered by type ID.
+func (g *Generator) AddElaboration(elaboration *sema.Elaboration) {
+	typeIDs := make([]string, 0, len(elaboration.CompositeTypes))
+	for typeID := range elaboration.CompositeTypes { //nolint:maprangecheck
+		typeIDs = append(typeIDs, string(typeID))
+	}
+	sort.Strings(typeIDs)
+
+	for _, typeID := range typeIDs {
+		g.AddType(elaboration.CompositeTypes[sema.TypeID(typeID)])
+	}
+}
+
+// AddType adds the given composite type,
+// and all composite types referenced by its fields.
+func (g *Generator) AddType(compositeType *sema.CompositeType) {
+	typeID := compositeType.ID()
+	if _, ok := g.names[typeID]; ok {
+		return
+	}
+
+	g.names[typeID] = g.newName(compositeType)
+	g.types = append(g.types, compositeType)
+
+	for _, field := range fields(compositeType) {
+		g.addReferencedTypes(field.TypeAnnotation.Type)
+	}
+}
+
+// Name returns the name of the definition generated for the given composite type,
+// i.e. the key in the JSON Schema definitions and the TypeScript type name.
+func (g *Generator) Name(compositeType *sema.CompositeType) (string, bool) {
+	name, ok := g.names[compositeType.ID()]
+	return name, ok
+}
+
+func (g *Generator) addReferencedTypes(ty sema.Type) {
+	switch ty := ty.(type) {
+	case *sema.CompositeType:
+		g.AddType(ty)
+
+	case *sema.OptionalType:
+		g.addReferencedTypes(ty.Type)
+
+	case sema.ArrayType:
+		g.addReferencedTypes(ty.ElementType(false))
+
+	case *sema.DictionaryType:
+		g.addReferencedTypes(ty.KeyType)
+		g.addReferencedTypes(ty.ValueType)
+
+	case *sema.ReferenceType:
+		g.addReferencedTypes(ty.Type)
+
+	case *sema.RestrictedType:
+		g.addReferencedTypes(ty.Type)
+	}
+}
+
+// newName returns a unique name for the given composite type.
+// The name is derived from the qualified identifier,
+// and falls back to the type ID if the qualified identifier is ambiguous,
+// e.g. when types with the same name are declared in different locations.
+func (g *Generator) newName(compositeType *sema.CompositeType) string {
+	name := sanitizeName(compositeType.QualifiedIdentifier())
+	if _, ok := g.usedNames[name]; ok {
+		name = sanitizeName(string(compositeType.ID()))
+	}
+	g.usedNames[name] = struct{}{}
+	return name
+}
+
+func sanitizeName(name string) string {
+	return strings.Map(
+		func(r rune) rune {
+			switch {
+			case r >= 'a' && r <= 'z',
+				r >= 'A' && r <= 'Z',
+				r >= '0' && r <= '9',
+				r == '_':
+				return r
+			default:
+				return '_'
+			}
+		},
+		name,
+	)
+}
+
+// fields returns the fields of the given composite type which are
+// included in its JSON-Cadence encoding, in declaration order.
+func fields(compositeType *sema.CompositeType) []*sema.Member {
+	result := make([]*sema.Member, 0, len(compositeType.Fields))
+
+	for _, identifier := range compositeType.Fields {
+		member, ok := compositeType.Members.Get(identifier)
+		if !ok {
+			panic(errors.NewUnreachableError())
+		}
+
+		if member.IgnoreInSerialization {
+			continue
+		}
+
+		if _, ok := member.TypeAnnotation.Type.(*sema.FunctionType); ok {
+			continue
+		}
+
+		result = append(result, member)
+	}
+
+	return result
+}
+
+// compositeKindName returns the JSON-Cadence value type name for the given composite kind
+func compositeKindName(kind common.CompositeKind) string {
+	switch kind {
+	case common.CompositeKindStructure:
+		return "Struct"
+	case common.CompositeKindResource:
+		return "Resource"
+	case common.CompositeKindEvent:
+		return "Event"
+	case common.CompositeKindContract:
+		return "Contract"
+	case common.CompositeKindEnum:
+		return "Enum"
+	default:
+		panic(errors.NewUnreachableError())
+	}
+}
+
+// numberPattern returns the regular expression matching the JSON-Cadence encoding
+// of values of the given number type, if the type is a concrete number type.
+func numberPattern(ty sema.Type) (string, bool) {
+	switch {
+	case containsType(sema.AllSignedIntegerTypes, ty):
+		return `^-?[0-9]+$`, true
+	case containsType(sema.AllUnsignedIntegerTypes, ty):
+		return `^[0-9]+$`, true
+	case containsType(sema.AllSignedFixedPointTypes, ty):
+		return `^-?[0-9]+\.[0-9]{8}$`, true
+	case containsType(sema.AllUnsignedFixedPointTypes, ty):
+		return `^[0-9]+\.[0-9]{8}$`, true
+	default:
+		return "", false
+	}
+}
+
+func containsType(types []sema.Type, ty sema.Type) bool {
+	for _, t := range types {
+		if t == ty {
+			return true
+		}
+	}
+	return false
+}
+
+// pathDomains returns the domains of the paths which are subtypes of the given path type
+func pathDomains(ty sema.Type) []string {
+	switch ty {
+	case sema.StoragePathType:
+		return []string{common.PathDomainStorage.Identifier()}
+	case sema.PublicPathType:
+		return []string{common.PathDomainPublic.Identifier()}
+	case sema.PrivatePathType:
+		return []string{common.PathDomainPrivate.Identifier()}
+	case sema.CapabilityPathType:
+		return []string{
+			common.PathDomainPublic.Identifier(),
+			common.PathDomainPrivate.Identifier(),
+		}
+	case sema.PathType:
+		return []string{
+			common.PathDomainStorage.Identifier(),
+			common.PathDomainPublic.Identifier(),
+			common.PathDomainPrivate.Identifier(),
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,295 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"strings"
+
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// Dialect is the JSON Schema dialect of the generated schemas
+const Dialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema.
+//
+// Only the keywords used by the generator are supported.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Const                any                `json:"const,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	PrefixItems          []*Schema          `json:"prefixItems,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// JSONSchema returns a JSON Schema document which contains a definition
+// for each composite type added to the generator.
+//
+// The definitions are keyed by the names returned by Name,
+// and can be referenced using e.g. `#/$defs/Name`.
+func (g *Generator) JSONSchema() *Schema {
+	defs := make(map[string]*Schema, len(g.types))
+
+	for _, compositeType := range g.types {
+		defs[g.names[compositeType.ID()]] = g.compositeSchema(compositeType)
+	}
+
+	return &Schema{
+		Schema: Dialect,
+		Defs:   defs,
+	}
+}
+
+func (g *Generator) compositeSchema(compositeType *sema.CompositeType) *Schema {
+	members := fields(compositeType)
+
+	fieldSchemas := make([]*Schema, len(members))
+	for i, member := range members {
+		fieldSchemas[i] = objectSchema(
+			map[string]*Schema{
+				"name":  {Const: member.Identifier.Identifier},
+				"value": g.valueSchema(member.TypeAnnotation.Type),
+			},
+			"name",
+			"value",
+		)
+		fieldSchemas[i].Description = strings.TrimSpace(member.DocString)
+	}
+
+	fieldCount := len(members)
+
+	result := valueObjectSchema(
+		compositeKindName(compositeType.Kind),
+		objectSchema(
+			map[string]*Schema{
+				"id": {Const: string(compositeType.ID())},
+				"fields": {
+					Type:        "array",
+					PrefixItems: fieldSchemas,
+					MinItems:    &fieldCount,
+					MaxItems:    &fieldCount,
+				},
+			},
+			"id",
+			"fields",
+		),
+	)
+	result.Title = string(compositeType.ID())
+
+	return result
+}
+
+// valueSchema returns the schema for the JSON-Cadence encoding of values of the given type
+func (g *Generator) valueSchema(ty sema.Type) *Schema {
+	switch ty := ty.(type) {
+	case *sema.CompositeType:
+		name, ok := g.names[ty.ID()]
+		if !ok {
+			return anyValueSchema()
+		}
+		return &Schema{Ref: "#/$defs/" + name}
+
+	case *sema.OptionalType:
+		return valueObjectSchema(
+			"Optional",
+			&Schema{
+				AnyOf: []*Schema{
+					{Type: "null"},
+					g.valueSchema(ty.Type),
+				},
+			},
+		)
+
+	case *sema.VariableSizedType:
+		return valueObjectSchema(
+			"Array",
+			&Schema{
+				Type:  "array",
+				Items: g.valueSchema(ty.Type),
+			},
+		)
+
+	case *sema.ConstantSizedType:
+		size := int(ty.Size)
+		return valueObjectSchema(
+			"Array",
+			&Schema{
+				Type:     "array",
+				Items:    g.valueSchema(ty.Type),
+				MinItems: &size,
+				MaxItems: &size,
+			},
+		)
+
+	case *sema.DictionaryType:
+		return valueObjectSchema(
+			"Dictionary",
+			&Schema{
+				Type: "array",
+				Items: objectSchema(
+					map[string]*Schema{
+						"key":   g.valueSchema(ty.KeyType),
+						"value": g.valueSchema(ty.ValueType),
+					},
+					"key",
+					"value",
+				),
+			},
+		)
+
+	case *sema.ReferenceType:
+		// references are exported as the referenced value
+		return g.valueSchema(ty.Type)
+
+	case *sema.RestrictedType:
+		return g.valueSchema(ty.Type)
+
+	case *sema.CapabilityType:
+		return valueObjectSchema(
+			"Capability",
+			objectSchema(
+				map[string]*Schema{
+					"path":       pathSchema(pathDomains(sema.CapabilityPathType)),
+					"address":    addressStringSchema(),
+					"borrowType": {},
+				},
+				"path",
+				"address",
+				"borrowType",
+			),
+		)
+
+	case *sema.AddressType:
+		return valueObjectSchema("Address", addressStringSchema())
+
+	case *sema.NumericType, *sema.FixedPointNumericType:
+		pattern, ok := numberPattern(ty)
+		if !ok {
+			return anyValueSchema()
+		}
+		return valueObjectSchema(
+			string(ty.ID()),
+			&Schema{
+				Type:    "string",
+				Pattern: pattern,
+			},
+		)
+	}
+
+	switch ty {
+	case sema.VoidType:
+		return valueObjectSchema("Void", nil)
+
+	case sema.BoolType:
+		return valueObjectSchema("Bool", &Schema{Type: "boolean"})
+
+	case sema.StringType:
+		return valueObjectSchema("String", &Schema{Type: "string"})
+
+	case sema.CharacterType:
+		return valueObjectSchema("Character", &Schema{Type: "string"})
+
+	case sema.MetaType:
+		return valueObjectSchema(
+			"Type",
+			objectSchema(
+				map[string]*Schema{
+					"staticType": {},
+				},
+				"staticType",
+			),
+		)
+	}
+
+	if domains := pathDomains(ty); domains != nil {
+		return pathSchema(domains)
+	}
+
+	return anyValueSchema()
+}
+
+func valueObjectSchema(typeName string, value *Schema) *Schema {
+	properties := map[string]*Schema{
+		"type": {Const: typeName},
+	}
+	required := []string{"type"}
+
+	if value != nil {
+		properties["value"] = value
+		required = append(required, "value")
+	}
+
+	return objectSchema(properties, required...)
+}
+
+func objectSchema(properties map[string]*Schema, required ...string) *Schema {
+	additionalProperties := false
+	return &Schema{
+		Type:                 "object",
+		Properties:           properties,
+		Required:             required,
+		AdditionalProperties: &additionalProperties,
+	}
+}
+
+// anyValueSchema returns the schema for the JSON-Cadence encoding of any value,
+// and is used for types which do not determine the encoding,
+// e.g. AnyStruct, or interface types
+func anyValueSchema() *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"type": {Type: "string"},
+		},
+		Required: []string{"type"},
+	}
+}
+
+func addressStringSchema() *Schema {
+	return &Schema{
+		Type:    "string",
+		Pattern: `^0x[0-9a-f]{16}$`,
+	}
+}
+
+func pathSchema(domains []string) *Schema {
+	return valueObjectSchema(
+		"Path",
+		objectSchema(
+			map[string]*Schema{
+				"domain":     {Enum: domains},
+				"identifier": {Type: "string"},
+			},
+			"domain",
+			"identifier",
+		),
+	)
+}
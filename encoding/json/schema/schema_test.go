@@ -0,0 +1,448 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/encoding/json/schema"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func check(t *testing.T, code string, location common.Location) *sema.Checker {
+	program, err := parser2.ParseProgram(code, nil)
+	require.NoError(t, err)
+
+	checker, err := sema.NewChecker(program, location, nil)
+	require.NoError(t, err)
+
+	err = checker.Check()
+	require.NoError(t, err)
+
+	return checker
+}
+
+const testCode = `
+  pub contract C {
+
+      pub struct S {
+          /// The ID of the item
+          pub let id: UInt64
+          pub let tags: [String]
+          pub let owner: Address?
+          pub let balances: {String: Fix64}
+          pub let kind: Kind
+          pub let path: PublicPath
+          pub let any: AnyStruct
+
+          init() {
+              self.id = 1
+              self.tags = []
+              self.owner = nil
+              self.balances = {}
+              self.kind = Kind.a
+              self.path = /public/s
+              self.any = 1
+          }
+
+          pub fun get(): UInt64 {
+              return self.id
+          }
+      }
+
+      pub enum Kind: UInt8 {
+          pub case a
+      }
+
+      pub event Deposited(id: UInt64, to: Address?)
+  }
+`
+
+func TestJSONSchema(t *testing.T) {
+
+	t.Parallel()
+
+	checker := check(t, testCode, common.StringLocation("test"))
+
+	generator := schema.NewGenerator()
+	generator.AddElaboration(checker.Elaboration)
+
+	result := generator.JSONSchema()
+
+	assert.Equal(t, schema.Dialect, result.Schema)
+
+	names := make([]string, 0, len(result.Defs))
+	for name, def := range result.Defs { //nolint:maprangecheck
+		names = append(names, name+": "+def.Title)
+	}
+	assert.ElementsMatch(t,
+		[]string{
+			"C: S.test.C",
+			"C_Deposited: S.test.C.Deposited",
+			"C_Kind: S.test.C.Kind",
+			"C_S: S.test.C.S",
+		},
+		names,
+	)
+
+	location := common.StringLocation("test")
+
+	kindType := &cadence.EnumType{
+		Location:            location,
+		QualifiedIdentifier: "C.Kind",
+		RawType:             cadence.UInt8Type{},
+		Fields: []cadence.Field{
+			{Identifier: "rawValue", Type: cadence.UInt8Type{}},
+		},
+	}
+
+	structType := &cadence.StructType{
+		Location:            location,
+		QualifiedIdentifier: "C.S",
+		Fields: []cadence.Field{
+			{Identifier: "id", Type: cadence.UInt64Type{}},
+			{Identifier: "tags", Type: cadence.VariableSizedArrayType{ElementType: cadence.StringType{}}},
+			{Identifier: "owner", Type: cadence.OptionalType{Type: cadence.AddressType{}}},
+			{Identifier: "balances", Type: cadence.DictionaryType{KeyType: cadence.StringType{}, ElementType: cadence.Fix64Type{}}},
+			{Identifier: "kind", Type: kindType},
+			{Identifier: "path", Type: cadence.PublicPathType{}},
+			{Identifier: "any", Type: cadence.AnyStructType{}},
+		},
+	}
+
+	newStruct := func(owner cadence.Optional, balance cadence.Value) cadence.Struct {
+		return cadence.NewStruct([]cadence.Value{
+			cadence.NewUInt64(1),
+			cadence.NewArray([]cadence.Value{
+				cadence.String("a"),
+				cadence.String("b"),
+			}),
+			owner,
+			cadence.NewDictionary([]cadence.KeyValuePair{
+				{
+					Key:   cadence.String("x"),
+					Value: balance,
+				},
+			}),
+			cadence.NewEnum([]cadence.Value{
+				cadence.NewUInt8(0),
+			}).WithType(kindType),
+			cadence.Path{
+				Domain:     "public",
+				Identifier: "s",
+			},
+			cadence.NewInt(42),
+		}).WithType(structType)
+	}
+
+	validValues := map[string]cadence.Value{
+		"nil owner": newStruct(
+			cadence.NewOptional(nil),
+			cadence.Fix64(-150000000),
+		),
+		"owner": newStruct(
+			cadence.NewOptional(cadence.BytesToAddress([]byte{0x1})),
+			cadence.Fix64(150000000),
+		),
+	}
+
+	for name, value := range validValues { //nolint:maprangecheck
+		t.Run(name, func(t *testing.T) {
+			assert.NoError(t, validate(result, "C_S", value))
+		})
+	}
+
+	invalidValues := map[string]cadence.Value{
+		"wrong balance type": newStruct(
+			cadence.NewOptional(nil),
+			cadence.UFix64(150000000),
+		),
+		"wrong owner type": newStruct(
+			cadence.NewOptional(cadence.String("0x1")),
+			cadence.Fix64(150000000),
+		),
+	}
+
+	for name, value := range invalidValues { //nolint:maprangecheck
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, validate(result, "C_S", value))
+		})
+	}
+
+	t.Run("field documentation", func(t *testing.T) {
+		fields := result.Defs["C_S"].Properties["value"].Properties["fields"]
+		require.Len(t, fields.PrefixItems, 7)
+		assert.Equal(t, "The ID of the item", fields.PrefixItems[0].Description)
+		assert.Equal(t, 7, *fields.MinItems)
+		assert.Equal(t, 7, *fields.MaxItems)
+	})
+}
+
+// validate validates the JSON-Cadence encoding of the given value
+// against the given definition of the given schema.
+//
+// Only the keywords produced by the generator are supported.
+func validate(document *schema.Schema, name string, value cadence.Value) error {
+	encoded, err := jsoncdc.Encode(value)
+	if err != nil {
+		return err
+	}
+
+	var decoded any
+	err = json.Unmarshal(encoded, &decoded)
+	if err != nil {
+		return err
+	}
+
+	return validateSchema(document, &schema.Schema{Ref: "#/$defs/" + name}, decoded)
+}
+
+func validateSchema(document *schema.Schema, s *schema.Schema, value any) error {
+	if s.Ref != "" {
+		def, ok := document.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if !ok {
+			return fmt.Errorf("unknown reference: %s", s.Ref)
+		}
+		return validateSchema(document, def, value)
+	}
+
+	if s.AnyOf != nil {
+		for _, alternative := range s.AnyOf {
+			if validateSchema(document, alternative, value) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("no alternative matches %v", value)
+	}
+
+	if s.Const != nil && s.Const != value {
+		return fmt.Errorf("expected %v, got %v", s.Const, value)
+	}
+
+	if s.Enum != nil {
+		found := false
+		for _, element := range s.Enum {
+			if element == value {
+				found = true
+			}
+		}
+		if !found {
+			return fmt.Errorf("expected one of %v, got %v", s.Enum, value)
+		}
+	}
+
+	switch s.Type {
+	case "":
+		return nil
+
+	case "null":
+		if value != nil {
+			return fmt.Errorf("expected null, got %v", value)
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("expected boolean, got %v", value)
+		}
+
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected string, got %v", value)
+		}
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(str) {
+			return fmt.Errorf("%s does not match %s", str, s.Pattern)
+		}
+
+	case "array":
+		elements, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("expected array, got %v", value)
+		}
+		if s.MinItems != nil && len(elements) < *s.MinItems ||
+			s.MaxItems != nil && len(elements) > *s.MaxItems {
+
+			return fmt.Errorf("unexpected number of elements: %d", len(elements))
+		}
+		for i, element := range elements {
+			elementSchema := s.Items
+			if i < len(s.PrefixItems) {
+				elementSchema = s.PrefixItems[i]
+			}
+			if elementSchema == nil {
+				continue
+			}
+			err := validateSchema(document, elementSchema, element)
+			if err != nil {
+				return err
+			}
+		}
+
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected object, got %v", value)
+		}
+		for _, required := range s.Required {
+			if _, ok := object[required]; !ok {
+				return fmt.Errorf("missing property %s", required)
+			}
+		}
+		for key, member := range object { //nolint:maprangecheck
+			propertySchema, ok := s.Properties[key]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("unexpected property %s", key)
+				}
+				continue
+			}
+			err := validateSchema(document, propertySchema, member)
+			if err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("unsupported type: %s", s.Type)
+	}
+
+	return nil
+}
+
+func TestTypeScript(t *testing.T) {
+
+	t.Parallel()
+
+	checker := check(t, testCode, common.StringLocation("test"))
+
+	generator := schema.NewGenerator()
+	generator.AddElaboration(checker.Elaboration)
+
+	const expected = `export type CadenceValue = { type: string; value?: unknown };
+
+/** S.test.C */
+export type C = {
+  type: "Contract";
+  value: {
+    id: "S.test.C";
+    fields: [];
+  };
+};
+
+/** S.test.C.Deposited */
+export type C_Deposited = {
+  type: "Event";
+  value: {
+    id: "S.test.C.Deposited";
+    fields: [
+      { name: "id"; value: { type: "UInt64"; value: string } },
+      { name: "to"; value: { type: "Optional"; value: { type: "Address"; value: string } | null } },
+    ];
+  };
+};
+
+/** S.test.C.Kind */
+export type C_Kind = {
+  type: "Enum";
+  value: {
+    id: "S.test.C.Kind";
+    fields: [
+      { name: "rawValue"; value: { type: "UInt8"; value: string } },
+    ];
+  };
+};
+
+/** S.test.C.S */
+export type C_S = {
+  type: "Struct";
+  value: {
+    id: "S.test.C.S";
+    fields: [
+      { name: "id"; value: { type: "UInt64"; value: string } },
+      { name: "tags"; value: { type: "Array"; value: Array<{ type: "String"; value: string }> } },
+      { name: "owner"; value: { type: "Optional"; value: { type: "Address"; value: string } | null } },
+      { name: "balances"; value: { type: "Dictionary"; value: Array<{ key: { type: "String"; value: string }; value: { type: "Fix64"; value: string } }> } },
+      { name: "kind"; value: C_Kind },
+      { name: "path"; value: { type: "Path"; value: { domain: "public"; identifier: string } } },
+      { name: "any"; value: CadenceValue },
+    ];
+  };
+};
+`
+
+	assert.Equal(t, expected, generator.TypeScript())
+}
+
+func TestNames(t *testing.T) {
+
+	t.Parallel()
+
+	const code = `
+      pub struct S {
+          pub let t: T
+
+          init() {
+              self.t = T()
+          }
+      }
+
+      pub struct T {}
+    `
+
+	checkerA := check(t, code, common.StringLocation("a"))
+	checkerB := check(t, code, common.StringLocation("b"))
+
+	generator := schema.NewGenerator()
+
+	// adding a type also adds the types of its fields
+
+	generator.AddType(checkerA.Elaboration.CompositeTypes["S.a.S"])
+	generator.AddElaboration(checkerB.Elaboration)
+
+	for typeID, expectedName := range map[sema.TypeID]string{ //nolint:maprangecheck
+		"S.a.S": "S",
+		"S.a.T": "T",
+		"S.b.S": "S_b_S",
+		"S.b.T": "S_b_T",
+	} {
+		var compositeType *sema.CompositeType
+		if location, _, _ := common.DecodeTypeID(nil, string(typeID)); location == common.StringLocation("a") {
+			compositeType = checkerA.Elaboration.CompositeTypes[typeID]
+		} else {
+			compositeType = checkerB.Elaboration.CompositeTypes[typeID]
+		}
+
+		name, ok := generator.Name(compositeType)
+		require.True(t, ok)
+		assert.Equal(t, expectedName, name)
+	}
+
+	assert.Len(t, generator.JSONSchema().Defs, 4)
+}
@@ -0,0 +1,172 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// TypeScriptPrelude declares the types which are referenced by the generated declarations
+const TypeScriptPrelude = `export type CadenceValue = { type: string; value?: unknown };
+`
+
+// TypeScript returns TypeScript declarations for the JSON-Cadence encoding
+// of each composite type added to the generator.
+//
+// The declared types are named by the names returned by Name.
+func (g *Generator) TypeScript() string {
+	var builder strings.Builder
+
+	builder.WriteString(TypeScriptPrelude)
+
+	for _, compositeType := range g.types {
+		builder.WriteString("\n")
+		g.writeCompositeTypeScript(&builder, compositeType)
+	}
+
+	return builder.String()
+}
+
+func (g *Generator) writeCompositeTypeScript(builder *strings.Builder, compositeType *sema.CompositeType) {
+	typeID := string(compositeType.ID())
+
+	fmt.Fprintf(builder, "/** %s */\n", typeID)
+	fmt.Fprintf(builder, "export type %s = {\n", g.names[compositeType.ID()])
+	fmt.Fprintf(builder, "  type: %s;\n", strconv.Quote(compositeKindName(compositeType.Kind)))
+	builder.WriteString("  value: {\n")
+	fmt.Fprintf(builder, "    id: %s;\n", strconv.Quote(typeID))
+
+	members := fields(compositeType)
+	if len(members) == 0 {
+		builder.WriteString("    fields: [];\n")
+	} else {
+		builder.WriteString("    fields: [\n")
+		for _, member := range members {
+			fmt.Fprintf(
+				builder,
+				"      { name: %s; value: %s },\n",
+				strconv.Quote(member.Identifier.Identifier),
+				g.valueTypeScript(member.TypeAnnotation.Type),
+			)
+		}
+		builder.WriteString("    ];\n")
+	}
+
+	builder.WriteString("  };\n")
+	builder.WriteString("};\n")
+}
+
+// valueTypeScript returns the TypeScript type for the JSON-Cadence encoding of values of the given type
+func (g *Generator) valueTypeScript(ty sema.Type) string {
+	switch ty := ty.(type) {
+	case *sema.CompositeType:
+		name, ok := g.names[ty.ID()]
+		if !ok {
+			return "CadenceValue"
+		}
+		return name
+
+	case *sema.OptionalType:
+		return valueObjectTypeScript("Optional", g.valueTypeScript(ty.Type)+" | null")
+
+	case sema.ArrayType:
+		return valueObjectTypeScript("Array", fmt.Sprintf("Array<%s>", g.valueTypeScript(ty.ElementType(false))))
+
+	case *sema.DictionaryType:
+		return valueObjectTypeScript(
+			"Dictionary",
+			fmt.Sprintf(
+				"Array<{ key: %s; value: %s }>",
+				g.valueTypeScript(ty.KeyType),
+				g.valueTypeScript(ty.ValueType),
+			),
+		)
+
+	case *sema.ReferenceType:
+		// references are exported as the referenced value
+		return g.valueTypeScript(ty.Type)
+
+	case *sema.RestrictedType:
+		return g.valueTypeScript(ty.Type)
+
+	case *sema.CapabilityType:
+		return valueObjectTypeScript(
+			"Capability",
+			fmt.Sprintf(
+				"{ path: %s; address: string; borrowType: unknown }",
+				pathTypeScript(pathDomains(sema.CapabilityPathType)),
+			),
+		)
+
+	case *sema.AddressType:
+		return valueObjectTypeScript("Address", "string")
+
+	case *sema.NumericType, *sema.FixedPointNumericType:
+		if _, ok := numberPattern(ty); !ok {
+			return "CadenceValue"
+		}
+		return valueObjectTypeScript(string(ty.ID()), "string")
+	}
+
+	switch ty {
+	case sema.VoidType:
+		return `{ type: "Void" }`
+
+	case sema.BoolType:
+		return valueObjectTypeScript("Bool", "boolean")
+
+	case sema.StringType:
+		return valueObjectTypeScript("String", "string")
+
+	case sema.CharacterType:
+		return valueObjectTypeScript("Character", "string")
+
+	case sema.MetaType:
+		return valueObjectTypeScript("Type", "{ staticType: unknown }")
+	}
+
+	if domains := pathDomains(ty); domains != nil {
+		return pathTypeScript(domains)
+	}
+
+	return "CadenceValue"
+}
+
+func valueObjectTypeScript(typeName string, valueType string) string {
+	return fmt.Sprintf("{ type: %s; value: %s }", strconv.Quote(typeName), valueType)
+}
+
+func pathTypeScript(domains []string) string {
+	quotedDomains := make([]string, len(domains))
+	for i, domain := range domains {
+		quotedDomains[i] = strconv.Quote(domain)
+	}
+
+	return valueObjectTypeScript(
+		"Path",
+		fmt.Sprintf(
+			"{ domain: %s; identifier: string }",
+			strings.Join(quotedDomains, " | "),
+		),
+	)
+}
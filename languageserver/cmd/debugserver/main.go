@@ -0,0 +1,87 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/sourcegraph/jsonrpc2"
+
+	"github.com/onflow/cadence/languageserver/debugserver"
+	"github.com/onflow/cadence/languageserver/server"
+)
+
+var portFlag = flag.Int("port", 0, "listen for connections on the given port, instead of using STDIN and STDOUT")
+
+func main() {
+	flag.Parse()
+
+	if *portFlag > 0 {
+		runWithPort(*portFlag)
+		return
+	}
+
+	if isatty.IsTerminal(os.Stdout.Fd()) {
+		print(
+			"This program implements the Debug Adapter Protocol for Cadence.\n" +
+				"Please check the documentation on how to run it.\n" +
+				"It does nothing in a terminal, it should be run with an editor/IDE.\n",
+		)
+		os.Exit(1)
+	}
+
+	stream := jsonrpc2.NewBufferedStream(
+		server.StdinStdoutReadWriterCloser{},
+		jsonrpc2.VSCodeObjectCodec{},
+	)
+
+	err := debugserver.NewServer().Serve(stream)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// runWithPort serves each connection with a new debug server, one program per connection
+func runWithPort(port int) {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		go func() {
+			stream := jsonrpc2.NewBufferedStream(conn, jsonrpc2.VSCodeObjectCodec{})
+			err := debugserver.NewServer().Serve(stream)
+			if err != nil {
+				log.Print(err)
+			}
+			_ = stream.Close()
+		}()
+	}
+}
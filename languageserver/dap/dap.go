@@ -0,0 +1,297 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package dap contains data types for the subset of the Debug Adapter Protocol (DAP)
+// which is supported by the Cadence debug server.
+//
+// https://microsoft.github.io/debug-adapter-protocol/specification
+package dap
+
+import "encoding/json"
+
+// Message types
+
+const (
+	MessageTypeRequest  = "request"
+	MessageTypeResponse = "response"
+	MessageTypeEvent    = "event"
+)
+
+// ProtocolMessage is the base of all messages
+type ProtocolMessage struct {
+	Seq  int    `json:"seq"`
+	Type string `json:"type"`
+}
+
+// Request is a client or debug adapter initiated request
+type Request struct {
+	ProtocolMessage
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// Response is the response for a request
+type Response struct {
+	ProtocolMessage
+	RequestSeq int    `json:"request_seq"`
+	Success    bool   `json:"success"`
+	Command    string `json:"command"`
+	Message    string `json:"message,omitempty"`
+	Body       any    `json:"body,omitempty"`
+}
+
+// Event is a debug adapter initiated event
+type Event struct {
+	ProtocolMessage
+	Event string `json:"event"`
+	Body  any    `json:"body,omitempty"`
+}
+
+// Commands
+
+const (
+	CommandInitialize        = "initialize"
+	CommandLaunch            = "launch"
+	CommandSetBreakpoints    = "setBreakpoints"
+	CommandConfigurationDone = "configurationDone"
+	CommandThreads           = "threads"
+	CommandStackTrace        = "stackTrace"
+	CommandScopes            = "scopes"
+	CommandVariables         = "variables"
+	CommandContinue          = "continue"
+	CommandNext              = "next"
+	CommandStepIn            = "stepIn"
+	CommandStepOut           = "stepOut"
+	CommandPause             = "pause"
+	CommandEvaluate          = "evaluate"
+	CommandDisconnect        = "disconnect"
+)
+
+// Events
+
+const (
+	EventInitialized = "initialized"
+	EventStopped     = "stopped"
+	EventContinued   = "continued"
+	EventOutput      = "output"
+	EventExited      = "exited"
+	EventTerminated  = "terminated"
+)
+
+// Reasons for stopped events
+
+const (
+	StoppedReasonEntry      = "entry"
+	StoppedReasonStep       = "step"
+	StoppedReasonBreakpoint = "breakpoint"
+	StoppedReasonPause      = "pause"
+)
+
+// Categories for output events
+
+const (
+	OutputCategoryConsole = "console"
+	OutputCategoryStdout  = "stdout"
+	OutputCategoryStderr  = "stderr"
+)
+
+// Capabilities are the capabilities of the debug adapter
+type Capabilities struct {
+	SupportsConfigurationDoneRequest bool `json:"supportsConfigurationDoneRequest,omitempty"`
+	SupportsConditionalBreakpoints   bool `json:"supportsConditionalBreakpoints,omitempty"`
+	SupportsEvaluateForHovers        bool `json:"supportsEvaluateForHovers,omitempty"`
+}
+
+// InitializeRequestArguments are the arguments of the initialize request
+type InitializeRequestArguments struct {
+	ClientID        string `json:"clientID,omitempty"`
+	AdapterID       string `json:"adapterID"`
+	LinesStartAt1   *bool  `json:"linesStartAt1,omitempty"`
+	ColumnsStartAt1 *bool  `json:"columnsStartAt1,omitempty"`
+}
+
+// LaunchRequestArguments are the arguments of the launch request.
+//
+// The launch arguments are specific to the debug adapter:
+// Program is the path of the script or transaction,
+// Arguments are the JSON-Cadence encoded arguments,
+// and Signers are the addresses of the signers of a transaction
+type LaunchRequestArguments struct {
+	NoDebug     bool     `json:"noDebug,omitempty"`
+	Program     string   `json:"program"`
+	Arguments   []string `json:"arguments,omitempty"`
+	Signers     []string `json:"signers,omitempty"`
+	StopOnEntry bool     `json:"stopOnEntry,omitempty"`
+}
+
+// Source is a source, e.g. a file
+type Source struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+// SourceBreakpoint is a breakpoint requested by the client
+type SourceBreakpoint struct {
+	Line      int    `json:"line"`
+	Column    int    `json:"column,omitempty"`
+	Condition string `json:"condition,omitempty"`
+}
+
+// SetBreakpointsArguments are the arguments of the setBreakpoints request
+type SetBreakpointsArguments struct {
+	Source      Source             `json:"source"`
+	Breakpoints []SourceBreakpoint `json:"breakpoints,omitempty"`
+}
+
+// Breakpoint is a breakpoint set by the debug adapter
+type Breakpoint struct {
+	Verified bool    `json:"verified"`
+	Message  string  `json:"message,omitempty"`
+	Source   *Source `json:"source,omitempty"`
+	Line     int     `json:"line,omitempty"`
+}
+
+// SetBreakpointsResponseBody is the body of the setBreakpoints response
+type SetBreakpointsResponseBody struct {
+	Breakpoints []Breakpoint `json:"breakpoints"`
+}
+
+// Thread is a thread
+type Thread struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ThreadsResponseBody is the body of the threads response
+type ThreadsResponseBody struct {
+	Threads []Thread `json:"threads"`
+}
+
+// StackTraceArguments are the arguments of the stackTrace request
+type StackTraceArguments struct {
+	ThreadID   int `json:"threadId"`
+	StartFrame int `json:"startFrame,omitempty"`
+	Levels     int `json:"levels,omitempty"`
+}
+
+// StackFrame is a stack frame
+type StackFrame struct {
+	ID     int     `json:"id"`
+	Name   string  `json:"name"`
+	Source *Source `json:"source,omitempty"`
+	Line   int     `json:"line"`
+	Column int     `json:"column"`
+}
+
+// StackTraceResponseBody is the body of the stackTrace response
+type StackTraceResponseBody struct {
+	StackFrames []StackFrame `json:"stackFrames"`
+	TotalFrames int          `json:"totalFrames,omitempty"`
+}
+
+// ScopesArguments are the arguments of the scopes request
+type ScopesArguments struct {
+	FrameID int `json:"frameId"`
+}
+
+// Scope is a named container for variables
+type Scope struct {
+	Name               string `json:"name"`
+	VariablesReference int    `json:"variablesReference"`
+	Expensive          bool   `json:"expensive"`
+}
+
+// ScopesResponseBody is the body of the scopes response
+type ScopesResponseBody struct {
+	Scopes []Scope `json:"scopes"`
+}
+
+// VariablesArguments are the arguments of the variables request
+type VariablesArguments struct {
+	VariablesReference int `json:"variablesReference"`
+}
+
+// Variable is a name-value pair.
+// If the variables reference is greater than zero,
+// the variable is structured and its children can be retrieved
+// with a variables request
+type Variable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+// VariablesResponseBody is the body of the variables response
+type VariablesResponseBody struct {
+	Variables []Variable `json:"variables"`
+}
+
+// ThreadArguments are the arguments of the continue, next, stepIn, stepOut, and pause requests
+type ThreadArguments struct {
+	ThreadID int `json:"threadId"`
+}
+
+// ContinueResponseBody is the body of the continue response
+type ContinueResponseBody struct {
+	AllThreadsContinued bool `json:"allThreadsContinued"`
+}
+
+// EvaluateArguments are the arguments of the evaluate request
+type EvaluateArguments struct {
+	Expression string `json:"expression"`
+	FrameID    int    `json:"frameId,omitempty"`
+	Context    string `json:"context,omitempty"`
+}
+
+// EvaluateResponseBody is the body of the evaluate response
+type EvaluateResponseBody struct {
+	Result             string `json:"result"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+// DisconnectArguments are the arguments of the disconnect request
+type DisconnectArguments struct {
+	TerminateDebuggee bool `json:"terminateDebuggee,omitempty"`
+}
+
+// StoppedEventBody is the body of the stopped event
+type StoppedEventBody struct {
+	Reason            string `json:"reason"`
+	Description       string `json:"description,omitempty"`
+	ThreadID          int    `json:"threadId"`
+	AllThreadsStopped bool   `json:"allThreadsStopped"`
+}
+
+// ContinuedEventBody is the body of the continued event
+type ContinuedEventBody struct {
+	ThreadID            int  `json:"threadId"`
+	AllThreadsContinued bool `json:"allThreadsContinued"`
+}
+
+// OutputEventBody is the body of the output event
+type OutputEventBody struct {
+	Category string `json:"category,omitempty"`
+	Output   string `json:"output"`
+}
+
+// ExitedEventBody is the body of the exited event
+type ExitedEventBody struct {
+	ExitCode int `json:"exitCode"`
+}
@@ -0,0 +1,86 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package debugserver
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/inmemory"
+)
+
+// ledgerInterface is a runtime.Interface which stores all data in memory.
+//
+// String locations are file paths, relative paths are resolved against the directory of the launched program.
+// Contracts are stored in the ledger, and can be deployed by a launched transaction
+type ledgerInterface struct {
+	*inmemory.Interface
+	directory string
+}
+
+var _ runtime.Interface = &ledgerInterface{}
+
+func newLedgerInterface(
+	directory string,
+	signers []runtime.Address,
+	onLog func(message string),
+	onEmitEvent func(event cadence.Event),
+) *ledgerInterface {
+	i := &ledgerInterface{
+		Interface: inmemory.NewInterface(
+			inmemory.WithOnLogHandler(onLog),
+			inmemory.WithOnEventEmittedHandler(onEmitEvent),
+		),
+		directory: directory,
+	}
+	i.SetSigningAccounts(signers...)
+	return i
+}
+
+// resolvePath resolves the path of a string location
+func (i *ledgerInterface) resolvePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(i.directory, path)
+}
+
+func (i *ledgerInterface) ResolveLocation(identifiers []runtime.Identifier, location runtime.Location) ([]runtime.ResolvedLocation, error) {
+	if stringLocation, ok := location.(common.StringLocation); ok {
+		return []runtime.ResolvedLocation{
+			{
+				Location:    common.StringLocation(i.resolvePath(string(stringLocation))),
+				Identifiers: identifiers,
+			},
+		}, nil
+	}
+
+	return i.Interface.ResolveLocation(identifiers, location)
+}
+
+func (i *ledgerInterface) GetCode(location runtime.Location) ([]byte, error) {
+	if stringLocation, ok := location.(common.StringLocation); ok {
+		return os.ReadFile(i.resolvePath(string(stringLocation)))
+	}
+
+	return i.Interface.GetCode(location)
+}
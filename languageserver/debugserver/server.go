@@ -0,0 +1,611 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package debugserver implements a Debug Adapter Protocol (DAP) server for Cadence programs.
+//
+// Launched scripts and transactions are executed by the interpreter runtime against an in-memory ledger,
+// and debugged using the interpreter's debugger
+package debugserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/jsonrpc2"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/languageserver/dap"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/parser2"
+)
+
+// threadID is the ID of the only thread, the execution of the launched program
+const threadID = 1
+
+// Server is a Debug Adapter Protocol server.
+// It debugs one program per session
+type Server struct {
+	stream   jsonrpc2.ObjectStream
+	debugger *interpreter.Debugger
+	// writeMutex guards the writes to the stream and the sequence number
+	writeMutex sync.Mutex
+	seq        int
+	// mutex guards the fields below,
+	// which are accessed by the request handlers, and by the goroutines
+	// which execute the program and forward its stops
+	mutex       sync.Mutex
+	launch      *dap.LaunchRequestArguments
+	breakpoints map[string][]int
+	started     bool
+	// stop is the current stop, if the execution is stopped
+	stop *interpreter.Stop
+	// stopReason is the reason for the next stop, if it is not caused by a breakpoint
+	stopReason string
+	// variables are the variables references of the current stop.
+	// The reference of a variables entry is its index plus one
+	variables []variablesReference
+	done      chan struct{}
+}
+
+func NewServer() *Server {
+	return &Server{
+		debugger:    interpreter.NewDebugger(),
+		breakpoints: map[string][]int{},
+		done:        make(chan struct{}),
+	}
+}
+
+// Serve reads requests from the given stream and handles them,
+// until the stream is closed or the client disconnects
+func (s *Server) Serve(stream jsonrpc2.ObjectStream) error {
+	s.stream = stream
+
+	for {
+		var request dap.Request
+		err := stream.ReadObject(&request)
+		if err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			return err
+		}
+
+		if request.Type != dap.MessageTypeRequest {
+			continue
+		}
+
+		body, err := s.handle(&request)
+
+		response := dap.Response{
+			ProtocolMessage: dap.ProtocolMessage{
+				Type: dap.MessageTypeResponse,
+			},
+			RequestSeq: request.Seq,
+			Success:    err == nil,
+			Command:    request.Command,
+			Body:       body,
+		}
+		if err != nil {
+			response.Message = err.Error()
+		}
+
+		err = s.send(&response.ProtocolMessage, &response)
+		if err != nil {
+			return err
+		}
+
+		switch request.Command {
+		case dap.CommandInitialize:
+			if response.Success {
+				s.sendEvent(dap.EventInitialized, nil)
+			}
+
+		case dap.CommandDisconnect:
+			return nil
+		}
+	}
+}
+
+// send sends the given message, after assigning it the next sequence number
+func (s *Server) send(protocolMessage *dap.ProtocolMessage, message any) error {
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	s.seq++
+	protocolMessage.Seq = s.seq
+
+	return s.stream.WriteObject(message)
+}
+
+func (s *Server) sendEvent(name string, body any) {
+	event := dap.Event{
+		ProtocolMessage: dap.ProtocolMessage{
+			Type: dap.MessageTypeEvent,
+		},
+		Event: name,
+		Body:  body,
+	}
+	_ = s.send(&event.ProtocolMessage, &event)
+}
+
+func (s *Server) sendOutput(category string, output string) {
+	s.sendEvent(
+		dap.EventOutput,
+		dap.OutputEventBody{
+			Category: category,
+			Output:   output + "\n",
+		},
+	)
+}
+
+func (s *Server) handle(request *dap.Request) (any, error) {
+	switch request.Command {
+	case dap.CommandInitialize:
+		return s.initialize()
+
+	case dap.CommandLaunch:
+		var arguments dap.LaunchRequestArguments
+		err := decodeArguments(request, &arguments)
+		if err != nil {
+			return nil, err
+		}
+		return nil, s.handleLaunch(&arguments)
+
+	case dap.CommandSetBreakpoints:
+		var arguments dap.SetBreakpointsArguments
+		err := decodeArguments(request, &arguments)
+		if err != nil {
+			return nil, err
+		}
+		return s.setBreakpoints(&arguments)
+
+	case dap.CommandConfigurationDone:
+		return nil, s.configurationDone()
+
+	case dap.CommandThreads:
+		return dap.ThreadsResponseBody{
+			Threads: []dap.Thread{
+				{
+					ID:   threadID,
+					Name: "main",
+				},
+			},
+		}, nil
+
+	case dap.CommandStackTrace:
+		var arguments dap.StackTraceArguments
+		err := decodeArguments(request, &arguments)
+		if err != nil {
+			return nil, err
+		}
+		return s.stackTrace(&arguments)
+
+	case dap.CommandScopes:
+		var arguments dap.ScopesArguments
+		err := decodeArguments(request, &arguments)
+		if err != nil {
+			return nil, err
+		}
+		return s.scopes(&arguments)
+
+	case dap.CommandVariables:
+		var arguments dap.VariablesArguments
+		err := decodeArguments(request, &arguments)
+		if err != nil {
+			return nil, err
+		}
+		return s.variablesOf(&arguments)
+
+	case dap.CommandContinue:
+		err := s.resume(func() {})
+		if err != nil {
+			return nil, err
+		}
+		return dap.ContinueResponseBody{
+			AllThreadsContinued: true,
+		}, nil
+
+	case dap.CommandNext:
+		return nil, s.resume(s.debugger.RequestStepOver)
+
+	case dap.CommandStepIn:
+		return nil, s.resume(s.debugger.RequestPause)
+
+	case dap.CommandStepOut:
+		return nil, s.resume(s.debugger.RequestStepOut)
+
+	case dap.CommandPause:
+		s.mutex.Lock()
+		s.stopReason = dap.StoppedReasonPause
+		s.mutex.Unlock()
+
+		s.debugger.RequestPause()
+		return nil, nil
+
+	case dap.CommandEvaluate:
+		var arguments dap.EvaluateArguments
+		err := decodeArguments(request, &arguments)
+		if err != nil {
+			return nil, err
+		}
+		return s.evaluate(&arguments)
+
+	case dap.CommandDisconnect:
+		s.disconnect()
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported command: %s", request.Command)
+	}
+}
+
+func decodeArguments(request *dap.Request, arguments any) error {
+	if len(request.Arguments) == 0 {
+		return nil
+	}
+	err := json.Unmarshal(request.Arguments, arguments)
+	if err != nil {
+		return fmt.Errorf("invalid arguments for command %s: %w", request.Command, err)
+	}
+	return nil
+}
+
+func (s *Server) initialize() (any, error) {
+	return dap.Capabilities{
+		SupportsConfigurationDoneRequest: true,
+		SupportsConditionalBreakpoints:   true,
+		SupportsEvaluateForHovers:        true,
+	}, nil
+}
+
+func (s *Server) handleLaunch(arguments *dap.LaunchRequestArguments) error {
+	if arguments.Program == "" {
+		return fmt.Errorf("missing program")
+	}
+
+	program, err := filepath.Abs(arguments.Program)
+	if err != nil {
+		return err
+	}
+	arguments.Program = program
+
+	_, err = os.Stat(program)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.launch != nil {
+		return fmt.Errorf("program already launched")
+	}
+
+	s.launch = arguments
+
+	if arguments.NoDebug {
+		s.debugger.ClearBreakpoints()
+	} else if arguments.StopOnEntry {
+		s.stopReason = dap.StoppedReasonEntry
+		s.debugger.RequestPause()
+	}
+
+	return nil
+}
+
+// parseExpression parses the given code as an expression,
+// e.g. the condition of a breakpoint, or an expression to evaluate
+func parseExpression(code string) (ast.Expression, error) {
+	expression, errs := parser2.ParseExpression(code, nil)
+	if len(errs) > 0 {
+		return nil, parser2.Error{
+			Code:   code,
+			Errors: errs,
+		}
+	}
+	return expression, nil
+}
+
+// sourceLocation returns the location of the program at the given path
+func sourceLocation(path string) (common.Location, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	return common.StringLocation(path), nil
+}
+
+func (s *Server) setBreakpoints(arguments *dap.SetBreakpointsArguments) (any, error) {
+	location, err := sourceLocation(arguments.Source.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// The breakpoints replace all existing breakpoints of the source
+
+	path := string(location.(common.StringLocation))
+
+	for _, line := range s.breakpoints[path] {
+		s.debugger.RemoveBreakpoint(location, line)
+	}
+
+	lines := make([]int, 0, len(arguments.Breakpoints))
+	breakpoints := make([]dap.Breakpoint, 0, len(arguments.Breakpoints))
+
+	for _, sourceBreakpoint := range arguments.Breakpoints {
+
+		// Breakpoints with an invalid condition are rejected
+
+		var condition ast.Expression
+		if sourceBreakpoint.Condition != "" {
+			condition, err = parseExpression(sourceBreakpoint.Condition)
+			if err != nil {
+				breakpoints = append(breakpoints, dap.Breakpoint{
+					Verified: false,
+					Message:  err.Error(),
+					Source:   &arguments.Source,
+					Line:     sourceBreakpoint.Line,
+				})
+				continue
+			}
+		}
+
+		if s.launch == nil || !s.launch.NoDebug {
+			s.debugger.AddConditionalBreakpoint(location, sourceBreakpoint.Line, condition)
+		}
+
+		lines = append(lines, sourceBreakpoint.Line)
+		breakpoints = append(breakpoints, dap.Breakpoint{
+			Verified: true,
+			Source:   &arguments.Source,
+			Line:     sourceBreakpoint.Line,
+		})
+	}
+
+	s.breakpoints[path] = lines
+
+	return dap.SetBreakpointsResponseBody{
+		Breakpoints: breakpoints,
+	}, nil
+}
+
+func (s *Server) configurationDone() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.launch == nil {
+		return fmt.Errorf("no program launched")
+	}
+
+	if s.started {
+		return nil
+	}
+	s.started = true
+
+	go s.forwardStops()
+	go s.run(s.launch)
+
+	return nil
+}
+
+// run executes the launched program and reports its result
+func (s *Server) run(launch *dap.LaunchRequestArguments) {
+	result, err := s.execute(launch)
+
+	exitCode := 0
+	if err != nil {
+		exitCode = 1
+		s.sendOutput(dap.OutputCategoryStderr, err.Error())
+	} else if result != nil {
+		s.sendOutput(dap.OutputCategoryConsole, fmt.Sprintf("Result: %s", result))
+	}
+
+	close(s.done)
+
+	s.sendEvent(dap.EventExited, dap.ExitedEventBody{ExitCode: exitCode})
+	s.sendEvent(dap.EventTerminated, nil)
+}
+
+// execute executes the launched program, a script or a transaction,
+// and returns the result of a script
+func (s *Server) execute(launch *dap.LaunchRequestArguments) (cadence.Value, error) {
+
+	code, err := os.ReadFile(launch.Program)
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := make([][]byte, len(launch.Arguments))
+	for i, argument := range launch.Arguments {
+		arguments[i] = []byte(argument)
+	}
+
+	signers := make([]runtime.Address, len(launch.Signers))
+	for i, signer := range launch.Signers {
+		signers[i], err = common.HexToAddress(signer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signer address %s: %w", signer, err)
+		}
+	}
+
+	runtimeInterface := newLedgerInterface(
+		filepath.Dir(launch.Program),
+		signers,
+		func(message string) {
+			s.sendOutput(dap.OutputCategoryStdout, message)
+		},
+		func(event cadence.Event) {
+			s.sendOutput(dap.OutputCategoryConsole, fmt.Sprintf("Event: %s", event))
+		},
+	)
+
+	rt := runtime.NewInterpreterRuntime()
+	rt.SetDebugger(s.debugger)
+
+	script := runtime.Script{
+		Source:    code,
+		Arguments: arguments,
+	}
+
+	context := runtime.Context{
+		Interface: runtimeInterface,
+		Location:  common.StringLocation(launch.Program),
+	}
+
+	if isTransaction(code) {
+		return nil, rt.ExecuteTransaction(script, context)
+	}
+
+	return rt.ExecuteScript(script, context)
+}
+
+// isTransaction returns true if the given program declares a transaction.
+// Programs which cannot be parsed are executed as scripts, which reports the syntax errors
+func isTransaction(code []byte) bool {
+	program, err := parser2.ParseProgram(string(code), nil)
+	if err != nil {
+		return false
+	}
+	return len(program.TransactionDeclarations()) > 0
+}
+
+// forwardStops forwards the stops of the execution to the client,
+// until the execution is done
+func (s *Server) forwardStops() {
+	for {
+		select {
+		case stop := <-s.debugger.Stops():
+			s.stopped(stop)
+
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *Server) stopped(stop interpreter.Stop) {
+	s.mutex.Lock()
+
+	s.stop = &stop
+	s.variables = nil
+
+	reason := s.stopReason
+	if stop.Breakpoint != nil {
+		reason = dap.StoppedReasonBreakpoint
+	} else if reason == "" {
+		reason = dap.StoppedReasonStep
+	}
+	s.stopReason = ""
+
+	s.mutex.Unlock()
+
+	s.sendEvent(
+		dap.EventStopped,
+		dap.StoppedEventBody{
+			Reason:            reason,
+			ThreadID:          threadID,
+			AllThreadsStopped: true,
+		},
+	)
+}
+
+// currentStop returns the current stop, or an error if the execution is not stopped
+func (s *Server) currentStop() (*interpreter.Stop, error) {
+	if s.stop == nil {
+		return nil, fmt.Errorf("execution is not stopped")
+	}
+	return s.stop, nil
+}
+
+// resume resumes the stopped execution, after performing the given request,
+// e.g. a request to stop at the next statement
+func (s *Server) resume(request func()) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, err := s.currentStop(); err != nil {
+		return err
+	}
+
+	request()
+
+	s.stop = nil
+	s.variables = nil
+
+	// The stop is forwarded to the client before the interpreter waits to be continued,
+	// so the interpreter might not be waiting yet
+
+	for !s.debugger.Continue() {
+		time.Sleep(time.Millisecond)
+	}
+
+	return nil
+}
+
+func (s *Server) evaluate(arguments *dap.EvaluateArguments) (any, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stop, err := s.currentStop()
+	if err != nil {
+		return nil, err
+	}
+
+	expression, err := parseExpression(arguments.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := s.debugger.Evaluate(stop.Interpreter, expression)
+	if err != nil {
+		return nil, err
+	}
+
+	variable := s.variable(stop.Interpreter, arguments.Expression, value)
+
+	return dap.EvaluateResponseBody{
+		Result:             variable.Value,
+		Type:               variable.Type,
+		VariablesReference: variable.VariablesReference,
+	}, nil
+}
+
+// disconnect removes all breakpoints and resumes the execution, if it is stopped,
+// so the execution can finish
+func (s *Server) disconnect() {
+	s.debugger.ClearBreakpoints()
+
+	s.mutex.Lock()
+	stopped := s.stop != nil
+	s.mutex.Unlock()
+
+	if stopped {
+		_ = s.resume(func() {})
+	}
+}
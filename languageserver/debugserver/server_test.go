@@ -0,0 +1,427 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package debugserver
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/languageserver/dap"
+)
+
+type testClient struct {
+	t      *testing.T
+	stream jsonrpc2.ObjectStream
+	seq    int
+	// messages are the messages received from the server.
+	// They are read concurrently, as the server might send events at any time
+	messages chan message
+	// events are the received events which were not consumed yet
+	events []dap.Event
+}
+
+func newTestClient(t *testing.T) *testClient {
+	clientConn, serverConn := net.Pipe()
+
+	serverStream := jsonrpc2.NewBufferedStream(serverConn, jsonrpc2.VSCodeObjectCodec{})
+	go func() {
+		_ = NewServer().Serve(serverStream)
+	}()
+
+	client := &testClient{
+		t:        t,
+		stream:   jsonrpc2.NewBufferedStream(clientConn, jsonrpc2.VSCodeObjectCodec{}),
+		messages: make(chan message, 100),
+	}
+
+	go func() {
+		for {
+			var m message
+			err := client.stream.ReadObject(&m)
+			if err != nil {
+				close(client.messages)
+				return
+			}
+			client.messages <- m
+		}
+	}()
+
+	return client
+}
+
+// message is a response or an event
+type message struct {
+	dap.ProtocolMessage
+	RequestSeq int             `json:"request_seq"`
+	Success    bool            `json:"success"`
+	Message    string          `json:"message"`
+	Event      string          `json:"event"`
+	Body       json.RawMessage `json:"body"`
+}
+
+func (c *testClient) read() message {
+	m, ok := <-c.messages
+	require.True(c.t, ok, "connection closed")
+
+	if m.Type == dap.MessageTypeEvent {
+		c.events = append(c.events, dap.Event{
+			ProtocolMessage: m.ProtocolMessage,
+			Event:           m.Event,
+			Body:            m.Body,
+		})
+	}
+
+	return m
+}
+
+// request sends a request and returns the body of the successful response
+func (c *testClient) request(command string, arguments any, body any) {
+	response := c.send(command, arguments)
+	require.True(c.t, response.Success, response.Message)
+
+	if body != nil {
+		err := json.Unmarshal(response.Body, body)
+		require.NoError(c.t, err)
+	}
+}
+
+func (c *testClient) send(command string, arguments any) message {
+	c.seq++
+
+	encodedArguments, err := json.Marshal(arguments)
+	require.NoError(c.t, err)
+
+	err = c.stream.WriteObject(dap.Request{
+		ProtocolMessage: dap.ProtocolMessage{
+			Seq:  c.seq,
+			Type: dap.MessageTypeRequest,
+		},
+		Command:   command,
+		Arguments: encodedArguments,
+	})
+	require.NoError(c.t, err)
+
+	for {
+		m := c.read()
+		if m.Type == dap.MessageTypeResponse && m.RequestSeq == c.seq {
+			return m
+		}
+	}
+}
+
+// waitForEvent waits for the event with the given name and returns its body
+func (c *testClient) waitForEvent(name string, body any) {
+	for {
+		for i, event := range c.events {
+			if event.Event != name {
+				continue
+			}
+
+			c.events = append(c.events[:i], c.events[i+1:]...)
+
+			if body != nil {
+				err := json.Unmarshal(event.Body.(json.RawMessage), body)
+				require.NoError(c.t, err)
+			}
+			return
+		}
+
+		c.read()
+	}
+}
+
+func (c *testClient) stackTrace() []dap.StackFrame {
+	var body dap.StackTraceResponseBody
+	c.request(
+		dap.CommandStackTrace,
+		dap.StackTraceArguments{ThreadID: threadID},
+		&body,
+	)
+	return body.StackFrames
+}
+
+func (c *testClient) evaluate(expression string) string {
+	var body dap.EvaluateResponseBody
+	c.request(
+		dap.CommandEvaluate,
+		dap.EvaluateArguments{Expression: expression},
+		&body,
+	)
+	return body.Result
+}
+
+func (c *testClient) waitForStop(reason string, line int) {
+	var stopped dap.StoppedEventBody
+	c.waitForEvent(dap.EventStopped, &stopped)
+	require.Equal(c.t, reason, stopped.Reason)
+
+	frames := c.stackTrace()
+	require.NotEmpty(c.t, frames)
+	require.Equal(c.t, line, frames[0].Line)
+}
+
+const testScript = `pub fun double(_ x: Int): Int {
+    return x * 2
+}
+
+pub fun main(): Int {
+    let values = [1, 2, 3]
+    var sum = 0
+    for value in values {
+        sum = sum + double(value)
+    }
+    log(sum)
+    return sum
+}
+`
+
+func writeTestProgram(t *testing.T, code string) string {
+	path := filepath.Join(t.TempDir(), "test.cdc")
+	err := os.WriteFile(path, []byte(code), 0600)
+	require.NoError(t, err)
+	return path
+}
+
+func TestServerDebugScript(t *testing.T) {
+
+	t.Parallel()
+
+	path := writeTestProgram(t, testScript)
+
+	client := newTestClient(t)
+
+	var capabilities dap.Capabilities
+	client.request(dap.CommandInitialize, dap.InitializeRequestArguments{AdapterID: "cadence"}, &capabilities)
+	assert.True(t, capabilities.SupportsConditionalBreakpoints)
+
+	client.waitForEvent(dap.EventInitialized, nil)
+
+	client.request(dap.CommandLaunch, dap.LaunchRequestArguments{Program: path}, nil)
+
+	var breakpoints dap.SetBreakpointsResponseBody
+	client.request(
+		dap.CommandSetBreakpoints,
+		dap.SetBreakpointsArguments{
+			Source: dap.Source{Path: path},
+			Breakpoints: []dap.SourceBreakpoint{
+				{
+					Line:      9,
+					Condition: "value == 2",
+				},
+			},
+		},
+		&breakpoints,
+	)
+	require.Len(t, breakpoints.Breakpoints, 1)
+	assert.True(t, breakpoints.Breakpoints[0].Verified)
+
+	client.request(dap.CommandConfigurationDone, nil, nil)
+
+	// Stop at the conditional breakpoint
+
+	client.waitForStop(dap.StoppedReasonBreakpoint, 9)
+
+	frames := client.stackTrace()
+	assert.Equal(t, "main", frames[0].Name)
+	assert.Equal(t, path, frames[0].Source.Path)
+
+	// Inspect the variables
+
+	var scopes dap.ScopesResponseBody
+	client.request(dap.CommandScopes, dap.ScopesArguments{FrameID: frames[0].ID}, &scopes)
+	require.Len(t, scopes.Scopes, 1)
+
+	var locals dap.VariablesResponseBody
+	client.request(
+		dap.CommandVariables,
+		dap.VariablesArguments{VariablesReference: scopes.Scopes[0].VariablesReference},
+		&locals,
+	)
+
+	variables := map[string]dap.Variable{}
+	for _, variable := range locals.Variables {
+		variables[variable.Name] = variable
+	}
+
+	assert.Equal(t, "2", variables["value"].Value)
+	assert.Equal(t, "Int", variables["value"].Type)
+	assert.Equal(t, "2", variables["sum"].Value)
+
+	values := variables["values"]
+	assert.Equal(t, "[1, 2, 3]", values.Value)
+	require.Greater(t, values.VariablesReference, 0)
+
+	var elements dap.VariablesResponseBody
+	client.request(
+		dap.CommandVariables,
+		dap.VariablesArguments{VariablesReference: values.VariablesReference},
+		&elements,
+	)
+	require.Equal(t,
+		[]dap.Variable{
+			{Name: "[0]", Value: "1", Type: "Int"},
+			{Name: "[1]", Value: "2", Type: "Int"},
+			{Name: "[2]", Value: "3", Type: "Int"},
+		},
+		elements.Variables,
+	)
+
+	assert.Equal(t, "4", client.evaluate("sum + value"))
+
+	// Step into the invoked function
+
+	client.request(dap.CommandStepIn, dap.ThreadArguments{ThreadID: threadID}, nil)
+	client.waitForStop(dap.StoppedReasonStep, 2)
+
+	frames = client.stackTrace()
+	require.GreaterOrEqual(t, len(frames), 2)
+	assert.Equal(t, "double", frames[0].Name)
+	assert.Equal(t, "main", frames[1].Name)
+	assert.Equal(t, 9, frames[1].Line)
+
+	assert.Equal(t, "2", client.evaluate("x"))
+
+	// Step out of the invoked function, into the next iteration
+
+	client.request(dap.CommandStepOut, dap.ThreadArguments{ThreadID: threadID}, nil)
+	client.waitForStop(dap.StoppedReasonStep, 9)
+
+	assert.Equal(t, "3", client.evaluate("value"))
+
+	// Step over the remaining loop
+
+	client.request(dap.CommandNext, dap.ThreadArguments{ThreadID: threadID}, nil)
+	client.waitForStop(dap.StoppedReasonStep, 11)
+
+	assert.Equal(t, "12", client.evaluate("sum"))
+
+	client.request(dap.CommandContinue, dap.ThreadArguments{ThreadID: threadID}, nil)
+
+	var output dap.OutputEventBody
+	client.waitForEvent(dap.EventOutput, &output)
+	assert.Equal(t, dap.OutputEventBody{Category: dap.OutputCategoryStdout, Output: "12\n"}, output)
+
+	client.waitForEvent(dap.EventOutput, &output)
+	assert.Equal(t, dap.OutputEventBody{Category: dap.OutputCategoryConsole, Output: "Result: 12\n"}, output)
+
+	var exited dap.ExitedEventBody
+	client.waitForEvent(dap.EventExited, &exited)
+	assert.Equal(t, 0, exited.ExitCode)
+
+	client.waitForEvent(dap.EventTerminated, nil)
+
+	client.request(dap.CommandDisconnect, dap.DisconnectArguments{}, nil)
+}
+
+func TestServerDebugTransaction(t *testing.T) {
+
+	t.Parallel()
+
+	path := writeTestProgram(t, `
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save(42, to: /storage/answer)
+              let answer = signer.load<Int>(from: /storage/answer)!
+              log(answer)
+          }
+      }
+    `)
+
+	client := newTestClient(t)
+
+	client.request(dap.CommandInitialize, dap.InitializeRequestArguments{AdapterID: "cadence"}, nil)
+	client.request(
+		dap.CommandLaunch,
+		dap.LaunchRequestArguments{
+			Program:     path,
+			Signers:     []string{"0x1"},
+			StopOnEntry: true,
+		},
+		nil,
+	)
+	client.request(dap.CommandConfigurationDone, nil, nil)
+
+	client.waitForStop(dap.StoppedReasonEntry, 4)
+
+	frames := client.stackTrace()
+	assert.Equal(t, "transaction.prepare", frames[0].Name)
+
+	assert.Equal(t, "0x0000000000000001", client.evaluate("signer.address"))
+
+	client.request(dap.CommandNext, dap.ThreadArguments{ThreadID: threadID}, nil)
+	client.waitForStop(dap.StoppedReasonStep, 5)
+
+	client.request(dap.CommandNext, dap.ThreadArguments{ThreadID: threadID}, nil)
+	client.waitForStop(dap.StoppedReasonStep, 6)
+
+	assert.Equal(t, "42", client.evaluate("answer"))
+
+	client.request(dap.CommandContinue, dap.ThreadArguments{ThreadID: threadID}, nil)
+
+	var output dap.OutputEventBody
+	client.waitForEvent(dap.EventOutput, &output)
+	assert.Equal(t, "42\n", output.Output)
+
+	var exited dap.ExitedEventBody
+	client.waitForEvent(dap.EventExited, &exited)
+	assert.Equal(t, 0, exited.ExitCode)
+
+	client.request(dap.CommandDisconnect, dap.DisconnectArguments{}, nil)
+}
+
+func TestServerErrors(t *testing.T) {
+
+	t.Parallel()
+
+	client := newTestClient(t)
+
+	client.request(dap.CommandInitialize, dap.InitializeRequestArguments{AdapterID: "cadence"}, nil)
+
+	response := client.send(dap.CommandLaunch, dap.LaunchRequestArguments{})
+	assert.False(t, response.Success)
+
+	response = client.send(dap.CommandStackTrace, dap.StackTraceArguments{ThreadID: threadID})
+	assert.False(t, response.Success)
+
+	response = client.send("unknown", nil)
+	assert.False(t, response.Success)
+
+	path := writeTestProgram(t, `pub fun main() { panic("failure") }`)
+
+	client.request(dap.CommandLaunch, dap.LaunchRequestArguments{Program: path}, nil)
+	client.request(dap.CommandConfigurationDone, nil, nil)
+
+	var output dap.OutputEventBody
+	client.waitForEvent(dap.EventOutput, &output)
+	assert.Equal(t, dap.OutputCategoryStderr, output.Category)
+	assert.Contains(t, output.Output, "failure")
+
+	var exited dap.ExitedEventBody
+	client.waitForEvent(dap.EventExited, &exited)
+	assert.Equal(t, 1, exited.ExitCode)
+
+	client.request(dap.CommandDisconnect, dap.DisconnectArguments{}, nil)
+}
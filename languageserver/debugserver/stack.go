@@ -0,0 +1,188 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package debugserver
+
+import (
+	"path/filepath"
+
+	"github.com/onflow/cadence/languageserver/dap"
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// frame is a stack frame of the stopped execution
+type frame struct {
+	location common.Location
+	program  *ast.Program
+	position ast.Position
+}
+
+// frames returns the stack frames of the given stop, innermost first.
+//
+// The innermost frame is the stopped statement,
+// the other frames are the call sites of the invocations in the call stack
+func frames(stop *interpreter.Stop) []frame {
+	inter := stop.Interpreter
+
+	result := []frame{
+		{
+			location: inter.Location,
+			program:  programOf(inter),
+			position: stop.Statement.StartPosition(),
+		},
+	}
+
+	invocations := inter.CallStack.Invocations
+	for i := len(invocations) - 1; i >= 0; i-- {
+		invocation := invocations[i]
+		if invocation.GetLocationRange == nil {
+			continue
+		}
+
+		locationRange := invocation.GetLocationRange()
+		if locationRange.Location == nil {
+			continue
+		}
+
+		var program *ast.Program
+		if invocation.Interpreter != nil &&
+			common.LocationsMatch(invocation.Interpreter.Location, locationRange.Location) {
+
+			program = programOf(invocation.Interpreter)
+		}
+
+		result = append(result, frame{
+			location: locationRange.Location,
+			program:  program,
+			position: locationRange.StartPos,
+		})
+	}
+
+	return result
+}
+
+func programOf(inter *interpreter.Interpreter) *ast.Program {
+	if inter.Program == nil {
+		return nil
+	}
+	return inter.Program.Program
+}
+
+func (s *Server) stackTrace(arguments *dap.StackTraceArguments) (any, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stop, err := s.currentStop()
+	if err != nil {
+		return nil, err
+	}
+
+	allFrames := frames(stop)
+
+	start := arguments.StartFrame
+	if start > len(allFrames) {
+		start = len(allFrames)
+	}
+	end := len(allFrames)
+	if arguments.Levels > 0 && start+arguments.Levels < end {
+		end = start + arguments.Levels
+	}
+
+	stackFrames := make([]dap.StackFrame, 0, end-start)
+	for id := start; id < end; id++ {
+		frame := allFrames[id]
+		stackFrames = append(stackFrames, dap.StackFrame{
+			ID:     id,
+			Name:   functionName(frame.program, frame.position.Line),
+			Source: source(frame.location),
+			Line:   frame.position.Line,
+			// Columns are zero-based in the AST, but one-based in the protocol
+			Column: frame.position.Column + 1,
+		})
+	}
+
+	return dap.StackTraceResponseBody{
+		StackFrames: stackFrames,
+		TotalFrames: len(allFrames),
+	}, nil
+}
+
+// source returns the source for the given location.
+// Only string locations, the locations of launched programs and their imports, have a path
+func source(location common.Location) *dap.Source {
+	if stringLocation, ok := location.(common.StringLocation); ok {
+		path := string(stringLocation)
+		return &dap.Source{
+			Name: filepath.Base(path),
+			Path: path,
+		}
+	}
+
+	return &dap.Source{
+		Name: location.String(),
+	}
+}
+
+// functionName returns the qualified name of the innermost function
+// which contains the given line of the given program
+func functionName(program *ast.Program, line int) string {
+	const topLevelName = "<top level>"
+
+	if program == nil {
+		return topLevelName
+	}
+
+	var prefix, name string
+
+	ast.Inspect(program, func(element ast.Element) bool {
+		hasPosition, ok := element.(ast.HasPosition)
+		if ok {
+			if line < hasPosition.StartPosition().Line ||
+				line > hasPosition.EndPosition(nil).Line {
+
+				return false
+			}
+		}
+
+		switch element := element.(type) {
+		case *ast.CompositeDeclaration:
+			prefix += element.Identifier.Identifier + "."
+			name = ""
+
+		case *ast.TransactionDeclaration:
+			prefix += "transaction."
+			name = ""
+
+		case *ast.FunctionDeclaration:
+			name = element.Identifier.Identifier
+
+		case *ast.SpecialFunctionDeclaration:
+			name = element.Kind.Keywords()
+		}
+
+		return true
+	})
+
+	if name == "" {
+		return topLevelName
+	}
+
+	return prefix + name
+}
@@ -0,0 +1,174 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package debugserver
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/onflow/cadence/languageserver/dap"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// variablesReference refers to the children of a value,
+// or to the variables of an activation
+type variablesReference struct {
+	interpreter *interpreter.Interpreter
+	value       interpreter.Value
+	activation  *interpreter.VariableActivation
+}
+
+func (s *Server) addVariablesReference(reference variablesReference) int {
+	s.variables = append(s.variables, reference)
+	return len(s.variables)
+}
+
+func (s *Server) scopes(arguments *dap.ScopesArguments) (any, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stop, err := s.currentStop()
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the variables of the innermost frame are available
+
+	scopes := []dap.Scope{}
+
+	if arguments.FrameID == 0 {
+		activation := s.debugger.CurrentActivation(stop.Interpreter)
+		if activation != nil {
+			scopes = append(scopes, dap.Scope{
+				Name: "Locals",
+				VariablesReference: s.addVariablesReference(variablesReference{
+					interpreter: stop.Interpreter,
+					activation:  activation,
+				}),
+			})
+		}
+	}
+
+	return dap.ScopesResponseBody{
+		Scopes: scopes,
+	}, nil
+}
+
+func (s *Server) variablesOf(arguments *dap.VariablesArguments) (any, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, err := s.currentStop(); err != nil {
+		return nil, err
+	}
+
+	index := arguments.VariablesReference - 1
+	if index < 0 || index >= len(s.variables) {
+		return dap.VariablesResponseBody{
+			Variables: []dap.Variable{},
+		}, nil
+	}
+
+	return dap.VariablesResponseBody{
+		Variables: s.children(s.variables[index]),
+	}, nil
+}
+
+// children returns the variables of an activation, or the children of a value:
+// the fields of a composite, the elements of an array,
+// the entries of a dictionary, or the value of an optional
+func (s *Server) children(reference variablesReference) []dap.Variable {
+	inter := reference.interpreter
+
+	variables := []dap.Variable{}
+
+	if reference.activation != nil {
+		functionValues := reference.activation.FunctionValues()
+
+		names := make([]string, 0, len(functionValues))
+		for name := range functionValues { //nolint:maprangecheck
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			value := functionValues[name].GetValue()
+			variables = append(variables, s.variable(inter, name, value))
+		}
+
+		return variables
+	}
+
+	switch value := reference.value.(type) {
+	case *interpreter.CompositeValue:
+		value.ForEachField(inter, func(name string, fieldValue interpreter.Value) {
+			variables = append(variables, s.variable(inter, name, fieldValue))
+		})
+
+		sort.Slice(variables, func(i, j int) bool {
+			return variables[i].Name < variables[j].Name
+		})
+
+	case *interpreter.ArrayValue:
+		count := value.Count()
+		for i := 0; i < count; i++ {
+			element := value.Get(inter, interpreter.ReturnEmptyLocationRange, i)
+			variables = append(variables, s.variable(inter, fmt.Sprintf("[%d]", i), element))
+		}
+
+	case *interpreter.DictionaryValue:
+		value.Iterate(inter, func(key, entryValue interpreter.Value) (resume bool) {
+			variables = append(variables, s.variable(inter, fmt.Sprintf("[%s]", key), entryValue))
+			return true
+		})
+
+	case *interpreter.SomeValue:
+		innerValue := value.InnerValue(inter, interpreter.ReturnEmptyLocationRange)
+		variables = append(variables, s.variable(inter, "value", innerValue))
+	}
+
+	return variables
+}
+
+// variable returns the variable for the given value.
+// If the value has children, a variables reference is added for them
+func (s *Server) variable(inter *interpreter.Interpreter, name string, value interpreter.Value) dap.Variable {
+	variable := dap.Variable{
+		Name:  name,
+		Value: value.String(),
+	}
+
+	if staticType := value.StaticType(inter); staticType != nil {
+		variable.Type = staticType.String()
+	}
+
+	switch value.(type) {
+	case *interpreter.CompositeValue,
+		*interpreter.ArrayValue,
+		*interpreter.DictionaryValue,
+		*interpreter.SomeValue:
+
+		variable.VariablesReference = s.addVariablesReference(variablesReference{
+			interpreter: inter,
+			value:       value,
+		})
+	}
+
+	return variable
+}
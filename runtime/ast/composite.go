@@ -34,10 +34,11 @@ import (
 type CompositeDeclaration struct {
 	Access        Access
 	CompositeKind common.CompositeKind
-	Identifier    Identifier
-	Conformances  []*NominalType
-	Members       *Members
-	DocString     string
+	Identifier        Identifier
+	TypeParameterList *TypeParameterList `json:",omitempty"`
+	Conformances      []*NominalType
+	Members           *Members
+	DocString         string
 	Range
 }
 
@@ -50,6 +51,7 @@ func NewCompositeDeclaration(
 	access Access,
 	compositeKind common.CompositeKind,
 	identifier Identifier,
+	typeParameterList *TypeParameterList,
 	conformances []*NominalType,
 	members *Members,
 	docString string,
@@ -58,13 +60,14 @@ func NewCompositeDeclaration(
 	common.UseMemory(memoryGauge, common.CompositeDeclarationMemoryUsage)
 
 	return &CompositeDeclaration{
-		Access:        access,
-		CompositeKind: compositeKind,
-		Identifier:    identifier,
-		Conformances:  conformances,
-		Members:       members,
-		DocString:     docString,
-		Range:         declarationRange,
+		Access:            access,
+		CompositeKind:     compositeKind,
+		Identifier:        identifier,
+		TypeParameterList: typeParameterList,
+		Conformances:      conformances,
+		Members:           members,
+		DocString:         docString,
+		Range:             declarationRange,
 	}
 }
 
@@ -129,6 +132,7 @@ func (d *CompositeDeclaration) Doc() prettier.Doc {
 		d.CompositeKind,
 		false,
 		d.Identifier.Identifier,
+		d.TypeParameterList,
 		d.Conformances,
 		d.Members,
 	)
@@ -179,6 +183,7 @@ func CompositeDocument(
 	kind common.CompositeKind,
 	isInterface bool,
 	identifier string,
+	typeParameterList *TypeParameterList,
 	conformances []*NominalType,
 	members *Members,
 ) prettier.Doc {
@@ -211,6 +216,13 @@ func CompositeDocument(
 		prettier.Text(identifier),
 	)
 
+	if !typeParameterList.IsEmpty() {
+		doc = append(
+			doc,
+			typeParameterList.Doc(),
+		)
+	}
+
 	if len(conformances) > 0 {
 
 		conformancesDoc := prettier.Concat{
@@ -40,6 +40,7 @@ const (
 	ElementTypePragmaDeclaration
 	ElementTypeImportDeclaration
 	ElementTypeTransactionDeclaration
+	ElementTypeTypeAliasDeclaration
 
 	// Statements
 
@@ -73,6 +74,7 @@ const (
 	ElementTypeBinaryExpression
 	ElementTypeFunctionExpression
 	ElementTypeStringExpression
+	ElementTypeStringTemplateExpression
 	ElementTypeCastingExpression
 	ElementTypeCreateExpression
 	ElementTypeDestroyExpression
@@ -245,6 +245,106 @@ func (*StringExpression) precedence() precedence {
 	return precedenceLiteral
 }
 
+// StringTemplateExpression
+
+// StringTemplateExpression is a string literal with embedded expressions,
+// e.g. `"Hello, \(name)!"`.
+//
+// The literal parts of the string are stored in Values,
+// the embedded expressions in Expressions.
+// There is always one more value than there are expressions:
+// Values[i] precedes Expressions[i], and the last value follows the last expression.
+//
+type StringTemplateExpression struct {
+	Values      []string
+	Expressions []Expression
+	Range
+}
+
+var _ Element = &StringTemplateExpression{}
+var _ Expression = &StringTemplateExpression{}
+
+func NewStringTemplateExpression(
+	gauge common.MemoryGauge,
+	values []string,
+	expressions []Expression,
+	exprRange Range,
+) *StringTemplateExpression {
+
+	common.UseMemory(gauge, common.NewStringTemplateExpressionMemoryUsage(len(expressions)))
+
+	return &StringTemplateExpression{
+		Values:      values,
+		Expressions: expressions,
+		Range:       exprRange,
+	}
+}
+
+func (*StringTemplateExpression) ElementType() ElementType {
+	return ElementTypeStringTemplateExpression
+}
+
+func (*StringTemplateExpression) isExpression() {}
+
+func (*StringTemplateExpression) isIfStatementTest() {}
+
+func (e *StringTemplateExpression) Accept(visitor Visitor) Repr {
+	return e.AcceptExp(visitor)
+}
+
+func (e *StringTemplateExpression) Walk(walkChild func(Element)) {
+	walkExpressions(walkChild, e.Expressions)
+}
+
+func (e *StringTemplateExpression) AcceptExp(visitor ExpressionVisitor) Repr {
+	return visitor.VisitStringTemplateExpression(e)
+}
+
+func (e *StringTemplateExpression) String() string {
+	return Prettier(e)
+}
+
+var stringTemplateExpressionStartDoc prettier.Doc = prettier.Text(`\(`)
+var stringTemplateExpressionEndDoc prettier.Doc = prettier.Text(")")
+
+func (e *StringTemplateExpression) Doc() prettier.Doc {
+	doc := prettier.Concat{
+		prettier.Text(`"`),
+	}
+
+	for i, value := range e.Values {
+		var b strings.Builder
+		writeEscapedString(&b, value)
+		doc = append(doc, prettier.Text(b.String()))
+
+		if i < len(e.Expressions) {
+			doc = append(
+				doc,
+				stringTemplateExpressionStartDoc,
+				e.Expressions[i].Doc(),
+				stringTemplateExpressionEndDoc,
+			)
+		}
+	}
+
+	return append(doc, prettier.Text(`"`))
+}
+
+func (e *StringTemplateExpression) MarshalJSON() ([]byte, error) {
+	type Alias StringTemplateExpression
+	return json.Marshal(&struct {
+		Type string
+		*Alias
+	}{
+		Type:  "StringTemplateExpression",
+		Alias: (*Alias)(e),
+	})
+}
+
+func (*StringTemplateExpression) precedence() precedence {
+	return precedenceLiteral
+}
+
 // IntegerExpression
 
 type IntegerExpression struct {
@@ -1447,6 +1547,7 @@ func (e *BinaryExpression) IsLeftAssociative() bool {
 // FunctionExpression
 
 type FunctionExpression struct {
+	Purity               FunctionPurity `json:",omitempty"`
 	ParameterList        *ParameterList
 	ReturnTypeAnnotation *TypeAnnotation
 	FunctionBlock        *FunctionBlock
@@ -1458,6 +1559,7 @@ var _ Expression = &FunctionExpression{}
 
 func NewFunctionExpression(
 	gauge common.MemoryGauge,
+	purity FunctionPurity,
 	parameters *ParameterList,
 	returnType *TypeAnnotation,
 	functionBlock *FunctionBlock,
@@ -1466,6 +1568,7 @@ func NewFunctionExpression(
 	common.UseMemory(gauge, common.FunctionExpressionMemoryUsage)
 
 	return &FunctionExpression{
+		Purity:               purity,
 		ParameterList:        parameters,
 		ReturnTypeAnnotation: returnType,
 		FunctionBlock:        functionBlock,
@@ -1505,8 +1608,10 @@ var functionExpressionEmptyBlockDoc prettier.Doc = prettier.Text(" {}")
 
 func FunctionDocument(
 	access Access,
+	purity FunctionPurity,
 	includeKeyword bool,
 	identifier string,
+	typeParameterList *TypeParameterList,
 	parameterList *ParameterList,
 	returnTypeAnnotation *TypeAnnotation,
 	block *FunctionBlock,
@@ -1540,6 +1645,14 @@ func FunctionDocument(
 		)
 	}
 
+	if purity != FunctionPurityUnspecified {
+		doc = append(
+			doc,
+			prettier.Text(purity.Keyword()),
+			prettier.Space,
+		)
+	}
+
 	if includeKeyword {
 		doc = append(
 			doc,
@@ -1554,6 +1667,13 @@ func FunctionDocument(
 		)
 	}
 
+	if !typeParameterList.IsEmpty() {
+		doc = append(
+			doc,
+			typeParameterList.Doc(),
+		)
+	}
+
 	if signatureDoc != nil {
 		doc = append(
 			doc,
@@ -1579,8 +1699,10 @@ func FunctionDocument(
 func (e *FunctionExpression) Doc() prettier.Doc {
 	return FunctionDocument(
 		AccessNotSpecified,
+		e.Purity,
 		true,
 		"",
+		nil,
 		e.ParameterList,
 		e.ReturnTypeAnnotation,
 		e.FunctionBlock,
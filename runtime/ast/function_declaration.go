@@ -28,7 +28,9 @@ import (
 
 type FunctionDeclaration struct {
 	Access               Access
+	Purity               FunctionPurity `json:",omitempty"`
 	Identifier           Identifier
+	TypeParameterList    *TypeParameterList `json:",omitempty"`
 	ParameterList        *ParameterList
 	ReturnTypeAnnotation *TypeAnnotation
 	FunctionBlock        *FunctionBlock
@@ -43,7 +45,9 @@ var _ Statement = &FunctionDeclaration{}
 func NewFunctionDeclaration(
 	gauge common.MemoryGauge,
 	access Access,
+	purity FunctionPurity,
 	identifier Identifier,
+	typeParameterList *TypeParameterList,
 	parameterList *ParameterList,
 	returnTypeAnnotation *TypeAnnotation,
 	functionBlock *FunctionBlock,
@@ -54,7 +58,9 @@ func NewFunctionDeclaration(
 
 	return &FunctionDeclaration{
 		Access:               access,
+		Purity:               purity,
 		Identifier:           identifier,
+		TypeParameterList:    typeParameterList,
 		ParameterList:        parameterList,
 		ReturnTypeAnnotation: returnTypeAnnotation,
 		FunctionBlock:        functionBlock,
@@ -112,6 +118,7 @@ func (d *FunctionDeclaration) DeclarationAccess() Access {
 func (d *FunctionDeclaration) ToExpression(memoryGauge common.MemoryGauge) *FunctionExpression {
 	return NewFunctionExpression(
 		memoryGauge,
+		d.Purity,
 		d.ParameterList,
 		d.ReturnTypeAnnotation,
 		d.FunctionBlock,
@@ -130,8 +137,10 @@ func (d *FunctionDeclaration) DeclarationDocString() string {
 func (d *FunctionDeclaration) Doc() prettier.Doc {
 	return FunctionDocument(
 		d.Access,
+		d.Purity,
 		true,
 		d.Identifier.Identifier,
+		d.TypeParameterList,
 		d.ParameterList,
 		d.ReturnTypeAnnotation,
 		d.FunctionBlock,
@@ -226,8 +235,10 @@ func (d *SpecialFunctionDeclaration) DeclarationDocString() string {
 func (d *SpecialFunctionDeclaration) Doc() prettier.Doc {
 	return FunctionDocument(
 		d.FunctionDeclaration.Access,
+		d.FunctionDeclaration.Purity,
 		false,
 		d.Kind.Keywords(),
+		nil,
 		d.FunctionDeclaration.ParameterList,
 		d.FunctionDeclaration.ReturnTypeAnnotation,
 		d.FunctionDeclaration.FunctionBlock,
@@ -119,6 +119,7 @@ func (d *InterfaceDeclaration) Doc() prettier.Doc {
 		true,
 		d.Identifier.Identifier,
 		nil,
+		nil,
 		d.Members,
 	)
 }
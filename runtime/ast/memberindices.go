@@ -56,6 +56,10 @@ type memberIndices struct {
 	_composites []*CompositeDeclaration
 	// Use `EnumCases()` instead
 	_enumCases []*EnumCaseDeclaration
+	// Use `Pragmas()` instead
+	_pragmas []*PragmaDeclaration
+	// Use `TypeAliases()` instead
+	_typeAliases []*TypeAliasDeclaration
 }
 
 func (i *memberIndices) FieldsByIdentifier(declarations []Declaration) map[string]*FieldDeclaration {
@@ -118,6 +122,16 @@ func (i *memberIndices) EnumCases(declarations []Declaration) []*EnumCaseDeclara
 	return i._enumCases
 }
 
+func (i *memberIndices) Pragmas(declarations []Declaration) []*PragmaDeclaration {
+	i.once.Do(i.initializer(declarations))
+	return i._pragmas
+}
+
+func (i *memberIndices) TypeAliases(declarations []Declaration) []*TypeAliasDeclaration {
+	i.once.Do(i.initializer(declarations))
+	return i._typeAliases
+}
+
 func (i *memberIndices) initializer(declarations []Declaration) func() {
 	return func() {
 		i.init(declarations)
@@ -145,6 +159,10 @@ func (i *memberIndices) init(declarations []Declaration) {
 
 	i._enumCases = make([]*EnumCaseDeclaration, 0)
 
+	i._pragmas = make([]*PragmaDeclaration, 0)
+
+	i._typeAliases = make([]*TypeAliasDeclaration, 0)
+
 	for _, declaration := range declarations {
 		switch declaration := declaration.(type) {
 		case *FieldDeclaration:
@@ -175,6 +193,12 @@ func (i *memberIndices) init(declarations []Declaration) {
 
 		case *EnumCaseDeclaration:
 			i._enumCases = append(i._enumCases, declaration)
+
+		case *PragmaDeclaration:
+			i._pragmas = append(i._pragmas, declaration)
+
+		case *TypeAliasDeclaration:
+			i._typeAliases = append(i._typeAliases, declaration)
 		}
 	}
 }
@@ -102,6 +102,10 @@ func (p *Program) VariableDeclarations() []*VariableDeclaration {
 	return p.indices.variableDeclarations(p.declarations)
 }
 
+func (p *Program) TypeAliasDeclarations() []*TypeAliasDeclaration {
+	return p.indices.typeAliasDeclarations(p.declarations)
+}
+
 // SoleContractDeclaration returns the sole contract declaration, if any,
 // and if there are no other actionable declarations.
 //
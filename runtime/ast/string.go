@@ -27,6 +27,15 @@ import (
 func QuoteString(s string) string {
 	var b strings.Builder
 	b.WriteByte('"')
+	writeEscapedString(&b, s)
+	b.WriteByte('"')
+	return b.String()
+}
+
+// writeEscapedString writes the given string to the given builder,
+// escaping all characters which cannot be represented literally in a string literal
+//
+func writeEscapedString(b *strings.Builder, s string) {
 	for _, r := range s {
 		switch r {
 		case 0:
@@ -56,6 +65,4 @@ func QuoteString(s string) string {
 			}
 		}
 	}
-	b.WriteByte('"')
-	return b.String()
 }
@@ -432,6 +432,7 @@ func (t *DictionaryType) CheckEqual(other Type, checker TypeEqualityChecker) err
 // FunctionType
 
 type FunctionType struct {
+	Purity                   FunctionPurity    `json:",omitempty"`
 	ParameterTypeAnnotations []*TypeAnnotation `json:",omitempty"`
 	ReturnTypeAnnotation     *TypeAnnotation
 	Range
@@ -441,12 +442,14 @@ var _ Type = &FunctionType{}
 
 func NewFunctionType(
 	memoryGauge common.MemoryGauge,
+	purity FunctionPurity,
 	parameterTypes []*TypeAnnotation,
 	returnType *TypeAnnotation,
 	astRange Range,
 ) *FunctionType {
 	common.UseMemory(memoryGauge, common.FunctionTypeMemoryUsage)
 	return &FunctionType{
+		Purity:                   purity,
 		ParameterTypeAnnotations: parameterTypes,
 		ReturnTypeAnnotation:     returnType,
 		Range:                    astRange,
@@ -482,8 +485,20 @@ func (t *FunctionType) Doc() prettier.Doc {
 		)
 	}
 
-	return prettier.Concat{
+	doc := prettier.Concat{
 		functionTypeStartDoc,
+	}
+
+	if t.Purity != FunctionPurityUnspecified {
+		doc = append(
+			doc,
+			prettier.Text(t.Purity.Keyword()),
+			prettier.Space,
+		)
+	}
+
+	return append(
+		doc,
 		prettier.Group{
 			Doc: prettier.Concat{
 				functionTypeStartDoc,
@@ -497,7 +512,7 @@ func (t *FunctionType) Doc() prettier.Doc {
 		typeSeparatorSpaceDoc,
 		t.ReturnTypeAnnotation.Doc(),
 		functionTypeEndDoc,
-	}
+	)
 }
 
 func (t *FunctionType) MarshalJSON() ([]byte, error) {
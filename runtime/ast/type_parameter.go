@@ -0,0 +1,113 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package ast
+
+import (
+	"github.com/turbolent/prettier"
+
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// TypeParameter is a type parameter of a generic function or composite declaration,
+// e.g. `T` or `T: AnyStruct` in `fun identity<T: AnyStruct>(_ value: T): T`.
+// The type bound is optional
+//
+type TypeParameter struct {
+	Identifier Identifier
+	TypeBound  Type
+}
+
+func NewTypeParameter(
+	gauge common.MemoryGauge,
+	identifier Identifier,
+	typeBound Type,
+) *TypeParameter {
+	common.UseMemory(gauge, common.TypeParameterMemoryUsage)
+	return &TypeParameter{
+		Identifier: identifier,
+		TypeBound:  typeBound,
+	}
+}
+
+func (p *TypeParameter) Doc() prettier.Doc {
+	if p.TypeBound == nil {
+		return prettier.Text(p.Identifier.Identifier)
+	}
+
+	return prettier.Concat{
+		prettier.Text(p.Identifier.Identifier),
+		typeSeparatorSpaceDoc,
+		p.TypeBound.Doc(),
+	}
+}
+
+type TypeParameterList struct {
+	TypeParameters []*TypeParameter
+	Range
+}
+
+func NewTypeParameterList(
+	gauge common.MemoryGauge,
+	typeParameters []*TypeParameter,
+	astRange Range,
+) *TypeParameterList {
+	common.UseMemory(gauge, common.TypeParameterListMemoryUsage)
+	return &TypeParameterList{
+		TypeParameters: typeParameters,
+		Range:          astRange,
+	}
+}
+
+func (l *TypeParameterList) IsEmpty() bool {
+	return l == nil || len(l.TypeParameters) == 0
+}
+
+func (l *TypeParameterList) Doc() prettier.Doc {
+
+	if l.IsEmpty() {
+		return nil
+	}
+
+	typeParameterDocs := make([]prettier.Doc, 0, len(l.TypeParameters))
+
+	for _, typeParameter := range l.TypeParameters {
+		typeParameterDocs = append(typeParameterDocs, typeParameter.Doc())
+	}
+
+	return prettier.Group{
+		Doc: prettier.Concat{
+			instantiationTypeStartDoc,
+			prettier.Indent{
+				Doc: prettier.Concat{
+					prettier.SoftLine{},
+					prettier.Join(
+						parameterSeparatorDoc,
+						typeParameterDocs...,
+					),
+				},
+			},
+			prettier.SoftLine{},
+			instantiationTypeEndDoc,
+		},
+	}
+}
+
+func (l *TypeParameterList) String() string {
+	return Prettier(l)
+}
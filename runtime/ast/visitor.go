@@ -69,6 +69,7 @@ type DeclarationVisitor interface {
 	VisitEnumCaseDeclaration(*EnumCaseDeclaration) Repr
 	VisitPragmaDeclaration(*PragmaDeclaration) Repr
 	VisitImportDeclaration(*ImportDeclaration) Repr
+	VisitTypeAliasDeclaration(*TypeAliasDeclaration) Repr
 }
 
 type StatementVisitor interface {
@@ -102,6 +103,7 @@ type ExpressionVisitor interface {
 	VisitBinaryExpression(*BinaryExpression) Repr
 	VisitFunctionExpression(*FunctionExpression) Repr
 	VisitStringExpression(*StringExpression) Repr
+	VisitStringTemplateExpression(*StringTemplateExpression) Repr
 	VisitCastingExpression(*CastingExpression) Repr
 	VisitCreateExpression(*CreateExpression) Repr
 	VisitDestroyExpression(*DestroyExpression) Repr
@@ -0,0 +1,142 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/cmd"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+var toJSONFlag = flag.Bool("to-json", false, "convert an argument list with literals to JSON-Cadence")
+
+// arguments converts the arguments for the entry point (script or transaction) of the given program
+// between JSON-Cadence and Cadence literals.
+//
+// By default, the input is a JSON array of JSON-Cadence encoded arguments,
+// and the output is an argument list with literals, e.g. `(1, "hello")`.
+// With -to-json, the conversion is reversed.
+//
+// The input is read from the given file, or from standard input.
+func main() {
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-to-json] <program> [<arguments>]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	parameterTypes := entryPointParameterTypes(args[0])
+
+	input, err := readInput(args[1:])
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+
+	var output string
+	if *toJSONFlag {
+		output, err = literalToJSON(input, parameterTypes)
+	} else {
+		output, err = jsonToLiteral(input, parameterTypes)
+	}
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+
+	fmt.Println(output)
+}
+
+func entryPointParameterTypes(path string) []sema.Type {
+	location := common.NewStringLocation(nil, path)
+
+	codes := map[common.LocationID]string{}
+
+	program, must := cmd.PrepareProgramFromFile(location, codes)
+
+	checker, must := cmd.PrepareChecker(program, location, codes, nil, must)
+
+	must(checker.Check())
+
+	parameters := checker.EntryPointParameters()
+
+	parameterTypes := make([]sema.Type, len(parameters))
+	for i, parameter := range parameters {
+		parameterTypes[i] = parameter.TypeAnnotation.Type
+	}
+
+	return parameterTypes
+}
+
+func readInput(args []string) ([]byte, error) {
+	if len(args) > 0 {
+		return os.ReadFile(args[0])
+	}
+	return io.ReadAll(os.Stdin)
+}
+
+func jsonToLiteral(input []byte, parameterTypes []sema.Type) (string, error) {
+	var rawArguments []json.RawMessage
+	err := json.Unmarshal(input, &rawArguments)
+	if err != nil {
+		return "", fmt.Errorf("invalid arguments, expected JSON array: %w", err)
+	}
+
+	arguments := make([][]byte, len(rawArguments))
+	for i, argument := range rawArguments {
+		arguments[i] = argument
+	}
+
+	return runtime.JSONToLiteralArgumentList(arguments, parameterTypes, nil)
+}
+
+func literalToJSON(input []byte, parameterTypes []sema.Type) (string, error) {
+	arguments, err := runtime.LiteralArgumentListToJSON(
+		strings.TrimSpace(string(input)),
+		parameterTypes,
+		nil,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	rawArguments := make([]json.RawMessage, len(arguments))
+	for i, argument := range arguments {
+		rawArguments[i] = argument
+	}
+
+	output, err := json.MarshalIndent(rawArguments, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(output), nil
+}
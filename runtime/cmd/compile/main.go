@@ -21,11 +21,9 @@ package main
 import (
 	"os"
 
-	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/cmd"
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/compiler"
-	"github.com/onflow/cadence/runtime/compiler/ir"
 	"github.com/onflow/cadence/runtime/compiler/wasm"
 )
 
@@ -50,42 +48,21 @@ func main() {
 
 	// Compile all functions
 
-	comp := compiler.NewCompiler(checker)
-
-	functionDeclarations := checker.Program.FunctionDeclarations()
-
-	funcs := make([]*ir.Func, len(functionDeclarations))
-
-	for i, functionDeclaration := range functionDeclarations {
-		funcs[i] = functionDeclaration.Accept(comp).(*ir.Func)
+	funcs, err := compiler.Compile(checker)
+	if err != nil {
+		cmd.ExitWithError(err.Error())
 	}
 
-	// Generate a WebAssembly module for the functions
+	// Generate a WebAssembly module for the functions.
+	// All functions are exported by name, independent of their access modifier
 
 	module := compiler.GenerateWasm(funcs)
 
-	// Export all public functions
-
-	for i, functionDeclaration := range functionDeclarations {
-		if functionDeclaration.Access != ast.AccessPublic {
-			continue
-		}
-
-		module.Exports = append(module.Exports,
-			&wasm.Export{
-				Name: functionDeclaration.Identifier.Identifier,
-				Descriptor: wasm.FunctionExport{
-					FunctionIndex: uint32(i),
-				},
-			},
-		)
-	}
-
 	// Generate WASM binary
 
 	var buf wasm.Buffer
 	w := wasm.NewWASMWriter(&buf)
-	err := w.WriteModule(module)
+	err = w.WriteModule(module)
 	if err != nil {
 		panic(nil)
 	}
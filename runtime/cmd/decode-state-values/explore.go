@@ -0,0 +1,390 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/onflow/atree"
+
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+var accountFlag = flag.String("account", "", "explore the storage of the given account")
+var listFlag = flag.Bool("list", false, "list the storage domains and paths of the explored account, and the number of bytes they use")
+var valueFlag = flag.String("value", "", "print the value stored at the given path of the explored account, e.g. /storage/foo")
+var jsonFlag = flag.Bool("json", false, "print values in JSON-Cadence format instead of Cadence literal syntax")
+
+// accountDomains are the domains of the storage of an account,
+// in the order they are listed
+var accountDomains = []string{
+	common.PathDomainStorage.Identifier(),
+	common.PathDomainPublic.Identifier(),
+	common.PathDomainPrivate.Identifier(),
+	runtime.StorageDomainContract,
+}
+
+// storedValue is a value stored in a domain of an account
+type storedValue struct {
+	identifier string
+	value      interpreter.Value
+	// size is the number of bytes used to store the value,
+	// including all slabs reachable from it
+	size int
+}
+
+// accountDomain is a domain of the storage of an account
+type accountDomain struct {
+	name string
+	// size is the number of bytes used by the domain,
+	// including all values stored in it
+	size   int
+	values []storedValue
+}
+
+// accountExplorer explores the storage of an account.
+//
+// Both storage formats are supported:
+// Values stored in a storage map, which is referred to by the register of the domain,
+// and values stored in separate registers, which are keyed by domain and identifier
+type accountExplorer struct {
+	address common.Address
+	storage *interpreterStorage
+	inter   *interpreter.Interpreter
+}
+
+func newAccountExplorer(address common.Address) (*accountExplorer, error) {
+	explorerStorage := &interpreterStorage{
+		slabStorage: &slabStorage{},
+	}
+
+	inter, err := interpreter.NewInterpreter(
+		nil,
+		nil,
+		interpreter.WithStorage(explorerStorage),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &accountExplorer{
+		address: address,
+		storage: explorerStorage,
+		inter:   inter,
+	}, nil
+}
+
+// domains returns the non-empty domains of the storage of the account.
+//
+// The values of each domain are ordered by size, largest first
+func (e *accountExplorer) domains() (domains []accountDomain, err error) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to load storage of account %s: %v", e.address, r)
+		}
+	}()
+
+	owner := string(e.address[:])
+
+	domainsByName := map[string]*accountDomain{}
+
+	getDomain := func(name string) *accountDomain {
+		domain, ok := domainsByName[name]
+		if !ok {
+			domain = &accountDomain{name: name}
+			domainsByName[name] = domain
+		}
+		return domain
+	}
+
+	// Values stored in storage maps
+
+	for _, name := range accountDomains {
+		storageMap := e.storage.GetStorageMap(e.address, name, false)
+		if storageMap == nil {
+			continue
+		}
+
+		domain := getDomain(name)
+
+		// The register of the domain refers to the storage map
+
+		domain.size += e.footprint(atree.StorageIDStorable(storageMap.StorageID()))
+
+		iterator := storageMap.Iterator(nil)
+		for {
+			identifier, value := iterator.Next()
+			if value == nil {
+				break
+			}
+
+			size, _ := storageMap.ValueFootprint(nil, identifier)
+
+			domain.values = append(
+				domain.values,
+				storedValue{
+					identifier: identifier,
+					value:      value,
+					size:       int(size),
+				},
+			)
+		}
+	}
+
+	// Values stored in separate registers.
+	//
+	// NOTE: iteration over map is safe,
+	// as the values are sorted below
+
+	for key, data := range storage { //nolint:maprangecheck
+		if key[0] != owner {
+			continue
+		}
+
+		name, identifier, ok := registerPath(key[2])
+		if !ok {
+			continue
+		}
+
+		value, size := e.registerValue(data)
+
+		domain := getDomain(name)
+		domain.size += size
+		domain.values = append(
+			domain.values,
+			storedValue{
+				identifier: identifier,
+				value:      value,
+				size:       size,
+			},
+		)
+	}
+
+	for _, name := range accountDomains {
+		domain, ok := domainsByName[name]
+		if !ok {
+			continue
+		}
+
+		sort.Slice(domain.values, func(i, j int) bool {
+			a := domain.values[i]
+			b := domain.values[j]
+			if a.size != b.size {
+				return a.size > b.size
+			}
+			return a.identifier < b.identifier
+		})
+
+		domains = append(domains, *domain)
+	}
+
+	return domains, nil
+}
+
+// value returns the value stored in the given domain of the account
+// with the given identifier, or nil if no value is stored
+func (e *accountExplorer) value(domain string, identifier string) (value interpreter.Value, err error) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to load value /%s/%s of account %s: %v", domain, identifier, e.address, r)
+		}
+	}()
+
+	storageMap := e.storage.GetStorageMap(e.address, domain, false)
+	if storageMap != nil && storageMap.ValueExists(identifier) {
+		return storageMap.ReadValue(e.inter, identifier), nil
+	}
+
+	owner := string(e.address[:])
+
+	for key, data := range storage { //nolint:maprangecheck
+		if key[0] != owner {
+			continue
+		}
+
+		registerDomain, registerIdentifier, ok := registerPath(key[2])
+		if ok && registerDomain == domain && registerIdentifier == identifier {
+			value, _ = e.registerValue(data)
+			return value, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// registerPath returns the domain and identifier of the given register key,
+// if the register stores a value separately, i.e. not in a storage map
+func registerPath(key string) (domain string, identifier string, ok bool) {
+	if isSlabStorageKey(key) {
+		return "", "", false
+	}
+
+	keyParts := strings.SplitN(key, storagePathSeparator, 2)
+	if len(keyParts) != 2 ||
+		common.PathDomainFromIdentifier(keyParts[0]) == common.PathDomainUnknown {
+
+		return "", "", false
+	}
+
+	return keyParts[0], keyParts[1], true
+}
+
+// registerValue decodes the value stored in a separate register,
+// and returns it together with the number of bytes it uses
+func (e *accountExplorer) registerValue(data []byte) (interpreter.Value, int) {
+	decoder := interpreter.CBORDecMode.NewStreamDecoder(bytes.NewReader(data))
+	storable, err := interpreter.DecodeStorable(decoder, atree.StorageIDUndefined, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	value := interpreter.StoredValue(nil, storable, e.storage)
+
+	return value, e.footprint(storable)
+}
+
+// footprint returns the number of bytes used by the given storable,
+// including all slabs reachable from it.
+//
+// The same measure is used by the storageSize function of accounts
+func (e *accountExplorer) footprint(storable atree.Storable) int {
+	size, err := interpreter.StorableFootprint(nil, storable, e.storage)
+	if err != nil {
+		panic(err)
+	}
+
+	return int(size)
+}
+
+// printDomains writes the given domains and their values to the given writer,
+// together with the number of bytes they use
+func printDomains(w io.Writer, domains []accountDomain) {
+	var total int
+
+	for _, domain := range domains {
+		total += domain.size
+
+		_, _ = fmt.Fprintf(w, "/%s: %d bytes\n", domain.name, domain.size)
+
+		for _, value := range domain.values {
+			_, _ = fmt.Fprintf(w, "  /%s/%s: %d bytes\n", domain.name, value.identifier, value.size)
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "total: %d bytes\n", total)
+}
+
+// printValue writes the given value to the given writer,
+// in Cadence literal syntax, or in JSON-Cadence format
+func (e *accountExplorer) printValue(w io.Writer, value interpreter.Value, json bool) error {
+	if !json {
+		_, err := fmt.Fprintln(w, value.String())
+		return err
+	}
+
+	exportedValue, err := newValueExporter(e.inter).exportValue(value)
+	if err != nil {
+		return err
+	}
+
+	encoded, err := jsoncdc.Encode(exportedValue)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s\n", encoded)
+	return err
+}
+
+// parseStoragePath parses a path of the form `/domain/identifier`.
+// The domain may also be the contract domain, in which case the identifier is a contract name
+func parseStoragePath(path string) (domain string, identifier string, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(path, "/"), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid path, expected /domain/identifier: %s", path)
+	}
+
+	for _, accountDomain := range accountDomains {
+		if parts[0] == accountDomain {
+			return parts[0], parts[1], nil
+		}
+	}
+
+	return "", "", fmt.Errorf(
+		"invalid domain %s, expected one of: %s",
+		parts[0],
+		strings.Join(accountDomains, ", "),
+	)
+}
+
+// explore lists the storage of the account with the given address,
+// and prints the requested value
+func explore(hexAddress string) {
+
+	address, err := common.HexToAddress(hexAddress)
+	if err != nil {
+		log.Fatalf("Invalid address: %s", hexAddress)
+	}
+
+	explorer, err := newAccountExplorer(address)
+	if err != nil {
+		log.Fatalf("Failed to create explorer: %s", err)
+	}
+
+	if *listFlag {
+		domains, err := explorer.domains()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		printDomains(os.Stdout, domains)
+	}
+
+	if *valueFlag != "" {
+		domain, identifier, err := parseStoragePath(*valueFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		value, err := explorer.value(domain, identifier)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if value == nil {
+			log.Fatalf("No value stored at %s in account %s", *valueFlag, address)
+		}
+
+		err = explorer.printValue(os.Stdout, value, *jsonFlag)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/onflow/atree"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// dumpLedger is a ledger which stores registers in the state dump
+type dumpLedger struct {
+	storageIndices map[string]uint64
+}
+
+var _ atree.Ledger = dumpLedger{}
+
+func (l dumpLedger) GetValue(owner, key []byte) ([]byte, error) {
+	return storage[storageKey{string(owner), "", string(key)}], nil
+}
+
+func (l dumpLedger) SetValue(owner, key, value []byte) error {
+	k := storageKey{string(owner), "", string(key)}
+	if len(value) == 0 {
+		delete(storage, k)
+	} else {
+		storage[k] = append([]byte(nil), value...)
+	}
+	return nil
+}
+
+func (l dumpLedger) ValueExists(owner, key []byte) (bool, error) {
+	return len(storage[storageKey{string(owner), "", string(key)}]) > 0, nil
+}
+
+func (l dumpLedger) AllocateStorageIndex(owner []byte) (result atree.StorageIndex, err error) {
+	index := l.storageIndices[string(owner)] + 1
+	l.storageIndices[string(owner)] = index
+	binary.BigEndian.PutUint64(result[:], index)
+	return
+}
+
+// newTestState replaces the state dump with one which has values stored
+// in the storage and public domains of the given account,
+// both in storage maps and in separate registers
+func newTestState(t *testing.T, address common.Address) {
+
+	storage = map[storageKey][]byte{}
+
+	runtimeStorage := runtime.NewStorage(
+		dumpLedger{
+			storageIndices: map[string]uint64{},
+		},
+		nil,
+	)
+
+	inter, err := interpreter.NewInterpreter(
+		nil,
+		common.StringLocation("test"),
+		interpreter.WithStorage(runtimeStorage),
+	)
+	require.NoError(t, err)
+
+	storageMap := runtimeStorage.GetStorageMap(address, common.PathDomainStorage.Identifier(), true)
+
+	storageMap.WriteValue(
+		inter,
+		"numbers",
+		interpreter.NewArrayValue(
+			inter,
+			interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeInt,
+			},
+			address,
+			interpreter.NewUnmeteredIntValueFromInt64(1),
+			interpreter.NewUnmeteredIntValueFromInt64(2),
+			interpreter.NewUnmeteredIntValueFromInt64(3),
+		),
+	)
+
+	storageMap.WriteValue(
+		inter,
+		"foo",
+		interpreter.NewCompositeValue(
+			inter,
+			common.AddressLocation{
+				Address: address,
+				Name:    "Test",
+			},
+			"Test.Foo",
+			common.CompositeKindStructure,
+			[]interpreter.CompositeField{
+				interpreter.NewUnmeteredCompositeField("b", interpreter.NewUnmeteredStringValue("x")),
+				interpreter.NewUnmeteredCompositeField("a", interpreter.NewUnmeteredIntValueFromInt64(1)),
+			},
+			address,
+		),
+	)
+
+	storageMap.WriteValue(inter, "answer", interpreter.NewUnmeteredIntValueFromInt64(42))
+
+	publicStorageMap := runtimeStorage.GetStorageMap(address, common.PathDomainPublic.Identifier(), true)
+
+	publicStorageMap.WriteValue(
+		inter,
+		"numbers",
+		interpreter.LinkValue{
+			TargetPath: interpreter.PathValue{
+				Domain:     common.PathDomainStorage,
+				Identifier: "numbers",
+			},
+			Type: interpreter.ReferenceStaticType{
+				BorrowedType: interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeInt,
+				},
+			},
+		},
+	)
+
+	err = runtimeStorage.Commit(inter, false)
+	require.NoError(t, err)
+
+	// A value stored in a separate register
+
+	data, err := atree.Encode(interpreter.NewUnmeteredStringValue("hello"), interpreter.CBOREncMode)
+	require.NoError(t, err)
+
+	storage[storageKey{
+		string(address[:]),
+		"",
+		common.PathDomainStorage.Identifier() + storagePathSeparator + "legacy",
+	}] = data
+}
+
+func TestAccountExplorerDomains(t *testing.T) {
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	newTestState(t, address)
+
+	explorer, err := newAccountExplorer(address)
+	require.NoError(t, err)
+
+	domains, err := explorer.domains()
+	require.NoError(t, err)
+
+	require.Len(t, domains, 2)
+
+	storageDomain := domains[0]
+	assert.Equal(t, "storage", storageDomain.name)
+
+	identifiers := make([]string, len(storageDomain.values))
+	for i, value := range storageDomain.values {
+		identifiers[i] = value.identifier
+	}
+
+	// The values are ordered by size, largest first.
+	// The array and the composite are stored in separate slabs,
+	// the other values are inlined
+
+	assert.ElementsMatch(t, []string{"foo", "numbers", "legacy", "answer"}, identifiers)
+	assert.ElementsMatch(t, []string{"foo", "numbers"}, identifiers[:2])
+
+	for i := 1; i < len(storageDomain.values); i++ {
+		assert.GreaterOrEqual(t, storageDomain.values[i-1].size, storageDomain.values[i].size)
+	}
+
+	publicDomain := domains[1]
+	assert.Equal(t, "public", publicDomain.name)
+	require.Len(t, publicDomain.values, 1)
+	assert.Equal(t, "numbers", publicDomain.values[0].identifier)
+
+	// The sizes of values in storage maps are the ones reported by storageSize,
+	// and the sizes of the domains include the storage maps themselves
+
+	storageMap := explorer.storage.GetStorageMap(address, storageDomain.name, false)
+	require.NotNil(t, storageMap)
+
+	var valuesSize int
+	for _, value := range storageDomain.values {
+		assert.Positive(t, value.size)
+		valuesSize += value.size
+
+		if value.identifier == "legacy" {
+			continue
+		}
+
+		expectedSize, ok := storageMap.ValueFootprint(nil, value.identifier)
+		require.True(t, ok)
+		assert.Equal(t, int(expectedSize), value.size)
+	}
+
+	assert.Greater(t, storageDomain.size, valuesSize)
+
+	var out bytes.Buffer
+	printDomains(&out, domains)
+	assert.Contains(t, out.String(), "/storage/numbers: ")
+	assert.Contains(t, out.String(), "/public/numbers: ")
+}
+
+func TestAccountExplorerValue(t *testing.T) {
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	newTestState(t, address)
+
+	explorer, err := newAccountExplorer(address)
+	require.NoError(t, err)
+
+	test := func(domain, identifier string, expectedLiteral string, expectedJSON string) {
+		value, err := explorer.value(domain, identifier)
+		require.NoError(t, err)
+		require.NotNil(t, value)
+
+		var out bytes.Buffer
+		err = explorer.printValue(&out, value, false)
+		require.NoError(t, err)
+		assert.Equal(t, expectedLiteral+"\n", out.String())
+
+		out.Reset()
+		err = explorer.printValue(&out, value, true)
+		require.NoError(t, err)
+		assert.JSONEq(t, expectedJSON, out.String())
+	}
+
+	test(
+		"storage",
+		"numbers",
+		"[1, 2, 3]",
+		`{
+          "type": "Array",
+          "value": [
+            {"type": "Int", "value": "1"},
+            {"type": "Int", "value": "2"},
+            {"type": "Int", "value": "3"}
+          ]
+        }`,
+	)
+
+	test(
+		"storage",
+		"foo",
+		`A.0000000000000001.Test.Foo(b: "x", a: 1)`,
+		`{
+          "type": "Struct",
+          "value": {
+            "id": "A.0000000000000001.Test.Foo",
+            "fields": [
+              {"name": "a", "value": {"type": "Int", "value": "1"}},
+              {"name": "b", "value": {"type": "String", "value": "x"}}
+            ]
+          }
+        }`,
+	)
+
+	test(
+		"storage",
+		"legacy",
+		`"hello"`,
+		`{"type": "String", "value": "hello"}`,
+	)
+
+	test(
+		"public",
+		"numbers",
+		"Link<&[Int]>(/storage/numbers)",
+		`{
+          "type": "Link",
+          "value": {
+            "targetPath": {
+              "type": "Path",
+              "value": {"domain": "storage", "identifier": "numbers"}
+            },
+            "borrowType": "&[Int]"
+          }
+        }`,
+	)
+
+	value, err := explorer.value("storage", "missing")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestParseStoragePath(t *testing.T) {
+
+	t.Parallel()
+
+	domain, identifier, err := parseStoragePath("/storage/foo")
+	require.NoError(t, err)
+	assert.Equal(t, "storage", domain)
+	assert.Equal(t, "foo", identifier)
+
+	domain, identifier, err = parseStoragePath("/contract/Test")
+	require.NoError(t, err)
+	assert.Equal(t, "contract", domain)
+	assert.Equal(t, "Test", identifier)
+
+	for _, path := range []string{"", "/storage", "/storage/", "/unknown/foo"} {
+		_, _, err = parseStoragePath(path)
+		assert.Error(t, err, path)
+	}
+}
@@ -0,0 +1,256 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// valueExporter exports stored values to their JSON-Cadence representation.
+//
+// Unlike runtime.ExportValue, the export does not require the programs
+// which declare the types of the values, which are not available in a state dump:
+// Composite values are exported with the fields they have, ordered by name,
+// and composite and interface types are exported without members.
+// The kind of composite types is unknown, so they are exported as structure types
+type valueExporter struct {
+	inter *interpreter.Interpreter
+}
+
+func newValueExporter(inter *interpreter.Interpreter) valueExporter {
+	return valueExporter{
+		inter: inter,
+	}
+}
+
+func (e valueExporter) exportValue(value interpreter.Value) (cadence.Value, error) {
+	switch v := value.(type) {
+	case interpreter.NilValue:
+		return cadence.NewOptional(nil), nil
+
+	case *interpreter.SomeValue:
+		innerValue, err := e.exportValue(v.InnerValue(e.inter, interpreter.ReturnEmptyLocationRange))
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewOptional(innerValue), nil
+
+	case *interpreter.ArrayValue:
+		return e.exportArrayValue(v)
+
+	case *interpreter.DictionaryValue:
+		return e.exportDictionaryValue(v)
+
+	case *interpreter.CompositeValue:
+		return e.exportCompositeValue(v)
+
+	case interpreter.LinkValue:
+		targetPath, err := e.exportValue(v.TargetPath)
+		if err != nil {
+			return nil, err
+		}
+		borrowType, err := e.exportType(v.Type)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewLink(targetPath.(cadence.Path), borrowType.ID()), nil
+
+	case *interpreter.CapabilityValue:
+		path, err := e.exportValue(v.Path)
+		if err != nil {
+			return nil, err
+		}
+		borrowType, err := e.exportType(v.BorrowType)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewCapability(
+			path.(cadence.Path),
+			cadence.Address(v.Address),
+			borrowType,
+		), nil
+
+	case interpreter.TypeValue:
+		staticType, err := e.exportType(v.Type)
+		if err != nil {
+			return nil, err
+		}
+		return cadence.NewTypeValue(staticType), nil
+
+	default:
+		// All other values do not contain values or types
+		return runtime.ExportValue(value, e.inter)
+	}
+}
+
+func (e valueExporter) exportArrayValue(v *interpreter.ArrayValue) (cadence.Value, error) {
+	values := make([]cadence.Value, 0, v.Count())
+
+	var err error
+	v.Iterate(nil, func(element interpreter.Value) (resume bool) {
+		var exportedElement cadence.Value
+		exportedElement, err = e.exportValue(element)
+		if err != nil {
+			return false
+		}
+		values = append(values, exportedElement)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cadence.NewArray(values), nil
+}
+
+func (e valueExporter) exportDictionaryValue(v *interpreter.DictionaryValue) (cadence.Value, error) {
+	pairs := make([]cadence.KeyValuePair, 0, v.Count())
+
+	var err error
+	v.Iterate(nil, func(key, value interpreter.Value) (resume bool) {
+		var exportedKey, exportedValue cadence.Value
+		exportedKey, err = e.exportValue(key)
+		if err != nil {
+			return false
+		}
+		exportedValue, err = e.exportValue(value)
+		if err != nil {
+			return false
+		}
+		pairs = append(
+			pairs,
+			cadence.KeyValuePair{
+				Key:   exportedKey,
+				Value: exportedValue,
+			},
+		)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cadence.NewDictionary(pairs), nil
+}
+
+func (e valueExporter) exportCompositeValue(v *interpreter.CompositeValue) (cadence.Value, error) {
+
+	var fieldNames []string
+	v.ForEachField(nil, func(fieldName string, _ interpreter.Value) {
+		fieldNames = append(fieldNames, fieldName)
+	})
+	sort.Strings(fieldNames)
+
+	// The types of the fields are unknown
+
+	fieldTypes := make([]cadence.Field, len(fieldNames))
+	fieldValues := make([]cadence.Value, len(fieldNames))
+
+	for i, fieldName := range fieldNames {
+		fieldValue, err := e.exportValue(v.GetField(e.inter, interpreter.ReturnEmptyLocationRange, fieldName))
+		if err != nil {
+			return nil, err
+		}
+
+		fieldTypes[i] = cadence.Field{
+			Identifier: fieldName,
+		}
+		fieldValues[i] = fieldValue
+	}
+
+	switch v.Kind {
+	case common.CompositeKindStructure:
+		return cadence.NewStruct(fieldValues).WithType(&cadence.StructType{
+			Location:            v.Location,
+			QualifiedIdentifier: v.QualifiedIdentifier,
+			Fields:              fieldTypes,
+		}), nil
+
+	case common.CompositeKindResource:
+		return cadence.NewResource(fieldValues).WithType(&cadence.ResourceType{
+			Location:            v.Location,
+			QualifiedIdentifier: v.QualifiedIdentifier,
+			Fields:              fieldTypes,
+		}), nil
+
+	case common.CompositeKindEvent:
+		return cadence.NewEvent(fieldValues).WithType(&cadence.EventType{
+			Location:            v.Location,
+			QualifiedIdentifier: v.QualifiedIdentifier,
+			Fields:              fieldTypes,
+		}), nil
+
+	case common.CompositeKindContract:
+		return cadence.NewContract(fieldValues).WithType(&cadence.ContractType{
+			Location:            v.Location,
+			QualifiedIdentifier: v.QualifiedIdentifier,
+			Fields:              fieldTypes,
+		}), nil
+
+	case common.CompositeKindEnum:
+		return cadence.NewEnum(fieldValues).WithType(&cadence.EnumType{
+			Location:            v.Location,
+			QualifiedIdentifier: v.QualifiedIdentifier,
+			Fields:              fieldTypes,
+		}), nil
+	}
+
+	return nil, fmt.Errorf("cannot export composite value of kind %s", v.Kind.Name())
+}
+
+// exportType exports the given static type.
+// Composite and interface types are exported without members
+func (e valueExporter) exportType(staticType interpreter.StaticType) (cadence.Type, error) {
+	if staticType == nil {
+		return nil, nil
+	}
+
+	semaType, err := interpreter.ConvertStaticToSemaType(
+		nil,
+		staticType,
+		func(location common.Location, qualifiedIdentifier string) (*sema.InterfaceType, error) {
+			return &sema.InterfaceType{
+				Location:      location,
+				Identifier:    qualifiedIdentifier,
+				CompositeKind: common.CompositeKindStructure,
+				Members:       sema.NewStringMemberOrderedMap(),
+			}, nil
+		},
+		func(location common.Location, qualifiedIdentifier string, _ common.TypeID) (*sema.CompositeType, error) {
+			return &sema.CompositeType{
+				Location:   location,
+				Identifier: qualifiedIdentifier,
+				Kind:       common.CompositeKindStructure,
+				Members:    sema.NewStringMemberOrderedMap(),
+			}, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return runtime.ExportType(semaType, map[sema.TypeID]cadence.Type{}), nil
+}
@@ -16,7 +16,10 @@
  * limitations under the License.
  */
 
-// A utility program that parses a state dump in JSON Lines format and decodes all values
+// A utility program that parses a state dump in JSON Lines format and decodes all values.
+//
+// The storage of an account can be explored: Its domains and paths can be listed,
+// together with the number of bytes they use, and stored values can be printed
 
 package main
 
@@ -76,6 +79,9 @@ var storagePathSeparator = "\x1f"
 // '$' + 8 byte index
 const slabKeyLength = 9
 
+// 8 byte index of the root slab of a storage map
+const storageIndexLength = 8
+
 func isSlabStorageKey(key string) bool {
 	return len(key) == slabKeyLength && key[0] == '$'
 }
@@ -220,8 +226,25 @@ type interpreterStorage struct {
 
 var _ interpreter.Storage = &interpreterStorage{}
 
-func (i interpreterStorage) GetStorageMap(_ common.Address, _ string, _ bool) *interpreter.StorageMap {
-	panic("unexpected GetStorageMap call")
+// GetStorageMap returns the storage map for the given account domain,
+// if the domain register refers to one.
+//
+// The storage is read-only, so storage maps are never created
+func (i interpreterStorage) GetStorageMap(address common.Address, domain string, _ bool) *interpreter.StorageMap {
+	data, ok := storage[storageKey{string(address[:]), "", domain}]
+	if !ok || len(data) != storageIndexLength {
+		return nil
+	}
+
+	var storageIndex atree.StorageIndex
+	copy(storageIndex[:], data)
+
+	storageID := atree.StorageID{
+		Address: atree.Address(address),
+		Index:   storageIndex,
+	}
+
+	return interpreter.NewStorageMapWithRootID(i.slabStorage, storageID)
 }
 
 func (i interpreterStorage) CheckHealth() error {
@@ -415,6 +438,13 @@ func main() {
 
 	var addresses []common.Address
 
+	// Only keep the ledger keys of the explored account,
+	// unless other addresses are given explicitly
+
+	if *accountFlag != "" && len(addressesFlag) == 0 {
+		addressesFlag = append(addressesFlag, *accountFlag)
+	}
+
 	for _, hexAddress := range addressesFlag {
 		address, err := common.HexToAddress(hexAddress)
 		if err != nil {
@@ -435,6 +465,10 @@ func main() {
 		load()
 	}
 
+	if *accountFlag != "" {
+		explore(*accountFlag)
+	}
+
 	if *printFlag {
 		for key, value := range storage { //nolint:maprangecheck
 			var keyParts []encodedKeyPart
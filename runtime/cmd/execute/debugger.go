@@ -21,12 +21,15 @@ package execute
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/c-bata/go-prompt"
 
+	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/parser2"
 )
 
 const commandShortHelp = "h"
@@ -35,6 +38,20 @@ const commandShortContinue = "c"
 const commandLongContinue = "continue"
 const commandShortNext = "n"
 const commandLongNext = "next"
+const commandShortStepOver = "o"
+const commandLongStepOver = "over"
+const commandShortStepOut = "u"
+const commandLongStepOut = "out"
+const commandShortBreak = "b"
+const commandLongBreak = "break"
+const commandShortDelete = "d"
+const commandLongDelete = "delete"
+const commandShortBreakpoints = "l"
+const commandLongBreakpoints = "breakpoints"
+const commandShortPrint = "p"
+const commandLongPrint = "print"
+const commandLongWatch = "watch"
+const commandLongUnwatch = "unwatch"
 const commandLongExit = "exit"
 const commandShortShow = "s"
 const commandLongShow = "show"
@@ -43,7 +60,15 @@ const commandLongWhere = "where"
 
 var debuggerCommandSuggestions = []prompt.Suggest{
 	{Text: commandLongContinue, Description: "Continue"},
-	{Text: commandLongNext, Description: "Next / step"},
+	{Text: commandLongNext, Description: "Next / step in"},
+	{Text: commandLongStepOver, Description: "Step over"},
+	{Text: commandLongStepOut, Description: "Step out"},
+	{Text: commandLongBreak, Description: "Set breakpoint: break <line> [<condition>]"},
+	{Text: commandLongDelete, Description: "Delete breakpoint: delete <line>"},
+	{Text: commandLongBreakpoints, Description: "List breakpoints"},
+	{Text: commandLongPrint, Description: "Evaluate expression: print <expression>"},
+	{Text: commandLongWatch, Description: "Watch expression: watch <expression>"},
+	{Text: commandLongUnwatch, Description: "Remove watch expression: unwatch <expression>"},
 	{Text: commandLongWhere, Description: "Location info"},
 	{Text: commandLongShow, Description: "Show variable(s)"},
 	{Text: commandLongExit, Description: "Exit"},
@@ -53,6 +78,7 @@ var debuggerCommandSuggestions = []prompt.Suggest{
 type InteractiveDebugger struct {
 	debugger *interpreter.Debugger
 	stop     interpreter.Stop
+	watches  []string
 }
 
 func NewInteractiveDebugger(debugger *interpreter.Debugger, stop interpreter.Stop) *InteractiveDebugger {
@@ -62,12 +88,146 @@ func NewInteractiveDebugger(debugger *interpreter.Debugger, stop interpreter.Sto
 	}
 }
 
+// SetStop sets the stop the interactive debugger operates on,
+// e.g. when the execution stopped at a breakpoint
+//
+func (d *InteractiveDebugger) SetStop(stop interpreter.Stop) {
+	d.stop = stop
+}
+
 func (d *InteractiveDebugger) Continue() {
 	d.debugger.Continue()
 }
 
 func (d *InteractiveDebugger) Next() {
 	d.stop = d.debugger.Next()
+	d.showStop()
+}
+
+func (d *InteractiveDebugger) StepOver() {
+	d.stop = d.debugger.StepOver()
+	d.showStop()
+}
+
+func (d *InteractiveDebugger) StepOut() {
+	d.stop = d.debugger.StepOut()
+	d.showStop()
+}
+
+// showStop shows the location of the current stop and the values of the watch expressions
+//
+func (d *InteractiveDebugger) showStop() {
+	d.Where()
+	for _, expression := range d.watches {
+		d.showEvaluation(expression)
+	}
+}
+
+// Break sets a breakpoint for the given line in the current location.
+// The optional remaining arguments are the condition of the breakpoint
+//
+func (d *InteractiveDebugger) Break(arguments []string) {
+	line, ok := parseLine(arguments)
+	if !ok {
+		return
+	}
+
+	var condition ast.Expression
+	if len(arguments) > 1 {
+		code := strings.Join(arguments[1:], " ")
+
+		var err error
+		condition, err = parseExpression(code)
+		if err != nil {
+			fmt.Println(colorizeError(fmt.Sprintf("%s: error: %s", code, err)))
+			return
+		}
+	}
+
+	d.debugger.AddConditionalBreakpoint(d.stop.Interpreter.Location, line, condition)
+}
+
+// Delete deletes the breakpoint for the given line in the current location
+//
+func (d *InteractiveDebugger) Delete(arguments []string) {
+	line, ok := parseLine(arguments)
+	if !ok {
+		return
+	}
+
+	if !d.debugger.RemoveBreakpoint(d.stop.Interpreter.Location, line) {
+		fmt.Println(colorizeError(fmt.Sprintf("error: no breakpoint at line %d", line)))
+	}
+}
+
+func parseLine(arguments []string) (int, bool) {
+	if len(arguments) < 1 {
+		fmt.Println(colorizeError("error: missing line"))
+		return 0, false
+	}
+
+	line, err := strconv.Atoi(arguments[0])
+	if err != nil || line < 1 {
+		fmt.Println(colorizeError(fmt.Sprintf("error: invalid line: %s", arguments[0])))
+		return 0, false
+	}
+
+	return line, true
+}
+
+// Breakpoints lists all breakpoints
+//
+func (d *InteractiveDebugger) Breakpoints() {
+	for _, breakpoint := range d.debugger.Breakpoints() {
+		if breakpoint.Condition == nil {
+			fmt.Printf("%s @ %d\n", breakpoint.Location, breakpoint.Line)
+		} else {
+			fmt.Printf("%s @ %d if %s\n", breakpoint.Location, breakpoint.Line, breakpoint.Condition)
+		}
+	}
+}
+
+// Print evaluates the given expression in the current activation and shows the result
+//
+func (d *InteractiveDebugger) Print(arguments []string) {
+	d.showEvaluation(strings.Join(arguments, " "))
+}
+
+func (d *InteractiveDebugger) showEvaluation(code string) {
+	expression, err := parseExpression(code)
+	if err != nil {
+		fmt.Println(colorizeError(fmt.Sprintf("%s: error: %s", code, err)))
+		return
+	}
+
+	value, err := d.debugger.Evaluate(d.stop.Interpreter, expression)
+	if err != nil {
+		fmt.Println(colorizeError(fmt.Sprintf("%s: error: %s", code, err)))
+		return
+	}
+
+	fmt.Printf("%s = %s\n", code, formatValue(value))
+}
+
+// Watch adds the given expression to the expressions
+// which are evaluated and shown each time the execution stops
+//
+func (d *InteractiveDebugger) Watch(arguments []string) {
+	expression := strings.Join(arguments, " ")
+	d.watches = append(d.watches, expression)
+	d.showEvaluation(expression)
+}
+
+// Unwatch removes the given expression from the watched expressions
+//
+func (d *InteractiveDebugger) Unwatch(arguments []string) {
+	expression := strings.Join(arguments, " ")
+	for i, watch := range d.watches {
+		if watch == expression {
+			d.watches = append(d.watches[:i], d.watches[i+1:]...)
+			return
+		}
+	}
 }
 
 // Show shows the values for the variables with the given names.
@@ -123,6 +283,22 @@ func (d *InteractiveDebugger) Run() {
 			d.Continue()
 		case commandShortNext, commandLongNext:
 			d.Next()
+		case commandShortStepOver, commandLongStepOver:
+			d.StepOver()
+		case commandShortStepOut, commandLongStepOut:
+			d.StepOut()
+		case commandShortBreak, commandLongBreak:
+			d.Break(arguments)
+		case commandShortDelete, commandLongDelete:
+			d.Delete(arguments)
+		case commandShortBreakpoints, commandLongBreakpoints:
+			d.Breakpoints()
+		case commandShortPrint, commandLongPrint:
+			d.Print(arguments)
+		case commandLongWatch:
+			d.Watch(arguments)
+		case commandLongUnwatch:
+			d.Unwatch(arguments)
 		case commandShortShow, commandLongShow:
 			d.Show(arguments)
 		case commandShortWhere, commandLongWhere:
@@ -156,6 +332,8 @@ func (d *InteractiveDebugger) Run() {
 
 	fmt.Println()
 
+	d.showStop()
+
 	prompt.New(
 		executor,
 		suggest,
@@ -183,3 +361,17 @@ func (d *InteractiveDebugger) Where() {
 		d.stop.Statement.StartPosition().Line,
 	)
 }
+
+// parseExpression parses the given code as an expression,
+// e.g. the condition of a breakpoint, or a watch expression
+//
+func parseExpression(code string) (ast.Expression, error) {
+	expression, errs := parser2.ParseExpression(code, nil)
+	if len(errs) > 0 {
+		return nil, parser2.Error{
+			Code:   code,
+			Errors: errs,
+		}
+	}
+	return expression, nil
+}
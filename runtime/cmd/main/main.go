@@ -19,15 +19,28 @@
 package main
 
 import (
+	"flag"
 	"os"
 	"os/signal"
 
 	"github.com/onflow/cadence/runtime/cmd/execute"
+	"github.com/onflow/cadence/runtime/cmd/test"
 	"github.com/onflow/cadence/runtime/interpreter"
 )
 
+var debugFlag = flag.Bool("debug", false, "stop in the interactive debugger before the first statement")
+
 func main() {
-	if len(os.Args) > 1 {
+	flag.Parse()
+
+	args := flag.Args()
+
+	if len(args) > 0 && args[0] == "test" {
+		test.Run(args[1:])
+		return
+	}
+
+	if len(args) > 0 {
 		// TODO: also make the REPL support the interactive debugger
 
 		signals := make(chan os.Signal, 1)
@@ -36,15 +49,34 @@ func main() {
 
 		debugger := interpreter.NewDebugger()
 
+		if *debugFlag {
+			debugger.RequestPause()
+		}
+
 		go func() {
 			for range signals {
-				stop := debugger.Pause()
-				execute.NewInteractiveDebugger(debugger, stop).Run()
+				debugger.RequestPause()
+			}
+		}()
+
+		// The execution stops when a pause is requested, or when a breakpoint is hit
+
+		go func() {
+			var interactiveDebugger *execute.InteractiveDebugger
+
+			for stop := range debugger.Stops() {
+				if interactiveDebugger == nil {
+					interactiveDebugger = execute.NewInteractiveDebugger(debugger, stop)
+				} else {
+					interactiveDebugger.SetStop(stop)
+				}
+
+				interactiveDebugger.Run()
 				debugger.Continue()
 			}
 		}()
 
-		execute.Execute(os.Args[1:], debugger)
+		execute.Execute(args, debugger)
 	} else {
 		execute.RunREPL()
 	}
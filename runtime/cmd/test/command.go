@@ -0,0 +1,146 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/pretty"
+)
+
+// testFileSuffix is the suffix of the names of the test program files
+// which are run when a directory is given
+const testFileSuffix = "_test.cdc"
+
+// Run runs the test functions of the test programs at the given paths,
+// reports the results, and exits with a non-zero status if any test failed.
+//
+// Directories are searched recursively for files ending in `_test.cdc`
+func Run(paths []string) {
+	if !RunAndReport(paths, os.Stdout, os.Stderr) {
+		os.Exit(1)
+	}
+}
+
+// RunAndReport runs the test functions of the test programs at the given paths,
+// writes the results to the given writers, and returns true if all tests passed
+func RunAndReport(paths []string, out io.Writer, errOut io.Writer) bool {
+
+	files, err := testFiles(paths)
+	if err != nil {
+		_, _ = fmt.Fprintln(errOut, pretty.FormatErrorMessage(pretty.ErrorPrefix, err.Error(), true))
+		return false
+	}
+
+	var passed, failed int
+
+	for _, file := range files {
+		runner := NewTestRunner()
+		location := common.StringLocation(file)
+
+		results, err := runner.RunFile(file)
+		if err != nil {
+			failed++
+			printErr := pretty.NewErrorPrettyPrinter(errOut, true).
+				PrettyPrintError(err, location, runner.Codes)
+			if printErr != nil {
+				panic(printErr)
+			}
+			continue
+		}
+
+		for _, result := range results {
+			if result.Passed() {
+				passed++
+				_, _ = fmt.Fprintf(out, "PASS: %s: %s\n", file, result.TestName)
+				continue
+			}
+
+			failed++
+
+			position := file
+			if result.Location.Location != nil {
+				position = fmt.Sprintf(
+					"%s:%d:%d",
+					result.Location.Location,
+					result.Location.StartPos.Line,
+					result.Location.StartPos.Column,
+				)
+			}
+
+			_, _ = fmt.Fprintf(out, "FAIL: %s: %s\n", position, result.TestName)
+
+			printErr := pretty.NewErrorPrettyPrinter(errOut, true).
+				PrettyPrintError(result.Error, location, runner.Codes)
+			if printErr != nil {
+				panic(printErr)
+			}
+		}
+	}
+
+	_, _ = fmt.Fprintf(out, "%d passed, %d failed\n", passed, failed)
+
+	return failed == 0
+}
+
+// testFiles returns the test program files at the given paths.
+// Files are used as given, directories are searched for files ending in `_test.cdc`
+func testFiles(paths []string) ([]string, error) {
+	var files []string
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		var directoryFiles []string
+
+		err = filepath.WalkDir(path, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), testFileSuffix) {
+				directoryFiles = append(directoryFiles, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Strings(directoryFiles)
+
+		files = append(files, directoryFiles...)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,260 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/inmemory"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+// emulatorBackend is a blockchain backend for the Test contract,
+// which executes scripts and transactions using a new runtime and in-memory storage
+//
+type emulatorBackend struct {
+	runtime        runtime.Runtime
+	ledger         *inmemory.Interface
+	events         []cadence.Event
+	pendingEvents  []cadence.Event
+	executionCount uint64
+}
+
+var _ stdlib.TestBlockchainBackend = &emulatorBackend{}
+
+func newEmulatorBackend() *emulatorBackend {
+	backend := &emulatorBackend{
+		runtime: runtime.NewInterpreterRuntime(),
+	}
+
+	// Emitted events are buffered, and only kept if the execution succeeds
+
+	backend.ledger = inmemory.NewInterface(
+		inmemory.WithOnEventEmittedHandler(func(event cadence.Event) {
+			backend.pendingEvents = append(backend.pendingEvents, event)
+		}),
+	)
+
+	return backend
+}
+
+func (b *emulatorBackend) RunScript(
+	inter *interpreter.Interpreter,
+	code string,
+	arguments []interpreter.Value,
+) stdlib.TestScriptResult {
+
+	encodedArguments, err := encodeArguments(inter, arguments)
+	if err != nil {
+		return stdlib.TestScriptResult{
+			Error: err,
+		}
+	}
+
+	b.ledger.SetSigningAccounts()
+
+	value, err := b.runtime.ExecuteScript(
+		runtime.Script{
+			Source:    []byte(code),
+			Arguments: encodedArguments,
+		},
+		runtime.Context{
+			Interface: b.ledger,
+			Location:  common.ScriptLocation(b.nextExecutionID()),
+		},
+	)
+	b.commitEvents(err)
+	if err != nil {
+		return stdlib.TestScriptResult{
+			Error: err,
+		}
+	}
+
+	result, err := runtime.ImportValue(inter, value, sema.AnyStructType)
+	if err != nil {
+		return stdlib.TestScriptResult{
+			Error: fmt.Errorf("cannot use script result in test: %w", err),
+		}
+	}
+
+	return stdlib.TestScriptResult{
+		Value: result,
+	}
+}
+
+func (b *emulatorBackend) CreateAccount() (common.Address, error) {
+	return b.ledger.CreateAccount(common.Address{})
+}
+
+func (b *emulatorBackend) RunTransaction(
+	inter *interpreter.Interpreter,
+	code string,
+	authorizers []common.Address,
+	arguments []interpreter.Value,
+) error {
+
+	encodedArguments, err := encodeArguments(inter, arguments)
+	if err != nil {
+		return err
+	}
+
+	b.ledger.SetSigningAccounts(authorizers...)
+
+	err = b.runtime.ExecuteTransaction(
+		runtime.Script{
+			Source:    []byte(code),
+			Arguments: encodedArguments,
+		},
+		runtime.Context{
+			Interface: b.ledger,
+			Location:  common.TransactionLocation(b.nextExecutionID()),
+		},
+	)
+	b.commitEvents(err)
+
+	return err
+}
+
+// DeployContract deploys the contract by executing a transaction
+// which is signed by the given account and adds the contract to it.
+//
+// The contract code is passed hex-encoded. The initializer arguments are passed
+// as transaction parameters of the types of the given values
+//
+func (b *emulatorBackend) DeployContract(
+	inter *interpreter.Interpreter,
+	name string,
+	code string,
+	account common.Address,
+	arguments []interpreter.Value,
+) error {
+
+	var builder strings.Builder
+
+	builder.WriteString("transaction(code: String")
+	for i, argument := range arguments {
+		argumentType := inter.MustConvertStaticToSemaType(argument.StaticType(inter))
+		builder.WriteString(fmt.Sprintf(", arg%d: %s", i, argumentType.QualifiedString()))
+	}
+	builder.WriteString(") {\n")
+	builder.WriteString("    prepare(signer: AuthAccount) {\n")
+	builder.WriteString(fmt.Sprintf("        signer.contracts.add(name: %q, code: code.decodeHex()", name))
+	for i := range arguments {
+		builder.WriteString(fmt.Sprintf(", arg%d", i))
+	}
+	builder.WriteString(")\n")
+	builder.WriteString("    }\n")
+	builder.WriteString("}\n")
+
+	transactionArguments := make([]interpreter.Value, 0, len(arguments)+1)
+	transactionArguments = append(
+		transactionArguments,
+		interpreter.NewUnmeteredStringValue(hex.EncodeToString([]byte(code))),
+	)
+	transactionArguments = append(transactionArguments, arguments...)
+
+	return b.RunTransaction(
+		inter,
+		builder.String(),
+		[]common.Address{account},
+		transactionArguments,
+	)
+}
+
+// Events returns the events of all successful scripts and transactions.
+//
+// Field values which cannot be imported into the test program,
+// e.g. values of types declared in deployed contracts, are given as strings
+//
+func (b *emulatorBackend) Events(inter *interpreter.Interpreter) []stdlib.TestEvent {
+	events := make([]stdlib.TestEvent, 0, len(b.events))
+
+	for _, event := range b.events {
+		fields := make([]stdlib.TestEventField, len(event.Fields))
+
+		for i, field := range event.Fields {
+			value, err := runtime.ImportValue(inter, field, sema.AnyStructType)
+			if err != nil {
+				value = interpreter.NewUnmeteredStringValue(field.String())
+			}
+
+			fields[i] = stdlib.TestEventField{
+				Name:  event.EventType.Fields[i].Identifier,
+				Value: value,
+			}
+		}
+
+		events = append(
+			events,
+			stdlib.TestEvent{
+				TypeID: common.TypeID(event.EventType.ID()),
+				Fields: fields,
+			},
+		)
+	}
+
+	return events
+}
+
+// commitEvents keeps the events emitted by the last execution if it succeeded,
+// and discards them otherwise
+//
+func (b *emulatorBackend) commitEvents(err error) {
+	if err == nil {
+		b.events = append(b.events, b.pendingEvents...)
+	}
+	b.pendingEvents = nil
+}
+
+// nextExecutionID returns a unique identifier for the location of the next script or transaction,
+// so programs of previous executions are not reused
+//
+func (b *emulatorBackend) nextExecutionID() []byte {
+	b.executionCount++
+	var id [8]byte
+	binary.BigEndian.PutUint64(id[:], b.executionCount)
+	return id[:]
+}
+
+func encodeArguments(inter *interpreter.Interpreter, arguments []interpreter.Value) ([][]byte, error) {
+	encodedArguments := make([][]byte, len(arguments))
+
+	for i, argument := range arguments {
+		exportedValue, err := runtime.ExportValue(argument, inter)
+		if err != nil {
+			return nil, err
+		}
+
+		encodedArguments[i], err = jsoncdc.Encode(exportedValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return encodedArguments, nil
+}
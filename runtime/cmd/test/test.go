@@ -0,0 +1,397 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	goErrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+// testFunctionPrefix is the prefix of the names of test functions.
+// Test functions are global functions without parameters
+const testFunctionPrefix = "test"
+
+// setupFunctionName is the name of the optional global function
+// which is run before each test function
+const setupFunctionName = "setup"
+
+var valueDeclarations = append(
+	stdlib.BuiltinFunctions,
+	stdlib.HelperFunctions...,
+)
+
+var typeDeclarations = stdlib.BuiltinTypes.ToTypeDeclarations()
+
+// Result is the result of running a test function
+type Result struct {
+	TestName string
+	// Error is the error which made the test fail, or nil if the test passed
+	Error error
+	// Location is the location in the test program where the test failed, if known
+	Location interpreter.LocationRange
+}
+
+func (r Result) Passed() bool {
+	return r.Error == nil
+}
+
+// TestRunner runs the test functions of test programs.
+//
+// Test programs may import the `Test` contract, and programs in other files.
+// Relative paths, of imports and of files read using `Test.readFile`,
+// are resolved against the directory of the test program.
+//
+// Each test function is run in a new interpreter with new storage,
+// so global state, e.g. a blockchain created in a global variable, is not shared between tests
+type TestRunner struct {
+	// Codes are the codes of all loaded programs, e.g. for pretty-printing errors
+	Codes    map[common.LocationID]string
+	checkers map[common.LocationID]*sema.Checker
+}
+
+func NewTestRunner() *TestRunner {
+	return &TestRunner{
+		Codes:    map[common.LocationID]string{},
+		checkers: map[common.LocationID]*sema.Checker{},
+	}
+}
+
+// RunFile runs the test functions of the test program in the given file.
+// An error is returned if the program could not be loaded, e.g. if it is invalid
+func (r *TestRunner) RunFile(path string) ([]Result, error) {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.RunCode(string(code), common.StringLocation(path))
+}
+
+// RunCode runs the test functions of the given test program.
+// An error is returned if the program could not be loaded, e.g. if it is invalid
+func (r *TestRunner) RunCode(code string, location common.StringLocation) ([]Result, error) {
+	directory := filepath.Dir(string(location))
+
+	checker, err := r.check(code, location, directory)
+	if err != nil {
+		return nil, err
+	}
+
+	var testFunctions []string
+	var hasSetup bool
+
+	for _, declaration := range checker.Program.FunctionDeclarations() {
+		name := declaration.Identifier.Identifier
+
+		if declaration.ParameterList != nil &&
+			len(declaration.ParameterList.Parameters) > 0 {
+
+			continue
+		}
+
+		switch {
+		case name == setupFunctionName:
+			hasSetup = true
+
+		case strings.HasPrefix(name, testFunctionPrefix):
+			testFunctions = append(testFunctions, name)
+		}
+	}
+
+	results := make([]Result, 0, len(testFunctions))
+
+	for _, testFunction := range testFunctions {
+		result := r.runTest(checker, directory, testFunction, hasSetup)
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (r *TestRunner) runTest(
+	checker *sema.Checker,
+	directory string,
+	testFunction string,
+	hasSetup bool,
+) Result {
+
+	result := Result{
+		TestName: testFunction,
+	}
+
+	err := func() error {
+		inter, err := r.newInterpreter(checker, directory)
+		if err != nil {
+			return err
+		}
+
+		err = inter.Interpret()
+		if err != nil {
+			return err
+		}
+
+		if hasSetup {
+			_, err = inter.Invoke(setupFunctionName)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = inter.Invoke(testFunction)
+		return err
+	}()
+
+	if err != nil {
+		result.Error = err
+		result.Location = failureLocation(err, checker.Location)
+	}
+
+	return result
+}
+
+func (r *TestRunner) check(
+	code string,
+	location common.StringLocation,
+	directory string,
+) (*sema.Checker, error) {
+
+	r.Codes[location.ID()] = code
+
+	program, err := parser2.ParseProgram(code, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	checker, err := sema.NewChecker(
+		program,
+		location,
+		nil,
+		sema.WithPredeclaredValues(valueDeclarations.ToSemaValueDeclarations()),
+		sema.WithPredeclaredTypes(typeDeclarations),
+		sema.WithImportHandler(
+			func(_ *sema.Checker, importedLocation common.Location, _ ast.Range) (sema.Import, error) {
+				if importedLocation == stdlib.TestContractLocation {
+					return sema.ElaborationImport{
+						Elaboration: stdlib.TestContractChecker.Elaboration,
+					}, nil
+				}
+
+				stringLocation, ok := importedLocation.(common.StringLocation)
+				if !ok {
+					return nil, fmt.Errorf(
+						"cannot import `%s`: only the Test contract and files can be imported",
+						importedLocation,
+					)
+				}
+
+				importedChecker, ok := r.checkers[importedLocation.ID()]
+				if !ok {
+					importedCode, err := os.ReadFile(resolvePath(directory, string(stringLocation)))
+					if err != nil {
+						return nil, err
+					}
+
+					importedChecker, err = r.check(string(importedCode), stringLocation, directory)
+					if err != nil {
+						return nil, err
+					}
+				}
+
+				return sema.ElaborationImport{
+					Elaboration: importedChecker.Elaboration,
+				}, nil
+			},
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checker.Check()
+	if err != nil {
+		return nil, err
+	}
+
+	r.checkers[location.ID()] = checker
+
+	return checker, nil
+}
+
+func (r *TestRunner) newInterpreter(checker *sema.Checker, directory string) (*interpreter.Interpreter, error) {
+
+	var uuid uint64
+
+	framework := testFramework{
+		directory: directory,
+	}
+
+	return interpreter.NewInterpreter(
+		interpreter.ProgramFromChecker(checker),
+		checker.Location,
+		interpreter.WithStorage(interpreter.NewInMemoryStorage(nil)),
+		interpreter.WithPredeclaredValues(valueDeclarations.ToInterpreterValueDeclarations()),
+		interpreter.WithUUIDHandler(func() (uint64, error) {
+			uuid++
+			return uuid, nil
+		}),
+		interpreter.WithImportLocationHandler(
+			func(inter *interpreter.Interpreter, location common.Location) interpreter.Import {
+				var program *interpreter.Program
+
+				if location == stdlib.TestContractLocation {
+					program = interpreter.ProgramFromChecker(stdlib.TestContractChecker)
+				} else {
+					importedChecker, ok := r.checkers[location.ID()]
+					if !ok {
+						panic(errors.NewUnreachableError())
+					}
+					program = interpreter.ProgramFromChecker(importedChecker)
+				}
+
+				subInterpreter, err := inter.NewSubInterpreter(program, location)
+				if err != nil {
+					panic(err)
+				}
+
+				return interpreter.InterpreterImport{
+					Interpreter: subInterpreter,
+				}
+			},
+		),
+		interpreter.WithContractValueHandler(
+			func(
+				inter *interpreter.Interpreter,
+				compositeType *sema.CompositeType,
+				constructorGenerator func(common.Address) *interpreter.HostFunctionValue,
+				invocationRange ast.Range,
+			) *interpreter.CompositeValue {
+
+				if compositeType.Location == stdlib.TestContractLocation {
+					contract, err := stdlib.NewTestContract(
+						inter,
+						constructorGenerator(common.Address{}),
+						invocationRange,
+						framework,
+					)
+					if err != nil {
+						panic(err)
+					}
+					return contract
+				}
+
+				// Contracts declared in test programs and imported files
+				// are not deployed to an account, they are only instantiated
+
+				if len(compositeType.ConstructorParameters) > 0 {
+					panic(fmt.Errorf(
+						"cannot instantiate contract `%s` with initializer parameters: deploy it to a blockchain instead",
+						compositeType.QualifiedIdentifier(),
+					))
+				}
+
+				value, err := inter.InvokeFunctionValue(
+					constructorGenerator(common.Address{}),
+					nil,
+					nil,
+					nil,
+					invocationRange,
+				)
+				if err != nil {
+					panic(err)
+				}
+
+				return value.(*interpreter.CompositeValue)
+			},
+		),
+	)
+}
+
+// failureLocation returns the location in the test program where the test failed:
+// The location of the error, if it occurred in the test program,
+// or the location of the innermost invocation in the test program otherwise
+func failureLocation(err error, location common.Location) interpreter.LocationRange {
+	var interpreterErr interpreter.Error
+	if !goErrors.As(err, &interpreterErr) {
+		return interpreter.LocationRange{}
+	}
+
+	errLocation := interpreterErr.Location
+	if locatedErr, ok := interpreterErr.Err.(common.HasImportLocation); ok &&
+		locatedErr.ImportLocation() != nil {
+
+		errLocation = locatedErr.ImportLocation()
+	}
+
+	if positionedErr, ok := interpreterErr.Err.(ast.HasPosition); ok &&
+		common.LocationsMatch(errLocation, location) {
+
+		return interpreter.LocationRange{
+			Location: location,
+			Range:    ast.NewUnmeteredRangeFromPositioned(positionedErr),
+		}
+	}
+
+	stackTrace := interpreterErr.StackTrace
+	for i := len(stackTrace) - 1; i >= 0; i-- {
+		locationRange := stackTrace[i].GetLocationRange()
+		if common.LocationsMatch(locationRange.Location, location) {
+			return locationRange
+		}
+	}
+
+	return interpreter.LocationRange{}
+}
+
+// testFramework provides the host functionality of the Test contract to test programs
+type testFramework struct {
+	directory string
+}
+
+var _ stdlib.TestFramework = testFramework{}
+
+func (f testFramework) NewEmulatorBackend() stdlib.TestBlockchainBackend {
+	return newEmulatorBackend()
+}
+
+func (f testFramework) ReadFile(path string) (string, error) {
+	content, err := os.ReadFile(resolvePath(f.directory, path))
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// resolvePath resolves the given path against the given directory, if it is relative
+func resolvePath(directory string, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(directory, path)
+}
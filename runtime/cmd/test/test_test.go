@@ -0,0 +1,393 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+)
+
+func runTestCode(t *testing.T, code string) map[string]Result {
+	return runTestCodeInDirectory(t, t.TempDir(), code)
+}
+
+func runTestCodeInDirectory(t *testing.T, directory string, code string) map[string]Result {
+	location := common.StringLocation(filepath.Join(directory, "test.cdc"))
+
+	results, err := NewTestRunner().RunCode(code, location)
+	require.NoError(t, err)
+
+	resultsByName := map[string]Result{}
+	for _, result := range results {
+		resultsByName[result.TestName] = result
+	}
+	return resultsByName
+}
+
+func TestRunnerDiscovery(t *testing.T) {
+
+	t.Parallel()
+
+	results, err := NewTestRunner().RunCode(
+		`
+          pub fun testB() {}
+
+          pub fun testA() {}
+
+          pub fun testWithParameter(x: Int) {}
+
+          pub fun helper() {}
+        `,
+		common.StringLocation("test.cdc"),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+	assert.Equal(t, "testB", results[0].TestName)
+	assert.Equal(t, "testA", results[1].TestName)
+	assert.True(t, results[0].Passed())
+	assert.True(t, results[1].Passed())
+}
+
+func TestRunnerAssertions(t *testing.T) {
+
+	t.Parallel()
+
+	results := runTestCode(t, `
+      import Test
+
+      pub fun testAssert() {
+          Test.assert(true)
+          Test.assert(1 < 2, message: "ordered")
+      }
+
+      pub fun testAssertFails() {
+          Test.assert(false, message: "not true")
+      }
+
+      pub fun testFail() {
+          Test.fail(message: "failed")
+      }
+
+      pub fun testAssertEqual() {
+          Test.assertEqual("a", "a")
+          Test.assertEqual([1, 2], [1, 2])
+      }
+
+      pub fun testAssertEqualFails() {
+          Test.assertEqual(1, 2)
+      }
+
+      pub fun testExpect() {
+          Test.expect(1, Test.equal(1))
+          Test.expect(2, Test.equal(1).or(Test.equal(2)))
+          Test.expect(3, Test.Matcher(test: fun (value: AnyStruct): Bool {
+              return (value as! Int) > 2
+          }))
+      }
+
+      pub fun testExpectFails() {
+          Test.expect(1, Test.equal(1).and(Test.equal(2)))
+      }
+    `)
+
+	require.Len(t, results, 7)
+
+	for _, name := range []string{"testAssert", "testAssertEqual", "testExpect"} {
+		assert.NoError(t, results[name].Error, name)
+	}
+
+	assertFailure := func(name string, line int, message string) {
+		result := results[name]
+		require.Error(t, result.Error, name)
+		assert.Contains(t, result.Error.Error(), message, name)
+		assert.Equal(t, line, result.Location.StartPos.Line, name)
+	}
+
+	assertFailure("testAssertFails", 10, "assertion failed: not true")
+	assertFailure("testFail", 14, "assertion failed: failed")
+	assertFailure("testAssertEqualFails", 23, "not equal: expected `1`, got `2`")
+	assertFailure("testExpectFails", 35, "given value does not match")
+}
+
+func TestRunnerFailureLocation(t *testing.T) {
+
+	t.Parallel()
+
+	results := runTestCode(t, `
+      import Test
+
+      pub fun testNested() {
+          helper()
+      }
+
+      pub fun helper() {
+          Test.expect("a", Test.equal("b"))
+      }
+    `)
+
+	result := results["testNested"]
+	require.Error(t, result.Error)
+
+	// The innermost location in the test program is reported
+	assert.Equal(t, 9, result.Location.StartPos.Line)
+	assert.Equal(t, 10, result.Location.StartPos.Column)
+}
+
+func TestRunnerSetupAndIsolation(t *testing.T) {
+
+	t.Parallel()
+
+	results := runTestCode(t, `
+      import Test
+
+      pub var count = 0
+
+      pub fun setup() {
+          count = count + 10
+      }
+
+      pub fun testFirst() {
+          Test.assertEqual(10, count)
+          count = count + 1
+      }
+
+      pub fun testSecond() {
+          Test.assertEqual(10, count)
+          count = count + 1
+      }
+    `)
+
+	require.Len(t, results, 2)
+	assert.NoError(t, results["testFirst"].Error)
+	assert.NoError(t, results["testSecond"].Error)
+}
+
+func TestRunnerInvalidProgram(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := NewTestRunner().RunCode(
+		`
+          import Test
+
+          pub fun testInvalid() {
+              Test.assertEqual(1)
+          }
+        `,
+		common.StringLocation("test.cdc"),
+	)
+	require.Error(t, err)
+
+	var checkerErr *sema.CheckerError
+	require.ErrorAs(t, err, &checkerErr)
+}
+
+func TestRunnerEmulator(t *testing.T) {
+
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	const counterContract = `
+      pub contract Counter {
+
+          pub event Incremented(count: Int)
+
+          pub var count: Int
+
+          init(initial: Int) {
+              self.count = initial
+          }
+
+          pub fun increment() {
+              self.count = self.count + 1
+              emit Incremented(count: self.count)
+          }
+      }
+    `
+
+	err := os.WriteFile(filepath.Join(directory, "counter.cdc"), []byte(counterContract), 0600)
+	require.NoError(t, err)
+
+	results := runTestCodeInDirectory(t, directory, `
+      import Test
+
+      pub let blockchain = Test.newEmulatorBlockchain()
+      pub let account = blockchain.createAccount()
+
+      pub fun setup() {
+          let err = blockchain.deployContract(
+              name: "Counter",
+              code: Test.readFile("counter.cdc"),
+              account: account,
+              arguments: [10]
+          )
+          Test.assert(err == nil)
+      }
+
+      pub fun testScript() {
+          let result = blockchain.executeScript(
+              "import Counter from 0x0000000000000001 \n pub fun main(x: Int): Int { return Counter.count + x }",
+              [5]
+          )
+          Test.assertEqual(Test.ResultStatus.succeeded, result.status)
+          Test.assertEqual(15, result.returnValue!)
+          Test.assert(result.error == nil)
+      }
+
+      pub fun testFailingScript() {
+          let result = blockchain.executeScript("pub fun main() { panic(\"broken\") }", [])
+          Test.assertEqual(Test.ResultStatus.failed, result.status)
+          Test.assert(result.returnValue == nil)
+          Test.assert(result.error!.message != "")
+      }
+
+      pub fun testTransactions() {
+          let increment = Test.Transaction(
+              code: "import Counter from 0x0000000000000001 \n transaction { prepare(signer: AuthAccount) { Counter.increment() } }",
+              authorizers: [account.address],
+              arguments: []
+          )
+          let fail = Test.Transaction(
+              code: "import Counter from 0x0000000000000001 \n transaction { prepare(signer: AuthAccount) { Counter.increment(); panic(\"broken\") } }",
+              authorizers: [account.address],
+              arguments: []
+          )
+
+          let results = blockchain.executeTransactions([increment, fail, increment])
+          Test.assertEqual(Test.ResultStatus.succeeded, results[0].status)
+          Test.assertEqual(Test.ResultStatus.failed, results[1].status)
+          Test.assertEqual(Test.ResultStatus.succeeded, results[2].status)
+
+          // Events of the failed transaction are discarded
+          let events = blockchain.eventsOfType("A.0000000000000001.Counter.Incremented")
+          Test.assertEqual(2, events.length)
+          Test.assertEqual(11, events[0].fields["count"]!)
+          Test.assertEqual(12, events[1].fields["count"]!)
+
+          // The deployment emitted an event, too
+          let allEvents = blockchain.events()
+          Test.assertEqual(3, allEvents.length)
+          Test.assertEqual("flow.AccountContractAdded", allEvents[0].typeID)
+      }
+
+      pub fun testDeploymentFailure() {
+          let err = blockchain.deployContract(
+              name: "Counter",
+              code: Test.readFile("counter.cdc"),
+              account: blockchain.createAccount(),
+              arguments: ["not an integer"]
+          )
+          Test.assert(err != nil)
+      }
+    `)
+
+	require.Len(t, results, 4)
+
+	for name, result := range results { //nolint:maprangecheck
+		assert.NoError(t, result.Error, name)
+	}
+}
+
+func TestRunAndReport(t *testing.T) {
+
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	writeFile := func(name string, code string) {
+		path := filepath.Join(directory, name)
+		err := os.MkdirAll(filepath.Dir(path), 0700)
+		require.NoError(t, err)
+		err = os.WriteFile(path, []byte(code), 0600)
+		require.NoError(t, err)
+	}
+
+	writeFile(
+		"a_test.cdc",
+		`
+          import Test
+
+          pub fun testPass() {}
+        `,
+	)
+	writeFile(
+		"nested/b_test.cdc",
+		`
+          import Test
+
+          pub fun testFail() {
+              Test.fail(message: "broken")
+          }
+        `,
+	)
+	writeFile(
+		"helper.cdc",
+		`
+          pub fun testNotRun() {
+              panic("not a test file")
+          }
+        `,
+	)
+
+	var out, errOut bytes.Buffer
+	passed := RunAndReport([]string{directory}, &out, &errOut)
+	assert.False(t, passed)
+
+	assert.Equal(t,
+		"PASS: "+filepath.Join(directory, "a_test.cdc")+": testPass\n"+
+			"FAIL: "+filepath.Join(directory, "nested/b_test.cdc")+":5:14: testFail\n"+
+			"1 passed, 1 failed\n",
+		out.String(),
+	)
+	assert.Contains(t, errOut.String(), "broken")
+}
+
+func TestTestContractChecker(t *testing.T) {
+
+	t.Parallel()
+
+	// The natively implemented functions are members of the contract type
+
+	variable, ok := stdlib.TestContractChecker.Elaboration.GlobalTypes.Get("Test")
+	require.True(t, ok)
+
+	members := variable.Type.GetMembers()
+	for _, name := range []string{
+		"assert",
+		"fail",
+		"assertEqual",
+		"equal",
+		"expect",
+		"newEmulatorBlockchain",
+		"readFile",
+	} {
+		assert.Contains(t, members, name)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/onflow/cadence/encoding/json/schema"
+	"github.com/onflow/cadence/runtime/cmd"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+var typeScriptFlag = flag.Bool("ts", false, "generate TypeScript declarations instead of a JSON Schema")
+
+// typegen generates a JSON Schema, or TypeScript declarations,
+// for the JSON-Cadence encoding of the composite types (including events)
+// declared in the given programs
+func main() {
+	flag.Parse()
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		cmd.ExitWithError("no input file")
+	}
+
+	generator := schema.NewGenerator()
+
+	codes := map[common.LocationID]string{}
+
+	for _, path := range paths {
+		location := common.NewStringLocation(nil, path)
+
+		program, must := cmd.PrepareProgramFromFile(location, codes)
+
+		checker, must := cmd.PrepareChecker(program, location, codes, nil, must)
+
+		must(checker.Check())
+
+		generator.AddElaboration(checker.Elaboration)
+	}
+
+	if *typeScriptFlag {
+		fmt.Print(generator.TypeScript())
+		return
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(generator.JSONSchema())
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+}
@@ -84,6 +84,8 @@ const (
 	_
 	_
 	_
+	// interpreter storage operations
+	ComputationKindStorageFootprintSlab
 	_
 	_
 	_
@@ -98,8 +100,8 @@ const (
 	_
 	_
 	_
-	_
-	_
+	// interpreter string operations
+	ComputationKindStringScan
 	_
 	_
 	_
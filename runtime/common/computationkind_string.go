@@ -21,6 +21,8 @@ func _() {
 	_ = x[ComputationKindCreateDictionaryValue-1040]
 	_ = x[ComputationKindTransferDictionaryValue-1041]
 	_ = x[ComputationKindDestroyDictionaryValue-1042]
+	_ = x[ComputationKindStorageFootprintSlab-1055]
+	_ = x[ComputationKindStringScan-1070]
 	_ = x[ComputationKindSTDLIBPanic-1100]
 	_ = x[ComputationKindSTDLIBAssert-1101]
 	_ = x[ComputationKindSTDLIBUnsafeRandom-1102]
@@ -34,8 +36,10 @@ const (
 	_ComputationKind_name_2 = "CreateCompositeValueTransferCompositeValueDestroyCompositeValue"
 	_ComputationKind_name_3 = "CreateArrayValueTransferArrayValueDestroyArrayValue"
 	_ComputationKind_name_4 = "CreateDictionaryValueTransferDictionaryValueDestroyDictionaryValue"
-	_ComputationKind_name_5 = "STDLIBPanicSTDLIBAssertSTDLIBUnsafeRandom"
-	_ComputationKind_name_6 = "STDLIBRLPDecodeStringSTDLIBRLPDecodeList"
+	_ComputationKind_name_5 = "StorageFootprintSlab"
+	_ComputationKind_name_6 = "StringScan"
+	_ComputationKind_name_7 = "STDLIBPanicSTDLIBAssertSTDLIBUnsafeRandom"
+	_ComputationKind_name_8 = "STDLIBRLPDecodeStringSTDLIBRLPDecodeList"
 )
 
 var (
@@ -43,8 +47,8 @@ var (
 	_ComputationKind_index_2 = [...]uint8{0, 20, 42, 63}
 	_ComputationKind_index_3 = [...]uint8{0, 16, 34, 51}
 	_ComputationKind_index_4 = [...]uint8{0, 21, 44, 66}
-	_ComputationKind_index_5 = [...]uint8{0, 11, 23, 41}
-	_ComputationKind_index_6 = [...]uint8{0, 21, 40}
+	_ComputationKind_index_7 = [...]uint8{0, 11, 23, 41}
+	_ComputationKind_index_8 = [...]uint8{0, 21, 40}
 )
 
 func (i ComputationKind) String() string {
@@ -63,12 +67,16 @@ func (i ComputationKind) String() string {
 	case 1040 <= i && i <= 1042:
 		i -= 1040
 		return _ComputationKind_name_4[_ComputationKind_index_4[i]:_ComputationKind_index_4[i+1]]
+	case i == 1055:
+		return _ComputationKind_name_5
+	case i == 1070:
+		return _ComputationKind_name_6
 	case 1100 <= i && i <= 1102:
 		i -= 1100
-		return _ComputationKind_name_5[_ComputationKind_index_5[i]:_ComputationKind_index_5[i+1]]
+		return _ComputationKind_name_7[_ComputationKind_index_7[i]:_ComputationKind_index_7[i+1]]
 	case 1108 <= i && i <= 1109:
 		i -= 1108
-		return _ComputationKind_name_6[_ComputationKind_index_6[i]:_ComputationKind_index_6[i+1]]
+		return _ComputationKind_name_8[_ComputationKind_index_8[i]:_ComputationKind_index_8[i+1]]
 	default:
 		return "ComputationKind(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
@@ -233,6 +233,18 @@ const (
 	MemoryKindOrderedMapEntryList
 	MemoryKindOrderedMapEntry
 
+	// JSON-Cadence
+	MemoryKindJSONValue
+
+	// AST nodes
+	MemoryKindTypeAliasDeclaration
+	MemoryKindTypeParameter
+	MemoryKindTypeParameterList
+	MemoryKindStringTemplateExpression
+
+	// Strings
+	MemoryKindStringGraphemeBoundaries
+
 	// Placeholder kind to allow consistent indexing
 	// this should always be the last kind
 	MemoryKindLast
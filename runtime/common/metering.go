@@ -44,16 +44,18 @@ var (
 
 	// AST
 
-	ProgramMemoryUsage         = NewConstantMemoryUsage(MemoryKindProgram)
-	IdentifierMemoryUsage      = NewConstantMemoryUsage(MemoryKindIdentifier)
-	ArgumentMemoryUsage        = NewConstantMemoryUsage(MemoryKindArgument)
-	BlockMemoryUsage           = NewConstantMemoryUsage(MemoryKindBlock)
-	FunctionBlockMemoryUsage   = NewConstantMemoryUsage(MemoryKindFunctionBlock)
-	ParameterMemoryUsage       = NewConstantMemoryUsage(MemoryKindParameter)
-	ParameterListMemoryUsage   = NewConstantMemoryUsage(MemoryKindParameterList)
-	TransferMemoryUsage        = NewConstantMemoryUsage(MemoryKindTransfer)
-	TypeAnnotationMemoryUsage  = NewConstantMemoryUsage(MemoryKindTypeAnnotation)
-	DictionaryEntryMemoryUsage = NewConstantMemoryUsage(MemoryKindDictionaryEntry)
+	ProgramMemoryUsage           = NewConstantMemoryUsage(MemoryKindProgram)
+	IdentifierMemoryUsage        = NewConstantMemoryUsage(MemoryKindIdentifier)
+	ArgumentMemoryUsage          = NewConstantMemoryUsage(MemoryKindArgument)
+	BlockMemoryUsage             = NewConstantMemoryUsage(MemoryKindBlock)
+	FunctionBlockMemoryUsage     = NewConstantMemoryUsage(MemoryKindFunctionBlock)
+	ParameterMemoryUsage         = NewConstantMemoryUsage(MemoryKindParameter)
+	ParameterListMemoryUsage     = NewConstantMemoryUsage(MemoryKindParameterList)
+	TypeParameterMemoryUsage     = NewConstantMemoryUsage(MemoryKindTypeParameter)
+	TypeParameterListMemoryUsage = NewConstantMemoryUsage(MemoryKindTypeParameterList)
+	TransferMemoryUsage          = NewConstantMemoryUsage(MemoryKindTransfer)
+	TypeAnnotationMemoryUsage    = NewConstantMemoryUsage(MemoryKindTypeAnnotation)
+	DictionaryEntryMemoryUsage   = NewConstantMemoryUsage(MemoryKindDictionaryEntry)
 
 	// AST Declarations
 
@@ -67,6 +69,7 @@ var (
 	VariableDeclarationMemoryUsage        = NewConstantMemoryUsage(MemoryKindVariableDeclaration)
 	SpecialFunctionDeclarationMemoryUsage = NewConstantMemoryUsage(MemoryKindSpecialFunctionDeclaration)
 	PragmaDeclarationMemoryUsage          = NewConstantMemoryUsage(MemoryKindPragmaDeclaration)
+	TypeAliasDeclarationMemoryUsage       = NewConstantMemoryUsage(MemoryKindTypeAliasDeclaration)
 
 	// AST Statements
 
@@ -408,6 +411,16 @@ func NewRawStringMemoryUsage(length int) MemoryUsage {
 	}
 }
 
+// NewJSONValueMemoryUsage returns the memory usage of a value of the
+// intermediate JSON representation, e.g. when decoding JSON-Cadence.
+// The length is the length of string values, and zero otherwise.
+func NewJSONValueMemoryUsage(length int) MemoryUsage {
+	return MemoryUsage{
+		Kind:   MemoryKindJSONValue,
+		Amount: uint64(length) + 1, // +1 to account for empty strings, and non-string values
+	}
+}
+
 func NewCadenceStringMemoryUsage(length int) MemoryUsage {
 	return MemoryUsage{
 		Kind:   MemoryKindCadenceStringValue,
@@ -763,6 +776,22 @@ func NewArrayExpressionMemoryUsage(length int) MemoryUsage {
 	}
 }
 
+func NewStringTemplateExpressionMemoryUsage(expressionCount int) MemoryUsage {
+	return MemoryUsage{
+		Kind: MemoryKindStringTemplateExpression,
+		// +1 to account for the literal parts, of which there is one more than expressions
+		Amount: uint64(expressionCount) + 1,
+	}
+}
+
+func NewStringGraphemeBoundariesMemoryUsage(length int) MemoryUsage {
+	return MemoryUsage{
+		Kind: MemoryKindStringGraphemeBoundaries,
+		// +1 to account for the boundary at the end of the string
+		Amount: uint64(length) + 1,
+	}
+}
+
 func NewDictionaryExpressionMemoryUsage(length int) MemoryUsage {
 	return MemoryUsage{
 		Kind: MemoryKindDictionaryExpression,
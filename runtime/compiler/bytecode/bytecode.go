@@ -0,0 +1,94 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package bytecode implements a compact bytecode format for compiled Cadence programs,
+// which can be executed by a stack-based virtual machine,
+// as an alternative to the tree-walking interpreter.
+//
+// A program (Program) consists of functions (Function) and a pool of constants (Constant).
+// The code of a function is a sequence of instructions.
+// Each instruction is an opcode (Opcode), followed by the operands of the opcode.
+// All operands are unsigned 16-bit integers, encoded in big-endian byte order.
+//
+// Instructions operate on a stack of values.
+// For example, the instruction `Add` pops two values and pushes their sum.
+//
+// The values of the program are the values of the interpreter,
+// so compiled programs and interpreted programs can be used interchangeably.
+//
+package bytecode
+
+// Program is a compiled program
+//
+type Program struct {
+	// Functions are the functions of the program.
+	// The index of a function is used as the operand of Call instructions
+	Functions []*Function
+	// Constants are the constants of the program.
+	// The index of a constant is used as the operand of instructions,
+	// e.g. of String instructions
+	Constants []*Constant
+	// StartFunctionIndex is the index of the function which initializes the program,
+	// e.g. its global variables, if any.
+	// The function is called once, before any other function is invoked
+	StartFunctionIndex *uint16
+}
+
+// Function is a compiled function
+//
+type Function struct {
+	Name string
+	// ParameterCount is the number of parameters.
+	// The arguments of an invocation are the first locals
+	ParameterCount uint16
+	// LocalCount is the number of locals, including the parameters
+	LocalCount uint16
+	// HasResult is true if the function returns a value.
+	// Functions which return Void have no result
+	HasResult bool
+	Code      []byte
+}
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=ConstantKind -trimprefix=ConstantKind
+
+// ConstantKind is the kind of constant, which determines how the data of the constant is encoded
+//
+type ConstantKind uint8
+
+const (
+	ConstantKindUnknown ConstantKind = iota
+	// ConstantKindInt is an integer: a sign byte (0 for negative, 1 for positive),
+	// followed by the big-endian bytes of the magnitude
+	ConstantKindInt
+	// ConstantKindString is a string, encoded in UTF-8
+	ConstantKindString
+	// ConstantKindCharacter is a character, encoded in UTF-8
+	ConstantKindCharacter
+	// ConstantKindAddress is an address
+	ConstantKindAddress
+	// ConstantKindType is a static type, encoded in CBOR
+	ConstantKindType
+)
+
+// Constant is a constant of a program,
+// e.g. a string literal, or the type of a created array
+//
+type Constant struct {
+	Kind ConstantKind
+	Data []byte
+}
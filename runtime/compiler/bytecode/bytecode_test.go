@@ -0,0 +1,112 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bytecode
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendInstruction(t *testing.T) {
+
+	t.Parallel()
+
+	var code []byte
+	code = AppendInstruction(code, OpcodeGetLocal, 1)
+	code = AppendInstruction(code, OpcodeCallMember, 2, 0x1234, 4)
+	code = AppendInstruction(code, OpcodeReturnValue)
+
+	require.Equal(t,
+		[]byte{
+			byte(OpcodeGetLocal), 0, 1,
+			byte(OpcodeCallMember), 0, 2, 0x12, 0x34, 0, 4,
+			byte(OpcodeReturnValue),
+		},
+		code,
+	)
+
+	assert.Equal(t, uint16(1), Operand(code, 1))
+	assert.Equal(t, uint16(0x1234), Operand(code, 6))
+}
+
+func TestDisassemble(t *testing.T) {
+
+	t.Parallel()
+
+	var code []byte
+	code = AppendInstruction(code, OpcodeInt, 0)
+	code = AppendInstruction(code, OpcodeString, 1)
+	code = AppendInstruction(code, OpcodeDrop)
+	code = AppendInstruction(code, OpcodeReturnValue)
+	code = AppendInstruction(code, OpcodeGetLocal)
+
+	program := &Program{
+		Constants: []*Constant{
+			{
+				Kind: ConstantKindInt,
+				Data: []byte{0, 42},
+			},
+			{
+				Kind: ConstantKindString,
+				Data: []byte("hello"),
+			},
+		},
+		Functions: []*Function{
+			{
+				Name:       "test",
+				LocalCount: 1,
+				HasResult:  true,
+				Code:       code,
+			},
+		},
+	}
+
+	assert.Equal(t,
+		`constants:
+  0: Int -42
+  1: String "hello"
+function 0 test (parameters: 0, locals: 1, result: true):
+  0000: Int 0
+  0003: String 1
+  0006: Drop
+  0007: ReturnValue
+  0008: GetLocal <truncated>
+`,
+		Disassemble(program),
+	)
+}
+
+func TestDecodeInteger(t *testing.T) {
+
+	t.Parallel()
+
+	value, err := DecodeInteger([]byte{1, 1, 0})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(256), value)
+
+	value, err = DecodeInteger([]byte{0, 1})
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(-1), value)
+
+	_, err = DecodeInteger(nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,127 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bytecode
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// Disassemble returns a human-readable representation of the given program,
+// its constants and the instructions of its functions
+//
+func Disassemble(program *Program) string {
+	var builder strings.Builder
+
+	if len(program.Constants) > 0 {
+		builder.WriteString("constants:\n")
+		for i, constant := range program.Constants {
+			_, _ = fmt.Fprintf(&builder, "  %d: %s %s\n", i, constant.Kind, constant)
+		}
+	}
+
+	if program.StartFunctionIndex != nil {
+		_, _ = fmt.Fprintf(&builder, "start: function %d\n", *program.StartFunctionIndex)
+	}
+
+	for i, function := range program.Functions {
+		_, _ = fmt.Fprintf(
+			&builder,
+			"function %d %s (parameters: %d, locals: %d, result: %t):\n",
+			i,
+			function.Name,
+			function.ParameterCount,
+			function.LocalCount,
+			function.HasResult,
+		)
+
+		code := function.Code
+		offset := 0
+		for offset < len(code) {
+			opcode := Opcode(code[offset])
+			_, _ = fmt.Fprintf(&builder, "  %04d: %s", offset, opcode)
+			offset++
+
+			for i := 0; i < opcode.OperandCount(); i++ {
+				if offset+OperandSize > len(code) {
+					builder.WriteString(" <truncated>")
+					break
+				}
+				_, _ = fmt.Fprintf(&builder, " %d", Operand(code, offset))
+				offset += OperandSize
+			}
+
+			builder.WriteByte('\n')
+		}
+	}
+
+	return builder.String()
+}
+
+// String returns a human-readable representation of the data of the constant
+//
+func (c *Constant) String() string {
+	switch c.Kind {
+	case ConstantKindInt:
+		value, err := DecodeInteger(c.Data)
+		if err != nil {
+			break
+		}
+		return value.String()
+
+	case ConstantKindString, ConstantKindCharacter:
+		return strconv.Quote(string(c.Data))
+
+	case ConstantKindAddress:
+		address, err := common.BytesToAddress(c.Data)
+		if err != nil {
+			break
+		}
+		return address.ShortHexWithPrefix()
+
+	case ConstantKindType:
+		decoder := interpreter.CBORDecMode.NewByteStreamDecoder(c.Data)
+		staticType, err := interpreter.NewTypeDecoder(decoder, nil).DecodeStaticType()
+		if err != nil {
+			break
+		}
+		return staticType.String()
+	}
+
+	return fmt.Sprintf("%x", c.Data)
+}
+
+// DecodeInteger decodes the data of an Int constant
+//
+func DecodeInteger(data []byte) (*big.Int, error) {
+	if len(data) < 1 {
+		return nil, fmt.Errorf("invalid integer encoding")
+	}
+
+	value := new(big.Int).SetBytes(data[1:])
+	if data[0] == 0 {
+		value.Neg(value)
+	}
+	return value, nil
+}
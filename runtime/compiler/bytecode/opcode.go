@@ -0,0 +1,254 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bytecode
+
+import (
+	"encoding/binary"
+)
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=Opcode -trimprefix=Opcode
+
+// Opcode is the operation code of an instruction
+//
+type Opcode byte
+
+const (
+	OpcodeUnknown Opcode = iota
+
+	// Control flow
+
+	// OpcodeUnreachable aborts the execution
+	OpcodeUnreachable
+	// OpcodeReturn returns from the function without a value
+	OpcodeReturn
+	// OpcodeReturnValue pops a value and returns it from the function
+	OpcodeReturnValue
+	// OpcodeJump continues execution at the code offset given by the operand
+	OpcodeJump
+	// OpcodeJumpIfTrue pops a boolean and jumps to the code offset given by the operand,
+	// if the boolean is true
+	OpcodeJumpIfTrue
+	// OpcodeJumpIfFalse pops a boolean and jumps to the code offset given by the operand,
+	// if the boolean is false
+	OpcodeJumpIfFalse
+
+	// Stack and locals
+
+	// OpcodeDrop pops a value and discards it
+	OpcodeDrop
+	// OpcodeDup pushes the value on top of the stack again
+	OpcodeDup
+	// OpcodeGetLocal pushes the value of the local given by the operand
+	OpcodeGetLocal
+	// OpcodeSetLocal pops a value and stores it in the local given by the operand
+	OpcodeSetLocal
+
+	// Globals
+
+	// OpcodeGetGlobal pushes the value of the global variable
+	// with the name given by the constant operand
+	OpcodeGetGlobal
+	// OpcodeSetGlobal pops a value and stores it in the global variable
+	// with the name given by the constant operand
+	OpcodeSetGlobal
+
+	// Constants
+
+	// OpcodeInt pushes the Int given by the constant operand
+	OpcodeInt
+	// OpcodeString pushes the String given by the constant operand
+	OpcodeString
+	// OpcodeCharacter pushes the Character given by the constant operand
+	OpcodeCharacter
+	// OpcodeNumber pushes a number of the type given by the first constant operand,
+	// with the integer value given by the second constant operand.
+	// For fixed-point types, the value is the scaled integer
+	OpcodeNumber
+	// OpcodeAddress pushes the Address given by the constant operand
+	OpcodeAddress
+	// OpcodePath pushes the path with the domain given by the first operand
+	// and the identifier given by the second, constant operand
+	OpcodePath
+	OpcodeTrue
+	OpcodeFalse
+	OpcodeNil
+	OpcodeVoid
+
+	// Binary operations pop two values and push the result
+
+	OpcodeAdd
+	OpcodeSubtract
+	OpcodeMultiply
+	OpcodeDivide
+	OpcodeMod
+	OpcodeEqual
+	OpcodeNotEqual
+	OpcodeLess
+	OpcodeLessEqual
+	OpcodeGreater
+	OpcodeGreaterEqual
+	OpcodeBitwiseOr
+	OpcodeBitwiseXor
+	OpcodeBitwiseAnd
+	OpcodeBitwiseLeftShift
+	OpcodeBitwiseRightShift
+
+	// Unary operations pop a value and push the result
+
+	OpcodeNegate
+	OpcodeNot
+	OpcodeForce
+	OpcodeIsNil
+	OpcodeSome
+	OpcodeTransfer
+
+	// Invocations
+
+	// OpcodeCall pops the arguments and calls the function given by the operand.
+	// The result is pushed, if the function has a result
+	OpcodeCall
+	// OpcodeCallMember pops the arguments, the number of which is given by the third operand,
+	// and the target, and invokes the member function of the target
+	// with the name given by the first, constant operand.
+	// The second operand is 1 if the invocation is optional chaining
+	OpcodeCallMember
+	// OpcodeCallBuiltin pops the arguments, the number of which is given by the second operand,
+	// and invokes the built-in function with the name given by the first, constant operand
+	OpcodeCallBuiltin
+
+	// Members and indexing
+
+	// OpcodeGetMember pops the target and pushes the member with the name given by the first,
+	// constant operand. The second operand is 1 if the access is optional chaining
+	OpcodeGetMember
+	// OpcodeSetMember pops the value and the target,
+	// and sets the member with the name given by the constant operand
+	OpcodeSetMember
+	// OpcodeGetIndex pops the index and the target, and pushes the element
+	OpcodeGetIndex
+	// OpcodeSetIndex pops the value, the index, and the target, and sets the element
+	OpcodeSetIndex
+
+	// Creation and destruction
+
+	// OpcodeNewArray pops the elements, the number of which is given by the second operand,
+	// and pushes a new array of the type given by the first, constant operand
+	OpcodeNewArray
+	// OpcodeNewDictionary pops the alternating keys and values of the entries,
+	// the number of which is given by the second operand,
+	// and pushes a new dictionary of the type given by the first, constant operand
+	OpcodeNewDictionary
+	// OpcodeNewComposite pushes a new composite value without fields,
+	// of the type given by the constant operand
+	OpcodeNewComposite
+	// OpcodeStringTemplate pops the alternating literal parts and embedded values of a string template,
+	// the number of embedded values of which is given by the operand,
+	// and pushes the resulting string
+	OpcodeStringTemplate
+	// OpcodeCast pops a value and casts it to the type given by the first, constant operand.
+	// The second operand is 1 if the cast is a force cast
+	OpcodeCast
+	// OpcodeIsComposite pops a value and pushes true if it is a composite value,
+	// or a reference to a composite value, of the composite type given by the constant operand
+	OpcodeIsComposite
+	// OpcodeBox pops a value and boxes it into optionals,
+	// until it has the optional type given by the constant operand
+	OpcodeBox
+	// OpcodeReference pops a value and pushes a reference to it,
+	// of the reference type given by the constant operand
+	OpcodeReference
+	OpcodeDestroy
+
+	// Statements
+
+	// OpcodeEmit pops an event and emits it
+	OpcodeEmit
+	// OpcodeFailCondition pops the message and aborts the execution,
+	// because the condition of the kind given by the operand failed
+	OpcodeFailCondition
+)
+
+// OperandCount returns the number of operands of the opcode
+//
+func (o Opcode) OperandCount() int {
+	switch o {
+	case OpcodeJump,
+		OpcodeJumpIfTrue,
+		OpcodeJumpIfFalse,
+		OpcodeGetLocal,
+		OpcodeSetLocal,
+		OpcodeGetGlobal,
+		OpcodeSetGlobal,
+		OpcodeInt,
+		OpcodeString,
+		OpcodeCharacter,
+		OpcodeAddress,
+		OpcodeCall,
+		OpcodeSetMember,
+		OpcodeNewComposite,
+		OpcodeStringTemplate,
+		OpcodeIsComposite,
+		OpcodeBox,
+		OpcodeReference,
+		OpcodeFailCondition:
+
+		return 1
+
+	case OpcodeNumber,
+		OpcodePath,
+		OpcodeCallBuiltin,
+		OpcodeGetMember,
+		OpcodeNewArray,
+		OpcodeNewDictionary,
+		OpcodeCast:
+
+		return 2
+
+	case OpcodeCallMember:
+		return 3
+
+	default:
+		return 0
+	}
+}
+
+// OperandSize is the size of an operand, in bytes
+//
+const OperandSize = 2
+
+// MaxOperand is the largest value of an operand
+//
+const MaxOperand = 1<<(OperandSize*8) - 1
+
+// AppendInstruction appends the encoding of the instruction
+// with the given opcode and operands to the given code
+//
+func AppendInstruction(code []byte, opcode Opcode, operands ...uint16) []byte {
+	code = append(code, byte(opcode))
+	for _, operand := range operands {
+		code = append(code, byte(operand>>8), byte(operand))
+	}
+	return code
+}
+
+// Operand returns the operand encoded at the given offset of the code
+//
+func Operand(code []byte, offset int) uint16 {
+	return binary.BigEndian.Uint16(code[offset:])
+}
@@ -0,0 +1,650 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/compiler/bytecode"
+	"github.com/onflow/cadence/runtime/compiler/ir"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+type bytecodeCodeGen struct {
+	program   *bytecode.Program
+	funcs     []*ir.Func
+	function  *bytecode.Function
+	constants map[bytecodeConstantKey]uint16
+	// labels are the targets of branches, the innermost label last
+	labels []*bytecodeLabel
+}
+
+type bytecodeConstantKey struct {
+	kind bytecode.ConstantKind
+	data string
+}
+
+// bytecodeLabel is the target of branches.
+// Branches to a loop continue at the start of the loop,
+// branches to other blocks continue at the end of the block
+type bytecodeLabel struct {
+	loop  bool
+	start int
+	// jumps are the offsets of the operands of the jumps to the end of the block,
+	// which are patched when the end of the block is known
+	jumps []int
+}
+
+func (codeGen *bytecodeCodeGen) VisitInt(i ir.Int) ir.Repr {
+	codeGen.emit(
+		bytecode.OpcodeInt,
+		codeGen.addConstant(bytecode.ConstantKindInt, i.Value),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitString(s ir.String) ir.Repr {
+	codeGen.emit(
+		bytecode.OpcodeString,
+		codeGen.addConstant(bytecode.ConstantKindString, []byte(s.Value)),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitCharacter(c ir.Character) ir.Repr {
+	codeGen.emit(
+		bytecode.OpcodeCharacter,
+		codeGen.addConstant(bytecode.ConstantKindCharacter, []byte(c.Value)),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitBool(b ir.Bool) ir.Repr {
+	if b.Value {
+		codeGen.emit(bytecode.OpcodeTrue)
+	} else {
+		codeGen.emit(bytecode.OpcodeFalse)
+	}
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitNil(_ ir.Nil) ir.Repr {
+	codeGen.emit(bytecode.OpcodeNil)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitNumber(n ir.Number) ir.Repr {
+	codeGen.emit(
+		bytecode.OpcodeNumber,
+		codeGen.addTypeConstant(n.Type),
+		codeGen.addConstant(bytecode.ConstantKindInt, n.Value),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitAddress(a ir.Address) ir.Repr {
+	codeGen.emit(
+		bytecode.OpcodeAddress,
+		codeGen.addConstant(bytecode.ConstantKindAddress, a.Value[:]),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitPath(p ir.Path) ir.Repr {
+	codeGen.emit(
+		bytecode.OpcodePath,
+		uint16(p.Domain),
+		codeGen.addConstant(bytecode.ConstantKindString, []byte(p.Identifier)),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitSequence(sequence *ir.Sequence) ir.Repr {
+	for _, stmt := range sequence.Stmts {
+		codeGen.emitStmt(stmt)
+	}
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitBlock(block *ir.Block) ir.Repr {
+	codeGen.pushLabel(false)
+	for _, stmt := range block.Stmts {
+		codeGen.emitStmt(stmt)
+	}
+	codeGen.popLabel()
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitLoop(loop *ir.Loop) ir.Repr {
+	codeGen.pushLabel(true)
+	for _, stmt := range loop.Stmts {
+		codeGen.emitStmt(stmt)
+	}
+	codeGen.popLabel()
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitIf(stmt *ir.If) ir.Repr {
+	codeGen.emitConditional(
+		stmt.Test,
+		func() {
+			codeGen.emitStmt(stmt.Then)
+		},
+		func() {
+			codeGen.emitStmt(stmt.Else)
+		},
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitBranch(branch *ir.Branch) ir.Repr {
+	codeGen.emitBranch(bytecode.OpcodeJump, branch.Index)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitBranchIf(branchIf *ir.BranchIf) ir.Repr {
+	branchIf.Exp.Accept(codeGen)
+	codeGen.emitBranch(bytecode.OpcodeJumpIfTrue, branchIf.Index)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitStoreLocal(storeLocal *ir.StoreLocal) ir.Repr {
+	storeLocal.Exp.Accept(codeGen)
+	codeGen.emit(bytecode.OpcodeSetLocal, codeGen.operand(int(storeLocal.LocalIndex)))
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitSetGlobal(setGlobal *ir.SetGlobal) ir.Repr {
+	setGlobal.Exp.Accept(codeGen)
+	codeGen.emit(
+		bytecode.OpcodeSetGlobal,
+		codeGen.addConstant(bytecode.ConstantKindString, []byte(setGlobal.Name)),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitDrop(drop *ir.Drop) ir.Repr {
+	drop.Exp.Accept(codeGen)
+	codeGen.emit(bytecode.OpcodeDrop)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitReturn(r *ir.Return) ir.Repr {
+	if r.Exp != nil {
+		r.Exp.Accept(codeGen)
+		codeGen.emit(bytecode.OpcodeReturnValue)
+	} else {
+		codeGen.emit(bytecode.OpcodeReturn)
+	}
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitSetMember(setMember *ir.SetMember) ir.Repr {
+	setMember.Target.Accept(codeGen)
+	setMember.Exp.Accept(codeGen)
+	codeGen.emit(
+		bytecode.OpcodeSetMember,
+		codeGen.addConstant(bytecode.ConstantKindString, []byte(setMember.Name)),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitSetIndex(setIndex *ir.SetIndex) ir.Repr {
+	setIndex.Target.Accept(codeGen)
+	setIndex.Index.Accept(codeGen)
+	setIndex.Exp.Accept(codeGen)
+	codeGen.emit(bytecode.OpcodeSetIndex)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitEmit(emit *ir.Emit) ir.Repr {
+	emit.Exp.Accept(codeGen)
+	codeGen.emit(bytecode.OpcodeEmit)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitFailCondition(failCondition *ir.FailCondition) ir.Repr {
+	failCondition.Message.Accept(codeGen)
+	codeGen.emit(bytecode.OpcodeFailCondition, uint16(failCondition.Kind))
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitConst(c *ir.Const) ir.Repr {
+	c.Constant.Accept(codeGen)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitCopyLocal(c *ir.CopyLocal) ir.Repr {
+	// NOTE: values are copied when they are transferred
+	codeGen.emit(bytecode.OpcodeGetLocal, codeGen.operand(int(c.LocalIndex)))
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitMoveLocal(m *ir.MoveLocal) ir.Repr {
+	// TODO: invalidate the local
+	codeGen.emit(bytecode.OpcodeGetLocal, codeGen.operand(int(m.LocalIndex)))
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitGetGlobal(g *ir.GetGlobal) ir.Repr {
+	codeGen.emit(
+		bytecode.OpcodeGetGlobal,
+		codeGen.addConstant(bytecode.ConstantKindString, []byte(g.Name)),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitTeeLocal(t *ir.TeeLocal) ir.Repr {
+	t.Exp.Accept(codeGen)
+	codeGen.emit(bytecode.OpcodeDup)
+	codeGen.emit(bytecode.OpcodeSetLocal, codeGen.operand(int(t.LocalIndex)))
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitUnOpExpr(expr *ir.UnOpExpr) ir.Repr {
+	expr.Expr.Accept(codeGen)
+	opcode, ok := unOpOpcodes[expr.Op]
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	codeGen.emit(opcode)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitBinOpExpr(expr *ir.BinOpExpr) ir.Repr {
+	expr.Left.Accept(codeGen)
+	expr.Right.Accept(codeGen)
+	opcode, ok := binOpOpcodes[expr.Op]
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	codeGen.emit(opcode)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitCall(call *ir.Call) ir.Repr {
+	for _, argument := range call.Arguments {
+		argument.Accept(codeGen)
+	}
+	codeGen.emit(bytecode.OpcodeCall, codeGen.operand(int(call.FunctionIndex)))
+
+	// Every expression results in a value.
+	// Functions which return Void have no result,
+	// so produce the Void value
+
+	if len(codeGen.funcs[call.FunctionIndex].Type.Results) == 0 {
+		codeGen.emit(bytecode.OpcodeVoid)
+	}
+
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitConditional(conditional *ir.Conditional) ir.Repr {
+	codeGen.emitConditional(
+		conditional.Test,
+		func() {
+			conditional.Then.Accept(codeGen)
+		},
+		func() {
+			conditional.Else.Accept(codeGen)
+		},
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitCallMember(call *ir.CallMember) ir.Repr {
+	call.Target.Accept(codeGen)
+	for _, argument := range call.Arguments {
+		argument.Accept(codeGen)
+	}
+	codeGen.emit(
+		bytecode.OpcodeCallMember,
+		codeGen.addConstant(bytecode.ConstantKindString, []byte(call.Name)),
+		boolOperand(call.Optional),
+		codeGen.operand(len(call.Arguments)),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitCallBuiltin(call *ir.CallBuiltin) ir.Repr {
+	for _, argument := range call.Arguments {
+		argument.Accept(codeGen)
+	}
+	codeGen.emit(
+		bytecode.OpcodeCallBuiltin,
+		codeGen.addConstant(bytecode.ConstantKindString, []byte(call.Name)),
+		codeGen.operand(len(call.Arguments)),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitGetMember(getMember *ir.GetMember) ir.Repr {
+	getMember.Target.Accept(codeGen)
+	codeGen.emit(
+		bytecode.OpcodeGetMember,
+		codeGen.addConstant(bytecode.ConstantKindString, []byte(getMember.Name)),
+		boolOperand(getMember.Optional),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitGetIndex(getIndex *ir.GetIndex) ir.Repr {
+	getIndex.Target.Accept(codeGen)
+	getIndex.Index.Accept(codeGen)
+	codeGen.emit(bytecode.OpcodeGetIndex)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitNewArray(newArray *ir.NewArray) ir.Repr {
+	for _, element := range newArray.Elements {
+		element.Accept(codeGen)
+	}
+	codeGen.emit(
+		bytecode.OpcodeNewArray,
+		codeGen.addTypeConstant(newArray.Type),
+		codeGen.operand(len(newArray.Elements)),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitNewDictionary(newDictionary *ir.NewDictionary) ir.Repr {
+	for _, entry := range newDictionary.Entries {
+		entry.Key.Accept(codeGen)
+		entry.Value.Accept(codeGen)
+	}
+	codeGen.emit(
+		bytecode.OpcodeNewDictionary,
+		codeGen.addTypeConstant(newDictionary.Type),
+		codeGen.operand(len(newDictionary.Entries)),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitNewComposite(newComposite *ir.NewComposite) ir.Repr {
+	codeGen.emit(
+		bytecode.OpcodeNewComposite,
+		codeGen.addTypeConstant(newComposite.Type),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitCast(cast *ir.Cast) ir.Repr {
+	cast.Exp.Accept(codeGen)
+	codeGen.emit(
+		bytecode.OpcodeCast,
+		codeGen.addTypeConstant(cast.Type),
+		boolOperand(cast.Force),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitBox(box *ir.Box) ir.Repr {
+	box.Exp.Accept(codeGen)
+	codeGen.emit(
+		bytecode.OpcodeBox,
+		codeGen.addTypeConstant(box.Type),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitReference(reference *ir.Reference) ir.Repr {
+	reference.Exp.Accept(codeGen)
+	codeGen.emit(
+		bytecode.OpcodeReference,
+		codeGen.addTypeConstant(reference.Type),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitDestroy(destroy *ir.Destroy) ir.Repr {
+	destroy.Exp.Accept(codeGen)
+	codeGen.emit(bytecode.OpcodeDestroy)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitIsComposite(isComposite *ir.IsComposite) ir.Repr {
+	isComposite.Exp.Accept(codeGen)
+	codeGen.emit(
+		bytecode.OpcodeIsComposite,
+		codeGen.addTypeConstant(isComposite.Type),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitStringTemplate(template *ir.StringTemplate) ir.Repr {
+	for i, value := range template.Values {
+		codeGen.VisitString(ir.String{Value: value})
+		if i < len(template.Exps) {
+			template.Exps[i].Accept(codeGen)
+		}
+	}
+	codeGen.emit(
+		bytecode.OpcodeStringTemplate,
+		codeGen.operand(len(template.Exps)),
+	)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) VisitFunc(f *ir.Func) ir.Repr {
+	parameterCount := len(f.Type.Params)
+
+	codeGen.function = &bytecode.Function{
+		Name:           f.Name,
+		ParameterCount: codeGen.operand(parameterCount),
+		LocalCount:     codeGen.operand(parameterCount + len(f.Locals)),
+		HasResult:      len(f.Type.Results) > 0,
+	}
+
+	f.Statement.Accept(codeGen)
+
+	// The function body might not end with a return instruction,
+	// e.g. if all branches of an if-statement return
+	if codeGen.function.HasResult {
+		codeGen.emit(bytecode.OpcodeUnreachable)
+	} else {
+		codeGen.emit(bytecode.OpcodeReturn)
+	}
+
+	if f.Start {
+		functionIndex := codeGen.operand(len(codeGen.program.Functions))
+		codeGen.program.StartFunctionIndex = &functionIndex
+	}
+
+	codeGen.program.Functions = append(codeGen.program.Functions, codeGen.function)
+	return nil
+}
+
+func (codeGen *bytecodeCodeGen) emit(opcode bytecode.Opcode, operands ...uint16) {
+	codeGen.function.Code = bytecode.AppendInstruction(codeGen.function.Code, opcode, operands...)
+}
+
+// emitStmt emits the given statement, which might be nil,
+// e.g. the missing else-branch of an if-statement
+func (codeGen *bytecodeCodeGen) emitStmt(stmt ir.Stmt) {
+	if stmt == nil {
+		return
+	}
+	stmt.Accept(codeGen)
+}
+
+// emitConditional emits a conditional, an if-statement or a conditional expression.
+// Like in WebAssembly, the branches of the conditional are nested in a block
+func (codeGen *bytecodeCodeGen) emitConditional(test ir.Expr, emitThen func(), emitElse func()) {
+	test.Accept(codeGen)
+	elseJump := codeGen.emitJump(bytecode.OpcodeJumpIfFalse)
+
+	label := codeGen.pushLabel(false)
+
+	emitThen()
+	label.jumps = append(label.jumps, codeGen.emitJump(bytecode.OpcodeJump))
+
+	codeGen.patchJump(elseJump)
+	emitElse()
+
+	codeGen.popLabel()
+}
+
+// emitJump emits a jump with an unknown target,
+// and returns the offset of the operand, which must be patched
+func (codeGen *bytecodeCodeGen) emitJump(opcode bytecode.Opcode) int {
+	codeGen.emit(opcode, 0)
+	return len(codeGen.function.Code) - bytecode.OperandSize
+}
+
+// patchJump sets the target of the jump with the given operand offset
+// to the current offset
+func (codeGen *bytecodeCodeGen) patchJump(operandOffset int) {
+	target := codeGen.operand(len(codeGen.function.Code))
+	code := codeGen.function.Code
+	code[operandOffset] = byte(target >> 8)
+	code[operandOffset+1] = byte(target)
+}
+
+// emitBranch emits a jump to the label with the given depth
+func (codeGen *bytecodeCodeGen) emitBranch(opcode bytecode.Opcode, depth uint32) {
+	if int(depth) >= len(codeGen.labels) {
+		panic(errors.NewUnreachableError())
+	}
+
+	label := codeGen.labels[len(codeGen.labels)-1-int(depth)]
+
+	if label.loop {
+		codeGen.emit(opcode, codeGen.operand(label.start))
+	} else {
+		label.jumps = append(label.jumps, codeGen.emitJump(opcode))
+	}
+}
+
+func (codeGen *bytecodeCodeGen) pushLabel(loop bool) *bytecodeLabel {
+	label := &bytecodeLabel{
+		loop:  loop,
+		start: len(codeGen.function.Code),
+	}
+	codeGen.labels = append(codeGen.labels, label)
+	return label
+}
+
+// popLabel ends the innermost block,
+// and patches the jumps to the end of the block
+func (codeGen *bytecodeCodeGen) popLabel() {
+	lastIndex := len(codeGen.labels) - 1
+	label := codeGen.labels[lastIndex]
+	codeGen.labels = codeGen.labels[:lastIndex]
+
+	for _, jump := range label.jumps {
+		codeGen.patchJump(jump)
+	}
+}
+
+func (codeGen *bytecodeCodeGen) addConstant(kind bytecode.ConstantKind, data []byte) uint16 {
+	key := bytecodeConstantKey{
+		kind: kind,
+		data: string(data),
+	}
+	if index, ok := codeGen.constants[key]; ok {
+		return index
+	}
+
+	index := codeGen.operand(len(codeGen.program.Constants))
+	codeGen.program.Constants = append(
+		codeGen.program.Constants,
+		&bytecode.Constant{
+			Kind: kind,
+			Data: data,
+		},
+	)
+
+	codeGen.constants[key] = index
+	return index
+}
+
+// addTypeConstant adds a constant for the encoded static type
+func (codeGen *bytecodeCodeGen) addTypeConstant(ty sema.Type) uint16 {
+	staticType := interpreter.ConvertSemaToStaticType(nil, ty)
+	encoded, err := interpreter.StaticTypeToBytes(staticType)
+	if err != nil {
+		panic(fmt.Errorf("failed to encode type %s: %w", ty, err))
+	}
+	return codeGen.addConstant(bytecode.ConstantKindType, encoded)
+}
+
+// operand returns the given value as an operand.
+// Programs which exceed the limits of the bytecode format,
+// e.g. functions with too many locals, cannot be compiled
+func (codeGen *bytecodeCodeGen) operand(value int) uint16 {
+	if value < 0 || value > bytecode.MaxOperand {
+		panic(fmt.Errorf("failed to generate bytecode: operand %d exceeds limit", value))
+	}
+	return uint16(value)
+}
+
+func boolOperand(value bool) uint16 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// binOpOpcodes are the opcodes of binary operations
+var binOpOpcodes = map[ir.BinOp]bytecode.Opcode{
+	ir.BinOpPlus:              bytecode.OpcodeAdd,
+	ir.BinOpMinus:             bytecode.OpcodeSubtract,
+	ir.BinOpMul:               bytecode.OpcodeMultiply,
+	ir.BinOpDiv:               bytecode.OpcodeDivide,
+	ir.BinOpMod:               bytecode.OpcodeMod,
+	ir.BinOpEqual:             bytecode.OpcodeEqual,
+	ir.BinOpNotEqual:          bytecode.OpcodeNotEqual,
+	ir.BinOpLess:              bytecode.OpcodeLess,
+	ir.BinOpLessEqual:         bytecode.OpcodeLessEqual,
+	ir.BinOpGreater:           bytecode.OpcodeGreater,
+	ir.BinOpGreaterEqual:      bytecode.OpcodeGreaterEqual,
+	ir.BinOpBitwiseOr:         bytecode.OpcodeBitwiseOr,
+	ir.BinOpBitwiseXor:        bytecode.OpcodeBitwiseXor,
+	ir.BinOpBitwiseAnd:        bytecode.OpcodeBitwiseAnd,
+	ir.BinOpBitwiseLeftShift:  bytecode.OpcodeBitwiseLeftShift,
+	ir.BinOpBitwiseRightShift: bytecode.OpcodeBitwiseRightShift,
+}
+
+// unOpOpcodes are the opcodes of unary operations
+var unOpOpcodes = map[ir.UnOp]bytecode.Opcode{
+	ir.UnOpNegate:   bytecode.OpcodeNegate,
+	ir.UnOpNot:      bytecode.OpcodeNot,
+	ir.UnOpForce:    bytecode.OpcodeForce,
+	ir.UnOpIsNil:    bytecode.OpcodeIsNil,
+	ir.UnOpSome:     bytecode.OpcodeSome,
+	ir.UnOpTransfer: bytecode.OpcodeTransfer,
+}
+
+// GenerateBytecode generates a bytecode program for the given functions.
+// The index of a function in the given list is the function index used in IR call expressions.
+func GenerateBytecode(funcs []*ir.Func) *bytecode.Program {
+	g := &bytecodeCodeGen{
+		program:   &bytecode.Program{},
+		funcs:     funcs,
+		constants: map[bytecodeConstantKey]uint16{},
+	}
+
+	for _, f := range funcs {
+		f.Accept(g)
+	}
+
+	return g.program
+}
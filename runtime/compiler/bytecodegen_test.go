@@ -0,0 +1,166 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compiler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/compiler/bytecode"
+	"github.com/onflow/cadence/runtime/compiler/ir"
+)
+
+func TestBytecodeCodeGenSimple(t *testing.T) {
+
+	t.Parallel()
+
+	program := GenerateBytecode([]*ir.Func{
+		{
+			Name: "inc",
+			Type: ir.FuncType{
+				Params: []ir.ValType{
+					ir.ValTypeInt,
+				},
+				Results: []ir.ValType{
+					ir.ValTypeInt,
+				},
+			},
+			Locals: []ir.Local{
+				{Type: ir.ValTypeInt},
+			},
+			Statement: &ir.Sequence{
+				Stmts: []ir.Stmt{
+					&ir.StoreLocal{
+						LocalIndex: 1,
+						Exp: &ir.Const{
+							Constant: ir.Int{Value: []byte{1, 1}},
+						},
+					},
+					&ir.Return{
+						Exp: &ir.BinOpExpr{
+							Op: ir.BinOpPlus,
+							Left: &ir.CopyLocal{
+								LocalIndex: 0,
+							},
+							Right: &ir.CopyLocal{
+								LocalIndex: 1,
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	require.Equal(t,
+		&bytecode.Program{
+			Functions: []*bytecode.Function{
+				{
+					Name:           "inc",
+					ParameterCount: 1,
+					LocalCount:     2,
+					HasResult:      true,
+					Code: []byte{
+						byte(bytecode.OpcodeInt), 0, 0,
+						byte(bytecode.OpcodeSetLocal), 0, 1,
+						byte(bytecode.OpcodeGetLocal), 0, 0,
+						byte(bytecode.OpcodeGetLocal), 0, 1,
+						byte(bytecode.OpcodeAdd),
+						byte(bytecode.OpcodeReturnValue),
+						byte(bytecode.OpcodeUnreachable),
+					},
+				},
+			},
+			Constants: []*bytecode.Constant{
+				{
+					Kind: bytecode.ConstantKindInt,
+					Data: []byte{1, 1},
+				},
+			},
+		},
+		program,
+	)
+}
+
+func TestBytecodeCodeGenLoop(t *testing.T) {
+
+	t.Parallel()
+
+	// block
+	//   loop
+	//     br_if 1 (local 0)
+	//     block
+	//       if (local 0)
+	//         br 1
+	//       else
+	//         br 2
+	//     end
+	//     br 0
+	//   end
+	// end
+
+	program := GenerateBytecode([]*ir.Func{
+		{
+			Name: "test",
+			Type: ir.FuncType{
+				Params: []ir.ValType{
+					ir.ValTypeBool,
+				},
+			},
+			Statement: &ir.Block{
+				Stmts: []ir.Stmt{
+					&ir.Loop{
+						Stmts: []ir.Stmt{
+							&ir.BranchIf{
+								Exp:   &ir.CopyLocal{LocalIndex: 0},
+								Index: 1,
+							},
+							&ir.Block{
+								Stmts: []ir.Stmt{
+									&ir.If{
+										Test: &ir.CopyLocal{LocalIndex: 0},
+										Then: &ir.Branch{Index: 1},
+										Else: &ir.Branch{Index: 2},
+									},
+								},
+							},
+							&ir.Branch{Index: 0},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	require.Equal(t,
+		`function 0 test (parameters: 1, locals: 1, result: false):
+  0000: GetLocal 0
+  0003: JumpIfTrue 24
+  0006: GetLocal 0
+  0009: JumpIfFalse 18
+  0012: Jump 21
+  0015: Jump 21
+  0018: Jump 0
+  0021: Jump 0
+  0024: Return
+`,
+		bytecode.Disassemble(program),
+	)
+}
@@ -24,16 +24,52 @@ import (
 	"github.com/onflow/cadence/runtime/compiler/ir"
 	"github.com/onflow/cadence/runtime/compiler/wasm"
 	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
 )
 
 const RuntimeModuleName = "crt"
 
 type wasmCodeGen struct {
-	mod                        *wasm.ModuleBuilder
-	code                       *wasm.Code
-	runtimeFunctionIndexInt    uint32
-	runtimeFunctionIndexString uint32
-	runtimeFunctionIndexAdd    uint32
+	mod                                *wasm.ModuleBuilder
+	code                               *wasm.Code
+	funcs                              []*ir.Func
+	functionIndexOffset                uint32
+	constantOffsets                    map[string]uint32
+	runtimeFunctionIndexInt            uint32
+	runtimeFunctionIndexString         uint32
+	runtimeFunctionIndexCharacter      uint32
+	runtimeFunctionIndexNumber         uint32
+	runtimeFunctionIndexAddress        uint32
+	runtimeFunctionIndexPath           uint32
+	runtimeFunctionIndexBool           uint32
+	runtimeFunctionIndexNil            uint32
+	runtimeFunctionIndexVoid           uint32
+	runtimeFunctionIndexAdd            uint32
+	runtimeFunctionIndicesBinOp        map[ir.BinOp]uint32
+	runtimeFunctionIndicesUnOp         map[ir.UnOp]uint32
+	runtimeFunctionIndexTruthy         uint32
+	runtimeFunctionIndexGetGlobal      uint32
+	runtimeFunctionIndexSetGlobal      uint32
+	runtimeFunctionIndexGetMember      uint32
+	runtimeFunctionIndexSetMember      uint32
+	runtimeFunctionIndexGetIndex       uint32
+	runtimeFunctionIndexSetIndex       uint32
+	runtimeFunctionIndexArguments      uint32
+	runtimeFunctionIndexArgumentsPush  uint32
+	runtimeFunctionIndexCallMember     uint32
+	runtimeFunctionIndexCallBuiltin    uint32
+	runtimeFunctionIndexNewArray       uint32
+	runtimeFunctionIndexNewDictionary  uint32
+	runtimeFunctionIndexNewComposite   uint32
+	runtimeFunctionIndexStringTemplate uint32
+	runtimeFunctionIndexCast           uint32
+	runtimeFunctionIndexIsComposite    uint32
+	runtimeFunctionIndexReference      uint32
+	runtimeFunctionIndexBox            uint32
+	runtimeFunctionIndexDestroy        uint32
+	runtimeFunctionIndexEmit           uint32
+	runtimeFunctionIndexFailCondition  uint32
 }
 
 func (codeGen *wasmCodeGen) VisitInt(i ir.Int) ir.Repr {
@@ -52,36 +88,104 @@ func (codeGen *wasmCodeGen) VisitString(s ir.String) ir.Repr {
 	return nil
 }
 
+func (codeGen *wasmCodeGen) VisitCharacter(c ir.Character) ir.Repr {
+	codeGen.emitConstantCall(
+		codeGen.runtimeFunctionIndexCharacter,
+		[]byte(c.Value),
+	)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitBool(b ir.Bool) ir.Repr {
+	var value int32
+	if b.Value {
+		value = 1
+	}
+	codeGen.emit(wasm.InstructionI32Const{Value: value})
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexBool})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitNil(_ ir.Nil) ir.Repr {
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexNil})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitNumber(n ir.Number) ir.Repr {
+	codeGen.emitType(n.Type)
+	codeGen.emitConstantCall(
+		codeGen.runtimeFunctionIndexNumber,
+		n.Value,
+	)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitAddress(a ir.Address) ir.Repr {
+	codeGen.emitConstantCall(
+		codeGen.runtimeFunctionIndexAddress,
+		a.Value[:],
+	)
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitPath(p ir.Path) ir.Repr {
+	codeGen.emit(wasm.InstructionI32Const{Value: int32(p.Domain)})
+	codeGen.emitConstantCall(
+		codeGen.runtimeFunctionIndexPath,
+		[]byte(p.Identifier),
+	)
+	return nil
+}
+
 func (codeGen *wasmCodeGen) VisitSequence(sequence *ir.Sequence) ir.Repr {
 	for _, stmt := range sequence.Stmts {
-		stmt.Accept(codeGen)
+		codeGen.emitStmt(stmt)
 	}
 	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitBlock(_ *ir.Block) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitBlock(block *ir.Block) ir.Repr {
+	codeGen.emit(wasm.InstructionBlock{
+		Block: wasm.Block{
+			Instructions1: codeGen.emitNestedStmts(block.Stmts),
+		},
+	})
+	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitLoop(_ *ir.Loop) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitLoop(loop *ir.Loop) ir.Repr {
+	codeGen.emit(wasm.InstructionLoop{
+		Block: wasm.Block{
+			Instructions1: codeGen.emitNestedStmts(loop.Stmts),
+		},
+	})
+	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitIf(_ *ir.If) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitIf(stmt *ir.If) ir.Repr {
+	codeGen.emitTest(stmt.Test)
+	codeGen.emit(wasm.InstructionIf{
+		Block: wasm.Block{
+			Instructions1: codeGen.emitNestedStmts([]ir.Stmt{stmt.Then}),
+			Instructions2: codeGen.emitNestedStmts([]ir.Stmt{stmt.Else}),
+		},
+	})
+	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitBranch(_ *ir.Branch) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitBranch(branch *ir.Branch) ir.Repr {
+	codeGen.emit(wasm.InstructionBr{
+		LabelIndex: branch.Index,
+	})
+	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitBranchIf(_ *ir.BranchIf) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitBranchIf(branchIf *ir.BranchIf) ir.Repr {
+	codeGen.emitTest(branchIf.Exp)
+	codeGen.emit(wasm.InstructionBrIf{
+		LabelIndex: branchIf.Index,
+	})
+	return nil
 }
 
 func (codeGen *wasmCodeGen) VisitStoreLocal(storeLocal *ir.StoreLocal) ir.Repr {
@@ -92,66 +196,285 @@ func (codeGen *wasmCodeGen) VisitStoreLocal(storeLocal *ir.StoreLocal) ir.Repr {
 	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitDrop(_ *ir.Drop) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitSetGlobal(setGlobal *ir.SetGlobal) ir.Repr {
+	codeGen.emitConstant([]byte(setGlobal.Name))
+	setGlobal.Exp.Accept(codeGen)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexSetGlobal})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitDrop(drop *ir.Drop) ir.Repr {
+	drop.Exp.Accept(codeGen)
+	codeGen.emit(wasm.InstructionDrop{})
+	return nil
 }
 
 func (codeGen *wasmCodeGen) VisitReturn(r *ir.Return) ir.Repr {
-	r.Exp.Accept(codeGen)
+	if r.Exp != nil {
+		r.Exp.Accept(codeGen)
+	}
 	codeGen.emit(wasm.InstructionReturn{})
 	return nil
 }
 
+func (codeGen *wasmCodeGen) VisitSetMember(setMember *ir.SetMember) ir.Repr {
+	setMember.Target.Accept(codeGen)
+	codeGen.emitConstant([]byte(setMember.Name))
+	setMember.Exp.Accept(codeGen)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexSetMember})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitSetIndex(setIndex *ir.SetIndex) ir.Repr {
+	setIndex.Target.Accept(codeGen)
+	setIndex.Index.Accept(codeGen)
+	setIndex.Exp.Accept(codeGen)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexSetIndex})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitEmit(emit *ir.Emit) ir.Repr {
+	emit.Exp.Accept(codeGen)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexEmit})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitFailCondition(failCondition *ir.FailCondition) ir.Repr {
+	codeGen.emit(wasm.InstructionI32Const{Value: int32(failCondition.Kind)})
+	failCondition.Message.Accept(codeGen)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexFailCondition})
+	// NOTE: the run-time function does not return
+	codeGen.emit(wasm.InstructionUnreachable{})
+	return nil
+}
+
 func (codeGen *wasmCodeGen) VisitConst(c *ir.Const) ir.Repr {
 	c.Constant.Accept(codeGen)
 	return nil
 }
 
 func (codeGen *wasmCodeGen) VisitCopyLocal(c *ir.CopyLocal) ir.Repr {
-	// TODO: copy
+	// NOTE: values are copied when they are transferred
 	codeGen.emit(wasm.InstructionLocalGet{
 		LocalIndex: c.LocalIndex,
 	})
 	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitMoveLocal(_ *ir.MoveLocal) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitMoveLocal(m *ir.MoveLocal) ir.Repr {
+	// TODO: invalidate the local
+	codeGen.emit(wasm.InstructionLocalGet{
+		LocalIndex: m.LocalIndex,
+	})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitGetGlobal(g *ir.GetGlobal) ir.Repr {
+	codeGen.emitConstantCall(
+		codeGen.runtimeFunctionIndexGetGlobal,
+		[]byte(g.Name),
+	)
+	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitUnOpExpr(_ *ir.UnOpExpr) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitTeeLocal(t *ir.TeeLocal) ir.Repr {
+	t.Exp.Accept(codeGen)
+	codeGen.emit(wasm.InstructionLocalTee{
+		LocalIndex: t.LocalIndex,
+	})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitUnOpExpr(expr *ir.UnOpExpr) ir.Repr {
+	expr.Expr.Accept(codeGen)
+	funcIndex, ok := codeGen.runtimeFunctionIndicesUnOp[expr.Op]
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	codeGen.emit(wasm.InstructionCall{
+		FuncIndex: funcIndex,
+	})
+	return nil
 }
 
 func (codeGen *wasmCodeGen) VisitBinOpExpr(expr *ir.BinOpExpr) ir.Repr {
 	expr.Left.Accept(codeGen)
 	expr.Right.Accept(codeGen)
-	// TODO: add remaining operations, take types into account
-	switch expr.Op {
-	case ir.BinOpPlus:
+	funcIndex, ok := codeGen.runtimeFunctionIndicesBinOp[expr.Op]
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	codeGen.emit(wasm.InstructionCall{
+		FuncIndex: funcIndex,
+	})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitCall(call *ir.Call) ir.Repr {
+	for _, argument := range call.Arguments {
+		argument.Accept(codeGen)
+	}
+	codeGen.emit(wasm.InstructionCall{
+		FuncIndex: codeGen.functionIndexOffset + call.FunctionIndex,
+	})
+
+	// Every expression results in a value.
+	// Functions which return Void have no results,
+	// so produce the Void value
+
+	if len(codeGen.funcs[call.FunctionIndex].Type.Results) == 0 {
 		codeGen.emit(wasm.InstructionCall{
-			FuncIndex: codeGen.runtimeFunctionIndexAdd,
+			FuncIndex: codeGen.runtimeFunctionIndexVoid,
 		})
-		return nil
 	}
-	panic(errors.NewUnreachableError())
+
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitConditional(conditional *ir.Conditional) ir.Repr {
+	codeGen.emitTest(conditional.Test)
+	codeGen.emit(wasm.InstructionIf{
+		Block: wasm.Block{
+			BlockType:     wasm.ValueTypeExternRef,
+			Instructions1: codeGen.emitNested(conditional.Then.Accept),
+			Instructions2: codeGen.emitNested(conditional.Else.Accept),
+		},
+	})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitCallMember(call *ir.CallMember) ir.Repr {
+	call.Target.Accept(codeGen)
+	codeGen.emitConstant([]byte(call.Name))
+	codeGen.emitBool(call.Optional)
+	codeGen.emitArguments(call.Arguments)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexCallMember})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitCallBuiltin(call *ir.CallBuiltin) ir.Repr {
+	codeGen.emitConstant([]byte(call.Name))
+	codeGen.emitArguments(call.Arguments)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexCallBuiltin})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitGetMember(getMember *ir.GetMember) ir.Repr {
+	getMember.Target.Accept(codeGen)
+	codeGen.emitConstant([]byte(getMember.Name))
+	codeGen.emitBool(getMember.Optional)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexGetMember})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitGetIndex(getIndex *ir.GetIndex) ir.Repr {
+	getIndex.Target.Accept(codeGen)
+	getIndex.Index.Accept(codeGen)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexGetIndex})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitNewArray(newArray *ir.NewArray) ir.Repr {
+	codeGen.emitType(newArray.Type)
+	codeGen.emitArguments(newArray.Elements)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexNewArray})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitNewDictionary(newDictionary *ir.NewDictionary) ir.Repr {
+	codeGen.emitType(newDictionary.Type)
+
+	// Pass keys and values as alternating arguments
+
+	keysAndValues := make([]ir.Expr, 0, len(newDictionary.Entries)*2)
+	for _, entry := range newDictionary.Entries {
+		keysAndValues = append(keysAndValues, entry.Key, entry.Value)
+	}
+	codeGen.emitArguments(keysAndValues)
+
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexNewDictionary})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitNewComposite(newComposite *ir.NewComposite) ir.Repr {
+	codeGen.emitType(newComposite.Type)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexNewComposite})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitCast(cast *ir.Cast) ir.Repr {
+	cast.Exp.Accept(codeGen)
+	codeGen.emitType(cast.Type)
+	codeGen.emitBool(cast.Force)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexCast})
+	return nil
 }
 
-func (codeGen *wasmCodeGen) VisitCall(_ *ir.Call) ir.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (codeGen *wasmCodeGen) VisitBox(box *ir.Box) ir.Repr {
+	box.Exp.Accept(codeGen)
+	codeGen.emitType(box.Type)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexBox})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitReference(reference *ir.Reference) ir.Repr {
+	reference.Exp.Accept(codeGen)
+	codeGen.emitType(reference.Type)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexReference})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitDestroy(destroy *ir.Destroy) ir.Repr {
+	destroy.Exp.Accept(codeGen)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexDestroy})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitIsComposite(isComposite *ir.IsComposite) ir.Repr {
+	isComposite.Exp.Accept(codeGen)
+	codeGen.emitType(isComposite.Type)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexIsComposite})
+	return nil
+}
+
+func (codeGen *wasmCodeGen) VisitStringTemplate(template *ir.StringTemplate) ir.Repr {
+
+	// Pass literal parts and embedded values as alternating arguments
+
+	literalsAndValues := make([]ir.Expr, 0, len(template.Values)+len(template.Exps))
+	for i, value := range template.Values {
+		literalsAndValues = append(literalsAndValues, &ir.Const{
+			Constant: ir.String{Value: value},
+		})
+		if i < len(template.Exps) {
+			literalsAndValues = append(literalsAndValues, template.Exps[i])
+		}
+	}
+	codeGen.emitArguments(literalsAndValues)
+
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexStringTemplate})
+	return nil
 }
 
 func (codeGen *wasmCodeGen) VisitFunc(f *ir.Func) ir.Repr {
 	codeGen.code = &wasm.Code{}
 	codeGen.code.Locals = generateWasmLocalTypes(f.Locals)
 	f.Statement.Accept(codeGen)
+
+	// The function body might not end with a return instruction,
+	// e.g. if all branches of an if-statement return
+	if len(f.Type.Results) > 0 {
+		codeGen.emit(wasm.InstructionUnreachable{})
+	}
+
 	functionType := generateWasmFunctionType(f.Type)
 	funcIndex := codeGen.mod.AddFunction(f.Name, functionType, codeGen.code)
-	// TODO: make export dependent on visibility modifier
+	if f.Start {
+		codeGen.mod.SetStartFunction(funcIndex)
+	}
+	// All functions are exported, independent of their access modifier:
+	// the host invokes entry points, initializers, destructors and methods by name,
+	// and access control is already enforced by the checker
 	codeGen.mod.AddExport(&wasm.Export{
 		Name: f.Name,
 		Descriptor: wasm.FunctionExport{
@@ -165,25 +488,109 @@ func (codeGen *wasmCodeGen) emit(inst wasm.Instruction) {
 	codeGen.code.Instructions = append(codeGen.code.Instructions, inst)
 }
 
+// emitNested returns the instructions emitted by the given function,
+// e.g. the instructions of a block
+func (codeGen *wasmCodeGen) emitNested(f func(ir.Visitor) ir.Repr) []wasm.Instruction {
+	previousInstructions := codeGen.code.Instructions
+	codeGen.code.Instructions = nil
+
+	f(codeGen)
+
+	instructions := codeGen.code.Instructions
+	codeGen.code.Instructions = previousInstructions
+	return instructions
+}
+
+func (codeGen *wasmCodeGen) emitNestedStmts(stmts []ir.Stmt) []wasm.Instruction {
+	return codeGen.emitNested(func(_ ir.Visitor) ir.Repr {
+		for _, stmt := range stmts {
+			codeGen.emitStmt(stmt)
+		}
+		return nil
+	})
+}
+
+// emitStmt emits the given statement, which might be nil,
+// e.g. the missing else-branch of an if-statement
+func (codeGen *wasmCodeGen) emitStmt(stmt ir.Stmt) {
+	if stmt == nil {
+		return
+	}
+	stmt.Accept(codeGen)
+}
+
+// emitTest emits the given expression, which results in a boolean value,
+// and converts the value to an i32, as expected by control instructions
+func (codeGen *wasmCodeGen) emitTest(test ir.Expr) {
+	test.Accept(codeGen)
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexTruthy})
+}
+
+func (codeGen *wasmCodeGen) emitBool(value bool) {
+	var i int32
+	if value {
+		i = 1
+	}
+	codeGen.emit(wasm.InstructionI32Const{Value: i})
+}
+
+// emitArguments emits a list of the values of the given expressions.
+// The list is passed to run-time functions which have a variable number of parameters
+func (codeGen *wasmCodeGen) emitArguments(arguments []ir.Expr) {
+	codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexArguments})
+	for _, argument := range arguments {
+		argument.Accept(codeGen)
+		codeGen.emit(wasm.InstructionCall{FuncIndex: codeGen.runtimeFunctionIndexArgumentsPush})
+	}
+}
+
+// emitType emits the memory offset and length of the encoded static type
+func (codeGen *wasmCodeGen) emitType(ty sema.Type) {
+	staticType := interpreter.ConvertSemaToStaticType(nil, ty)
+	encoded, err := interpreter.StaticTypeToBytes(staticType)
+	if err != nil {
+		panic(fmt.Errorf("failed to encode type %s: %w", ty, err))
+	}
+	codeGen.emitConstant(encoded)
+}
+
 func (codeGen *wasmCodeGen) addConstant(value []byte) uint32 {
+	key := string(value)
+	if offset, ok := codeGen.constantOffsets[key]; ok {
+		return offset
+	}
+
 	offset := codeGen.mod.RequireMemory(uint32(len(value)))
 	// TODO: optimize:
 	//   let module builder generate one data entry of all constants,
 	//   instead of one data entry for each constant
 	codeGen.mod.AddData(offset, value)
+
+	codeGen.constantOffsets[key] = offset
 	return offset
 }
 
-func (codeGen *wasmCodeGen) emitConstantCall(funcIndex uint32, value []byte) {
+// emitConstant emits the memory offset and length of the given constant
+func (codeGen *wasmCodeGen) emitConstant(value []byte) {
 	memoryOffset := codeGen.addConstant(value)
 	codeGen.emit(wasm.InstructionI32Const{Value: int32(memoryOffset)})
 
 	length := int32(len(value))
 	codeGen.emit(wasm.InstructionI32Const{Value: length})
+}
 
+func (codeGen *wasmCodeGen) emitConstantCall(funcIndex uint32, value []byte) {
+	codeGen.emitConstant(value)
 	codeGen.emit(wasm.InstructionCall{FuncIndex: funcIndex})
 }
 
+func runtimeFunctionType(params []wasm.ValueType, results ...wasm.ValueType) *wasm.FunctionType {
+	return &wasm.FunctionType{
+		Params:  params,
+		Results: results,
+	}
+}
+
 var constantFunctionType = &wasm.FunctionType{
 	Params: []wasm.ValueType{
 		// memory offset
@@ -196,6 +603,44 @@ var constantFunctionType = &wasm.FunctionType{
 	},
 }
 
+var numberFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// type memory offset
+		wasm.ValueTypeI32,
+		// type length
+		wasm.ValueTypeI32,
+		// value memory offset
+		wasm.ValueTypeI32,
+		// value length
+		wasm.ValueTypeI32,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var pathFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// domain
+		wasm.ValueTypeI32,
+		// identifier memory offset
+		wasm.ValueTypeI32,
+		// identifier length
+		wasm.ValueTypeI32,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var boolFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		wasm.ValueTypeI32,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var valueFunctionType = runtimeFunctionType(
+	nil,
+	wasm.ValueTypeExternRef,
+)
+
 var addFunctionType = &wasm.FunctionType{
 	Params: []wasm.ValueType{
 		wasm.ValueTypeExternRef,
@@ -206,11 +651,297 @@ var addFunctionType = &wasm.FunctionType{
 	},
 }
 
+var unaryFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		wasm.ValueTypeExternRef,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var truthyFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		wasm.ValueTypeExternRef,
+	},
+	wasm.ValueTypeI32,
+)
+
+var getMemberFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// target
+		wasm.ValueTypeExternRef,
+		// name memory offset
+		wasm.ValueTypeI32,
+		// name length
+		wasm.ValueTypeI32,
+		// optional
+		wasm.ValueTypeI32,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var setMemberFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// target
+		wasm.ValueTypeExternRef,
+		// name memory offset
+		wasm.ValueTypeI32,
+		// name length
+		wasm.ValueTypeI32,
+		// value
+		wasm.ValueTypeExternRef,
+	},
+)
+
+var getIndexFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// target
+		wasm.ValueTypeExternRef,
+		// index
+		wasm.ValueTypeExternRef,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var setIndexFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// target
+		wasm.ValueTypeExternRef,
+		// index
+		wasm.ValueTypeExternRef,
+		// value
+		wasm.ValueTypeExternRef,
+	},
+)
+
+var argumentsPushFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// arguments
+		wasm.ValueTypeExternRef,
+		// argument
+		wasm.ValueTypeExternRef,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var callMemberFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// target
+		wasm.ValueTypeExternRef,
+		// name memory offset
+		wasm.ValueTypeI32,
+		// name length
+		wasm.ValueTypeI32,
+		// optional
+		wasm.ValueTypeI32,
+		// arguments
+		wasm.ValueTypeExternRef,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var callBuiltinFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// name memory offset
+		wasm.ValueTypeI32,
+		// name length
+		wasm.ValueTypeI32,
+		// arguments
+		wasm.ValueTypeExternRef,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var newContainerFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// type memory offset
+		wasm.ValueTypeI32,
+		// type length
+		wasm.ValueTypeI32,
+		// elements, or alternating keys and values
+		wasm.ValueTypeExternRef,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var newCompositeFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// type memory offset
+		wasm.ValueTypeI32,
+		// type length
+		wasm.ValueTypeI32,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var castFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// value
+		wasm.ValueTypeExternRef,
+		// type memory offset
+		wasm.ValueTypeI32,
+		// type length
+		wasm.ValueTypeI32,
+		// force
+		wasm.ValueTypeI32,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var referenceFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// value
+		wasm.ValueTypeExternRef,
+		// type memory offset
+		wasm.ValueTypeI32,
+		// type length
+		wasm.ValueTypeI32,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var boxFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// value
+		wasm.ValueTypeExternRef,
+		// type memory offset
+		wasm.ValueTypeI32,
+		// type length
+		wasm.ValueTypeI32,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var getGlobalFunctionType = constantFunctionType
+
+var setGlobalFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// name memory offset
+		wasm.ValueTypeI32,
+		// name length
+		wasm.ValueTypeI32,
+		// value
+		wasm.ValueTypeExternRef,
+	},
+)
+
+var stringTemplateFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// alternating literal parts and embedded values
+		wasm.ValueTypeExternRef,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var isCompositeFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// value
+		wasm.ValueTypeExternRef,
+		// type memory offset
+		wasm.ValueTypeI32,
+		// type length
+		wasm.ValueTypeI32,
+	},
+	wasm.ValueTypeExternRef,
+)
+
+var emitFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// event
+		wasm.ValueTypeExternRef,
+	},
+)
+
+var failConditionFunctionType = runtimeFunctionType(
+	[]wasm.ValueType{
+		// condition kind
+		wasm.ValueTypeI32,
+		// message
+		wasm.ValueTypeExternRef,
+	},
+)
+
+// binOpRuntimeFunctionNames are the names of the run-time functions
+// which implement binary operations
+var binOpRuntimeFunctionNames = map[ir.BinOp]string{
+	ir.BinOpMinus:             "sub",
+	ir.BinOpMul:               "mul",
+	ir.BinOpDiv:               "div",
+	ir.BinOpMod:               "mod",
+	ir.BinOpEqual:             "eq",
+	ir.BinOpNotEqual:          "ne",
+	ir.BinOpLess:              "lt",
+	ir.BinOpLessEqual:         "le",
+	ir.BinOpGreater:           "gt",
+	ir.BinOpGreaterEqual:      "ge",
+	ir.BinOpBitwiseOr:         "bit_or",
+	ir.BinOpBitwiseXor:        "bit_xor",
+	ir.BinOpBitwiseAnd:        "bit_and",
+	ir.BinOpBitwiseLeftShift:  "shl",
+	ir.BinOpBitwiseRightShift: "shr",
+}
+
+// unOpRuntimeFunctionNames are the names of the run-time functions
+// which implement unary operations
+var unOpRuntimeFunctionNames = map[ir.UnOp]string{
+	ir.UnOpNegate:   "neg",
+	ir.UnOpNot:      "not",
+	ir.UnOpForce:    "force",
+	ir.UnOpIsNil:    "is_nil",
+	ir.UnOpSome:     "some",
+	ir.UnOpTransfer: "transfer",
+}
+
 func (codeGen *wasmCodeGen) addRuntimeImports() {
 	// NOTE: ensure to update the imports in the vm
 	codeGen.runtimeFunctionIndexInt = codeGen.addRuntimeImport("Int", constantFunctionType)
 	codeGen.runtimeFunctionIndexString = codeGen.addRuntimeImport("String", constantFunctionType)
 	codeGen.runtimeFunctionIndexAdd = codeGen.addRuntimeImport("add", addFunctionType)
+	codeGen.runtimeFunctionIndexCharacter = codeGen.addRuntimeImport("Character", constantFunctionType)
+	codeGen.runtimeFunctionIndexNumber = codeGen.addRuntimeImport("Number", numberFunctionType)
+	codeGen.runtimeFunctionIndexAddress = codeGen.addRuntimeImport("Address", constantFunctionType)
+	codeGen.runtimeFunctionIndexPath = codeGen.addRuntimeImport("Path", pathFunctionType)
+	codeGen.runtimeFunctionIndexBool = codeGen.addRuntimeImport("Bool", boolFunctionType)
+	codeGen.runtimeFunctionIndexNil = codeGen.addRuntimeImport("Nil", valueFunctionType)
+	codeGen.runtimeFunctionIndexVoid = codeGen.addRuntimeImport("Void", valueFunctionType)
+
+	codeGen.runtimeFunctionIndicesBinOp = map[ir.BinOp]uint32{
+		ir.BinOpPlus: codeGen.runtimeFunctionIndexAdd,
+	}
+	for op := ir.BinOpMinus; op <= ir.BinOpBitwiseRightShift; op++ {
+		codeGen.runtimeFunctionIndicesBinOp[op] =
+			codeGen.addRuntimeImport(binOpRuntimeFunctionNames[op], addFunctionType)
+	}
+
+	codeGen.runtimeFunctionIndicesUnOp = map[ir.UnOp]uint32{}
+	for op := ir.UnOpNegate; op <= ir.UnOpTransfer; op++ {
+		codeGen.runtimeFunctionIndicesUnOp[op] =
+			codeGen.addRuntimeImport(unOpRuntimeFunctionNames[op], unaryFunctionType)
+	}
+
+	codeGen.runtimeFunctionIndexTruthy = codeGen.addRuntimeImport("truthy", truthyFunctionType)
+	codeGen.runtimeFunctionIndexGetGlobal = codeGen.addRuntimeImport("get_global", getGlobalFunctionType)
+	codeGen.runtimeFunctionIndexSetGlobal = codeGen.addRuntimeImport("set_global", setGlobalFunctionType)
+	codeGen.runtimeFunctionIndexGetMember = codeGen.addRuntimeImport("get_member", getMemberFunctionType)
+	codeGen.runtimeFunctionIndexSetMember = codeGen.addRuntimeImport("set_member", setMemberFunctionType)
+	codeGen.runtimeFunctionIndexGetIndex = codeGen.addRuntimeImport("get_index", getIndexFunctionType)
+	codeGen.runtimeFunctionIndexSetIndex = codeGen.addRuntimeImport("set_index", setIndexFunctionType)
+	codeGen.runtimeFunctionIndexArguments = codeGen.addRuntimeImport("args", valueFunctionType)
+	codeGen.runtimeFunctionIndexArgumentsPush = codeGen.addRuntimeImport("args_push", argumentsPushFunctionType)
+	codeGen.runtimeFunctionIndexCallMember = codeGen.addRuntimeImport("call_member", callMemberFunctionType)
+	codeGen.runtimeFunctionIndexCallBuiltin = codeGen.addRuntimeImport("call_builtin", callBuiltinFunctionType)
+	codeGen.runtimeFunctionIndexNewArray = codeGen.addRuntimeImport("new_array", newContainerFunctionType)
+	codeGen.runtimeFunctionIndexNewDictionary = codeGen.addRuntimeImport("new_dictionary", newContainerFunctionType)
+	codeGen.runtimeFunctionIndexNewComposite = codeGen.addRuntimeImport("new_composite", newCompositeFunctionType)
+	codeGen.runtimeFunctionIndexStringTemplate = codeGen.addRuntimeImport("string_template", stringTemplateFunctionType)
+	codeGen.runtimeFunctionIndexCast = codeGen.addRuntimeImport("cast", castFunctionType)
+	codeGen.runtimeFunctionIndexIsComposite = codeGen.addRuntimeImport("is_composite", isCompositeFunctionType)
+	codeGen.runtimeFunctionIndexReference = codeGen.addRuntimeImport("reference", referenceFunctionType)
+	codeGen.runtimeFunctionIndexDestroy = codeGen.addRuntimeImport("destroy", unaryFunctionType)
+	codeGen.runtimeFunctionIndexEmit = codeGen.addRuntimeImport("emit", emitFunctionType)
+	codeGen.runtimeFunctionIndexBox = codeGen.addRuntimeImport("box", boxFunctionType)
+	codeGen.runtimeFunctionIndexFailCondition = codeGen.addRuntimeImport("fail_condition", failConditionFunctionType)
+
+	codeGen.functionIndexOffset = codeGen.runtimeFunctionIndexFailCondition + 1
 }
 
 func (codeGen *wasmCodeGen) addRuntimeImport(name string, funcType *wasm.FunctionType) uint32 {
@@ -221,9 +952,14 @@ func (codeGen *wasmCodeGen) addRuntimeImport(name string, funcType *wasm.Functio
 	return funcIndex
 }
 
+// GenerateWasm generates a WebAssembly module for the given functions.
+// The index of a function in the given list is the function index used in IR call expressions.
+// All functions are exported by name.
 func GenerateWasm(funcs []*ir.Func) *wasm.Module {
 	g := &wasmCodeGen{
-		mod: &wasm.ModuleBuilder{},
+		mod:             &wasm.ModuleBuilder{},
+		funcs:           funcs,
+		constantOffsets: map[string]uint32{},
 	}
 
 	g.addRuntimeImports()
@@ -246,15 +982,13 @@ func generateWasmLocalTypes(locals []ir.Local) []wasm.ValueType {
 }
 
 func generateWasmValType(valType ir.ValType) wasm.ValueType {
-	// TODO: add remaining types
 	switch valType {
-	case ir.ValTypeInt,
-		ir.ValTypeString:
-
-		return wasm.ValueTypeExternRef
+	case ir.ValTypeUnknown:
+		panic(errors.NewUnreachableError())
 	}
 
-	panic(errors.NewUnreachableError())
+	// All values are values of the embedder, i.e. the run-time
+	return wasm.ValueTypeExternRef
 }
 
 func generateWasmFunctionType(funcType ir.FuncType) *wasm.FunctionType {
@@ -25,6 +25,7 @@ import (
 
 	"github.com/onflow/cadence/runtime/compiler/ir"
 	"github.com/onflow/cadence/runtime/compiler/wasm"
+	"github.com/onflow/cadence/runtime/tests/checker"
 )
 
 func TestWasmCodeGenSimple(t *testing.T) {
@@ -198,3 +199,54 @@ func TestWasmCodeGenSimple(t *testing.T) {
 
 	_ = wasm.WASM2WAT(buf.Bytes())
 }
+
+func TestWasmCodeGenProgram(t *testing.T) {
+
+	checker, err := checker.ParseAndCheck(t, `
+      struct S {
+          let values: [Int]
+
+          init() {
+              self.values = [1, 2, 3]
+          }
+
+          fun sum(): Int {
+              var sum = 0
+              for value in self.values {
+                  sum = sum + value
+              }
+              return sum
+          }
+      }
+
+      fun test(_ x: Int?): String {
+          let s = S()
+          switch s.sum() + (x ?? 0) {
+          case 6:
+              return "six"
+          default:
+              return "other"
+          }
+      }
+    `)
+
+	require.NoError(t, err)
+
+	funcs, err := Compile(checker)
+	require.NoError(t, err)
+
+	mod := GenerateWasm(funcs)
+
+	var buf wasm.Buffer
+	w := wasm.NewWASMWriter(&buf)
+	err = w.WriteModule(mod)
+	require.NoError(t, err)
+
+	r := wasm.NewWASMReader(wasm.NewBuffer(buf.Bytes()))
+	err = r.ReadModule()
+	require.NoError(t, err)
+
+	require.Equal(t, mod.Imports, r.Module.Imports)
+	require.Equal(t, mod.Exports, r.Module.Exports)
+	require.Len(t, r.Module.Functions, len(funcs))
+}
@@ -19,7 +19,11 @@
 package compiler
 
 import (
+	"math/big"
+
+	"github.com/onflow/cadence/fixedpoint"
 	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/compiler/ir"
 	"github.com/onflow/cadence/runtime/errors"
 	"github.com/onflow/cadence/runtime/sema"
@@ -29,22 +33,110 @@ type Compiler struct {
 	Checker     *sema.Checker
 	activations *LocalActivations
 	locals      []*Local
+	// functions are the compiled functions of the program,
+	// indexed by function index
+	functions []*function
+	// globals maps the qualified names of top-level functions
+	// and composite constructors to compiled functions
+	globals map[string]*function
+	// members maps composite types to their compiled member functions
+	members map[*sema.CompositeType]map[string]*function
+	// enumCases maps enum types to the compiled functions
+	// which create their cases
+	enumCases map[*sema.CompositeType]map[string]*function
+	// composites are the composite types declared in the program,
+	// in declaration order
+	composites []*sema.CompositeType
+	// globalVariables maps the names of global variables to their types
+	globalVariables map[string]sema.Type
+	// compositeTypes is the stack of composite types
+	// which are currently being compiled
+	compositeTypes []*sema.CompositeType
+	// depth is the number of enclosing IR control structures
+	depth   uint32
+	loops   []loopTargets
+	returns *returnTarget
+}
+
+// function is a function of the program which is compiled
+// to an IR function
+type function struct {
+	index        uint32
+	name         string
+	functionType *sema.FunctionType
+}
+
+// loopTargets are the depths of the IR control structures
+// which a break statement and a continue statement branch to
+type loopTargets struct {
+	breakDepth    uint32
+	continueDepth uint32
+}
+
+// returnTarget is used for functions where a return statement
+// does not immediately return, but branches to the end of the function body,
+// e.g. to check post-conditions, or to return the constructed value in an initializer
+type returnTarget struct {
+	depth       uint32
+	resultLocal *Local
 }
 
 func NewCompiler(checker *sema.Checker) *Compiler {
 	return &Compiler{
-		Checker:     checker,
-		activations: &LocalActivations{},
+		Checker:         checker,
+		activations:     &LocalActivations{},
+		globals:         map[string]*function{},
+		members:         map[*sema.CompositeType]map[string]*function{},
+		enumCases:       map[*sema.CompositeType]map[string]*function{},
+		globalVariables: map[string]sema.Type{},
 	}
 }
 
-// declareLocal declares a local
-func (compiler *Compiler) declareLocal(identifier string, valType ir.ValType) *Local {
+// Compile compiles the checked program to a list of IR functions.
+// The list is indexed by function index, i.e. the index of a function
+// is the index used in IR call expressions.
+//
+// The following subset of the language is not supported yet,
+// and an UnsupportedFeatureError is returned for programs using it:
+// imports, transactions, function expressions, nested functions,
+// functions as values, e.g. the invocation of a function stored in a variable,
+// imported functions, conditions of interface functions,
+// and variable declarations with a second value.
+func Compile(checker *sema.Checker) (funcs []*ir.Func, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			unsupportedErr, ok := r.(*UnsupportedFeatureError)
+			if !ok {
+				panic(r)
+			}
+			err = unsupportedErr
+		}
+	}()
+
+	compiler := NewCompiler(checker)
+	return compiler.VisitProgram(checker.Program).([]*ir.Func), nil
+}
+
+func (compiler *Compiler) elaboration() *sema.Elaboration {
+	return compiler.Checker.Elaboration
+}
+
+// declareLocal declares a local for a variable of the given type.
+// The type might be nil if it is unknown
+func (compiler *Compiler) declareLocal(identifier string, ty sema.Type) *Local {
 	// NOTE: semantic analysis already checked possible invalid redeclaration
+	local := compiler.declareTemporaryLocal(compileValueType(ty))
+	local.SemaType = ty
+	compiler.setLocal(identifier, local)
+	return local
+}
+
+// declareTemporaryLocal declares a local which has no name
+// and is only used by the compiler, e.g. to evaluate an expression only once
+func (compiler *Compiler) declareTemporaryLocal(valType ir.ValType) *Local {
 	index := uint32(len(compiler.locals))
 	local := NewLocal(index, valType)
 	compiler.locals = append(compiler.locals, local)
-	compiler.setLocal(identifier, local)
 	return local
 }
 
@@ -56,265 +148,2237 @@ func (compiler *Compiler) setLocal(name string, variable *Local) {
 	compiler.activations.Set(name, variable)
 }
 
-func (compiler *Compiler) VisitReturnStatement(statement *ast.ReturnStatement) ast.Repr {
-	exp := statement.Expression.Accept(compiler).(ir.Expr)
-	return &ir.Return{
+func (compiler *Compiler) unsupported(feature string, element ast.HasPosition) {
+	panic(&UnsupportedFeatureError{
+		Feature: feature,
+		Range:   ast.NewUnmeteredRangeFromPositioned(element),
+	})
+}
+
+// declareFunction declares a new function and returns it.
+// The function is compiled later, but can already be called
+func (compiler *Compiler) declareFunction(name string, functionType *sema.FunctionType) *function {
+	f := &function{
+		index:        uint32(len(compiler.functions)),
+		name:         name,
+		functionType: functionType,
+	}
+	compiler.functions = append(compiler.functions, f)
+	return f
+}
+
+func (compiler *Compiler) declareMember(compositeType *sema.CompositeType, name string, functionType *sema.FunctionType) {
+	members := compiler.members[compositeType]
+	if members == nil {
+		members = map[string]*function{}
+		compiler.members[compositeType] = members
+	}
+	qualifiedName := compositeType.QualifiedIdentifier() + "." + name
+	members[name] = compiler.declareFunction(qualifiedName, functionType)
+}
+
+// findGlobal returns the top-level function or composite constructor
+// with the given name, taking the enclosing composite declarations into account
+func (compiler *Compiler) findGlobal(name string) *function {
+	for i := len(compiler.compositeTypes) - 1; i >= 0; i-- {
+		qualifiedName := compiler.compositeTypes[i].QualifiedIdentifier() + "." + name
+		if global, ok := compiler.globals[qualifiedName]; ok {
+			return global
+		}
+	}
+	return compiler.globals[name]
+}
+
+// withNestedControl compiles the given function as part of an IR control structure,
+// i.e. a block, loop, or if-statement, which can be branched to
+func (compiler *Compiler) withNestedControl(f func()) {
+	compiler.depth++
+	defer func() {
+		compiler.depth--
+	}()
+	f()
+}
+
+func (compiler *Compiler) branchIndex(targetDepth uint32) uint32 {
+	return compiler.depth - targetDepth
+}
+
+func (compiler *Compiler) VisitReturnStatement(statement *ast.ReturnStatement) ast.Repr {
+	var exp ir.Expr
+	if statement.Expression != nil {
+		valueType := compiler.elaboration().ReturnStatementValueTypes[statement]
+		returnType := compiler.elaboration().ReturnStatementReturnTypes[statement]
+		exp = compiler.compileConvertedExpression(statement.Expression, valueType, returnType)
+	}
+
+	returns := compiler.returns
+	if returns == nil {
+		return &ir.Return{
+			Exp: exp,
+		}
+	}
+
+	branch := &ir.Branch{
+		Index: compiler.branchIndex(returns.depth),
+	}
+
+	if exp == nil || returns.resultLocal == nil {
+		return branch
+	}
+
+	return &ir.Sequence{
+		Stmts: []ir.Stmt{
+			&ir.StoreLocal{
+				LocalIndex: returns.resultLocal.Index,
+				Exp:        exp,
+			},
+			branch,
+		},
+	}
+}
+
+func (compiler *Compiler) VisitBreakStatement(_ *ast.BreakStatement) ast.Repr {
+	loop := compiler.loops[len(compiler.loops)-1]
+	return &ir.Branch{
+		Index: compiler.branchIndex(loop.breakDepth),
+	}
+}
+
+func (compiler *Compiler) VisitContinueStatement(_ *ast.ContinueStatement) ast.Repr {
+	// NOTE: switch statements do not declare a continue target,
+	// find the innermost loop
+	for i := len(compiler.loops) - 1; i >= 0; i-- {
+		loop := compiler.loops[i]
+		if loop.continueDepth == 0 {
+			continue
+		}
+		return &ir.Branch{
+			Index: compiler.branchIndex(loop.continueDepth),
+		}
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+func (compiler *Compiler) VisitIfStatement(statement *ast.IfStatement) ast.Repr {
+	switch test := statement.Test.(type) {
+	case ast.Expression:
+		result := &ir.If{
+			Test: compiler.compileExpression(test),
+		}
+		compiler.withNestedControl(func() {
+			result.Then = compiler.compileBlock(statement.Then)
+			result.Else = compiler.compileBlock(statement.Else)
+		})
+		return result
+
+	case *ast.VariableDeclaration:
+		return compiler.compileIfLet(statement, test)
+
+	default:
+		panic(errors.NewUnreachableError())
+	}
+}
+
+// compileIfLet compiles an optional binding, i.e. an if-statement
+// with a variable declaration as its test:
+// The optional value is evaluated into a temporary local,
+// and if it is not nil, the unwrapped value is stored in the declared local
+func (compiler *Compiler) compileIfLet(statement *ast.IfStatement, declaration *ast.VariableDeclaration) ir.Stmt {
+	if declaration.SecondValue != nil {
+		compiler.unsupported("optional binding with second value", declaration)
+	}
+
+	valueType := compiler.elaboration().VariableDeclarationValueTypes[declaration]
+	targetType := compiler.elaboration().VariableDeclarationTargetTypes[declaration]
+
+	optionalLocal := compiler.declareTemporaryLocal(compileValueType(valueType))
+
+	result := &ir.If{
+		Test: &ir.UnOpExpr{
+			Op: ir.UnOpNot,
+			Expr: &ir.UnOpExpr{
+				Op: ir.UnOpIsNil,
+				Expr: &ir.CopyLocal{
+					LocalIndex: optionalLocal.Index,
+				},
+			},
+		},
+	}
+
+	compiler.withNestedControl(func() {
+
+		// The declared local is only visible in the then-branch
+
+		compiler.activations.PushNewWithCurrent()
+		local := compiler.declareLocal(
+			declaration.Identifier.Identifier,
+			optionalType(targetType),
+		)
+		then := compiler.compileBlock(statement.Then)
+		compiler.activations.Pop()
+
+		result.Then = &ir.Sequence{
+			Stmts: []ir.Stmt{
+				&ir.StoreLocal{
+					LocalIndex: local.Index,
+					Exp: &ir.UnOpExpr{
+						Op: ir.UnOpForce,
+						Expr: &ir.CopyLocal{
+							LocalIndex: optionalLocal.Index,
+						},
+					},
+				},
+				then,
+			},
+		}
+
+		result.Else = compiler.compileBlock(statement.Else)
+	})
+
+	return &ir.Sequence{
+		Stmts: []ir.Stmt{
+			&ir.StoreLocal{
+				LocalIndex: optionalLocal.Index,
+				Exp:        compiler.compileTransferredExpression(declaration.Value, valueType),
+			},
+			result,
+		},
+	}
+}
+
+// compileBlock compiles the given block, which might be nil
+func (compiler *Compiler) compileBlock(block *ast.Block) ir.Stmt {
+	if block == nil {
+		return nil
+	}
+	return block.Accept(compiler).(ir.Stmt)
+}
+
+// compileLoop compiles a loop, which has the following structure:
+//
+//	block                  ;; break target
+//	  loop
+//	    <prologue>         ;; e.g. branch out of the outer block if the loop condition is false
+//	    block              ;; continue target
+//	      <body>
+//	    end
+//	    br 0
+//	  end
+//	end
+func (compiler *Compiler) compileLoop(prologue func() []ir.Stmt, body *ast.Block) ir.Stmt {
+	var result *ir.Block
+
+	compiler.withNestedControl(func() {
+		breakDepth := compiler.depth
+
+		compiler.withNestedControl(func() {
+			stmts := prologue()
+
+			compiler.withNestedControl(func() {
+				compiler.loops = append(compiler.loops, loopTargets{
+					breakDepth:    breakDepth,
+					continueDepth: compiler.depth,
+				})
+				defer func() {
+					compiler.loops = compiler.loops[:len(compiler.loops)-1]
+				}()
+
+				stmts = append(stmts,
+					&ir.Block{
+						Stmts: []ir.Stmt{
+							compiler.compileBlock(body),
+						},
+					},
+				)
+			})
+
+			stmts = append(stmts, &ir.Branch{Index: 0})
+
+			result = &ir.Block{
+				Stmts: []ir.Stmt{
+					&ir.Loop{
+						Stmts: stmts,
+					},
+				},
+			}
+		})
+	})
+
+	return result
+}
+
+func (compiler *Compiler) VisitWhileStatement(statement *ast.WhileStatement) ast.Repr {
+	return compiler.compileLoop(
+		func() []ir.Stmt {
+			return []ir.Stmt{
+				&ir.BranchIf{
+					Exp: &ir.UnOpExpr{
+						Op:   ir.UnOpNot,
+						Expr: compiler.compileExpression(statement.Test),
+					},
+					Index: 1,
+				},
+			}
+		},
+		statement.Block,
+	)
+}
+
+func (compiler *Compiler) VisitForStatement(statement *ast.ForStatement) ast.Repr {
+
+	// Like the interpreter, iterate over a copy of the array
+
+	arrayLocal := compiler.declareTemporaryLocal(ir.ValTypeArray)
+	indexLocal := compiler.declareTemporaryLocal(ir.ValTypeInt)
+	countLocal := compiler.declareTemporaryLocal(ir.ValTypeInt)
+
+	stmts := []ir.Stmt{
+		&ir.StoreLocal{
+			LocalIndex: arrayLocal.Index,
+			Exp: &ir.UnOpExpr{
+				Op:   ir.UnOpTransfer,
+				Expr: compiler.compileExpression(statement.Value),
+			},
+		},
+		&ir.StoreLocal{
+			LocalIndex: indexLocal.Index,
+			Exp:        intConstant(0),
+		},
+		&ir.StoreLocal{
+			LocalIndex: countLocal.Index,
+			Exp: &ir.GetMember{
+				Target: &ir.CopyLocal{
+					LocalIndex: arrayLocal.Index,
+				},
+				Name: "length",
+			},
+		},
+	}
+
+	compiler.activations.PushNewWithCurrent()
+	defer compiler.activations.Pop()
+
+	// NOTE: the element type is not recorded in the elaboration
+	valueLocal := compiler.declareLocal(statement.Identifier.Identifier, nil)
+
+	var indexVariableLocal *Local
+	if statement.Index != nil {
+		indexVariableLocal = compiler.declareLocal(statement.Index.Identifier, sema.IntType)
+	}
+
+	loop := compiler.compileLoop(
+		func() []ir.Stmt {
+			prologue := []ir.Stmt{
+				&ir.BranchIf{
+					Exp: &ir.BinOpExpr{
+						Op: ir.BinOpGreaterEqual,
+						Left: &ir.CopyLocal{
+							LocalIndex: indexLocal.Index,
+						},
+						Right: &ir.CopyLocal{
+							LocalIndex: countLocal.Index,
+						},
+					},
+					Index: 1,
+				},
+				&ir.StoreLocal{
+					LocalIndex: valueLocal.Index,
+					Exp: &ir.GetIndex{
+						Target: &ir.CopyLocal{
+							LocalIndex: arrayLocal.Index,
+						},
+						Index: &ir.CopyLocal{
+							LocalIndex: indexLocal.Index,
+						},
+					},
+				},
+			}
+
+			if indexVariableLocal != nil {
+				prologue = append(prologue,
+					&ir.StoreLocal{
+						LocalIndex: indexVariableLocal.Index,
+						Exp: &ir.CopyLocal{
+							LocalIndex: indexLocal.Index,
+						},
+					},
+				)
+			}
+
+			// NOTE: increment before the body,
+			// so continue statements do not need to
+
+			return append(prologue,
+				&ir.StoreLocal{
+					LocalIndex: indexLocal.Index,
+					Exp: &ir.BinOpExpr{
+						Op: ir.BinOpPlus,
+						Left: &ir.CopyLocal{
+							LocalIndex: indexLocal.Index,
+						},
+						Right: intConstant(1),
+					},
+				},
+			)
+		},
+		statement.Block,
+	)
+
+	stmts = append(stmts, loop)
+
+	return &ir.Sequence{
+		Stmts: stmts,
+	}
+}
+
+func (compiler *Compiler) VisitEmitStatement(statement *ast.EmitStatement) ast.Repr {
+	return &ir.Emit{
+		Exp: compiler.compileExpression(statement.InvocationExpression),
+	}
+}
+
+// VisitSwitchStatement compiles a switch statement to a chain of if-statements,
+// which are nested in a block, so break statements can branch out of it
+func (compiler *Compiler) VisitSwitchStatement(statement *ast.SwitchStatement) ast.Repr {
+	testLocal := compiler.declareTemporaryLocal(ir.ValTypeAny)
+
+	var cases ir.Stmt
+
+	compiler.withNestedControl(func() {
+		compiler.loops = append(compiler.loops, loopTargets{
+			breakDepth: compiler.depth,
+		})
+		defer func() {
+			compiler.loops = compiler.loops[:len(compiler.loops)-1]
+		}()
+
+		cases = compiler.compileSwitchCases(testLocal, statement.Cases)
+	})
+
+	return &ir.Sequence{
+		Stmts: []ir.Stmt{
+			&ir.StoreLocal{
+				LocalIndex: testLocal.Index,
+				Exp:        compiler.compileExpression(statement.Expression),
+			},
+			&ir.Block{
+				Stmts: []ir.Stmt{
+					cases,
+				},
+			},
+		},
+	}
+}
+
+func (compiler *Compiler) compileSwitchCases(testLocal *Local, cases []*ast.SwitchCase) ir.Stmt {
+	if len(cases) == 0 {
+		return nil
+	}
+
+	switchCase := cases[0]
+
+	// The default case is always the last case
+
+	if switchCase.Expression == nil {
+		return compiler.compileSwitchCaseStatements(switchCase)
+	}
+
+	result := &ir.If{
+		Test: &ir.BinOpExpr{
+			Op: ir.BinOpEqual,
+			Left: &ir.CopyLocal{
+				LocalIndex: testLocal.Index,
+			},
+			Right: compiler.compileExpression(switchCase.Expression),
+		},
+	}
+
+	compiler.withNestedControl(func() {
+		result.Then = compiler.compileSwitchCaseStatements(switchCase)
+		result.Else = compiler.compileSwitchCases(testLocal, cases[1:])
+	})
+
+	return result
+}
+
+func (compiler *Compiler) compileSwitchCaseStatements(switchCase *ast.SwitchCase) ir.Stmt {
+	compiler.activations.PushNewWithCurrent()
+	defer compiler.activations.Pop()
+
+	return &ir.Sequence{
+		Stmts: compiler.compileStatements(switchCase.Statements),
+	}
+}
+
+func (compiler *Compiler) VisitVariableDeclaration(declaration *ast.VariableDeclaration) ast.Repr {
+
+	// TODO: potential storage removal
+	// TODO: second value
+
+	if declaration.SecondValue != nil {
+		compiler.unsupported("variable declaration with second value", declaration)
+	}
+
+	identifier := declaration.Identifier.Identifier
+	valueType := compiler.elaboration().VariableDeclarationValueTypes[declaration]
+	targetType := compiler.elaboration().VariableDeclarationTargetTypes[declaration]
+	// NOTE: compile the value before declaring the local,
+	// the value might refer to a variable with the same name in an outer scope
+	exp := compiler.compileConvertedExpression(declaration.Value, valueType, targetType)
+
+	local := compiler.declareLocal(identifier, targetType)
+
+	return &ir.StoreLocal{
+		LocalIndex: local.Index,
+		Exp:        exp,
+	}
+}
+
+func (compiler *Compiler) VisitAssignmentStatement(statement *ast.AssignmentStatement) ast.Repr {
+	valueType := compiler.elaboration().AssignmentStatementValueTypes[statement]
+	targetType := compiler.elaboration().AssignmentStatementTargetTypes[statement]
+
+	exp := compiler.compileConvertedExpression(statement.Value, valueType, targetType)
+
+	return compiler.compileAssignment(statement.Target, exp)
+}
+
+// compileAssignment returns a statement which assigns the given value
+// to the given target expression, i.e. a local, a member, or an index
+func (compiler *Compiler) compileAssignment(target ast.Expression, value ir.Expr) ir.Stmt {
+	switch target := target.(type) {
+	case *ast.IdentifierExpression:
+		name := target.Identifier.Identifier
+		local := compiler.findLocal(name)
+		if local == nil {
+			return &ir.SetGlobal{
+				Name: name,
+				Exp:  value,
+			}
+		}
+		return &ir.StoreLocal{
+			LocalIndex: local.Index,
+			Exp:        value,
+		}
+
+	case *ast.MemberExpression:
+		return &ir.SetMember{
+			Target: compiler.compileExpression(target.Expression),
+			Name:   target.Identifier.Identifier,
+			Exp:    value,
+		}
+
+	case *ast.IndexExpression:
+		return &ir.SetIndex{
+			Target: compiler.compileExpression(target.TargetExpression),
+			Index:  compiler.compileExpression(target.IndexingExpression),
+			Exp:    value,
+		}
+
+	default:
+		panic(errors.NewUnreachableError())
+	}
+}
+
+// VisitSwapStatement compiles a swap statement:
+// The target and index sub-expressions of both sides are evaluated once into temporary locals,
+// then both values are read, and finally written to the other side
+func (compiler *Compiler) VisitSwapStatement(statement *ast.SwapStatement) ast.Repr {
+	leftType := compiler.elaboration().SwapStatementLeftTypes[statement]
+	rightType := compiler.elaboration().SwapStatementRightTypes[statement]
+
+	var stmts []ir.Stmt
+
+	leftGet, leftSet := compiler.compileSwapSide(statement.Left, &stmts)
+	rightGet, rightSet := compiler.compileSwapSide(statement.Right, &stmts)
+
+	leftValueLocal := compiler.declareTemporaryLocal(compileValueType(leftType))
+	rightValueLocal := compiler.declareTemporaryLocal(compileValueType(rightType))
+
+	stmts = append(stmts,
+		&ir.StoreLocal{
+			LocalIndex: leftValueLocal.Index,
+			Exp:        leftGet,
+		},
+		&ir.StoreLocal{
+			LocalIndex: rightValueLocal.Index,
+			Exp:        rightGet,
+		},
+		leftSet(
+			compiler.convert(
+				&ir.MoveLocal{LocalIndex: rightValueLocal.Index},
+				rightType,
+				leftType,
+			),
+		),
+		rightSet(
+			compiler.convert(
+				&ir.MoveLocal{LocalIndex: leftValueLocal.Index},
+				leftType,
+				rightType,
+			),
+		),
+	)
+
+	return &ir.Sequence{
+		Stmts: stmts,
+	}
+}
+
+func (compiler *Compiler) compileSwapSide(
+	expression ast.Expression,
+	stmts *[]ir.Stmt,
+) (
+	get ir.Expr,
+	set func(ir.Expr) ir.Stmt,
+) {
+	evaluateOnce := func(expression ast.Expression) *Local {
+		local := compiler.declareTemporaryLocal(ir.ValTypeAny)
+		*stmts = append(*stmts, &ir.StoreLocal{
+			LocalIndex: local.Index,
+			Exp:        compiler.compileExpression(expression),
+		})
+		return local
+	}
+
+	switch expression := expression.(type) {
+	case *ast.IdentifierExpression:
+		name := expression.Identifier.Identifier
+		local := compiler.findLocal(name)
+		if local == nil {
+			get = &ir.GetGlobal{
+				Name: name,
+			}
+			set = func(value ir.Expr) ir.Stmt {
+				return &ir.SetGlobal{
+					Name: name,
+					Exp:  value,
+				}
+			}
+			break
+		}
+		get = &ir.MoveLocal{
+			LocalIndex: local.Index,
+		}
+		set = func(value ir.Expr) ir.Stmt {
+			return &ir.StoreLocal{
+				LocalIndex: local.Index,
+				Exp:        value,
+			}
+		}
+
+	case *ast.MemberExpression:
+		targetLocal := evaluateOnce(expression.Expression)
+		name := expression.Identifier.Identifier
+		get = &ir.GetMember{
+			Target: &ir.CopyLocal{LocalIndex: targetLocal.Index},
+			Name:   name,
+		}
+		set = func(value ir.Expr) ir.Stmt {
+			return &ir.SetMember{
+				Target: &ir.CopyLocal{LocalIndex: targetLocal.Index},
+				Name:   name,
+				Exp:    value,
+			}
+		}
+
+	case *ast.IndexExpression:
+		targetLocal := evaluateOnce(expression.TargetExpression)
+		indexLocal := evaluateOnce(expression.IndexingExpression)
+		get = &ir.GetIndex{
+			Target: &ir.CopyLocal{LocalIndex: targetLocal.Index},
+			Index:  &ir.CopyLocal{LocalIndex: indexLocal.Index},
+		}
+		set = func(value ir.Expr) ir.Stmt {
+			return &ir.SetIndex{
+				Target: &ir.CopyLocal{LocalIndex: targetLocal.Index},
+				Index:  &ir.CopyLocal{LocalIndex: indexLocal.Index},
+				Exp:    value,
+			}
+		}
+
+	default:
+		panic(errors.NewUnreachableError())
+	}
+
+	return
+}
+
+func (compiler *Compiler) VisitExpressionStatement(statement *ast.ExpressionStatement) ast.Repr {
+	return &ir.Drop{
+		Exp: compiler.compileExpression(statement.Expression),
+	}
+}
+
+func (compiler *Compiler) compileExpression(expression ast.Expression) ir.Expr {
+	return expression.Accept(compiler).(ir.Expr)
+}
+
+// compileTransferredExpression compiles the given expression,
+// and transfers the resulting value, if it is not a primitive value.
+// Like in the interpreter, values are transferred when they are
+// stored in a variable, passed as an argument, or stored in a container
+func (compiler *Compiler) compileTransferredExpression(expression ast.Expression, valueType sema.Type) ir.Expr {
+	exp := compiler.compileExpression(expression)
+
+	if !isTransferred(valueType) {
+		return exp
+	}
+
+	return &ir.UnOpExpr{
+		Op:   ir.UnOpTransfer,
+		Expr: exp,
+	}
+}
+
+// compileConvertedExpression compiles the given expression,
+// transfers the resulting value, and converts it from the given value type
+// to the given target type
+func (compiler *Compiler) compileConvertedExpression(
+	expression ast.Expression,
+	valueType sema.Type,
+	targetType sema.Type,
+) ir.Expr {
+	exp := compiler.compileTransferredExpression(expression, valueType)
+	return compiler.convert(exp, valueType, targetType)
+}
+
+// convert boxes the given expression into optionals,
+// if the target type is an optional type with more levels of optionality
+// than the value type.
+//
+// If the value type is not optional, the value is boxed statically.
+// Otherwise, like in the interpreter, the value is boxed at run-time,
+// as a value of an optional type may have fewer levels of optionality,
+// e.g. the result of a conditional expression with an optional type
+func (compiler *Compiler) convert(exp ir.Expr, valueType, targetType sema.Type) ir.Expr {
+	if valueType == nil || targetType == nil {
+		return exp
+	}
+
+	// NOTE: nil is never boxed
+	if valueType.Equal(sema.NilType) {
+		return exp
+	}
+
+	targetDepth := optionalDepth(targetType)
+	if targetDepth == 0 {
+		return exp
+	}
+
+	valueDepth := optionalDepth(valueType)
+	if valueDepth > 0 || mayBeOptional(valueType) {
+		return &ir.Box{
+			Exp:  exp,
+			Type: targetType,
+		}
+	}
+
+	for depth := targetDepth; depth > 0; depth-- {
+		exp = &ir.UnOpExpr{
+			Op:   ir.UnOpSome,
+			Expr: exp,
+		}
+	}
+
+	return exp
+}
+
+// mayBeOptional returns true if values of the given non-optional type
+// may be optional values, i.e. the type is a top type
+func mayBeOptional(ty sema.Type) bool {
+	switch ty {
+	case sema.AnyType,
+		sema.AnyStructType,
+		sema.AnyResourceType:
+
+		return true
+	}
+
+	return false
+}
+
+// optionalType returns the type wrapped in the given optional type
+func optionalType(ty sema.Type) sema.Type {
+	if optionalType, ok := ty.(*sema.OptionalType); ok {
+		return optionalType.Type
+	}
+	return ty
+}
+
+func optionalDepth(ty sema.Type) int {
+	depth := 0
+	for {
+		optionalType, ok := ty.(*sema.OptionalType)
+		if !ok {
+			return depth
+		}
+		depth++
+		ty = optionalType.Type
+	}
+}
+
+// isTransferred returns true if values of the given type must be transferred
+// when they are stored, i.e. they are not immutable primitive values
+func isTransferred(ty sema.Type) bool {
+	switch ty := ty.(type) {
+	case *sema.OptionalType:
+		return isTransferred(ty.Type)
+
+	case *sema.CompositeType:
+		return ty.Kind != common.CompositeKindContract
+
+	case sema.ArrayType,
+		*sema.DictionaryType,
+		*sema.RestrictedType:
+
+		return true
+	}
+
+	switch ty {
+	case sema.AnyType,
+		sema.AnyStructType,
+		sema.AnyResourceType:
+
+		return true
+	}
+
+	return false
+}
+
+func (compiler *Compiler) VisitBoolExpression(expression *ast.BoolExpression) ast.Repr {
+	return &ir.Const{
+		Constant: ir.Bool{
+			Value: expression.Value,
+		},
+	}
+}
+
+func (compiler *Compiler) VisitNilExpression(_ *ast.NilExpression) ast.Repr {
+	return &ir.Const{
+		Constant: ir.Nil{},
+	}
+}
+
+func (compiler *Compiler) VisitIntegerExpression(expression *ast.IntegerExpression) ast.Repr {
+	ty := compiler.elaboration().IntegerExpressionType[expression]
+
+	if _, ok := ty.(*sema.AddressType); ok {
+		return &ir.Const{
+			Constant: ir.Address{
+				Value: common.MustBytesToAddress(expression.Value.Bytes()),
+			},
+		}
+	}
+
+	return integerConstant(ty, expression.Value)
+}
+
+// integerConstant returns a constant for the given integer value of the given type
+func integerConstant(ty sema.Type, integer *big.Int) ir.Expr {
+	value := compileIntegerValue(integer)
+
+	switch ty {
+	case nil,
+		sema.IntType,
+		sema.IntegerType,
+		sema.SignedIntegerType:
+
+		return &ir.Const{
+			Constant: ir.Int{
+				Value: value,
+			},
+		}
+	}
+
+	return &ir.Const{
+		Constant: ir.Number{
+			Type:  ty,
+			Value: value,
+		},
+	}
+}
+
+// compileIntegerValue encodes the given integer
+// as a sign byte (0 for negative, 1 for positive)
+// followed by the big-endian bytes of the magnitude
+func compileIntegerValue(integer *big.Int) []byte {
+	var value []byte
+
+	if integer.Sign() < 0 {
+		value = append(value, 0)
+	} else {
+		value = append(value, 1)
+	}
+
+	return append(value,
+		integer.Bytes()...,
+	)
+}
+
+func intConstant(value int64) ir.Expr {
+	return &ir.Const{
+		Constant: ir.Int{
+			Value: compileIntegerValue(big.NewInt(value)),
+		},
+	}
+}
+
+func (compiler *Compiler) VisitFixedPointExpression(expression *ast.FixedPointExpression) ast.Repr {
+	ty := compiler.elaboration().FixedPointExpression[expression]
+
+	switch ty {
+	case sema.SignedFixedPointType:
+		ty = sema.Fix64Type
+
+	case sema.FixedPointType:
+		if expression.Negative {
+			ty = sema.Fix64Type
+		} else {
+			ty = sema.UFix64Type
+		}
+	}
+
+	value := fixedpoint.ConvertToFixedPointBigInt(
+		expression.Negative,
+		expression.UnsignedInteger,
+		expression.Fractional,
+		expression.Scale,
+		sema.Fix64Scale,
+	)
+
+	return &ir.Const{
+		Constant: ir.Number{
+			Type:  ty,
+			Value: compileIntegerValue(value),
+		},
+	}
+}
+
+func (compiler *Compiler) VisitArrayExpression(expression *ast.ArrayExpression) ast.Repr {
+	argumentTypes := compiler.elaboration().ArrayExpressionArgumentTypes[expression]
+	arrayType := compiler.elaboration().ArrayExpressionArrayType[expression]
+	elementType := arrayType.ElementType(false)
+
+	elements := make([]ir.Expr, len(expression.Values))
+	for i, value := range expression.Values {
+		elements[i] = compiler.compileConvertedExpression(value, argumentTypes[i], elementType)
+	}
+
+	return &ir.NewArray{
+		Type:     arrayType,
+		Elements: elements,
+	}
+}
+
+func (compiler *Compiler) VisitDictionaryExpression(expression *ast.DictionaryExpression) ast.Repr {
+	dictionaryType := compiler.elaboration().DictionaryExpressionType[expression]
+	entryTypes := compiler.elaboration().DictionaryExpressionEntryTypes[expression]
+
+	entries := make([]ir.DictionaryEntry, len(expression.Entries))
+	for i, entry := range expression.Entries {
+		entryType := entryTypes[i]
+		entries[i] = ir.DictionaryEntry{
+			Key: compiler.compileConvertedExpression(
+				entry.Key,
+				entryType.KeyType,
+				dictionaryType.KeyType,
+			),
+			Value: compiler.compileConvertedExpression(
+				entry.Value,
+				entryType.ValueType,
+				dictionaryType.ValueType,
+			),
+		}
+	}
+
+	return &ir.NewDictionary{
+		Type:    dictionaryType,
+		Entries: entries,
+	}
+}
+
+func (compiler *Compiler) VisitIdentifierExpression(expression *ast.IdentifierExpression) ast.Repr {
+	name := expression.Identifier.Identifier
+	local := compiler.findLocal(name)
+	if local == nil {
+		if _, ok := compiler.globalVariables[name]; !ok {
+			compiler.unsupported("function value", expression)
+		}
+		return &ir.GetGlobal{
+			Name: name,
+		}
+	}
+
+	// NOTE: moves are compiled in VisitUnaryExpression
+
+	return &ir.CopyLocal{
+		LocalIndex: local.Index,
+	}
+}
+
+func (compiler *Compiler) VisitInvocationExpression(expression *ast.InvocationExpression) ast.Repr {
+	switch invokedExpression := expression.InvokedExpression.(type) {
+	case *ast.IdentifierExpression:
+		name := invokedExpression.Identifier.Identifier
+
+		if compiler.findLocal(name) != nil {
+			compiler.unsupported("invocation of function value", invokedExpression)
+		}
+
+		if _, ok := compiler.globalVariables[name]; ok {
+			compiler.unsupported("invocation of function value", invokedExpression)
+		}
+
+		arguments := compiler.compileArguments(expression)
+
+		global := compiler.findGlobal(name)
+		if global != nil {
+			return &ir.Call{
+				FunctionIndex: global.index,
+				Arguments:     arguments,
+			}
+		}
+
+		if _, ok := compiler.elaboration().EffectivePredeclaredValues[name]; !ok {
+			if sema.BaseValueActivation.Find(name) == nil {
+				compiler.unsupported("invocation of imported function", invokedExpression)
+			}
+		}
+
+		return &ir.CallBuiltin{
+			Name:      name,
+			Arguments: arguments,
+		}
+
+	case *ast.MemberExpression:
+		return compiler.compileMemberInvocation(expression, invokedExpression)
+
+	default:
+		compiler.unsupported("invocation of function value", invokedExpression)
+		return nil
+	}
+}
+
+// compileMemberInvocation compiles the invocation of a member:
+// Member functions of composites declared in the program, interface functions implemented by them,
+// and nested composite constructors are compiled to calls of the compiled functions,
+// all other members are invoked by the run-time
+func (compiler *Compiler) compileMemberInvocation(
+	expression *ast.InvocationExpression,
+	memberExpression *ast.MemberExpression,
+) ir.Expr {
+	memberInfo := compiler.elaboration().MemberExpressionMemberInfos[memberExpression]
+	member := memberInfo.Member
+	name := memberExpression.Identifier.Identifier
+
+	if interfaceType, ok := member.ContainerType.(*sema.InterfaceType); ok {
+		return compiler.compileInterfaceFunctionCall(expression, memberExpression, interfaceType)
+	}
+
+	if containerType, ok := member.ContainerType.(*sema.CompositeType); ok {
+
+		// Nested composite constructor, e.g. `C.R()`
+
+		if member.DeclarationKind != common.DeclarationKindFunction {
+			qualifiedName := containerType.QualifiedIdentifier() + "." + name
+			if global, ok := compiler.globals[qualifiedName]; ok {
+				return &ir.Call{
+					FunctionIndex: global.index,
+					Arguments:     compiler.compileArguments(expression),
+				}
+			}
+		}
+
+		// Member function of a composite declared in the program
+
+		if function, ok := compiler.members[containerType][name]; ok {
+			return compiler.compileMemberFunctionCall(expression, memberExpression, function)
+		}
+	}
+
+	return &ir.CallMember{
+		Target:    compiler.compileExpression(memberExpression.Expression),
+		Name:      name,
+		Optional:  memberExpression.Optional,
+		Arguments: compiler.compileArguments(expression),
+	}
+}
+
+// compileMemberFunctionCall compiles the call of a compiled member function.
+// The receiver is passed as the first argument.
+// For optional chaining, the function is only called if the receiver is not nil,
+// and the result is wrapped in an optional
+func (compiler *Compiler) compileMemberFunctionCall(
+	expression *ast.InvocationExpression,
+	memberExpression *ast.MemberExpression,
+	function *function,
+) ir.Expr {
+	receiver := compiler.compileExpression(memberExpression.Expression)
+
+	if !memberExpression.Optional {
+		return &ir.Call{
+			FunctionIndex: function.index,
+			Arguments: append(
+				[]ir.Expr{receiver},
+				compiler.compileArguments(expression)...,
+			),
+		}
+	}
+
+	receiverLocal := compiler.declareTemporaryLocal(ir.ValTypeOptional)
+
+	result := &ir.UnOpExpr{
+		Op: ir.UnOpSome,
+		Expr: &ir.Call{
+			FunctionIndex: function.index,
+			Arguments: append(
+				[]ir.Expr{
+					&ir.UnOpExpr{
+						Op: ir.UnOpForce,
+						Expr: &ir.CopyLocal{
+							LocalIndex: receiverLocal.Index,
+						},
+					},
+				},
+				compiler.compileArguments(expression)...,
+			),
+		},
+	}
+
+	return &ir.Conditional{
+		Test: &ir.UnOpExpr{
+			Op: ir.UnOpIsNil,
+			Expr: &ir.TeeLocal{
+				LocalIndex: receiverLocal.Index,
+				Exp:        receiver,
+			},
+		},
+		Then: &ir.Const{
+			Constant: ir.Nil{},
+		},
+		Else: result,
+	}
+}
+
+// compileInterfaceFunctionCall compiles the call of an interface function.
+// The receiver is evaluated once, and its run-time type is tested against
+// each composite declared in the program which conforms to the interface:
+// The compiled member function of the matching composite is called,
+// or, if there is none, e.g. for an imported composite, the member is invoked by the run-time.
+// For optional chaining, the function is only called if the receiver is not nil,
+// and the result is wrapped in an optional
+func (compiler *Compiler) compileInterfaceFunctionCall(
+	expression *ast.InvocationExpression,
+	memberExpression *ast.MemberExpression,
+	interfaceType *sema.InterfaceType,
+) ir.Expr {
+	name := memberExpression.Identifier.Identifier
+
+	var functions []*function
+	var compositeTypes []*sema.CompositeType
+
+	for _, compositeType := range compiler.composites {
+		if !compositeType.ExplicitInterfaceConformanceSet().Includes(interfaceType) {
+			continue
+		}
+		function, ok := compiler.members[compositeType][name]
+		if !ok {
+			continue
+		}
+		functions = append(functions, function)
+		compositeTypes = append(compositeTypes, compositeType)
+	}
+
+	if len(functions) == 0 {
+		return &ir.CallMember{
+			Target:    compiler.compileExpression(memberExpression.Expression),
+			Name:      name,
+			Optional:  memberExpression.Optional,
+			Arguments: compiler.compileArguments(expression),
+		}
+	}
+
+	receiverValType := ir.ValTypeAny
+	if memberExpression.Optional {
+		receiverValType = ir.ValTypeOptional
+	}
+	receiverLocal := compiler.declareTemporaryLocal(receiverValType)
+
+	var storedReceiver ir.Expr = &ir.TeeLocal{
+		LocalIndex: receiverLocal.Index,
+		Exp:        compiler.compileExpression(memberExpression.Expression),
+	}
+
+	var receiver ir.Expr = &ir.CopyLocal{
+		LocalIndex: receiverLocal.Index,
+	}
+
+	if memberExpression.Optional {
+		receiver = &ir.UnOpExpr{
+			Op:   ir.UnOpForce,
+			Expr: receiver,
+		}
+	}
+
+	var result ir.Expr = &ir.CallMember{
+		Target:    receiver,
+		Name:      name,
+		Arguments: compiler.compileArguments(expression),
+	}
+
+	for i := len(functions) - 1; i >= 0; i-- {
+
+		// The receiver is stored when it is tested first,
+		// or when it is tested for nil for optional chaining
+
+		testedReceiver := receiver
+		if i == 0 && !memberExpression.Optional {
+			testedReceiver = storedReceiver
+		}
+
+		result = &ir.Conditional{
+			Test: &ir.IsComposite{
+				Exp:  testedReceiver,
+				Type: compositeTypes[i],
+			},
+			Then: &ir.Call{
+				FunctionIndex: functions[i].index,
+				Arguments: append(
+					[]ir.Expr{receiver},
+					compiler.compileArguments(expression)...,
+				),
+			},
+			Else: result,
+		}
+	}
+
+	if !memberExpression.Optional {
+		return result
+	}
+
+	return &ir.Conditional{
+		Test: &ir.UnOpExpr{
+			Op:   ir.UnOpIsNil,
+			Expr: storedReceiver,
+		},
+		Then: &ir.Const{
+			Constant: ir.Nil{},
+		},
+		Else: &ir.UnOpExpr{
+			Op:   ir.UnOpSome,
+			Expr: result,
+		},
+	}
+}
+
+func (compiler *Compiler) compileArguments(expression *ast.InvocationExpression) []ir.Expr {
+	argumentTypes := compiler.elaboration().InvocationExpressionArgumentTypes[expression]
+	parameterTypes := compiler.elaboration().InvocationExpressionParameterTypes[expression]
+
+	arguments := make([]ir.Expr, len(expression.Arguments))
+	for i, argument := range expression.Arguments {
+		argumentType := argumentTypes[i]
+
+		// NOTE: the parameter types of some built-in functions are not available
+		var parameterType sema.Type
+		if i < len(parameterTypes) {
+			parameterType = parameterTypes[i]
+		}
+
+		arguments[i] = compiler.compileConvertedExpression(
+			argument.Expression,
+			argumentType,
+			parameterType,
+		)
+	}
+	return arguments
+}
+
+func (compiler *Compiler) VisitMemberExpression(expression *ast.MemberExpression) ast.Repr {
+
+	// Enum case, e.g. `E.a`
+
+	if caseFunction := compiler.enumCase(expression); caseFunction != nil {
+		return &ir.Call{
+			FunctionIndex: caseFunction.index,
+		}
+	}
+
+	return &ir.GetMember{
+		Target:   compiler.compileExpression(expression.Expression),
+		Name:     expression.Identifier.Identifier,
+		Optional: expression.Optional,
+	}
+}
+
+// enumCase returns the compiled function which creates the enum case
+// the given member expression refers to, if any.
+// Enum cases are members of the enum constructor
+func (compiler *Compiler) enumCase(expression *ast.MemberExpression) *function {
+	memberInfo := compiler.elaboration().MemberExpressionMemberInfos[expression]
+	if memberInfo.Member == nil {
+		return nil
+	}
+
+	constructorType, ok := memberInfo.Member.ContainerType.(*sema.FunctionType)
+	if !ok {
+		return nil
+	}
+
+	returnType, ok := constructorType.ReturnTypeAnnotation.Type.(*sema.OptionalType)
+	if !ok {
+		return nil
+	}
+
+	compositeType, ok := returnType.Type.(*sema.CompositeType)
+	if !ok {
+		return nil
+	}
+
+	return compiler.enumCases[compositeType][expression.Identifier.Identifier]
+}
+
+func (compiler *Compiler) VisitIndexExpression(expression *ast.IndexExpression) ast.Repr {
+	return &ir.GetIndex{
+		Target: compiler.compileExpression(expression.TargetExpression),
+		Index:  compiler.compileExpression(expression.IndexingExpression),
+	}
+}
+
+func (compiler *Compiler) VisitConditionalExpression(expression *ast.ConditionalExpression) ast.Repr {
+	return &ir.Conditional{
+		Test: compiler.compileExpression(expression.Test),
+		Then: compiler.compileExpression(expression.Then),
+		Else: compiler.compileExpression(expression.Else),
+	}
+}
+
+func (compiler *Compiler) VisitUnaryExpression(expression *ast.UnaryExpression) ast.Repr {
+	switch expression.Operation {
+	case ast.OperationMove:
+		if identifierExpression, ok := expression.Expression.(*ast.IdentifierExpression); ok {
+			local := compiler.findLocal(identifierExpression.Identifier.Identifier)
+			if local == nil {
+				// NOTE: the checker does not allow moving global resources
+				return compiler.compileExpression(identifierExpression)
+			}
+			return &ir.MoveLocal{
+				LocalIndex: local.Index,
+			}
+		}
+		return compiler.compileExpression(expression.Expression)
+
+	case ast.OperationNegate:
+		return &ir.UnOpExpr{
+			Op:   ir.UnOpNot,
+			Expr: compiler.compileExpression(expression.Expression),
+		}
+
+	case ast.OperationMinus:
+		return &ir.UnOpExpr{
+			Op:   ir.UnOpNegate,
+			Expr: compiler.compileExpression(expression.Expression),
+		}
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+func (compiler *Compiler) VisitBinaryExpression(expression *ast.BinaryExpression) ast.Repr {
+	switch expression.Operation {
+	case ast.OperationAnd:
+		// a && b => a ? b : false
+		return &ir.Conditional{
+			Test: compiler.compileExpression(expression.Left),
+			Then: compiler.compileExpression(expression.Right),
+			Else: &ir.Const{
+				Constant: ir.Bool{Value: false},
+			},
+		}
+
+	case ast.OperationOr:
+		// a || b => a ? true : b
+		return &ir.Conditional{
+			Test: compiler.compileExpression(expression.Left),
+			Then: &ir.Const{
+				Constant: ir.Bool{Value: true},
+			},
+			Else: compiler.compileExpression(expression.Right),
+		}
+
+	case ast.OperationNilCoalesce:
+		return compiler.compileNilCoalescing(expression)
+	}
+
+	op := compileBinaryOperation(expression.Operation)
+	left := expression.Left.Accept(compiler).(ir.Expr)
+	right := expression.Right.Accept(compiler).(ir.Expr)
+
+	return &ir.BinOpExpr{
+		Op:    op,
+		Left:  left,
+		Right: right,
+	}
+}
+
+// compileNilCoalescing compiles a nil-coalescing expression:
+//
+//	a ?? b => (tmp = a) == nil ? b : tmp!
+func (compiler *Compiler) compileNilCoalescing(expression *ast.BinaryExpression) ir.Expr {
+	rightType := compiler.elaboration().BinaryExpressionRightTypes[expression]
+	resultType := compiler.elaboration().BinaryExpressionResultTypes[expression]
+
+	leftLocal := compiler.declareTemporaryLocal(ir.ValTypeOptional)
+
+	return &ir.Conditional{
+		Test: &ir.UnOpExpr{
+			Op: ir.UnOpIsNil,
+			Expr: &ir.TeeLocal{
+				LocalIndex: leftLocal.Index,
+				Exp:        compiler.compileExpression(expression.Left),
+			},
+		},
+		Then: compiler.convert(
+			compiler.compileExpression(expression.Right),
+			rightType,
+			resultType,
+		),
+		Else: &ir.UnOpExpr{
+			Op: ir.UnOpForce,
+			Expr: &ir.MoveLocal{
+				LocalIndex: leftLocal.Index,
+			},
+		},
+	}
+}
+
+func (compiler *Compiler) VisitFunctionExpression(expression *ast.FunctionExpression) ast.Repr {
+	compiler.unsupported("function expression", expression)
+	return nil
+}
+
+func (compiler *Compiler) VisitStringExpression(e *ast.StringExpression) ast.Repr {
+	if compiler.elaboration().StringExpressionType[e] == sema.CharacterType {
+		return &ir.Const{
+			Constant: ir.Character{
+				Value: e.Value,
+			},
+		}
+	}
+
+	return &ir.Const{
+		Constant: ir.String{
+			Value: e.Value,
+		},
+	}
+}
+
+func (compiler *Compiler) VisitStringTemplateExpression(expression *ast.StringTemplateExpression) ast.Repr {
+	exps := make([]ir.Expr, len(expression.Expressions))
+	for i, embeddedExpression := range expression.Expressions {
+		exps[i] = compiler.compileExpression(embeddedExpression)
+	}
+
+	return &ir.StringTemplate{
+		Values: expression.Values,
+		Exps:   exps,
+	}
+}
+
+func (compiler *Compiler) VisitCastingExpression(expression *ast.CastingExpression) ast.Repr {
+	exp := compiler.compileExpression(expression.Expression)
+	targetType := compiler.elaboration().CastingTargetTypes[expression]
+
+	switch expression.Operation {
+	case ast.OperationCast:
+		valueType := compiler.elaboration().CastingStaticValueTypes[expression]
+		return compiler.convert(exp, valueType, targetType)
+
+	case ast.OperationFailableCast:
+		return &ir.Cast{
+			Exp:  exp,
+			Type: targetType,
+		}
+
+	case ast.OperationForceCast:
+		return &ir.Cast{
+			Exp:   exp,
+			Type:  targetType,
+			Force: true,
+		}
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+func (compiler *Compiler) VisitCreateExpression(expression *ast.CreateExpression) ast.Repr {
+	return compiler.compileExpression(expression.InvocationExpression)
+}
+
+func (compiler *Compiler) VisitDestroyExpression(expression *ast.DestroyExpression) ast.Repr {
+	exp := compiler.compileExpression(expression.Expression)
+
+	// Call the destructor of a resource declared in the program first.
+	// The compiled destructor returns the destroyed resource.
+	// NOTE: destructors of nested resources are not called yet
+
+	if identifierExpression, ok := expression.Expression.(*ast.IdentifierExpression); ok {
+		local := compiler.findLocal(identifierExpression.Identifier.Identifier)
+		if local == nil {
+			// NOTE: the checker does not allow destroying global resources
+			panic(errors.NewUnreachableError())
+		}
+		if compositeType, ok := local.SemaType.(*sema.CompositeType); ok {
+			if destructor, ok := compiler.members[compositeType][destructorName]; ok {
+				exp = &ir.Call{
+					FunctionIndex: destructor.index,
+					Arguments:     []ir.Expr{exp},
+				}
+			}
+		}
+	}
+
+	return &ir.Destroy{
 		Exp: exp,
 	}
 }
 
-func (compiler *Compiler) VisitBreakStatement(_ *ast.BreakStatement) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
-}
+func (compiler *Compiler) VisitReferenceExpression(expression *ast.ReferenceExpression) ast.Repr {
+	borrowType := compiler.elaboration().ReferenceExpressionBorrowTypes[expression]
 
-func (compiler *Compiler) VisitContinueStatement(_ *ast.ContinueStatement) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+	return &ir.Reference{
+		Exp:  compiler.compileExpression(expression.Expression),
+		Type: borrowType,
+	}
 }
 
-func (compiler *Compiler) VisitIfStatement(_ *ast.IfStatement) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitForceExpression(expression *ast.ForceExpression) ast.Repr {
+	return &ir.UnOpExpr{
+		Op:   ir.UnOpForce,
+		Expr: compiler.compileExpression(expression.Expression),
+	}
 }
 
-func (compiler *Compiler) VisitWhileStatement(_ *ast.WhileStatement) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitPathExpression(expression *ast.PathExpression) ast.Repr {
+	return &ir.Const{
+		Constant: ir.Path{
+			Domain:     common.PathDomainFromIdentifier(expression.Domain.Identifier),
+			Identifier: expression.Identifier.Identifier,
+		},
+	}
 }
 
-func (compiler *Compiler) VisitForStatement(_ *ast.ForStatement) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
-}
+// VisitProgram compiles all functions and composites of the program.
+// All functions are declared first, so they can be called
+// before they are compiled.
+//
+// Global variables are initialized in declaration order
+// by the start function of the program
+func (compiler *Compiler) VisitProgram(program *ast.Program) ast.Repr {
+
+	var compileFunctions []func() *ir.Func
+	var variableDeclarations []*ast.VariableDeclaration
+
+	for _, declaration := range program.Declarations() {
+		switch declaration := declaration.(type) {
+		case *ast.FunctionDeclaration:
+			functionType := compiler.elaboration().FunctionDeclarationFunctionTypes[declaration]
+			name := declaration.Identifier.Identifier
+			function := compiler.declareFunction(name, functionType)
+			compiler.globals[name] = function
+			compileFunctions = append(compileFunctions, func() *ir.Func {
+				return compiler.compileFunctionDeclaration(function, declaration, nil)
+			})
+
+		case *ast.CompositeDeclaration:
+			compileFunctions = append(compileFunctions,
+				compiler.declareComposite(declaration)...,
+			)
+
+		case *ast.InterfaceDeclaration:
+			compiler.checkInterface(declaration)
+
+		case *ast.PragmaDeclaration,
+			*ast.TypeAliasDeclaration:
+
+			continue
+
+		case *ast.ImportDeclaration:
+			compiler.unsupported("import", declaration)
+
+		case *ast.TransactionDeclaration:
+			compiler.unsupported("transaction", declaration)
+
+		case *ast.VariableDeclaration:
+			name := declaration.Identifier.Identifier
+			targetType := compiler.elaboration().VariableDeclarationTargetTypes[declaration]
+			compiler.globalVariables[name] = targetType
+			variableDeclarations = append(variableDeclarations, declaration)
+
+		default:
+			panic(errors.NewUnreachableError())
+		}
+	}
 
-func (compiler *Compiler) VisitEmitStatement(_ *ast.EmitStatement) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
-}
+	if len(variableDeclarations) > 0 {
+		startFunctionType := &sema.FunctionType{
+			ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.VoidType),
+		}
+		function := compiler.declareFunction(startFunctionName, startFunctionType)
+		compileFunctions = append(compileFunctions, func() *ir.Func {
+			return compiler.compileStartFunction(function, variableDeclarations)
+		})
+	}
 
-func (compiler *Compiler) VisitSwitchStatement(_ *ast.SwitchStatement) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+	funcs := make([]*ir.Func, len(compileFunctions))
+	for i, compileFunction := range compileFunctions {
+		funcs[i] = compileFunction()
+	}
+
+	return funcs
 }
 
-func (compiler *Compiler) VisitVariableDeclaration(declaration *ast.VariableDeclaration) ast.Repr {
+// startFunctionName is the name of the compiled function which initializes the global variables.
+// A user-defined function cannot have this name, as it is not a valid identifier
+const startFunctionName = "$start"
 
-	// TODO: potential storage removal
-	// TODO: copy and convert
-	// TODO: second value
+// compileStartFunction compiles the function which initializes the given global variables
+func (compiler *Compiler) compileStartFunction(
+	function *function,
+	declarations []*ast.VariableDeclaration,
+) *ir.Func {
 
-	identifier := declaration.Identifier.Identifier
-	targetType := compiler.Checker.Elaboration.VariableDeclarationTargetTypes[declaration]
-	valType := compileValueType(targetType)
-	local := compiler.declareLocal(identifier, valType)
-	exp := declaration.Value.Accept(compiler).(ir.Expr)
+	compiler.locals = nil
 
-	return &ir.StoreLocal{
-		LocalIndex: local.Index,
-		Exp:        exp,
+	stmts := make([]ir.Stmt, len(declarations))
+
+	for i, declaration := range declarations {
+		if declaration.SecondValue != nil {
+			compiler.unsupported("variable declaration with second value", declaration)
+		}
+
+		valueType := compiler.elaboration().VariableDeclarationValueTypes[declaration]
+		targetType := compiler.elaboration().VariableDeclarationTargetTypes[declaration]
+
+		stmts[i] = &ir.SetGlobal{
+			Name: declaration.Identifier.Identifier,
+			Exp:  compiler.compileConvertedExpression(declaration.Value, valueType, targetType),
+		}
 	}
-}
 
-func (compiler *Compiler) VisitAssignmentStatement(_ *ast.AssignmentStatement) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
-}
+	locals := compileLocals(compiler.locals)
+	compiler.locals = nil
 
-func (compiler *Compiler) VisitSwapStatement(_ *ast.SwapStatement) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+	return &ir.Func{
+		Name:   function.name,
+		Type:   compileFunctionType(function.functionType),
+		Locals: locals,
+		Statement: &ir.Sequence{
+			Stmts: stmts,
+		},
+		Start: true,
+	}
 }
 
-func (compiler *Compiler) VisitExpressionStatement(_ *ast.ExpressionStatement) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
-}
+// checkInterface reports interface declarations which cannot be compiled yet:
+// Interfaces only declare the functions of the conforming composites,
+// but their conditions are not compiled
+func (compiler *Compiler) checkInterface(declaration *ast.InterfaceDeclaration) {
+	for _, functionDeclaration := range declaration.Members.Functions() {
+		if functionDeclaration.FunctionBlock != nil {
+			compiler.unsupported("interface function with conditions", functionDeclaration)
+		}
+	}
 
-func (compiler *Compiler) VisitBoolExpression(_ *ast.BoolExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
-}
+	for _, specialFunctionDeclaration := range declaration.Members.SpecialFunctions() {
+		if specialFunctionDeclaration.FunctionDeclaration.FunctionBlock != nil {
+			compiler.unsupported("interface function with conditions", specialFunctionDeclaration)
+		}
+	}
 
-func (compiler *Compiler) VisitNilExpression(_ *ast.NilExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+	for _, nestedDeclaration := range declaration.Members.Interfaces() {
+		compiler.checkInterface(nestedDeclaration)
+	}
 }
 
-func (compiler *Compiler) VisitIntegerExpression(expression *ast.IntegerExpression) ast.Repr {
-	var value []byte
+// destructorName is the name of the compiled member function for a destructor.
+// A user-defined function cannot have this name, as it is a keyword
+const destructorName = "destroy"
 
-	if expression.Value.Sign() < 0 {
-		value = append(value, 0)
-	} else {
-		value = append(value, 1)
+// declareComposite declares the constructor, the member functions,
+// and the destructor of the given composite declaration, and its nested composite declarations,
+// and returns functions which compile the declared functions
+func (compiler *Compiler) declareComposite(declaration *ast.CompositeDeclaration) []func() *ir.Func {
+	compositeType := compiler.elaboration().CompositeDeclarationTypes[declaration]
+
+	if compositeType.Kind == common.CompositeKindEnum {
+		return compiler.declareEnum(declaration, compositeType)
 	}
 
-	value = append(value,
-		expression.Value.Bytes()...,
-	)
+	compiler.composites = append(compiler.composites, compositeType)
 
-	return &ir.Const{
-		Constant: ir.Int{
-			Value: value,
-		},
+	var compileFunctions []func() *ir.Func
+
+	withComposite := func(f func() *ir.Func) func() *ir.Func {
+		return func() *ir.Func {
+			compiler.compositeTypes = append(compiler.compositeTypes, compositeType)
+			defer func() {
+				compiler.compositeTypes = compiler.compositeTypes[:len(compiler.compositeTypes)-1]
+			}()
+			return f()
+		}
 	}
-}
 
-func (compiler *Compiler) VisitFixedPointExpression(_ *ast.FixedPointExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
-}
+	// Constructor
 
-func (compiler *Compiler) VisitArrayExpression(_ *ast.ArrayExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
-}
+	initializers := declaration.Members.Initializers()
 
-func (compiler *Compiler) VisitDictionaryExpression(_ *ast.DictionaryExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
-}
+	var initializer *ast.SpecialFunctionDeclaration
+	if len(initializers) > 0 {
+		initializer = initializers[0]
+	}
 
-func (compiler *Compiler) VisitIdentifierExpression(expression *ast.IdentifierExpression) ast.Repr {
-	// TODO
-	local := compiler.findLocal(expression.Identifier.Identifier)
-	// TODO: moves
-	return &ir.CopyLocal{
-		LocalIndex: local.Index,
+	constructorType := &sema.FunctionType{
+		Parameters:           compositeType.ConstructorParameters,
+		ReturnTypeAnnotation: sema.NewTypeAnnotation(compositeType),
 	}
-}
 
-func (compiler *Compiler) VisitInvocationExpression(_ *ast.InvocationExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
-}
+	qualifiedIdentifier := compositeType.QualifiedIdentifier()
+	constructor := compiler.declareFunction(qualifiedIdentifier, constructorType)
+	compiler.globals[qualifiedIdentifier] = constructor
+
+	compileFunctions = append(compileFunctions, withComposite(func() *ir.Func {
+		return compiler.compileConstructor(constructor, compositeType, initializer)
+	}))
+
+	// Member functions
+
+	for _, functionDeclaration := range declaration.Members.Functions() {
+		functionDeclaration := functionDeclaration
+		name := functionDeclaration.Identifier.Identifier
+		functionType := compiler.elaboration().FunctionDeclarationFunctionTypes[functionDeclaration]
+		compiler.declareMember(compositeType, name, functionType)
+		function := compiler.members[compositeType][name]
+		compileFunctions = append(compileFunctions, withComposite(func() *ir.Func {
+			return compiler.compileFunctionDeclaration(function, functionDeclaration, compositeType)
+		}))
+	}
 
-func (compiler *Compiler) VisitMemberExpression(_ *ast.MemberExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
-}
+	// Destructor
 
-func (compiler *Compiler) VisitIndexExpression(_ *ast.IndexExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
-}
+	if destructor := declaration.Members.Destructor(); destructor != nil {
+		destructorType := &sema.FunctionType{
+			ReturnTypeAnnotation: sema.NewTypeAnnotation(compositeType),
+		}
+		compiler.declareMember(compositeType, destructorName, destructorType)
+		function := compiler.members[compositeType][destructorName]
+		compileFunctions = append(compileFunctions, withComposite(func() *ir.Func {
+			return compiler.compileDestructor(function, compositeType, destructor)
+		}))
+	}
 
-func (compiler *Compiler) VisitConditionalExpression(_ *ast.ConditionalExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
-}
+	// Nested composites
 
-func (compiler *Compiler) VisitUnaryExpression(_ *ast.UnaryExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+	for _, nestedDeclaration := range declaration.Members.Composites() {
+		compileFunctions = append(compileFunctions, compiler.declareComposite(nestedDeclaration)...)
+	}
+
+	for _, nestedDeclaration := range declaration.Members.Interfaces() {
+		compiler.checkInterface(nestedDeclaration)
+	}
+
+	return compileFunctions
 }
 
-func (compiler *Compiler) VisitBinaryExpression(expression *ast.BinaryExpression) ast.Repr {
-	op := compileBinaryOperation(expression.Operation)
-	left := expression.Left.Accept(compiler).(ir.Expr)
-	right := expression.Right.Accept(compiler).(ir.Expr)
+// declareEnum declares a function for each case of the given enum declaration,
+// which creates the case value, and the enum constructor,
+// which returns the case with the given raw value, if any
+func (compiler *Compiler) declareEnum(
+	declaration *ast.CompositeDeclaration,
+	compositeType *sema.CompositeType,
+) []func() *ir.Func {
 
-	return &ir.BinOpExpr{
-		Op:    op,
-		Left:  left,
-		Right: right,
+	var compileFunctions []func() *ir.Func
+
+	caseType := &sema.FunctionType{
+		ReturnTypeAnnotation: sema.NewTypeAnnotation(compositeType),
 	}
-}
 
-func (compiler *Compiler) VisitFunctionExpression(_ *ast.FunctionExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
-}
+	qualifiedIdentifier := compositeType.QualifiedIdentifier()
+
+	enumCases := declaration.Members.EnumCases()
+	caseFunctions := make([]*function, len(enumCases))
+	compiler.enumCases[compositeType] = map[string]*function{}
+
+	for i, enumCase := range enumCases {
+		i := i
+		name := enumCase.Identifier.Identifier
+		function := compiler.declareFunction(qualifiedIdentifier+"."+name, caseType)
+		caseFunctions[i] = function
+		compiler.enumCases[compositeType][name] = function
+		compileFunctions = append(compileFunctions, func() *ir.Func {
+			return compiler.compileEnumCase(function, compositeType, i)
+		})
+	}
 
-func (compiler *Compiler) VisitStringExpression(e *ast.StringExpression) ast.Repr {
-	return &ir.Const{
-		Constant: ir.String{
-			Value: e.Value,
+	constructorType := &sema.FunctionType{
+		Parameters: []*sema.Parameter{
+			{
+				Identifier:     sema.EnumRawValueFieldName,
+				TypeAnnotation: sema.NewTypeAnnotation(compositeType.EnumRawType),
+			},
 		},
+		ReturnTypeAnnotation: sema.NewTypeAnnotation(
+			&sema.OptionalType{
+				Type: compositeType,
+			},
+		),
 	}
-}
 
-func (compiler *Compiler) VisitCastingExpression(_ *ast.CastingExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+	constructor := compiler.declareFunction(qualifiedIdentifier, constructorType)
+	compiler.globals[qualifiedIdentifier] = constructor
+
+	compileFunctions = append(compileFunctions, func() *ir.Func {
+		return compiler.compileEnumConstructor(constructor, compositeType, caseFunctions)
+	})
+
+	return compileFunctions
 }
 
-func (compiler *Compiler) VisitCreateExpression(_ *ast.CreateExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+// compileEnumCase compiles the function which creates the enum case with the given index.
+// Like in the interpreter, the raw value of the case is its index
+func (compiler *Compiler) compileEnumCase(
+	function *function,
+	compositeType *sema.CompositeType,
+	index int,
+) *ir.Func {
+	const selfLocalIndex = 0
+
+	return &ir.Func{
+		Name: function.name,
+		Type: compileFunctionType(function.functionType),
+		Locals: []ir.Local{
+			{Type: ir.ValTypeComposite},
+		},
+		Statement: &ir.Sequence{
+			Stmts: []ir.Stmt{
+				&ir.StoreLocal{
+					LocalIndex: selfLocalIndex,
+					Exp: &ir.NewComposite{
+						Type: compositeType,
+					},
+				},
+				&ir.SetMember{
+					Target: &ir.CopyLocal{
+						LocalIndex: selfLocalIndex,
+					},
+					Name: sema.EnumRawValueFieldName,
+					Exp: integerConstant(
+						compositeType.EnumRawType,
+						big.NewInt(int64(index)),
+					),
+				},
+				&ir.Return{
+					Exp: &ir.CopyLocal{
+						LocalIndex: selfLocalIndex,
+					},
+				},
+			},
+		},
+	}
 }
 
-func (compiler *Compiler) VisitDestroyExpression(_ *ast.DestroyExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+// compileEnumConstructor compiles the enum constructor,
+// which compares the given raw value with the raw value of each case
+func (compiler *Compiler) compileEnumConstructor(
+	function *function,
+	compositeType *sema.CompositeType,
+	caseFunctions []*function,
+) *ir.Func {
+	const rawValueLocalIndex = 0
+
+	stmts := make([]ir.Stmt, 0, len(caseFunctions)+1)
+
+	for i, caseFunction := range caseFunctions {
+		stmts = append(stmts, &ir.If{
+			Test: &ir.BinOpExpr{
+				Op: ir.BinOpEqual,
+				Left: &ir.CopyLocal{
+					LocalIndex: rawValueLocalIndex,
+				},
+				Right: integerConstant(
+					compositeType.EnumRawType,
+					big.NewInt(int64(i)),
+				),
+			},
+			Then: &ir.Return{
+				Exp: &ir.UnOpExpr{
+					Op: ir.UnOpSome,
+					Expr: &ir.Call{
+						FunctionIndex: caseFunction.index,
+					},
+				},
+			},
+		})
+	}
+
+	stmts = append(stmts, &ir.Return{
+		Exp: &ir.Const{
+			Constant: ir.Nil{},
+		},
+	})
+
+	return &ir.Func{
+		Name: function.name,
+		Type: compileFunctionType(function.functionType),
+		Statement: &ir.Sequence{
+			Stmts: stmts,
+		},
+	}
 }
 
-func (compiler *Compiler) VisitReferenceExpression(_ *ast.ReferenceExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitSpecialFunctionDeclaration(declaration *ast.SpecialFunctionDeclaration) ast.Repr {
+	return compiler.VisitFunctionDeclaration(declaration.FunctionDeclaration)
 }
 
-func (compiler *Compiler) VisitForceExpression(_ *ast.ForceExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitFunctionDeclaration(declaration *ast.FunctionDeclaration) ast.Repr {
+	// TODO: declare function in current scope, use current scope in function
+
+	if compiler.activations.Depth() > 0 {
+		compiler.unsupported("nested function", declaration)
+	}
+
+	functionType := compiler.elaboration().FunctionDeclarationFunctionTypes[declaration]
+	function := &function{
+		name:         declaration.Identifier.Identifier,
+		functionType: functionType,
+	}
+	return compiler.compileFunctionDeclaration(function, declaration, nil)
 }
 
-func (compiler *Compiler) VisitPathExpression(_ *ast.PathExpression) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) compileFunctionDeclaration(
+	function *function,
+	declaration *ast.FunctionDeclaration,
+	selfType *sema.CompositeType,
+) *ir.Func {
+	kind := functionKindFunction
+	if selfType != nil {
+		kind = functionKindMember
+	}
+
+	return compiler.compileFunction(
+		function,
+		kind,
+		selfType,
+		declaration.ParameterList,
+		declaration.FunctionBlock,
+	)
 }
 
-func (compiler *Compiler) VisitProgram(_ *ast.Program) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+// compileConstructor compiles the constructor of a composite
+func (compiler *Compiler) compileConstructor(
+	function *function,
+	compositeType *sema.CompositeType,
+	initializer *ast.SpecialFunctionDeclaration,
+) *ir.Func {
+	var parameterList *ast.ParameterList
+	var functionBlock *ast.FunctionBlock
+	if initializer != nil {
+		parameterList = initializer.FunctionDeclaration.ParameterList
+		functionBlock = initializer.FunctionDeclaration.FunctionBlock
+	}
+
+	return compiler.compileFunction(
+		function,
+		functionKindConstructor,
+		compositeType,
+		parameterList,
+		functionBlock,
+	)
 }
 
-func (compiler *Compiler) VisitSpecialFunctionDeclaration(declaration *ast.SpecialFunctionDeclaration) ast.Repr {
-	return compiler.VisitFunctionDeclaration(declaration.FunctionDeclaration)
+// compileDestructor compiles the destructor of a composite
+func (compiler *Compiler) compileDestructor(
+	function *function,
+	compositeType *sema.CompositeType,
+	destructor *ast.SpecialFunctionDeclaration,
+) *ir.Func {
+	return compiler.compileFunction(
+		function,
+		functionKindDestructor,
+		compositeType,
+		nil,
+		destructor.FunctionDeclaration.FunctionBlock,
+	)
 }
 
-func (compiler *Compiler) VisitFunctionDeclaration(declaration *ast.FunctionDeclaration) ast.Repr {
+type functionKind uint
+
+const (
+	// functionKindFunction is a function without a receiver
+	functionKindFunction functionKind = iota
+	// functionKindMember is a function which has the receiver as its first parameter
+	functionKindMember
+	// functionKindConstructor is a function which creates a new composite value as the receiver,
+	// runs the initializer, and returns the receiver.
+	// Event constructors have no initializer and set the fields from the parameters
+	functionKindConstructor
+	// functionKindDestructor is a function which has the receiver as its first parameter,
+	// runs the destructor, and returns the receiver
+	functionKindDestructor
+)
 
-	// TODO: declare function in current scope, use current scope in function
-	// TODO: conditions
+func (compiler *Compiler) compileFunction(
+	function *function,
+	kind functionKind,
+	selfType *sema.CompositeType,
+	parameterList *ast.ParameterList,
+	functionBlock *ast.FunctionBlock,
+) *ir.Func {
 
 	compiler.locals = nil
 
-	block := declaration.FunctionBlock.Block
+	compiler.activations.PushNewWithCurrent()
+	defer compiler.activations.Pop()
+
+	functionType := function.functionType
+
+	// Declare a local for the receiver and each parameter.
+	// NOTE: parameters must be the first locals
+
+	var self *Local
+	switch kind {
+	case functionKindMember, functionKindDestructor:
+		self = compiler.declareLocal(sema.SelfIdentifier, selfType)
+	}
 
-	// Declare a local for each parameter
+	parameters := make([]*Local, len(functionType.Parameters))
 
-	functionType := compiler.Checker.Elaboration.FunctionDeclarationFunctionTypes[declaration]
+	for i, parameter := range functionType.Parameters {
+		name := parameter.Identifier
+		if parameterList != nil {
+			name = parameterList.Parameters[i].Identifier.Identifier
+		}
+		parameters[i] = compiler.declareLocal(name, parameter.TypeAnnotation.Type)
+	}
 
-	parameters := declaration.ParameterList.Parameters
+	parameterCount := len(compiler.locals)
 
-	for i, parameter := range parameters {
-		parameterType := functionType.Parameters[i].TypeAnnotation.Type
-		valType := compileValueType(parameterType)
-		name := parameter.Identifier.Identifier
-		compiler.declareLocal(name, valType)
+	if kind == functionKindConstructor {
+		self = compiler.declareTemporaryLocal(ir.ValTypeComposite)
+		self.SemaType = selfType
+		compiler.setLocal(sema.SelfIdentifier, self)
 	}
 
 	// Compile the function block
 
-	stmt := block.Accept(compiler).(ir.Stmt)
+	stmt := compiler.compileFunctionBlock(
+		kind,
+		functionType,
+		functionBlock,
+		self,
+		parameters,
+	)
 
 	// Important: compile locals after compiling function block,
 	// and don't include parameters in locals
-	locals := compileLocals(compiler.locals[len(parameters):])
+	locals := compileLocals(compiler.locals[parameterCount:])
 
 	compiledFunctionType := compileFunctionType(functionType)
+	switch kind {
+	case functionKindMember, functionKindDestructor:
+		compiledFunctionType.Params = append(
+			[]ir.ValType{ir.ValTypeComposite},
+			compiledFunctionType.Params...,
+		)
+	}
+
+	compiler.locals = nil
 
 	return &ir.Func{
-		// TODO: fully qualify
-		Name:      declaration.Identifier.Identifier,
+		Name:      function.name,
 		Type:      compiledFunctionType,
 		Locals:    locals,
 		Statement: stmt,
 	}
 }
 
+// compileFunctionBlock compiles the body of a function, including its conditions.
+//
+// If the function has post-conditions or returns the receiver,
+// the body is compiled as a block, and return statements branch to the end of the block.
+func (compiler *Compiler) compileFunctionBlock(
+	kind functionKind,
+	functionType *sema.FunctionType,
+	functionBlock *ast.FunctionBlock,
+	self *Local,
+	parameters []*Local,
+) ir.Stmt {
+
+	var block *ast.Block
+	var preConditions, postConditions *ast.Conditions
+	if functionBlock != nil {
+		block = functionBlock.Block
+		preConditions = functionBlock.PreConditions
+		postConditions = functionBlock.PostConditions
+	}
+
+	returnsSelf := kind == functionKindConstructor || kind == functionKindDestructor
+
+	if !returnsSelf && preConditions.IsEmpty() && postConditions.IsEmpty() {
+		if block == nil {
+			return &ir.Sequence{}
+		}
+		return block.Accept(compiler).(ir.Stmt)
+	}
+
+	var stmts []ir.Stmt
+
+	if kind == functionKindConstructor {
+		stmts = append(stmts, compiler.compileConstructorPrologue(self, parameters)...)
+	}
+
+	stmts = append(stmts, compiler.compileConditions(preConditions)...)
+
+	var rewrite sema.PostConditionsRewrite
+	if !postConditions.IsEmpty() {
+		rewrite = compiler.elaboration().PostConditionsRewrite[postConditions]
+
+		// NOTE: before-statements are declared in the function activation
+		stmts = append(stmts, compiler.compileStatements(rewrite.BeforeStatements)...)
+	}
+
+	returnType := functionType.ReturnTypeAnnotation.Type
+
+	var resultLocal *Local
+	if !returnsSelf && returnType != sema.VoidType {
+		resultLocal = compiler.declareTemporaryLocal(compileValueType(returnType))
+	}
+
+	// Compile the body into a block, and let return statements branch out of it
+
+	var body ir.Stmt = &ir.Sequence{}
+	compiler.withNestedControl(func() {
+		previousReturns := compiler.returns
+		compiler.returns = &returnTarget{
+			depth:       compiler.depth,
+			resultLocal: resultLocal,
+		}
+		defer func() {
+			compiler.returns = previousReturns
+		}()
+
+		if block != nil {
+			body = block.Accept(compiler).(ir.Stmt)
+		}
+	})
+
+	stmts = append(stmts, &ir.Block{
+		Stmts: []ir.Stmt{body},
+	})
+
+	if !postConditions.IsEmpty() {
+		compiler.activations.PushNewWithCurrent()
+		if resultLocal != nil {
+			compiler.setLocal(sema.ResultIdentifier, resultLocal)
+		}
+		stmts = append(stmts, compiler.compileConditions(&rewrite.RewrittenPostConditions)...)
+		compiler.activations.Pop()
+	}
+
+	switch {
+	case returnsSelf:
+		stmts = append(stmts, &ir.Return{
+			Exp: &ir.CopyLocal{
+				LocalIndex: self.Index,
+			},
+		})
+
+	case resultLocal != nil:
+		stmts = append(stmts, &ir.Return{
+			Exp: &ir.CopyLocal{
+				LocalIndex: resultLocal.Index,
+			},
+		})
+	}
+
+	return &ir.Sequence{
+		Stmts: stmts,
+	}
+}
+
+// compileConstructorPrologue creates the new composite value.
+// Events have no initializer, so the fields are set from the parameters
+func (compiler *Compiler) compileConstructorPrologue(self *Local, parameters []*Local) []ir.Stmt {
+	compositeType := self.SemaType.(*sema.CompositeType)
+
+	stmts := []ir.Stmt{
+		&ir.StoreLocal{
+			LocalIndex: self.Index,
+			Exp: &ir.NewComposite{
+				Type: compositeType,
+			},
+		},
+	}
+
+	if compositeType.Kind == common.CompositeKindEvent {
+		for i, parameter := range compositeType.ConstructorParameters {
+			stmts = append(stmts, &ir.SetMember{
+				Target: &ir.CopyLocal{
+					LocalIndex: self.Index,
+				},
+				Name: parameter.Identifier,
+				Exp: &ir.CopyLocal{
+					LocalIndex: parameters[i].Index,
+				},
+			})
+		}
+	}
+
+	return stmts
+}
+
+// compileConditions compiles each condition to an if-statement
+// which fails if the condition's test is false
+func (compiler *Compiler) compileConditions(conditions *ast.Conditions) []ir.Stmt {
+	if conditions == nil {
+		return nil
+	}
+
+	stmts := make([]ir.Stmt, len(*conditions))
+
+	for i, condition := range *conditions {
+		var message ir.Expr
+		if condition.Message != nil {
+			message = compiler.compileExpression(condition.Message)
+		} else {
+			message = &ir.Const{
+				Constant: ir.String{},
+			}
+		}
+
+		result := &ir.If{
+			Test: &ir.UnOpExpr{
+				Op:   ir.UnOpNot,
+				Expr: compiler.compileExpression(condition.Test),
+			},
+		}
+
+		compiler.withNestedControl(func() {
+			result.Then = &ir.FailCondition{
+				Kind:    condition.Kind,
+				Message: message,
+			}
+		})
+
+		stmts[i] = result
+	}
+
+	return stmts
+}
+
 func (compiler *Compiler) VisitBlock(block *ast.Block) ast.Repr {
 
 	// Block scope: each block gets an activation record
@@ -322,86 +2386,160 @@ func (compiler *Compiler) VisitBlock(block *ast.Block) ast.Repr {
 	compiler.activations.PushNewWithCurrent()
 	defer compiler.activations.Pop()
 
-	// Compile each statement in the block
-
-	stmts := make([]ir.Stmt, len(block.Statements))
-	for i, statement := range block.Statements {
-		stmts[i] = statement.Accept(compiler).(ir.Stmt)
-	}
-
 	// NOTE: just return an IR statement sequence,
 	// there is no need for an IR block
 	return &ir.Sequence{
-		Stmts: stmts,
+		Stmts: compiler.compileStatements(block.Statements),
 	}
 }
 
+func (compiler *Compiler) compileStatements(statements []ast.Statement) []ir.Stmt {
+	stmts := make([]ir.Stmt, len(statements))
+	for i, statement := range statements {
+		stmts[i] = statement.Accept(compiler).(ir.Stmt)
+	}
+	return stmts
+}
+
 func (compiler *Compiler) VisitFunctionBlock(_ *ast.FunctionBlock) ast.Repr {
-	// TODO
+	// NOTE: function blocks are compiled in compileFunctionBlock
 	panic(errors.NewUnreachableError())
 }
 
 func (compiler *Compiler) VisitCompositeDeclaration(_ *ast.CompositeDeclaration) ast.Repr {
-	// TODO
+	// NOTE: composite declarations are compiled in VisitProgram
 	panic(errors.NewUnreachableError())
 }
 
 func (compiler *Compiler) VisitInterfaceDeclaration(_ *ast.InterfaceDeclaration) ast.Repr {
-	// TODO
+	// NOTE: interface declarations are only checked in VisitProgram
 	panic(errors.NewUnreachableError())
 }
 
 func (compiler *Compiler) VisitFieldDeclaration(_ *ast.FieldDeclaration) ast.Repr {
-	// TODO
+	// NOTE: fields are created by the run-time when composite values are created
 	panic(errors.NewUnreachableError())
 }
 
 func (compiler *Compiler) VisitCondition(_ *ast.Condition) ast.Repr {
-	// TODO
+	// NOTE: conditions are compiled in compileConditions
 	panic(errors.NewUnreachableError())
 }
 
 func (compiler *Compiler) VisitPragmaDeclaration(_ *ast.PragmaDeclaration) ast.Repr {
-	// TODO
+	// NOTE: pragma declarations are skipped in VisitProgram
 	panic(errors.NewUnreachableError())
 }
 
-func (compiler *Compiler) VisitImportDeclaration(_ *ast.ImportDeclaration) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitImportDeclaration(declaration *ast.ImportDeclaration) ast.Repr {
+	compiler.unsupported("import", declaration)
+	return nil
 }
 
-func (compiler *Compiler) VisitTransactionDeclaration(_ *ast.TransactionDeclaration) ast.Repr {
-	// TODO
-	panic(errors.NewUnreachableError())
+func (compiler *Compiler) VisitTransactionDeclaration(declaration *ast.TransactionDeclaration) ast.Repr {
+	compiler.unsupported("transaction", declaration)
+	return nil
 }
 
 func (compiler *Compiler) VisitEnumCaseDeclaration(_ *ast.EnumCaseDeclaration) ast.Repr {
-	// TODO
+	// NOTE: enum cases are compiled in declareEnum
+	panic(errors.NewUnreachableError())
+}
+
+func (compiler *Compiler) VisitTypeAliasDeclaration(_ *ast.TypeAliasDeclaration) ast.Repr {
+	// NOTE: type aliases are resolved by the checker and skipped in VisitProgram
 	panic(errors.NewUnreachableError())
 }
 
 func compileBinaryOperation(operation ast.Operation) ir.BinOp {
-	// TODO: add remaining operations
 	switch operation {
 	case ast.OperationPlus:
 		return ir.BinOpPlus
+	case ast.OperationMinus:
+		return ir.BinOpMinus
+	case ast.OperationMul:
+		return ir.BinOpMul
+	case ast.OperationDiv:
+		return ir.BinOpDiv
+	case ast.OperationMod:
+		return ir.BinOpMod
+	case ast.OperationEqual:
+		return ir.BinOpEqual
+	case ast.OperationNotEqual:
+		return ir.BinOpNotEqual
+	case ast.OperationLess:
+		return ir.BinOpLess
+	case ast.OperationLessEqual:
+		return ir.BinOpLessEqual
+	case ast.OperationGreater:
+		return ir.BinOpGreater
+	case ast.OperationGreaterEqual:
+		return ir.BinOpGreaterEqual
+	case ast.OperationBitwiseOr:
+		return ir.BinOpBitwiseOr
+	case ast.OperationBitwiseXor:
+		return ir.BinOpBitwiseXor
+	case ast.OperationBitwiseAnd:
+		return ir.BinOpBitwiseAnd
+	case ast.OperationBitwiseLeftShift:
+		return ir.BinOpBitwiseLeftShift
+	case ast.OperationBitwiseRightShift:
+		return ir.BinOpBitwiseRightShift
 	}
 
 	panic(errors.NewUnreachableError())
 }
 
 func compileValueType(ty sema.Type) ir.ValType {
-	// TODO: add remaining types
+	switch ty := ty.(type) {
+	case *sema.OptionalType:
+		return ir.ValTypeOptional
 
-	switch ty {
-	case sema.StringType:
-		return ir.ValTypeString
-	case sema.IntType:
-		return ir.ValTypeInt
+	case sema.ArrayType:
+		return ir.ValTypeArray
+
+	case *sema.DictionaryType:
+		return ir.ValTypeDictionary
+
+	case *sema.CompositeType:
+		return ir.ValTypeComposite
+
+	case *sema.ReferenceType:
+		return ir.ValTypeReference
+
+	case *sema.AddressType:
+		return ir.ValTypeAddress
+
+	case *sema.SimpleType:
+		switch ty {
+		case sema.StringType,
+			sema.CharacterType:
+
+			return ir.ValTypeString
+
+		case sema.BoolType:
+			return ir.ValTypeBool
+
+		case sema.PathType,
+			sema.StoragePathType,
+			sema.CapabilityPathType,
+			sema.PublicPathType,
+			sema.PrivatePathType:
+
+			return ir.ValTypePath
+		}
+
+	case *sema.NumericType:
+		if ty == sema.IntType {
+			return ir.ValTypeInt
+		}
+		return ir.ValTypeNumber
+
+	case *sema.FixedPointNumericType:
+		return ir.ValTypeNumber
 	}
 
-	panic(errors.NewUnreachableError())
+	return ir.ValTypeAny
 }
 
 func compileFunctionType(functionType *sema.FunctionType) ir.FuncType {
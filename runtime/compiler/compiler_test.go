@@ -81,3 +81,291 @@ func TestCompilerSimple(t *testing.T) {
 		res,
 	)
 }
+
+func TestCompilerWhile(t *testing.T) {
+
+	checker, err := checker.ParseAndCheck(t, `
+      fun count(n: Int): Int {
+          var i = 0
+          while i < n {
+              if i == 5 {
+                  break
+              }
+              i = i + 1
+          }
+          return i
+      }
+    `)
+
+	require.NoError(t, err)
+
+	funcs, err := Compile(checker)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		[]*ir.Func{
+			{
+				Name: "count",
+				Type: ir.FuncType{
+					Params: []ir.ValType{
+						ir.ValTypeInt,
+					},
+					Results: []ir.ValType{
+						ir.ValTypeInt,
+					},
+				},
+				Locals: []ir.Local{
+					{Type: ir.ValTypeInt},
+				},
+				Statement: &ir.Sequence{
+					Stmts: []ir.Stmt{
+						&ir.StoreLocal{
+							LocalIndex: 1,
+							Exp: &ir.Const{
+								Constant: ir.Int{Value: []byte{1}},
+							},
+						},
+						// break target
+						&ir.Block{
+							Stmts: []ir.Stmt{
+								&ir.Loop{
+									Stmts: []ir.Stmt{
+										&ir.BranchIf{
+											Exp: &ir.UnOpExpr{
+												Op: ir.UnOpNot,
+												Expr: &ir.BinOpExpr{
+													Op:    ir.BinOpLess,
+													Left:  &ir.CopyLocal{LocalIndex: 1},
+													Right: &ir.CopyLocal{LocalIndex: 0},
+												},
+											},
+											Index: 1,
+										},
+										// continue target
+										&ir.Block{
+											Stmts: []ir.Stmt{
+												&ir.Sequence{
+													Stmts: []ir.Stmt{
+														&ir.If{
+															Test: &ir.BinOpExpr{
+																Op:   ir.BinOpEqual,
+																Left: &ir.CopyLocal{LocalIndex: 1},
+																Right: &ir.Const{
+																	Constant: ir.Int{Value: []byte{1, 5}},
+																},
+															},
+															Then: &ir.Sequence{
+																Stmts: []ir.Stmt{
+																	&ir.Branch{Index: 3},
+																},
+															},
+														},
+														&ir.StoreLocal{
+															LocalIndex: 1,
+															Exp: &ir.BinOpExpr{
+																Op:   ir.BinOpPlus,
+																Left: &ir.CopyLocal{LocalIndex: 1},
+																Right: &ir.Const{
+																	Constant: ir.Int{Value: []byte{1, 1}},
+																},
+															},
+														},
+													},
+												},
+											},
+										},
+										&ir.Branch{Index: 0},
+									},
+								},
+							},
+						},
+						&ir.Return{
+							Exp: &ir.CopyLocal{LocalIndex: 1},
+						},
+					},
+				},
+			},
+		},
+		funcs,
+	)
+}
+
+func TestCompilerInvocation(t *testing.T) {
+
+	checker, err := checker.ParseAndCheck(t, `
+      fun double(_ n: Int): Int {
+          return n * 2
+      }
+
+      fun quadruple(_ n: Int): Int {
+          return double(double(n))
+      }
+    `)
+
+	require.NoError(t, err)
+
+	funcs, err := Compile(checker)
+	require.NoError(t, err)
+	require.Len(t, funcs, 2)
+
+	require.Equal(t,
+		&ir.Sequence{
+			Stmts: []ir.Stmt{
+				&ir.Return{
+					Exp: &ir.Call{
+						FunctionIndex: 0,
+						Arguments: []ir.Expr{
+							&ir.Call{
+								FunctionIndex: 0,
+								Arguments: []ir.Expr{
+									&ir.CopyLocal{LocalIndex: 0},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		funcs[1].Statement,
+	)
+}
+
+func TestCompilerComposite(t *testing.T) {
+
+	checker, err := checker.ParseAndCheck(t, `
+      resource R {
+          var count: Int
+
+          init(count: Int) {
+              self.count = count
+          }
+
+          fun increment() {
+              self.count = self.count + 1
+          }
+
+          destroy() {}
+      }
+
+      fun test(): Int {
+          let r <- create R(count: 1)
+          r.increment()
+          let count = r.count
+          destroy r
+          return count
+      }
+    `)
+
+	require.NoError(t, err)
+
+	funcs, err := Compile(checker)
+	require.NoError(t, err)
+
+	type signature struct {
+		name string
+		typ  ir.FuncType
+	}
+
+	signatures := make([]signature, len(funcs))
+	for i, f := range funcs {
+		signatures[i] = signature{
+			name: f.Name,
+			typ:  f.Type,
+		}
+	}
+
+	require.Equal(t,
+		[]signature{
+			{
+				name: "R",
+				typ: ir.FuncType{
+					Params:  []ir.ValType{ir.ValTypeInt},
+					Results: []ir.ValType{ir.ValTypeComposite},
+				},
+			},
+			{
+				name: "R.increment",
+				typ: ir.FuncType{
+					Params: []ir.ValType{ir.ValTypeComposite},
+				},
+			},
+			{
+				name: "R.destroy",
+				typ: ir.FuncType{
+					Params:  []ir.ValType{ir.ValTypeComposite},
+					Results: []ir.ValType{ir.ValTypeComposite},
+				},
+			},
+			{
+				name: "test",
+				typ: ir.FuncType{
+					Params:  []ir.ValType{},
+					Results: []ir.ValType{ir.ValTypeInt},
+				},
+			},
+		},
+		signatures,
+	)
+}
+
+func TestCompilerUnsupported(t *testing.T) {
+
+	t.Parallel()
+
+	tests := map[string]string{
+		"function expression": `
+          fun test(): Int {
+              let f = fun (): Int {
+                  return 1
+              }
+              return f()
+          }
+        `,
+		"nested function": `
+          fun test(): Int {
+              fun f(): Int {
+                  return 1
+              }
+              return f()
+          }
+        `,
+		"function value": `
+          fun one(): Int {
+              return 1
+          }
+
+          fun test(): Int {
+              let f = one
+              return f()
+          }
+        `,
+		"interface function with conditions": `
+          struct interface I {
+              fun test(n: Int) {
+                  pre {
+                      n > 0
+                  }
+              }
+          }
+        `,
+		"transaction": `
+          transaction {}
+        `,
+	}
+
+	for name, code := range tests {
+		code := code
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			checker, err := checker.ParseAndCheck(t, code)
+			require.NoError(t, err)
+
+			_, err = Compile(checker)
+			require.Error(t, err)
+
+			var unsupportedErr *UnsupportedFeatureError
+			require.ErrorAs(t, err, &unsupportedErr)
+		})
+	}
+}
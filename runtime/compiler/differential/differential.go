@@ -0,0 +1,372 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package differential implements differential testing of the compiler:
+// A program is executed both by the interpreter and as a compiled WebAssembly module,
+// and the results of both executions are compared.
+//
+package differential
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/compiler"
+	"github.com/onflow/cadence/runtime/compiler/ir"
+	"github.com/onflow/cadence/runtime/compiler/wasm"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib"
+	"github.com/onflow/cadence/vm"
+)
+
+// Backend creates a VM which executes the given compiled functions
+//
+type Backend func(funcs []*ir.Func, config *vm.Config) (vm.VM, error)
+
+// WASMBackend returns a backend which generates a WebAssembly module for the compiled functions,
+// and executes it using the VM created by the given function
+//
+func WASMBackend(newVM func(wasm []byte, config *vm.Config) (vm.VM, error)) Backend {
+	return func(funcs []*ir.Func, config *vm.Config) (vm.VM, error) {
+		module, err := generateWasm(funcs)
+		if err != nil {
+			return nil, err
+		}
+		return newVM(module, config)
+	}
+}
+
+// BytecodeBackend is a backend which generates a bytecode program for the compiled functions,
+// and executes it using the bytecode VM
+//
+func BytecodeBackend(funcs []*ir.Func, config *vm.Config) (vm.VM, error) {
+	return vm.NewBytecodeVM(compiler.GenerateBytecode(funcs), config)
+}
+
+var location = common.StringLocation("test")
+
+// Result is the observable outcome of invoking a function:
+// the returned value or the error, and the emitted events
+//
+type Result struct {
+	// Value is the string representation of the returned value and its type,
+	// or empty if the invocation failed
+	Value string
+	// Error is the kind of error the invocation failed with,
+	// or empty if the invocation succeeded
+	Error string
+	// Events are the string representations of the emitted events
+	Events []string
+}
+
+func (r Result) Equal(other Result) bool {
+	if r.Value != other.Value || r.Error != other.Error {
+		return false
+	}
+
+	if len(r.Events) != len(other.Events) {
+		return false
+	}
+
+	for i, event := range r.Events {
+		if event != other.Events[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r Result) String() string {
+	var builder strings.Builder
+	if r.Error != "" {
+		builder.WriteString("error: ")
+		builder.WriteString(r.Error)
+	} else {
+		builder.WriteString("value: ")
+		builder.WriteString(r.Value)
+	}
+	for _, event := range r.Events {
+		builder.WriteString("\nevent: ")
+		builder.WriteString(event)
+	}
+	return builder.String()
+}
+
+// Mismatch is a function for which the interpreter and the compiled program
+// produced different results
+//
+type Mismatch struct {
+	Function    string
+	Interpreted Result
+	Compiled    Result
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf(
+		"function %s:\ninterpreted:\n%s\ncompiled:\n%s",
+		m.Function,
+		m.Interpreted,
+		m.Compiled,
+	)
+}
+
+// Run checks and compiles the given program,
+// and invokes each public function which has no parameters,
+// both using the interpreter and using the given backend.
+//
+// It returns the functions for which the results differ.
+// An error is returned if the program is invalid or cannot be compiled
+//
+func Run(code string, backend Backend) ([]Mismatch, error) {
+	checker, err := Check(code)
+	if err != nil {
+		return nil, err
+	}
+
+	funcs, err := compiler.Compile(checker)
+	if err != nil {
+		return nil, err
+	}
+
+	var mismatches []Mismatch
+
+	for _, name := range entryPoints(checker) {
+
+		interpreted, err := invokeInterpreted(checker, name)
+		if err != nil {
+			return nil, err
+		}
+
+		compiled, err := invokeCompiled(checker, funcs, backend, name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !interpreted.Equal(compiled) {
+			mismatches = append(mismatches, Mismatch{
+				Function:    name,
+				Interpreted: interpreted,
+				Compiled:    compiled,
+			})
+		}
+	}
+
+	return mismatches, nil
+}
+
+// Compile checks the given program and compiles it to a WebAssembly module
+//
+func Compile(code string) (*sema.Checker, []byte, error) {
+	checker, err := Check(code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	funcs, err := compiler.Compile(checker)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	module, err := generateWasm(funcs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return checker, module, nil
+}
+
+func generateWasm(funcs []*ir.Func) ([]byte, error) {
+	var buf wasm.Buffer
+	err := wasm.NewWASMWriter(&buf).WriteModule(compiler.GenerateWasm(funcs))
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Check parses and checks the given program.
+// The program may use the built-in functions `assert` and `panic`
+//
+func Check(code string) (*sema.Checker, error) {
+	program, err := parser2.ParseProgram(code, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	checker, err := sema.NewChecker(
+		program,
+		location,
+		nil,
+		sema.WithPredeclaredValues(stdlib.BuiltinFunctions.ToSemaValueDeclarations()),
+		sema.WithAccessCheckMode(sema.AccessCheckModeNotSpecifiedUnrestricted),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checker.Check()
+	if err != nil {
+		return nil, err
+	}
+
+	return checker, nil
+}
+
+// entryPoints returns the names of the functions which are invoked,
+// the public top-level functions without parameters
+//
+func entryPoints(checker *sema.Checker) []string {
+	var names []string
+	for _, declaration := range checker.Program.FunctionDeclarations() {
+		if declaration.Access != ast.AccessPublic ||
+			len(declaration.ParameterList.Parameters) > 0 {
+
+			continue
+		}
+		names = append(names, declaration.Identifier.Identifier)
+	}
+	return names
+}
+
+// environment is the environment of one invocation.
+// Each invocation uses a new interpreter, so invocations are independent
+//
+type environment struct {
+	interpreter *interpreter.Interpreter
+	uuid        uint64
+	events      []string
+}
+
+func (e *environment) uuidHandler() (uint64, error) {
+	e.uuid++
+	return e.uuid, nil
+}
+
+func (e *environment) onEventEmitted(
+	inter *interpreter.Interpreter,
+	_ func() interpreter.LocationRange,
+	event *interpreter.CompositeValue,
+	_ *sema.CompositeType,
+) error {
+	e.events = append(e.events, event.String())
+	return nil
+}
+
+func newEnvironment(checker *sema.Checker) (*environment, error) {
+	env := &environment{}
+
+	inter, err := interpreter.NewInterpreter(
+		interpreter.ProgramFromChecker(checker),
+		checker.Location,
+		interpreter.WithStorage(interpreter.NewInMemoryStorage(nil)),
+		interpreter.WithPredeclaredValues(stdlib.BuiltinFunctions.ToInterpreterValueDeclarations()),
+		interpreter.WithUUIDHandler(env.uuidHandler),
+		interpreter.WithOnEventEmittedHandler(env.onEventEmitted),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = inter.Interpret()
+	if err != nil {
+		return nil, err
+	}
+
+	env.interpreter = inter
+
+	return env, nil
+}
+
+func (e *environment) result(value interpreter.Value, err error) Result {
+	result := Result{
+		Events: e.events,
+	}
+
+	if err != nil {
+		result.Error = errorKind(err)
+	} else {
+		result.Value = fmt.Sprintf(
+			"%s: %s",
+			value,
+			value.StaticType(e.interpreter),
+		)
+	}
+
+	return result
+}
+
+func invokeInterpreted(checker *sema.Checker, name string) (Result, error) {
+	env, err := newEnvironment(checker)
+	if err != nil {
+		return Result{}, err
+	}
+
+	value, err := env.interpreter.Invoke(name)
+	return env.result(value, err), nil
+}
+
+func invokeCompiled(checker *sema.Checker, funcs []*ir.Func, backend Backend, name string) (Result, error) {
+	env, err := newEnvironment(checker)
+	if err != nil {
+		return Result{}, err
+	}
+
+	machine, err := backend(
+		funcs,
+		&vm.Config{
+			Interpreter:    env.interpreter,
+			UUIDHandler:    env.uuidHandler,
+			OnEventEmitted: env.onEventEmitted,
+		},
+	)
+	if err != nil {
+		return Result{}, err
+	}
+
+	value, err := machine.Invoke(name)
+	return env.result(value, err), nil
+}
+
+// errorKind returns the kind of the given error, the type of the underlying error.
+// Error messages are not compared, as compiled programs have no position information
+//
+func errorKind(err error) string {
+	for {
+		switch typedErr := err.(type) {
+		case interpreter.Error:
+			err = typedErr.Err
+			continue
+
+		case interpreter.PositionedError:
+			err = typedErr.Err
+			continue
+		}
+
+		unwrapped := errors.Unwrap(err)
+		if unwrapped == nil {
+			return fmt.Sprintf("%T", err)
+		}
+		err = unwrapped
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package differential
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/compiler/wasm"
+)
+
+func TestGeneratorDeterministic(t *testing.T) {
+
+	t.Parallel()
+
+	require.Equal(t,
+		NewGenerator(42).Program(),
+		NewGenerator(42).Program(),
+	)
+}
+
+func TestGeneratedCorpusUpToDate(t *testing.T) {
+
+	t.Parallel()
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "generated", "*.cdc"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for i, path := range paths {
+		code, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		require.Equal(t,
+			NewGenerator(int64(i)).Program(),
+			string(code),
+			"corpus is outdated, regenerate it using `go generate`",
+		)
+	}
+}
+
+func TestGeneratedCorpusCompiles(t *testing.T) {
+
+	t.Parallel()
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "generated", "*.cdc"))
+	require.NoError(t, err)
+
+	for _, path := range paths {
+		path := path
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			t.Parallel()
+
+			code, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			checker, module, err := Compile(string(code))
+			require.NoError(t, err)
+
+			// The module can be read back, and exports all entry points
+
+			reader := wasm.NewWASMReader(wasm.NewBuffer(module))
+			err = reader.ReadModule()
+			require.NoError(t, err)
+
+			exports := map[string]struct{}{}
+			for _, export := range reader.Module.Exports {
+				exports[export.Name] = struct{}{}
+			}
+
+			for _, name := range entryPoints(checker) {
+				require.Contains(t, exports, name)
+			}
+		})
+	}
+}
+
+// TestGeneratedCorpusInterprets invokes the entry points of the generated corpus
+// using the interpreter. Unlike the differential tests, it does not require wasmtime,
+// so it ensures that the corpus is valid and that all invocations terminate
+//
+func TestGeneratedCorpusInterprets(t *testing.T) {
+
+	t.Parallel()
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "generated", "*.cdc"))
+	require.NoError(t, err)
+
+	for _, path := range paths {
+		path := path
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			t.Parallel()
+
+			code, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			checker, err := Check(string(code))
+			require.NoError(t, err)
+
+			names := entryPoints(checker)
+			require.NotEmpty(t, names)
+
+			for _, name := range names {
+				_, err := invokeInterpreted(checker, name)
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+
+// gen generates the corpus of programs used by the differential tests.
+// Each program is checked and compiled, so the corpus only contains programs
+// which can be executed by both the interpreter and the compiled program.
+//
+// Usage: gen <directory> [count]
+//
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/onflow/cadence/runtime/cmd"
+	"github.com/onflow/cadence/runtime/compiler/differential"
+)
+
+const defaultCount = 50
+
+func main() {
+	if len(os.Args) < 2 {
+		cmd.ExitWithError("usage: gen <directory> [count]")
+	}
+
+	directory := os.Args[1]
+
+	count := defaultCount
+	if len(os.Args) > 2 {
+		var err error
+		count, err = strconv.Atoi(os.Args[2])
+		if err != nil {
+			cmd.ExitWithError(fmt.Sprintf("invalid count: %s", err))
+		}
+	}
+
+	err := os.MkdirAll(directory, 0755)
+	if err != nil {
+		cmd.ExitWithError(err.Error())
+	}
+
+	for i := 0; i < count; i++ {
+		// Use the index as the seed, so the corpus is reproducible
+
+		code := differential.NewGenerator(int64(i)).Program()
+
+		_, _, err := differential.Compile(code)
+		if err != nil {
+			cmd.ExitWithError(fmt.Sprintf("generated program %d is invalid: %s\n%s", i, err, code))
+		}
+
+		path := filepath.Join(directory, fmt.Sprintf("program%03d.cdc", i))
+		err = os.WriteFile(path, []byte(code), 0644)
+		if err != nil {
+			cmd.ExitWithError(err.Error())
+		}
+	}
+}
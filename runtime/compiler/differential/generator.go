@@ -0,0 +1,773 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package differential
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// valueType is a type of values used in generated programs
+//
+type valueType int
+
+const (
+	typeInt valueType = iota
+	typeUInt8
+	typeBool
+	typeString
+	typeIntArray
+	typeOptionalInt
+	typeDictionary
+	typeStruct
+	typeOptionalStruct
+)
+
+// resultTypes are the types of values returned by the entry points
+//
+var resultTypes = []valueType{
+	typeInt,
+	typeUInt8,
+	typeBool,
+	typeString,
+	typeIntArray,
+	typeOptionalInt,
+	typeDictionary,
+}
+
+func (t valueType) String() string {
+	switch t {
+	case typeInt:
+		return "Int"
+	case typeUInt8:
+		return "UInt8"
+	case typeBool:
+		return "Bool"
+	case typeString:
+		return "String"
+	case typeIntArray:
+		return "[Int]"
+	case typeOptionalInt:
+		return "Int?"
+	case typeDictionary:
+		return "{String: Int}"
+	case typeStruct:
+		return "S"
+	case typeOptionalStruct:
+		return "S?"
+	}
+
+	panic(fmt.Errorf("unknown type: %d", t))
+}
+
+var stringLiterals = []string{"", "a", "bc", "hello", "Cadence"}
+
+const (
+	maxExpressionDepth = 3
+	maxStatementDepth  = 2
+	maxLoopIterations  = 5
+)
+
+type variable struct {
+	name    string
+	ty      valueType
+	mutable bool
+}
+
+// Generator generates random, well-typed programs for differential testing.
+//
+// Generated programs declare a struct `S`, a resource `R` which emits an event when destroyed,
+// helper functions with parameters and pre- and post-conditions,
+// and public entry point functions without parameters.
+// The programs use the features supported by the compiler:
+// arithmetic, comparisons, conditionals, loops, optionals, arrays, dictionaries,
+// composites, resources, and events.
+//
+// Programs may fail at run-time, e.g. due to an overflow or a failed condition,
+// which is also an outcome that is compared.
+//
+// Given the same seed, the generator generates the same programs
+//
+type Generator struct {
+	rand        *rand.Rand
+	builder     strings.Builder
+	indentation int
+	scopes      [][]variable
+	names       int
+	helpers     int
+}
+
+func NewGenerator(seed int64) *Generator {
+	return &Generator{
+		rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Program generates a new program
+//
+func (g *Generator) Program() string {
+	g.builder.Reset()
+	g.names = 0
+	g.helpers = 0
+
+	g.writeDeclarations()
+
+	helperCount := g.rand.Intn(3)
+	for i := 0; i < helperCount; i++ {
+		g.writeHelper()
+	}
+
+	testCount := 1 + g.rand.Intn(4)
+	for i := 0; i < testCount; i++ {
+		g.writeEntryPoint(i)
+	}
+
+	return g.builder.String()
+}
+
+const declarations = `pub event Destroyed(value: Int)
+
+pub struct S {
+    pub var x: Int
+    pub let name: String
+
+    init(x: Int, name: String) {
+        self.x = x
+        self.name = name
+    }
+
+    pub fun add(_ n: Int): Int {
+        self.x = self.x + n
+        return self.x
+    }
+}
+
+pub resource R {
+    pub var value: Int
+
+    init(value: Int) {
+        self.value = value
+    }
+
+    pub fun inc(): Int {
+        self.value = self.value + 1
+        return self.value
+    }
+
+    destroy() {
+        emit Destroyed(value: self.value)
+    }
+}
+`
+
+func (g *Generator) writeDeclarations() {
+	g.builder.WriteString(declarations)
+}
+
+func (g *Generator) writeLine(format string, args ...any) {
+	g.builder.WriteString(strings.Repeat("    ", g.indentation))
+	fmt.Fprintf(&g.builder, format, args...)
+	g.builder.WriteByte('\n')
+}
+
+func (g *Generator) openBlock(format string, args ...any) {
+	g.writeLine(format+" {", args...)
+	g.indentation++
+	g.pushScope()
+}
+
+func (g *Generator) closeBlock(suffix string) {
+	g.popScope()
+	g.indentation--
+	g.writeLine("}" + suffix)
+}
+
+func (g *Generator) pushScope() {
+	g.scopes = append(g.scopes, nil)
+}
+
+func (g *Generator) popScope() {
+	g.scopes = g.scopes[:len(g.scopes)-1]
+}
+
+func (g *Generator) newName(prefix string) string {
+	g.names++
+	return fmt.Sprintf("%s%d", prefix, g.names)
+}
+
+func (g *Generator) declare(ty valueType, mutable bool) string {
+	name := g.newName("v")
+	g.declareName(name, ty, mutable)
+	return name
+}
+
+func (g *Generator) declareName(name string, ty valueType, mutable bool) {
+	last := len(g.scopes) - 1
+	g.scopes[last] = append(g.scopes[last], variable{
+		name:    name,
+		ty:      ty,
+		mutable: mutable,
+	})
+}
+
+// variables returns the variables in scope which have the given type
+//
+func (g *Generator) variables(ty valueType, mutable bool) []variable {
+	var result []variable
+	for _, scope := range g.scopes {
+		for _, variable := range scope {
+			if variable.ty != ty || (mutable && !variable.mutable) {
+				continue
+			}
+			result = append(result, variable)
+		}
+	}
+	return result
+}
+
+func (g *Generator) chance(n int) bool {
+	return g.rand.Intn(n) == 0
+}
+
+// writeHelper writes a helper function.
+// Helper functions may only call previously declared helper functions,
+// so calls always terminate
+//
+func (g *Generator) writeHelper() {
+	g.openBlock("fun helper%d(a: Int, b: Int): Int", g.helpers+1)
+	g.scopes[len(g.scopes)-1] = []variable{
+		{name: "a", ty: typeInt},
+		{name: "b", ty: typeInt},
+	}
+
+	g.openBlock("pre")
+	g.writeLine("a > -1000: \"a is too small\"")
+	g.closeBlock("")
+
+	g.openBlock("post")
+	g.writeLine("result != %d", g.rand.Intn(10))
+	g.closeBlock("")
+
+	g.writeStatements(0)
+	g.writeLine("return %s", g.expression(typeInt, 0))
+
+	g.closeBlock("\n")
+
+	g.helpers++
+}
+
+func (g *Generator) writeEntryPoint(index int) {
+	resultType := resultTypes[g.rand.Intn(len(resultTypes))]
+
+	g.openBlock("pub fun test%d(): %s", index, resultType)
+	g.writeStatements(0)
+	g.writeLine("return %s", g.expression(resultType, 0))
+	g.closeBlock("\n")
+}
+
+func (g *Generator) writeStatements(depth int) {
+	count := 1 + g.rand.Intn(4)
+	for i := 0; i < count; i++ {
+		g.writeStatement(depth)
+	}
+}
+
+func (g *Generator) writeStatement(depth int) {
+	nested := depth < maxStatementDepth
+
+	switch g.rand.Intn(12) {
+	case 0, 1:
+		g.writeVariableDeclaration(depth)
+
+	case 2:
+		g.writeAssignment(depth)
+
+	case 3:
+		if !nested {
+			g.writeAssignment(depth)
+			return
+		}
+		g.openBlock("if %s", g.expression(typeBool, 0))
+		g.writeStatements(depth + 1)
+		if g.chance(2) {
+			g.popScope()
+			g.indentation--
+			g.openBlock("} else")
+			g.writeStatements(depth + 1)
+		}
+		g.closeBlock("")
+
+	case 4:
+		if !nested {
+			g.writeAssignment(depth)
+			return
+		}
+		g.writeWhile(depth)
+
+	case 5:
+		if !nested {
+			g.writeAssignment(depth)
+			return
+		}
+		name := g.newName("v")
+		g.openBlock("for %s in %s", name, g.arrayLiteral(false))
+		g.declareName(name, typeInt, false)
+		g.writeStatements(depth + 1)
+		g.closeBlock("")
+
+	case 6:
+		arrays := g.variables(typeIntArray, false)
+		if len(arrays) == 0 {
+			g.writeVariableDeclaration(depth)
+			return
+		}
+		array := arrays[g.rand.Intn(len(arrays))]
+		g.writeLine("%s.append(%s)", array.name, g.expression(typeInt, 0))
+
+	case 7:
+		dictionaries := g.variables(typeDictionary, false)
+		if len(dictionaries) == 0 {
+			g.writeVariableDeclaration(depth)
+			return
+		}
+		dictionary := dictionaries[g.rand.Intn(len(dictionaries))]
+		g.writeLine(
+			"%s[%s] = %s",
+			dictionary.name,
+			g.expression(typeString, 0),
+			g.expression(typeOptionalInt, 0),
+		)
+
+	case 8:
+		if !nested {
+			g.writeAssignment(depth)
+			return
+		}
+		name := g.newName("v")
+		g.openBlock("if let %s = %s", name, g.optionalIntOperand(0))
+		g.declareName(name, typeInt, false)
+		g.writeStatements(depth + 1)
+		g.closeBlock("")
+
+	case 9:
+		if !nested {
+			g.writeAssignment(depth)
+			return
+		}
+		g.writeSwitch(depth)
+
+	case 10:
+		g.writeResourceUsage()
+
+	case 11:
+		structs := g.variables(typeStruct, false)
+		if len(structs) == 0 {
+			g.writeVariableDeclaration(depth)
+			return
+		}
+		s := structs[g.rand.Intn(len(structs))]
+		g.writeLine("%s.add(%s)", s.name, g.expression(typeInt, 0))
+	}
+}
+
+func (g *Generator) writeVariableDeclaration(depth int) {
+	ty := valueType(g.rand.Intn(int(typeOptionalStruct) + 1))
+	value := g.expression(ty, 0)
+
+	mutable := g.chance(2)
+	keyword := "let"
+	if mutable {
+		keyword = "var"
+	}
+
+	name := g.declare(ty, mutable)
+	g.writeLine("%s %s: %s = %s", keyword, name, ty, value)
+}
+
+func (g *Generator) writeAssignment(depth int) {
+	ty := valueType(g.rand.Intn(int(typeOptionalStruct) + 1))
+	variables := g.variables(ty, true)
+	if len(variables) == 0 {
+		g.writeVariableDeclaration(depth)
+		return
+	}
+	target := variables[g.rand.Intn(len(variables))]
+	g.writeLine("%s = %s", target.name, g.expression(ty, 0))
+}
+
+// writeWhile writes a while loop which is bounded by a counter.
+// The counter is incremented first, so continue statements cannot cause an infinite loop
+//
+func (g *Generator) writeWhile(depth int) {
+	counter := g.newName("i")
+
+	g.writeLine("var %s = 0", counter)
+	g.openBlock("while %s < %d", counter, 1+g.rand.Intn(maxLoopIterations))
+	g.writeLine("%s = %s + 1", counter, counter)
+
+	g.writeStatements(depth + 1)
+
+	if g.chance(2) {
+		keyword := "break"
+		if g.chance(2) {
+			keyword = "continue"
+		}
+		g.openBlock("if %s", g.expression(typeBool, 0))
+		g.writeLine(keyword)
+		g.closeBlock("")
+		g.writeStatements(depth + 1)
+	}
+
+	g.closeBlock("")
+}
+
+func (g *Generator) writeSwitch(depth int) {
+	g.writeLine("switch %s {", g.expression(typeInt, 0))
+
+	caseCount := 1 + g.rand.Intn(3)
+	for i := 0; i < caseCount; i++ {
+		g.writeLine("case %d:", g.rand.Intn(5))
+		g.indentation++
+		g.pushScope()
+		g.writeStatements(depth + 1)
+		g.popScope()
+		g.indentation--
+	}
+
+	if g.chance(2) {
+		g.writeLine("default:")
+		g.indentation++
+		g.pushScope()
+		g.writeStatements(depth + 1)
+		g.popScope()
+		g.indentation--
+	}
+
+	g.writeLine("}")
+}
+
+// writeResourceUsage writes the creation, use, and destruction of a resource,
+// which emits an event
+//
+func (g *Generator) writeResourceUsage() {
+	name := g.newName("r")
+
+	g.writeLine("let %s <- create R(value: %s)", name, g.expression(typeInt, 0))
+	g.writeLine("%s.inc()", name)
+
+	targets := g.variables(typeInt, true)
+	if len(targets) > 0 {
+		target := targets[g.rand.Intn(len(targets))]
+		g.writeLine("%s = %s + %s.value", target.name, target.name, name)
+	}
+
+	g.writeLine("destroy %s", name)
+}
+
+func (g *Generator) expression(ty valueType, depth int) string {
+	nested := depth < maxExpressionDepth
+
+	if nested && g.chance(8) {
+		return fmt.Sprintf(
+			"(%s ? %s : %s)",
+			g.expression(typeBool, depth+1),
+			g.expression(ty, depth+1),
+			g.expression(ty, depth+1),
+		)
+	}
+
+	if g.chance(3) {
+		variables := g.variables(ty, false)
+		if len(variables) > 0 {
+			return variables[g.rand.Intn(len(variables))].name
+		}
+	}
+
+	switch ty {
+	case typeInt:
+		return g.intExpression(depth)
+	case typeUInt8:
+		return g.uint8Expression(depth)
+	case typeBool:
+		return g.boolExpression(depth)
+	case typeString:
+		return g.stringExpression(depth)
+	case typeIntArray:
+		return g.arrayLiteral(depth == 0)
+	case typeOptionalInt:
+		return g.optionalIntExpression(depth)
+	case typeDictionary:
+		return g.dictionaryLiteral(depth == 0)
+	case typeStruct:
+		return g.structExpression(depth)
+	case typeOptionalStruct:
+		if g.chance(2) {
+			return "nil"
+		}
+		return g.structExpression(depth)
+	}
+
+	panic(fmt.Errorf("unknown type: %d", ty))
+}
+
+func (g *Generator) intLiteral() string {
+	value := g.rand.Intn(20)
+	if g.chance(4) {
+		return fmt.Sprintf("-%d", value)
+	}
+	return fmt.Sprint(value)
+}
+
+func (g *Generator) intExpression(depth int) string {
+	if depth >= maxExpressionDepth {
+		return g.intLiteral()
+	}
+
+	switch g.rand.Intn(12) {
+	case 0, 1:
+		return g.intLiteral()
+
+	case 2, 3:
+		operators := []string{"+", "-", "*"}
+		return fmt.Sprintf(
+			"(%s %s %s)",
+			g.expression(typeInt, depth+1),
+			operators[g.rand.Intn(len(operators))],
+			g.expression(typeInt, depth+1),
+		)
+
+	case 4:
+		// Divide mostly by non-zero literals, so only few programs fail with a division by zero
+
+		operator := "/"
+		if g.chance(2) {
+			operator = "%"
+		}
+
+		divisor := fmt.Sprint(1 + g.rand.Intn(5))
+		if g.chance(10) {
+			divisor = g.expression(typeInt, depth+1)
+		}
+
+		return fmt.Sprintf(
+			"(%s %s %s)",
+			g.expression(typeInt, depth+1),
+			operator,
+			divisor,
+		)
+
+	case 5:
+		return fmt.Sprintf("%s.length", g.expression(typeIntArray, depth+1))
+
+	case 6:
+		return fmt.Sprintf(
+			"%s[%d]",
+			g.expression(typeIntArray, depth+1),
+			g.rand.Intn(3),
+		)
+
+	case 7:
+		return fmt.Sprintf(
+			"(%s ?? %s)",
+			g.optionalIntOperand(depth+1),
+			g.expression(typeInt, depth+1),
+		)
+
+	case 8:
+		if g.helpers == 0 {
+			return g.intLiteral()
+		}
+		return fmt.Sprintf(
+			"helper%d(a: %s, b: %s)",
+			1+g.rand.Intn(g.helpers),
+			g.expression(typeInt, depth+1),
+			g.expression(typeInt, depth+1),
+		)
+
+	case 9:
+		return fmt.Sprintf(
+			"%s.add(%s)",
+			g.expression(typeStruct, depth+1),
+			g.expression(typeInt, depth+1),
+		)
+
+	case 10:
+		return fmt.Sprintf("%s.x", g.expression(typeStruct, depth+1))
+
+	default:
+		return fmt.Sprintf("%s.length", g.expression(typeString, depth+1))
+	}
+}
+
+func (g *Generator) uint8Expression(depth int) string {
+	if depth >= maxExpressionDepth || g.chance(2) {
+		return fmt.Sprintf("(%d as UInt8)", g.rand.Intn(256))
+	}
+
+	operators := []string{"+", "-", "*", "&", "|", "^"}
+	return fmt.Sprintf(
+		"(%s %s %s)",
+		g.expression(typeUInt8, depth+1),
+		operators[g.rand.Intn(len(operators))],
+		g.expression(typeUInt8, depth+1),
+	)
+}
+
+func (g *Generator) boolExpression(depth int) string {
+	if depth >= maxExpressionDepth {
+		return fmt.Sprint(g.chance(2))
+	}
+
+	switch g.rand.Intn(7) {
+	case 0:
+		return fmt.Sprint(g.chance(2))
+
+	case 1, 2:
+		operators := []string{"<", "<=", ">", ">=", "==", "!="}
+		return fmt.Sprintf(
+			"(%s %s %s)",
+			g.expression(typeInt, depth+1),
+			operators[g.rand.Intn(len(operators))],
+			g.expression(typeInt, depth+1),
+		)
+
+	case 3:
+		operators := []string{"&&", "||", "==", "!="}
+		return fmt.Sprintf(
+			"(%s %s %s)",
+			g.expression(typeBool, depth+1),
+			operators[g.rand.Intn(len(operators))],
+			g.expression(typeBool, depth+1),
+		)
+
+	case 4:
+		return fmt.Sprintf("!%s", g.expression(typeBool, depth+1))
+
+	case 5:
+		return fmt.Sprintf(
+			"(%s == %s)",
+			g.expression(typeString, depth+1),
+			g.expression(typeString, depth+1),
+		)
+
+	default:
+		return fmt.Sprintf("(%s == nil)", g.optionalIntOperand(depth+1))
+	}
+}
+
+func (g *Generator) stringExpression(depth int) string {
+	if depth >= maxExpressionDepth || g.chance(2) {
+		return fmt.Sprintf("%q", stringLiterals[g.rand.Intn(len(stringLiterals))])
+	}
+
+	if g.chance(2) {
+		return fmt.Sprintf("%s.name", g.expression(typeStruct, depth+1))
+	}
+
+	return fmt.Sprintf(
+		"%s.concat(%s)",
+		g.expression(typeString, depth+1),
+		g.expression(typeString, depth+1),
+	)
+}
+
+// arrayLiteral returns an array literal.
+// Empty literals are only valid where the type is inferred from the context
+//
+func (g *Generator) arrayLiteral(allowEmpty bool) string {
+	count := g.rand.Intn(4)
+	if !allowEmpty && count == 0 {
+		count = 1
+	}
+	elements := make([]string, count)
+	for i := range elements {
+		elements[i] = g.expression(typeInt, maxExpressionDepth)
+	}
+	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+}
+
+func (g *Generator) optionalIntExpression(depth int) string {
+	if depth >= maxExpressionDepth {
+		return "nil"
+	}
+
+	switch g.rand.Intn(3) {
+	case 0:
+		return "nil"
+
+	case 1:
+		return g.expression(typeInt, depth+1)
+
+	default:
+		return g.optionalIntOperand(depth)
+	}
+}
+
+// optionalIntOperand returns an expression which has the type `Int?`
+// without relying on the context, e.g. as the left-hand side of a nil-coalescing expression
+//
+func (g *Generator) optionalIntOperand(depth int) string {
+	variables := g.variables(typeOptionalInt, false)
+	if len(variables) > 0 && g.chance(3) {
+		return variables[g.rand.Intn(len(variables))].name
+	}
+
+	structs := g.variables(typeOptionalStruct, false)
+	if len(structs) > 0 && g.chance(2) {
+		return fmt.Sprintf(
+			"%s?.add(%s)",
+			structs[g.rand.Intn(len(structs))].name,
+			g.expression(typeInt, maxExpressionDepth),
+		)
+	}
+
+	return fmt.Sprintf(
+		"%s[%s]",
+		g.expression(typeDictionary, maxExpressionDepth),
+		g.expression(typeString, maxExpressionDepth),
+	)
+}
+
+// dictionaryLiteral returns a dictionary literal.
+// Empty literals are only valid where the type is inferred from the context
+//
+func (g *Generator) dictionaryLiteral(allowEmpty bool) string {
+	count := g.rand.Intn(3)
+	if !allowEmpty && count == 0 {
+		count = 1
+	}
+	entries := make([]string, count)
+	for i := range entries {
+		entries[i] = fmt.Sprintf(
+			"%q: %s",
+			stringLiterals[g.rand.Intn(len(stringLiterals))],
+			g.expression(typeInt, maxExpressionDepth),
+		)
+	}
+	return fmt.Sprintf("{%s}", strings.Join(entries, ", "))
+}
+
+func (g *Generator) structExpression(depth int) string {
+	return fmt.Sprintf(
+		"S(x: %s, name: %s)",
+		g.expression(typeInt, maxExpressionDepth),
+		g.expression(typeString, maxExpressionDepth),
+	)
+}
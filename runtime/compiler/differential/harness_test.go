@@ -0,0 +1,406 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package differential
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/vm"
+)
+
+// backends are the backends the compiled programs are executed with
+//
+var backends = map[string]Backend{
+	"go":       WASMBackend(vm.NewGoVM),
+	"bytecode": BytecodeBackend,
+}
+
+func testDifferential(t *testing.T, code string) {
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			mismatches, err := Run(code, backend)
+			require.NoError(t, err)
+
+			for _, mismatch := range mismatches {
+				t.Error(mismatch)
+			}
+		})
+	}
+}
+
+func TestDifferential(t *testing.T) {
+
+	t.Parallel()
+
+	tests := map[string]string{
+		"arithmetic": `
+          pub fun test(): Int {
+              let a = 1 + 2 * 3
+              return a - 10 / 3 % 2
+          }
+        `,
+		"overflow": `
+          pub fun test(): UInt8 {
+              let a: UInt8 = 255
+              return a + 1
+          }
+        `,
+		"division by zero": `
+          pub fun test(): Int {
+              let zero = 0
+              return 1 / zero
+          }
+        `,
+		"loop": `
+          pub fun test(): [Int] {
+              let values: [Int] = []
+              var i = 0
+              while i < 10 {
+                  i = i + 1
+                  if i % 2 == 0 {
+                      continue
+                  }
+                  if i > 7 {
+                      break
+                  }
+                  values.append(i)
+              }
+              return values
+          }
+        `,
+		"for-in": `
+          pub fun test(): Int {
+              var sum = 0
+              for value in [1, 2, 3] {
+                  sum = sum + value
+              }
+              return sum
+          }
+        `,
+		"dictionary": `
+          pub fun test(): Int {
+              let values: {String: Int} = {"a": 1}
+              values["b"] = 2
+              return (values["a"] ?? 0) + (values["c"] ?? 3)
+          }
+        `,
+		"optional chaining": `
+          pub struct S {
+              pub fun value(): Int? {
+                  return 1
+              }
+          }
+
+          pub fun test(): Int?? {
+              let s: S? = S()
+              return s?.value()
+          }
+        `,
+		"resource": `
+          pub event Destroyed(value: Int)
+
+          pub resource R {
+              pub var value: Int
+
+              init(value: Int) {
+                  self.value = value
+              }
+
+              pub fun inc() {
+                  self.value = self.value + 1
+              }
+
+              destroy() {
+                  emit Destroyed(value: self.value)
+              }
+          }
+
+          pub fun test(): UInt64 {
+              let r <- create R(value: 1)
+              r.inc()
+              let uuid = r.uuid
+              destroy r
+              return uuid
+          }
+        `,
+		"conditions": `
+          fun half(_ n: Int): Int {
+              pre {
+                  n % 2 == 0: "n must be even"
+              }
+              post {
+                  result * 2 == n
+              }
+              return n / 2
+          }
+
+          pub fun even(): Int {
+              return half(4)
+          }
+
+          pub fun odd(): Int {
+              return half(3)
+          }
+        `,
+		"failed assertion": `
+          pub fun test() {
+              assert(1 == 2, message: "not equal")
+          }
+        `,
+		"switch": `
+          fun name(_ n: Int): String {
+              switch n {
+              case 1:
+                  return "one"
+              case 2:
+                  return "two"
+              default:
+                  return "many"
+              }
+          }
+
+          pub fun test(): [String] {
+              return [name(1), name(2), name(3)]
+          }
+        `,
+		"swap": `
+          pub fun test(): [Int] {
+              var a = 1
+              var b = 2
+              a <-> b
+              let values = [3, 4]
+              values[0] <-> values[1]
+              return [a, b, values[0], values[1]]
+          }
+        `,
+		"optional binding": `
+          fun unwrap(_ value: Int?): Int {
+              if let unwrapped = value {
+                  return unwrapped
+              } else {
+                  return -1
+              }
+          }
+
+          pub fun test(): [Int] {
+              return [unwrap(1), unwrap(nil)]
+          }
+        `,
+		"casting": `
+          pub fun failable(): Int? {
+              let value: AnyStruct = 1
+              return value as? Int
+          }
+
+          pub fun failed(): String? {
+              let value: AnyStruct = 1
+              return value as? String
+          }
+
+          pub fun force(): String {
+              let value: AnyStruct = 1
+              return value as! String
+          }
+        `,
+		"reference": `
+          pub struct S {
+              pub var value: Int
+
+              init() {
+                  self.value = 1
+              }
+
+              pub fun inc() {
+                  self.value = self.value + 1
+              }
+          }
+
+          pub fun test(): Int {
+              let s = S()
+              let ref = &s as &S
+              ref.inc()
+              return ref.value
+          }
+        `,
+		"literals": `
+          pub fun test(): [AnyStruct] {
+              let c: Character = "c"
+              return ["s", c, 1.5, -2.25 as Fix64, 0x1 as Address, /storage/path, true, nil]
+          }
+        `,
+		"global variables": `
+          let base = 10
+          var counter = base + 1
+          var other = 0
+
+          pub fun test(): [Int] {
+              counter = counter + 1
+              other <-> counter
+              return [base, counter, other]
+          }
+        `,
+		"enum": `
+          pub enum Color: UInt8 {
+              pub case red
+              pub case green
+              pub case blue
+          }
+
+          pub fun rawValue(): UInt8 {
+              return Color.blue.rawValue
+          }
+
+          pub fun construct(): [UInt8?] {
+              return [Color(rawValue: 1)?.rawValue, Color(rawValue: 3)?.rawValue]
+          }
+
+          pub fun compare(): [Bool] {
+              let color = Color.green
+              return [color == Color.green, color == Color.red]
+          }
+
+          pub fun select(): String {
+              switch Color.red {
+              case Color.red:
+                  return "red"
+              default:
+                  return "other"
+              }
+          }
+        `,
+		"string template": `
+          pub fun test(): String {
+              let name = "world"
+              let count: UInt8 = 3
+              return "hello \(name), \(count) times: \(true) \(1.5) \(0x1 as Address) \(/storage/path)"
+          }
+        `,
+		"optional reference": `
+          pub struct S {
+              pub var value: Int
+
+              init() {
+                  self.value = 1
+              }
+
+              pub fun inc() {
+                  self.value = self.value + 1
+              }
+          }
+
+          pub fun some(): Int? {
+              let s: S? = S()
+              let ref = &s as &S?
+              ref?.inc()
+              return ref?.value
+          }
+
+          pub fun none(): Int? {
+              let s: S? = nil
+              let ref = &s as &S?
+              return ref?.value
+          }
+        `,
+		"interface function": `
+          pub struct interface Shape {
+              pub fun area(): Int
+          }
+
+          pub struct Square: Shape {
+              pub let side: Int
+
+              init(side: Int) {
+                  self.side = side
+              }
+
+              pub fun area(): Int {
+                  return self.side * self.side
+              }
+          }
+
+          pub struct Rectangle: Shape {
+              pub let width: Int
+              pub let height: Int
+
+              init(width: Int, height: Int) {
+                  self.width = width
+                  self.height = height
+              }
+
+              pub fun area(): Int {
+                  return self.width * self.height
+              }
+          }
+
+          pub fun test(): [Int] {
+              let shapes: [{Shape}] = [Square(side: 2), Rectangle(width: 2, height: 3)]
+              return [shapes[0].area(), shapes[1].area()]
+          }
+
+          pub fun reference(): Int {
+              let square = Square(side: 3)
+              let ref = &square as &{Shape}
+              return ref.area()
+          }
+
+          pub fun optional(): [Int?] {
+              let some: {Shape}? = Square(side: 4)
+              let none: {Shape}? = nil
+              return [some?.area(), none?.area()]
+          }
+        `,
+	}
+
+	for name, code := range tests {
+		code := code
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			testDifferential(t, code)
+		})
+	}
+}
+
+// TestDifferentialGenerated runs the programs of the generated corpus.
+// Regenerate the corpus using `go generate`
+//
+func TestDifferentialGenerated(t *testing.T) {
+
+	t.Parallel()
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "generated", "*.cdc"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		path := path
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			t.Parallel()
+
+			code, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			testDifferential(t, string(code))
+		})
+	}
+}
@@ -0,0 +1,38 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+)
+
+// UnsupportedFeatureError is reported when the program
+// uses a language feature which cannot be compiled yet.
+// See Compile for the unsupported subset of the language
+//
+type UnsupportedFeatureError struct {
+	Feature string
+	ast.Range
+}
+
+func (e *UnsupportedFeatureError) Error() string {
+	return fmt.Sprintf("compilation of %s is not supported yet", e.Feature)
+}
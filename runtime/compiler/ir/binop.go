@@ -25,4 +25,19 @@ type BinOp uint
 const (
 	BinOpUnknown BinOp = iota
 	BinOpPlus
+	BinOpMinus
+	BinOpMul
+	BinOpDiv
+	BinOpMod
+	BinOpEqual
+	BinOpNotEqual
+	BinOpLess
+	BinOpLessEqual
+	BinOpGreater
+	BinOpGreaterEqual
+	BinOpBitwiseOr
+	BinOpBitwiseXor
+	BinOpBitwiseAnd
+	BinOpBitwiseLeftShift
+	BinOpBitwiseRightShift
 )
@@ -18,6 +18,11 @@
 
 package ir
 
+import (
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
 type Constant interface {
 	isConstant()
 	Accept(Visitor) Repr
@@ -42,3 +47,67 @@ func (String) isConstant() {}
 func (c String) Accept(v Visitor) Repr {
 	return v.VisitString(c)
 }
+
+type Character struct {
+	Value string
+}
+
+func (Character) isConstant() {}
+
+func (c Character) Accept(v Visitor) Repr {
+	return v.VisitCharacter(c)
+}
+
+type Bool struct {
+	Value bool
+}
+
+func (Bool) isConstant() {}
+
+func (c Bool) Accept(v Visitor) Repr {
+	return v.VisitBool(c)
+}
+
+type Nil struct{}
+
+func (Nil) isConstant() {}
+
+func (c Nil) Accept(v Visitor) Repr {
+	return v.VisitNil(c)
+}
+
+// Number is a constant of a fixed-size integer type or a fixed-point type.
+// The value is encoded like the value of an Int constant.
+// For fixed-point types, the value is the scaled integer.
+//
+type Number struct {
+	Type  sema.Type
+	Value []byte
+}
+
+func (Number) isConstant() {}
+
+func (c Number) Accept(v Visitor) Repr {
+	return v.VisitNumber(c)
+}
+
+type Address struct {
+	Value common.Address
+}
+
+func (Address) isConstant() {}
+
+func (c Address) Accept(v Visitor) Repr {
+	return v.VisitAddress(c)
+}
+
+type Path struct {
+	Domain     common.PathDomain
+	Identifier string
+}
+
+func (Path) isConstant() {}
+
+func (c Path) Accept(v Visitor) Repr {
+	return v.VisitPath(c)
+}
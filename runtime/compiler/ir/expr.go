@@ -18,6 +18,10 @@
 
 package ir
 
+import (
+	"github.com/onflow/cadence/runtime/sema"
+)
+
 type Expr interface {
 	isExpr()
 	Accept(Visitor) Repr
@@ -53,6 +57,18 @@ func (e *MoveLocal) Accept(v Visitor) Repr {
 	return v.VisitMoveLocal(e)
 }
 
+// GetGlobal evaluates to the value of the global variable with the given name
+//
+type GetGlobal struct {
+	Name string
+}
+
+func (*GetGlobal) isExpr() {}
+
+func (e *GetGlobal) Accept(v Visitor) Repr {
+	return v.VisitGetGlobal(e)
+}
+
 type UnOpExpr struct {
 	Op   UnOp
 	Expr Expr
@@ -86,3 +102,209 @@ func (*Call) isExpr() {}
 func (e *Call) Accept(v Visitor) Repr {
 	return v.VisitCall(e)
 }
+
+// TeeLocal stores the value of the expression in the local,
+// and also evaluates to the value
+//
+type TeeLocal struct {
+	LocalIndex uint32
+	Exp        Expr
+}
+
+func (*TeeLocal) isExpr() {}
+
+func (e *TeeLocal) Accept(v Visitor) Repr {
+	return v.VisitTeeLocal(e)
+}
+
+type Conditional struct {
+	Test Expr
+	Then Expr
+	Else Expr
+}
+
+func (*Conditional) isExpr() {}
+
+func (e *Conditional) Accept(v Visitor) Repr {
+	return v.VisitConditional(e)
+}
+
+// CallMember invokes a member function of a value
+// which is not implemented by a compiled function,
+// e.g. a built-in function of an array.
+//
+type CallMember struct {
+	Target    Expr
+	Name      string
+	Optional  bool
+	Arguments []Expr
+}
+
+func (*CallMember) isExpr() {}
+
+func (e *CallMember) Accept(v Visitor) Repr {
+	return v.VisitCallMember(e)
+}
+
+// CallBuiltin invokes a built-in function,
+// e.g. `panic` or a number conversion function.
+//
+type CallBuiltin struct {
+	Name      string
+	Arguments []Expr
+}
+
+func (*CallBuiltin) isExpr() {}
+
+func (e *CallBuiltin) Accept(v Visitor) Repr {
+	return v.VisitCallBuiltin(e)
+}
+
+type GetMember struct {
+	Target   Expr
+	Name     string
+	Optional bool
+}
+
+func (*GetMember) isExpr() {}
+
+func (e *GetMember) Accept(v Visitor) Repr {
+	return v.VisitGetMember(e)
+}
+
+type GetIndex struct {
+	Target Expr
+	Index  Expr
+}
+
+func (*GetIndex) isExpr() {}
+
+func (e *GetIndex) Accept(v Visitor) Repr {
+	return v.VisitGetIndex(e)
+}
+
+type NewArray struct {
+	Type     sema.ArrayType
+	Elements []Expr
+}
+
+func (*NewArray) isExpr() {}
+
+func (e *NewArray) Accept(v Visitor) Repr {
+	return v.VisitNewArray(e)
+}
+
+type DictionaryEntry struct {
+	Key   Expr
+	Value Expr
+}
+
+type NewDictionary struct {
+	Type    *sema.DictionaryType
+	Entries []DictionaryEntry
+}
+
+func (*NewDictionary) isExpr() {}
+
+func (e *NewDictionary) Accept(v Visitor) Repr {
+	return v.VisitNewDictionary(e)
+}
+
+// NewComposite creates a new composite value with no fields.
+// It is used in constructor functions.
+//
+type NewComposite struct {
+	Type *sema.CompositeType
+}
+
+func (*NewComposite) isExpr() {}
+
+func (e *NewComposite) Accept(v Visitor) Repr {
+	return v.VisitNewComposite(e)
+}
+
+// Cast is a failable or force cast.
+// Static casts do not need to be checked at run-time.
+//
+type Cast struct {
+	Exp   Expr
+	Type  sema.Type
+	Force bool
+}
+
+func (*Cast) isExpr() {}
+
+func (e *Cast) Accept(v Visitor) Repr {
+	return v.VisitCast(e)
+}
+
+// Box boxes the value into optionals until it has the given optional type.
+// Like in the interpreter, the boxing depends on the value at run-time,
+// as a value of an optional type may not be an optional value
+//
+type Box struct {
+	Exp  Expr
+	Type sema.Type
+}
+
+func (*Box) isExpr() {}
+
+func (e *Box) Accept(v Visitor) Repr {
+	return v.VisitBox(e)
+}
+
+// Reference creates a reference to the value.
+// Like in the interpreter, if the type is an optional reference type,
+// the reference to an optional value is an optional reference
+//
+type Reference struct {
+	Exp  Expr
+	Type sema.Type
+}
+
+func (*Reference) isExpr() {}
+
+func (e *Reference) Accept(v Visitor) Repr {
+	return v.VisitReference(e)
+}
+
+type Destroy struct {
+	Exp Expr
+}
+
+func (*Destroy) isExpr() {}
+
+func (e *Destroy) Accept(v Visitor) Repr {
+	return v.VisitDestroy(e)
+}
+
+// IsComposite tests if the value is a composite value of the given type,
+// or a reference to such a value.
+// It is used to dispatch invocations of interface functions to the compiled member functions
+//
+type IsComposite struct {
+	Exp  Expr
+	Type *sema.CompositeType
+}
+
+func (*IsComposite) isExpr() {}
+
+func (e *IsComposite) Accept(v Visitor) Repr {
+	return v.VisitIsComposite(e)
+}
+
+// StringTemplate creates a string from the literal parts of a string template
+// and the values embedded in it.
+// There is always one more literal part than there are embedded values:
+// Values[i] precedes Exps[i], and the last value follows the last expression
+//
+type StringTemplate struct {
+	Values []string
+	Exps   []Expr
+}
+
+func (*StringTemplate) isExpr() {}
+
+func (e *StringTemplate) Accept(v Visitor) Repr {
+	return v.VisitStringTemplate(e)
+}
@@ -23,6 +23,9 @@ type Func struct {
 	Type      FuncType
 	Locals    []Local
 	Statement Stmt
+	// Start is true if the function initializes the program, e.g. its global variables.
+	// It is called once, before any other function is invoked
+	Start bool
 }
 
 func (f *Func) Accept(v Visitor) Repr {
@@ -18,6 +18,10 @@
 
 package ir
 
+import (
+	"github.com/onflow/cadence/runtime/ast"
+)
+
 type Stmt interface {
 	isStmt()
 	Accept(Visitor) Repr
@@ -97,6 +101,19 @@ func (s *StoreLocal) Accept(v Visitor) Repr {
 	return v.VisitStoreLocal(s)
 }
 
+// SetGlobal stores the value of the expression in the global variable with the given name
+//
+type SetGlobal struct {
+	Name string
+	Exp  Expr
+}
+
+func (*SetGlobal) isStmt() {}
+
+func (s *SetGlobal) Accept(v Visitor) Repr {
+	return v.VisitSetGlobal(s)
+}
+
 type Drop struct {
 	Exp Expr
 }
@@ -116,3 +133,51 @@ func (*Return) isStmt() {}
 func (s *Return) Accept(v Visitor) Repr {
 	return v.VisitReturn(s)
 }
+
+type SetMember struct {
+	Target Expr
+	Name   string
+	Exp    Expr
+}
+
+func (*SetMember) isStmt() {}
+
+func (s *SetMember) Accept(v Visitor) Repr {
+	return v.VisitSetMember(s)
+}
+
+type SetIndex struct {
+	Target Expr
+	Index  Expr
+	Exp    Expr
+}
+
+func (*SetIndex) isStmt() {}
+
+func (s *SetIndex) Accept(v Visitor) Repr {
+	return v.VisitSetIndex(s)
+}
+
+type Emit struct {
+	Exp Expr
+}
+
+func (*Emit) isStmt() {}
+
+func (s *Emit) Accept(v Visitor) Repr {
+	return v.VisitEmit(s)
+}
+
+// FailCondition aborts execution because a pre-condition
+// or post-condition of a function failed
+//
+type FailCondition struct {
+	Kind    ast.ConditionKind
+	Message Expr
+}
+
+func (*FailCondition) isStmt() {}
+
+func (s *FailCondition) Accept(v Visitor) Repr {
+	return v.VisitFailCondition(s)
+}
@@ -24,4 +24,10 @@ type UnOp uint
 
 const (
 	UnOpUnknown UnOp = iota
+	UnOpNegate
+	UnOpNot
+	UnOpForce
+	UnOpIsNil
+	UnOpSome
+	UnOpTransfer
 )
@@ -9,11 +9,17 @@ func _() {
 	// Re-run the stringer command to generate them again.
 	var x [1]struct{}
 	_ = x[UnOpUnknown-0]
+	_ = x[UnOpNegate-1]
+	_ = x[UnOpNot-2]
+	_ = x[UnOpForce-3]
+	_ = x[UnOpIsNil-4]
+	_ = x[UnOpSome-5]
+	_ = x[UnOpTransfer-6]
 }
 
-const _UnOp_name = "UnOpUnknown"
+const _UnOp_name = "UnOpUnknownUnOpNegateUnOpNotUnOpForceUnOpIsNilUnOpSomeUnOpTransfer"
 
-var _UnOp_index = [...]uint8{0, 11}
+var _UnOp_index = [...]uint8{0, 11, 21, 28, 37, 46, 54, 66}
 
 func (i UnOp) String() string {
 	if i >= UnOp(len(_UnOp_index)-1) {
@@ -26,4 +26,14 @@ const (
 	ValTypeUnknown ValType = iota
 	ValTypeInt
 	ValTypeString
+	ValTypeBool
+	ValTypeNumber
+	ValTypeAddress
+	ValTypePath
+	ValTypeOptional
+	ValTypeArray
+	ValTypeDictionary
+	ValTypeComposite
+	ValTypeReference
+	ValTypeAny
 )
@@ -11,11 +11,21 @@ func _() {
 	_ = x[ValTypeUnknown-0]
 	_ = x[ValTypeInt-1]
 	_ = x[ValTypeString-2]
+	_ = x[ValTypeBool-3]
+	_ = x[ValTypeNumber-4]
+	_ = x[ValTypeAddress-5]
+	_ = x[ValTypePath-6]
+	_ = x[ValTypeOptional-7]
+	_ = x[ValTypeArray-8]
+	_ = x[ValTypeDictionary-9]
+	_ = x[ValTypeComposite-10]
+	_ = x[ValTypeReference-11]
+	_ = x[ValTypeAny-12]
 }
 
-const _ValType_name = "ValTypeUnknownValTypeIntValTypeString"
+const _ValType_name = "ValTypeUnknownValTypeIntValTypeStringValTypeBoolValTypeNumberValTypeAddressValTypePathValTypeOptionalValTypeArrayValTypeDictionaryValTypeCompositeValTypeReferenceValTypeAny"
 
-var _ValType_index = [...]uint8{0, 14, 24, 37}
+var _ValType_index = [...]uint8{0, 14, 24, 37, 48, 61, 75, 86, 101, 113, 130, 146, 162, 172}
 
 func (i ValType) String() string {
 	if i >= ValType(len(_ValType_index)-1) {
@@ -23,6 +23,12 @@ type Repr any
 type ConstVisitor interface {
 	VisitInt(Int) Repr
 	VisitString(String) Repr
+	VisitCharacter(Character) Repr
+	VisitBool(Bool) Repr
+	VisitNil(Nil) Repr
+	VisitNumber(Number) Repr
+	VisitAddress(Address) Repr
+	VisitPath(Path) Repr
 }
 
 type StmtVisitor interface {
@@ -33,17 +39,38 @@ type StmtVisitor interface {
 	VisitBranch(*Branch) Repr
 	VisitBranchIf(*BranchIf) Repr
 	VisitStoreLocal(*StoreLocal) Repr
+	VisitSetGlobal(*SetGlobal) Repr
 	VisitDrop(*Drop) Repr
 	VisitReturn(*Return) Repr
+	VisitSetMember(*SetMember) Repr
+	VisitSetIndex(*SetIndex) Repr
+	VisitEmit(*Emit) Repr
+	VisitFailCondition(*FailCondition) Repr
 }
 
 type ExprVisitor interface {
 	VisitConst(*Const) Repr
 	VisitCopyLocal(*CopyLocal) Repr
 	VisitMoveLocal(*MoveLocal) Repr
+	VisitGetGlobal(*GetGlobal) Repr
 	VisitUnOpExpr(*UnOpExpr) Repr
 	VisitBinOpExpr(*BinOpExpr) Repr
 	VisitCall(*Call) Repr
+	VisitTeeLocal(*TeeLocal) Repr
+	VisitConditional(*Conditional) Repr
+	VisitCallMember(*CallMember) Repr
+	VisitCallBuiltin(*CallBuiltin) Repr
+	VisitGetMember(*GetMember) Repr
+	VisitGetIndex(*GetIndex) Repr
+	VisitNewArray(*NewArray) Repr
+	VisitNewDictionary(*NewDictionary) Repr
+	VisitNewComposite(*NewComposite) Repr
+	VisitCast(*Cast) Repr
+	VisitBox(*Box) Repr
+	VisitReference(*Reference) Repr
+	VisitDestroy(*Destroy) Repr
+	VisitIsComposite(*IsComposite) Repr
+	VisitStringTemplate(*StringTemplate) Repr
 }
 
 type Visitor interface {
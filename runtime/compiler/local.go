@@ -20,11 +20,14 @@ package compiler
 
 import (
 	"github.com/onflow/cadence/runtime/compiler/ir"
+	"github.com/onflow/cadence/runtime/sema"
 )
 
 type Local struct {
 	Index uint32
 	Type  ir.ValType
+	// SemaType is the type of the variable, if it is known
+	SemaType sema.Type
 }
 
 func NewLocal(index uint32, valType ir.ValType) *Local {
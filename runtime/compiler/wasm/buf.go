@@ -31,6 +31,15 @@ type Buffer struct {
 	offset offset
 }
 
+// NewBuffer returns a new buffer for the given data,
+// positioned at the start of the data.
+//
+func NewBuffer(data []byte) *Buffer {
+	return &Buffer{
+		data: data,
+	}
+}
+
 func (buf *Buffer) WriteByte(b byte) error {
 	if buf.offset < offset(len(buf.data)) {
 		buf.data[buf.offset] = b
@@ -977,3 +977,86 @@ func (e InvalidStartSectionFunctionIndexError) Error() string {
 func (e InvalidStartSectionFunctionIndexError) Unwrap() error {
 	return e.ReadError
 }
+
+// WATPosition is a position in a WAT text
+//
+type WATPosition struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+func (p WATPosition) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// InvalidWATSyntaxError is returned when the WAT text is not well-formed,
+// e.g. a parenthesis or an expected keyword is missing
+//
+type InvalidWATSyntaxError struct {
+	Position WATPosition
+	Message  string
+}
+
+func (e InvalidWATSyntaxError) Error() string {
+	return fmt.Sprintf(
+		"invalid WAT syntax at %s: %s",
+		e.Position,
+		e.Message,
+	)
+}
+
+// UnknownWATInstructionError is returned when the WAT text contains
+// an instruction which is not supported
+//
+type UnknownWATInstructionError struct {
+	Position WATPosition
+	Name     string
+}
+
+func (e UnknownWATInstructionError) Error() string {
+	return fmt.Sprintf(
+		"unknown instruction %s at %s",
+		e.Name,
+		e.Position,
+	)
+}
+
+// UnknownWATIdentifierError is returned when the WAT text refers to
+// an identifier which is not declared, e.g. a function or a label
+//
+type UnknownWATIdentifierError struct {
+	Position   WATPosition
+	Identifier string
+	IndexSpace string
+}
+
+func (e UnknownWATIdentifierError) Error() string {
+	return fmt.Sprintf(
+		"unknown %s %s at %s",
+		e.IndexSpace,
+		e.Identifier,
+		e.Position,
+	)
+}
+
+// InvalidWATIntegerError is returned when the WAT text contains
+// an invalid integer literal, or an integer which is out of range
+//
+type InvalidWATIntegerError struct {
+	Position   WATPosition
+	Literal    string
+	ParseError error
+}
+
+func (e InvalidWATIntegerError) Error() string {
+	return fmt.Sprintf(
+		"invalid integer %s at %s",
+		e.Literal,
+		e.Position,
+	)
+}
+
+func (e InvalidWATIntegerError) Unwrap() error {
+	return e.ParseError
+}
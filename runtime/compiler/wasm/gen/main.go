@@ -19,7 +19,9 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
+	"go/format"
 	"os"
 	"regexp"
 	"strings"
@@ -110,6 +112,49 @@ func (r *WASMReader) readInstruction() (Instruction, error) {
 }
 `
 
+const watFileTemplate = `// Code generated by utils/version. DO NOT EDIT.
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wasm
+
+// parsePlainInstruction parses the immediate arguments of the instruction with the given name
+// in the WAT text, i.e. the instruction in its plain (unfolded) form, after its name
+//
+func (p *watParser) parsePlainInstruction(name string) (Instruction, error) {
+	switch name {
+{{- range .Instructions}}
+	case "{{.Name}}":
+{{- range .Arguments}}
+		{{.Type.ParseWAT .Variable}}
+{{end}}
+		return Instruction{{.Identifier}}{{if .Arguments}}{
+{{- range .Arguments}}
+			{{.Identifier}}: {{.Variable}},{{end}}
+		}
+{{- else}}{}{{- end}}, nil
+{{end}}
+	default:
+		return nil, p.unknownInstructionError(name)
+	}
+}
+`
+
 const switchTemplate = `
 switch c {
 {{- range $key, $group := . }}
@@ -144,9 +189,16 @@ type argumentType interface {
 	FieldType() string
 	Read(variable string) string
 	Write(variable string) string
+	ParseWAT(variable string) string
 }
 
-type ArgumentTypeUint32 struct{}
+// ArgumentTypeUint32 is an index argument.
+// In the text format, the index may also be given as an identifier
+// of the index space
+//
+type ArgumentTypeUint32 struct {
+	IndexSpace string
+}
 
 func (t ArgumentTypeUint32) isArgumentType() {}
 
@@ -174,6 +226,51 @@ func (t ArgumentTypeUint32) Write(variable string) string {
 	)
 }
 
+func (t ArgumentTypeUint32) IndexSpaceIdentifier() string {
+	return "watIndexSpace" + strings.ToUpper(t.IndexSpace[:1]) + t.IndexSpace[1:]
+}
+
+func (t ArgumentTypeUint32) ParseWAT(variable string) string {
+	return fmt.Sprintf(
+		`%s, err := p.parseIndexInstructionArgument(%s)
+	if err != nil {
+		return nil, err
+	}`,
+		variable,
+		t.IndexSpaceIdentifier(),
+	)
+}
+
+// ArgumentTypeReferenceType is a reference type argument.
+// It is encoded like an index in the binary format,
+// and is given as a keyword in the text format, e.g. `func` or `extern`
+//
+type ArgumentTypeReferenceType struct{}
+
+func (t ArgumentTypeReferenceType) isArgumentType() {}
+
+func (t ArgumentTypeReferenceType) FieldType() string {
+	return "uint32"
+}
+
+func (t ArgumentTypeReferenceType) Read(variable string) string {
+	return ArgumentTypeUint32{}.Read(variable)
+}
+
+func (t ArgumentTypeReferenceType) Write(variable string) string {
+	return ArgumentTypeUint32{}.Write(variable)
+}
+
+func (t ArgumentTypeReferenceType) ParseWAT(variable string) string {
+	return fmt.Sprintf(
+		`%s, err := p.parseReferenceTypeInstructionArgument()
+	if err != nil {
+		return nil, err
+	}`,
+		variable,
+	)
+}
+
 type ArgumentTypeInt32 struct{}
 
 func (t ArgumentTypeInt32) isArgumentType() {}
@@ -202,6 +299,16 @@ func (t ArgumentTypeInt32) Write(variable string) string {
 	)
 }
 
+func (t ArgumentTypeInt32) ParseWAT(variable string) string {
+	return fmt.Sprintf(
+		`%s, err := p.parseInt32InstructionArgument()
+	if err != nil {
+		return nil, err
+	}`,
+		variable,
+	)
+}
+
 type ArgumentTypeInt64 struct{}
 
 func (t ArgumentTypeInt64) isArgumentType() {}
@@ -230,6 +337,16 @@ func (t ArgumentTypeInt64) Write(variable string) string {
 	)
 }
 
+func (t ArgumentTypeInt64) ParseWAT(variable string) string {
+	return fmt.Sprintf(
+		`%s, err := p.parseInt64InstructionArgument()
+	if err != nil {
+		return nil, err
+	}`,
+		variable,
+	)
+}
+
 type ArgumentTypeBlock struct {
 	AllowElse bool
 }
@@ -262,6 +379,17 @@ func (t ArgumentTypeBlock) Write(variable string) string {
 	)
 }
 
+func (t ArgumentTypeBlock) ParseWAT(variable string) string {
+	return fmt.Sprintf(
+		`%s, err := p.parseBlockInstructionArgument(%v)
+	if err != nil {
+		return nil, err
+	}`,
+		variable,
+		t.AllowElse,
+	)
+}
+
 type ArgumentTypeVector struct {
 	ArgumentType argumentType
 }
@@ -322,6 +450,24 @@ func (t ArgumentTypeVector) Write(variable string) string {
 	)
 }
 
+func (t ArgumentTypeVector) ParseWAT(variable string) string {
+	// Only vectors of indices are supported in the text format
+
+	indexArgumentType, ok := t.ArgumentType.(ArgumentTypeUint32)
+	if !ok {
+		panic(fmt.Errorf("unsupported vector element type: %T", t.ArgumentType))
+	}
+
+	return fmt.Sprintf(
+		`%s, err := p.parseIndexVectorInstructionArgument(%s)
+	if err != nil {
+		return nil, err
+	}`,
+		variable,
+		indexArgumentType.IndexSpaceIdentifier(),
+	)
+}
+
 type argument struct {
 	Identifier string
 	Type       argumentType
@@ -416,7 +562,16 @@ var trailingWhitespaceRegexp = regexp.MustCompile("(?m:[ \t]+$)")
 
 const target = "instructions.go"
 
-var indexArgumentType = ArgumentTypeUint32{}
+const watTarget = "wat_instructions.go"
+
+var (
+	labelIndexArgumentType  = ArgumentTypeUint32{IndexSpace: "label"}
+	funcIndexArgumentType   = ArgumentTypeUint32{IndexSpace: "func"}
+	typeIndexArgumentType   = ArgumentTypeUint32{IndexSpace: "type"}
+	tableIndexArgumentType  = ArgumentTypeUint32{IndexSpace: "table"}
+	localIndexArgumentType  = ArgumentTypeUint32{IndexSpace: "local"}
+	globalIndexArgumentType = ArgumentTypeUint32{IndexSpace: "global"}
+)
 
 func main() {
 
@@ -469,16 +624,37 @@ func main() {
 		return b.String(), nil
 	}
 
+	parsedWATFileTemplate := template.Must(
+		template.New("wat_instructions").
+			Parse(watFileTemplate),
+	)
+
 	declare := func(instructions []instruction) {
-		err = parsedFileTemplate.Execute(f,
-			instructionGroup{
-				Depth:        0,
-				Instructions: instructions,
-			},
-		)
+		group := instructionGroup{
+			Depth:        0,
+			Instructions: instructions,
+		}
+
+		err = parsedFileTemplate.Execute(f, group)
+		if err != nil {
+			panic(err)
+		}
+
+		var b bytes.Buffer
+		err = parsedWATFileTemplate.Execute(&b, group)
 		if err != nil {
 			panic(err)
 		}
+
+		formatted, err := format.Source(b.Bytes())
+		if err != nil {
+			panic(fmt.Errorf("could not format %s: %w", watTarget, err))
+		}
+
+		err = os.WriteFile(watTarget, formatted, 0644)
+		if err != nil {
+			panic(fmt.Errorf("could not write %s: %w", watTarget, err))
+		}
 	}
 
 	declare([]instruction{
@@ -523,22 +699,22 @@ func main() {
 			Name:    "br",
 			Opcodes: opcodes{0x0C},
 			Arguments: arguments{
-				{"LabelIndex", indexArgumentType},
+				{"LabelIndex", labelIndexArgumentType},
 			},
 		},
 		{
 			Name:    "br_if",
 			Opcodes: opcodes{0x0D},
 			Arguments: arguments{
-				{"LabelIndex", indexArgumentType},
+				{"LabelIndex", labelIndexArgumentType},
 			},
 		},
 		{
 			Name:    "br_table",
 			Opcodes: opcodes{0x0E},
 			Arguments: arguments{
-				{"LabelIndices", ArgumentTypeVector{indexArgumentType}},
-				{"DefaultLabelIndex", indexArgumentType},
+				{"LabelIndices", ArgumentTypeVector{labelIndexArgumentType}},
+				{"DefaultLabelIndex", labelIndexArgumentType},
 			},
 		},
 		{
@@ -550,15 +726,15 @@ func main() {
 			Name:    "call",
 			Opcodes: opcodes{0x10},
 			Arguments: arguments{
-				{"FuncIndex", indexArgumentType},
+				{"FuncIndex", funcIndexArgumentType},
 			},
 		},
 		{
 			Name:    "call_indirect",
 			Opcodes: opcodes{0x11},
 			Arguments: arguments{
-				{"TypeIndex", indexArgumentType},
-				{"TableIndex", indexArgumentType},
+				{"TypeIndex", typeIndexArgumentType},
+				{"TableIndex", tableIndexArgumentType},
 			},
 		},
 		// Reference Instructions
@@ -566,7 +742,7 @@ func main() {
 			Name:    "ref.null",
 			Opcodes: opcodes{0xD0},
 			Arguments: arguments{
-				{"TypeIndex", indexArgumentType},
+				{"TypeIndex", ArgumentTypeReferenceType{}},
 			},
 		},
 		{
@@ -578,7 +754,7 @@ func main() {
 			Name:    "ref.func",
 			Opcodes: opcodes{0xD2},
 			Arguments: arguments{
-				{"FuncIndex", indexArgumentType},
+				{"FuncIndex", funcIndexArgumentType},
 			},
 		},
 		// Parametric Instructions
@@ -597,35 +773,35 @@ func main() {
 			Name:    "local.get",
 			Opcodes: opcodes{0x20},
 			Arguments: arguments{
-				{"LocalIndex", indexArgumentType},
+				{"LocalIndex", localIndexArgumentType},
 			},
 		},
 		{
 			Name:    "local.set",
 			Opcodes: opcodes{0x21},
 			Arguments: arguments{
-				{"LocalIndex", indexArgumentType},
+				{"LocalIndex", localIndexArgumentType},
 			},
 		},
 		{
 			Name:    "local.tee",
 			Opcodes: opcodes{0x22},
 			Arguments: arguments{
-				{"LocalIndex", indexArgumentType},
+				{"LocalIndex", localIndexArgumentType},
 			},
 		},
 		{
 			Name:    "global.get",
 			Opcodes: opcodes{0x23},
 			Arguments: arguments{
-				{"GlobalIndex", indexArgumentType},
+				{"GlobalIndex", globalIndexArgumentType},
 			},
 		},
 		{
 			Name:    "global.set",
 			Opcodes: opcodes{0x24},
 			Arguments: arguments{
-				{"GlobalIndex", indexArgumentType},
+				{"GlobalIndex", globalIndexArgumentType},
 			},
 		},
 		// Numeric Instructions
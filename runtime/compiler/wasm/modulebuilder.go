@@ -32,6 +32,7 @@ type ModuleBuilder struct {
 	data               []*Data
 	requiredMemorySize uint32
 	exports            []*Export
+	startFunctionIndex *uint32
 }
 
 func (b *ModuleBuilder) AddFunction(name string, functionType *FunctionType, code *Code) uint32 {
@@ -97,12 +98,13 @@ func (b *ModuleBuilder) Build() *Module {
 	}
 
 	return &Module{
-		Types:     b.types,
-		Imports:   b.functionImports,
-		Functions: b.functions,
-		Memories:  memories,
-		Data:      b.data,
-		Exports:   b.exports,
+		Types:              b.types,
+		Imports:            b.functionImports,
+		Functions:          b.functions,
+		Memories:           memories,
+		Data:               b.data,
+		Exports:            b.exports,
+		StartFunctionIndex: b.startFunctionIndex,
 	}
 }
 
@@ -118,3 +120,10 @@ func (b *ModuleBuilder) ExportMemory(name string) {
 func (b *ModuleBuilder) AddExport(export *Export) {
 	b.exports = append(b.exports, export)
 }
+
+// SetStartFunction declares the function with the given index as the start function,
+// which is called when the module is instantiated
+//
+func (b *ModuleBuilder) SetStartFunction(funcIndex uint32) {
+	b.startFunctionIndex = &funcIndex
+}
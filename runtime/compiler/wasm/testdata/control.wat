@@ -0,0 +1,47 @@
+(module
+  (type $binary (func (param i64 i64) (result i64)))
+  (type $nullary (func))
+
+  (import "env" "log" (func $log (param externref)))
+  (func $assert (import "env" "assert") (param i32))
+
+  (func $start (type $nullary)
+    (call $log (ref.null extern)))
+
+  (func $max (type $binary)
+    (select (local.get 0) (local.get 1) (i64.gt_s (local.get 0) (local.get 1))))
+
+  (func $classify (param i32) (result i64)
+    (block $default
+      (block $two
+        (block $one
+          (br_table $one $two $default (local.get 0)))
+        (return (i64.const 0x1_0000)))
+      (return (i64.const -9_223_372_036_854_775_808)))
+    i64.const 18446744073709551615)
+
+  (func $sign (param $x i32) (result i32)
+    local.get $x
+    i32.const 0
+    i32.lt_s
+    if $negative (result i32)
+      i32.const -1
+    else
+      local.get $x
+      i32.eqz
+      if $zero
+        i32.const 0
+        return
+      end $zero
+      i32.const 1
+    end $negative)
+
+  (func $pair (param i32 i32) (result i32 i32)
+    (local.get 1)
+    (local.get 0)
+    (block (param i32 i32) (result i32 i32)
+      nop))
+
+  (export "max" (func $max))
+  (export "classify" (func $classify))
+  (start $start))
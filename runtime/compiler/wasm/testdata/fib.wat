@@ -0,0 +1,40 @@
+;; Computes Fibonacci numbers recursively and iteratively
+(module $fib
+  (memory $mem (export "mem") 1 2)
+
+  (func $fib (export "fib") (param $n i32) (result i32)
+    (if (result i32) (i32.lt_s (local.get $n) (i32.const 2))
+      (then (local.get $n))
+      (else
+        (i32.add
+          (call $fib (i32.sub (local.get $n) (i32.const 1)))
+          (call $fib (i32.sub (local.get $n) (i32.const 2)))))))
+
+  (func $fibIterative (export "fibIterative") (param $n i32) (result i32)
+    (local $a i32) (local $b i32) (local $t i32)
+    i32.const 1
+    local.set $b
+    block $done
+      loop $next
+        local.get $n
+        i32.eqz
+        br_if $done
+        ;; t = a + b, a = b, b = t
+        local.get $a
+        local.get $b
+        i32.add
+        local.set $t
+        local.get $b
+        local.set $a
+        local.get $t
+        local.set $b
+        local.get $n
+        i32.const 1
+        i32.sub
+        local.set $n
+        br $next
+      end
+    end
+    local.get $a)
+
+  (data (i32.const 0) "fib\00" "\u{1F600}"))
@@ -42,7 +42,12 @@
 // - The writer (WASMWriter) allows encoding the representation of the module (Module)
 // to a WebAssembly program in binary form ([]byte).
 //
-// Package wasm does not currently provide a reader and writer for the textual format (WAT).
+// Package wasm also implements a parser for the textual format:
+//
+// - The parser (ParseWAT) allows parsing a WebAssembly module in text form (string)
+// into an representation of the module (Module).
+//
+// Package wasm does not currently provide a writer for the textual format (WAT).
 //
 // Package wasm is not a compiler for Cadence programs, but rather a building block that allows
 // reading and writing WebAssembly modules.
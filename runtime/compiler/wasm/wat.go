@@ -0,0 +1,1771 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wasm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseWAT parses a module in the WebAssembly text format (WAT).
+//
+// The supported subset of the text format corresponds to the module representation (Module):
+// types, function imports, functions, memories, exports, the start function, and data segments.
+// Instructions may be given in plain form, e.g. `local.get 0`,
+// or in folded form, e.g. `(i32.add (local.get 0) (i32.const 1))`.
+// Indices may be given as numbers or as identifiers, e.g. `$add`.
+//
+// Function names are set from the identifiers of the functions
+//
+func ParseWAT(text string) (*Module, error) {
+	tokens, err := tokenizeWAT(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &watParser{
+		tokens:    tokens,
+		module:    &Module{},
+		typeIDs:   map[string]uint32{},
+		funcIDs:   map[string]uint32{},
+		memoryIDs: map[string]uint32{},
+	}
+
+	err = p.parseModule()
+	if err != nil {
+		return nil, err
+	}
+
+	return p.module, nil
+}
+
+// Tokens
+
+type watTokenKind int
+
+const (
+	watTokenKindLeftParen watTokenKind = iota
+	watTokenKindRightParen
+	// watTokenKindKeyword is a keyword or a number
+	watTokenKindKeyword
+	// watTokenKindIdentifier is an identifier, starting with `$`
+	watTokenKindIdentifier
+	watTokenKindString
+	watTokenKindEOF
+)
+
+type watToken struct {
+	kind     watTokenKind
+	text     string
+	position WATPosition
+}
+
+// tokenizeWAT splits the given WAT text into tokens,
+// skipping whitespace and comments.
+// The text of string tokens is the decoded string
+//
+func tokenizeWAT(text string) ([]watToken, error) {
+	var tokens []watToken
+
+	line := 1
+	lineStart := 0
+
+	position := func(offset int) WATPosition {
+		return WATPosition{
+			Offset: offset,
+			Line:   line,
+			Column: offset - lineStart,
+		}
+	}
+
+	offset := 0
+	for offset < len(text) {
+		c := text[offset]
+
+		switch {
+		case c == '\n':
+			offset++
+			line++
+			lineStart = offset
+
+		case c == ' ' || c == '\t' || c == '\r':
+			offset++
+
+		case strings.HasPrefix(text[offset:], ";;"):
+			// line comment
+			end := strings.IndexByte(text[offset:], '\n')
+			if end < 0 {
+				offset = len(text)
+			} else {
+				offset += end
+			}
+
+		case strings.HasPrefix(text[offset:], "(;"):
+			// block comment, which may be nested
+			start := offset
+			depth := 0
+			for {
+				if offset >= len(text) {
+					return nil, InvalidWATSyntaxError{
+						Position: position(start),
+						Message:  "unterminated block comment",
+					}
+				}
+				if strings.HasPrefix(text[offset:], "(;") {
+					depth++
+					offset += 2
+				} else if strings.HasPrefix(text[offset:], ";)") {
+					depth--
+					offset += 2
+					if depth == 0 {
+						break
+					}
+				} else {
+					if text[offset] == '\n' {
+						line++
+						lineStart = offset + 1
+					}
+					offset++
+				}
+			}
+
+		case c == '(':
+			tokens = append(tokens, watToken{
+				kind:     watTokenKindLeftParen,
+				text:     "(",
+				position: position(offset),
+			})
+			offset++
+
+		case c == ')':
+			tokens = append(tokens, watToken{
+				kind:     watTokenKindRightParen,
+				text:     ")",
+				position: position(offset),
+			})
+			offset++
+
+		case c == '"':
+			start := offset
+			value, length, err := decodeWATString(text[offset:])
+			if err != nil {
+				return nil, InvalidWATSyntaxError{
+					Position: position(start),
+					Message:  err.Error(),
+				}
+			}
+			tokens = append(tokens, watToken{
+				kind:     watTokenKindString,
+				text:     value,
+				position: position(start),
+			})
+			offset += length
+
+		default:
+			start := offset
+			for offset < len(text) && isWATIdentifierCharacter(text[offset]) {
+				offset++
+			}
+			if offset == start {
+				return nil, InvalidWATSyntaxError{
+					Position: position(start),
+					Message:  fmt.Sprintf("unexpected character %q", c),
+				}
+			}
+
+			kind := watTokenKindKeyword
+			if c == '$' {
+				kind = watTokenKindIdentifier
+			}
+
+			tokens = append(tokens, watToken{
+				kind:     kind,
+				text:     text[start:offset],
+				position: position(start),
+			})
+		}
+	}
+
+	tokens = append(tokens, watToken{
+		kind:     watTokenKindEOF,
+		position: position(len(text)),
+	})
+
+	return tokens, nil
+}
+
+func isWATIdentifierCharacter(c byte) bool {
+	switch {
+	case c >= '0' && c <= '9',
+		c >= 'a' && c <= 'z',
+		c >= 'A' && c <= 'Z':
+		return true
+	}
+	return strings.IndexByte("!#$%&'*+-./:<=>?@\\^_`|~", c) >= 0
+}
+
+// decodeWATString decodes the string literal at the start of the given text.
+// It returns the decoded string and the length of the literal
+//
+func decodeWATString(text string) (string, int, error) {
+	var builder strings.Builder
+
+	offset := 1
+	for {
+		if offset >= len(text) {
+			return "", 0, fmt.Errorf("unterminated string")
+		}
+
+		c := text[offset]
+		switch c {
+		case '"':
+			return builder.String(), offset + 1, nil
+
+		case '\n':
+			return "", 0, fmt.Errorf("unterminated string")
+
+		case '\\':
+			if offset+1 >= len(text) {
+				return "", 0, fmt.Errorf("unterminated string")
+			}
+			escaped := text[offset+1]
+			offset += 2
+
+			switch escaped {
+			case 't':
+				builder.WriteByte('\t')
+			case 'n':
+				builder.WriteByte('\n')
+			case 'r':
+				builder.WriteByte('\r')
+			case '"', '\'', '\\':
+				builder.WriteByte(escaped)
+			case 'u':
+				end := strings.IndexByte(text[offset:], '}')
+				if !strings.HasPrefix(text[offset:], "{") || end < 0 {
+					return "", 0, fmt.Errorf("invalid unicode escape")
+				}
+				codePoint, err := strconv.ParseUint(text[offset+1:offset+end], 16, 32)
+				if err != nil || !utf8.ValidRune(rune(codePoint)) {
+					return "", 0, fmt.Errorf("invalid unicode escape")
+				}
+				builder.WriteRune(rune(codePoint))
+				offset += end + 1
+			default:
+				if offset >= len(text) {
+					return "", 0, fmt.Errorf("unterminated string")
+				}
+				b, err := strconv.ParseUint(text[offset-1:offset+1], 16, 8)
+				if err != nil {
+					return "", 0, fmt.Errorf("invalid escape sequence")
+				}
+				builder.WriteByte(byte(b))
+				offset++
+			}
+
+		default:
+			builder.WriteByte(c)
+			offset++
+		}
+	}
+}
+
+// Parser
+
+// watIndexSpace is an index space, in which identifiers are resolved to indices
+//
+type watIndexSpace string
+
+const (
+	watIndexSpaceType   watIndexSpace = "type"
+	watIndexSpaceFunc   watIndexSpace = "function"
+	watIndexSpaceTable  watIndexSpace = "table"
+	watIndexSpaceMemory watIndexSpace = "memory"
+	watIndexSpaceGlobal watIndexSpace = "global"
+	watIndexSpaceLocal  watIndexSpace = "local"
+	watIndexSpaceLabel  watIndexSpace = "label"
+)
+
+type watParser struct {
+	tokens    []watToken
+	offset    int
+	module    *Module
+	typeIDs   map[string]uint32
+	funcIDs   map[string]uint32
+	memoryIDs map[string]uint32
+	// localIDs are the identifiers of the parameters and locals of the current function
+	localIDs map[string]uint32
+	// labels are the labels of the enclosing blocks of the current function,
+	// the innermost block last. Blocks without a label have an empty label
+	labels []string
+}
+
+// watField is a module field, e.g. a function or an export
+//
+type watField struct {
+	keyword string
+	// offset is the offset of the token after the keyword
+	offset int
+}
+
+func (p *watParser) current() watToken {
+	return p.tokens[p.offset]
+}
+
+func (p *watParser) peek(n int) watToken {
+	offset := p.offset + n
+	if offset >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[offset]
+}
+
+func (p *watParser) next() watToken {
+	token := p.current()
+	if token.kind != watTokenKindEOF {
+		p.offset++
+	}
+	return token
+}
+
+func (p *watParser) syntaxError(format string, args ...any) error {
+	return InvalidWATSyntaxError{
+		Position: p.current().position,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *watParser) unknownInstructionError(name string) error {
+	return UnknownWATInstructionError{
+		Position: p.tokens[p.offset-1].position,
+		Name:     name,
+	}
+}
+
+func (p *watParser) expect(kind watTokenKind, description string) (watToken, error) {
+	token := p.current()
+	if token.kind != kind {
+		return watToken{}, p.syntaxError("expected %s, got %s", description, describeWATToken(token))
+	}
+	p.next()
+	return token, nil
+}
+
+func (p *watParser) expectLeftParen() error {
+	_, err := p.expect(watTokenKindLeftParen, "(")
+	return err
+}
+
+func (p *watParser) expectRightParen() error {
+	_, err := p.expect(watTokenKindRightParen, ")")
+	return err
+}
+
+func (p *watParser) expectKeyword(keyword string) error {
+	token := p.current()
+	if token.kind != watTokenKindKeyword || token.text != keyword {
+		return p.syntaxError("expected %s, got %s", keyword, describeWATToken(token))
+	}
+	p.next()
+	return nil
+}
+
+func (p *watParser) expectString() (string, error) {
+	token, err := p.expect(watTokenKindString, "string")
+	if err != nil {
+		return "", err
+	}
+	return token.text, nil
+}
+
+func describeWATToken(token watToken) string {
+	switch token.kind {
+	case watTokenKindEOF:
+		return "end of text"
+	case watTokenKindString:
+		return strconv.Quote(token.text)
+	default:
+		return token.text
+	}
+}
+
+// isKeyword returns true if the current token is the given keyword
+//
+func (p *watParser) isKeyword(keyword string) bool {
+	token := p.current()
+	return token.kind == watTokenKindKeyword && token.text == keyword
+}
+
+// isListStart returns true if the current token starts a list
+// with the given keyword, e.g. `(param`
+//
+func (p *watParser) isListStart(keyword string) bool {
+	if p.current().kind != watTokenKindLeftParen {
+		return false
+	}
+	token := p.peek(1)
+	return token.kind == watTokenKindKeyword && token.text == keyword
+}
+
+// optionalIdentifier parses an identifier, if any, and returns it without the `$` prefix
+//
+func (p *watParser) optionalIdentifier() string {
+	token := p.current()
+	if token.kind != watTokenKindIdentifier {
+		return ""
+	}
+	p.next()
+	return token.text[1:]
+}
+
+// skipList skips the tokens until the end of the current list,
+// including the closing parenthesis
+//
+func (p *watParser) skipList() error {
+	depth := 1
+	for {
+		switch p.next().kind {
+		case watTokenKindLeftParen:
+			depth++
+		case watTokenKindRightParen:
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		case watTokenKindEOF:
+			return p.syntaxError("expected )")
+		}
+	}
+}
+
+// parseModule parses the module.
+//
+// Functions may be referred to before they are declared,
+// so the fields are first scanned to declare the identifiers of the functions,
+// types, and memories, and are then parsed
+//
+func (p *watParser) parseModule() error {
+	err := p.expectLeftParen()
+	if err != nil {
+		return err
+	}
+
+	err = p.expectKeyword("module")
+	if err != nil {
+		return err
+	}
+
+	p.module.Name = p.optionalIdentifier()
+
+	fields, err := p.scanFields()
+	if err != nil {
+		return err
+	}
+
+	err = p.declareIdentifiers(fields)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		p.offset = field.offset
+
+		switch field.keyword {
+		case "type":
+			// already parsed when declaring identifiers
+			continue
+		case "import":
+			err = p.parseImport()
+		case "func":
+			err = p.parseFunction()
+		case "memory":
+			err = p.parseMemory()
+		case "export":
+			err = p.parseExport()
+		case "start":
+			err = p.parseStart()
+		case "data":
+			err = p.parseData()
+		default:
+			return p.syntaxError("unsupported module field: %s", field.keyword)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scanFields scans the fields of the module, until the end of the module
+//
+func (p *watParser) scanFields() ([]watField, error) {
+	var fields []watField
+
+	for p.current().kind != watTokenKindRightParen {
+		err := p.expectLeftParen()
+		if err != nil {
+			return nil, err
+		}
+
+		keyword, err := p.expect(watTokenKindKeyword, "module field")
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, watField{
+			keyword: keyword.text,
+			offset:  p.offset,
+		})
+
+		err = p.skipList()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.next()
+
+	if p.current().kind != watTokenKindEOF {
+		return nil, p.syntaxError("expected end of text, got %s", describeWATToken(p.current()))
+	}
+
+	return fields, nil
+}
+
+// declareIdentifiers declares the identifiers of the module fields.
+// Types are parsed, as they are needed to parse functions.
+//
+// Imported functions come first in the function index space,
+// followed by the functions defined in the module
+//
+func (p *watParser) declareIdentifiers(fields []watField) error {
+	var functionIDs []string
+	var importedFunctionCount uint32
+	var memoryCount uint32
+
+	for _, field := range fields {
+		p.offset = field.offset
+
+		switch field.keyword {
+		case "type":
+			err := p.parseType()
+			if err != nil {
+				return err
+			}
+
+		case "import":
+			// (import "module" "name" (func $id ...))
+			for i := 0; i < 2; i++ {
+				_, err := p.expectString()
+				if err != nil {
+					return err
+				}
+			}
+			if !p.isListStart("func") {
+				return p.syntaxError("unsupported import: only functions can be imported")
+			}
+			p.offset += 2
+			if id := p.optionalIdentifier(); id != "" {
+				p.funcIDs[id] = importedFunctionCount
+			}
+			importedFunctionCount++
+
+		case "func":
+			id := p.optionalIdentifier()
+			for p.isListStart("export") {
+				p.next()
+				err := p.skipList()
+				if err != nil {
+					return err
+				}
+			}
+			if p.isListStart("import") {
+				if id != "" {
+					p.funcIDs[id] = importedFunctionCount
+				}
+				importedFunctionCount++
+			} else {
+				functionIDs = append(functionIDs, id)
+			}
+
+		case "memory":
+			if id := p.optionalIdentifier(); id != "" {
+				p.memoryIDs[id] = memoryCount
+			}
+			memoryCount++
+		}
+	}
+
+	for i, id := range functionIDs {
+		if id != "" {
+			p.funcIDs[id] = importedFunctionCount + uint32(i)
+		}
+	}
+
+	return nil
+}
+
+// parseType parses a type definition:
+//
+//   (type $id? (func (param ...)* (result ...)*))
+//
+func (p *watParser) parseType() error {
+	id := p.optionalIdentifier()
+
+	err := p.expectLeftParen()
+	if err != nil {
+		return err
+	}
+
+	err = p.expectKeyword("func")
+	if err != nil {
+		return err
+	}
+
+	functionType, _, err := p.parseFunctionSignature()
+	if err != nil {
+		return err
+	}
+
+	err = p.expectRightParen()
+	if err != nil {
+		return err
+	}
+
+	err = p.expectRightParen()
+	if err != nil {
+		return err
+	}
+
+	if id != "" {
+		p.typeIDs[id] = uint32(len(p.module.Types))
+	}
+
+	p.module.Types = append(p.module.Types, functionType)
+
+	return nil
+}
+
+// parseFunctionSignature parses parameters and results:
+//
+//   (param $id? valtype) | (param valtype*)
+//   (result valtype*)
+//
+// It returns the function type and the identifiers of the parameters
+//
+func (p *watParser) parseFunctionSignature() (*FunctionType, []string, error) {
+	functionType := &FunctionType{}
+	var parameterIDs []string
+
+	for p.isListStart("param") {
+		p.offset += 2
+
+		if id := p.optionalIdentifier(); id != "" {
+			valueType, err := p.parseValueType()
+			if err != nil {
+				return nil, nil, err
+			}
+			functionType.Params = append(functionType.Params, valueType)
+			parameterIDs = append(parameterIDs, id)
+		} else {
+			valueTypes, err := p.parseValueTypes()
+			if err != nil {
+				return nil, nil, err
+			}
+			functionType.Params = append(functionType.Params, valueTypes...)
+			parameterIDs = append(parameterIDs, make([]string, len(valueTypes))...)
+		}
+
+		err := p.expectRightParen()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for p.isListStart("result") {
+		p.offset += 2
+
+		valueTypes, err := p.parseValueTypes()
+		if err != nil {
+			return nil, nil, err
+		}
+		functionType.Results = append(functionType.Results, valueTypes...)
+
+		err = p.expectRightParen()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return functionType, parameterIDs, nil
+}
+
+func (p *watParser) parseValueTypes() ([]ValueType, error) {
+	var valueTypes []ValueType
+	for p.current().kind == watTokenKindKeyword {
+		valueType, err := p.parseValueType()
+		if err != nil {
+			return nil, err
+		}
+		valueTypes = append(valueTypes, valueType)
+	}
+	return valueTypes, nil
+}
+
+func (p *watParser) parseValueType() (ValueType, error) {
+	token := p.current()
+	if token.kind == watTokenKindKeyword {
+		switch token.text {
+		case "i32":
+			p.next()
+			return ValueTypeI32, nil
+		case "i64":
+			p.next()
+			return ValueTypeI64, nil
+		case "funcref":
+			p.next()
+			return ValueTypeFuncRef, nil
+		case "externref":
+			p.next()
+			return ValueTypeExternRef, nil
+		}
+	}
+
+	return 0, p.syntaxError("expected value type, got %s", describeWATToken(token))
+}
+
+// parseTypeUse parses a type use:
+//
+//   (type x)? (param ...)* (result ...)*
+//
+// If no type index is given, the type with the given parameters and results is used,
+// and is added to the types of the module, if there is no such type yet.
+//
+// It returns the type index and the identifiers of the parameters
+//
+func (p *watParser) parseTypeUse() (uint32, []string, error) {
+	var typeIndex uint32
+	hasTypeIndex := false
+
+	if p.isListStart("type") {
+		p.offset += 2
+
+		var err error
+		typeIndex, err = p.parseIndex(watIndexSpaceType)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		err = p.expectRightParen()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		hasTypeIndex = true
+	}
+
+	functionType, parameterIDs, err := p.parseFunctionSignature()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if hasTypeIndex {
+		if int(typeIndex) >= len(p.module.Types) {
+			return 0, nil, p.syntaxError("unknown type %d", typeIndex)
+		}
+		// The parameters may be omitted
+		parameterCount := len(p.module.Types[typeIndex].Params)
+		if len(parameterIDs) < parameterCount {
+			parameterIDs = append(parameterIDs, make([]string, parameterCount-len(parameterIDs))...)
+		}
+		return typeIndex, parameterIDs, nil
+	}
+
+	return p.typeIndex(functionType), parameterIDs, nil
+}
+
+// typeIndex returns the index of the given function type,
+// adding the type to the module, if it does not exist yet
+//
+func (p *watParser) typeIndex(functionType *FunctionType) uint32 {
+	for i, existingType := range p.module.Types {
+		if equalValueTypes(existingType.Params, functionType.Params) &&
+			equalValueTypes(existingType.Results, functionType.Results) {
+
+			return uint32(i)
+		}
+	}
+
+	p.module.Types = append(p.module.Types, functionType)
+	return uint32(len(p.module.Types) - 1)
+}
+
+func equalValueTypes(a, b []ValueType) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, valueType := range a {
+		if valueType != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// parseImport parses a function import:
+//
+//   (import "module" "name" (func $id? typeuse))
+//
+func (p *watParser) parseImport() error {
+	moduleName, err := p.expectString()
+	if err != nil {
+		return err
+	}
+
+	name, err := p.expectString()
+	if err != nil {
+		return err
+	}
+
+	// the import kind was already checked when declaring identifiers
+	p.offset += 2
+	p.optionalIdentifier()
+
+	typeIndex, _, err := p.parseTypeUse()
+	if err != nil {
+		return err
+	}
+
+	err = p.expectRightParen()
+	if err != nil {
+		return err
+	}
+
+	err = p.expectRightParen()
+	if err != nil {
+		return err
+	}
+
+	p.module.Imports = append(p.module.Imports, &Import{
+		Module:    moduleName,
+		Name:      name,
+		TypeIndex: typeIndex,
+	})
+
+	return nil
+}
+
+// parseInlineExports parses inline exports of a function or memory:
+//
+//   (export "name")*
+//
+func (p *watParser) parseInlineExports(descriptor ExportDescriptor) error {
+	for p.isListStart("export") {
+		p.offset += 2
+
+		name, err := p.expectString()
+		if err != nil {
+			return err
+		}
+
+		err = p.expectRightParen()
+		if err != nil {
+			return err
+		}
+
+		p.module.Exports = append(p.module.Exports, &Export{
+			Name:       name,
+			Descriptor: descriptor,
+		})
+	}
+
+	return nil
+}
+
+// parseFunction parses a function:
+//
+//   (func $id? (export "name")* typeuse (local $id? valtype | local valtype*)* instr*)
+//
+// or an inline function import:
+//
+//   (func $id? (export "name")* (import "module" "name") typeuse)
+//
+func (p *watParser) parseFunction() error {
+	id := p.optionalIdentifier()
+
+	var functionIndex uint32
+	if p.isInlineImport() {
+		functionIndex = uint32(len(p.module.Imports))
+	} else {
+		functionIndex = uint32(len(p.module.Imports) + len(p.module.Functions))
+	}
+
+	err := p.parseInlineExports(FunctionExport{
+		FunctionIndex: functionIndex,
+	})
+	if err != nil {
+		return err
+	}
+
+	if p.isListStart("import") {
+		return p.parseInlineImport()
+	}
+
+	typeIndex, parameterIDs, err := p.parseTypeUse()
+	if err != nil {
+		return err
+	}
+
+	p.localIDs = map[string]uint32{}
+	p.labels = nil
+
+	for i, parameterID := range parameterIDs {
+		if parameterID != "" {
+			p.localIDs[parameterID] = uint32(i)
+		}
+	}
+
+	var locals []ValueType
+
+	for p.isListStart("local") {
+		p.offset += 2
+
+		if localID := p.optionalIdentifier(); localID != "" {
+			valueType, err := p.parseValueType()
+			if err != nil {
+				return err
+			}
+			p.localIDs[localID] = uint32(len(parameterIDs) + len(locals))
+			locals = append(locals, valueType)
+		} else {
+			valueTypes, err := p.parseValueTypes()
+			if err != nil {
+				return err
+			}
+			locals = append(locals, valueTypes...)
+		}
+
+		err = p.expectRightParen()
+		if err != nil {
+			return err
+		}
+	}
+
+	instructions, err := p.parseInstructions()
+	if err != nil {
+		return err
+	}
+
+	err = p.expectRightParen()
+	if err != nil {
+		return err
+	}
+
+	p.module.Functions = append(p.module.Functions, &Function{
+		Name:      id,
+		TypeIndex: typeIndex,
+		Code: &Code{
+			Locals:       locals,
+			Instructions: instructions,
+		},
+	})
+
+	return nil
+}
+
+// isInlineImport returns true if the current function is an inline import,
+// i.e. an import follows the inline exports
+//
+func (p *watParser) isInlineImport() bool {
+	offset := p.offset
+	defer func() {
+		p.offset = offset
+	}()
+
+	for p.isListStart("export") {
+		p.next()
+		if p.skipList() != nil {
+			return false
+		}
+	}
+
+	return p.isListStart("import")
+}
+
+// parseInlineImport parses the import of a function:
+//
+//   (import "module" "name") typeuse
+//
+func (p *watParser) parseInlineImport() error {
+	p.offset += 2
+
+	moduleName, err := p.expectString()
+	if err != nil {
+		return err
+	}
+
+	name, err := p.expectString()
+	if err != nil {
+		return err
+	}
+
+	err = p.expectRightParen()
+	if err != nil {
+		return err
+	}
+
+	typeIndex, _, err := p.parseTypeUse()
+	if err != nil {
+		return err
+	}
+
+	err = p.expectRightParen()
+	if err != nil {
+		return err
+	}
+
+	p.module.Imports = append(p.module.Imports, &Import{
+		Module:    moduleName,
+		Name:      name,
+		TypeIndex: typeIndex,
+	})
+
+	return nil
+}
+
+// parseMemory parses a memory:
+//
+//   (memory $id? (export "name")* min max?)
+//
+func (p *watParser) parseMemory() error {
+	p.optionalIdentifier()
+
+	err := p.parseInlineExports(MemoryExport{
+		MemoryIndex: uint32(len(p.module.Memories)),
+	})
+	if err != nil {
+		return err
+	}
+
+	min, err := p.parseUint32()
+	if err != nil {
+		return err
+	}
+
+	memory := &Memory{
+		Min: min,
+	}
+
+	if p.current().kind == watTokenKindKeyword {
+		max, err := p.parseUint32()
+		if err != nil {
+			return err
+		}
+		memory.Max = &max
+	}
+
+	err = p.expectRightParen()
+	if err != nil {
+		return err
+	}
+
+	p.module.Memories = append(p.module.Memories, memory)
+
+	return nil
+}
+
+// parseExport parses an export:
+//
+//   (export "name" (func x))
+//   (export "name" (memory x))
+//
+func (p *watParser) parseExport() error {
+	name, err := p.expectString()
+	if err != nil {
+		return err
+	}
+
+	err = p.expectLeftParen()
+	if err != nil {
+		return err
+	}
+
+	var descriptor ExportDescriptor
+
+	switch {
+	case p.isKeyword("func"):
+		p.next()
+		functionIndex, err := p.parseIndex(watIndexSpaceFunc)
+		if err != nil {
+			return err
+		}
+		descriptor = FunctionExport{
+			FunctionIndex: functionIndex,
+		}
+
+	case p.isKeyword("memory"):
+		p.next()
+		memoryIndex, err := p.parseIndex(watIndexSpaceMemory)
+		if err != nil {
+			return err
+		}
+		descriptor = MemoryExport{
+			MemoryIndex: memoryIndex,
+		}
+
+	default:
+		return p.syntaxError("unsupported export: %s", describeWATToken(p.current()))
+	}
+
+	err = p.expectRightParen()
+	if err != nil {
+		return err
+	}
+
+	err = p.expectRightParen()
+	if err != nil {
+		return err
+	}
+
+	p.module.Exports = append(p.module.Exports, &Export{
+		Name:       name,
+		Descriptor: descriptor,
+	})
+
+	return nil
+}
+
+// parseStart parses the start function:
+//
+//   (start x)
+//
+func (p *watParser) parseStart() error {
+	functionIndex, err := p.parseIndex(watIndexSpaceFunc)
+	if err != nil {
+		return err
+	}
+
+	err = p.expectRightParen()
+	if err != nil {
+		return err
+	}
+
+	p.module.StartFunctionIndex = &functionIndex
+
+	return nil
+}
+
+// parseData parses a data segment:
+//
+//   (data $id? (memory x)? (offset instr*) "string"*)
+//   (data $id? (memory x)? (instr) "string"*)
+//
+func (p *watParser) parseData() error {
+	p.optionalIdentifier()
+
+	data := &Data{}
+
+	if p.isListStart("memory") {
+		p.offset += 2
+
+		memoryIndex, err := p.parseIndex(watIndexSpaceMemory)
+		if err != nil {
+			return err
+		}
+		data.MemoryIndex = memoryIndex
+
+		err = p.expectRightParen()
+		if err != nil {
+			return err
+		}
+	}
+
+	p.localIDs = nil
+	p.labels = nil
+
+	if p.isListStart("offset") {
+		p.offset += 2
+
+		offset, err := p.parseInstructions()
+		if err != nil {
+			return err
+		}
+		data.Offset = offset
+
+		err = p.expectRightParen()
+		if err != nil {
+			return err
+		}
+	} else {
+		offset, err := p.parseFoldedInstruction(nil)
+		if err != nil {
+			return err
+		}
+		data.Offset = offset
+	}
+
+	var init []byte
+	for p.current().kind == watTokenKindString {
+		init = append(init, p.next().text...)
+	}
+	data.Init = init
+
+	err := p.expectRightParen()
+	if err != nil {
+		return err
+	}
+
+	p.module.Data = append(p.module.Data, data)
+
+	return nil
+}
+
+// Instructions
+
+// parseInstructions parses a sequence of instructions, in plain or folded form,
+// until the end of the enclosing list or block
+//
+func (p *watParser) parseInstructions() ([]Instruction, error) {
+	var instructions []Instruction
+
+	for {
+		token := p.current()
+
+		switch token.kind {
+		case watTokenKindRightParen, watTokenKindEOF:
+			return instructions, nil
+
+		case watTokenKindLeftParen:
+			var err error
+			instructions, err = p.parseFoldedInstruction(instructions)
+			if err != nil {
+				return nil, err
+			}
+
+		case watTokenKindKeyword:
+			if token.text == "end" || token.text == "else" {
+				return instructions, nil
+			}
+
+			p.next()
+
+			instruction, err := p.parsePlainInstruction(token.text)
+			if err != nil {
+				return nil, err
+			}
+
+			instructions = append(instructions, instruction)
+
+		default:
+			return nil, p.syntaxError("expected instruction, got %s", describeWATToken(token))
+		}
+	}
+}
+
+// parseFoldedInstruction parses an instruction in folded form,
+// and appends the resulting instructions to the given instructions.
+//
+// The operands of a folded instruction are appended before the instruction:
+// `(i32.add (local.get 0) (i32.const 1))` is `local.get 0`, `i32.const 1`, `i32.add`.
+//
+// Blocks have the form `(block label? blocktype instr*)`,
+// and conditionals have the form `(if label? blocktype (condition)* (then instr*) (else instr*)?)`
+//
+func (p *watParser) parseFoldedInstruction(instructions []Instruction) ([]Instruction, error) {
+	err := p.expectLeftParen()
+	if err != nil {
+		return nil, err
+	}
+
+	nameToken, err := p.expect(watTokenKindKeyword, "instruction")
+	if err != nil {
+		return nil, err
+	}
+
+	name := nameToken.text
+
+	switch name {
+	case "block", "loop":
+		block, err := p.parseFoldedBlock()
+		if err != nil {
+			return nil, err
+		}
+
+		if name == "block" {
+			instructions = append(instructions, InstructionBlock{Block: block})
+		} else {
+			instructions = append(instructions, InstructionLoop{Block: block})
+		}
+
+	case "if":
+		instructions, err = p.parseFoldedIf(instructions)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		instruction, err := p.parsePlainInstruction(name)
+		if err != nil {
+			return nil, err
+		}
+
+		for p.current().kind == watTokenKindLeftParen {
+			instructions, err = p.parseFoldedInstruction(instructions)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		instructions = append(instructions, instruction)
+	}
+
+	err = p.expectRightParen()
+	if err != nil {
+		return nil, err
+	}
+
+	return instructions, nil
+}
+
+// parseFoldedBlock parses the label, block type, and instructions of a folded block
+//
+func (p *watParser) parseFoldedBlock() (Block, error) {
+	label := p.optionalIdentifier()
+
+	blockType, err := p.parseBlockType()
+	if err != nil {
+		return Block{}, err
+	}
+
+	p.labels = append(p.labels, label)
+	defer func() {
+		p.labels = p.labels[:len(p.labels)-1]
+	}()
+
+	instructions, err := p.parseInstructions()
+	if err != nil {
+		return Block{}, err
+	}
+
+	return Block{
+		BlockType:     blockType,
+		Instructions1: instructions,
+	}, nil
+}
+
+// parseFoldedIf parses a folded conditional,
+// and appends the condition and the conditional to the given instructions
+//
+func (p *watParser) parseFoldedIf(instructions []Instruction) ([]Instruction, error) {
+	label := p.optionalIdentifier()
+
+	blockType, err := p.parseBlockType()
+	if err != nil {
+		return nil, err
+	}
+
+	// The condition is evaluated outside of the block
+
+	for p.current().kind == watTokenKindLeftParen && !p.isListStart("then") {
+		instructions, err = p.parseFoldedInstruction(instructions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	p.labels = append(p.labels, label)
+	defer func() {
+		p.labels = p.labels[:len(p.labels)-1]
+	}()
+
+	if !p.isListStart("then") {
+		return nil, p.syntaxError("expected (then, got %s", describeWATToken(p.current()))
+	}
+	p.offset += 2
+
+	instructions1, err := p.parseInstructions()
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.expectRightParen()
+	if err != nil {
+		return nil, err
+	}
+
+	var instructions2 []Instruction
+
+	if p.isListStart("else") {
+		p.offset += 2
+
+		instructions2, err = p.parseInstructions()
+		if err != nil {
+			return nil, err
+		}
+
+		err = p.expectRightParen()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return append(instructions, InstructionIf{
+		Block: Block{
+			BlockType:     blockType,
+			Instructions1: instructions1,
+			Instructions2: instructions2,
+		},
+	}), nil
+}
+
+// parseBlockType parses the type of a block:
+// either no type, a single result `(result valtype)`, or a type use
+//
+func (p *watParser) parseBlockType() (BlockType, error) {
+	if !p.isListStart("type") && !p.isListStart("param") && !p.isListStart("result") {
+		return nil, nil
+	}
+
+	if !p.isListStart("type") {
+		functionType, parameterIDs, err := p.parseFunctionSignature()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, parameterID := range parameterIDs {
+			if parameterID != "" {
+				return nil, p.syntaxError("block parameters cannot have identifiers")
+			}
+		}
+
+		if len(functionType.Params) == 0 {
+			switch len(functionType.Results) {
+			case 0:
+				return nil, nil
+			case 1:
+				return functionType.Results[0], nil
+			}
+		}
+
+		return TypeIndexBlockType{
+			TypeIndex: p.typeIndex(functionType),
+		}, nil
+	}
+
+	typeIndex, _, err := p.parseTypeUse()
+	if err != nil {
+		return nil, err
+	}
+
+	return TypeIndexBlockType{
+		TypeIndex: typeIndex,
+	}, nil
+}
+
+// parseBlockInstructionArgument parses the block of a plain block instruction:
+//
+//   label? blocktype instr* (else label? instr*)? end label?
+//
+func (p *watParser) parseBlockInstructionArgument(allowElse bool) (Block, error) {
+	label := p.optionalIdentifier()
+
+	blockType, err := p.parseBlockType()
+	if err != nil {
+		return Block{}, err
+	}
+
+	p.labels = append(p.labels, label)
+	defer func() {
+		p.labels = p.labels[:len(p.labels)-1]
+	}()
+
+	instructions1, err := p.parseInstructions()
+	if err != nil {
+		return Block{}, err
+	}
+
+	var instructions2 []Instruction
+
+	if allowElse && p.isKeyword("else") {
+		p.next()
+		p.optionalIdentifier()
+
+		instructions2, err = p.parseInstructions()
+		if err != nil {
+			return Block{}, err
+		}
+	}
+
+	err = p.expectKeyword("end")
+	if err != nil {
+		return Block{}, err
+	}
+	p.optionalIdentifier()
+
+	return Block{
+		BlockType:     blockType,
+		Instructions1: instructions1,
+		Instructions2: instructions2,
+	}, nil
+}
+
+// parseIndexInstructionArgument parses an index argument of an instruction
+// in the given index space.
+//
+// For types, the index is given as a type use, e.g. `(type 0)`,
+// optionally preceded by a table index, e.g. `call_indirect 0 (type 0)`.
+// Table indices are optional and default to 0
+//
+func (p *watParser) parseIndexInstructionArgument(indexSpace watIndexSpace) (uint32, error) {
+	switch indexSpace {
+	case watIndexSpaceType:
+		if p.isIndex() {
+			tableIndex, err := p.parseIndex(watIndexSpaceTable)
+			if err != nil {
+				return 0, err
+			}
+			if tableIndex != 0 {
+				return 0, p.syntaxError("unsupported table index: %d", tableIndex)
+			}
+		}
+
+		typeIndex, _, err := p.parseTypeUse()
+		return typeIndex, err
+
+	case watIndexSpaceTable:
+		if !p.isIndex() {
+			return 0, nil
+		}
+	}
+
+	return p.parseIndex(indexSpace)
+}
+
+// parseIndexVectorInstructionArgument parses the vector of indices of a `br_table` instruction.
+// All labels but the last one are parsed, as the last label is the default label
+//
+func (p *watParser) parseIndexVectorInstructionArgument(indexSpace watIndexSpace) ([]uint32, error) {
+	var indices []uint32
+
+	for p.isIndex() && p.isIndexAt(1) {
+		index, err := p.parseIndex(indexSpace)
+		if err != nil {
+			return nil, err
+		}
+		indices = append(indices, index)
+	}
+
+	return indices, nil
+}
+
+// parseReferenceTypeInstructionArgument parses a reference type, i.e. `func` or `extern`,
+// and returns the value type encoding
+//
+func (p *watParser) parseReferenceTypeInstructionArgument() (uint32, error) {
+	switch {
+	case p.isKeyword("func"):
+		p.next()
+		return uint32(ValueTypeFuncRef), nil
+
+	case p.isKeyword("extern"):
+		p.next()
+		return uint32(ValueTypeExternRef), nil
+
+	default:
+		return 0, p.syntaxError("expected reference type, got %s", describeWATToken(p.current()))
+	}
+}
+
+func (p *watParser) parseInt32InstructionArgument() (int32, error) {
+	token := p.current()
+	value, err := p.parseInteger(32)
+	if err != nil {
+		return 0, err
+	}
+	if value.negative {
+		if value.magnitude > 1<<31 {
+			return 0, invalidWATIntegerError(token, nil)
+		}
+		return int32(-int64(value.magnitude)), nil
+	}
+	if value.magnitude > 1<<32-1 {
+		return 0, invalidWATIntegerError(token, nil)
+	}
+	return int32(uint32(value.magnitude)), nil
+}
+
+func (p *watParser) parseInt64InstructionArgument() (int64, error) {
+	token := p.current()
+	value, err := p.parseInteger(64)
+	if err != nil {
+		return 0, err
+	}
+	if value.negative {
+		if value.magnitude > 1<<63 {
+			return 0, invalidWATIntegerError(token, nil)
+		}
+		return -int64(value.magnitude-1) - 1, nil
+	}
+	return int64(value.magnitude), nil
+}
+
+// Indices and integers
+
+// isIndex returns true if the current token is an index,
+// i.e. a number or an identifier
+//
+func (p *watParser) isIndex() bool {
+	return p.isIndexAt(0)
+}
+
+func (p *watParser) isIndexAt(n int) bool {
+	token := p.peek(n)
+	switch token.kind {
+	case watTokenKindIdentifier:
+		return true
+	case watTokenKindKeyword:
+		c := token.text[0]
+		return c >= '0' && c <= '9'
+	}
+	return false
+}
+
+// parseIndex parses an index in the given index space,
+// which is either a number or an identifier
+//
+func (p *watParser) parseIndex(indexSpace watIndexSpace) (uint32, error) {
+	token := p.current()
+
+	if token.kind != watTokenKindIdentifier {
+		if !p.isIndex() {
+			return 0, p.syntaxError("expected %s index, got %s", indexSpace, describeWATToken(token))
+		}
+		return p.parseUint32()
+	}
+
+	p.next()
+
+	id := token.text[1:]
+
+	var index uint32
+	var ok bool
+
+	switch indexSpace {
+	case watIndexSpaceType:
+		index, ok = p.typeIDs[id]
+	case watIndexSpaceFunc:
+		index, ok = p.funcIDs[id]
+	case watIndexSpaceMemory:
+		index, ok = p.memoryIDs[id]
+	case watIndexSpaceLocal:
+		index, ok = p.localIDs[id]
+	case watIndexSpaceLabel:
+		// Labels are resolved to the relative depth of the block
+		for i := len(p.labels) - 1; i >= 0; i-- {
+			if p.labels[i] == id {
+				index = uint32(len(p.labels) - 1 - i)
+				ok = true
+				break
+			}
+		}
+	}
+
+	if !ok {
+		return 0, UnknownWATIdentifierError{
+			Position:   token.position,
+			Identifier: token.text,
+			IndexSpace: string(indexSpace),
+		}
+	}
+
+	return index, nil
+}
+
+func (p *watParser) parseUint32() (uint32, error) {
+	token := p.current()
+	value, err := p.parseInteger(32)
+	if err != nil {
+		return 0, err
+	}
+	if value.negative || value.magnitude > 1<<32-1 {
+		return 0, invalidWATIntegerError(token, nil)
+	}
+	return uint32(value.magnitude), nil
+}
+
+type watInteger struct {
+	negative  bool
+	magnitude uint64
+}
+
+// parseInteger parses an integer literal, which may have a sign,
+// may be hexadecimal, and may contain underscores as separators
+//
+func (p *watParser) parseInteger(bitSize int) (watInteger, error) {
+	token := p.current()
+	if token.kind != watTokenKindKeyword {
+		return watInteger{}, p.syntaxError("expected integer, got %s", describeWATToken(token))
+	}
+
+	literal := token.text
+
+	var result watInteger
+
+	switch literal[0] {
+	case '-':
+		result.negative = true
+		literal = literal[1:]
+	case '+':
+		literal = literal[1:]
+	}
+
+	base := 10
+	if strings.HasPrefix(literal, "0x") {
+		base = 16
+		literal = literal[2:]
+	}
+
+	if strings.HasPrefix(literal, "_") ||
+		strings.HasSuffix(literal, "_") ||
+		strings.Contains(literal, "__") {
+
+		return watInteger{}, invalidWATIntegerError(token, nil)
+	}
+
+	magnitude, err := strconv.ParseUint(strings.ReplaceAll(literal, "_", ""), base, bitSize)
+	if err != nil {
+		return watInteger{}, invalidWATIntegerError(token, err)
+	}
+
+	p.next()
+
+	result.magnitude = magnitude
+	return result, nil
+}
+
+func invalidWATIntegerError(token watToken, err error) error {
+	return InvalidWATIntegerError{
+		Position:   token.position,
+		Literal:    token.text,
+		ParseError: err,
+	}
+}
@@ -0,0 +1,413 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package wasm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWAT(t *testing.T) {
+
+	t.Parallel()
+
+	module, err := ParseWAT(`
+      (module $test
+        (type (;0;) (func))
+        (type (;1;) (func (param i32 i32) (result i32)))
+        (import "env" "add" (func $env.add (type 1)))
+        (func $start (type 0)
+          return)
+        (func $add (type 1) (param i32 i32) (result i32)
+          (local i32)
+          local.get 0
+          local.get 1
+          i32.add)
+        (memory (;0;) 1024 2048)
+        (export "add" (func $env.add))
+        (export "mem" (memory 0))
+        (start $start)
+        (data (;0;) (i32.const 0) "\00\01\02\03"))
+    `)
+	require.NoError(t, err)
+
+	max := uint32(2048)
+	startFunctionIndex := uint32(1)
+
+	require.Equal(t,
+		&Module{
+			Name: "test",
+			Types: []*FunctionType{
+				{},
+				{
+					Params:  []ValueType{ValueTypeI32, ValueTypeI32},
+					Results: []ValueType{ValueTypeI32},
+				},
+			},
+			Imports: []*Import{
+				{
+					Module:    "env",
+					Name:      "add",
+					TypeIndex: 1,
+				},
+			},
+			Functions: []*Function{
+				{
+					Name:      "start",
+					TypeIndex: 0,
+					Code: &Code{
+						Instructions: []Instruction{
+							InstructionReturn{},
+						},
+					},
+				},
+				{
+					Name:      "add",
+					TypeIndex: 1,
+					Code: &Code{
+						Locals: []ValueType{
+							ValueTypeI32,
+						},
+						Instructions: []Instruction{
+							InstructionLocalGet{LocalIndex: 0},
+							InstructionLocalGet{LocalIndex: 1},
+							InstructionI32Add{},
+						},
+					},
+				},
+			},
+			Memories: []*Memory{
+				{
+					Min: 1024,
+					Max: &max,
+				},
+			},
+			Exports: []*Export{
+				{
+					Name: "add",
+					Descriptor: FunctionExport{
+						FunctionIndex: 0,
+					},
+				},
+				{
+					Name: "mem",
+					Descriptor: MemoryExport{
+						MemoryIndex: 0,
+					},
+				},
+			},
+			StartFunctionIndex: &startFunctionIndex,
+			Data: []*Data{
+				{
+					MemoryIndex: 0,
+					Offset: []Instruction{
+						InstructionI32Const{Value: 0},
+					},
+					Init: []byte{0x0, 0x1, 0x2, 0x3},
+				},
+			},
+		},
+		module,
+	)
+}
+
+func TestParseWAT_folded(t *testing.T) {
+
+	t.Parallel()
+
+	module, err := ParseWAT(`
+      (module
+        (func $f (export "f") (param $x i32) (result i32)
+          (if (result i32) (i32.eqz (local.get $x))
+            (then (i32.const 1))
+            (else (i32.add (local.get $x) (call $f (i32.const 0)))))))
+    `)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		[]*FunctionType{
+			{
+				Params:  []ValueType{ValueTypeI32},
+				Results: []ValueType{ValueTypeI32},
+			},
+		},
+		module.Types,
+	)
+
+	require.Equal(t,
+		[]*Export{
+			{
+				Name: "f",
+				Descriptor: FunctionExport{
+					FunctionIndex: 0,
+				},
+			},
+		},
+		module.Exports,
+	)
+
+	require.Equal(t,
+		[]Instruction{
+			InstructionLocalGet{LocalIndex: 0},
+			InstructionI32Eqz{},
+			InstructionIf{
+				Block: Block{
+					BlockType: ValueTypeI32,
+					Instructions1: []Instruction{
+						InstructionI32Const{Value: 1},
+					},
+					Instructions2: []Instruction{
+						InstructionLocalGet{LocalIndex: 0},
+						InstructionI32Const{Value: 0},
+						InstructionCall{FuncIndex: 0},
+						InstructionI32Add{},
+					},
+				},
+			},
+		},
+		module.Functions[0].Code.Instructions,
+	)
+}
+
+func TestParseWAT_labels(t *testing.T) {
+
+	t.Parallel()
+
+	module, err := ParseWAT(`
+      (module
+        (func (param i32)
+          block $outer
+            loop $inner
+              local.get 0
+              br_if $outer
+              local.get 0
+              br_table $inner $outer 1
+              br $inner
+            end
+          end))
+    `)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		[]Instruction{
+			InstructionBlock{
+				Block: Block{
+					Instructions1: []Instruction{
+						InstructionLoop{
+							Block: Block{
+								Instructions1: []Instruction{
+									InstructionLocalGet{LocalIndex: 0},
+									InstructionBrIf{LabelIndex: 1},
+									InstructionLocalGet{LocalIndex: 0},
+									InstructionBrTable{
+										LabelIndices:      []uint32{0, 1},
+										DefaultLabelIndex: 1,
+									},
+									InstructionBr{LabelIndex: 0},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		module.Functions[0].Code.Instructions,
+	)
+}
+
+func TestParseWAT_functionIndices(t *testing.T) {
+
+	t.Parallel()
+
+	// Imported functions come first in the function index space,
+	// even if they are declared after functions defined in the module
+
+	module, err := ParseWAT(`
+      (module
+        (func $a (call $b) (call $c))
+        (import "env" "b" (func $b))
+        (func $c (import "env" "c")))
+    `)
+	require.NoError(t, err)
+
+	require.Len(t, module.Imports, 2)
+	require.Len(t, module.Functions, 1)
+
+	require.Equal(t,
+		[]Instruction{
+			InstructionCall{FuncIndex: 0},
+			InstructionCall{FuncIndex: 1},
+		},
+		module.Functions[0].Code.Instructions,
+	)
+}
+
+func TestParseWAT_integers(t *testing.T) {
+
+	t.Parallel()
+
+	module, err := ParseWAT(`
+      (module
+        (func
+          i32.const -2147483648
+          i32.const 4294967295
+          i32.const 0xff
+          i32.const 1_000
+          i64.const -9223372036854775808
+          i64.const 18446744073709551615
+          i64.const +0x1_0000))
+    `)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		[]Instruction{
+			InstructionI32Const{Value: -2147483648},
+			InstructionI32Const{Value: -1},
+			InstructionI32Const{Value: 255},
+			InstructionI32Const{Value: 1000},
+			InstructionI64Const{Value: -9223372036854775808},
+			InstructionI64Const{Value: -1},
+			InstructionI64Const{Value: 0x10000},
+		},
+		module.Functions[0].Code.Instructions,
+	)
+}
+
+func TestParseWAT_errors(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("unknown instruction", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseWAT("(module (func\n  i32.foo))")
+		require.Error(t, err)
+
+		var instructionErr UnknownWATInstructionError
+		require.ErrorAs(t, err, &instructionErr)
+		assert.Equal(t, "i32.foo", instructionErr.Name)
+		assert.Equal(t, WATPosition{Offset: 16, Line: 2, Column: 2}, instructionErr.Position)
+	})
+
+	t.Run("unknown identifier", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseWAT("(module (func (call $missing)))")
+		require.Error(t, err)
+
+		var identifierErr UnknownWATIdentifierError
+		require.ErrorAs(t, err, &identifierErr)
+		assert.Equal(t, "$missing", identifierErr.Identifier)
+	})
+
+	t.Run("invalid integer", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseWAT("(module (func i32.const 4294967296))")
+		require.Error(t, err)
+
+		var integerErr InvalidWATIntegerError
+		require.ErrorAs(t, err, &integerErr)
+		assert.Equal(t, "4294967296", integerErr.Literal)
+	})
+
+	t.Run("unterminated comment", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseWAT("(module (; (; ;) )")
+		require.Error(t, err)
+
+		var syntaxErr InvalidWATSyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+	})
+
+	t.Run("unsupported field", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseWAT("(module (table 1 funcref))")
+		require.Error(t, err)
+
+		var syntaxErr InvalidWATSyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+	})
+
+	t.Run("trailing tokens", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseWAT("(module) (module)")
+		require.Error(t, err)
+	})
+}
+
+// TestParseWAT_testdata parses the WAT files in testdata,
+// and ensures that the modules can be written and read back
+//
+func TestParseWAT_testdata(t *testing.T) {
+
+	t.Parallel()
+
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.wat"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+
+		path := path
+
+		t.Run(filepath.Base(path), func(t *testing.T) {
+
+			t.Parallel()
+
+			text, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			module, err := ParseWAT(string(text))
+			require.NoError(t, err)
+
+			var b Buffer
+			err = NewWASMWriter(&b).WriteModule(module)
+			require.NoError(t, err)
+
+			b.offset = 0
+
+			r := NewWASMReader(&b)
+			err = r.ReadModule()
+			require.NoError(t, err)
+
+			// the name section is not read
+
+			module.Name = ""
+			for _, function := range module.Functions {
+				function.Name = ""
+			}
+
+			require.Equal(t, module, &r.Module)
+		})
+	}
+}
@@ -220,3 +220,290 @@ func TestContractUpdateWithDependencies(t *testing.T) {
 	)
 	require.NoError(t, err)
 }
+
+func TestContractUpdateFieldRemoval(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+	runtime.SetContractUpdateValidationEnabled(true)
+
+	accountCodes := map[common.LocationID][]byte{}
+	signerAccount := common.MustBytesToAddress([]byte{0x1})
+
+	runtimeInterface := &testRuntimeInterface{
+		getCode: func(location Location) (bytes []byte, err error) {
+			return accountCodes[location.ID()], nil
+		},
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{signerAccount}, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		getAccountContractCode: func(address Address, name string) (code []byte, err error) {
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			return accountCodes[location.ID()], nil
+		},
+		updateAccountContractCode: func(address Address, name string, code []byte) error {
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			accountCodes[location.ID()] = code
+			return nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			return nil
+		},
+		getProgram: func(location Location) (*interpreter.Program, error) {
+			// Always load the programs from the code, which is updated
+			return nil, nil
+		},
+		setProgram: func(location Location, program *interpreter.Program) error {
+			return nil
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	executeTransaction := func(code string) error {
+		return runtime.ExecuteTransaction(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  nextTransactionLocation(),
+			},
+		)
+	}
+
+	executeScript := func(code string) cadence.Value {
+		value, err := runtime.ExecuteScript(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  common.ScriptLocation{},
+			},
+		)
+		require.NoError(t, err)
+		return value
+	}
+
+	// storedValueHasField returns true if the stored composite value
+	// still contains data for the given field
+
+	storedValueHasField := func(path string, name string) bool {
+		storage, inter, err := runtime.Storage(Context{
+			Interface: runtimeInterface,
+		})
+		require.NoError(t, err)
+
+		storageMap := storage.GetStorageMap(signerAccount, common.PathDomainStorage.Identifier(), false)
+		require.NotNil(t, storageMap)
+
+		value := storageMap.ReadValue(inter, path).(*interpreter.CompositeValue)
+		return value.GetField(inter, interpreter.ReturnEmptyLocationRange, name) != nil
+	}
+
+	const contractV1 = `
+      pub contract Test {
+
+          pub resource NFT {
+              pub let id: UInt64
+              pub let name: String
+              pub var level: UInt64
+
+              init(id: UInt64, name: String) {
+                  self.id = id
+                  self.name = name
+                  self.level = 0
+              }
+          }
+
+          pub struct Info {
+              pub let id: UInt64
+              pub let name: String
+
+              init(id: UInt64, name: String) {
+                  self.id = id
+                  self.name = name
+              }
+          }
+
+          pub fun mint(id: UInt64): @NFT {
+              return <-create NFT(id: id, name: "test")
+          }
+
+          pub fun info(id: UInt64): Info {
+              return Info(id: id, name: "test")
+          }
+      }
+    `
+
+	const contractV2 = `
+      pub contract Test {
+
+          pub resource NFT {
+              #removedField("name")
+
+              pub let id: UInt64
+              pub var level: UInt64
+
+              init(id: UInt64) {
+                  self.id = id
+                  self.level = 0
+              }
+
+              pub fun levelUp() {
+                  self.level = self.level + 1
+              }
+          }
+
+          pub struct Info {
+              #removedField("name")
+
+              pub let id: UInt64
+
+              init(id: UInt64) {
+                  self.id = id
+              }
+          }
+
+          pub fun mint(id: UInt64): @NFT {
+              return <-create NFT(id: id)
+          }
+
+          pub fun info(id: UInt64): Info {
+              return Info(id: id)
+          }
+      }
+    `
+
+	// Adding back the removed field with a different type is rejected,
+	// as stored values might still contain the data of the removed field.
+	// The pragma may not be dropped to allow it either
+
+	const contractV3 = `
+      pub contract Test {
+
+          pub resource NFT {
+              pub let id: UInt64
+              pub var level: UInt64
+              pub var name: Int?
+
+              init(id: UInt64) {
+                  self.id = id
+                  self.level = 0
+                  self.name = nil
+              }
+          }
+
+          pub struct Info {
+              #removedField("name")
+
+              pub let id: UInt64
+
+              init(id: UInt64) {
+                  self.id = id
+              }
+          }
+      }
+    `
+
+	err := executeTransaction(newContractAddTransaction("Test", contractV1))
+	require.NoError(t, err)
+
+	err = executeTransaction(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save(<-Test.mint(id: 1), to: /storage/nft)
+              signer.save(Test.info(id: 1), to: /storage/info)
+          }
+      }
+    `)
+	require.NoError(t, err)
+
+	err = executeTransaction(newContractUpdateTransaction("Test", contractV2))
+	require.NoError(t, err)
+
+	// The data of the removed field was not purged yet
+
+	require.True(t, storedValueHasField("nft", "name"))
+
+	// Copying a stored value does not purge the data of the removed field
+
+	value := executeScript(`
+      import Test from 0x1
+
+      pub fun main(): UInt64 {
+          return getAuthAccount(0x1).copy<Test.Info>(from: /storage/info)!.id
+      }
+    `)
+	require.Equal(t, cadence.UInt64(1), value)
+
+	require.True(t, storedValueHasField("info", "name"))
+
+	// Exporting a stored value does not export the removed field
+
+	value = executeScript(`
+      import Test from 0x1
+
+      pub fun main(): Test.Info {
+          return getAuthAccount(0x1).copy<Test.Info>(from: /storage/info)!
+      }
+    `)
+	require.Equal(t,
+		[]cadence.Value{
+			cadence.UInt64(1),
+		},
+		value.(cadence.Struct).Fields,
+	)
+
+	// Writing the NFT purges the data of the removed field
+
+	err = executeTransaction(`
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              let nft = signer.borrow<&Test.NFT>(from: /storage/nft)!
+              nft.levelUp()
+          }
+      }
+    `)
+	require.NoError(t, err)
+
+	value = executeScript(`
+      import Test from 0x1
+
+      pub fun main(): UInt64 {
+          let nft = getAuthAccount(0x1).borrow<&Test.NFT>(from: /storage/nft)!
+          return nft.level
+      }
+    `)
+	require.Equal(t, cadence.UInt64(1), value)
+
+	require.False(t, storedValueHasField("nft", "name"))
+
+	err = executeTransaction(newContractUpdateTransaction("Test", contractV3))
+	require.Error(t, err)
+
+	updateErr := getContractUpdateError(t, err, "Test")
+
+	childErrors := updateErr.Errors
+	require.Len(t, childErrors, 2)
+
+	assertExtraneousFieldError(t, childErrors[0], "NFT", "name")
+
+	var missingRemovedFieldError *MissingRemovedFieldError
+	require.ErrorAs(t, childErrors[1], &missingRemovedFieldError)
+	require.Equal(t, "name", missingRemovedFieldError.FieldName)
+}
@@ -27,13 +27,15 @@ import (
 )
 
 type ContractUpdateValidator struct {
-	location     Location
-	contractName string
-	oldProgram   *ast.Program
-	newProgram   *ast.Program
-	rootDecl     ast.Declaration
-	currentDecl  ast.Declaration
-	errors       []error
+	location       Location
+	contractName   string
+	oldProgram     *ast.Program
+	newProgram     *ast.Program
+	rootDecl       ast.Declaration
+	currentDecl    ast.Declaration
+	oldTypeAliases typeAliases
+	newTypeAliases typeAliases
+	errors         []error
 }
 
 // ContractUpdateValidator should implement ast.TypeEqualityChecker
@@ -69,6 +71,9 @@ func (validator *ContractUpdateValidator) Validate() error {
 	}
 
 	validator.rootDecl = newRootDecl
+	validator.oldTypeAliases = getTypeAliases(validator.oldProgram, oldRootDecl)
+	validator.newTypeAliases = getTypeAliases(validator.newProgram, newRootDecl)
+
 	validator.checkDeclarationUpdatability(oldRootDecl, newRootDecl)
 
 	if validator.hasErrors() {
@@ -142,6 +147,7 @@ func (validator *ContractUpdateValidator) checkDeclarationUpdatability(
 
 	if newDecl, ok := newDeclaration.(*ast.CompositeDeclaration); ok {
 		if oldDecl, ok := oldDeclaration.(*ast.CompositeDeclaration); ok {
+			validator.checkTypeParameters(oldDecl, newDecl)
 			validator.checkConformances(oldDecl, newDecl)
 		}
 	}
@@ -149,15 +155,23 @@ func (validator *ContractUpdateValidator) checkDeclarationUpdatability(
 
 func (validator *ContractUpdateValidator) checkFields(oldDeclaration ast.Declaration, newDeclaration ast.Declaration) {
 
-	oldFields := oldDeclaration.DeclarationMembers().FieldsByIdentifier()
-	newFields := newDeclaration.DeclarationMembers().Fields()
+	oldMembers := oldDeclaration.DeclarationMembers()
+	newMembers := newDeclaration.DeclarationMembers()
+
+	oldFields := oldMembers.FieldsByIdentifier()
+	newFields := newMembers.FieldsByIdentifier()
 
-	// Updated contract has to have at-most the same number of field as the old contract.
-	// Any additional field may cause crashes/garbage-values when deserializing the already-stored data.
-	// However, having fewer fields is fine for now. It will only leave some unused data,
-	// and will not do any harm to the programs that are running.
+	// Fields cannot be added, not even optional ones.
+	//
+	// Already stored values might still contain data for a field with the same name,
+	// e.g. for a field which was removed before removals had to be declared.
+	// The validator cannot prove that the name was never used,
+	// and the new field might have a different type than the stored data.
+	//
+	// Any additional field would also be missing in the already-stored data,
+	// as initializers are not run again on update.
 
-	for _, newField := range newFields {
+	for _, newField := range newMembers.Fields() {
 		oldField := oldFields[newField.Identifier.Identifier]
 		if oldField == nil {
 			validator.report(&ExtraneousFieldError{
@@ -171,6 +185,74 @@ func (validator *ContractUpdateValidator) checkFields(oldDeclaration ast.Declara
 
 		validator.checkField(oldField, newField)
 	}
+
+	validator.checkRemovedFields(oldDeclaration, newDeclaration, oldFields, newFields)
+}
+
+// checkRemovedFields checks the removal of fields.
+//
+// Fields of composites may only be removed explicitly, using the `#removedField` pragma.
+// Removed fields must stay removed, as their data is purged lazily,
+// i.e. stored values might still contain the data of the removed field.
+// Resource fields cannot be removed, as the resources would be lost.
+//
+// Interfaces have no stored data, so their fields may be removed implicitly
+//
+func (validator *ContractUpdateValidator) checkRemovedFields(
+	oldDeclaration ast.Declaration,
+	newDeclaration ast.Declaration,
+	oldFields map[string]*ast.FieldDeclaration,
+	newFields map[string]*ast.FieldDeclaration,
+) {
+	declName := newDeclaration.DeclarationIdentifier().Identifier
+
+	newRemovedFields := map[string]struct{}{}
+	for _, name := range sema.RemovedFieldNames(newDeclaration.DeclarationMembers()) {
+		newRemovedFields[name] = struct{}{}
+
+		oldField := oldFields[name]
+		if oldField != nil && oldField.TypeAnnotation.IsResource {
+			validator.report(&ResourceFieldRemovalError{
+				DeclName:  declName,
+				FieldName: name,
+				Range:     ast.NewUnmeteredRangeFromPositioned(newDeclaration.DeclarationIdentifier()),
+			})
+		}
+	}
+
+	for _, name := range sema.RemovedFieldNames(oldDeclaration.DeclarationMembers()) {
+		if _, ok := newRemovedFields[name]; ok {
+			continue
+		}
+
+		validator.report(&MissingRemovedFieldError{
+			DeclName:  declName,
+			FieldName: name,
+			Range:     ast.NewUnmeteredRangeFromPositioned(newDeclaration.DeclarationIdentifier()),
+		})
+	}
+
+	if _, ok := newDeclaration.(*ast.CompositeDeclaration); !ok {
+		return
+	}
+
+	for _, oldField := range oldDeclaration.DeclarationMembers().Fields() {
+		name := oldField.Identifier.Identifier
+
+		if _, ok := newFields[name]; ok {
+			continue
+		}
+
+		if _, ok := newRemovedFields[name]; ok {
+			continue
+		}
+
+		validator.report(&ImplicitFieldRemovalError{
+			DeclName:  declName,
+			FieldName: name,
+			Range:     ast.NewUnmeteredRangeFromPositioned(newDeclaration.DeclarationIdentifier()),
+		})
+	}
 }
 
 func (validator *ContractUpdateValidator) checkField(oldField *ast.FieldDeclaration, newField *ast.FieldDeclaration) {
@@ -311,6 +393,13 @@ func (validator *ContractUpdateValidator) checkEnumCases(oldDeclaration ast.Decl
 }
 
 func (validator *ContractUpdateValidator) CheckNominalTypeEquality(expected *ast.NominalType, found ast.Type) error {
+	// Type aliases are not nominal, compare the aliased type instead
+	if typeAlias := validator.oldTypeAliases.lookup(expected); typeAlias != nil {
+		return typeAlias.Type.CheckEqual(found, validator)
+	}
+
+	found = validator.newTypeAliases.expand(found)
+
 	foundNominalType, ok := found.(*ast.NominalType)
 	if !ok {
 		return getTypeMismatchError(expected, found)
@@ -326,6 +415,8 @@ func (validator *ContractUpdateValidator) CheckNominalTypeEquality(expected *ast
 }
 
 func (validator *ContractUpdateValidator) CheckOptionalTypeEquality(expected *ast.OptionalType, found ast.Type) error {
+	found = validator.newTypeAliases.expand(found)
+
 	foundOptionalType, ok := found.(*ast.OptionalType)
 	if !ok {
 		return getTypeMismatchError(expected, found)
@@ -335,6 +426,8 @@ func (validator *ContractUpdateValidator) CheckOptionalTypeEquality(expected *as
 }
 
 func (validator *ContractUpdateValidator) CheckVariableSizedTypeEquality(expected *ast.VariableSizedType, found ast.Type) error {
+	found = validator.newTypeAliases.expand(found)
+
 	foundVarSizedType, ok := found.(*ast.VariableSizedType)
 	if !ok {
 		return getTypeMismatchError(expected, found)
@@ -344,6 +437,8 @@ func (validator *ContractUpdateValidator) CheckVariableSizedTypeEquality(expecte
 }
 
 func (validator *ContractUpdateValidator) CheckConstantSizedTypeEquality(expected *ast.ConstantSizedType, found ast.Type) error {
+	found = validator.newTypeAliases.expand(found)
+
 	foundConstSizedType, ok := found.(*ast.ConstantSizedType)
 	if !ok {
 		return getTypeMismatchError(expected, found)
@@ -360,6 +455,8 @@ func (validator *ContractUpdateValidator) CheckConstantSizedTypeEquality(expecte
 }
 
 func (validator *ContractUpdateValidator) CheckDictionaryTypeEquality(expected *ast.DictionaryType, found ast.Type) error {
+	found = validator.newTypeAliases.expand(found)
+
 	foundDictionaryType, ok := found.(*ast.DictionaryType)
 	if !ok {
 		return getTypeMismatchError(expected, found)
@@ -374,6 +471,8 @@ func (validator *ContractUpdateValidator) CheckDictionaryTypeEquality(expected *
 }
 
 func (validator *ContractUpdateValidator) CheckRestrictedTypeEquality(expected *ast.RestrictedType, found ast.Type) error {
+	found = validator.newTypeAliases.expand(found)
+
 	foundRestrictedType, ok := found.(*ast.RestrictedType)
 	if !ok {
 		return getTypeMismatchError(expected, found)
@@ -413,6 +512,8 @@ func (validator *ContractUpdateValidator) CheckRestrictedTypeEquality(expected *
 }
 
 func (validator *ContractUpdateValidator) CheckInstantiationTypeEquality(expected *ast.InstantiationType, found ast.Type) error {
+	found = validator.newTypeAliases.expand(found)
+
 	foundInstType, ok := found.(*ast.InstantiationType)
 	if !ok {
 		return getTypeMismatchError(expected, found)
@@ -435,6 +536,8 @@ func (validator *ContractUpdateValidator) CheckInstantiationTypeEquality(expecte
 }
 
 func (validator *ContractUpdateValidator) CheckFunctionTypeEquality(expected *ast.FunctionType, found ast.Type) error {
+	found = validator.newTypeAliases.expand(found)
+
 	foundFuncType, ok := found.(*ast.FunctionType)
 	if !ok || len(expected.ParameterTypeAnnotations) != len(foundFuncType.ParameterTypeAnnotations) {
 		return getTypeMismatchError(expected, found)
@@ -452,6 +555,8 @@ func (validator *ContractUpdateValidator) CheckFunctionTypeEquality(expected *as
 }
 
 func (validator *ContractUpdateValidator) CheckReferenceTypeEquality(expected *ast.ReferenceType, found ast.Type) error {
+	found = validator.newTypeAliases.expand(found)
+
 	refType, ok := found.(*ast.ReferenceType)
 	if !ok {
 		return getTypeMismatchError(expected, found)
@@ -547,6 +652,52 @@ func (validator *ContractUpdateValidator) checkConformances(
 	}
 }
 
+// checkTypeParameters checks that the type parameters of a generic composite are unchanged.
+// Stored values of generic composites record their type arguments,
+// so the number of type parameters and their bounds must stay the same.
+func (validator *ContractUpdateValidator) checkTypeParameters(
+	oldDecl *ast.CompositeDeclaration,
+	newDecl *ast.CompositeDeclaration,
+) {
+	var oldTypeParameters, newTypeParameters []*ast.TypeParameter
+
+	if oldDecl.TypeParameterList != nil {
+		oldTypeParameters = oldDecl.TypeParameterList.TypeParameters
+	}
+
+	if newDecl.TypeParameterList != nil {
+		newTypeParameters = newDecl.TypeParameterList.TypeParameters
+	}
+
+	report := func() {
+		validator.report(&TypeParameterMismatchError{
+			DeclName: newDecl.Identifier.Identifier,
+			Range:    ast.NewUnmeteredRangeFromPositioned(newDecl.Identifier),
+		})
+	}
+
+	if len(oldTypeParameters) != len(newTypeParameters) {
+		report()
+		return
+	}
+
+	for index, oldTypeParameter := range oldTypeParameters {
+		oldTypeBound := oldTypeParameter.TypeBound
+		newTypeBound := newTypeParameters[index].TypeBound
+
+		if oldTypeBound == nil && newTypeBound == nil {
+			continue
+		}
+
+		if oldTypeBound == nil || newTypeBound == nil ||
+			oldTypeBound.CheckEqual(newTypeBound, validator) != nil {
+
+			report()
+			return
+		}
+	}
+}
+
 func (validator *ContractUpdateValidator) report(err error) {
 	if err == nil {
 		return
@@ -626,3 +777,70 @@ func containsEnums(declaration ast.Declaration) bool {
 
 	return false
 }
+
+// typeAliases are the type aliases declared in a program, by name.
+// Type aliases declared in the contract are also available by their qualified name.
+type typeAliases map[string]*ast.TypeAliasDeclaration
+
+func getTypeAliases(program *ast.Program, rootDecl ast.Declaration) typeAliases {
+	aliases := typeAliases{}
+
+	for _, typeAlias := range program.TypeAliasDeclarations() {
+		aliases[typeAlias.Identifier.Identifier] = typeAlias
+	}
+
+	rootName := rootDecl.DeclarationIdentifier().Identifier
+
+	for _, typeAlias := range rootDecl.DeclarationMembers().TypeAliases() {
+		name := typeAlias.Identifier.Identifier
+		aliases[name] = typeAlias
+		aliases[rootName+"."+name] = typeAlias
+	}
+
+	return aliases
+}
+
+// lookup returns the type alias declaration the given nominal type refers to, if any.
+func (aliases typeAliases) lookup(nominalType *ast.NominalType) *ast.TypeAliasDeclaration {
+	name := nominalType.Identifier.Identifier
+
+	switch len(nominalType.NestedIdentifiers) {
+	case 0:
+		break
+	case 1:
+		name += "." + nominalType.NestedIdentifiers[0].Identifier
+	default:
+		return nil
+	}
+
+	return aliases[name]
+}
+
+// expand returns the type aliased by the given type, if it refers to a type alias,
+// or the given type itself otherwise.
+//
+// NOTE: Only the outermost type is expanded. Nested types are expanded
+// when they are compared, as the type equality check recurses into them.
+func (aliases typeAliases) expand(ty ast.Type) ast.Type {
+	seen := map[*ast.TypeAliasDeclaration]struct{}{}
+
+	for {
+		nominalType, ok := ty.(*ast.NominalType)
+		if !ok {
+			return ty
+		}
+
+		typeAlias := aliases.lookup(nominalType)
+		if typeAlias == nil {
+			return ty
+		}
+
+		// Guard against cyclic type aliases in invalid programs
+		if _, ok := seen[typeAlias]; ok {
+			return ty
+		}
+		seen[typeAlias] = struct{}{}
+
+		ty = typeAlias.Type
+	}
+}
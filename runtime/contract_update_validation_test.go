@@ -251,10 +251,183 @@ func TestRuntimeContractUpdateValidation(t *testing.T) {
             }
         `
 
+		err := testDeployAndUpdate(t, contractValidationEnabled, "Test", oldCode, newCode)
+		require.Error(t, err)
+
+		cause := getSingleContractUpdateErrorCause(t, err, "Test")
+		assertImplicitFieldRemovalError(t, cause, "Test", "b")
+	})
+
+	t.Run("remove interface field", func(t *testing.T) {
+
+		t.Parallel()
+
+		const oldCode = `
+            pub contract interface Test {
+                pub var a: String
+                pub var b: Int
+            }
+        `
+
+		const newCode = `
+            pub contract interface Test {
+                pub var a: String
+            }
+        `
+
 		err := testDeployAndUpdate(t, contractValidationEnabled, "Test", oldCode, newCode)
 		require.NoError(t, err)
 	})
 
+	t.Run("add optional field", func(t *testing.T) {
+
+		t.Parallel()
+
+		const oldCode = `
+            pub contract Test {
+                pub var a: String
+
+                init() {
+                    self.a = "hello"
+                }
+            }
+        `
+
+		const newCode = `
+            pub contract Test {
+                pub var a: String
+                pub var b: {String: String}?
+
+                init() {
+                    self.a = "hello"
+                    self.b = nil
+                }
+            }
+        `
+
+		err := testDeployAndUpdate(t, contractValidationEnabled, "Test", oldCode, newCode)
+		require.Error(t, err)
+
+		cause := getSingleContractUpdateErrorCause(t, err, "Test")
+		assertExtraneousFieldError(t, cause, "Test", "b")
+	})
+
+	t.Run("remove field explicitly", func(t *testing.T) {
+
+		t.Parallel()
+
+		const oldCode = `
+            pub contract Test {
+                pub var a: String
+                pub var b: Int
+
+                init() {
+                    self.a = "hello"
+                    self.b = 0
+                }
+            }
+        `
+
+		const newCode = `
+            pub contract Test {
+                #removedField("b")
+
+                pub var a: String
+
+                init() {
+                    self.a = "hello"
+                }
+            }
+        `
+
+		err := testDeployAndUpdate(t, contractValidationEnabled, "Test", oldCode, newCode)
+		require.NoError(t, err)
+	})
+
+	t.Run("remove resource field explicitly", func(t *testing.T) {
+
+		t.Parallel()
+
+		const oldCode = `
+            pub contract Test {
+                pub resource R {}
+
+                pub var r: @R
+
+                init() {
+                    self.r <- create R()
+                }
+            }
+        `
+
+		const newCode = `
+            pub contract Test {
+                #removedField("r")
+
+                pub resource R {}
+
+                init() {}
+            }
+        `
+
+		err := testDeployAndUpdate(t, contractValidationEnabled, "Test", oldCode, newCode)
+		require.Error(t, err)
+
+		cause := getSingleContractUpdateErrorCause(t, err, "Test")
+
+		var resourceFieldRemovalError *ResourceFieldRemovalError
+		require.ErrorAs(t, cause, &resourceFieldRemovalError)
+
+		assert.Equal(t, "Test", resourceFieldRemovalError.DeclName)
+		assert.Equal(t, "r", resourceFieldRemovalError.FieldName)
+	})
+
+	t.Run("re-add removed field", func(t *testing.T) {
+
+		t.Parallel()
+
+		const oldCode = `
+            pub contract Test {
+                #removedField("b")
+
+                pub var a: String
+
+                init() {
+                    self.a = "hello"
+                }
+            }
+        `
+
+		const newCode = `
+            pub contract Test {
+                pub var a: String
+                pub var b: String?
+
+                init() {
+                    self.a = "hello"
+                    self.b = nil
+                }
+            }
+        `
+
+		err := testDeployAndUpdate(t, contractValidationEnabled, "Test", oldCode, newCode)
+		require.Error(t, err)
+
+		updateErr := getContractUpdateError(t, err, "Test")
+		require.NotNil(t, updateErr)
+
+		childErrors := updateErr.Errors
+		require.Len(t, childErrors, 2)
+
+		assertExtraneousFieldError(t, childErrors[0], "Test", "b")
+
+		var missingRemovedFieldError *MissingRemovedFieldError
+		require.ErrorAs(t, childErrors[1], &missingRemovedFieldError)
+
+		assert.Equal(t, "Test", missingRemovedFieldError.DeclName)
+		assert.Equal(t, "b", missingRemovedFieldError.FieldName)
+	})
+
 	t.Run("change nested decl field type", func(t *testing.T) {
 
 		t.Parallel()
@@ -999,8 +1172,14 @@ func TestRuntimeContractUpdateValidation(t *testing.T) {
 		err := testDeployAndUpdate(t, contractValidationEnabled, "Test", oldCode, newCode)
 		require.Error(t, err)
 
-		cause := getSingleContractUpdateErrorCause(t, err, "Test")
-		assertExtraneousFieldError(t, cause, "Test", "b")
+		updateErr := getContractUpdateError(t, err, "Test")
+		require.NotNil(t, updateErr)
+
+		childErrors := updateErr.Errors
+		require.Len(t, childErrors, 2)
+
+		assertExtraneousFieldError(t, childErrors[0], "Test", "b")
+		assertImplicitFieldRemovalError(t, childErrors[1], "Test", "a")
 	})
 
 	t.Run("multiple errors", func(t *testing.T) {
@@ -1688,6 +1867,8 @@ func TestRuntimeContractUpdateValidation(t *testing.T) {
 		    pub contract Test {
 
 		        pub struct TestStruct {
+		            #removedField("b")
+
 		            pub let a: String
 
 		            init() {
@@ -1831,6 +2012,218 @@ func TestRuntimeContractUpdateValidation(t *testing.T) {
 			assertMissingDeclarationError(t, childErrors[1], "B")
 		}
 	})
+
+	t.Run("introduce type alias", func(t *testing.T) {
+
+		t.Parallel()
+
+		const oldCode = `
+            pub contract Test {
+                pub var a: {String: UFix64}
+                pub var b: @{UInt64: R}
+
+                pub resource R {}
+
+                init() {
+                    self.a = {}
+                    self.b <- {}
+                }
+            }
+        `
+
+		const newCode = `
+            pub typealias Balances = {String: UFix64}
+
+            pub contract Test {
+                pub typealias Vaults = {UInt64: Test.R}
+
+                pub var a: Balances
+                pub var b: @Vaults
+
+                pub resource R {}
+
+                init() {
+                    self.a = {}
+                    self.b <- {}
+                }
+            }
+        `
+
+		err := testDeployAndUpdate(t, contractValidationEnabled, "Test", oldCode, newCode)
+		require.NoError(t, err)
+	})
+
+	t.Run("remove type alias", func(t *testing.T) {
+
+		t.Parallel()
+
+		const oldCode = `
+            pub contract Test {
+                pub typealias Amount = UFix64
+                pub typealias Amounts = [Amount]
+
+                pub var a: Test.Amounts
+
+                init() {
+                    self.a = []
+                }
+            }
+        `
+
+		const newCode = `
+            pub contract Test {
+                pub var a: [UFix64]
+
+                init() {
+                    self.a = []
+                }
+            }
+        `
+
+		err := testDeployAndUpdate(t, contractValidationEnabled, "Test", oldCode, newCode)
+		require.NoError(t, err)
+	})
+
+	t.Run("change aliased type", func(t *testing.T) {
+
+		t.Parallel()
+
+		const oldCode = `
+            pub contract Test {
+                pub typealias Amount = UFix64
+
+                pub var a: Amount
+
+                init() {
+                    self.a = 0.0
+                }
+            }
+        `
+
+		const newCode = `
+            pub contract Test {
+                pub typealias Amount = UInt64
+
+                pub var a: Amount
+
+                init() {
+                    self.a = 0
+                }
+            }
+        `
+
+		err := testDeployAndUpdate(t, contractValidationEnabled, "Test", oldCode, newCode)
+		require.Error(t, err)
+
+		cause := getSingleContractUpdateErrorCause(t, err, "Test")
+		assertFieldTypeMismatchError(t, cause, "Test", "a", "UFix64", "UInt64")
+	})
+
+	t.Run("keep type parameters", func(t *testing.T) {
+
+		t.Parallel()
+
+		const oldCode = `
+            pub contract Test {
+                pub struct Box<T: AnyStruct> {
+                    pub let value: T
+
+                    init(value: T) {
+                        self.value = value
+                    }
+                }
+            }
+        `
+
+		const newCode = `
+            pub contract Test {
+                pub struct Box<T: AnyStruct> {
+                    pub let value: T
+
+                    init(value: T) {
+                        self.value = value
+                    }
+
+                    pub fun get(): T {
+                        return self.value
+                    }
+                }
+            }
+        `
+
+		err := testDeployAndUpdate(t, contractValidationEnabled, "Test", oldCode, newCode)
+		require.NoError(t, err)
+	})
+
+	t.Run("add type parameter", func(t *testing.T) {
+
+		t.Parallel()
+
+		const oldCode = `
+            pub contract Test {
+                pub struct Box<T> {
+                    pub let value: T
+
+                    init(value: T) {
+                        self.value = value
+                    }
+                }
+            }
+        `
+
+		const newCode = `
+            pub contract Test {
+                pub struct Box<T, U> {
+                    pub let value: T
+
+                    init(value: T) {
+                        self.value = value
+                    }
+                }
+            }
+        `
+
+		err := testDeployAndUpdate(t, contractValidationEnabled, "Test", oldCode, newCode)
+		require.Error(t, err)
+
+		cause := getSingleContractUpdateErrorCause(t, err, "Test")
+		assertTypeParameterMismatchError(t, cause, "Box")
+	})
+
+	t.Run("change type parameter bound", func(t *testing.T) {
+
+		t.Parallel()
+
+		const oldCode = `
+            pub contract Test {
+                pub struct Box<T> {
+                    pub let value: T
+
+                    init(value: T) {
+                        self.value = value
+                    }
+                }
+            }
+        `
+
+		const newCode = `
+            pub contract Test {
+                pub struct Box<T: Integer> {
+                    pub let value: T
+
+                    init(value: T) {
+                        self.value = value
+                    }
+                }
+            }
+        `
+
+		err := testDeployAndUpdate(t, contractValidationEnabled, "Test", oldCode, newCode)
+		require.Error(t, err)
+
+		cause := getSingleContractUpdateErrorCause(t, err, "Test")
+		assertTypeParameterMismatchError(t, cause, "Box")
+	})
 }
 
 func assertContractRemovalError(t *testing.T, err error, name string) {
@@ -1863,6 +2256,14 @@ func assertExtraneousFieldError(t *testing.T, err error, erroneousDeclName strin
 	assert.Equal(t, erroneousDeclName, extraFieldError.DeclName)
 }
 
+func assertImplicitFieldRemovalError(t *testing.T, err error, erroneousDeclName string, fieldName string) {
+	var implicitFieldRemovalError *ImplicitFieldRemovalError
+	require.ErrorAs(t, err, &implicitFieldRemovalError)
+
+	assert.Equal(t, fieldName, implicitFieldRemovalError.FieldName)
+	assert.Equal(t, erroneousDeclName, implicitFieldRemovalError.DeclName)
+}
+
 func assertFieldTypeMismatchError(
 	t *testing.T,
 	err error,
@@ -1895,6 +2296,17 @@ func assertConformanceMismatchError(
 	assert.Equal(t, erroneousDeclName, conformanceMismatchError.DeclName)
 }
 
+func assertTypeParameterMismatchError(
+	t *testing.T,
+	err error,
+	erroneousDeclName string,
+) {
+	var typeParameterMismatchError *TypeParameterMismatchError
+	require.ErrorAs(t, err, &typeParameterMismatchError)
+
+	assert.Equal(t, erroneousDeclName, typeParameterMismatchError.DeclName)
+}
+
 func assertEnumCaseMismatchError(t *testing.T, err error, expectedEnumCase string, foundEnumCase string) {
 	var enumMismatchError *EnumCaseMismatchError
 	require.ErrorAs(t, err, &enumMismatchError)
@@ -366,8 +366,12 @@ func exportCompositeType(
 
 	fieldMembers := make([]*sema.Member, 0, len(t.Fields))
 
+	// NOTE: use the resolved members, as the composite type might be
+	// an instantiation of a generic composite type
+	members := t.ResolvedMembers()
+
 	for _, identifier := range t.Fields {
-		member, ok := t.Members.Get(identifier)
+		member, ok := members.Get(identifier)
 
 		if !ok {
 			panic(errors.NewUnreachableError())
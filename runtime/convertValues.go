@@ -656,6 +656,12 @@ func exportEvent(
 	return exported.WithType(eventType), nil
 }
 
+// ImportValue converts a Cadence value to a runtime value.
+// The expected type, if any, determines the static types of imported containers
+func ImportValue(inter *interpreter.Interpreter, value cadence.Value, expectedType sema.Type) (interpreter.Value, error) {
+	return importValue(inter, value, expectedType)
+}
+
 // importValue converts a Cadence value to a runtime value.
 func importValue(
 	inter *interpreter.Interpreter,
@@ -1238,6 +1244,12 @@ func importCompositeValue(
 		return nil, typeErr
 	}
 
+	// The type arguments of values of generic composite types are not exported,
+	// so such values cannot be imported
+	if compositeType.IsGeneric() {
+		return nil, fmt.Errorf("cannot import value of generic composite type %s", typeID)
+	}
+
 	for i := 0; i < len(fieldTypes) && i < len(fieldValues); i++ {
 		fieldType := fieldTypes[i]
 		fieldValue := fieldValues[i]
@@ -18,22 +18,367 @@
 
 package runtime
 
-import "github.com/onflow/cadence/runtime/common"
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// FunctionCoverage records how often a function was entered
+//
+type FunctionCoverage struct {
+	Name string `json:"name"`
+	Line int    `json:"line"`
+	Hits int    `json:"hits"`
+}
+
+// BranchKind is the kind of element which has branches
+//
+type BranchKind string
+
+const (
+	BranchKindIf            BranchKind = "if"
+	BranchKindSwitch        BranchKind = "switch"
+	BranchKindConditional   BranchKind = "conditional"
+	BranchKindNilCoalescing BranchKind = "nil-coalescing"
+)
+
+// BranchCoverage records how often each outcome of a branching element was taken.
+//
+// For if statements and conditional expressions, the first outcome is the "then" branch,
+// and the second outcome is the "else" branch.
+//
+// For switch statements, there is one outcome per case,
+// and an additional outcome for "no case matched" if there is no default case.
+//
+// For nil-coalescing expressions, the first outcome is the left-hand side,
+// and the second outcome is the right-hand side.
+//
+type BranchCoverage struct {
+	Kind   BranchKind `json:"kind"`
+	Line   int        `json:"line"`
+	Column int        `json:"column"`
+	Hits   []int      `json:"hits"`
+}
+
+type branchKey struct {
+	startOffset int
+	endOffset   int
+}
+
+func newBranchKey(element ast.Element) branchKey {
+	return branchKey{
+		startOffset: element.StartPosition().Offset,
+		endOffset:   element.EndPosition(nil).Offset,
+	}
+}
 
 // LocationCoverage records coverage information for a location
 //
 type LocationCoverage struct {
-	LineHits map[int]int `json:"line_hits"`
+	LineHits       map[int]int         `json:"line_hits"`
+	CoverableLines []int               `json:"coverable_lines"`
+	Functions      []*FunctionCoverage `json:"functions"`
+	Branches       []*BranchCoverage   `json:"branches"`
+	// program is the program which was inspected to determine
+	// the coverable lines, the functions, and the branches
+	program   *ast.Program
+	functions map[int]*FunctionCoverage
+	branches  map[branchKey]*BranchCoverage
 }
 
 func (c *LocationCoverage) AddLineHit(line int) {
 	c.LineHits[line]++
 }
 
+// AddFunctionHit records the entry of the function with the function block
+// starting at the given position.
+// Entries of functions which were not found during inspection are ignored.
+//
+func (c *LocationCoverage) AddFunctionHit(functionBlockPosition ast.Position) {
+	function, ok := c.functions[functionBlockPosition.Offset]
+	if !ok {
+		return
+	}
+	function.Hits++
+}
+
+// AddBranchHit records that the given branch of the given element was taken.
+// Branches of elements which were not found during inspection are ignored.
+//
+func (c *LocationCoverage) AddBranchHit(element ast.Element, branch int) {
+	branchCoverage, ok := c.branches[newBranchKey(element)]
+	if !ok || branch < 0 || branch >= len(branchCoverage.Hits) {
+		return
+	}
+	branchCoverage.Hits[branch]++
+}
+
+// Lines returns all lines which are coverable or were hit, in ascending order
+//
+func (c *LocationCoverage) Lines() []int {
+	lines := make([]int, 0, len(c.CoverableLines))
+	seen := map[int]struct{}{}
+
+	for _, line := range c.CoverableLines {
+		seen[line] = struct{}{}
+		lines = append(lines, line)
+	}
+
+	for line := range c.LineHits { //nolint:maprangecheck
+		if _, ok := seen[line]; ok {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	sort.Ints(lines)
+
+	return lines
+}
+
+// CoveredLines returns the number of lines which were hit
+//
+func (c *LocationCoverage) CoveredLines() int {
+	count := 0
+	for _, line := range c.Lines() {
+		if c.LineHits[line] > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// CoveredFunctions returns the number of functions which were entered
+//
+func (c *LocationCoverage) CoveredFunctions() int {
+	count := 0
+	for _, function := range c.Functions {
+		if function.Hits > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// BranchOutcomes returns the number of branch outcomes,
+// and the number of branch outcomes which were taken
+//
+func (c *LocationCoverage) BranchOutcomes() (total int, covered int) {
+	for _, branch := range c.Branches {
+		for _, hits := range branch.Hits {
+			total++
+			if hits > 0 {
+				covered++
+			}
+		}
+	}
+	return
+}
+
+// InspectProgram determines the coverable lines, the functions, and the branches
+// of the given program.
+//
+// The inspection is skipped if the program was already inspected.
+// If a different program was previously inspected (e.g. the code of a contract was updated),
+// the previous information is replaced, but the line hits are kept.
+//
+func (c *LocationCoverage) InspectProgram(program *ast.Program) {
+	if c.program == program {
+		return
+	}
+
+	c.program = program
+	c.Functions = []*FunctionCoverage{}
+	c.Branches = []*BranchCoverage{}
+	c.functions = map[int]*FunctionCoverage{}
+	c.branches = map[branchKey]*BranchCoverage{}
+
+	inspector := &coverageInspector{
+		coverage: c,
+		lines:    map[int]struct{}{},
+	}
+
+	ast.Inspect(program, inspector.inspect)
+
+	c.CoverableLines = make([]int, 0, len(inspector.lines))
+	for line := range inspector.lines { //nolint:maprangecheck
+		c.CoverableLines = append(c.CoverableLines, line)
+	}
+	sort.Ints(c.CoverableLines)
+
+	sort.SliceStable(c.Functions, func(i, j int) bool {
+		return c.Functions[i].Line < c.Functions[j].Line
+	})
+
+	sort.SliceStable(c.Branches, func(i, j int) bool {
+		a := c.Branches[i]
+		b := c.Branches[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
 func NewLocationCoverage() *LocationCoverage {
 	return &LocationCoverage{
-		LineHits: map[int]int{},
+		LineHits:       map[int]int{},
+		CoverableLines: []int{},
+		Functions:      []*FunctionCoverage{},
+		Branches:       []*BranchCoverage{},
+		functions:      map[int]*FunctionCoverage{},
+		branches:       map[branchKey]*BranchCoverage{},
+	}
+}
+
+// coverageInspector walks a program and records
+// the coverable lines, the functions, and the branches
+//
+type coverageInspector struct {
+	coverage *LocationCoverage
+	lines    map[int]struct{}
+	parents  []ast.Element
+}
+
+func (i *coverageInspector) inspect(element ast.Element) bool {
+	if element == nil {
+		i.parents = i.parents[:len(i.parents)-1]
+		return true
+	}
+
+	var parent ast.Element
+	if len(i.parents) > 0 {
+		parent = i.parents[len(i.parents)-1]
+	}
+
+	// Statements are only executed (and reported) when they are part of a block,
+	// or part of a switch case
+
+	if statement, ok := element.(ast.Statement); ok {
+		switch parent.(type) {
+		case *ast.Block, *ast.SwitchStatement:
+			i.addLine(statement)
+		}
+	}
+
+	switch element := element.(type) {
+	case *ast.FunctionDeclaration:
+		i.addFunction(element, element.Identifier.Identifier, element.FunctionBlock)
+
+	case *ast.SpecialFunctionDeclaration:
+		i.addFunction(element, element.Kind.Keywords(), element.FunctionDeclaration.FunctionBlock)
+
+	case *ast.FunctionExpression:
+		position := element.StartPosition()
+		name := fmt.Sprintf("<anonymous %d:%d>", position.Line, position.Column)
+		i.addFunction(element, name, element.FunctionBlock)
+
+	case *ast.FunctionBlock:
+		i.addConditions(element.PreConditions)
+		i.addConditions(element.PostConditions)
+
+	case *ast.TransactionDeclaration:
+		i.addConditions(element.PreConditions)
+		i.addConditions(element.PostConditions)
+
+	case *ast.IfStatement:
+		i.addBranch(element, BranchKindIf, 2)
+
+	case *ast.SwitchStatement:
+		outcomes := len(element.Cases)
+		hasDefault := false
+		for _, switchCase := range element.Cases {
+			if switchCase.Expression == nil {
+				hasDefault = true
+			}
+		}
+		if !hasDefault {
+			outcomes++
+		}
+		i.addBranch(element, BranchKindSwitch, outcomes)
+
+	case *ast.ConditionalExpression:
+		i.addBranch(element, BranchKindConditional, 2)
+
+	case *ast.BinaryExpression:
+		if element.Operation == ast.OperationNilCoalesce {
+			i.addBranch(element, BranchKindNilCoalescing, 2)
+		}
+	}
+
+	i.parents = append(i.parents, element)
+
+	return true
+}
+
+func (i *coverageInspector) addLine(element ast.HasPosition) {
+	i.lines[element.StartPosition().Line] = struct{}{}
+}
+
+func (i *coverageInspector) addConditions(conditions *ast.Conditions) {
+	if conditions == nil {
+		return
+	}
+	for _, condition := range *conditions {
+		i.addLine(condition.Test)
+	}
+}
+
+func (i *coverageInspector) addFunction(
+	element ast.Element,
+	name string,
+	functionBlock *ast.FunctionBlock,
+) {
+	// Functions without a body and functions of interfaces are never entered
+
+	if functionBlock == nil {
+		return
 	}
+
+	var qualifiers []string
+
+	for _, parent := range i.parents {
+		switch parent := parent.(type) {
+		case *ast.CompositeDeclaration:
+			qualifiers = append(qualifiers, parent.Identifier.Identifier)
+		case *ast.InterfaceDeclaration:
+			return
+		}
+	}
+
+	if len(qualifiers) > 0 {
+		name = fmt.Sprintf("%s.%s", strings.Join(qualifiers, "."), name)
+	}
+
+	function := &FunctionCoverage{
+		Name: name,
+		Line: element.StartPosition().Line,
+	}
+
+	c := i.coverage
+	c.Functions = append(c.Functions, function)
+	c.functions[functionBlock.StartPosition().Offset] = function
+}
+
+func (i *coverageInspector) addBranch(element ast.Element, kind BranchKind, outcomes int) {
+	position := element.StartPosition()
+
+	branch := &BranchCoverage{
+		Kind:   kind,
+		Line:   position.Line,
+		Column: position.Column,
+		Hits:   make([]int, outcomes),
+	}
+
+	c := i.coverage
+	c.Branches = append(c.Branches, branch)
+	c.branches[newBranchKey(element)] = branch
 }
 
 // CoverageReport is a collection of coverage per location
@@ -42,14 +387,54 @@ type CoverageReport struct {
 	Coverage map[common.LocationID]*LocationCoverage `json:"coverage"`
 }
 
-func (r *CoverageReport) AddLineHit(location common.Location, line int) {
+func (r *CoverageReport) locationCoverage(location common.Location) *LocationCoverage {
 	locationID := location.ID()
 	locationCoverage := r.Coverage[locationID]
 	if locationCoverage == nil {
 		locationCoverage = NewLocationCoverage()
 		r.Coverage[locationID] = locationCoverage
 	}
-	locationCoverage.AddLineHit(line)
+	return locationCoverage
+}
+
+// InspectProgram determines the coverable lines, the functions, and the branches
+// of the given program at the given location
+//
+func (r *CoverageReport) InspectProgram(location common.Location, program *ast.Program) {
+	r.locationCoverage(location).InspectProgram(program)
+}
+
+func (r *CoverageReport) AddLineHit(location common.Location, line int) {
+	r.locationCoverage(location).AddLineHit(line)
+}
+
+func (r *CoverageReport) AddFunctionHit(location common.Location, functionBlockPosition ast.Position) {
+	r.locationCoverage(location).AddFunctionHit(functionBlockPosition)
+}
+
+func (r *CoverageReport) AddBranchHit(location common.Location, element ast.Element, branch int) {
+	r.locationCoverage(location).AddBranchHit(element, branch)
+}
+
+// LocationIDs returns the IDs of all covered locations, in ascending order
+//
+func (r *CoverageReport) LocationIDs() []common.LocationID {
+	locationIDs := make([]common.LocationID, 0, len(r.Coverage))
+	for locationID := range r.Coverage { //nolint:maprangecheck
+		locationIDs = append(locationIDs, locationID)
+	}
+	sort.Slice(locationIDs, func(i, j int) bool {
+		return locationIDs[i] < locationIDs[j]
+	})
+	return locationIDs
+}
+
+// ExportJSON writes the report in JSON format to the given writer
+//
+func (r *CoverageReport) ExportJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
 }
 
 func NewCoverageReport() *CoverageReport {
@@ -0,0 +1,230 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const coberturaHeader = xml.Header +
+	`<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">` + "\n"
+
+type coberturaCoverage struct {
+	XMLName         xml.Name           `xml:"coverage"`
+	LineRate        string             `xml:"line-rate,attr"`
+	BranchRate      string             `xml:"branch-rate,attr"`
+	LinesCovered    int                `xml:"lines-covered,attr"`
+	LinesValid      int                `xml:"lines-valid,attr"`
+	BranchesCovered int                `xml:"branches-covered,attr"`
+	BranchesValid   int                `xml:"branches-valid,attr"`
+	Complexity      int                `xml:"complexity,attr"`
+	Version         string             `xml:"version,attr"`
+	Timestamp       int64              `xml:"timestamp,attr"`
+	Packages        []coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Name       string           `xml:"name,attr"`
+	LineRate   string           `xml:"line-rate,attr"`
+	BranchRate string           `xml:"branch-rate,attr"`
+	Complexity int              `xml:"complexity,attr"`
+	Classes    []coberturaClass `xml:"classes>class"`
+}
+
+type coberturaClass struct {
+	Name       string            `xml:"name,attr"`
+	Filename   string            `xml:"filename,attr"`
+	LineRate   string            `xml:"line-rate,attr"`
+	BranchRate string            `xml:"branch-rate,attr"`
+	Complexity int               `xml:"complexity,attr"`
+	Methods    []coberturaMethod `xml:"methods>method"`
+	Lines      []coberturaLine   `xml:"lines>line"`
+}
+
+type coberturaMethod struct {
+	Name       string          `xml:"name,attr"`
+	Signature  string          `xml:"signature,attr"`
+	LineRate   string          `xml:"line-rate,attr"`
+	BranchRate string          `xml:"branch-rate,attr"`
+	Complexity int             `xml:"complexity,attr"`
+	Lines      []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number            int    `xml:"number,attr"`
+	Hits              int    `xml:"hits,attr"`
+	Branch            bool   `xml:"branch,attr"`
+	ConditionCoverage string `xml:"condition-coverage,attr,omitempty"`
+}
+
+func coberturaRate(covered, valid int) string {
+	if valid == 0 {
+		return "1"
+	}
+	return strconv.FormatFloat(float64(covered)/float64(valid), 'f', -1, 64)
+}
+
+// ExportCobertura writes the report in the Cobertura XML format to the given writer.
+//
+// All locations are reported as classes of a single package,
+// with the location ID as the class name and file name.
+// The output is deterministic: the timestamp is always zero.
+//
+func (r *CoverageReport) ExportCobertura(w io.Writer) error {
+
+	pkg := coberturaPackage{
+		Name: "cadence",
+	}
+
+	var linesCovered, linesValid, branchesCovered, branchesValid int
+
+	for _, locationID := range r.LocationIDs() {
+		coverage := r.Coverage[locationID]
+
+		class, classLinesCovered, classLinesValid, classBranchesCovered, classBranchesValid :=
+			newCoberturaClass(string(locationID), coverage)
+
+		pkg.Classes = append(pkg.Classes, class)
+
+		linesCovered += classLinesCovered
+		linesValid += classLinesValid
+		branchesCovered += classBranchesCovered
+		branchesValid += classBranchesValid
+	}
+
+	lineRate := coberturaRate(linesCovered, linesValid)
+	branchRate := coberturaRate(branchesCovered, branchesValid)
+
+	pkg.LineRate = lineRate
+	pkg.BranchRate = branchRate
+
+	report := coberturaCoverage{
+		LineRate:        lineRate,
+		BranchRate:      branchRate,
+		LinesCovered:    linesCovered,
+		LinesValid:      linesValid,
+		BranchesCovered: branchesCovered,
+		BranchesValid:   branchesValid,
+		Version:         "cadence",
+		Packages:        []coberturaPackage{pkg},
+	}
+
+	_, err := io.WriteString(w, coberturaHeader)
+	if err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(report)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func newCoberturaClass(
+	name string,
+	coverage *LocationCoverage,
+) (
+	class coberturaClass,
+	linesCovered, linesValid, branchesCovered, branchesValid int,
+) {
+	// Group branch outcomes by line
+
+	type lineBranches struct {
+		covered int
+		valid   int
+	}
+
+	branchesByLine := map[int]*lineBranches{}
+
+	for _, branch := range coverage.Branches {
+		branches := branchesByLine[branch.Line]
+		if branches == nil {
+			branches = &lineBranches{}
+			branchesByLine[branch.Line] = branches
+		}
+
+		for _, hits := range branch.Hits {
+			branches.valid++
+			if hits > 0 {
+				branches.covered++
+			}
+		}
+	}
+
+	lines := coverage.Lines()
+
+	class.Lines = make([]coberturaLine, 0, len(lines))
+
+	for _, number := range lines {
+		line := coberturaLine{
+			Number: number,
+			Hits:   coverage.LineHits[number],
+		}
+
+		if branches, ok := branchesByLine[number]; ok {
+			line.Branch = true
+			line.ConditionCoverage = fmt.Sprintf(
+				"%d%% (%d/%d)",
+				branches.covered*100/branches.valid,
+				branches.covered,
+				branches.valid,
+			)
+		}
+
+		class.Lines = append(class.Lines, line)
+	}
+
+	for _, function := range coverage.Functions {
+		functionLineRate := "0"
+		if function.Hits > 0 {
+			functionLineRate = "1"
+		}
+
+		class.Methods = append(class.Methods, coberturaMethod{
+			Name:       function.Name,
+			LineRate:   functionLineRate,
+			BranchRate: "1",
+			Lines: []coberturaLine{
+				{
+					Number: function.Line,
+					Hits:   function.Hits,
+				},
+			},
+		})
+	}
+
+	linesValid = len(lines)
+	linesCovered = coverage.CoveredLines()
+	branchesValid, branchesCovered = coverage.BranchOutcomes()
+
+	class.Name = name
+	class.Filename = name
+	class.LineRate = coberturaRate(linesCovered, linesValid)
+	class.BranchRate = coberturaRate(branchesCovered, branchesValid)
+
+	return
+}
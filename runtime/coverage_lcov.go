@@ -0,0 +1,90 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// ExportLCOV writes the report in the LCOV tracefile format to the given writer.
+//
+// Each location is reported as a separate source file record,
+// with the location ID as the source file name.
+//
+func (r *CoverageReport) ExportLCOV(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+
+	for _, locationID := range r.LocationIDs() {
+		writeLCOVRecord(writer, string(locationID), r.Coverage[locationID])
+	}
+
+	return writer.Flush()
+}
+
+func writeLCOVRecord(w *bufio.Writer, sourceFile string, coverage *LocationCoverage) {
+
+	// NOTE: errors are reported by the final flush
+
+	_, _ = fmt.Fprintf(w, "TN:\nSF:%s\n", sourceFile)
+
+	// Functions
+
+	for _, function := range coverage.Functions {
+		_, _ = fmt.Fprintf(w, "FN:%d,%s\n", function.Line, function.Name)
+	}
+	for _, function := range coverage.Functions {
+		_, _ = fmt.Fprintf(w, "FNDA:%d,%s\n", function.Hits, function.Name)
+	}
+	_, _ = fmt.Fprintf(w, "FNF:%d\nFNH:%d\n", len(coverage.Functions), coverage.CoveredFunctions())
+
+	// Branches.
+	// If the branching element was never reached, the outcomes are reported as "-"
+
+	for block, branch := range coverage.Branches {
+		reached := false
+		for _, hits := range branch.Hits {
+			if hits > 0 {
+				reached = true
+				break
+			}
+		}
+
+		for outcome, hits := range branch.Hits {
+			taken := "-"
+			if reached {
+				taken = fmt.Sprint(hits)
+			}
+			_, _ = fmt.Fprintf(w, "BRDA:%d,%d,%d,%s\n", branch.Line, block, outcome, taken)
+		}
+	}
+	branchesFound, branchesHit := coverage.BranchOutcomes()
+	_, _ = fmt.Fprintf(w, "BRF:%d\nBRH:%d\n", branchesFound, branchesHit)
+
+	// Lines
+
+	lines := coverage.Lines()
+	for _, line := range lines {
+		_, _ = fmt.Fprintf(w, "DA:%d,%d\n", line, coverage.LineHits[line])
+	}
+	_, _ = fmt.Fprintf(w, "LF:%d\nLH:%d\n", len(lines), coverage.CoveredLines())
+
+	_, _ = w.WriteString("end_of_record\n")
+}
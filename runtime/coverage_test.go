@@ -20,7 +20,9 @@ package runtime
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -101,14 +103,26 @@ func TestRuntimeCoverage(t *testing.T) {
                 "4": 1,
                 "5": 42,
                 "7": 1
-              }
+              },
+              "coverable_lines": [3, 4, 5, 7],
+              "functions": [
+                {"name": "answer", "line": 2, "hits": 1}
+              ],
+              "branches": []
             },
             "t.00": {
               "line_hits": {
                 "5": 1,
                 "6": 1,
                 "9": 1
-              }
+              },
+              "coverable_lines": [5, 6, 7, 9],
+              "functions": [
+                {"name": "main", "line": 4, "hits": 1}
+              ],
+              "branches": [
+                {"kind": "if", "line": 6, "column": 10, "hits": [0, 1]}
+              ]
             }
           }
         }
@@ -116,3 +130,206 @@ func TestRuntimeCoverage(t *testing.T) {
 		string(actual),
 	)
 }
+
+func TestRuntimeCoverageFunctionsAndBranches(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	script := []byte(`
+      pub struct S {
+        init() {}
+
+        pub fun classify(_ x: Int): String {
+          switch x {
+            case 1:
+              return "one"
+            case 2:
+              return "two"
+          }
+          return "many"
+        }
+      }
+
+      pub fun main(): Int {
+        let s = S()
+        let f = fun (_ x: Int?): Int {
+          return x ?? 0
+        }
+        var n = f(nil) + f(1)
+        n = n > 0 ? n : 0
+        let one = s.classify(1)
+        let many = s.classify(3)
+        return n
+      }
+    `)
+
+	coverageReport := NewCoverageReport()
+
+	runtime.SetCoverageReport(coverageReport)
+
+	value, err := runtime.ExecuteScript(
+		Script{
+			Source: script,
+		},
+		Context{
+			Interface: &testRuntimeInterface{},
+			Location:  common.StringLocation("test"),
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, cadence.NewInt(1), value)
+
+	t.Run("JSON", func(t *testing.T) {
+
+		t.Parallel()
+
+		var builder strings.Builder
+		err := coverageReport.ExportJSON(&builder)
+		require.NoError(t, err)
+
+		require.JSONEq(t,
+			`
+            {
+              "coverage": {
+                "S.test": {
+                  "line_hits": {
+                    "6": 2,
+                    "8": 1,
+                    "12": 1,
+                    "17": 1,
+                    "18": 1,
+                    "19": 2,
+                    "21": 1,
+                    "22": 1,
+                    "23": 1,
+                    "24": 1,
+                    "25": 1
+                  },
+                  "coverable_lines": [6, 8, 10, 12, 17, 18, 19, 21, 22, 23, 24, 25],
+                  "functions": [
+                    {"name": "S.init", "line": 3, "hits": 1},
+                    {"name": "S.classify", "line": 5, "hits": 2},
+                    {"name": "main", "line": 16, "hits": 1},
+                    {"name": "<anonymous 18:16>", "line": 18, "hits": 2}
+                  ],
+                  "branches": [
+                    {"kind": "switch", "line": 6, "column": 10, "hits": [1, 0, 1]},
+                    {"kind": "nil-coalescing", "line": 19, "column": 17, "hits": [1, 1]},
+                    {"kind": "conditional", "line": 22, "column": 12, "hits": [1, 0]}
+                  ]
+                }
+              }
+            }
+            `,
+			builder.String(),
+		)
+	})
+
+	t.Run("LCOV", func(t *testing.T) {
+
+		t.Parallel()
+
+		var builder strings.Builder
+		err := coverageReport.ExportLCOV(&builder)
+		require.NoError(t, err)
+
+		require.Equal(t,
+			`TN:
+SF:S.test
+FN:3,S.init
+FN:5,S.classify
+FN:16,main
+FN:18,<anonymous 18:16>
+FNDA:1,S.init
+FNDA:2,S.classify
+FNDA:1,main
+FNDA:2,<anonymous 18:16>
+FNF:4
+FNH:4
+BRDA:6,0,0,1
+BRDA:6,0,1,0
+BRDA:6,0,2,1
+BRDA:19,1,0,1
+BRDA:19,1,1,1
+BRDA:22,2,0,1
+BRDA:22,2,1,0
+BRF:7
+BRH:5
+DA:6,2
+DA:8,1
+DA:10,0
+DA:12,1
+DA:17,1
+DA:18,1
+DA:19,2
+DA:21,1
+DA:22,1
+DA:23,1
+DA:24,1
+DA:25,1
+LF:12
+LH:11
+end_of_record
+`,
+			builder.String(),
+		)
+	})
+
+	t.Run("Cobertura", func(t *testing.T) {
+
+		t.Parallel()
+
+		var builder strings.Builder
+		err := coverageReport.ExportCobertura(&builder)
+		require.NoError(t, err)
+
+		output := builder.String()
+
+		require.True(t, strings.HasPrefix(output, coberturaHeader))
+
+		var report coberturaCoverage
+		err = xml.Unmarshal([]byte(output), &report)
+		require.NoError(t, err)
+
+		assert.Equal(t, 11, report.LinesCovered)
+		assert.Equal(t, 12, report.LinesValid)
+		assert.Equal(t, 5, report.BranchesCovered)
+		assert.Equal(t, 7, report.BranchesValid)
+
+		require.Len(t, report.Packages, 1)
+		require.Len(t, report.Packages[0].Classes, 1)
+
+		class := report.Packages[0].Classes[0]
+		assert.Equal(t, "S.test", class.Filename)
+
+		methodNames := make([]string, 0, len(class.Methods))
+		for _, method := range class.Methods {
+			methodNames = append(methodNames, method.Name)
+		}
+		assert.Equal(t,
+			[]string{"S.init", "S.classify", "main", "<anonymous 18:16>"},
+			methodNames,
+		)
+
+		require.Len(t, class.Lines, 12)
+		assert.Equal(t,
+			coberturaLine{
+				Number:            6,
+				Hits:              2,
+				Branch:            true,
+				ConditionCoverage: "66% (2/3)",
+			},
+			class.Lines[0],
+		)
+		assert.Equal(t,
+			coberturaLine{
+				Number: 10,
+				Hits:   0,
+			},
+			class.Lines[2],
+		)
+	})
+}
@@ -22,11 +22,14 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/cadence"
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/parser2"
 )
 
 func TestRuntimeDebugger(t *testing.T) {
@@ -115,3 +118,229 @@ func TestRuntimeDebugger(t *testing.T) {
 
 	require.True(t, logged)
 }
+
+const debuggerTestScript = `
+  pub fun double(_ x: Int): Int {
+      let doubled = x * 2
+      return doubled
+  }
+
+  pub fun main(): Int {
+      var sum = 0
+      var i = 0
+      while i < 3 {
+          sum = sum + double(i)
+          i = i + 1
+      }
+      return sum
+  }
+`
+
+// executeDebuggedScript executes the given script in a goroutine, using the given debugger,
+// and returns a function which waits for the execution to finish
+//
+func executeDebuggedScript(
+	t *testing.T,
+	debugger *interpreter.Debugger,
+	location common.Location,
+	code string,
+) (
+	wait func() cadence.Value,
+) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	var result cadence.Value
+
+	go func() {
+		defer wg.Done()
+
+		runtime := newTestInterpreterRuntime()
+		runtime.SetDebugger(debugger)
+
+		runtimeInterface := &testRuntimeInterface{
+			storage: newTestLedger(nil, nil),
+		}
+
+		var err error
+		result, err = runtime.ExecuteScript(
+			Script{
+				Source: []byte(code),
+			},
+			Context{
+				Interface: runtimeInterface,
+				Location:  location,
+			},
+		)
+		require.NoError(t, err)
+	}()
+
+	return func() cadence.Value {
+		wg.Wait()
+		return result
+	}
+}
+
+func requireStopLine(t *testing.T, stop interpreter.Stop, line int) {
+	require.Equal(t, line, stop.Statement.StartPosition().Line)
+}
+
+func parseDebuggerExpression(t *testing.T, code string) ast.Expression {
+	expression, errs := parser2.ParseExpression(code, nil)
+	require.Empty(t, errs)
+	return expression
+}
+
+func requireEvaluation(t *testing.T, debugger *interpreter.Debugger, stop interpreter.Stop, code string, expected int64) {
+	value, err := debugger.Evaluate(stop.Interpreter, parseDebuggerExpression(t, code))
+	require.NoError(t, err)
+	require.Equal(t,
+		interpreter.NewUnmeteredIntValueFromInt64(expected),
+		value,
+	)
+}
+
+func TestRuntimeDebuggerBreakpoints(t *testing.T) {
+
+	t.Parallel()
+
+	location := common.ScriptLocation{0x1}
+
+	debugger := interpreter.NewDebugger()
+	breakpoint := debugger.AddBreakpoint(location, 3)
+
+	wait := executeDebuggedScript(t, debugger, location, debuggerTestScript)
+
+	for i := 0; i < 3; i++ {
+		stop := <-debugger.Stops()
+
+		requireStopLine(t, stop, 3)
+		require.Same(t, breakpoint, stop.Breakpoint)
+
+		requireEvaluation(t, debugger, stop, "x", int64(i))
+
+		debugger.Continue()
+	}
+
+	assert.Equal(t, cadence.NewInt(6), wait())
+}
+
+func TestRuntimeDebuggerConditionalBreakpoint(t *testing.T) {
+
+	t.Parallel()
+
+	location := common.ScriptLocation{0x1}
+
+	debugger := interpreter.NewDebugger()
+	debugger.AddConditionalBreakpoint(location, 11, parseDebuggerExpression(t, "i == 2"))
+
+	wait := executeDebuggedScript(t, debugger, location, debuggerTestScript)
+
+	stop := <-debugger.Stops()
+
+	requireStopLine(t, stop, 11)
+	require.NotNil(t, stop.Breakpoint)
+
+	requireEvaluation(t, debugger, stop, "i", 2)
+	requireEvaluation(t, debugger, stop, "sum", 2)
+
+	debugger.Continue()
+
+	assert.Equal(t, cadence.NewInt(6), wait())
+}
+
+func TestRuntimeDebuggerStepping(t *testing.T) {
+
+	t.Parallel()
+
+	location := common.ScriptLocation{0x1}
+
+	debugger := interpreter.NewDebugger()
+	debugger.AddConditionalBreakpoint(location, 11, parseDebuggerExpression(t, "i == 1"))
+
+	wait := executeDebuggedScript(t, debugger, location, debuggerTestScript)
+
+	stop := <-debugger.Stops()
+	requireStopLine(t, stop, 11)
+
+	// Step into the invoked function
+
+	stop = debugger.StepIn()
+	requireStopLine(t, stop, 3)
+	require.Nil(t, stop.Breakpoint)
+	requireEvaluation(t, debugger, stop, "x", 1)
+
+	stop = debugger.StepOver()
+	requireStopLine(t, stop, 4)
+	requireEvaluation(t, debugger, stop, "doubled", 2)
+
+	// Step out of the invoked function
+
+	stop = debugger.StepOut()
+	requireStopLine(t, stop, 12)
+	requireEvaluation(t, debugger, stop, "sum", 2)
+
+	// Step over the invocation in the next iteration
+
+	stop = debugger.StepOver()
+	requireStopLine(t, stop, 11)
+
+	stop = debugger.StepOver()
+	requireStopLine(t, stop, 12)
+	requireEvaluation(t, debugger, stop, "sum", 6)
+
+	stop = debugger.StepOver()
+	requireStopLine(t, stop, 14)
+
+	debugger.Continue()
+
+	assert.Equal(t, cadence.NewInt(6), wait())
+}
+
+func TestRuntimeDebuggerEvaluate(t *testing.T) {
+
+	t.Parallel()
+
+	location := common.ScriptLocation{0x1}
+
+	debugger := interpreter.NewDebugger()
+	debugger.AddBreakpoint(location, 14)
+
+	// A breakpoint in a function invoked by an evaluated expression is ignored
+	debugger.AddBreakpoint(location, 3)
+
+	debugger.AddBreakpoint(location, 4)
+	require.True(t, debugger.RemoveBreakpoint(location, 4))
+	require.False(t, debugger.RemoveBreakpoint(location, 4))
+
+	require.Len(t, debugger.Breakpoints(), 2)
+
+	wait := executeDebuggedScript(t, debugger, location, debuggerTestScript)
+
+	// The breakpoint in the function is hit three times
+
+	for i := 0; i < 3; i++ {
+		stop := <-debugger.Stops()
+		requireStopLine(t, stop, 3)
+		debugger.Continue()
+	}
+
+	stop := <-debugger.Stops()
+	requireStopLine(t, stop, 14)
+
+	requireEvaluation(t, debugger, stop, "double(sum) + i", 15)
+
+	value, err := debugger.Evaluate(stop.Interpreter, parseDebuggerExpression(t, "[sum, i]"))
+	require.NoError(t, err)
+	require.Equal(t, "[6, 3]", value.String())
+
+	_, err = debugger.Evaluate(stop.Interpreter, parseDebuggerExpression(t, "unknown"))
+	require.Error(t, err)
+
+	_, err = debugger.Evaluate(stop.Interpreter, parseDebuggerExpression(t, "sum + true"))
+	require.Error(t, err)
+
+	debugger.Continue()
+
+	assert.Equal(t, cadence.NewInt(6), wait())
+}
@@ -373,6 +373,67 @@ func (e *ExtraneousFieldError) Error() string {
 	)
 }
 
+// ImplicitFieldRemovalError is reported during a contract update, when an updated composite
+// declaration removes a field of the existing declaration without the `#removedField` pragma.
+type ImplicitFieldRemovalError struct {
+	DeclName  string
+	FieldName string
+	ast.Range
+}
+
+func (e *ImplicitFieldRemovalError) Error() string {
+	return fmt.Sprintf("missing field `%s` in `%s`",
+		e.FieldName,
+		e.DeclName,
+	)
+}
+
+func (e *ImplicitFieldRemovalError) SecondaryError() string {
+	return fmt.Sprintf(
+		"fields can only be removed using the pragma `#%s(\"%s\")`",
+		sema.RemovedFieldPragmaIdentifier,
+		e.FieldName,
+	)
+}
+
+// MissingRemovedFieldError is reported during a contract update, when an updated composite
+// declaration is missing the `#removedField` pragma of an existing declaration.
+type MissingRemovedFieldError struct {
+	DeclName  string
+	FieldName string
+	ast.Range
+}
+
+func (e *MissingRemovedFieldError) Error() string {
+	return fmt.Sprintf("missing removed field `%s` in `%s`",
+		e.FieldName,
+		e.DeclName,
+	)
+}
+
+func (e *MissingRemovedFieldError) SecondaryError() string {
+	return "removed fields must stay removed"
+}
+
+// ResourceFieldRemovalError is reported during a contract update, when an updated composite
+// declaration removes a resource field using the `#removedField` pragma.
+type ResourceFieldRemovalError struct {
+	DeclName  string
+	FieldName string
+	ast.Range
+}
+
+func (e *ResourceFieldRemovalError) Error() string {
+	return fmt.Sprintf("cannot remove resource field `%s` in `%s`",
+		e.FieldName,
+		e.DeclName,
+	)
+}
+
+func (e *ResourceFieldRemovalError) SecondaryError() string {
+	return "the stored resources would be lost"
+}
+
 // ContractNotFoundError is reported during a contract update, if no contract can be
 // found in the program.
 type ContractNotFoundError struct {
@@ -407,6 +468,21 @@ func (e *ConformanceMismatchError) Error() string {
 	return fmt.Sprintf("conformances does not match in `%s`", e.DeclName)
 }
 
+// TypeParameterMismatchError is reported during a contract update, when the type parameters
+// of a generic composite declaration do not match the existing ones.
+type TypeParameterMismatchError struct {
+	DeclName string
+	ast.Range
+}
+
+func (e *TypeParameterMismatchError) Error() string {
+	return fmt.Sprintf("type parameters do not match in `%s`", e.DeclName)
+}
+
+func (e *TypeParameterMismatchError) SecondaryError() string {
+	return "stored values record their type arguments"
+}
+
 // EnumCaseMismatchError is reported during an enum update, when an updated enum case
 // does not match the existing enum case.
 type EnumCaseMismatchError struct {
@@ -0,0 +1,274 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/stdlib/rlp"
+)
+
+// firstAccountAddress is the address of the first created account.
+// Addresses of further accounts are sequential
+const firstAccountAddress = 1
+
+type account struct {
+	keys      []*runtime.AccountKey
+	contracts map[string][]byte
+	balance   uint64
+}
+
+// account returns the account with the given address.
+// Accounts which were not created are treated as empty accounts
+func (i *Interface) account(address common.Address) *account {
+	a, ok := i.accounts[address]
+	if !ok {
+		a = &account{
+			contracts: map[string][]byte{},
+		}
+		i.accounts[address] = a
+	}
+	return a
+}
+
+func (i *Interface) CreateAccount(_ runtime.Address) (address runtime.Address, err error) {
+	binary.BigEndian.PutUint64(address[:], firstAccountAddress+i.accountCount)
+	i.accountCount++
+	i.account(address)
+	return address, nil
+}
+
+// Accounts returns the addresses of all created accounts, in creation order
+func (i *Interface) Accounts() []common.Address {
+	addresses := make([]common.Address, i.accountCount)
+	for index := range addresses {
+		binary.BigEndian.PutUint64(addresses[index][:], firstAccountAddress+uint64(index))
+	}
+	return addresses
+}
+
+// SetAccountBalance sets the balance of the account with the given address
+func (i *Interface) SetAccountBalance(address common.Address, balance uint64) {
+	i.account(address).balance = balance
+}
+
+func (i *Interface) GetAccountBalance(address common.Address) (value uint64, err error) {
+	return i.account(address).balance, nil
+}
+
+func (i *Interface) GetAccountAvailableBalance(address common.Address) (value uint64, err error) {
+	return i.account(address).balance, nil
+}
+
+func (i *Interface) GetStorageUsed(address runtime.Address) (value uint64, err error) {
+	return i.StorageUsed(address[:]), nil
+}
+
+func (i *Interface) GetStorageCapacity(_ runtime.Address) (value uint64, err error) {
+	return i.storageCapacity, nil
+}
+
+// Keys
+
+func (i *Interface) AddAccountKey(
+	address runtime.Address,
+	publicKey *runtime.PublicKey,
+	hashAlgo runtime.HashAlgorithm,
+	weight int,
+) (*runtime.AccountKey, error) {
+	a := i.account(address)
+
+	key := &runtime.AccountKey{
+		KeyIndex:  len(a.keys),
+		PublicKey: publicKey,
+		HashAlgo:  hashAlgo,
+		Weight:    weight,
+	}
+	a.keys = append(a.keys, key)
+
+	return key, nil
+}
+
+// GetAccountKey returns the key at the given index, or nil if there is none
+func (i *Interface) GetAccountKey(address runtime.Address, index int) (*runtime.AccountKey, error) {
+	keys := i.account(address).keys
+	if index < 0 || index >= len(keys) {
+		return nil, nil
+	}
+	key := *keys[index]
+	return &key, nil
+}
+
+// RevokeAccountKey marks the key at the given index revoked, and returns it.
+// Returns nil if there is no key at the given index
+func (i *Interface) RevokeAccountKey(address runtime.Address, index int) (*runtime.AccountKey, error) {
+	keys := i.account(address).keys
+	if index < 0 || index >= len(keys) {
+		return nil, nil
+	}
+	keys[index].IsRevoked = true
+	key := *keys[index]
+	return &key, nil
+}
+
+// AddEncodedAccountKey adds the given encoded key.
+//
+// An encoded key is an RLP list of the public key, the signature algorithm,
+// the hash algorithm, and the weight. The algorithms are given as their raw values
+func (i *Interface) AddEncodedAccountKey(address runtime.Address, encodedKey []byte) error {
+	items, _, err := rlp.DecodeList(encodedKey, 0)
+	if err != nil {
+		return fmt.Errorf("invalid encoded account key: %w", err)
+	}
+
+	if len(items) != 4 {
+		return fmt.Errorf("invalid encoded account key: expected 4 items, got %d", len(items))
+	}
+
+	fields := make([][]byte, len(items))
+	for index, item := range items {
+		fields[index], _, err = rlp.DecodeString(item, 0)
+		if err != nil {
+			return fmt.Errorf("invalid encoded account key: %w", err)
+		}
+	}
+
+	signAlgo := new(big.Int).SetBytes(fields[1])
+	hashAlgo := new(big.Int).SetBytes(fields[2])
+	weight := new(big.Int).SetBytes(fields[3])
+
+	if !signAlgo.IsUint64() || signAlgo.Uint64() > 255 ||
+		!hashAlgo.IsUint64() || hashAlgo.Uint64() > 255 ||
+		!weight.IsInt64() {
+
+		return fmt.Errorf("invalid encoded account key: invalid field")
+	}
+
+	_, err = i.AddAccountKey(
+		address,
+		&runtime.PublicKey{
+			PublicKey: fields[0],
+			SignAlgo:  runtime.SignatureAlgorithm(signAlgo.Uint64()),
+		},
+		runtime.HashAlgorithm(hashAlgo.Uint64()),
+		int(weight.Int64()),
+	)
+	return err
+}
+
+// RevokeEncodedAccountKey marks the key at the given index revoked, and returns it encoded.
+// Returns nil if there is no key at the given index
+func (i *Interface) RevokeEncodedAccountKey(address runtime.Address, index int) (encodedKey []byte, err error) {
+	key, err := i.RevokeAccountKey(address, index)
+	if err != nil || key == nil {
+		return nil, err
+	}
+
+	return EncodeAccountKey(key), nil
+}
+
+// EncodeAccountKey returns the encoding of the given account key,
+// as expected by AddEncodedAccountKey
+func EncodeAccountKey(key *runtime.AccountKey) []byte {
+	return encodeRLPList(
+		encodeRLPString(key.PublicKey.PublicKey),
+		encodeRLPUint(uint64(key.PublicKey.SignAlgo)),
+		encodeRLPUint(uint64(key.HashAlgo)),
+		encodeRLPUint(uint64(key.Weight)),
+	)
+}
+
+func encodeRLPUint(value uint64) []byte {
+	var buffer [8]byte
+	binary.BigEndian.PutUint64(buffer[:], value)
+
+	// Integers are encoded as strings of their big-endian representation, without leading zeros
+	start := 0
+	for start < len(buffer) && buffer[start] == 0 {
+		start++
+	}
+	return encodeRLPString(buffer[start:])
+}
+
+func encodeRLPString(value []byte) []byte {
+	if len(value) == 1 && value[0] <= rlp.ByteRangeEnd {
+		return []byte{value[0]}
+	}
+	return append(encodeRLPLength(len(value), rlp.ShortStringRangeStart), value...)
+}
+
+func encodeRLPList(items ...[]byte) []byte {
+	var payload []byte
+	for _, item := range items {
+		payload = append(payload, item...)
+	}
+	return append(encodeRLPLength(len(payload), rlp.ShortListRangeStart), payload...)
+}
+
+func encodeRLPLength(length int, offset byte) []byte {
+	if length <= rlp.MaxShortLengthAllowed {
+		return []byte{offset + byte(length)}
+	}
+
+	var buffer [8]byte
+	binary.BigEndian.PutUint64(buffer[:], uint64(length))
+	start := 0
+	for buffer[start] == 0 {
+		start++
+	}
+	lengthBytes := buffer[start:]
+
+	// Long forms start after the short forms
+	prefix := offset + rlp.MaxShortLengthAllowed + byte(len(lengthBytes))
+	return append([]byte{prefix}, lengthBytes...)
+}
+
+// Contracts
+
+func (i *Interface) UpdateAccountContractCode(address runtime.Address, name string, code []byte) (err error) {
+	i.account(address).contracts[name] = code
+	return nil
+}
+
+func (i *Interface) GetAccountContractCode(address runtime.Address, name string) (code []byte, err error) {
+	return i.account(address).contracts[name], nil
+}
+
+func (i *Interface) RemoveAccountContractCode(address runtime.Address, name string) (err error) {
+	delete(i.account(address).contracts, name)
+	return nil
+}
+
+// GetAccountContractNames returns the names of the contracts of the account, sorted
+func (i *Interface) GetAccountContractNames(address runtime.Address) ([]string, error) {
+	contracts := i.account(address).contracts
+
+	names := make([]string, 0, len(contracts))
+	for name := range contracts { //nolint:maprangecheck
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
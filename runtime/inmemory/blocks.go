@@ -0,0 +1,72 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+
+	"github.com/onflow/cadence/runtime"
+)
+
+func newBlock(height uint64, timestamp time.Time, parentHash runtime.BlockHash) runtime.Block {
+	var encodedHeight [8]byte
+	binary.BigEndian.PutUint64(encodedHeight[:], height)
+
+	hash := sha256.New()
+	hash.Write(parentHash[:])
+	hash.Write(encodedHeight[:])
+
+	var blockHash runtime.BlockHash
+	copy(blockHash[:], hash.Sum(nil))
+
+	return runtime.Block{
+		Height:    height,
+		View:      height,
+		Hash:      blockHash,
+		Timestamp: timestamp.UnixNano(),
+	}
+}
+
+// CurrentBlock returns the latest committed block
+func (i *Interface) CurrentBlock() runtime.Block {
+	return i.blocks[len(i.blocks)-1]
+}
+
+// CommitBlock adds a new block with the given timestamp, and returns it.
+// The height of the new block is the height of the current block plus one
+func (i *Interface) CommitBlock(timestamp time.Time) runtime.Block {
+	current := i.CurrentBlock()
+	block := newBlock(current.Height+1, timestamp, current.Hash)
+	i.blocks = append(i.blocks, block)
+	return block
+}
+
+func (i *Interface) GetCurrentBlockHeight() (uint64, error) {
+	return i.CurrentBlock().Height, nil
+}
+
+func (i *Interface) GetBlockAtHeight(height uint64) (block runtime.Block, exists bool, err error) {
+	first := i.blocks[0].Height
+	if height < first || height-first >= uint64(len(i.blocks)) {
+		return runtime.Block{}, false, nil
+	}
+	return i.blocks[height-first], true, nil
+}
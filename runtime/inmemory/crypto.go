@@ -0,0 +1,183 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"math/big"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/onflow/cadence/runtime"
+)
+
+// tagLength is the length to which non-empty domain separation tags are padded
+const tagLength = 32
+
+// ecdsaKeyLength is the length of an encoded ECDSA public key:
+// The X and Y coordinates, each 32 bytes, big-endian
+const ecdsaKeyLength = 64
+
+// ecdsaSignatureLength is the length of an encoded ECDSA signature:
+// The values r and s, each 32 bytes, big-endian
+const ecdsaSignatureLength = 64
+
+func newHasher(hashAlgorithm runtime.HashAlgorithm) (hash.Hash, error) {
+	switch hashAlgorithm {
+	case runtime.HashAlgorithmSHA2_256:
+		return sha256.New(), nil
+	case runtime.HashAlgorithmSHA2_384:
+		return sha512.New384(), nil
+	case runtime.HashAlgorithmSHA3_256:
+		return sha3.New256(), nil
+	case runtime.HashAlgorithmSHA3_384:
+		return sha3.New384(), nil
+	case runtime.HashAlgorithmKECCAK_256:
+		return sha3.NewLegacyKeccak256(), nil
+	default:
+		return nil, fmt.Errorf("hash algorithm %s: %w", hashAlgorithm, ErrNotSupported)
+	}
+}
+
+// Hash hashes the given data using the given algorithm.
+//
+// A non-empty tag is right-padded with zeros to 32 bytes and prefixed to the data
+func (i *Interface) Hash(data []byte, tag string, hashAlgorithm runtime.HashAlgorithm) ([]byte, error) {
+	hasher, err := newHasher(hashAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	if tag != "" {
+		if len(tag) > tagLength {
+			return nil, fmt.Errorf("tag is longer than %d bytes", tagLength)
+		}
+		var paddedTag [tagLength]byte
+		copy(paddedTag[:], tag)
+		hasher.Write(paddedTag[:])
+	}
+
+	hasher.Write(data)
+
+	return hasher.Sum(nil), nil
+}
+
+// VerifySignature verifies the given ECDSA signature of the hash of the given data.
+//
+// Signatures are the concatenation of r and s, public keys are the concatenation of X and Y.
+// BLS signatures are not supported
+func (i *Interface) VerifySignature(
+	signature []byte,
+	tag string,
+	signedData []byte,
+	publicKey []byte,
+	signatureAlgorithm runtime.SignatureAlgorithm,
+	hashAlgorithm runtime.HashAlgorithm,
+) (bool, error) {
+
+	x, y, err := decodeECDSAPublicKey(publicKey, signatureAlgorithm)
+	if err != nil {
+		return false, err
+	}
+
+	if len(signature) != ecdsaSignatureLength {
+		return false, nil
+	}
+
+	digest, err := i.Hash(signedData, tag, hashAlgorithm)
+	if err != nil {
+		return false, err
+	}
+
+	r := new(big.Int).SetBytes(signature[:ecdsaSignatureLength/2])
+	s := new(big.Int).SetBytes(signature[ecdsaSignatureLength/2:])
+
+	switch signatureAlgorithm {
+	case runtime.SignatureAlgorithmECDSA_P256:
+		key := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     x,
+			Y:     y,
+		}
+		return ecdsa.Verify(key, digest, r, s), nil
+
+	case runtime.SignatureAlgorithmECDSA_secp256k1:
+		return secp256k1Verify(x, y, digest, r, s), nil
+	}
+
+	panic("unreachable")
+}
+
+func (i *Interface) ValidatePublicKey(key *runtime.PublicKey) error {
+	_, _, err := decodeECDSAPublicKey(key.PublicKey, key.SignAlgo)
+	return err
+}
+
+// decodeECDSAPublicKey decodes the given public key,
+// and checks that it is a point on the curve of the given algorithm
+func decodeECDSAPublicKey(
+	publicKey []byte,
+	signatureAlgorithm runtime.SignatureAlgorithm,
+) (x, y *big.Int, err error) {
+
+	var onCurve func(x, y *big.Int) bool
+
+	switch signatureAlgorithm {
+	case runtime.SignatureAlgorithmECDSA_P256:
+		onCurve = elliptic.P256().IsOnCurve
+	case runtime.SignatureAlgorithmECDSA_secp256k1:
+		onCurve = secp256k1IsOnCurve
+	default:
+		return nil, nil, fmt.Errorf("signature algorithm %s: %w", signatureAlgorithm, ErrNotSupported)
+	}
+
+	if len(publicKey) != ecdsaKeyLength {
+		return nil, nil, fmt.Errorf(
+			"invalid public key: expected %d bytes, got %d",
+			ecdsaKeyLength,
+			len(publicKey),
+		)
+	}
+
+	x = new(big.Int).SetBytes(publicKey[:ecdsaKeyLength/2])
+	y = new(big.Int).SetBytes(publicKey[ecdsaKeyLength/2:])
+
+	if !onCurve(x, y) {
+		return nil, nil, fmt.Errorf("invalid public key: not a point on the curve")
+	}
+
+	return x, y, nil
+}
+
+func (i *Interface) BLSVerifyPOP(_ *runtime.PublicKey, _ []byte) (bool, error) {
+	return false, ErrNotSupported
+}
+
+func (i *Interface) BLSAggregateSignatures(_ [][]byte) ([]byte, error) {
+	return nil, ErrNotSupported
+}
+
+func (i *Interface) BLSAggregatePublicKeys(_ []*runtime.PublicKey) (*runtime.PublicKey, error) {
+	return nil, ErrNotSupported
+}
@@ -0,0 +1,212 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime"
+)
+
+type p256Key struct {
+	privateKey *ecdsa.PrivateKey
+	encoded    []byte
+}
+
+func newP256Key(t *testing.T) p256Key {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	return p256Key{
+		privateKey: privateKey,
+		encoded:    encodePoint(privateKey.X, privateKey.Y),
+	}
+}
+
+func encodePoint(x, y *big.Int) []byte {
+	encoded := make([]byte, ecdsaKeyLength)
+	x.FillBytes(encoded[:ecdsaKeyLength/2])
+	y.FillBytes(encoded[ecdsaKeyLength/2:])
+	return encoded
+}
+
+func TestInterfaceHash(t *testing.T) {
+
+	t.Parallel()
+
+	inter := NewInterface()
+
+	data := []byte("abc")
+
+	for hashAlgorithm, expected := range map[runtime.HashAlgorithm]string{ //nolint:maprangecheck
+		runtime.HashAlgorithmSHA2_256:   "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		runtime.HashAlgorithmSHA3_256:   "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532",
+		runtime.HashAlgorithmKECCAK_256: "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45",
+	} {
+		hash, err := inter.Hash(data, "", hashAlgorithm)
+		require.NoError(t, err)
+		assert.Equal(t, expected, hex.EncodeToString(hash), hashAlgorithm)
+	}
+
+	// The tag is padded and prefixed
+
+	tagged, err := inter.Hash(data, "tag", runtime.HashAlgorithmSHA2_256)
+	require.NoError(t, err)
+
+	padded := make([]byte, tagLength)
+	copy(padded, "tag")
+	expected, err := inter.Hash(append(padded, data...), "", runtime.HashAlgorithmSHA2_256)
+	require.NoError(t, err)
+	assert.Equal(t, expected, tagged)
+
+	_, err = inter.Hash(data, "", runtime.HashAlgorithmKMAC128_BLS_BLS12_381)
+	require.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestInterfaceVerifyP256Signature(t *testing.T) {
+
+	t.Parallel()
+
+	inter := NewInterface()
+
+	key := newP256Key(t)
+	data := []byte("message")
+
+	digest, err := inter.Hash(data, "tag", runtime.HashAlgorithmSHA3_256)
+	require.NoError(t, err)
+
+	r, s, err := ecdsa.Sign(rand.Reader, key.privateKey, digest)
+	require.NoError(t, err)
+
+	signature := encodePoint(r, s)
+
+	valid, err := inter.VerifySignature(
+		signature,
+		"tag",
+		data,
+		key.encoded,
+		runtime.SignatureAlgorithmECDSA_P256,
+		runtime.HashAlgorithmSHA3_256,
+	)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = inter.VerifySignature(
+		signature,
+		"other",
+		data,
+		key.encoded,
+		runtime.SignatureAlgorithmECDSA_P256,
+		runtime.HashAlgorithmSHA3_256,
+	)
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	err = inter.ValidatePublicKey(&runtime.PublicKey{
+		PublicKey: key.encoded,
+		SignAlgo:  runtime.SignatureAlgorithmECDSA_P256,
+	})
+	require.NoError(t, err)
+
+	err = inter.ValidatePublicKey(&runtime.PublicKey{
+		PublicKey: make([]byte, ecdsaKeyLength),
+		SignAlgo:  runtime.SignatureAlgorithmECDSA_P256,
+	})
+	require.Error(t, err)
+
+	err = inter.ValidatePublicKey(&runtime.PublicKey{
+		PublicKey: key.encoded,
+		SignAlgo:  runtime.SignatureAlgorithmBLS_BLS12_381,
+	})
+	require.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestSecp256k1(t *testing.T) {
+
+	t.Parallel()
+
+	assert.True(t, secp256k1IsOnCurve(secp256k1Gx, secp256k1Gy))
+
+	// The order of the generator is n
+
+	x, y := secp256k1ScalarMult(secp256k1Gx, secp256k1Gy, secp256k1N)
+	assert.Nil(t, x)
+	assert.Nil(t, y)
+
+	// 2G, see https://en.bitcoin.it/wiki/Secp256k1
+
+	x, _ = secp256k1ScalarMult(secp256k1Gx, secp256k1Gy, big.NewInt(2))
+	assert.Equal(t,
+		"c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5",
+		hex.EncodeToString(x.Bytes()),
+	)
+
+	// Sign using the textbook algorithm, and verify
+
+	privateKey := big.NewInt(0x1234567890)
+	publicX, publicY := secp256k1ScalarMult(secp256k1Gx, secp256k1Gy, privateKey)
+
+	inter := NewInterface()
+	data := []byte("message")
+
+	digest, err := inter.Hash(data, "", runtime.HashAlgorithmKECCAK_256)
+	require.NoError(t, err)
+
+	nonce := big.NewInt(0x42424242)
+	r, _ := secp256k1ScalarMult(secp256k1Gx, secp256k1Gy, nonce)
+	r.Mod(r, secp256k1N)
+
+	// s = k⁻¹ (e + r·d) mod n
+	s := new(big.Int).Mul(r, privateKey)
+	s.Add(s, secp256k1HashToInt(digest))
+	s.Mul(s, new(big.Int).ModInverse(nonce, secp256k1N))
+	s.Mod(s, secp256k1N)
+
+	publicKey := encodePoint(publicX, publicY)
+
+	valid, err := inter.VerifySignature(
+		encodePoint(r, s),
+		"",
+		data,
+		publicKey,
+		runtime.SignatureAlgorithmECDSA_secp256k1,
+		runtime.HashAlgorithmKECCAK_256,
+	)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = inter.VerifySignature(
+		encodePoint(r, s),
+		"",
+		[]byte("other message"),
+		publicKey,
+		runtime.SignatureAlgorithmECDSA_secp256k1,
+		runtime.HashAlgorithmKECCAK_256,
+	)
+	require.NoError(t, err)
+	assert.False(t, valid)
+}
@@ -0,0 +1,261 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// ErrNotSupported is returned for functionality which is not supported by the in-memory interface,
+// e.g. BLS signatures
+var ErrNotSupported = errors.New("not supported by the in-memory interface")
+
+// DefaultStorageCapacity is the storage capacity of each account, in bytes,
+// unless configured otherwise using WithStorageCapacity
+const DefaultStorageCapacity uint64 = 100_000_000
+
+// Interface is a fully functional runtime.Interface which keeps all state in memory:
+// The storage of accounts, the keys and contracts of accounts, blocks, emitted events, and logs.
+//
+// UUIDs and random numbers are generated deterministically.
+// Signatures are verified and data is hashed using the actual algorithms.
+//
+// An Interface is not safe for concurrent use
+type Interface struct {
+	*Ledger
+	accounts        map[common.Address]*account
+	accountCount    uint64
+	storageCapacity uint64
+	signers         []common.Address
+	programs        map[common.LocationID]*interpreter.Program
+	blocks          []runtime.Block
+	events          []cadence.Event
+	logs            []string
+	uuid            uint64
+	random          *rand.Rand
+	onLog           func(message string)
+	onEventEmitted  func(event cadence.Event)
+}
+
+var _ runtime.Interface = &Interface{}
+
+// Option is a function that configures an Interface
+type Option func(*Interface)
+
+// WithGenesisBlock returns an option which sets the first block
+// to have the given height and timestamp.
+// By default, the first block has height 0 and the Unix epoch as its timestamp
+func WithGenesisBlock(height uint64, timestamp time.Time) Option {
+	return func(i *Interface) {
+		i.blocks = []runtime.Block{
+			newBlock(height, timestamp, runtime.BlockHash{}),
+		}
+	}
+}
+
+// WithRandomSeed returns an option which sets the seed of the random number generator
+func WithRandomSeed(seed int64) Option {
+	return func(i *Interface) {
+		i.random = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithStorageCapacity returns an option which sets the storage capacity of all accounts, in bytes
+func WithStorageCapacity(capacity uint64) Option {
+	return func(i *Interface) {
+		i.storageCapacity = capacity
+	}
+}
+
+// WithOnLogHandler returns an option which sets the function
+// that is called when a program logs a message
+func WithOnLogHandler(handler func(message string)) Option {
+	return func(i *Interface) {
+		i.onLog = handler
+	}
+}
+
+// WithOnEventEmittedHandler returns an option which sets the function
+// that is called when a program emits an event
+func WithOnEventEmittedHandler(handler func(event cadence.Event)) Option {
+	return func(i *Interface) {
+		i.onEventEmitted = handler
+	}
+}
+
+func NewInterface(options ...Option) *Interface {
+	i := &Interface{
+		Ledger:          NewLedger(),
+		accounts:        map[common.Address]*account{},
+		storageCapacity: DefaultStorageCapacity,
+		programs:        map[common.LocationID]*interpreter.Program{},
+		blocks: []runtime.Block{
+			newBlock(0, time.Unix(0, 0), runtime.BlockHash{}),
+		},
+		random: rand.New(rand.NewSource(0)),
+	}
+
+	for _, option := range options {
+		option(i)
+	}
+
+	return i
+}
+
+// SetSigningAccounts sets the accounts which sign the next transactions
+func (i *Interface) SetSigningAccounts(signers ...common.Address) {
+	i.signers = signers
+}
+
+func (i *Interface) GetSigningAccounts() ([]runtime.Address, error) {
+	return i.signers, nil
+}
+
+// Events returns all events emitted so far, in emission order
+func (i *Interface) Events() []cadence.Event {
+	return i.events
+}
+
+// Logs returns all messages logged so far, in order
+func (i *Interface) Logs() []string {
+	return i.logs
+}
+
+func (i *Interface) ResolveLocation(identifiers []runtime.Identifier, location runtime.Location) ([]runtime.ResolvedLocation, error) {
+	addressLocation, ok := location.(common.AddressLocation)
+	if !ok {
+		return []runtime.ResolvedLocation{
+			{
+				Location:    location,
+				Identifiers: identifiers,
+			},
+		}, nil
+	}
+
+	// If no specific identifiers are imported, import all contracts of the account
+
+	if len(identifiers) == 0 {
+		names, err := i.GetAccountContractNames(addressLocation.Address)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			identifiers = append(identifiers, runtime.Identifier{Identifier: name})
+		}
+	}
+
+	resolvedLocations := make([]runtime.ResolvedLocation, len(identifiers))
+	for index, identifier := range identifiers {
+		resolvedLocations[index] = runtime.ResolvedLocation{
+			Location: common.AddressLocation{
+				Address: addressLocation.Address,
+				Name:    identifier.Identifier,
+			},
+			Identifiers: []runtime.Identifier{identifier},
+		}
+	}
+	return resolvedLocations, nil
+}
+
+// GetCode returns the code of the contract at the given address location.
+// Code for other locations is not available
+func (i *Interface) GetCode(location runtime.Location) ([]byte, error) {
+	addressLocation, ok := location.(common.AddressLocation)
+	if !ok {
+		return nil, fmt.Errorf("cannot get code for location: %s", location)
+	}
+
+	return i.GetAccountContractCode(addressLocation.Address, addressLocation.Name)
+}
+
+func (i *Interface) GetProgram(location runtime.Location) (*interpreter.Program, error) {
+	return i.programs[location.ID()], nil
+}
+
+func (i *Interface) SetProgram(location runtime.Location, program *interpreter.Program) error {
+	i.programs[location.ID()] = program
+	return nil
+}
+
+func (i *Interface) ProgramLog(message string) error {
+	i.logs = append(i.logs, message)
+	if i.onLog != nil {
+		i.onLog(message)
+	}
+	return nil
+}
+
+func (i *Interface) EmitEvent(event cadence.Event) error {
+	i.events = append(i.events, event)
+	if i.onEventEmitted != nil {
+		i.onEventEmitted(event)
+	}
+	return nil
+}
+
+// GenerateUUID returns the next UUID. UUIDs are sequential, starting at 1
+func (i *Interface) GenerateUUID() (uint64, error) {
+	i.uuid++
+	return i.uuid, nil
+}
+
+// UnsafeRandom returns the next number of the seeded random number generator
+func (i *Interface) UnsafeRandom() (uint64, error) {
+	return i.random.Uint64(), nil
+}
+
+func (i *Interface) MeterComputation(_ common.ComputationKind, _ uint) error {
+	return nil
+}
+
+func (i *Interface) MeterMemory(_ common.MemoryUsage) error {
+	return nil
+}
+
+func (i *Interface) DecodeArgument(argument []byte, _ cadence.Type) (cadence.Value, error) {
+	return jsoncdc.Decode(nil, argument, runtime.ArgumentDecodingOptions()...)
+}
+
+func (i *Interface) ImplementationDebugLog(_ string) error {
+	return nil
+}
+
+func (i *Interface) RecordTrace(_ string, _ common.Location, _ time.Duration, _ []opentracing.LogRecord) {
+	// NO-OP
+}
+
+func (i *Interface) ResourceOwnerChanged(
+	_ *interpreter.Interpreter,
+	_ *interpreter.CompositeValue,
+	_ common.Address,
+	_ common.Address,
+) {
+	// NO-OP
+}
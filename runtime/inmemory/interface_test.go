@@ -0,0 +1,442 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+func executeTransaction(t *testing.T, inter *Interface, code string, arguments ...cadence.Value) error {
+	encodedArguments := make([][]byte, len(arguments))
+	for index, argument := range arguments {
+		var err error
+		encodedArguments[index], err = jsoncdc.Encode(argument)
+		require.NoError(t, err)
+	}
+
+	return runtime.NewInterpreterRuntime().ExecuteTransaction(
+		runtime.Script{
+			Source:    []byte(code),
+			Arguments: encodedArguments,
+		},
+		runtime.Context{
+			Interface: inter,
+			Location:  common.TransactionLocation(code),
+		},
+	)
+}
+
+func executeScript(t *testing.T, inter *Interface, code string) cadence.Value {
+	value, err := runtime.NewInterpreterRuntime().ExecuteScript(
+		runtime.Script{
+			Source: []byte(code),
+		},
+		runtime.Context{
+			Interface: inter,
+			Location:  common.ScriptLocation(code),
+		},
+	)
+	require.NoError(t, err)
+	return value
+}
+
+func TestInterfaceAccountsAndContracts(t *testing.T) {
+
+	t.Parallel()
+
+	inter := NewInterface()
+
+	address, err := inter.CreateAccount(common.Address{})
+	require.NoError(t, err)
+	assert.Equal(t, common.Address{0, 0, 0, 0, 0, 0, 0, 1}, address)
+
+	const contract = `
+      pub contract Counter {
+
+          pub event Incremented(count: Int)
+
+          pub var count: Int
+
+          init() {
+              self.count = 0
+          }
+
+          pub fun increment() {
+              self.count = self.count + 1
+              emit Incremented(count: self.count)
+          }
+      }
+    `
+
+	inter.SetSigningAccounts(address)
+
+	err = executeTransaction(t,
+		inter,
+		`
+          transaction(code: String) {
+              prepare(signer: AuthAccount) {
+                  signer.contracts.add(name: "Counter", code: code.decodeHex())
+              }
+          }
+        `,
+		cadence.String(hex.EncodeToString([]byte(contract))),
+	)
+	require.NoError(t, err)
+
+	names, err := inter.GetAccountContractNames(address)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Counter"}, names)
+
+	err = executeTransaction(t,
+		inter,
+		`
+          import Counter from 0x1
+
+          transaction {
+              prepare(signer: AuthAccount) {
+                  Counter.increment()
+                  log(Counter.count)
+              }
+          }
+        `,
+	)
+	require.NoError(t, err)
+
+	events := inter.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, "flow.AccountContractAdded", events[0].EventType.ID())
+	assert.Equal(t, "A.0000000000000001.Counter.Incremented", events[1].EventType.ID())
+
+	assert.Equal(t, []string{"1"}, inter.Logs())
+
+	value := executeScript(t,
+		inter,
+		`
+          import Counter from 0x1
+
+          pub fun main(): Int {
+              return Counter.count
+          }
+        `,
+	)
+	assert.Equal(t, cadence.NewInt(1), value)
+
+	// The contract was stored in the account
+
+	storageUsed, err := inter.GetStorageUsed(address)
+	require.NoError(t, err)
+	assert.Greater(t, storageUsed, uint64(0))
+
+	storageUsed, err = inter.GetStorageUsed(common.Address{0x2})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), storageUsed)
+}
+
+func TestInterfaceAccountKeys(t *testing.T) {
+
+	t.Parallel()
+
+	inter := NewInterface()
+
+	address, err := inter.CreateAccount(common.Address{})
+	require.NoError(t, err)
+
+	publicKey := newP256Key(t)
+
+	inter.SetSigningAccounts(address)
+
+	err = executeTransaction(t,
+		inter,
+		`
+          transaction(publicKey: [UInt8], encodedKey: [UInt8]) {
+              prepare(signer: AuthAccount) {
+                  let key = PublicKey(
+                      publicKey: publicKey,
+                      signatureAlgorithm: SignatureAlgorithm.ECDSA_P256
+                  )
+                  signer.keys.add(publicKey: key, hashAlgorithm: HashAlgorithm.SHA3_256, weight: 100.0)
+                  signer.addPublicKey(encodedKey)
+                  signer.keys.revoke(keyIndex: 0)
+              }
+          }
+        `,
+		bytesValue(publicKey.encoded),
+		bytesValue(EncodeAccountKey(&runtime.AccountKey{
+			PublicKey: &runtime.PublicKey{
+				PublicKey: publicKey.encoded,
+				SignAlgo:  runtime.SignatureAlgorithmECDSA_P256,
+			},
+			HashAlgo: runtime.HashAlgorithmSHA3_256,
+			Weight:   1000,
+		})),
+	)
+	require.NoError(t, err)
+
+	key, err := inter.GetAccountKey(address, 0)
+	require.NoError(t, err)
+	require.NotNil(t, key)
+	assert.True(t, key.IsRevoked)
+	assert.Equal(t, publicKey.encoded, key.PublicKey.PublicKey)
+	assert.Equal(t, runtime.SignatureAlgorithmECDSA_P256, key.PublicKey.SignAlgo)
+	assert.Equal(t, runtime.HashAlgorithmSHA3_256, key.HashAlgo)
+	assert.Equal(t, 100, key.Weight)
+
+	// The key added in its encoded form is decoded
+
+	key, err = inter.GetAccountKey(address, 1)
+	require.NoError(t, err)
+	require.NotNil(t, key)
+	assert.False(t, key.IsRevoked)
+	assert.Equal(t, publicKey.encoded, key.PublicKey.PublicKey)
+	assert.Equal(t, runtime.SignatureAlgorithmECDSA_P256, key.PublicKey.SignAlgo)
+	assert.Equal(t, runtime.HashAlgorithmSHA3_256, key.HashAlgo)
+	assert.Equal(t, 1000, key.Weight)
+
+	key, err = inter.GetAccountKey(address, 2)
+	require.NoError(t, err)
+	assert.Nil(t, key)
+
+	// Invalid keys are rejected
+
+	err = executeTransaction(t,
+		inter,
+		`
+          transaction {
+              prepare(signer: AuthAccount) {
+                  let key = PublicKey(
+                      publicKey: [1, 2, 3],
+                      signatureAlgorithm: SignatureAlgorithm.ECDSA_P256
+                  )
+              }
+          }
+        `,
+	)
+	require.Error(t, err)
+}
+
+func TestEncodeAccountKey(t *testing.T) {
+
+	t.Parallel()
+
+	inter := NewInterface()
+	address := common.Address{0x1}
+
+	for _, length := range []int{0, 1, 55, 56, 300} {
+		publicKey := make([]byte, length)
+		for i := range publicKey {
+			publicKey[i] = byte(i)
+		}
+
+		key := &runtime.AccountKey{
+			PublicKey: &runtime.PublicKey{
+				PublicKey: publicKey,
+				SignAlgo:  runtime.SignatureAlgorithmECDSA_secp256k1,
+			},
+			HashAlgo: runtime.HashAlgorithmKECCAK_256,
+			Weight:   1000,
+		}
+
+		err := inter.AddEncodedAccountKey(address, EncodeAccountKey(key))
+		require.NoError(t, err, length)
+
+		encodedKey, err := inter.RevokeEncodedAccountKey(address, 0)
+		require.NoError(t, err)
+		assert.Equal(t, EncodeAccountKey(key), encodedKey)
+
+		decodedKey, err := inter.GetAccountKey(address, 0)
+		require.NoError(t, err)
+		assert.Equal(t, publicKey, decodedKey.PublicKey.PublicKey, length)
+		assert.Equal(t, key.PublicKey.SignAlgo, decodedKey.PublicKey.SignAlgo)
+		assert.Equal(t, key.HashAlgo, decodedKey.HashAlgo)
+		assert.Equal(t, key.Weight, decodedKey.Weight)
+
+		inter.account(address).keys = nil
+	}
+
+	err := inter.AddEncodedAccountKey(address, []byte{0x1, 0x2})
+	require.Error(t, err)
+}
+
+func TestInterfaceBlocks(t *testing.T) {
+
+	t.Parallel()
+
+	genesis := time.Unix(1_000, 0)
+
+	inter := NewInterface(WithGenesisBlock(10, genesis))
+
+	height, err := inter.GetCurrentBlockHeight()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), height)
+
+	next := inter.CommitBlock(genesis.Add(time.Second))
+	assert.Equal(t, uint64(11), next.Height)
+	assert.NotEqual(t, runtime.BlockHash{}, next.Hash)
+
+	value := executeScript(t,
+		inter,
+		`
+          pub fun main(): [UFix64] {
+              let current = getCurrentBlock()
+              let previous = getBlock(at: current.height - 1)!
+              assert(getBlock(at: current.height + 1) == nil)
+              assert(getBlock(at: 9) == nil)
+              return [UFix64(current.height), current.timestamp, previous.timestamp]
+          }
+        `,
+	)
+
+	assert.Equal(t,
+		"[11.00000000, 1001.00000000, 1000.00000000]",
+		value.String(),
+	)
+}
+
+func TestInterfaceDeterminism(t *testing.T) {
+
+	t.Parallel()
+
+	const script = `
+      pub fun main(): [UInt64] {
+          return [unsafeRandom(), unsafeRandom()]
+      }
+    `
+
+	first := executeScript(t, NewInterface(), script)
+	second := executeScript(t, NewInterface(), script)
+	assert.Equal(t, first, second)
+
+	seeded := executeScript(t, NewInterface(WithRandomSeed(42)), script)
+	assert.NotEqual(t, first, seeded)
+
+	inter := NewInterface()
+	for i := uint64(1); i <= 3; i++ {
+		uuid, err := inter.GenerateUUID()
+		require.NoError(t, err)
+		assert.Equal(t, i, uuid)
+	}
+}
+
+func TestInterfaceHandlers(t *testing.T) {
+
+	t.Parallel()
+
+	var logs []string
+	var events []cadence.Event
+
+	inter := NewInterface(
+		WithOnLogHandler(func(message string) {
+			logs = append(logs, message)
+		}),
+		WithOnEventEmittedHandler(func(event cadence.Event) {
+			events = append(events, event)
+		}),
+		WithStorageCapacity(42),
+	)
+
+	payer, err := inter.CreateAccount(common.Address{})
+	require.NoError(t, err)
+
+	inter.SetSigningAccounts(payer)
+
+	err = executeTransaction(t,
+		inter,
+		`
+          transaction {
+              prepare(signer: AuthAccount) {
+                  let account = AuthAccount(payer: signer)
+                  log(account.storageCapacity)
+              }
+          }
+        `,
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"42"}, logs)
+	require.Len(t, events, 1)
+	assert.Equal(t, "flow.AccountCreated", events[0].EventType.ID())
+	assert.Equal(t, "0x0000000000000002", events[0].Fields[0].String())
+}
+
+func TestInterfaceDecodeArgumentLimits(t *testing.T) {
+
+	t.Parallel()
+
+	inter := NewInterface()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		value, err := inter.DecodeArgument(
+			[]byte(`{"type":"Array","value":[{"type":"Int","value":"1"}]}`),
+			cadence.NewVariableSizedArrayType(cadence.IntType{}),
+		)
+		require.NoError(t, err)
+		assert.Equal(t,
+			cadence.NewArray([]cadence.Value{cadence.NewInt(1)}),
+			value,
+		)
+	})
+
+	t.Run("too deep", func(t *testing.T) {
+
+		t.Parallel()
+
+		const depth = runtime.ArgumentMaxDepth + 1
+
+		argument := strings.Repeat(`{"type":"Optional","value":`, depth) +
+			`null` +
+			strings.Repeat(`}`, depth)
+
+		_, err := inter.DecodeArgument([]byte(argument), nil)
+		require.ErrorIs(t, err, jsoncdc.ErrMaxDepthExceeded)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+
+		t.Parallel()
+
+		argument := `{"type":"String","value":"` +
+			strings.Repeat("a", runtime.ArgumentMaxInputSize) +
+			`"}`
+
+		_, err := inter.DecodeArgument([]byte(argument), cadence.StringType{})
+		require.ErrorIs(t, err, jsoncdc.ErrMaxInputSizeExceeded)
+	})
+}
+
+func bytesValue(data []byte) cadence.Array {
+	values := make([]cadence.Value, len(data))
+	for i, b := range data {
+		values[i] = cadence.NewUInt8(b)
+	}
+	return cadence.NewArray(values)
+}
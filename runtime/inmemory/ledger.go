@@ -0,0 +1,90 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"encoding/binary"
+
+	"github.com/onflow/atree"
+)
+
+// Ledger is an atree.Ledger which stores all registers in memory.
+//
+// Registers are grouped by owner, so the storage used by an account can be determined
+type Ledger struct {
+	registers      map[string]map[string][]byte
+	storageIndices map[string]uint64
+}
+
+var _ atree.Ledger = &Ledger{}
+
+func NewLedger() *Ledger {
+	return &Ledger{
+		registers:      map[string]map[string][]byte{},
+		storageIndices: map[string]uint64{},
+	}
+}
+
+func (l *Ledger) GetValue(owner, key []byte) (value []byte, err error) {
+	return l.registers[string(owner)][string(key)], nil
+}
+
+// SetValue sets the value of the register with the given owner and key.
+// Setting an empty value removes the register
+func (l *Ledger) SetValue(owner, key, value []byte) (err error) {
+	registers, ok := l.registers[string(owner)]
+	if !ok {
+		if len(value) == 0 {
+			return nil
+		}
+		registers = map[string][]byte{}
+		l.registers[string(owner)] = registers
+	}
+
+	if len(value) == 0 {
+		delete(registers, string(key))
+		return nil
+	}
+
+	// Copy the value, the caller might reuse the slice
+	registers[string(key)] = append([]byte(nil), value...)
+
+	return nil
+}
+
+func (l *Ledger) ValueExists(owner, key []byte) (exists bool, err error) {
+	return len(l.registers[string(owner)][string(key)]) > 0, nil
+}
+
+func (l *Ledger) AllocateStorageIndex(owner []byte) (result atree.StorageIndex, err error) {
+	index := l.storageIndices[string(owner)] + 1
+	l.storageIndices[string(owner)] = index
+	binary.BigEndian.PutUint64(result[:], index)
+	return
+}
+
+// StorageUsed returns the number of bytes used by the registers of the given owner,
+// i.e. the sum of the sizes of their keys and values
+func (l *Ledger) StorageUsed(owner []byte) uint64 {
+	var used uint64
+	for key, value := range l.registers[string(owner)] { //nolint:maprangecheck
+		used += uint64(len(key) + len(value))
+	}
+	return used
+}
@@ -0,0 +1,157 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package inmemory
+
+import (
+	"math/big"
+)
+
+// Parameters of the secp256k1 curve, y² = x³ + 7, see https://www.secg.org/sec2-v2.pdf
+
+var secp256k1P, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F", 16)
+var secp256k1N, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+var secp256k1Gx, _ = new(big.Int).SetString("79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798", 16)
+var secp256k1Gy, _ = new(big.Int).SetString("483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8", 16)
+var secp256k1B = big.NewInt(7)
+
+// secp256k1IsOnCurve returns true if the given affine point is on the curve
+func secp256k1IsOnCurve(x, y *big.Int) bool {
+	if x.Sign() < 0 || x.Cmp(secp256k1P) >= 0 ||
+		y.Sign() < 0 || y.Cmp(secp256k1P) >= 0 {
+
+		return false
+	}
+
+	// y² = x³ + 7 (mod p)
+
+	left := new(big.Int).Mul(y, y)
+	left.Mod(left, secp256k1P)
+
+	right := new(big.Int).Mul(x, x)
+	right.Mul(right, x)
+	right.Add(right, secp256k1B)
+	right.Mod(right, secp256k1P)
+
+	return left.Cmp(right) == 0
+}
+
+// secp256k1Add returns the sum of the given affine points.
+// The point at infinity is represented as nil coordinates
+func secp256k1Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	if x1 == nil {
+		return x2, y2
+	}
+	if x2 == nil {
+		return x1, y1
+	}
+
+	p := secp256k1P
+
+	var lambda *big.Int
+
+	if x1.Cmp(x2) == 0 {
+		sum := new(big.Int).Add(y1, y2)
+		if sum.Mod(sum, p).Sign() == 0 {
+			// P + (-P) = infinity
+			return nil, nil
+		}
+
+		// Doubling: λ = 3x² / 2y
+		numerator := new(big.Int).Mul(x1, x1)
+		numerator.Mul(numerator, big.NewInt(3))
+		denominator := new(big.Int).Lsh(y1, 1)
+		denominator.ModInverse(denominator, p)
+		lambda = numerator.Mul(numerator, denominator)
+	} else {
+		// λ = (y2 - y1) / (x2 - x1)
+		numerator := new(big.Int).Sub(y2, y1)
+		denominator := new(big.Int).Sub(x2, x1)
+		denominator.Mod(denominator, p)
+		denominator.ModInverse(denominator, p)
+		lambda = numerator.Mul(numerator, denominator)
+	}
+	lambda.Mod(lambda, p)
+
+	// x = λ² - x1 - x2
+	x = new(big.Int).Mul(lambda, lambda)
+	x.Sub(x, x1)
+	x.Sub(x, x2)
+	x.Mod(x, p)
+
+	// y = λ(x1 - x) - y1
+	y = new(big.Int).Sub(x1, x)
+	y.Mul(y, lambda)
+	y.Sub(y, y1)
+	y.Mod(y, p)
+
+	return x, y
+}
+
+// secp256k1ScalarMult returns k times the given affine point
+func secp256k1ScalarMult(x1, y1 *big.Int, k *big.Int) (x, y *big.Int) {
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		x, y = secp256k1Add(x, y, x, y)
+		if k.Bit(i) == 1 {
+			x, y = secp256k1Add(x, y, x1, y1)
+		}
+	}
+	return x, y
+}
+
+// secp256k1HashToInt converts the given digest to an integer,
+// truncated to the bit length of the group order
+func secp256k1HashToInt(digest []byte) *big.Int {
+	orderBytes := (secp256k1N.BitLen() + 7) / 8
+	if len(digest) > orderBytes {
+		digest = digest[:orderBytes]
+	}
+	return new(big.Int).SetBytes(digest)
+}
+
+// secp256k1Verify verifies the ECDSA signature (r, s) of the given digest
+// for the public key (x, y)
+func secp256k1Verify(x, y *big.Int, digest []byte, r, s *big.Int) bool {
+	n := secp256k1N
+
+	if r.Sign() <= 0 || r.Cmp(n) >= 0 ||
+		s.Sign() <= 0 || s.Cmp(n) >= 0 {
+
+		return false
+	}
+
+	e := secp256k1HashToInt(digest)
+
+	w := new(big.Int).ModInverse(s, n)
+
+	u1 := new(big.Int).Mul(e, w)
+	u1.Mod(u1, n)
+
+	u2 := new(big.Int).Mul(r, w)
+	u2.Mod(u2, n)
+
+	x1, y1 := secp256k1ScalarMult(secp256k1Gx, secp256k1Gy, u1)
+	x2, y2 := secp256k1ScalarMult(x, y, u2)
+	rx, _ := secp256k1Add(x1, y1, x2, y2)
+	if rx == nil {
+		return false
+	}
+
+	rx.Mod(rx, n)
+	return rx.Cmp(r) == 0
+}
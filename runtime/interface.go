@@ -26,6 +26,7 @@ import (
 	"github.com/onflow/atree"
 
 	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
 	"github.com/onflow/cadence/runtime/common"
 	"github.com/onflow/cadence/runtime/interpreter"
 )
@@ -103,6 +104,8 @@ type Interface interface {
 	// when computation passes the limit (set by the environment)
 	MeterComputation(operationType common.ComputationKind, intensity uint) error
 	// DecodeArgument decodes a transaction argument against the given type.
+	// Arguments are untrusted input, so implementations should enforce limits,
+	// e.g. by decoding JSON-Cadence with ArgumentDecodingOptions.
 	DecodeArgument(argument []byte, argumentType cadence.Type) (cadence.Value, error)
 	// GetCurrentBlockHeight returns the current block height.
 	GetCurrentBlockHeight() (uint64, error)
@@ -156,6 +159,32 @@ type Interface interface {
 	MeterMemory(usage common.MemoryUsage) error
 }
 
+// Limits for decoding JSON-Cadence encoded entry point arguments
+const (
+	// ArgumentMaxInputSize is the maximum size of an encoded argument, in bytes.
+	// It is larger than the maximum size of a transaction
+	ArgumentMaxInputSize = 2 << 20
+	// ArgumentMaxDepth is the maximum nesting depth of JSON arrays and objects
+	ArgumentMaxDepth = 256
+	// ArgumentMaxArrayLength is the maximum number of elements of a JSON array,
+	// or members of a JSON object
+	ArgumentMaxArrayLength = 1 << 17
+	// ArgumentMaxStringLength is the maximum length of a JSON string, in bytes
+	ArgumentMaxStringLength = ArgumentMaxInputSize
+)
+
+// ArgumentDecodingOptions returns the JSON-Cadence decoder options
+// which enforce the limits for decoding entry point arguments
+//
+func ArgumentDecodingOptions() []jsoncdc.Option {
+	return []jsoncdc.Option{
+		jsoncdc.WithMaxInputSize(ArgumentMaxInputSize),
+		jsoncdc.WithMaxDepth(ArgumentMaxDepth),
+		jsoncdc.WithMaxArrayLength(ArgumentMaxArrayLength),
+		jsoncdc.WithMaxStringLength(ArgumentMaxStringLength),
+	}
+}
+
 type Metrics interface {
 	ProgramParsed(location common.Location, duration time.Duration)
 	ProgramChecked(location common.Location, duration time.Duration)
@@ -92,6 +92,15 @@ func NewAuthAccountValue(
 		sema.AuthAccountTypeField: func(inter *Interpreter, _ func() LocationRange) Value {
 			return inter.authAccountTypeFunction(address)
 		},
+		sema.AuthAccountStorageSizeField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.authAccountStorageSizeFunction(address)
+		},
+		sema.AuthAccountCheckField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.authAccountCheckFunction(address)
+		},
+		sema.AuthAccountSwapField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.authAccountSwapFunction(address)
+		},
 		sema.AuthAccountLoadField: func(inter *Interpreter, _ func() LocationRange) Value {
 			return inter.authAccountLoadFunction(address)
 		},
@@ -113,6 +122,36 @@ func NewAuthAccountValue(
 		sema.AuthAccountGetLinkTargetField: func(inter *Interpreter, _ func() LocationRange) Value {
 			return inter.accountGetLinkTargetFunction(address)
 		},
+		sema.AuthAccountStoragePathsField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.accountPaths(address.ToAddress(), common.PathDomainStorage, PrimitiveStaticTypeStoragePath)
+		},
+		sema.AuthAccountPublicPathsField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.accountPaths(address.ToAddress(), common.PathDomainPublic, PrimitiveStaticTypePublicPath)
+		},
+		sema.AuthAccountPrivatePathsField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.accountPaths(address.ToAddress(), common.PathDomainPrivate, PrimitiveStaticTypePrivatePath)
+		},
+		sema.AuthAccountForEachStoredField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.accountForEachFunction(
+				address,
+				common.PathDomainStorage,
+				sema.AuthAccountForEachStoredFunctionType,
+			)
+		},
+		sema.AuthAccountForEachPublicField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.accountForEachFunction(
+				address,
+				common.PathDomainPublic,
+				sema.AccountForEachPublicFunctionType,
+			)
+		},
+		sema.AuthAccountForEachPrivateField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.accountForEachFunction(
+				address,
+				common.PathDomainPrivate,
+				sema.AuthAccountForEachPrivateFunctionType,
+			)
+		},
 	}
 
 	var str string
@@ -200,6 +239,16 @@ func NewPublicAccountValue(
 		sema.PublicAccountGetTargetLinkField: func(inter *Interpreter, _ func() LocationRange) Value {
 			return inter.accountGetLinkTargetFunction(address)
 		},
+		sema.PublicAccountPublicPathsField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.accountPaths(address.ToAddress(), common.PathDomainPublic, PrimitiveStaticTypePublicPath)
+		},
+		sema.PublicAccountForEachPublicField: func(inter *Interpreter, _ func() LocationRange) Value {
+			return inter.accountForEachFunction(
+				address,
+				common.PathDomainPublic,
+				sema.AccountForEachPublicFunctionType,
+			)
+		},
 	}
 
 	var str string
@@ -19,26 +19,70 @@
 package interpreter
 
 import (
+	"sync"
 	"sync/atomic"
 
 	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
 )
 
 type Stop struct {
 	Interpreter *Interpreter
 	Statement   ast.Statement
+	// Breakpoint is the breakpoint which caused the stop, if any
+	Breakpoint *Breakpoint
 }
 
+// Breakpoint is a line breakpoint.
+//
+// If the breakpoint has a condition, execution only stops
+// if the condition, a Cadence expression of type Bool,
+// evaluates to true in the current activation
+//
+type Breakpoint struct {
+	Location  common.Location
+	Line      int
+	Condition ast.Expression
+}
+
+type breakpointKey struct {
+	locationID common.LocationID
+	line       int
+}
+
+type stepKind uint8
+
+const (
+	stepKindNone stepKind = iota
+	stepKindOver
+	stepKindOut
+)
+
 type Debugger struct {
 	pauseRequested uint32
-	stops          chan Stop
-	continues      chan struct{}
+	// evaluating is non-zero while an expression is evaluated.
+	// The evaluation may call functions of the program,
+	// which must not stop
+	evaluating int32
+	stops      chan Stop
+	continues  chan struct{}
+	// mutex guards the fields below,
+	// which are accessed by both the interpreter and the debugger client
+	mutex       sync.Mutex
+	breakpoints map[breakpointKey]*Breakpoint
+	step        stepKind
+	// stepDepth is the call stack depth when a step was requested
+	stepDepth int
+	// depth is the call stack depth of the last stop
+	depth int
 }
 
 func NewDebugger() *Debugger {
 	return &Debugger{
-		stops:     make(chan Stop),
-		continues: make(chan struct{}),
+		stops:       make(chan Stop),
+		continues:   make(chan struct{}),
+		breakpoints: map[breakpointKey]*Breakpoint{},
 	}
 }
 
@@ -47,18 +91,88 @@ func (d *Debugger) Stops() <-chan Stop {
 }
 
 func (d *Debugger) onStatement(interpreter *Interpreter, statement ast.Statement) {
-	if !d.PauseRequested() {
+	if atomic.LoadInt32(&d.evaluating) > 0 {
+		return
+	}
+
+	stop, ok := d.shouldStop(interpreter, statement)
+	if !ok {
 		return
 	}
 
-	d.stops <- Stop{
+	d.stops <- stop
+
+	<-d.continues
+}
+
+// shouldStop determines if the execution should stop at the given statement,
+// because a pause or step was requested, or because a breakpoint was hit.
+//
+// If the execution should stop, the pause and step requests are reset
+//
+func (d *Debugger) shouldStop(interpreter *Interpreter, statement ast.Statement) (Stop, bool) {
+	depth := len(interpreter.CallStack.Invocations)
+
+	stop := Stop{
 		Interpreter: interpreter,
 		Statement:   statement,
 	}
 
+	d.mutex.Lock()
+
+	stepped := d.PauseRequested()
+
+	switch d.step {
+	case stepKindOver:
+		stepped = stepped || depth <= d.stepDepth
+	case stepKindOut:
+		stepped = stepped || depth < d.stepDepth
+	}
+
+	var breakpoint *Breakpoint
+	if !stepped && len(d.breakpoints) > 0 && interpreter.Location != nil {
+		key := breakpointKey{
+			locationID: interpreter.Location.ID(),
+			line:       statement.StartPosition().Line,
+		}
+		breakpoint = d.breakpoints[key]
+	}
+
+	d.mutex.Unlock()
+
+	if !stepped {
+		if breakpoint == nil || !d.conditionHolds(interpreter, breakpoint) {
+			return stop, false
+		}
+		stop.Breakpoint = breakpoint
+	}
+
+	d.mutex.Lock()
 	d.resetPauseRequest()
+	d.step = stepKindNone
+	d.depth = depth
+	d.mutex.Unlock()
 
-	<-d.continues
+	return stop, true
+}
+
+// conditionHolds returns true if the breakpoint has no condition,
+// or the condition evaluates to true.
+//
+// A condition which fails to evaluate is considered to hold,
+// so that the failure does not go unnoticed
+//
+func (d *Debugger) conditionHolds(interpreter *Interpreter, breakpoint *Breakpoint) bool {
+	if breakpoint.Condition == nil {
+		return true
+	}
+
+	value, err := d.evaluate(interpreter, breakpoint.Condition, sema.BoolType)
+	if err != nil {
+		return true
+	}
+
+	return value == BoolValue(true)
 }
 
 func (d *Debugger) PauseRequested() bool {
@@ -87,12 +201,391 @@ func (d *Debugger) Pause() Stop {
 	return <-d.Stops()
 }
 
+// Next continues the execution until the next statement is reached,
+// which might be in a function called by the current statement.
+//
+// Next is also known as "step in"
+//
 func (d *Debugger) Next() Stop {
 	d.RequestPause()
 	d.Continue()
 	return <-d.Stops()
 }
 
+// StepIn is an alias for Next
+//
+func (d *Debugger) StepIn() Stop {
+	return d.Next()
+}
+
+// StepOver continues the execution until the next statement
+// of the current function, or of a calling function, is reached.
+// Statements of functions called by the current statement are not stopped at,
+// unless they have a breakpoint
+//
+func (d *Debugger) StepOver() Stop {
+	d.RequestStepOver()
+	d.Continue()
+	return <-d.Stops()
+}
+
+// StepOut continues the execution until the current function returns,
+// and the next statement of a calling function is reached
+//
+func (d *Debugger) StepOut() Stop {
+	d.RequestStepOut()
+	d.Continue()
+	return <-d.Stops()
+}
+
+// RequestStepOver requests a stop at the next statement of the current function,
+// or of a calling function.
+// Unlike StepOver, it neither continues the execution, nor waits for the stop
+//
+func (d *Debugger) RequestStepOver() {
+	d.requestStep(stepKindOver)
+}
+
+// RequestStepOut requests a stop at the next statement of a calling function.
+// Unlike StepOut, it neither continues the execution, nor waits for the stop
+//
+func (d *Debugger) RequestStepOut() {
+	d.requestStep(stepKindOut)
+}
+
+func (d *Debugger) requestStep(kind stepKind) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.step = kind
+	d.stepDepth = d.depth
+}
+
+// AddBreakpoint adds a breakpoint for the given line in the given location.
+// An existing breakpoint for the same line is replaced
+//
+func (d *Debugger) AddBreakpoint(location common.Location, line int) *Breakpoint {
+	return d.AddConditionalBreakpoint(location, line, nil)
+}
+
+// AddConditionalBreakpoint adds a breakpoint for the given line in the given location,
+// which only stops the execution if the given condition evaluates to true.
+// If the condition is nil, the breakpoint is unconditional.
+// An existing breakpoint for the same line is replaced
+//
+func (d *Debugger) AddConditionalBreakpoint(
+	location common.Location,
+	line int,
+	condition ast.Expression,
+) *Breakpoint {
+	breakpoint := &Breakpoint{
+		Location:  location,
+		Line:      line,
+		Condition: condition,
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.breakpoints[breakpointKey{location.ID(), line}] = breakpoint
+
+	return breakpoint
+}
+
+// RemoveBreakpoint removes the breakpoint for the given line in the given location.
+// It returns true if a breakpoint was removed
+//
+func (d *Debugger) RemoveBreakpoint(location common.Location, line int) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	key := breakpointKey{location.ID(), line}
+	if _, ok := d.breakpoints[key]; !ok {
+		return false
+	}
+	delete(d.breakpoints, key)
+	return true
+}
+
+// ClearBreakpoints removes all breakpoints
+//
+func (d *Debugger) ClearBreakpoints() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.breakpoints = map[breakpointKey]*Breakpoint{}
+}
+
+// Breakpoints returns all breakpoints
+//
+func (d *Debugger) Breakpoints() []*Breakpoint {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	breakpoints := make([]*Breakpoint, 0, len(d.breakpoints))
+	for _, breakpoint := range d.breakpoints { //nolint:maprangecheck
+		breakpoints = append(breakpoints, breakpoint)
+	}
+	return breakpoints
+}
+
 func (d *Debugger) CurrentActivation(interpreter *Interpreter) *VariableActivation {
 	return interpreter.activations.Current()
 }
+
+// Evaluate evaluates the given expression in the current activation of the given interpreter,
+// e.g. the expression of a watch.
+//
+// The expression is type-checked separately from the program.
+// The variables it refers to are typed using the dynamic types of their values,
+// and access control is not enforced.
+//
+// The execution must be stopped, i.e. the interpreter must be the interpreter of the current stop
+//
+func (d *Debugger) Evaluate(interpreter *Interpreter, expression ast.Expression) (Value, error) {
+	return d.evaluate(interpreter, expression, sema.AnyStructType)
+}
+
+// debuggerLocation is the location of the programs which evaluate the expressions
+//
+const debuggerLocation = common.IdentifierLocation("debugger")
+
+// debuggerFunctionName is the name of the function which evaluates an expression
+//
+const debuggerFunctionName = "evaluate"
+
+func (d *Debugger) evaluate(interpreter *Interpreter, expression ast.Expression, resultType sema.Type) (Value, error) {
+
+	atomic.AddInt32(&d.evaluating, 1)
+	defer atomic.AddInt32(&d.evaluating, -1)
+
+	// Wrap the expression in a function, so the result type is checked
+
+	program := debuggerProgram(expression, resultType)
+
+	// Declare the variables the expression refers to
+
+	declarations, err := d.debuggerValueDeclarations(interpreter, expression)
+	if err != nil {
+		return nil, err
+	}
+
+	semaDeclarations := make([]sema.ValueDeclaration, len(declarations))
+	interpreterDeclarations := make([]ValueDeclaration, len(declarations))
+	for i, declaration := range declarations {
+		semaDeclarations[i] = declaration
+		interpreterDeclarations[i] = declaration
+	}
+
+	checker, err := sema.NewChecker(
+		program,
+		debuggerLocation,
+		interpreter.memoryGauge,
+		sema.WithPredeclaredValues(semaDeclarations),
+		sema.WithAccessCheckMode(sema.AccessCheckModeNone),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checker.Check()
+	if err != nil {
+		return nil, err
+	}
+
+	// The sub-interpreter must not register itself in the interpreters of the program,
+	// and the evaluation must not be observed by the handlers of the program
+
+	allInterpreters := make(map[common.LocationID]*Interpreter, len(interpreter.allInterpreters))
+	for locationID, other := range interpreter.allInterpreters { //nolint:maprangecheck
+		allInterpreters[locationID] = other
+	}
+
+	subInterpreter, err := interpreter.NewSubInterpreter(
+		ProgramFromChecker(checker),
+		debuggerLocation,
+		WithPredeclaredValues(interpreterDeclarations),
+		WithAllInterpreters(allInterpreters),
+		WithDebugger(nil),
+		WithOnStatementHandler(nil),
+		WithOnLoopIterationHandler(nil),
+		WithOnFunctionInvocationHandler(nil),
+		WithOnInvokedFunctionReturnHandler(nil),
+		WithOnFunctionEntryHandler(nil),
+		WithOnBranchHandler(nil),
+		WithOnMeterComputationFuncHandler(nil),
+		WithTracingEnabled(false),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = subInterpreter.Interpret()
+	if err != nil {
+		return nil, err
+	}
+
+	return subInterpreter.Invoke(debuggerFunctionName)
+}
+
+// debuggerProgram returns a program which declares a function
+// that returns the result of the given expression.
+//
+// NOTE: the program is constructed directly,
+// as the interpreter may not depend on the parser
+//
+func debuggerProgram(expression ast.Expression, resultType sema.Type) *ast.Program {
+	position := expression.StartPosition()
+
+	returnType := ast.NewNominalType(
+		nil,
+		ast.NewIdentifier(nil, resultType.QualifiedString(), position),
+		nil,
+	)
+
+	returnStatement := ast.NewReturnStatement(
+		nil,
+		expression,
+		ast.NewUnmeteredRangeFromPositioned(expression),
+	)
+
+	functionDeclaration := ast.NewFunctionDeclaration(
+		nil,
+		ast.AccessNotSpecified,
+		ast.FunctionPurityUnspecified,
+		ast.NewIdentifier(nil, debuggerFunctionName, position),
+		nil,
+		ast.NewParameterList(nil, nil, ast.EmptyRange),
+		ast.NewTypeAnnotation(nil, false, returnType, position),
+		ast.NewFunctionBlock(
+			nil,
+			ast.NewBlock(
+				nil,
+				[]ast.Statement{returnStatement},
+				ast.NewUnmeteredRangeFromPositioned(expression),
+			),
+			nil,
+			nil,
+		),
+		position,
+		"",
+	)
+
+	return ast.NewProgram(nil, []ast.Declaration{functionDeclaration})
+}
+
+// debuggerValueDeclarations returns declarations for the variables in the current activation
+// which are referred to by the given expression
+//
+func (d *Debugger) debuggerValueDeclarations(
+	interpreter *Interpreter,
+	expression ast.Expression,
+) (
+	[]debuggerValueDeclaration,
+	error,
+) {
+	activation := d.CurrentActivation(interpreter)
+	if activation == nil {
+		return nil, nil
+	}
+
+	var declarations []debuggerValueDeclaration
+	declared := map[string]struct{}{}
+
+	var err error
+
+	ast.Inspect(expression, func(element ast.Element) bool {
+		if err != nil {
+			return false
+		}
+
+		identifierExpression, ok := element.(*ast.IdentifierExpression)
+		if !ok {
+			return true
+		}
+
+		name := identifierExpression.Identifier.Identifier
+		if _, ok := declared[name]; ok {
+			return true
+		}
+		declared[name] = struct{}{}
+
+		variable := activation.Find(name)
+		if variable == nil {
+			return true
+		}
+
+		value := variable.GetValue()
+
+		var ty sema.Type
+		ty, err = interpreter.ConvertStaticToSemaType(value.StaticType(interpreter))
+		if err != nil {
+			return false
+		}
+		if ty == nil {
+			return true
+		}
+
+		declarations = append(declarations, debuggerValueDeclaration{
+			name:  name,
+			value: value,
+			ty:    ty,
+		})
+
+		return true
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return declarations, nil
+}
+
+// debuggerValueDeclaration declares a variable of the current activation
+// in the program which evaluates an expression
+//
+type debuggerValueDeclaration struct {
+	name  string
+	value Value
+	ty    sema.Type
+}
+
+var _ sema.ValueDeclaration = debuggerValueDeclaration{}
+var _ ValueDeclaration = debuggerValueDeclaration{}
+
+func (d debuggerValueDeclaration) ValueDeclarationName() string {
+	return d.name
+}
+
+func (d debuggerValueDeclaration) ValueDeclarationValue(_ *Interpreter) Value {
+	return d.value
+}
+
+func (d debuggerValueDeclaration) ValueDeclarationType() sema.Type {
+	return d.ty
+}
+
+func (debuggerValueDeclaration) ValueDeclarationDocString() string {
+	return ""
+}
+
+func (debuggerValueDeclaration) ValueDeclarationKind() common.DeclarationKind {
+	return common.DeclarationKindConstant
+}
+
+func (debuggerValueDeclaration) ValueDeclarationPosition() ast.Position {
+	return ast.Position{}
+}
+
+func (debuggerValueDeclaration) ValueDeclarationIsConstant() bool {
+	return true
+}
+
+func (debuggerValueDeclaration) ValueDeclarationArgumentLabels() []string {
+	return nil
+}
+
+func (debuggerValueDeclaration) ValueDeclarationAvailable(_ common.Location) bool {
+	return true
+}
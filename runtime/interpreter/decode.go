@@ -1130,7 +1130,9 @@ func (d TypeDecoder) decodeCompositeStaticType() (StaticType, error) {
 		return nil, err
 	}
 
-	if size != expectedLength {
+	// Instantiations of generic composite types additionally encode the type arguments
+
+	if size != expectedLength && size != encodedInstantiatedCompositeStaticTypeLength {
 		return nil, fmt.Errorf(
 			"invalid composite static type encoding: expected [%d]any, got [%d]any",
 			expectedLength,
@@ -1156,7 +1158,49 @@ func (d TypeDecoder) decodeCompositeStaticType() (StaticType, error) {
 		return nil, err
 	}
 
-	return NewCompositeStaticTypeComputeTypeID(d.memoryGauge, location, qualifiedIdentifier), nil
+	if size == expectedLength {
+		return NewCompositeStaticTypeComputeTypeID(d.memoryGauge, location, qualifiedIdentifier), nil
+	}
+
+	// Decode type arguments at array index encodedCompositeStaticTypeTypeArgumentsFieldKey
+	typeArguments, err := d.decodeStaticTypes()
+	if err != nil {
+		return nil, fmt.Errorf("invalid composite static type type arguments encoding: %w", err)
+	}
+
+	return NewInstantiatedCompositeStaticTypeComputeTypeID(
+		d.memoryGauge,
+		location,
+		qualifiedIdentifier,
+		typeArguments,
+	), nil
+}
+
+func (d TypeDecoder) decodeStaticTypes() ([]StaticType, error) {
+	size, err := d.decoder.DecodeArrayHead()
+	if err != nil {
+		if e, ok := err.(*cbor.WrongTypeError); ok {
+			return nil, fmt.Errorf(
+				"expected array, got %s",
+				e.ActualType.String(),
+			)
+		}
+		return nil, err
+	}
+
+	if size == 0 {
+		return nil, fmt.Errorf("expected at least one static type")
+	}
+
+	staticTypes := make([]StaticType, size)
+	for i := 0; i < int(size); i++ {
+		staticTypes[i], err = d.DecodeStaticType()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return staticTypes, nil
 }
 
 func (d TypeDecoder) decodeInterfaceStaticType() (InterfaceStaticType, error) {
@@ -1497,7 +1541,12 @@ func (d TypeDecoder) decodeCompositeTypeInfo() (atree.TypeInfo, error) {
 		return nil, err
 	}
 
-	if length != encodedCompositeTypeInfoLength {
+	// The type info of values of instantiations of generic composite types
+	// additionally includes the type arguments
+
+	if length != encodedCompositeTypeInfoLength &&
+		length != encodedInstantiatedCompositeTypeInfoLength {
+
 		return nil, fmt.Errorf(
 			"invalid composite type info: expected %d elements, got %d",
 			encodedCompositeTypeInfoLength, length,
@@ -1526,11 +1575,23 @@ func (d TypeDecoder) decodeCompositeTypeInfo() (atree.TypeInfo, error) {
 		)
 	}
 
-	return NewCompositeTypeInfo(
+	var typeArguments []StaticType
+	if length == encodedInstantiatedCompositeTypeInfoLength {
+		typeArguments, err = d.decodeStaticTypes()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"invalid composite ordered map type info: invalid type arguments: %w",
+				err,
+			)
+		}
+	}
+
+	return NewInstantiatedCompositeTypeInfo(
 		d.memoryGauge,
 		location,
 		qualifiedIdentifier,
 		common.CompositeKind(kind),
+		typeArguments,
 	), nil
 }
 
@@ -1009,12 +1009,17 @@ func (t OptionalStaticType) Encode(e *cbor.StreamEncoder) error {
 const (
 	// encodedCompositeStaticTypeLocationFieldKey            uint64 = 0
 	// encodedCompositeStaticTypeQualifiedIdentifierFieldKey uint64 = 1
+	// encodedCompositeStaticTypeTypeArgumentsFieldKey       uint64 = 2
 
 	// !!! *WARNING* !!!
 	//
 	// encodedCompositeStaticTypeLength MUST be updated when new element is added.
 	// It is used to verify encoded composite static type length during decoding.
 	encodedCompositeStaticTypeLength = 2
+
+	// encodedInstantiatedCompositeStaticTypeLength is the length of the encoding
+	// of an instantiation of a generic composite type, which additionally includes the type arguments.
+	encodedInstantiatedCompositeStaticTypeLength = 3
 )
 
 // Encode encodes CompositeStaticType as
@@ -1023,15 +1028,29 @@ const (
 // 			Content: cborArray{
 //				encodedCompositeStaticTypeLocationFieldKey:            Location(v.Location),
 //				encodedCompositeStaticTypeQualifiedIdentifierFieldKey: string(v.QualifiedIdentifier),
+//				encodedCompositeStaticTypeTypeArgumentsFieldKey:       []StaticType(v.TypeArguments()),
 //		},
 // }
+//
+// The type arguments are only encoded for instantiations of generic composite types,
+// so the encoding of all other composite static types is unchanged
+//
 func (t CompositeStaticType) Encode(e *cbor.StreamEncoder) error {
+	typeArguments := t.TypeArguments()
+	isInstantiation := len(typeArguments) > 0
+
+	// array, 2 items follow
+	var arrayHead byte = 0x82
+	if isInstantiation {
+		// array, 3 items follow
+		arrayHead = 0x83
+	}
+
 	// Encode tag number and array head
 	err := e.EncodeRawBytes([]byte{
 		// tag number
 		0xd8, CBORTagCompositeStaticType,
-		// array, 2 items follow
-		0x82,
+		arrayHead,
 	})
 	if err != nil {
 		return err
@@ -1044,7 +1063,31 @@ func (t CompositeStaticType) Encode(e *cbor.StreamEncoder) error {
 	}
 
 	// Encode qualified identifier at array index encodedCompositeStaticTypeQualifiedIdentifierFieldKey
-	return e.EncodeString(t.QualifiedIdentifier)
+	err = e.EncodeString(t.QualifiedIdentifier)
+	if err != nil {
+		return err
+	}
+
+	if !isInstantiation {
+		return nil
+	}
+
+	// Encode type arguments (as array) at array index encodedCompositeStaticTypeTypeArgumentsFieldKey
+	return encodeStaticTypes(e, typeArguments)
+}
+
+func encodeStaticTypes(e *cbor.StreamEncoder, staticTypes []StaticType) error {
+	err := e.EncodeArrayHead(uint64(len(staticTypes)))
+	if err != nil {
+		return err
+	}
+	for _, staticType := range staticTypes {
+		err = EncodeStaticType(e, staticType)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // NOTE: NEVER change, only add/increment; ensure uint64
@@ -1304,6 +1347,7 @@ type compositeTypeInfo struct {
 	location            common.Location
 	qualifiedIdentifier string
 	kind                common.CompositeKind
+	typeArguments       []StaticType
 }
 
 func NewCompositeTypeInfo(
@@ -1321,16 +1365,41 @@ func NewCompositeTypeInfo(
 	}
 }
 
+func NewInstantiatedCompositeTypeInfo(
+	memoryGauge common.MemoryGauge,
+	location common.Location,
+	qualifiedIdentifier string,
+	kind common.CompositeKind,
+	typeArguments []StaticType,
+) compositeTypeInfo {
+	typeInfo := NewCompositeTypeInfo(memoryGauge, location, qualifiedIdentifier, kind)
+	typeInfo.typeArguments = typeArguments
+	return typeInfo
+}
+
 var _ atree.TypeInfo = compositeTypeInfo{}
 
 const encodedCompositeTypeInfoLength = 3
 
+// encodedInstantiatedCompositeTypeInfoLength is the length of the encoding
+// of the type info of a value of an instantiation of a generic composite type,
+// which additionally includes the type arguments
+const encodedInstantiatedCompositeTypeInfoLength = 4
+
 func (c compositeTypeInfo) Encode(e *cbor.StreamEncoder) error {
+	isInstantiation := len(c.typeArguments) > 0
+
+	// array, 3 items follow
+	var arrayHead byte = 0x83
+	if isInstantiation {
+		// array, 4 items follow
+		arrayHead = 0x84
+	}
+
 	err := e.EncodeRawBytes([]byte{
 		// tag number
 		0xd8, CBORTagCompositeValue,
-		// array, 3 items follow
-		0x83,
+		arrayHead,
 	})
 	if err != nil {
 		return err
@@ -1351,15 +1420,34 @@ func (c compositeTypeInfo) Encode(e *cbor.StreamEncoder) error {
 		return err
 	}
 
+	if isInstantiation {
+		err = encodeStaticTypes(e, c.typeArguments)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func (c compositeTypeInfo) Equal(o atree.TypeInfo) bool {
 	other, ok := o.(compositeTypeInfo)
-	return ok &&
-		common.LocationsMatch(c.location, other.location) &&
-		c.qualifiedIdentifier == other.qualifiedIdentifier &&
-		c.kind == other.kind
+	if !ok ||
+		!common.LocationsMatch(c.location, other.location) ||
+		c.qualifiedIdentifier != other.qualifiedIdentifier ||
+		c.kind != other.kind ||
+		len(c.typeArguments) != len(other.typeArguments) {
+
+		return false
+	}
+
+	for i, typeArgument := range c.typeArguments {
+		if !typeArgument.Equal(other.typeArguments[i]) {
+			return false
+		}
+	}
+
+	return true
 }
 
 // EmptyTypeInfo
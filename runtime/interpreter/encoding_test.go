@@ -19,6 +19,7 @@
 package interpreter_test
 
 import (
+	"bytes"
 	"math"
 	"math/big"
 	"strings"
@@ -392,6 +393,104 @@ func TestEncodeDecodeComposite(t *testing.T) {
 	})
 }
 
+func TestEncodeDecodeCompositeTypeInfo(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(typeInfo atree.TypeInfo, expected []byte) {
+
+		var buf bytes.Buffer
+		enc := CBOREncMode.NewStreamEncoder(&buf)
+
+		err := typeInfo.Encode(enc)
+		require.NoError(t, err)
+
+		err = enc.Flush()
+		require.NoError(t, err)
+
+		AssertEqualWithDiff(t, expected, buf.Bytes())
+
+		decoder := CBORDecMode.NewByteStreamDecoder(buf.Bytes())
+		decoded, err := DecodeTypeInfo(decoder, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, typeInfo, decoded)
+	}
+
+	t.Run("structure", func(t *testing.T) {
+
+		t.Parallel()
+
+		test(
+			NewCompositeTypeInfo(
+				nil,
+				utils.TestLocation,
+				"S",
+				common.CompositeKindStructure,
+			),
+			[]byte{
+				// tag
+				0xd8, CBORTagCompositeValue,
+				// array, 3 items follow
+				0x83,
+				// tag
+				0xd8, CBORTagStringLocation,
+				// UTF-8 string, length 4
+				0x64,
+				// t, e, s, t
+				0x74, 0x65, 0x73, 0x74,
+				// UTF-8 string, length 1
+				0x61,
+				// S
+				0x53,
+				// positive integer 1
+				0x1,
+			},
+		)
+	})
+
+	t.Run("instantiated structure", func(t *testing.T) {
+
+		t.Parallel()
+
+		test(
+			NewInstantiatedCompositeTypeInfo(
+				nil,
+				utils.TestLocation,
+				"S",
+				common.CompositeKindStructure,
+				[]StaticType{
+					PrimitiveStaticTypeBool,
+				},
+			),
+			[]byte{
+				// tag
+				0xd8, CBORTagCompositeValue,
+				// array, 4 items follow
+				0x84,
+				// tag
+				0xd8, CBORTagStringLocation,
+				// UTF-8 string, length 4
+				0x64,
+				// t, e, s, t
+				0x74, 0x65, 0x73, 0x74,
+				// UTF-8 string, length 1
+				0x61,
+				// S
+				0x53,
+				// positive integer 1
+				0x1,
+				// array, 1 item follows
+				0x81,
+				// tag
+				0xd8, CBORTagPrimitiveStaticType,
+				// bool
+				0x6,
+			},
+		)
+	})
+}
+
 func TestEncodeDecodeIntValue(t *testing.T) {
 
 	t.Parallel()
@@ -3303,6 +3402,60 @@ func TestEncodeDecodeLinkValue(t *testing.T) {
 		)
 	})
 
+	t.Run("composite, struct, instantiation", func(t *testing.T) {
+
+		t.Parallel()
+
+		value := LinkValue{
+			TargetPath: publicPathValue,
+			Type: NewInstantiatedCompositeStaticTypeComputeTypeID(
+				nil,
+				utils.TestLocation,
+				"SimpleStruct",
+				[]StaticType{
+					PrimitiveStaticTypeBool,
+				},
+			),
+		}
+
+		require.Equal(t,
+			common.TypeID("S.test.SimpleStruct<Bool>"),
+			value.Type.(CompositeStaticType).TypeID,
+		)
+
+		//nolint:gocritic
+		encoded := append(
+			expectedLinkEncodingPrefix[:],
+			// tag
+			0xd8, CBORTagCompositeStaticType,
+			// array, 3 items follow
+			0x83,
+			// tag
+			0xd8, CBORTagStringLocation,
+			// UTF-8 string, length 4
+			0x64,
+			// t, e, s, t
+			0x74, 0x65, 0x73, 0x74,
+			// UTF-8 string, length 12
+			0x6c,
+			// SimpleStruct
+			0x53, 0x69, 0x6d, 0x70, 0x6c, 0x65, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74,
+			// array, 1 item follows
+			0x81,
+			// tag
+			0xd8, CBORTagPrimitiveStaticType,
+			// bool
+			0x6,
+		)
+
+		testEncodeDecode(t,
+			encodeDecodeTest{
+				value:   value,
+				encoded: encoded,
+			},
+		)
+	})
+
 	t.Run("interface, struct, qualified identifier", func(t *testing.T) {
 
 		t.Parallel()
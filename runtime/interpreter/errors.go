@@ -352,6 +352,26 @@ func (e OverwriteError) Error() string {
 	)
 }
 
+// StorageMutatedDuringIterationError
+//
+type StorageMutatedDuringIterationError struct {
+	LocationRange
+}
+
+func (StorageMutatedDuringIterationError) Error() string {
+	return "storage modified during iteration"
+}
+
+// ContainerMutatedDuringIterationError
+//
+type ContainerMutatedDuringIterationError struct {
+	LocationRange
+}
+
+func (ContainerMutatedDuringIterationError) Error() string {
+	return "container modified during iteration"
+}
+
 // CyclicLinkError
 //
 type CyclicLinkError struct {
@@ -46,6 +46,7 @@ type FunctionValue interface {
 type InterpretedFunctionValue struct {
 	Interpreter      *Interpreter
 	ParameterList    *ast.ParameterList
+	FunctionBlock    *ast.FunctionBlock
 	Type             *sema.FunctionType
 	Activation       *VariableActivation
 	BeforeStatements []ast.Statement
@@ -57,6 +58,7 @@ type InterpretedFunctionValue struct {
 func NewInterpretedFunctionValue(
 	interpreter *Interpreter,
 	parameterList *ast.ParameterList,
+	functionBlock *ast.FunctionBlock,
 	functionType *sema.FunctionType,
 	lexicalScope *VariableActivation,
 	beforeStatements []ast.Statement,
@@ -70,6 +72,7 @@ func NewInterpretedFunctionValue(
 	return &InterpretedFunctionValue{
 		Interpreter:      interpreter,
 		ParameterList:    parameterList,
+		FunctionBlock:    functionBlock,
 		Type:             functionType,
 		Activation:       lexicalScope,
 		BeforeStatements: beforeStatements,
@@ -24,7 +24,9 @@ import (
 	"fmt"
 	"math"
 	goRuntime "runtime"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/fxamacker/cbor/v2"
 	"github.com/onflow/atree"
@@ -117,6 +119,33 @@ type OnInvokedFunctionReturnFunc func(
 	line int,
 )
 
+// OnFunctionEntryFunc is a function that is triggered when an interpreted function is entered.
+//
+type OnFunctionEntryFunc func(
+	inter *Interpreter,
+	functionBlock *ast.FunctionBlock,
+)
+
+// OnBranchFunc is a function that is triggered when a branch is about to be taken.
+//
+// The element is an if statement, a switch statement, a conditional expression,
+// or a nil-coalescing binary expression.
+//
+// For if statements and conditional expressions, branch 0 is the "then" branch,
+// and branch 1 is the "else" branch (even if there is no else block).
+//
+// For switch statements, the branch is the index of the executed case,
+// or the number of cases if no case matched.
+//
+// For nil-coalescing expressions, branch 0 is the left-hand side (the value is not nil),
+// and branch 1 is the right-hand side.
+//
+type OnBranchFunc func(
+	inter *Interpreter,
+	element ast.Element,
+	branch int,
+)
+
 // OnRecordTraceFunc is a function thats records a trace.
 type OnRecordTraceFunc func(
 	inter *Interpreter,
@@ -270,6 +299,7 @@ type ExitHandlerFunc func() error
 type CompositeTypeCode struct {
 	CompositeFunctions map[string]FunctionValue
 	DestructorFunction FunctionValue
+	RemovedFields      []string
 }
 
 type FunctionWrapper = func(inner FunctionValue) FunctionValue
@@ -337,6 +367,8 @@ type Interpreter struct {
 	onLoopIteration                OnLoopIterationFunc
 	onFunctionInvocation           OnFunctionInvocationFunc
 	onInvokedFunctionReturn        OnInvokedFunctionReturnFunc
+	onFunctionEntry                OnFunctionEntryFunc
+	onBranch                       OnBranchFunc
 	onRecordTrace                  OnRecordTraceFunc
 	onResourceOwnerChange          OnResourceOwnerChangeFunc
 	onMeterComputation             OnMeterComputationFunc
@@ -364,6 +396,20 @@ type Interpreter struct {
 	resourceVariables                    map[ResourceKindedValue]*Variable
 	memoryGauge                          common.MemoryGauge
 	CallStack                            *CallStack
+	storageIteration                     *storageIterationState
+	// iteratedContainers records the number of ongoing iterations of arrays and dictionaries,
+	// e.g. by `forEach`, so modifications of the containers during the iterations can be detected.
+	// It is shared by an interpreter and all its sub-interpreters
+	iteratedContainers map[atree.StorageID]int
+}
+
+// storageIterationState records if account storage is being iterated,
+// and if it was modified during the iteration.
+// It is shared by an interpreter and all its sub-interpreters
+//
+type storageIterationState struct {
+	iterating bool
+	mutated   bool
 }
 
 var _ common.MemoryGauge = &Interpreter{}
@@ -420,6 +466,26 @@ func WithOnInvokedFunctionReturnHandler(handler OnInvokedFunctionReturnFunc) Opt
 	}
 }
 
+// WithOnFunctionEntryHandler returns an interpreter option which sets
+// the given function as the function entry handler.
+//
+func WithOnFunctionEntryHandler(handler OnFunctionEntryFunc) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetOnFunctionEntryHandler(handler)
+		return nil
+	}
+}
+
+// WithOnBranchHandler returns an interpreter option which sets
+// the given function as the branch handler.
+//
+func WithOnBranchHandler(handler OnBranchFunc) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.SetOnBranchHandler(handler)
+		return nil
+	}
+}
+
 // WithMemoryGauge returns an interpreter option which sets
 // the given object as the memory gauge.
 //
@@ -677,6 +743,24 @@ func withReferencedResourceKindedValues(referencedResourceKindedValues Reference
 	}
 }
 
+// withStorageIterationState returns an interpreter option which sets the storage iteration state.
+//
+func withStorageIterationState(state *storageIterationState) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.storageIteration = state
+		return nil
+	}
+}
+
+// withIteratedContainers returns an interpreter option which sets the iterated containers.
+//
+func withIteratedContainers(iteratedContainers map[atree.StorageID]int) Option {
+	return func(interpreter *Interpreter) error {
+		interpreter.iteratedContainers = iteratedContainers
+		return nil
+	}
+}
+
 // WithDebugger returns an interpreter option which sets the given debugger
 //
 func WithDebugger(debugger *Debugger) Option {
@@ -721,6 +805,8 @@ func NewInterpreter(program *Program, location common.Location, options ...Optio
 			TypeRequirementCodes: map[sema.TypeID]WrapperCode{},
 		}),
 		withReferencedResourceKindedValues(map[atree.StorageID]map[ReferenceTrackedResourceKindedValue]struct{}{}),
+		withStorageIterationState(&storageIterationState{}),
+		withIteratedContainers(map[atree.StorageID]int{}),
 		WithInvalidatedResourceValidationEnabled(true),
 	}
 
@@ -771,6 +857,18 @@ func (interpreter *Interpreter) SetOnInvokedFunctionReturnHandler(function OnInv
 	interpreter.onInvokedFunctionReturn = function
 }
 
+// SetOnFunctionEntryHandler sets the function that is triggered when an interpreted function is entered.
+//
+func (interpreter *Interpreter) SetOnFunctionEntryHandler(function OnFunctionEntryFunc) {
+	interpreter.onFunctionEntry = function
+}
+
+// SetOnBranchHandler sets the function that is triggered when a branch is about to be taken.
+//
+func (interpreter *Interpreter) SetOnBranchHandler(function OnBranchFunc) {
+	interpreter.onBranch = function
+}
+
 // SetMemoryGauge sets the object as the memory gauge.
 //
 func (interpreter *Interpreter) SetMemoryGauge(memoryGauge common.MemoryGauge) {
@@ -935,6 +1033,15 @@ func (interpreter *Interpreter) findVariable(name string) *Variable {
 	return interpreter.activations.Find(name)
 }
 
+// FindVariable returns the variable with the given name in the current scope.
+// If the interpreter is not executing, the current scope is the program scope,
+// i.e. the variable is either a global, a predeclared value, or a built-in value,
+// like a number conversion function
+//
+func (interpreter *Interpreter) FindVariable(name string) *Variable {
+	return interpreter.findVariable(name)
+}
+
 func (interpreter *Interpreter) findOrDeclareVariable(name string) *Variable {
 	variable := interpreter.findVariable(name)
 	if variable == nil {
@@ -1323,6 +1430,7 @@ func (interpreter *Interpreter) functionDeclarationValue(
 	return NewInterpretedFunctionValue(
 		interpreter,
 		declaration.ParameterList,
+		declaration.FunctionBlock,
 		functionType,
 		lexicalScope,
 		beforeStatements,
@@ -1601,6 +1709,7 @@ func (interpreter *Interpreter) declareNonEnumCompositeValue(
 	compositeType := interpreter.Program.Elaboration.CompositeDeclarationTypes[declaration]
 
 	constructorType := &sema.FunctionType{
+		Purity:        compositeType.ConstructorPurity,
 		IsConstructor: true,
 		Parameters:    compositeType.ConstructorParameters,
 		ReturnTypeAnnotation: &sema.TypeAnnotation{
@@ -1697,6 +1806,7 @@ func (interpreter *Interpreter) declareNonEnumCompositeValue(
 	interpreter.typeCodes.CompositeCodes[compositeType.ID()] = CompositeTypeCode{
 		DestructorFunction: destructorFunction,
 		CompositeFunctions: functions,
+		RemovedFields:      compositeType.RemovedFields,
 	}
 
 	location := interpreter.Location
@@ -1761,11 +1871,12 @@ func (interpreter *Interpreter) declareNonEnumCompositeValue(
 					)
 				}
 
-				value := NewCompositeValue(
+				value := NewInstantiatedCompositeValue(
 					interpreter,
 					location,
 					qualifiedIdentifier,
 					declaration.CompositeKind,
+					interpreter.compositeTypeArguments(compositeType, invocation),
 					fields,
 					address,
 				)
@@ -1977,6 +2088,7 @@ func (interpreter *Interpreter) compositeInitializerFunction(
 	return NewInterpretedFunctionValue(
 		interpreter,
 		parameterList,
+		initializer.FunctionDeclaration.FunctionBlock,
 		functionType,
 		lexicalScope,
 		beforeStatements,
@@ -2020,6 +2132,7 @@ func (interpreter *Interpreter) compositeDestructorFunction(
 	return NewInterpretedFunctionValue(
 		interpreter,
 		nil,
+		destructor.FunctionDeclaration.FunctionBlock,
 		emptyFunctionType,
 		lexicalScope,
 		beforeStatements,
@@ -2029,6 +2142,34 @@ func (interpreter *Interpreter) compositeDestructorFunction(
 	)
 }
 
+// compositeTypeArguments returns the type arguments of the constructor invocation
+// of a generic composite type, in the order of the type parameters.
+// It returns nil if the composite type is not generic
+//
+func (interpreter *Interpreter) compositeTypeArguments(
+	compositeType *sema.CompositeType,
+	invocation Invocation,
+) []StaticType {
+	typeParameters := compositeType.TypeParameters()
+	if len(typeParameters) == 0 {
+		return nil
+	}
+
+	typeArguments := make([]StaticType, len(typeParameters))
+	for i, typeParameter := range typeParameters {
+		if invocation.TypeParameterTypes == nil {
+			panic(errors.NewUnreachableError())
+		}
+		typeArgument, ok := invocation.TypeParameterTypes.Get(typeParameter)
+		if !ok {
+			panic(errors.NewUnreachableError())
+		}
+		typeArguments[i] = ConvertSemaToStaticType(interpreter, typeArgument)
+	}
+
+	return typeArguments
+}
+
 func (interpreter *Interpreter) compositeFunctions(
 	compositeDeclaration *ast.CompositeDeclaration,
 	lexicalScope *VariableActivation,
@@ -2105,6 +2246,7 @@ func (interpreter *Interpreter) compositeFunction(
 	return NewInterpretedFunctionValue(
 		interpreter,
 		parameterList,
+		functionDeclaration.FunctionBlock,
 		functionType,
 		lexicalScope,
 		beforeStatements,
@@ -2124,6 +2266,11 @@ func (interpreter *Interpreter) VisitEnumCaseDeclaration(_ *ast.EnumCaseDeclarat
 	panic(errors.NewUnreachableError())
 }
 
+func (interpreter *Interpreter) VisitTypeAliasDeclaration(_ *ast.TypeAliasDeclaration) ast.Repr {
+	// type aliases aren't interpreted, they are resolved by the checker
+	panic(errors.NewUnreachableError())
+}
+
 func (interpreter *Interpreter) CheckValueTransferTargetType(value Value, targetType sema.Type) bool {
 
 	if targetType == nil {
@@ -2698,6 +2845,8 @@ func (interpreter *Interpreter) NewSubInterpreter(
 		WithOnLoopIterationHandler(interpreter.onLoopIteration),
 		WithOnFunctionInvocationHandler(interpreter.onFunctionInvocation),
 		WithOnInvokedFunctionReturnHandler(interpreter.onInvokedFunctionReturn),
+		WithOnFunctionEntryHandler(interpreter.onFunctionEntry),
+		WithOnBranchHandler(interpreter.onBranch),
 		WithInjectedCompositeFieldsHandler(interpreter.injectedCompositeFieldsHandler),
 		WithContractValueHandler(interpreter.contractValueHandler),
 		WithImportLocationHandler(interpreter.importLocationHandler),
@@ -2708,6 +2857,8 @@ func (interpreter *Interpreter) NewSubInterpreter(
 		WithAtreeStorageValidationEnabled(interpreter.atreeStorageValidationEnabled),
 		withTypeCodes(interpreter.typeCodes),
 		withReferencedResourceKindedValues(interpreter.referencedResourceKindedValues),
+		withStorageIterationState(interpreter.storageIteration),
+		withIteratedContainers(interpreter.iteratedContainers),
 		WithPublicAccountHandler(interpreter.publicAccountHandler),
 		WithPublicKeyValidationHandler(interpreter.PublicKeyValidationHandler),
 		WithSignatureVerificationHandler(interpreter.SignatureVerificationHandler),
@@ -2760,12 +2911,66 @@ func (interpreter *Interpreter) ReadStored(
 	return accountStorage.ReadValue(interpreter, identifier)
 }
 
+// readStoredStaticType returns the static type of the value stored under the given domain and identifier,
+// without converting the stored value.
+// Returns nil if no value is stored.
+//
+func (interpreter *Interpreter) readStoredStaticType(
+	storageAddress common.Address,
+	domain string,
+	identifier string,
+) StaticType {
+	accountStorage := interpreter.Storage.GetStorageMap(storageAddress, domain, false)
+	if accountStorage == nil {
+		return nil
+	}
+	return accountStorage.ReadStaticType(interpreter, identifier)
+}
+
+// storedValueFootprint returns the number of bytes used to store the value
+// under the given domain and identifier, including all slabs reachable from it.
+// Returns false if no value is stored.
+//
+func (interpreter *Interpreter) storedValueFootprint(
+	storageAddress common.Address,
+	domain string,
+	identifier string,
+) (uint64, bool) {
+	accountStorage := interpreter.Storage.GetStorageMap(storageAddress, domain, false)
+	if accountStorage == nil {
+		return 0, false
+	}
+	return accountStorage.ValueFootprint(interpreter, identifier)
+}
+
+// swapStored writes the given value under the given domain and identifier,
+// and returns the value which was stored before and its storable, if any.
+// The existing value is not removed from storage.
+//
+func (interpreter *Interpreter) swapStored(
+	storageAddress common.Address,
+	domain string,
+	identifier string,
+	value Value,
+) (Value, atree.Storable) {
+	if interpreter.storageIteration.iterating {
+		interpreter.storageIteration.mutated = true
+	}
+
+	accountStorage := interpreter.Storage.GetStorageMap(storageAddress, domain, true)
+	return accountStorage.SwapValue(interpreter, identifier, value)
+}
+
 func (interpreter *Interpreter) writeStored(
 	storageAddress common.Address,
 	domain string,
 	identifier string,
 	value Value,
 ) {
+	if interpreter.storageIteration.iterating {
+		interpreter.storageIteration.mutated = true
+	}
+
 	accountStorage := interpreter.Storage.GetStorageMap(storageAddress, domain, true)
 	accountStorage.WriteValue(interpreter, identifier, value)
 }
@@ -3483,6 +3688,7 @@ var typeFunction = NewUnmeteredHostFunctionValue(
 		return NewTypeValue(invocation.Interpreter, staticType)
 	},
 	&sema.FunctionType{
+		Purity:               sema.FunctionPurityView,
 		ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.MetaType),
 	},
 )
@@ -3508,6 +3714,7 @@ var stringFunction = func() Value {
 			return emptyString
 		},
 		&sema.FunctionType{
+			Purity: sema.FunctionPurityView,
 			ReturnTypeAnnotation: sema.NewTypeAnnotation(
 				sema.StringType,
 			),
@@ -3549,6 +3756,143 @@ var stringFunction = func() Value {
 		),
 	)
 
+	addMember(
+		sema.StringTypeJoinFunctionName,
+		NewUnmeteredHostFunctionValue(
+			func(invocation Invocation) Value {
+				stringArray, ok := invocation.Arguments[0].(*ArrayValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				separator, ok := invocation.Arguments[1].(*StringValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				inter := invocation.Interpreter
+
+				var length int
+				count := 0
+				stringArray.Iterate(inter, func(element Value) (resume bool) {
+					str, ok := element.(*StringValue)
+					if !ok {
+						panic(errors.NewUnreachableError())
+					}
+					if count > 0 {
+						length = safeAdd(length, len(separator.Str))
+					}
+					length = safeAdd(length, len(str.Str))
+					count++
+					return true
+				})
+
+				memoryUsage := common.NewStringMemoryUsage(length)
+
+				return NewStringValue(
+					inter,
+					memoryUsage,
+					func() string {
+						var sb strings.Builder
+						sb.Grow(length)
+
+						first := true
+						stringArray.Iterate(inter, func(element Value) (resume bool) {
+							if !first {
+								sb.WriteString(separator.Str)
+							}
+							first = false
+							sb.WriteString(element.(*StringValue).Str)
+							return true
+						})
+
+						return sb.String()
+					},
+				)
+			},
+			sema.StringTypeJoinFunctionType,
+		),
+	)
+
+	addMember(
+		sema.StringTypeFromCharactersFunctionName,
+		NewUnmeteredHostFunctionValue(
+			func(invocation Invocation) Value {
+				characterArray, ok := invocation.Arguments[0].(*ArrayValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				inter := invocation.Interpreter
+
+				var length int
+				characterArray.Iterate(inter, func(element Value) (resume bool) {
+					character, ok := element.(CharacterValue)
+					if !ok {
+						panic(errors.NewUnreachableError())
+					}
+					length = safeAdd(length, len(character))
+					return true
+				})
+
+				memoryUsage := common.NewStringMemoryUsage(length)
+
+				return NewStringValue(
+					inter,
+					memoryUsage,
+					func() string {
+						var sb strings.Builder
+						sb.Grow(length)
+
+						characterArray.Iterate(inter, func(element Value) (resume bool) {
+							sb.WriteString(string(element.(CharacterValue)))
+							return true
+						})
+
+						return sb.String()
+					},
+				)
+			},
+			sema.StringTypeFromCharactersFunctionType,
+		),
+	)
+
+	addMember(
+		sema.StringTypeFromUTF8FunctionName,
+		NewUnmeteredHostFunctionValue(
+			func(invocation Invocation) Value {
+				argument, ok := invocation.Arguments[0].(*ArrayValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				inter := invocation.Interpreter
+
+				bytes, err := ByteArrayValueToByteSlice(inter, argument)
+				if err != nil {
+					panic(err)
+				}
+
+				if !utf8.Valid(bytes) {
+					return NewNilValue(inter)
+				}
+
+				memoryUsage := common.NewStringMemoryUsage(len(bytes))
+
+				str := NewStringValue(
+					inter,
+					memoryUsage,
+					func() string {
+						return string(bytes)
+					},
+				)
+
+				return NewSomeValueNonCopying(inter, str)
+			},
+			sema.StringTypeFromUTF8FunctionType,
+		),
+	)
+
 	return functionValue
 }()
 
@@ -3703,9 +4047,11 @@ func (interpreter *Interpreter) authAccountTypeFunction(addressValue AddressValu
 			domain := path.Domain.Identifier()
 			identifier := path.Identifier
 
-			value := interpreter.ReadStored(address, domain, identifier)
+			// Only read the type of the stored value, the value does not have to be converted
 
-			if value == nil {
+			staticType := interpreter.readStoredStaticType(address, domain, identifier)
+
+			if staticType == nil {
 				return NewNilValue(invocation.Interpreter)
 			}
 
@@ -3713,7 +4059,7 @@ func (interpreter *Interpreter) authAccountTypeFunction(addressValue AddressValu
 				invocation.Interpreter,
 				NewTypeValue(
 					invocation.Interpreter,
-					value.StaticType(invocation.Interpreter),
+					staticType,
 				),
 			)
 		},
@@ -3722,6 +4068,159 @@ func (interpreter *Interpreter) authAccountTypeFunction(addressValue AddressValu
 	)
 }
 
+func (interpreter *Interpreter) authAccountStorageSizeFunction(addressValue AddressValue) *HostFunctionValue {
+
+	// Converted addresses can be cached and don't have to be recomputed on each function invocation
+	address := addressValue.ToAddress()
+
+	return NewHostFunctionValue(
+		interpreter,
+		func(invocation Invocation) Value {
+			path, ok := invocation.Arguments[0].(PathValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+
+			domain := path.Domain.Identifier()
+			identifier := path.Identifier
+
+			// Only determine the size of the stored value, the value does not have to be converted
+
+			size, ok := interpreter.storedValueFootprint(address, domain, identifier)
+			if !ok {
+				return NewNilValue(invocation.Interpreter)
+			}
+
+			return NewSomeValueNonCopying(
+				invocation.Interpreter,
+				NewUInt64Value(
+					invocation.Interpreter,
+					func() uint64 {
+						return size
+					},
+				),
+			)
+		},
+
+		sema.AuthAccountTypeStorageSizeFunctionType,
+	)
+}
+
+func (interpreter *Interpreter) authAccountCheckFunction(addressValue AddressValue) *HostFunctionValue {
+
+	// Converted addresses can be cached and don't have to be recomputed on each function invocation
+	address := addressValue.ToAddress()
+
+	return NewHostFunctionValue(
+		interpreter,
+		func(invocation Invocation) Value {
+			path, ok := invocation.Arguments[0].(PathValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+
+			domain := path.Domain.Identifier()
+			identifier := path.Identifier
+
+			staticType := interpreter.readStoredStaticType(address, domain, identifier)
+
+			if staticType == nil {
+				return NewBoolValue(invocation.Interpreter, false)
+			}
+
+			typeParameterPair := invocation.TypeParameterTypes.Oldest()
+			if typeParameterPair == nil {
+				panic(errors.NewUnreachableError())
+			}
+
+			ty := typeParameterPair.Value
+
+			return NewBoolValue(
+				invocation.Interpreter,
+				interpreter.IsSubTypeOfSemaType(staticType, ty),
+			)
+		},
+		sema.AuthAccountTypeCheckFunctionType,
+	)
+}
+
+func (interpreter *Interpreter) authAccountSwapFunction(addressValue AddressValue) *HostFunctionValue {
+
+	// Converted addresses can be cached and don't have to be recomputed on each function invocation
+	address := addressValue.ToAddress()
+
+	return NewHostFunctionValue(
+		interpreter,
+		func(invocation Invocation) Value {
+			value := invocation.Arguments[0]
+
+			path, ok := invocation.Arguments[1].(PathValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+
+			domain := path.Domain.Identifier()
+			identifier := path.Identifier
+
+			inter := invocation.Interpreter
+			getLocationRange := invocation.GetLocationRange
+
+			// If there is a value stored for the given path,
+			// check that it satisfies the type given as the type argument,
+			// before the storage is modified
+
+			existingStaticType := interpreter.readStoredStaticType(address, domain, identifier)
+
+			if existingStaticType != nil {
+				typeParameterPair := invocation.TypeParameterTypes.Oldest()
+				if typeParameterPair == nil {
+					panic(errors.NewUnreachableError())
+				}
+
+				ty := typeParameterPair.Value
+
+				if !interpreter.IsSubTypeOfSemaType(existingStaticType, ty) {
+					panic(ForceCastTypeMismatchError{
+						ExpectedType:  ty,
+						LocationRange: getLocationRange(),
+					})
+				}
+			}
+
+			value = value.Transfer(
+				inter,
+				getLocationRange,
+				atree.Address(address),
+				true,
+				nil,
+			)
+
+			// Replace the existing value with the new value in the storage map.
+			// The existing value is still stored, it is moved out below
+
+			existingValue, existingStorable := interpreter.swapStored(address, domain, identifier, value)
+
+			if existingValue == nil {
+				return NewNilValue(inter)
+			}
+
+			// Move the existing value out of storage.
+			// Like in load, this transfers every slab of the value
+
+			transferredValue := existingValue.Transfer(
+				inter,
+				getLocationRange,
+				atree.Address{},
+				true,
+				existingStorable,
+			)
+
+			return NewSomeValueNonCopying(inter, transferredValue)
+		},
+		sema.AuthAccountTypeSwapFunctionType,
+	)
+}
+
 func (interpreter *Interpreter) authAccountLoadFunction(addressValue AddressValue) *HostFunctionValue {
 	return interpreter.authAccountReadFunction(addressValue, true)
 }
@@ -3978,6 +4477,151 @@ func (interpreter *Interpreter) authAccountUnlinkFunction(addressValue AddressVa
 	)
 }
 
+// accountPaths returns all paths of the given domain of the account
+// which store an object or a capability
+//
+func (interpreter *Interpreter) accountPaths(
+	address common.Address,
+	domain common.PathDomain,
+	pathType StaticType,
+) *ArrayValue {
+
+	var paths []Value
+
+	storageMap := interpreter.Storage.GetStorageMap(address, domain.Identifier(), false)
+	if storageMap != nil {
+		iterator := storageMap.Iterator(interpreter)
+		for key := iterator.NextKey(); key != ""; key = iterator.NextKey() {
+			interpreter.ReportComputation(common.ComputationKindLoop, 1)
+
+			paths = append(paths, NewPathValue(interpreter, domain, key))
+		}
+	}
+
+	return NewArrayValue(
+		interpreter,
+		NewVariableSizedStaticType(interpreter, pathType),
+		common.Address{},
+		paths...,
+	)
+}
+
+// accountForEachFunction returns a function which iterates over the given domain of the account,
+// and calls the given function with the path and the type of each stored object.
+// For capability paths, the type of the capability is passed.
+//
+// Iteration stops when the given function returns false.
+// If the storage is modified during iteration, and the given function returns true,
+// a StorageMutatedDuringIterationError is reported
+//
+func (interpreter *Interpreter) accountForEachFunction(
+	addressValue AddressValue,
+	domain common.PathDomain,
+	functionType *sema.FunctionType,
+) *HostFunctionValue {
+
+	// Converted addresses can be cached and don't have to be recomputed on each function invocation
+	address := addressValue.ToAddress()
+
+	iterationFunctionType, ok := functionType.Parameters[0].TypeAnnotation.Type.(*sema.FunctionType)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	argumentTypes := make([]sema.Type, len(iterationFunctionType.Parameters))
+	for i, parameter := range iterationFunctionType.Parameters {
+		argumentTypes[i] = parameter.TypeAnnotation.Type
+	}
+
+	return NewHostFunctionValue(
+		interpreter,
+		func(invocation Invocation) Value {
+			inter := invocation.Interpreter
+			getLocationRange := invocation.GetLocationRange
+
+			function, ok := invocation.Arguments[0].(FunctionValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+
+			storageMap := inter.Storage.GetStorageMap(address, domain.Identifier(), false)
+			if storageMap == nil {
+				return NewVoidValue(inter)
+			}
+
+			// Record that the storage is iterated,
+			// so modifications during the iteration can be detected.
+			// Iterations may be nested, so restore the outer state afterwards
+
+			state := inter.storageIteration
+			wasIterating := state.iterating
+			wasMutated := state.mutated
+
+			state.iterating = true
+			state.mutated = false
+
+			defer func() {
+				state.iterating = wasIterating
+				state.mutated = wasMutated || state.mutated
+			}()
+
+			iterator := storageMap.Iterator(inter)
+
+			for {
+				key, value := iterator.Next()
+				if value == nil {
+					break
+				}
+
+				inter.ReportComputation(common.ComputationKindLoop, 1)
+
+				var staticType StaticType
+				if domain == common.PathDomainStorage {
+					staticType = value.StaticType(inter)
+				} else {
+					link, ok := value.(LinkValue)
+					if !ok {
+						continue
+					}
+					staticType = NewCapabilityStaticType(inter, link.Type)
+				}
+
+				iterationInvocation := NewInvocation(
+					inter,
+					nil,
+					[]Value{
+						NewPathValue(inter, domain, key),
+						NewTypeValue(inter, staticType),
+					},
+					argumentTypes,
+					nil,
+					getLocationRange,
+				)
+
+				shouldContinue, ok := function.invoke(iterationInvocation).(BoolValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				if !shouldContinue {
+					break
+				}
+
+				// The iterator cannot continue if the storage was modified
+
+				if state.mutated {
+					panic(StorageMutatedDuringIterationError{
+						LocationRange: getLocationRange(),
+					})
+				}
+			}
+
+			return NewVoidValue(inter)
+		},
+		functionType,
+	)
+}
+
 func (interpreter *Interpreter) capabilityBorrowFunction(
 	addressValue AddressValue,
 	pathValue PathValue,
@@ -4194,6 +4838,63 @@ func (interpreter *Interpreter) MustConvertStaticToSemaType(staticType StaticTyp
 	return semaType
 }
 
+// resolveType returns the given type, with the type parameters it refers to
+// substituted by the types bound in the current activation, if any.
+//
+// The types of expressions in generic functions and composite types may refer to type parameters,
+// which are only known when the function is invoked, or the composite value is created.
+//
+func (interpreter *Interpreter) resolveType(ty sema.Type) sema.Type {
+	if ty == nil {
+		return nil
+	}
+
+	activation := interpreter.activations.Current()
+	if activation == nil {
+		return ty
+	}
+
+	typeArguments := activation.TypeArguments()
+	if typeArguments == nil {
+		return ty
+	}
+
+	resolvedType := ty.Resolve(typeArguments)
+	if resolvedType == nil {
+		return ty
+	}
+
+	return resolvedType
+}
+
+func (interpreter *Interpreter) resolveTypes(types []sema.Type) []sema.Type {
+	activation := interpreter.activations.Current()
+	if activation == nil || activation.TypeArguments() == nil {
+		return types
+	}
+
+	resolvedTypes := make([]sema.Type, len(types))
+	for i, ty := range types {
+		resolvedTypes[i] = interpreter.resolveType(ty)
+	}
+	return resolvedTypes
+}
+
+func (interpreter *Interpreter) resolveTypeArguments(
+	typeArguments *sema.TypeParameterTypeOrderedMap,
+) *sema.TypeParameterTypeOrderedMap {
+	activation := interpreter.activations.Current()
+	if typeArguments == nil || activation == nil || activation.TypeArguments() == nil {
+		return typeArguments
+	}
+
+	resolvedTypeArguments := sema.NewTypeParameterTypeOrderedMap()
+	typeArguments.Foreach(func(typeParameter *sema.TypeParameter, ty sema.Type) {
+		resolvedTypeArguments.Set(typeParameter, interpreter.resolveType(ty))
+	})
+	return resolvedTypeArguments
+}
+
 func (interpreter *Interpreter) getElaboration(location common.Location) *sema.Elaboration {
 
 	// Ensure the program for this location is loaded,
@@ -4325,6 +5026,22 @@ func (interpreter *Interpreter) reportInvokedFunctionReturn(line int) {
 	interpreter.onInvokedFunctionReturn(interpreter, line)
 }
 
+func (interpreter *Interpreter) reportFunctionEntry(functionBlock *ast.FunctionBlock) {
+	if interpreter.onFunctionEntry == nil || functionBlock == nil {
+		return
+	}
+
+	interpreter.onFunctionEntry(interpreter, functionBlock)
+}
+
+func (interpreter *Interpreter) reportBranch(element ast.Element, branch int) {
+	if interpreter.onBranch == nil {
+		return
+	}
+
+	interpreter.onBranch(interpreter, element, branch)
+}
+
 func (interpreter *Interpreter) ReportComputation(compKind common.ComputationKind, intensity uint) {
 	if interpreter.onMeterComputation != nil {
 		interpreter.onMeterComputation(compKind, intensity)
@@ -4435,6 +5152,39 @@ func (interpreter *Interpreter) checkContainerMutation(
 	}
 }
 
+// withContainerIteration records that the container with the given storage ID
+// is iterated while the given function is called.
+// Iterations may be nested
+//
+func (interpreter *Interpreter) withContainerIteration(storageID atree.StorageID, f func()) {
+	interpreter.iteratedContainers[storageID]++
+
+	defer func() {
+		count := interpreter.iteratedContainers[storageID] - 1
+		if count > 0 {
+			interpreter.iteratedContainers[storageID] = count
+		} else {
+			delete(interpreter.iteratedContainers, storageID)
+		}
+	}()
+
+	f()
+}
+
+// checkContainerNotIterated reports an error if the container with the given storage ID
+// is modified while it is iterated
+//
+func (interpreter *Interpreter) checkContainerNotIterated(
+	storageID atree.StorageID,
+	getLocationRange func() LocationRange,
+) {
+	if _, ok := interpreter.iteratedContainers[storageID]; ok {
+		panic(ContainerMutatedDuringIterationError{
+			LocationRange: getLocationRange(),
+		})
+	}
+}
+
 func (interpreter *Interpreter) checkReferencedResourceNotDestroyed(value Value, getLocationRange func() LocationRange) {
 	resourceKindedValue, ok := value.(ResourceKindedValue)
 	if !ok || !resourceKindedValue.IsDestroyed() {
@@ -20,6 +20,7 @@ package interpreter
 
 import (
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/onflow/cadence/fixedpoint"
@@ -378,13 +379,20 @@ func (interpreter *Interpreter) VisitBinaryExpression(expression *ast.BinaryExpr
 
 		// only evaluate right-hand side if left-hand side is nil
 		if some, ok := leftValue.(*SomeValue); ok {
+			interpreter.reportBranch(expression, 0)
 			return some.InnerValue(interpreter, getLocationRange)
 		}
 
+		interpreter.reportBranch(expression, 1)
+
 		value := rightValue()
 
-		rightType := interpreter.Program.Elaboration.BinaryExpressionRightTypes[expression]
-		resultType := interpreter.Program.Elaboration.BinaryExpressionResultTypes[expression]
+		rightType := interpreter.resolveType(
+			interpreter.Program.Elaboration.BinaryExpressionRightTypes[expression],
+		)
+		resultType := interpreter.resolveType(
+			interpreter.Program.Elaboration.BinaryExpressionResultTypes[expression],
+		)
 
 		// NOTE: important to convert both any and optional
 		return interpreter.ConvertAndBox(getLocationRange, value, rightType, resultType)
@@ -602,11 +610,79 @@ func (interpreter *Interpreter) VisitStringExpression(expression *ast.StringExpr
 	return NewUnmeteredStringValue(expression.Value)
 }
 
+func (interpreter *Interpreter) VisitStringTemplateExpression(expression *ast.StringTemplateExpression) ast.Repr {
+	values := interpreter.visitExpressionsNonCopying(expression.Expressions)
+	return interpreter.NewStringTemplateValue(expression.Values, values)
+}
+
+// NewStringTemplateValue returns the string of a string template:
+// the given literal parts, with the string representations of the given values embedded.
+// There is always one more literal part than there are values
+//
+func (interpreter *Interpreter) NewStringTemplateValue(literals []string, values []Value) *StringValue {
+
+	// NOTE: the literal parts are already metered in lexer/parser,
+	// the string representations of the embedded values are metered when they are produced
+
+	parts := make([]string, len(values))
+	length := 0
+	for i, value := range values {
+		part := interpreter.stringTemplateValuePart(value)
+		parts[i] = part
+		length = safeAdd(length, len(part))
+	}
+
+	for _, literal := range literals {
+		length = safeAdd(length, len(literal))
+	}
+
+	return NewStringValue(
+		interpreter,
+		common.NewStringMemoryUsage(length),
+		func() string {
+			var sb strings.Builder
+			sb.Grow(length)
+
+			for i, literal := range literals {
+				sb.WriteString(literal)
+				if i < len(parts) {
+					sb.WriteString(parts[i])
+				}
+			}
+
+			return sb.String()
+		},
+	)
+}
+
+// stringTemplateValuePart returns the string representation of a value embedded in a string template.
+//
+// Strings and characters are embedded as-is, i.e. without quotes.
+// All other values which can be embedded (booleans, numbers, addresses, and paths),
+// are embedded as their string representation, which is also returned by `toString`.
+//
+func (interpreter *Interpreter) stringTemplateValuePart(value Value) string {
+	switch value := value.(type) {
+	case *StringValue:
+		return value.Str
+
+	case CharacterValue:
+		return string(value)
+
+	default:
+		return value.MeteredString(interpreter, SeenReferences{})
+	}
+}
+
 func (interpreter *Interpreter) VisitArrayExpression(expression *ast.ArrayExpression) ast.Repr {
 	values := interpreter.visitExpressionsNonCopying(expression.Values)
 
-	argumentTypes := interpreter.Program.Elaboration.ArrayExpressionArgumentTypes[expression]
-	arrayType := interpreter.Program.Elaboration.ArrayExpressionArrayType[expression]
+	argumentTypes := interpreter.resolveTypes(
+		interpreter.Program.Elaboration.ArrayExpressionArgumentTypes[expression],
+	)
+	arrayType := interpreter.resolveType(
+		interpreter.Program.Elaboration.ArrayExpressionArrayType[expression],
+	).(sema.ArrayType)
 	elementType := arrayType.ElementType(false)
 
 	copies := make([]Value, len(values))
@@ -632,7 +708,9 @@ func (interpreter *Interpreter) VisitDictionaryExpression(expression *ast.Dictio
 	values := interpreter.visitEntries(expression.Entries)
 
 	entryTypes := interpreter.Program.Elaboration.DictionaryExpressionEntryTypes[expression]
-	dictionaryType := interpreter.Program.Elaboration.DictionaryExpressionType[expression]
+	dictionaryType := interpreter.resolveType(
+		interpreter.Program.Elaboration.DictionaryExpressionType[expression],
+	).(*sema.DictionaryType)
 
 	var keyValuePairs []Value
 
@@ -642,14 +720,14 @@ func (interpreter *Interpreter) VisitDictionaryExpression(expression *ast.Dictio
 
 		key := interpreter.transferAndConvert(
 			dictionaryEntryValues.Key,
-			entryType.KeyType,
+			interpreter.resolveType(entryType.KeyType),
 			dictionaryType.KeyType,
 			locationRangeGetter(interpreter, interpreter.Location, entry.Key),
 		)
 
 		value := interpreter.transferAndConvert(
 			dictionaryEntryValues.Value,
-			entryType.ValueType,
+			interpreter.resolveType(entryType.ValueType),
 			dictionaryType.ValueType,
 			locationRangeGetter(interpreter, interpreter.Location, entry.Value),
 		)
@@ -689,8 +767,10 @@ func (interpreter *Interpreter) VisitConditionalExpression(expression *ast.Condi
 		panic(errors.NewUnreachableError())
 	}
 	if value {
+		interpreter.reportBranch(expression, 0)
 		return interpreter.evalExpression(expression.Then)
 	} else {
+		interpreter.reportBranch(expression, 1)
 		return interpreter.evalExpression(expression.Else)
 	}
 }
@@ -751,12 +831,15 @@ func (interpreter *Interpreter) VisitInvocationExpression(invocationExpression *
 
 	arguments := interpreter.visitExpressionsNonCopying(argumentExpressions)
 
-	typeParameterTypes :=
-		interpreter.Program.Elaboration.InvocationExpressionTypeArguments[invocationExpression]
-	argumentTypes :=
-		interpreter.Program.Elaboration.InvocationExpressionArgumentTypes[invocationExpression]
-	parameterTypes :=
-		interpreter.Program.Elaboration.InvocationExpressionParameterTypes[invocationExpression]
+	typeParameterTypes := interpreter.resolveTypeArguments(
+		interpreter.Program.Elaboration.InvocationExpressionTypeArguments[invocationExpression],
+	)
+	argumentTypes := interpreter.resolveTypes(
+		interpreter.Program.Elaboration.InvocationExpressionArgumentTypes[invocationExpression],
+	)
+	parameterTypes := interpreter.resolveTypes(
+		interpreter.Program.Elaboration.InvocationExpressionParameterTypes[invocationExpression],
+	)
 
 	line := invocationExpression.StartPosition().Line
 
@@ -818,7 +901,9 @@ func (interpreter *Interpreter) VisitFunctionExpression(expression *ast.Function
 	// lexical scope: variables in functions are bound to what is visible at declaration time
 	lexicalScope := interpreter.activations.CurrentOrNew()
 
-	functionType := interpreter.Program.Elaboration.FunctionExpressionFunctionType[expression]
+	functionType := interpreter.resolveType(
+		interpreter.Program.Elaboration.FunctionExpressionFunctionType[expression],
+	).(*sema.FunctionType)
 
 	var preConditions ast.Conditions
 	if expression.FunctionBlock.PreConditions != nil {
@@ -841,6 +926,7 @@ func (interpreter *Interpreter) VisitFunctionExpression(expression *ast.Function
 	return NewInterpretedFunctionValue(
 		interpreter,
 		expression.ParameterList,
+		expression.FunctionBlock,
 		functionType,
 		lexicalScope,
 		beforeStatements,
@@ -855,7 +941,9 @@ func (interpreter *Interpreter) VisitCastingExpression(expression *ast.CastingEx
 
 	getLocationRange := locationRangeGetter(interpreter, interpreter.Location, expression.Expression)
 
-	expectedType := interpreter.Program.Elaboration.CastingTargetTypes[expression]
+	expectedType := interpreter.resolveType(
+		interpreter.Program.Elaboration.CastingTargetTypes[expression],
+	)
 
 	switch expression.Operation {
 	case ast.OperationFailableCast, ast.OperationForceCast:
@@ -889,7 +977,9 @@ func (interpreter *Interpreter) VisitCastingExpression(expression *ast.CastingEx
 		}
 
 	case ast.OperationCast:
-		staticValueType := interpreter.Program.Elaboration.CastingStaticValueTypes[expression]
+		staticValueType := interpreter.resolveType(
+			interpreter.Program.Elaboration.CastingStaticValueTypes[expression],
+		)
 		// The cast may upcast to an optional type, e.g. `1 as Int?`, so box
 		return interpreter.ConvertAndBox(getLocationRange, value, staticValueType, expectedType)
 
@@ -916,7 +1006,9 @@ func (interpreter *Interpreter) VisitDestroyExpression(expression *ast.DestroyEx
 
 func (interpreter *Interpreter) VisitReferenceExpression(referenceExpression *ast.ReferenceExpression) ast.Repr {
 
-	borrowType := interpreter.Program.Elaboration.ReferenceExpressionBorrowTypes[referenceExpression]
+	borrowType := interpreter.resolveType(
+		interpreter.Program.Elaboration.ReferenceExpressionBorrowTypes[referenceExpression],
+	)
 
 	result := interpreter.evalExpression(referenceExpression.Expression)
 
@@ -22,6 +22,7 @@ import (
 	"github.com/onflow/atree"
 
 	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/errors"
 	"github.com/onflow/cadence/runtime/sema"
 )
 
@@ -110,6 +111,43 @@ func (interpreter *Interpreter) invokeFunctionValue(
 	return function.invoke(invocation)
 }
 
+// invokeFunctionArgument invokes the given function value, which was passed as an argument to a host function,
+// e.g. the function passed to an array's `forEach` function.
+//
+// The arguments are transferred and converted to the parameter types of the given function type,
+// which is the static type of the argument.
+//
+func (interpreter *Interpreter) invokeFunctionArgument(
+	function FunctionValue,
+	functionType *sema.FunctionType,
+	arguments []Value,
+	argumentTypes []sema.Type,
+	getLocationRange func() LocationRange,
+) Value {
+
+	transferredArguments := make([]Value, len(arguments))
+
+	for i, argument := range arguments {
+		transferredArguments[i] = interpreter.transferAndConvert(
+			argument,
+			argumentTypes[i],
+			functionType.Parameters[i].TypeAnnotation.Type,
+			getLocationRange,
+		)
+	}
+
+	invocation := NewInvocation(
+		interpreter,
+		nil,
+		transferredArguments,
+		argumentTypes,
+		nil,
+		getLocationRange,
+	)
+
+	return function.invoke(invocation)
+}
+
 func (interpreter *Interpreter) invokeInterpretedFunction(
 	function *InterpretedFunctionValue,
 	invocation Invocation,
@@ -118,8 +156,10 @@ func (interpreter *Interpreter) invokeInterpretedFunction(
 	// Start a new activation record.
 	// Lexical scope: use the function declaration's activation record,
 	// not the current one (which would be dynamic scope)
-	interpreter.activations.PushNewWithParent(function.Activation)
-	interpreter.activations.Current().isFunction = true
+	activation := interpreter.activations.PushNewWithParent(function.Activation)
+	activation.isFunction = true
+
+	interpreter.bindTypeArguments(activation, function.Type, invocation)
 
 	interpreter.CallStack.Push(invocation)
 
@@ -131,6 +171,49 @@ func (interpreter *Interpreter) invokeInterpretedFunction(
 	return interpreter.invokeInterpretedFunctionActivated(function, invocation.Arguments)
 }
 
+// bindTypeArguments binds the type arguments of the invocation in the function's activation,
+// so that types which refer to type parameters can be resolved when the function is executed,
+// for example the element type of the array literal `[x]` in a generic function `fun f<T>(x: T): [T]`.
+//
+// The type arguments are the type arguments of the invocation, if the function is generic,
+// and the type arguments of the type of `self`, if it is an instantiation of a generic composite type.
+//
+func (interpreter *Interpreter) bindTypeArguments(
+	activation *VariableActivation,
+	functionType *sema.FunctionType,
+	invocation Invocation,
+) {
+	if self, ok := invocation.Self.(*CompositeValue); ok && len(self.TypeArguments) > 0 {
+		compositeType, err := interpreter.GetCompositeType(
+			self.Location,
+			self.QualifiedIdentifier,
+			self.TypeID(),
+		)
+		if err != nil {
+			panic(err)
+		}
+
+		typeParameters := compositeType.TypeParameters()
+		if len(typeParameters) != len(self.TypeArguments) {
+			panic(errors.NewUnreachableError())
+		}
+
+		typeArguments := sema.NewTypeParameterTypeOrderedMap()
+		for i, typeParameter := range typeParameters {
+			typeArguments.Set(
+				typeParameter,
+				interpreter.MustConvertStaticToSemaType(self.TypeArguments[i]),
+			)
+		}
+
+		activation.BindTypeArguments(typeArguments)
+	}
+
+	if len(functionType.TypeParameters) > 0 {
+		activation.BindTypeArguments(invocation.TypeParameterTypes)
+	}
+}
+
 // NOTE: assumes the function's activation (or an extension of it) is pushed!
 //
 func (interpreter *Interpreter) invokeInterpretedFunctionActivated(
@@ -146,6 +229,8 @@ func (interpreter *Interpreter) invokeInterpretedFunctionActivated(
 	}()
 	defer interpreter.activations.Pop()
 
+	interpreter.reportFunctionEntry(function.FunctionBlock)
+
 	if function.ParameterList != nil {
 		interpreter.bindParameterArguments(function.ParameterList, arguments)
 	}
@@ -157,7 +242,7 @@ func (interpreter *Interpreter) invokeInterpretedFunctionActivated(
 			return interpreter.visitStatements(function.Statements)
 		},
 		function.PostConditions,
-		function.Type.ReturnTypeAnnotation.Type,
+		interpreter.resolveType(function.Type.ReturnTypeAnnotation.Type),
 	)
 }
 
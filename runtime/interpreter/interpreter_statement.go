@@ -73,8 +73,12 @@ func (interpreter *Interpreter) VisitReturnStatement(statement *ast.ReturnStatem
 	} else {
 		value = interpreter.evalExpression(statement.Expression)
 
-		valueType := interpreter.Program.Elaboration.ReturnStatementValueTypes[statement]
-		returnType := interpreter.Program.Elaboration.ReturnStatementReturnTypes[statement]
+		valueType := interpreter.resolveType(
+			interpreter.Program.Elaboration.ReturnStatementValueTypes[statement],
+		)
+		returnType := interpreter.resolveType(
+			interpreter.Program.Elaboration.ReturnStatementReturnTypes[statement],
+		)
 
 		getLocationRange := locationRangeGetter(interpreter, interpreter.Location, statement.Expression)
 
@@ -96,15 +100,16 @@ func (interpreter *Interpreter) VisitContinueStatement(_ *ast.ContinueStatement)
 func (interpreter *Interpreter) VisitIfStatement(statement *ast.IfStatement) ast.Repr {
 	switch test := statement.Test.(type) {
 	case ast.Expression:
-		return interpreter.visitIfStatementWithTestExpression(test, statement.Then, statement.Else)
+		return interpreter.visitIfStatementWithTestExpression(statement, test, statement.Then, statement.Else)
 	case *ast.VariableDeclaration:
-		return interpreter.visitIfStatementWithVariableDeclaration(test, statement.Then, statement.Else)
+		return interpreter.visitIfStatementWithVariableDeclaration(statement, test, statement.Then, statement.Else)
 	default:
 		panic(errors.NewUnreachableError())
 	}
 }
 
 func (interpreter *Interpreter) visitIfStatementWithTestExpression(
+	statement *ast.IfStatement,
 	test ast.Expression,
 	thenBlock, elseBlock *ast.Block,
 ) controlReturn {
@@ -115,9 +120,13 @@ func (interpreter *Interpreter) visitIfStatementWithTestExpression(
 	}
 	var result any
 	if value {
+		interpreter.reportBranch(statement, 0)
 		result = thenBlock.Accept(interpreter)
-	} else if elseBlock != nil {
-		result = elseBlock.Accept(interpreter)
+	} else {
+		interpreter.reportBranch(statement, 1)
+		if elseBlock != nil {
+			result = elseBlock.Accept(interpreter)
+		}
 	}
 
 	if ret, ok := result.(controlReturn); ok {
@@ -127,6 +136,7 @@ func (interpreter *Interpreter) visitIfStatementWithTestExpression(
 }
 
 func (interpreter *Interpreter) visitIfStatementWithVariableDeclaration(
+	statement *ast.IfStatement,
 	declaration *ast.VariableDeclaration,
 	thenBlock, elseBlock *ast.Block,
 ) controlReturn {
@@ -149,10 +159,14 @@ func (interpreter *Interpreter) visitIfStatementWithVariableDeclaration(
 		panic(errors.NewUnreachableError())
 	}
 
-	valueType := interpreter.Program.Elaboration.VariableDeclarationValueTypes[declaration]
+	valueType := interpreter.resolveType(
+		interpreter.Program.Elaboration.VariableDeclarationValueTypes[declaration],
+	)
 
 	if declaration.SecondValue != nil {
-		secondValueType := interpreter.Program.Elaboration.VariableDeclarationSecondValueTypes[declaration]
+		secondValueType := interpreter.resolveType(
+			interpreter.Program.Elaboration.VariableDeclarationSecondValueTypes[declaration],
+		)
 
 		interpreter.visitAssignment(
 			declaration.Transfer.Operation,
@@ -167,7 +181,11 @@ func (interpreter *Interpreter) visitIfStatementWithVariableDeclaration(
 	var result any
 	if someValue, ok := value.(*SomeValue); ok {
 
-		targetType := interpreter.Program.Elaboration.VariableDeclarationTargetTypes[declaration]
+		interpreter.reportBranch(statement, 0)
+
+		targetType := interpreter.resolveType(
+			interpreter.Program.Elaboration.VariableDeclarationTargetTypes[declaration],
+		)
 		getLocationRange := locationRangeGetter(interpreter, interpreter.Location, declaration.Value)
 		innerValue := someValue.InnerValue(interpreter, getLocationRange)
 		transferredUnwrappedValue := interpreter.transferAndConvert(
@@ -189,8 +207,11 @@ func (interpreter *Interpreter) visitIfStatementWithVariableDeclaration(
 		)
 
 		result = thenBlock.Accept(interpreter)
-	} else if elseBlock != nil {
-		result = elseBlock.Accept(interpreter)
+	} else {
+		interpreter.reportBranch(statement, 1)
+		if elseBlock != nil {
+			result = elseBlock.Accept(interpreter)
+		}
 	}
 
 	if ret, ok := result.(controlReturn); ok {
@@ -206,9 +227,11 @@ func (interpreter *Interpreter) VisitSwitchStatement(switchStatement *ast.Switch
 		panic(errors.NewUnreachableError())
 	}
 
-	for _, switchCase := range switchStatement.Cases {
+	for caseIndex, switchCase := range switchStatement.Cases {
 
 		runStatements := func() ast.Repr {
+			interpreter.reportBranch(switchStatement, caseIndex)
+
 			// NOTE: the new block ensures that a new scope is introduced
 
 			block := ast.NewBlock(
@@ -257,6 +280,8 @@ func (interpreter *Interpreter) VisitSwitchStatement(switchStatement *ast.Switch
 		// then try the next case
 	}
 
+	interpreter.reportBranch(switchStatement, len(switchStatement.Cases))
+
 	return nil
 }
 
@@ -416,9 +441,15 @@ func (interpreter *Interpreter) visitVariableDeclaration(
 	valueCallback func(identifier string, value Value),
 ) {
 
-	targetType := interpreter.Program.Elaboration.VariableDeclarationTargetTypes[declaration]
-	valueType := interpreter.Program.Elaboration.VariableDeclarationValueTypes[declaration]
-	secondValueType := interpreter.Program.Elaboration.VariableDeclarationSecondValueTypes[declaration]
+	targetType := interpreter.resolveType(
+		interpreter.Program.Elaboration.VariableDeclarationTargetTypes[declaration],
+	)
+	valueType := interpreter.resolveType(
+		interpreter.Program.Elaboration.VariableDeclarationValueTypes[declaration],
+	)
+	secondValueType := interpreter.resolveType(
+		interpreter.Program.Elaboration.VariableDeclarationSecondValueTypes[declaration],
+	)
 
 	// NOTE: It is *REQUIRED* that the getter for the value is used
 	// instead of just evaluating value expression,
@@ -465,8 +496,12 @@ func (interpreter *Interpreter) visitVariableDeclaration(
 }
 
 func (interpreter *Interpreter) VisitAssignmentStatement(assignment *ast.AssignmentStatement) ast.Repr {
-	targetType := interpreter.Program.Elaboration.AssignmentStatementTargetTypes[assignment]
-	valueType := interpreter.Program.Elaboration.AssignmentStatementValueTypes[assignment]
+	targetType := interpreter.resolveType(
+		interpreter.Program.Elaboration.AssignmentStatementTargetTypes[assignment],
+	)
+	valueType := interpreter.resolveType(
+		interpreter.Program.Elaboration.AssignmentStatementValueTypes[assignment],
+	)
 
 	target := assignment.Target
 	value := assignment.Value
@@ -483,8 +518,12 @@ func (interpreter *Interpreter) VisitAssignmentStatement(assignment *ast.Assignm
 
 func (interpreter *Interpreter) VisitSwapStatement(swap *ast.SwapStatement) ast.Repr {
 
-	leftType := interpreter.Program.Elaboration.SwapStatementLeftTypes[swap]
-	rightType := interpreter.Program.Elaboration.SwapStatementRightTypes[swap]
+	leftType := interpreter.resolveType(
+		interpreter.Program.Elaboration.SwapStatementLeftTypes[swap],
+	)
+	rightType := interpreter.resolveType(
+		interpreter.Program.Elaboration.SwapStatementRightTypes[swap],
+	)
 
 	const allowMissing = false
 
@@ -56,6 +56,11 @@ type CompositeStaticType struct {
 	Location            common.Location
 	QualifiedIdentifier string
 	TypeID              common.TypeID
+	// typeArguments are the type arguments of an instantiation of a generic composite type.
+	// The type ID of an instantiation includes the type IDs of the type arguments.
+	// NOTE: the type arguments are referenced indirectly,
+	// so that composite static types stay comparable and hashable
+	typeArguments *[]StaticType
 }
 
 var _ StaticType = CompositeStaticType{}
@@ -85,8 +90,124 @@ func NewCompositeStaticTypeComputeTypeID(
 	return NewCompositeStaticType(memoryGauge, location, qualifiedIdentifier, typeID)
 }
 
+func NewInstantiatedCompositeStaticType(
+	memoryGauge common.MemoryGauge,
+	location common.Location,
+	qualifiedIdentifier string,
+	typeID common.TypeID,
+	typeArguments []StaticType,
+) CompositeStaticType {
+	staticType := NewCompositeStaticType(memoryGauge, location, qualifiedIdentifier, typeID)
+	if len(typeArguments) > 0 {
+		staticType.typeArguments = &typeArguments
+	}
+	return staticType
+}
+
+func NewInstantiatedCompositeStaticTypeComputeTypeID(
+	memoryGauge common.MemoryGauge,
+	location common.Location,
+	qualifiedIdentifier string,
+	typeArguments []StaticType,
+) CompositeStaticType {
+	typeID := common.NewTypeIDFromQualifiedName(memoryGauge, location, qualifiedIdentifier)
+	if len(typeArguments) > 0 {
+		typeID = instantiatedTypeID(typeID, typeArguments)
+	}
+
+	return NewInstantiatedCompositeStaticType(memoryGauge, location, qualifiedIdentifier, typeID, typeArguments)
+}
+
+// instantiatedTypeID returns the type ID of an instantiation of a generic composite type,
+// for example `S.test.Box<Int>`. It must match the type ID of the sema type
+//
+func instantiatedTypeID(typeID common.TypeID, typeArguments []StaticType) common.TypeID {
+	var builder strings.Builder
+	builder.WriteString(string(typeID))
+	builder.WriteRune('<')
+	for i, typeArgument := range typeArguments {
+		if i > 0 {
+			builder.WriteRune(',')
+		}
+		builder.WriteString(string(staticTypeID(typeArgument)))
+	}
+	builder.WriteRune('>')
+	return common.TypeID(builder.String())
+}
+
+// staticTypeID returns the type ID of the sema type corresponding to the given static type
+//
+func staticTypeID(t StaticType) common.TypeID {
+	switch t := t.(type) {
+	case CompositeStaticType:
+		return t.TypeID
+
+	case InterfaceStaticType:
+		return common.NewTypeIDFromQualifiedName(nil, t.Location, t.QualifiedIdentifier)
+
+	case VariableSizedStaticType:
+		return common.TypeID(fmt.Sprintf("[%s]", staticTypeID(t.Type)))
+
+	case ConstantSizedStaticType:
+		return common.TypeID(fmt.Sprintf("[%s;%d]", staticTypeID(t.Type), t.Size))
+
+	case DictionaryStaticType:
+		return common.TypeID(fmt.Sprintf("{%s:%s}", staticTypeID(t.KeyType), staticTypeID(t.ValueType)))
+
+	case OptionalStaticType:
+		return common.TypeID(fmt.Sprintf("%s?", staticTypeID(t.Type)))
+
+	case *RestrictedStaticType:
+		var builder strings.Builder
+		builder.WriteString(string(staticTypeID(t.Type)))
+		builder.WriteRune('{')
+		for i, restriction := range t.Restrictions {
+			if i > 0 {
+				builder.WriteRune(',')
+			}
+			builder.WriteString(string(staticTypeID(restriction)))
+		}
+		builder.WriteRune('}')
+		return common.TypeID(builder.String())
+
+	case ReferenceStaticType:
+		var builder strings.Builder
+		if t.Authorized {
+			builder.WriteString("auth ")
+		}
+		builder.WriteRune('&')
+		builder.WriteString(string(staticTypeID(t.BorrowedType)))
+		return common.TypeID(builder.String())
+
+	case CapabilityStaticType:
+		if t.BorrowType == nil {
+			return "Capability"
+		}
+		return common.TypeID(fmt.Sprintf("Capability<%s>", staticTypeID(t.BorrowType)))
+
+	case FunctionStaticType:
+		return t.Type.ID()
+
+	case PrimitiveStaticType:
+		return t.SemaType().ID()
+
+	default:
+		panic(errors.NewUnreachableError())
+	}
+}
+
 func (CompositeStaticType) isStaticType() {}
 
+// TypeArguments returns the type arguments of an instantiation of a generic composite type,
+// and nil for all other composite types
+//
+func (t CompositeStaticType) TypeArguments() []StaticType {
+	if t.typeArguments == nil {
+		return nil
+	}
+	return *t.typeArguments
+}
+
 func (CompositeStaticType) elementSize() uint {
 	return UnknownElementSize
 }
@@ -601,7 +722,23 @@ func (t CapabilityStaticType) Equal(other StaticType) bool {
 func ConvertSemaToStaticType(memoryGauge common.MemoryGauge, t sema.Type) StaticType {
 	switch t := t.(type) {
 	case *sema.CompositeType:
-		return NewCompositeStaticType(memoryGauge, t.Location, t.QualifiedIdentifier(), t.ID())
+		semaTypeArguments := t.TypeArguments()
+		if len(semaTypeArguments) == 0 {
+			return NewCompositeStaticType(memoryGauge, t.Location, t.QualifiedIdentifier(), t.ID())
+		}
+
+		typeArguments := make([]StaticType, len(semaTypeArguments))
+		for i, typeArgument := range semaTypeArguments {
+			typeArguments[i] = ConvertSemaToStaticType(memoryGauge, typeArgument)
+		}
+
+		return NewInstantiatedCompositeStaticType(
+			memoryGauge,
+			t.Location,
+			t.QualifiedIdentifier(),
+			t.ID(),
+			typeArguments,
+		)
 
 	case *sema.InterfaceType:
 		return ConvertSemaInterfaceTypeToStaticInterfaceType(memoryGauge, t)
@@ -711,7 +848,35 @@ func ConvertStaticToSemaType(
 ) (_ sema.Type, err error) {
 	switch t := typ.(type) {
 	case CompositeStaticType:
-		return getComposite(t.Location, t.QualifiedIdentifier, t.TypeID)
+		staticTypeArguments := t.TypeArguments()
+		if len(staticTypeArguments) == 0 {
+			return getComposite(t.Location, t.QualifiedIdentifier, t.TypeID)
+		}
+
+		// The type of an instantiation of a generic composite type
+		// is the generic composite type, instantiated with the type arguments
+
+		typeID := common.NewTypeIDFromQualifiedName(memoryGauge, t.Location, t.QualifiedIdentifier)
+		genericType, err := getComposite(t.Location, t.QualifiedIdentifier, typeID)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(genericType.TypeParameters()) != len(staticTypeArguments) {
+			return nil, TypeLoadingError{
+				TypeID: t.TypeID,
+			}
+		}
+
+		typeArguments := make([]sema.Type, len(staticTypeArguments))
+		for i, typeArgument := range staticTypeArguments {
+			typeArguments[i], err = ConvertStaticToSemaType(memoryGauge, typeArgument, getInterface, getComposite)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return genericType.Instantiate(typeArguments, nil), nil
 
 	case InterfaceStaticType:
 		return getInterface(t.Location, t.QualifiedIdentifier)
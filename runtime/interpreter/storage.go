@@ -42,6 +42,42 @@ func StoredValue(gauge common.MemoryGauge, storable atree.Storable, storage atre
 	return MustConvertStoredValue(gauge, storedValue)
 }
 
+// StoredStaticType returns the static type of the value stored as the given storable.
+//
+// Unlike StoredValue, container values (arrays, dictionaries, and composites) are not converted,
+// only the type information of their root slab is read.
+//
+func StoredStaticType(interpreter *Interpreter, storable atree.Storable, storage atree.SlabStorage) StaticType {
+	storedValue, err := storable.StoredValue(storage)
+	if err != nil {
+		panic(err)
+	}
+
+	switch storedValue := storedValue.(type) {
+	case *atree.Array:
+		staticType, ok := storedValue.Type().(ArrayStaticType)
+		if !ok {
+			panic(errors.NewUnreachableError())
+		}
+		return staticType
+
+	case *atree.OrderedMap:
+		switch typeInfo := storedValue.Type().(type) {
+		case DictionaryStaticType:
+			return typeInfo
+		case compositeTypeInfo:
+			return NewInstantiatedCompositeStaticTypeComputeTypeID(
+				interpreter,
+				typeInfo.location,
+				typeInfo.qualifiedIdentifier,
+				typeInfo.typeArguments,
+			)
+		}
+	}
+
+	return MustConvertStoredValue(interpreter, storedValue).StaticType(interpreter)
+}
+
 func MustConvertStoredValue(gauge common.MemoryGauge, value atree.Value) Value {
 	converted, err := ConvertStoredValue(gauge, value)
 	if err != nil {
@@ -213,6 +249,104 @@ func StorableSize(storable atree.Storable) (uint32, error) {
 	return uint32(size), nil
 }
 
+// StorableFootprint returns the number of bytes used to store the given storable,
+// i.e. the size of the storable itself, and the sizes of all slabs reachable from it,
+// e.g. the slabs of nested containers, or of large values stored in separate slabs.
+//
+// Each slab is only counted once.
+//
+// If an interpreter is given, computation is reported for each retrieved slab.
+//
+func StorableFootprint(
+	interpreter *Interpreter,
+	storable atree.Storable,
+	storage atree.SlabStorage,
+) (uint64, error) {
+	referencedSlabsSize, err := referencedSlabsSize(
+		interpreter,
+		storable,
+		storage,
+		map[atree.StorageID]struct{}{},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(storable.ByteSize()) + referencedSlabsSize, nil
+}
+
+// referencedSlabsSize returns the sum of the sizes of all slabs reachable from the given storable.
+//
+// The size of the storable itself is not included:
+// The size of an inlined storable is already included in the size of its parent.
+//
+func referencedSlabsSize(
+	interpreter *Interpreter,
+	storable atree.Storable,
+	storage atree.SlabStorage,
+	visited map[atree.StorageID]struct{},
+) (
+	uint64,
+	error,
+) {
+	var size uint64
+
+	if storageIDStorable, ok := storable.(atree.StorageIDStorable); ok {
+		slabSize, err := slabFootprint(interpreter, atree.StorageID(storageIDStorable), storage, visited)
+		if err != nil {
+			return 0, err
+		}
+		size += slabSize
+	}
+
+	for _, childStorable := range storable.ChildStorables() {
+		childSize, err := referencedSlabsSize(interpreter, childStorable, storage, visited)
+		if err != nil {
+			return 0, err
+		}
+		size += childSize
+	}
+
+	return size, nil
+}
+
+// slabFootprint returns the size of the given slab,
+// and the sizes of all slabs reachable from it.
+//
+func slabFootprint(
+	interpreter *Interpreter,
+	storageID atree.StorageID,
+	storage atree.SlabStorage,
+	visited map[atree.StorageID]struct{},
+) (
+	uint64,
+	error,
+) {
+	if _, ok := visited[storageID]; ok {
+		return 0, nil
+	}
+	visited[storageID] = struct{}{}
+
+	if interpreter != nil {
+		interpreter.ReportComputation(common.ComputationKindStorageFootprintSlab, 1)
+	}
+
+	slab, ok, err := storage.Retrieve(storageID)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, atree.NewSlabNotFoundErrorf(storageID, "slab not found")
+	}
+
+	referencedSize, err := referencedSlabsSize(interpreter, slab, storage, visited)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(slab.ByteSize()) + referencedSize, nil
+}
+
 // maybeLargeImmutableStorable either returns the given immutable atree.Storable
 // if it can be stored inline inside its parent container,
 // or else stores it in a separate slab and returns an atree.StorageIDStorable.
@@ -116,6 +116,21 @@ func (s StorageMap) WriteValue(interpreter *Interpreter, key string, value atree
 // If the given key already stores a value, it is overwritten.
 //
 func (s StorageMap) SetValue(interpreter *Interpreter, key string, value atree.Value) {
+	existingValue, existingStorable := s.SwapValue(interpreter, key, value)
+	if existingValue != nil {
+		existingValue.DeepRemove(interpreter)
+		interpreter.RemoveReferencedSlab(existingStorable)
+	}
+}
+
+// SwapValue sets a value in the storage map,
+// and returns the value which was stored for the given key before, and its storable.
+// Returns (nil, nil) if the key did not exist.
+//
+// Unlike SetValue, the existing value is not removed from storage.
+// The caller is responsible for it, e.g. by transferring it out of the account.
+//
+func (s StorageMap) SwapValue(interpreter *Interpreter, key string, value atree.Value) (Value, atree.Storable) {
 	existingStorable, err := s.orderedMap.Set(
 		StringAtreeComparator,
 		StringAtreeHashInput,
@@ -127,11 +142,59 @@ func (s StorageMap) SetValue(interpreter *Interpreter, key string, value atree.V
 	}
 	interpreter.maybeValidateAtreeValue(s.orderedMap)
 
-	if existingStorable != nil {
-		existingValue := StoredValue(interpreter, existingStorable, interpreter.Storage)
-		existingValue.DeepRemove(interpreter)
-		interpreter.RemoveReferencedSlab(existingStorable)
+	if existingStorable == nil {
+		return nil, nil
 	}
+
+	existingValue := StoredValue(interpreter, existingStorable, interpreter.Storage)
+	return existingValue, existingStorable
+}
+
+// ReadStaticType returns the static type of the value for the given key,
+// without converting the stored value.
+// Returns nil if the key does not exist.
+//
+func (s StorageMap) ReadStaticType(interpreter *Interpreter, key string) StaticType {
+	storable, err := s.orderedMap.Get(
+		StringAtreeComparator,
+		StringAtreeHashInput,
+		StringAtreeValue(key),
+	)
+	if err != nil {
+		if _, ok := err.(*atree.KeyNotFoundError); ok {
+			return nil
+		}
+		panic(ExternalError{err})
+	}
+
+	return StoredStaticType(interpreter, storable, s.orderedMap.Storage)
+}
+
+// ValueFootprint returns the number of bytes used to store the value for the given key,
+// including all slabs reachable from it (see StorableFootprint).
+// Returns false if the key does not exist.
+//
+// If an interpreter is given, computation is reported for each retrieved slab.
+//
+func (s StorageMap) ValueFootprint(interpreter *Interpreter, key string) (uint64, bool) {
+	storable, err := s.orderedMap.Get(
+		StringAtreeComparator,
+		StringAtreeHashInput,
+		StringAtreeValue(key),
+	)
+	if err != nil {
+		if _, ok := err.(*atree.KeyNotFoundError); ok {
+			return 0, false
+		}
+		panic(ExternalError{err})
+	}
+
+	size, err := StorableFootprint(interpreter, storable, s.orderedMap.Storage)
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	return size, true
 }
 
 // removeValue removes a value in the storage map, if it exists.
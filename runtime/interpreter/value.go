@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"sort"
 	"strings"
 	"time"
 	"unicode"
@@ -1154,6 +1155,85 @@ func (v *StringValue) GetMember(interpreter *Interpreter, _ func() LocationRange
 			},
 			sema.StringTypeToLowerFunctionType,
 		)
+
+	case "toUpper":
+		return NewHostFunctionValue(
+			interpreter,
+			func(invocation Invocation) Value {
+				return v.ToUpper(invocation.Interpreter)
+			},
+			sema.StringTypeToUpperFunctionType,
+		)
+
+	case "contains":
+		return NewHostFunctionValue(
+			interpreter,
+			func(invocation Invocation) Value {
+				other, ok := invocation.Arguments[0].(*StringValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				return v.Contains(invocation.Interpreter, other)
+			},
+			sema.StringTypeContainsFunctionType,
+		)
+
+	case "index":
+		return NewHostFunctionValue(
+			interpreter,
+			func(invocation Invocation) Value {
+				other, ok := invocation.Arguments[0].(*StringValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				return v.IndexOf(invocation.Interpreter, other)
+			},
+			sema.StringTypeIndexFunctionType,
+		)
+
+	case "split":
+		return NewHostFunctionValue(
+			interpreter,
+			func(invocation Invocation) Value {
+				separator, ok := invocation.Arguments[0].(*StringValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				return v.Split(invocation.Interpreter, separator)
+			},
+			sema.StringTypeSplitFunctionType,
+		)
+
+	case "replaceAll":
+		return NewHostFunctionValue(
+			interpreter,
+			func(invocation Invocation) Value {
+				original, ok := invocation.Arguments[0].(*StringValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				replacement, ok := invocation.Arguments[1].(*StringValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				return v.ReplaceAll(invocation.Interpreter, original, replacement)
+			},
+			sema.StringTypeReplaceAllFunctionType,
+		)
+
+	case "trim":
+		return NewHostFunctionValue(
+			interpreter,
+			func(invocation Invocation) Value {
+				return v.Trim(invocation.Interpreter)
+			},
+			sema.StringTypeTrimFunctionType,
+		)
 	}
 
 	return nil
@@ -1209,6 +1289,277 @@ func (v *StringValue) ToLower(interpreter *Interpreter) *StringValue {
 	)
 }
 
+func (v *StringValue) ToUpper(interpreter *Interpreter) *StringValue {
+
+	// Over-estimate resulting string length,
+	// as a lowercase character may be converted to several upper-case characters, e.g ß => [S, S]
+
+	var lengthEstimate int
+	for _, r := range v.Str {
+		if r < unicode.MaxASCII {
+			lengthEstimate += 1
+		} else {
+			lengthEstimate += utf8.UTFMax
+		}
+	}
+
+	memoryUsage := common.NewStringMemoryUsage(lengthEstimate)
+
+	return NewStringValue(
+		interpreter,
+		memoryUsage,
+		func() string {
+			return strings.ToUpper(v.Str)
+		},
+	)
+}
+
+// graphemeBoundaries returns the byte offsets at which the characters (grapheme clusters)
+// of the string start, followed by the length of the string in bytes
+//
+func (v *StringValue) graphemeBoundaries(interpreter *Interpreter) []int {
+	common.UseMemory(interpreter, common.NewStringGraphemeBoundariesMemoryUsage(len(v.Str)))
+	interpreter.ReportComputation(common.ComputationKindStringScan, uint(len(v.Str)))
+
+	boundaries := make([]int, 0, len(v.Str)+1)
+
+	v.prepareGraphemes()
+	for v.graphemes.Next() {
+		start, _ := v.graphemes.Positions()
+		boundaries = append(boundaries, start)
+	}
+
+	return append(boundaries, len(v.Str))
+}
+
+// indexOf returns the index into the given boundaries of the first occurrence of other
+// at or after the given byte offset, which starts and ends at a character boundary,
+// or -1 if there is no such occurrence
+//
+func (v *StringValue) indexOf(interpreter *Interpreter, boundaries []int, offset int, other string) int {
+	for offset <= len(v.Str) {
+		interpreter.ReportComputation(common.ComputationKindStringScan, uint(len(v.Str)-offset))
+
+		i := strings.Index(v.Str[offset:], other)
+		if i < 0 {
+			return -1
+		}
+
+		start := offset + i
+		end := start + len(other)
+
+		startIndex := sort.SearchInts(boundaries, start)
+		if startIndex < len(boundaries) && boundaries[startIndex] == start {
+			endIndex := sort.SearchInts(boundaries, end)
+			if endIndex < len(boundaries) && boundaries[endIndex] == end {
+				return startIndex
+			}
+		}
+
+		// The occurrence is not at character boundaries,
+		// continue searching after the start of the occurrence
+
+		_, size := utf8.DecodeRuneInString(v.Str[start:])
+		offset = start + size
+	}
+
+	return -1
+}
+
+// occurrences returns the indices into the given boundaries
+// of all non-overlapping occurrences of the given non-empty string
+//
+func (v *StringValue) occurrences(interpreter *Interpreter, boundaries []int, other string) []int {
+	var result []int
+
+	offset := 0
+	for {
+		index := v.indexOf(interpreter, boundaries, offset, other)
+		if index < 0 {
+			return result
+		}
+
+		result = append(result, index)
+		offset = boundaries[index] + len(other)
+	}
+}
+
+func (v *StringValue) Contains(interpreter *Interpreter, other *StringValue) BoolValue {
+	if len(other.Str) == 0 {
+		return NewBoolValue(interpreter, true)
+	}
+
+	boundaries := v.graphemeBoundaries(interpreter)
+	index := v.indexOf(interpreter, boundaries, 0, other.Str)
+
+	return NewBoolValue(interpreter, index >= 0)
+}
+
+// IndexOf returns the index of the first character of the first occurrence of the given string,
+// or -1 if the string does not contain the given string
+//
+func (v *StringValue) IndexOf(interpreter *Interpreter, other *StringValue) IntValue {
+	if len(other.Str) == 0 {
+		return NewIntValueFromInt64(interpreter, 0)
+	}
+
+	boundaries := v.graphemeBoundaries(interpreter)
+	index := v.indexOf(interpreter, boundaries, 0, other.Str)
+
+	return NewIntValueFromInt64(interpreter, int64(index))
+}
+
+// Memory is NOT metered for this value
+var StringArrayStaticType = ConvertSemaArrayTypeToStaticArrayType(
+	nil,
+	&sema.VariableSizedType{
+		Type: sema.StringType,
+	},
+)
+
+// Split returns the substrings of this string which are separated by the given separator.
+// If the separator is empty, the string is split into its characters
+//
+func (v *StringValue) Split(interpreter *Interpreter, separator *StringValue) *ArrayValue {
+	boundaries := v.graphemeBoundaries(interpreter)
+
+	var parts []string
+
+	if len(separator.Str) == 0 {
+		parts = make([]string, 0, len(boundaries)-1)
+		for i := 0; i < len(boundaries)-1; i++ {
+			parts = append(parts, v.Str[boundaries[i]:boundaries[i+1]])
+		}
+	} else {
+		start := 0
+		for _, index := range v.occurrences(interpreter, boundaries, separator.Str) {
+			end := boundaries[index]
+			parts = append(parts, v.Str[start:end])
+			start = end + len(separator.Str)
+		}
+		parts = append(parts, v.Str[start:])
+	}
+
+	i := 0
+
+	return NewArrayValueWithIterator(
+		interpreter,
+		StringArrayStaticType,
+		common.Address{},
+		uint64(len(parts)),
+		func() Value {
+			if i >= len(parts) {
+				return nil
+			}
+
+			part := parts[i]
+
+			i++
+
+			return NewStringValue(
+				interpreter,
+				common.NewStringMemoryUsage(len(part)),
+				func() string {
+					return part
+				},
+			)
+		},
+	)
+}
+
+// ReplaceAll returns a new string with all non-overlapping occurrences
+// of the given original string replaced with the given replacement.
+// If the original string is empty, this string is returned unchanged
+//
+func (v *StringValue) ReplaceAll(interpreter *Interpreter, original *StringValue, replacement *StringValue) *StringValue {
+	if len(original.Str) == 0 {
+		return v
+	}
+
+	boundaries := v.graphemeBoundaries(interpreter)
+	occurrences := v.occurrences(interpreter, boundaries, original.Str)
+
+	if len(occurrences) == 0 {
+		return v
+	}
+
+	newLength := safeAdd(
+		len(v.Str)-len(occurrences)*len(original.Str),
+		safeMul(len(occurrences), len(replacement.Str)),
+	)
+
+	memoryUsage := common.NewStringMemoryUsage(newLength)
+
+	return NewStringValue(
+		interpreter,
+		memoryUsage,
+		func() string {
+			var sb strings.Builder
+			sb.Grow(newLength)
+
+			start := 0
+			for _, index := range occurrences {
+				end := boundaries[index]
+				sb.WriteString(v.Str[start:end])
+				sb.WriteString(replacement.Str)
+				start = end + len(original.Str)
+			}
+			sb.WriteString(v.Str[start:])
+
+			return sb.String()
+		},
+	)
+}
+
+func isWhitespaceCharacter(char string) bool {
+	for _, r := range char {
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Trim returns this string without leading and trailing whitespace characters
+//
+func (v *StringValue) Trim(interpreter *Interpreter) *StringValue {
+	interpreter.ReportComputation(common.ComputationKindStringScan, uint(len(v.Str)))
+
+	start := -1
+	end := 0
+
+	v.prepareGraphemes()
+	for v.graphemes.Next() {
+		if isWhitespaceCharacter(v.graphemes.Str()) {
+			continue
+		}
+
+		charStart, charEnd := v.graphemes.Positions()
+		if start < 0 {
+			start = charStart
+		}
+		end = charEnd
+	}
+
+	if start < 0 {
+		return emptyString
+	}
+
+	if start == 0 && end == len(v.Str) {
+		return v
+	}
+
+	return NewStringValue(
+		interpreter,
+		common.NewStringMemoryUsage(end-start),
+		func() string {
+			// NOTE: string slicing in Go does not copy,
+			// see https://stackoverflow.com/questions/52395730/does-slice-of-string-perform-copy-of-underlying-data
+			return v.Str[start:end]
+		},
+	)
+}
+
 func (v *StringValue) Storable(storage atree.SlabStorage, address atree.Address, maxInlineSize uint64) (atree.Storable, error) {
 	return maybeLargeImmutableStorable(v, storage, address, maxInlineSize)
 }
@@ -1669,6 +2020,8 @@ func (v *ArrayValue) Set(interpreter *Interpreter, getLocationRange func() Locat
 		})
 	}
 
+	interpreter.checkContainerNotIterated(v.StorageID(), getLocationRange)
+
 	interpreter.checkContainerMutation(v.Type.ElementType(), element, getLocationRange)
 
 	common.UseMemory(interpreter, common.AtreeArrayElementOverhead)
@@ -1740,6 +2093,8 @@ func (v *ArrayValue) Append(interpreter *Interpreter, getLocationRange func() Lo
 	common.UseMemory(interpreter, metaDataSlabs)
 	common.UseMemory(interpreter, common.AtreeArrayElementOverhead)
 
+	interpreter.checkContainerNotIterated(v.StorageID(), getLocationRange)
+
 	interpreter.checkContainerMutation(v.Type.ElementType(), element, getLocationRange)
 
 	element = element.Transfer(
@@ -1796,6 +2151,8 @@ func (v *ArrayValue) Insert(interpreter *Interpreter, getLocationRange func() Lo
 	common.UseMemory(interpreter, metaDataSlabs)
 	common.UseMemory(interpreter, common.AtreeArrayElementOverhead)
 
+	interpreter.checkContainerNotIterated(v.StorageID(), getLocationRange)
+
 	interpreter.checkContainerMutation(v.Type.ElementType(), element, getLocationRange)
 
 	element = element.Transfer(
@@ -1838,6 +2195,8 @@ func (v *ArrayValue) Remove(interpreter *Interpreter, getLocationRange func() Lo
 		})
 	}
 
+	interpreter.checkContainerNotIterated(v.StorageID(), getLocationRange)
+
 	storable, err := v.array.Remove(uint64(index))
 	if err != nil {
 		v.handleIndexOutOfBoundsError(err, index, getLocationRange)
@@ -2114,6 +2473,115 @@ func (v *ArrayValue) GetMember(interpreter *Interpreter, getLocationRange func()
 				v.SemaType(interpreter).ElementType(false),
 			),
 		)
+
+	case "forEach":
+		return NewHostFunctionValue(
+			interpreter,
+			func(invocation Invocation) Value {
+				function, functionType := invocationFunctionArgument(invocation, 0)
+
+				v.ForEach(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					function,
+					functionType,
+				)
+
+				return NewVoidValue(invocation.Interpreter)
+			},
+			sema.ArrayForEachFunctionType(
+				v.SemaType(interpreter).ElementType(false),
+			),
+		)
+
+	case "filter":
+		return NewHostFunctionValue(
+			interpreter,
+			func(invocation Invocation) Value {
+				predicate, predicateType := invocationFunctionArgument(invocation, 0)
+
+				return v.Filter(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					predicate,
+					predicateType,
+				)
+			},
+			sema.ArrayFilterFunctionType(
+				v.SemaType(interpreter).ElementType(false),
+			),
+		)
+
+	case "map":
+		return NewHostFunctionValue(
+			interpreter,
+			func(invocation Invocation) Value {
+				transform, transformType := invocationFunctionArgument(invocation, 0)
+
+				return v.Map(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					transform,
+					transformType,
+					invocationTypeArgument(invocation),
+				)
+			},
+			sema.ArrayMapFunctionType(
+				v.SemaType(interpreter),
+			),
+		)
+
+	case "reverse":
+		return NewHostFunctionValue(
+			interpreter,
+			func(invocation Invocation) Value {
+				return v.Reverse(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+				)
+			},
+			sema.ArrayReverseFunctionType(
+				v.SemaType(interpreter),
+			),
+		)
+
+	case "sort":
+		return NewHostFunctionValue(
+			interpreter,
+			func(invocation Invocation) Value {
+				areInIncreasingOrder, areInIncreasingOrderType := invocationFunctionArgument(invocation, 0)
+
+				return v.Sort(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					areInIncreasingOrder,
+					areInIncreasingOrderType,
+				)
+			},
+			sema.ArraySortFunctionType(
+				v.SemaType(interpreter),
+			),
+		)
+
+	case "reduce":
+		return NewHostFunctionValue(
+			interpreter,
+			func(invocation Invocation) Value {
+				combine, combineType := invocationFunctionArgument(invocation, 1)
+
+				return v.Reduce(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					invocation.Arguments[0],
+					invocationTypeArgument(invocation),
+					combine,
+					combineType,
+				)
+			},
+			sema.ArrayReduceFunctionType(
+				v.SemaType(interpreter).ElementType(false),
+			),
+		)
 	}
 
 	return nil
@@ -2319,6 +2787,8 @@ func (v *ArrayValue) Transfer(
 		}
 
 		if remove {
+			interpreter.checkContainerNotIterated(currentStorageID, getLocationRange)
+
 			err = v.array.PopIterate(func(storable atree.Storable) {
 				interpreter.RemoveReferencedSlab(storable)
 			})
@@ -2531,30 +3001,400 @@ func (v *ArrayValue) Slice(
 		uint64(toIndex-fromIndex),
 		func() Value {
 
-			var value Value
+			var value Value
+
+			atreeValue, err := iterator.Next()
+			if err != nil {
+				panic(ExternalError{err})
+			}
+
+			if atreeValue != nil {
+				value = MustConvertStoredValue(interpreter, atreeValue)
+			}
+
+			if value == nil {
+				return nil
+			}
+
+			return value.Transfer(
+				interpreter,
+				getLocationRange,
+				atree.Address{},
+				false,
+				nil,
+			)
+		},
+	)
+}
+
+// invocationFunctionArgument returns the function value of the argument at the given index,
+// and the static type of the argument
+//
+func invocationFunctionArgument(invocation Invocation, index int) (FunctionValue, *sema.FunctionType) {
+	function, ok := invocation.Arguments[index].(FunctionValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	functionType, ok := invocation.ArgumentTypes[index].(*sema.FunctionType)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	return function, functionType
+}
+
+// invocationTypeArgument returns the type argument of the invocation of a function
+// which has exactly one type parameter
+//
+func invocationTypeArgument(invocation Invocation) sema.Type {
+	typeParameterPair := invocation.TypeParameterTypes.Oldest()
+	if typeParameterPair == nil {
+		panic(errors.NewUnreachableError())
+	}
+
+	return typeParameterPair.Value
+}
+
+// ForEach calls the given function for each element of the array, in order
+//
+func (v *ArrayValue) ForEach(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	function FunctionValue,
+	functionType *sema.FunctionType,
+) {
+	argumentTypes := []sema.Type{
+		v.SemaType(interpreter).ElementType(false),
+	}
+
+	interpreter.withContainerIteration(v.StorageID(), func() {
+		v.Iterate(interpreter, func(element Value) (resume bool) {
+			interpreter.ReportComputation(common.ComputationKindLoop, 1)
+
+			interpreter.invokeFunctionArgument(
+				function,
+				functionType,
+				[]Value{element},
+				argumentTypes,
+				getLocationRange,
+			)
+
+			return true
+		})
+	})
+}
+
+// Filter returns a new variable-sized array containing the elements
+// for which the given predicate returns true, in order
+//
+func (v *ArrayValue) Filter(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	predicate FunctionValue,
+	predicateType *sema.FunctionType,
+) *ArrayValue {
+	argumentTypes := []sema.Type{
+		v.SemaType(interpreter).ElementType(false),
+	}
+
+	iterator, err := v.array.Iterator()
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	var result *ArrayValue
+
+	interpreter.withContainerIteration(v.StorageID(), func() {
+		result = NewArrayValueWithIterator(
+			interpreter,
+			NewVariableSizedStaticType(interpreter, v.Type.ElementType()),
+			common.Address{},
+			// The number of elements of the result is not known in advance,
+			// so over-estimate it with the number of elements of the array
+			uint64(v.Count()),
+			func() Value {
+				for {
+					atreeValue, err := iterator.Next()
+					if err != nil {
+						panic(ExternalError{err})
+					}
+
+					if atreeValue == nil {
+						return nil
+					}
+
+					interpreter.ReportComputation(common.ComputationKindLoop, 1)
+
+					value := MustConvertStoredValue(interpreter, atreeValue)
+
+					include, ok := interpreter.invokeFunctionArgument(
+						predicate,
+						predicateType,
+						[]Value{value},
+						argumentTypes,
+						getLocationRange,
+					).(BoolValue)
+					if !ok {
+						panic(errors.NewUnreachableError())
+					}
+
+					if include {
+						return value.Transfer(
+							interpreter,
+							getLocationRange,
+							atree.Address{},
+							false,
+							nil,
+						)
+					}
+				}
+			},
+		)
+	})
+
+	return result
+}
+
+// Map returns a new array containing the results of calling the given transform function
+// for each element of the array, in order.
+// The result of mapping a constant-sized array is a constant-sized array of the same size
+//
+func (v *ArrayValue) Map(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	transform FunctionValue,
+	transformType *sema.FunctionType,
+	resultElementType sema.Type,
+) *ArrayValue {
+	argumentTypes := []sema.Type{
+		v.SemaType(interpreter).ElementType(false),
+	}
+
+	var resultType sema.ArrayType
+	if constantSizedType, ok := v.Type.(ConstantSizedStaticType); ok {
+		resultType = &sema.ConstantSizedType{
+			Type: resultElementType,
+			Size: constantSizedType.Size,
+		}
+	} else {
+		resultType = &sema.VariableSizedType{
+			Type: resultElementType,
+		}
+	}
+
+	iterator, err := v.array.Iterator()
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	var result *ArrayValue
+
+	interpreter.withContainerIteration(v.StorageID(), func() {
+		result = NewArrayValueWithIterator(
+			interpreter,
+			ConvertSemaArrayTypeToStaticArrayType(interpreter, resultType),
+			common.Address{},
+			uint64(v.Count()),
+			func() Value {
+				atreeValue, err := iterator.Next()
+				if err != nil {
+					panic(ExternalError{err})
+				}
+
+				if atreeValue == nil {
+					return nil
+				}
+
+				interpreter.ReportComputation(common.ComputationKindLoop, 1)
+
+				value := interpreter.invokeFunctionArgument(
+					transform,
+					transformType,
+					[]Value{MustConvertStoredValue(interpreter, atreeValue)},
+					argumentTypes,
+					getLocationRange,
+				)
+
+				value = interpreter.ConvertAndBox(
+					getLocationRange,
+					value,
+					transformType.ReturnTypeAnnotation.Type,
+					resultElementType,
+				)
+
+				return value.Transfer(
+					interpreter,
+					getLocationRange,
+					atree.Address{},
+					false,
+					nil,
+				)
+			},
+		)
+	})
+
+	return result
+}
+
+// Reverse returns a new array containing the elements of the array in reverse order
+//
+func (v *ArrayValue) Reverse(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+) *ArrayValue {
+	count := v.Count()
+	index := count - 1
+
+	return NewArrayValueWithIterator(
+		interpreter,
+		v.Type,
+		common.Address{},
+		uint64(count),
+		func() Value {
+			if index < 0 {
+				return nil
+			}
+
+			interpreter.ReportComputation(common.ComputationKindLoop, 1)
+
+			// atree arrays have no reverse iterator,
+			// so the elements are accessed by index
+
+			value := v.Get(interpreter, getLocationRange, index)
+
+			index--
+
+			return value.Transfer(
+				interpreter,
+				getLocationRange,
+				atree.Address{},
+				false,
+				nil,
+			)
+		},
+	)
+}
+
+// Sort returns a new array containing the elements of the array,
+// sorted using the given function, which returns true if its first argument
+// should be ordered before its second argument.
+// The sort is stable
+//
+func (v *ArrayValue) Sort(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	areInIncreasingOrder FunctionValue,
+	areInIncreasingOrderType *sema.FunctionType,
+) *ArrayValue {
+	elementType := v.SemaType(interpreter).ElementType(false)
+	argumentTypes := []sema.Type{elementType, elementType}
+
+	count := v.Count()
+
+	var result *ArrayValue
+
+	interpreter.withContainerIteration(v.StorageID(), func() {
+
+		// Sorting requires random access to the elements, so collect them.
+		// The elements are only copied once, when they are transferred into the result
+
+		elements := make([]Value, 0, count)
+
+		v.Iterate(interpreter, func(element Value) (resume bool) {
+			elements = append(elements, element)
+			return true
+		})
+
+		sort.SliceStable(elements, func(i, j int) bool {
+			interpreter.ReportComputation(common.ComputationKindLoop, 1)
+
+			isLess, ok := interpreter.invokeFunctionArgument(
+				areInIncreasingOrder,
+				areInIncreasingOrderType,
+				[]Value{elements[i], elements[j]},
+				argumentTypes,
+				getLocationRange,
+			).(BoolValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+
+			return bool(isLess)
+		})
+
+		index := 0
+
+		result = NewArrayValueWithIterator(
+			interpreter,
+			v.Type,
+			common.Address{},
+			uint64(count),
+			func() Value {
+				if index >= len(elements) {
+					return nil
+				}
+
+				value := elements[index]
+
+				index++
+
+				return value.Transfer(
+					interpreter,
+					getLocationRange,
+					atree.Address{},
+					false,
+					nil,
+				)
+			},
+		)
+	})
+
+	return result
+}
 
-			atreeValue, err := iterator.Next()
-			if err != nil {
-				panic(ExternalError{err})
-			}
+// Reduce returns the result of combining the elements of the array using the given function,
+// starting with the given initial value
+//
+func (v *ArrayValue) Reduce(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	initial Value,
+	resultType sema.Type,
+	combine FunctionValue,
+	combineType *sema.FunctionType,
+) Value {
+	argumentTypes := []sema.Type{
+		resultType,
+		v.SemaType(interpreter).ElementType(false),
+	}
 
-			if atreeValue != nil {
-				value = MustConvertStoredValue(interpreter, atreeValue)
-			}
+	result := initial
 
-			if value == nil {
-				return nil
-			}
+	interpreter.withContainerIteration(v.StorageID(), func() {
+		v.Iterate(interpreter, func(element Value) (resume bool) {
+			interpreter.ReportComputation(common.ComputationKindLoop, 1)
 
-			return value.Transfer(
-				interpreter,
+			value := interpreter.invokeFunctionArgument(
+				combine,
+				combineType,
+				[]Value{result, element},
+				argumentTypes,
 				getLocationRange,
-				atree.Address{},
-				false,
-				nil,
 			)
-		},
-	)
+
+			result = interpreter.ConvertAndBox(
+				getLocationRange,
+				value,
+				combineType.ReturnTypeAnnotation.Type,
+				resultType,
+			)
+
+			return true
+		})
+	})
+
+	return result
 }
 
 // NumberValue
@@ -14117,6 +14957,9 @@ type CompositeValue struct {
 	Location            common.Location
 	QualifiedIdentifier string
 	Kind                common.CompositeKind
+	// TypeArguments are the type arguments of the value's type,
+	// if it is an instantiation of a generic composite type
+	TypeArguments       []StaticType
 	InjectedFields      map[string]Value
 	ComputedFields      map[string]ComputedField
 	NestedVariables     map[string]*Variable
@@ -14124,6 +14967,7 @@ type CompositeValue struct {
 	Destructor          FunctionValue
 	Stringer            func(gauge common.MemoryGauge, value *CompositeValue, seenReferences SeenReferences) string
 	isDestroyed         bool
+	removedFieldsPurged bool
 	typeID              common.TypeID
 	staticType          StaticType
 }
@@ -14155,6 +14999,30 @@ func NewCompositeValue(
 	fields []CompositeField,
 	address common.Address,
 ) *CompositeValue {
+	return NewInstantiatedCompositeValue(
+		interpreter,
+		location,
+		qualifiedIdentifier,
+		kind,
+		nil,
+		fields,
+		address,
+	)
+}
+
+// NewInstantiatedCompositeValue returns a new composite value
+// of an instantiation of a generic composite type.
+// The type arguments are nil for composite types which are not generic
+//
+func NewInstantiatedCompositeValue(
+	interpreter *Interpreter,
+	location common.Location,
+	qualifiedIdentifier string,
+	kind common.CompositeKind,
+	typeArguments []StaticType,
+	fields []CompositeField,
+	address common.Address,
+) *CompositeValue {
 
 	interpreter.ReportComputation(common.ComputationKindCreateCompositeValue, 1)
 
@@ -14187,11 +15055,12 @@ func NewCompositeValue(
 			interpreter.Storage,
 			atree.Address(address),
 			atree.NewDefaultDigesterBuilder(),
-			NewCompositeTypeInfo(
+			NewInstantiatedCompositeTypeInfo(
 				interpreter,
 				location,
 				qualifiedIdentifier,
 				kind,
+				typeArguments,
 			),
 		)
 		if err != nil {
@@ -14200,11 +15069,12 @@ func NewCompositeValue(
 		return dictionary
 	}
 
-	typeInfo := NewCompositeTypeInfo(
+	typeInfo := NewInstantiatedCompositeTypeInfo(
 		interpreter,
 		location,
 		qualifiedIdentifier,
 		kind,
+		typeArguments,
 	)
 
 	v = newCompositeValueFromConstructor(interpreter, uint64(len(fields)), typeInfo, constructor)
@@ -14231,6 +15101,7 @@ func newCompositeValueFromOrderedMap(
 		Location:            typeInfo.location,
 		QualifiedIdentifier: typeInfo.qualifiedIdentifier,
 		Kind:                typeInfo.kind,
+		TypeArguments:       typeInfo.typeArguments,
 	}
 }
 
@@ -14280,12 +15151,21 @@ func (v *CompositeValue) StaticType(interpreter *Interpreter) StaticType {
 	if v.staticType == nil {
 		// NOTE: Instead of using NewCompositeStaticType, which always generates the type ID,
 		// use the TypeID accessor, which may return an already computed type ID
-		v.staticType = NewCompositeStaticType(
-			interpreter,
-			v.Location,
-			v.QualifiedIdentifier,
-			v.TypeID(), // TODO TypeID metering
-		)
+		if len(v.TypeArguments) > 0 {
+			v.staticType = NewInstantiatedCompositeStaticTypeComputeTypeID(
+				interpreter,
+				v.Location,
+				v.QualifiedIdentifier,
+				v.TypeArguments,
+			)
+		} else {
+			v.staticType = NewCompositeStaticType(
+				interpreter,
+				v.Location,
+				v.QualifiedIdentifier,
+				v.TypeID(), // TODO TypeID metering
+			)
+		}
 	}
 	return v.staticType
 }
@@ -14445,6 +15325,58 @@ func (v *CompositeValue) GetMember(interpreter *Interpreter, getLocationRange fu
 	return nil
 }
 
+// removedFields returns the names of the fields
+// which were removed from the composite's type using the `#removedField` pragma,
+// and which might still be stored in the value.
+//
+// If the program declaring the type is not loaded yet,
+// no fields are returned, and the fields are handled on a later access
+//
+func (v *CompositeValue) removedFields(interpreter *Interpreter) []string {
+	if v.removedFieldsPurged || v.Location == nil {
+		return nil
+	}
+
+	code, ok := interpreter.typeCodes.CompositeCodes[v.TypeID()]
+	if !ok {
+		return nil
+	}
+
+	return code.RemovedFields
+}
+
+// purgeRemovedFields removes the stored data of the fields
+// which were removed from the composite's type using the `#removedField` pragma.
+//
+// Removed fields are purged lazily, when the value is written or moved
+// after the program declaring the type was loaded.
+// Copies of the value omit the removed fields, but leave the value itself unchanged
+//
+func (v *CompositeValue) purgeRemovedFields(interpreter *Interpreter, getLocationRange func() LocationRange) {
+	removedFields := v.removedFields(interpreter)
+	if removedFields == nil {
+		return
+	}
+
+	v.removedFieldsPurged = true
+
+	for _, name := range removedFields {
+		v.RemoveField(interpreter, getLocationRange, name)
+	}
+}
+
+// isRemovedField returns true if the given stored field name
+// is one of the given removed fields
+//
+func isRemovedField(removedFields []string, atreeKey atree.Value) bool {
+	for _, name := range removedFields {
+		if atreeKey == StringAtreeValue(name) {
+			return true
+		}
+	}
+	return false
+}
+
 func (v *CompositeValue) checkInvalidatedResourceUse(getLocationRange func() LocationRange) {
 	if v.isDestroyed || (v.dictionary == nil && v.Kind == common.CompositeKindResource) {
 		panic(InvalidatedResourceError{
@@ -14579,6 +15511,8 @@ func (v *CompositeValue) SetMember(
 		}()
 	}
 
+	v.purgeRemovedFields(interpreter, getLocationRange)
+
 	address := v.StorageID().Address
 
 	value = value.Transfer(
@@ -14823,7 +15757,7 @@ func (v *CompositeValue) ConformsToStaticType(
 	compositeType, ok := semaType.(*sema.CompositeType)
 	if !ok ||
 		v.Kind != compositeType.Kind ||
-		v.TypeID() != compositeType.ID() {
+		staticType.TypeID != compositeType.ID() {
 
 		return false
 	}
@@ -14852,7 +15786,7 @@ func (v *CompositeValue) ConformsToStaticType(
 			value = fieldGetter(interpreter, getLocationRange)
 		}
 
-		member, ok := compositeType.Members.Get(fieldName)
+		member, ok := compositeType.ResolvedMembers().Get(fieldName)
 		if !ok {
 			return false
 		}
@@ -14950,6 +15884,17 @@ func (v *CompositeValue) Transfer(
 		}()
 	}
 
+	// Removed fields are only purged when the value is moved.
+	// Copying the value must not mutate the stored value,
+	// so the copy just omits the removed fields
+
+	var removedFields []string
+	if remove {
+		v.purgeRemovedFields(interpreter, getLocationRange)
+	} else {
+		removedFields = v.removedFields(interpreter)
+	}
+
 	currentStorageID := v.StorageID()
 	currentAddress := currentStorageID.Address
 
@@ -14977,12 +15922,20 @@ func (v *CompositeValue) Transfer(
 			v.dictionary.Seed(),
 			func() (atree.Value, atree.Value, error) {
 
-				atreeKey, atreeValue, err := iterator.Next()
-				if err != nil {
-					return nil, nil, err
-				}
-				if atreeKey == nil || atreeValue == nil {
-					return nil, nil, nil
+				var atreeKey, atreeValue atree.Value
+				var err error
+				for {
+					atreeKey, atreeValue, err = iterator.Next()
+					if err != nil {
+						return nil, nil, err
+					}
+					if atreeKey == nil || atreeValue == nil {
+						return nil, nil, nil
+					}
+
+					if !isRemovedField(removedFields, atreeKey) {
+						break
+					}
 				}
 
 				// NOTE: key is stringAtreeValue
@@ -15047,11 +16000,12 @@ func (v *CompositeValue) Transfer(
 	}
 
 	if res == nil {
-		info := NewCompositeTypeInfo(
+		info := NewInstantiatedCompositeTypeInfo(
 			interpreter,
 			v.Location,
 			v.QualifiedIdentifier,
 			v.Kind,
+			v.TypeArguments,
 		)
 		res = newCompositeValueFromOrderedMap(dictionary, info)
 		res.InjectedFields = v.InjectedFields
@@ -15132,6 +16086,7 @@ func (v *CompositeValue) Clone(interpreter *Interpreter) Value {
 		Location:            v.Location,
 		QualifiedIdentifier: v.QualifiedIdentifier,
 		Kind:                v.Kind,
+		TypeArguments:       v.TypeArguments,
 		InjectedFields:      v.InjectedFields,
 		ComputedFields:      v.ComputedFields,
 		NestedVariables:     v.NestedVariables,
@@ -15605,6 +16560,195 @@ func (v *DictionaryValue) SetKey(
 	}
 }
 
+// ForEachKey calls the given function for each key of the dictionary,
+// until the function returns false
+//
+func (v *DictionaryValue) ForEachKey(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	function FunctionValue,
+	functionType *sema.FunctionType,
+) {
+	argumentTypes := []sema.Type{
+		v.SemaType(interpreter).KeyType,
+	}
+
+	iterator, err := v.dictionary.Iterator()
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	interpreter.withContainerIteration(v.StorageID(), func() {
+		for {
+			key, err := iterator.NextKey()
+			if err != nil {
+				panic(ExternalError{err})
+			}
+			if key == nil {
+				return
+			}
+
+			interpreter.ReportComputation(common.ComputationKindLoop, 1)
+
+			resume, ok := interpreter.invokeFunctionArgument(
+				function,
+				functionType,
+				[]Value{MustConvertStoredValue(interpreter, key)},
+				argumentTypes,
+				getLocationRange,
+			).(BoolValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+
+			if !resume {
+				return
+			}
+		}
+	})
+}
+
+// Filter returns a new dictionary containing the entries
+// for which the given predicate returns true
+//
+func (v *DictionaryValue) Filter(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	predicate FunctionValue,
+	predicateType *sema.FunctionType,
+) *DictionaryValue {
+	dictionaryType := v.SemaType(interpreter)
+	argumentTypes := []sema.Type{
+		dictionaryType.KeyType,
+		dictionaryType.ValueType,
+	}
+
+	result := NewDictionaryValue(interpreter, v.Type)
+
+	iterator, err := v.dictionary.Iterator()
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	interpreter.withContainerIteration(v.StorageID(), func() {
+		for {
+			atreeKey, atreeValue, err := iterator.Next()
+			if err != nil {
+				panic(ExternalError{err})
+			}
+			if atreeKey == nil {
+				return
+			}
+
+			interpreter.ReportComputation(common.ComputationKindLoop, 1)
+
+			key := MustConvertStoredValue(interpreter, atreeKey)
+			value := MustConvertStoredValue(interpreter, atreeValue)
+
+			include, ok := interpreter.invokeFunctionArgument(
+				predicate,
+				predicateType,
+				[]Value{key, value},
+				argumentTypes,
+				getLocationRange,
+			).(BoolValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+
+			if !include {
+				continue
+			}
+
+			// Insert moves the given key and value,
+			// so insert copies to keep the entries of this dictionary intact
+
+			result.Insert(
+				interpreter,
+				getLocationRange,
+				key.Transfer(interpreter, getLocationRange, atree.Address{}, false, nil),
+				value.Transfer(interpreter, getLocationRange, atree.Address{}, false, nil),
+			)
+		}
+	})
+
+	return result
+}
+
+// Map returns a new dictionary with the same keys,
+// and the results of calling the given transform function for each entry as values
+//
+func (v *DictionaryValue) Map(
+	interpreter *Interpreter,
+	getLocationRange func() LocationRange,
+	transform FunctionValue,
+	transformType *sema.FunctionType,
+	resultValueType sema.Type,
+) *DictionaryValue {
+	dictionaryType := v.SemaType(interpreter)
+	argumentTypes := []sema.Type{
+		dictionaryType.KeyType,
+		dictionaryType.ValueType,
+	}
+
+	result := NewDictionaryValue(
+		interpreter,
+		NewDictionaryStaticType(
+			interpreter,
+			v.Type.KeyType,
+			ConvertSemaToStaticType(interpreter, resultValueType),
+		),
+	)
+
+	iterator, err := v.dictionary.Iterator()
+	if err != nil {
+		panic(ExternalError{err})
+	}
+
+	interpreter.withContainerIteration(v.StorageID(), func() {
+		for {
+			atreeKey, atreeValue, err := iterator.Next()
+			if err != nil {
+				panic(ExternalError{err})
+			}
+			if atreeKey == nil {
+				return
+			}
+
+			interpreter.ReportComputation(common.ComputationKindLoop, 1)
+
+			key := MustConvertStoredValue(interpreter, atreeKey)
+
+			value := interpreter.invokeFunctionArgument(
+				transform,
+				transformType,
+				[]Value{
+					key,
+					MustConvertStoredValue(interpreter, atreeValue),
+				},
+				argumentTypes,
+				getLocationRange,
+			)
+
+			value = interpreter.ConvertAndBox(
+				getLocationRange,
+				value,
+				transformType.ReturnTypeAnnotation.Type,
+				resultValueType,
+			)
+
+			result.Insert(
+				interpreter,
+				getLocationRange,
+				key.Transfer(interpreter, getLocationRange, atree.Address{}, false, nil),
+				value.Transfer(interpreter, getLocationRange, atree.Address{}, false, nil),
+			)
+		}
+	})
+
+	return result
+}
+
 func (v *DictionaryValue) String() string {
 	return v.RecursiveString(SeenReferences{})
 }
@@ -15785,6 +16929,62 @@ func (v *DictionaryValue) GetMember(
 			),
 		)
 
+	case "forEachKey":
+		return NewHostFunctionValue(
+			interpreter,
+			func(invocation Invocation) Value {
+				function, functionType := invocationFunctionArgument(invocation, 0)
+
+				v.ForEachKey(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					function,
+					functionType,
+				)
+
+				return NewVoidValue(invocation.Interpreter)
+			},
+			sema.DictionaryForEachKeyFunctionType(
+				v.SemaType(interpreter),
+			),
+		)
+
+	case "filter":
+		return NewHostFunctionValue(
+			interpreter,
+			func(invocation Invocation) Value {
+				predicate, predicateType := invocationFunctionArgument(invocation, 0)
+
+				return v.Filter(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					predicate,
+					predicateType,
+				)
+			},
+			sema.DictionaryFilterFunctionType(
+				v.SemaType(interpreter),
+			),
+		)
+
+	case "map":
+		return NewHostFunctionValue(
+			interpreter,
+			func(invocation Invocation) Value {
+				transform, transformType := invocationFunctionArgument(invocation, 0)
+
+				return v.Map(
+					invocation.Interpreter,
+					invocation.GetLocationRange,
+					transform,
+					transformType,
+					invocationTypeArgument(invocation),
+				)
+			},
+			sema.DictionaryMapFunctionType(
+				v.SemaType(interpreter),
+			),
+		)
 	}
 
 	return nil
@@ -15833,6 +17033,8 @@ func (v *DictionaryValue) Remove(
 	keyValue Value,
 ) OptionalValue {
 
+	interpreter.checkContainerNotIterated(v.StorageID(), getLocationRange)
+
 	valueComparator := newValueComparator(interpreter, getLocationRange)
 	hashInputProvider := newHashInputProvider(interpreter, getLocationRange)
 
@@ -15893,6 +17095,8 @@ func (v *DictionaryValue) Insert(
 	common.UseMemory(interpreter, dataSlabs)
 	common.UseMemory(interpreter, metaDataSlabs)
 
+	interpreter.checkContainerNotIterated(v.StorageID(), getLocationRange)
+
 	interpreter.checkContainerMutation(v.Type.KeyType, keyValue, getLocationRange)
 	interpreter.checkContainerMutation(v.Type.ValueType, value, getLocationRange)
 
@@ -16178,6 +17382,8 @@ func (v *DictionaryValue) Transfer(
 		}
 
 		if remove {
+			interpreter.checkContainerNotIterated(currentStorageID, getLocationRange)
+
 			err = v.dictionary.PopIterate(func(keyStorable atree.Storable, valueStorable atree.Storable) {
 				interpreter.RemoveReferencedSlab(keyStorable)
 				interpreter.RemoveReferencedSlab(valueStorable)
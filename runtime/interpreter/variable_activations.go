@@ -22,7 +22,10 @@
 
 package interpreter
 
-import "github.com/onflow/cadence/runtime/common"
+import (
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+)
 
 // A VariableActivation is a map of strings to values.
 // It can be used to represent an active scope in a program,
@@ -35,20 +38,27 @@ type VariableActivation struct {
 	Parent      *VariableActivation
 	isFunction  bool
 	memoryGauge common.MemoryGauge
+	// typeArguments are the types bound to the type parameters
+	// of the enclosing generic functions and composite types, if any.
+	// They are inherited from the parent activation
+	typeArguments *sema.TypeParameterTypeOrderedMap
 }
 
 func NewVariableActivation(memoryGauge common.MemoryGauge, parent *VariableActivation) *VariableActivation {
 	var depth int
+	var typeArguments *sema.TypeParameterTypeOrderedMap
 	if parent != nil {
 		depth = parent.Depth + 1
+		typeArguments = parent.typeArguments
 	}
 
 	common.UseMemory(memoryGauge, common.ActivationMemoryUsage)
 
 	return &VariableActivation{
-		Depth:       depth,
-		Parent:      parent,
-		memoryGauge: memoryGauge,
+		Depth:         depth,
+		Parent:        parent,
+		memoryGauge:   memoryGauge,
+		typeArguments: typeArguments,
 	}
 }
 
@@ -74,6 +84,37 @@ func (a *VariableActivation) Find(name string) *Variable {
 	return nil
 }
 
+// TypeArguments returns the types bound to the type parameters
+// of the enclosing generic functions and composite types.
+// It returns nil if there are none.
+//
+func (a *VariableActivation) TypeArguments() *sema.TypeParameterTypeOrderedMap {
+	return a.typeArguments
+}
+
+// BindTypeArguments binds the given type parameters to the given types
+// in the activation, in addition to the type arguments of the parent activation.
+//
+func (a *VariableActivation) BindTypeArguments(typeArguments *sema.TypeParameterTypeOrderedMap) {
+	if typeArguments == nil || typeArguments.Len() == 0 {
+		return
+	}
+
+	if a.typeArguments == nil {
+		a.typeArguments = typeArguments
+		return
+	}
+
+	combinedTypeArguments := sema.NewTypeParameterTypeOrderedMap()
+	a.typeArguments.Foreach(func(typeParameter *sema.TypeParameter, ty sema.Type) {
+		combinedTypeArguments.Set(typeParameter, ty)
+	})
+	typeArguments.Foreach(func(typeParameter *sema.TypeParameter, ty sema.Type) {
+		combinedTypeArguments.Set(typeParameter, ty)
+	})
+	a.typeArguments = combinedTypeArguments
+}
+
 // FunctionValues returns all values in the current function activation.
 //
 func (a *VariableActivation) FunctionValues() map[string]*Variable {
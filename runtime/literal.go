@@ -19,10 +19,13 @@
 package runtime
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
 	"github.com/onflow/cadence/fixedpoint"
 	"github.com/onflow/cadence/runtime/ast"
 	"github.com/onflow/cadence/runtime/common"
@@ -387,3 +390,281 @@ func LiteralValue(inter *interpreter.Interpreter, expression ast.Expression, ty
 
 	return nil, UnsupportedLiteralError
 }
+
+var LiteralValueTypeError = fmt.Errorf("value does not have the literal type")
+
+// FormatLiteral returns the literal expression for the given value, that should have the given type.
+// It is the inverse of ParseLiteral.
+//
+// Returns an error if the value does not have the given type,
+// or if the value cannot be expressed as a literal of the given type.
+//
+func FormatLiteral(value cadence.Value, ty sema.Type) (string, error) {
+	switch ty := ty.(type) {
+	case *sema.VariableSizedType:
+		array, ok := value.(cadence.Array)
+		if !ok {
+			return "", literalValueTypeError(value, ty)
+		}
+
+		return formatArrayLiteral(array.Values, ty.Type)
+
+	case *sema.ConstantSizedType:
+		array, ok := value.(cadence.Array)
+		if !ok || int64(len(array.Values)) != ty.Size {
+			return "", literalValueTypeError(value, ty)
+		}
+
+		return formatArrayLiteral(array.Values, ty.Type)
+
+	case *sema.OptionalType:
+		optional, ok := value.(cadence.Optional)
+		if !ok {
+			return "", literalValueTypeError(value, ty)
+		}
+
+		if optional.Value == nil {
+			return "nil", nil
+		}
+
+		// A nested nil cannot be expressed as a literal,
+		// it would be parsed as the outer nil
+
+		if innerOptional, ok := optional.Value.(cadence.Optional); ok && innerOptional.Value == nil {
+			return "", UnsupportedLiteralError
+		}
+
+		return FormatLiteral(optional.Value, ty.Type)
+
+	case *sema.DictionaryType:
+		dictionary, ok := value.(cadence.Dictionary)
+		if !ok {
+			return "", literalValueTypeError(value, ty)
+		}
+
+		var builder strings.Builder
+		builder.WriteByte('{')
+
+		for i, pair := range dictionary.Pairs {
+			if i > 0 {
+				builder.WriteString(", ")
+			}
+
+			key, err := FormatLiteral(pair.Key, ty.KeyType)
+			if err != nil {
+				return "", err
+			}
+
+			value, err := FormatLiteral(pair.Value, ty.ValueType)
+			if err != nil {
+				return "", err
+			}
+
+			builder.WriteString(key)
+			builder.WriteString(": ")
+			builder.WriteString(value)
+		}
+
+		builder.WriteByte('}')
+
+		return builder.String(), nil
+
+	case *sema.AddressType:
+		address, ok := value.(cadence.Address)
+		if !ok {
+			return "", literalValueTypeError(value, ty)
+		}
+
+		return address.String(), nil
+	}
+
+	switch ty {
+	case sema.BoolType:
+		boolValue, ok := value.(cadence.Bool)
+		if !ok {
+			return "", literalValueTypeError(value, ty)
+		}
+
+		return boolValue.String(), nil
+
+	case sema.StringType:
+		stringValue, ok := value.(cadence.String)
+		if !ok {
+			return "", literalValueTypeError(value, ty)
+		}
+
+		return stringValue.String(), nil
+	}
+
+	switch {
+	case sema.IsSameTypeKind(ty, sema.IntegerType):
+		// Abstract integer types are parsed as Int
+
+		expectedType := ty
+		switch ty {
+		case sema.IntegerType, sema.SignedIntegerType:
+			expectedType = sema.IntType
+		}
+
+		if value.Type() == nil || value.Type().ID() != string(expectedType.ID()) {
+			return "", literalValueTypeError(value, ty)
+		}
+
+		return value.String(), nil
+
+	case sema.IsSameTypeKind(ty, sema.FixedPointType):
+		// Abstract fixed-point types are parsed as Fix64
+
+		expectedType := ty
+		switch ty {
+		case sema.FixedPointType, sema.SignedFixedPointType:
+			expectedType = sema.Fix64Type
+		}
+
+		if value.Type() == nil || value.Type().ID() != string(expectedType.ID()) {
+			return "", literalValueTypeError(value, ty)
+		}
+
+		return value.String(), nil
+
+	case sema.IsSameTypeKind(ty, sema.PathType):
+		path, ok := value.(cadence.Path)
+		if !ok {
+			return "", literalValueTypeError(value, ty)
+		}
+
+		pathType, err := sema.CheckPathLiteral(
+			path.Domain,
+			path.Identifier,
+			func() ast.Range {
+				return ast.EmptyRange
+			},
+			func() ast.Range {
+				return ast.EmptyRange
+			},
+		)
+		if err != nil || !sema.IsSubType(pathType, ty) {
+			return "", literalValueTypeError(value, ty)
+		}
+
+		return path.String(), nil
+	}
+
+	return "", UnsupportedLiteralError
+}
+
+func formatArrayLiteral(values []cadence.Value, elementType sema.Type) (string, error) {
+	var builder strings.Builder
+	builder.WriteByte('[')
+
+	for i, value := range values {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+
+		element, err := FormatLiteral(value, elementType)
+		if err != nil {
+			return "", err
+		}
+
+		builder.WriteString(element)
+	}
+
+	builder.WriteByte(']')
+
+	return builder.String(), nil
+}
+
+func literalValueTypeError(value cadence.Value, ty sema.Type) error {
+	return fmt.Errorf("%w: expected %s, got %s", LiteralValueTypeError, ty, value)
+}
+
+// FormatLiteralArgumentList returns an argument list with literals for the given values,
+// that should have the given types.
+// It is the inverse of ParseLiteralArgumentList.
+//
+func FormatLiteralArgumentList(values []cadence.Value, parameterTypes []sema.Type) (string, error) {
+	valueCount := len(values)
+	parameterCount := len(parameterTypes)
+
+	if valueCount != parameterCount {
+		return "", fmt.Errorf(
+			"invalid number of arguments: got %d, expected %d",
+			valueCount,
+			parameterCount,
+		)
+	}
+
+	var builder strings.Builder
+	builder.WriteByte('(')
+
+	for i, value := range values {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+
+		literal, err := FormatLiteral(value, parameterTypes[i])
+		if err != nil {
+			return "", fmt.Errorf("invalid argument at index %d: %w", i, err)
+		}
+
+		builder.WriteString(literal)
+	}
+
+	builder.WriteByte(')')
+
+	return builder.String(), nil
+}
+
+// JSONToLiteralArgumentList converts the given JSON-Cadence encoded arguments,
+// that should have the given types, to an argument list with literals.
+//
+func JSONToLiteralArgumentList(
+	arguments [][]byte,
+	parameterTypes []sema.Type,
+	inter *interpreter.Interpreter,
+) (
+	string,
+	error,
+) {
+	values := make([]cadence.Value, len(arguments))
+
+	for i, argument := range arguments {
+		value, err := jsoncdc.Decode(inter, argument, ArgumentDecodingOptions()...)
+		if err != nil {
+			return "", fmt.Errorf("invalid argument at index %d: %w", i, err)
+		}
+		values[i] = value
+	}
+
+	return FormatLiteralArgumentList(values, parameterTypes)
+}
+
+// LiteralArgumentListToJSON converts the given argument list with literals,
+// that should have the given types, to JSON-Cadence encoded arguments.
+//
+func LiteralArgumentListToJSON(
+	argumentList string,
+	parameterTypes []sema.Type,
+	inter *interpreter.Interpreter,
+) (
+	[][]byte,
+	error,
+) {
+	values, err := ParseLiteralArgumentList(argumentList, parameterTypes, inter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][]byte, len(values))
+
+	for i, value := range values {
+		encoded, err := jsoncdc.Encode(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument at index %d: %w", i, err)
+		}
+		result[i] = bytes.TrimSuffix(encoded, []byte{'\n'})
+	}
+
+	return result, nil
+}
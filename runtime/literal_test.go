@@ -713,3 +713,165 @@ func TestParseLiteralArgumentList(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestFormatLiteral(t *testing.T) {
+
+	t.Parallel()
+
+	validLiterals := map[string]struct {
+		literal string
+		ty      sema.Type
+	}{
+		"String":                       {`"hello \"world\"\n"`, sema.StringType},
+		"Bool":                         {`true`, sema.BoolType},
+		"Int":                          {`42`, sema.IntType},
+		"Int, negative":                {`-42`, sema.IntType},
+		"Integer":                      {`42`, sema.IntegerType},
+		"Int8":                         {`-128`, sema.Int8Type},
+		"UInt256":                      {`115792089237316195423570985008687907853269984665640564039457584007913129639935`, sema.UInt256Type},
+		"Word64":                       {`18446744073709551615`, sema.Word64Type},
+		"Fix64":                        {`-1.50000000`, sema.Fix64Type},
+		"FixedPoint":                   {`1.50000000`, sema.FixedPointType},
+		"UFix64":                       {`1.50000000`, sema.UFix64Type},
+		"Address":                      {`0x0000000000000001`, &sema.AddressType{}},
+		"StoragePath":                  {`/storage/foo`, sema.StoragePathType},
+		"CapabilityPath":               {`/public/foo`, sema.CapabilityPathType},
+		"Optional, nil":                {`nil`, &sema.OptionalType{Type: sema.IntType}},
+		"Optional, some":               {`1`, &sema.OptionalType{Type: sema.IntType}},
+		"VariableSizedArray":           {`[1, 2, 3]`, &sema.VariableSizedType{Type: sema.IntType}},
+		"ConstantSizedArray":           {`[true, false]`, &sema.ConstantSizedType{Type: sema.BoolType, Size: 2}},
+		"Dictionary":                   {`{"a": [1], "b": []}`, &sema.DictionaryType{KeyType: sema.StringType, ValueType: &sema.VariableSizedType{Type: sema.UInt8Type}}},
+		"Dictionary, empty":            {`{}`, &sema.DictionaryType{KeyType: sema.StringType, ValueType: sema.IntType}},
+		"Optional, array of optionals": {`[nil]`, &sema.OptionalType{Type: &sema.VariableSizedType{Type: &sema.OptionalType{Type: sema.IntType}}}},
+	}
+
+	for name, test := range validLiterals { //nolint:maprangecheck
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			value, err := ParseLiteral(test.literal, test.ty, nil)
+			require.NoError(t, err)
+
+			literal, err := FormatLiteral(value, test.ty)
+			require.NoError(t, err)
+			require.Equal(t, test.literal, literal)
+		})
+	}
+
+	t.Run("type mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		for _, test := range []struct {
+			value cadence.Value
+			ty    sema.Type
+		}{
+			{cadence.String("1"), sema.IntType},
+			{cadence.NewInt8(1), sema.IntType},
+			{cadence.NewInt(1), sema.UIntType},
+			{cadence.Fix64(1), sema.UFix64Type},
+			{cadence.NewArray([]cadence.Value{cadence.NewInt(1)}), &sema.ConstantSizedType{Type: sema.IntType, Size: 2}},
+			{cadence.NewArray([]cadence.Value{cadence.String("a")}), &sema.VariableSizedType{Type: sema.IntType}},
+			{cadence.Path{Domain: "public", Identifier: "foo"}, sema.StoragePathType},
+			{cadence.NewBool(true), &sema.OptionalType{Type: sema.BoolType}},
+		} {
+			_, err := FormatLiteral(test.value, test.ty)
+			require.ErrorIs(t, err, LiteralValueTypeError)
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := FormatLiteral(cadence.NewInt(1), sema.AnyStructType)
+		require.ErrorIs(t, err, UnsupportedLiteralError)
+
+		// a nested nil cannot be expressed as a literal
+		_, err = FormatLiteral(
+			cadence.NewOptional(cadence.NewOptional(nil)),
+			&sema.OptionalType{Type: &sema.OptionalType{Type: sema.IntType}},
+		)
+		require.ErrorIs(t, err, UnsupportedLiteralError)
+	})
+}
+
+func TestLiteralArgumentListJSON(t *testing.T) {
+
+	t.Parallel()
+
+	parameterTypes := []sema.Type{
+		sema.StringType,
+		&sema.OptionalType{Type: &sema.AddressType{}},
+		&sema.DictionaryType{KeyType: sema.StringType, ValueType: sema.UFix64Type},
+	}
+
+	jsonArguments := [][]byte{
+		[]byte(`{"type":"String","value":"hello"}`),
+		[]byte(`{"type":"Optional","value":{"type":"Address","value":"0x0000000000000001"}}`),
+		[]byte(`{"type":"Dictionary","value":[{"key":{"type":"String","value":"a"},"value":{"type":"UFix64","value":"1.00000000"}}]}`),
+	}
+
+	const literalArguments = `("hello", 0x0000000000000001, {"a": 1.00000000})`
+
+	t.Run("JSON to literal", func(t *testing.T) {
+
+		t.Parallel()
+
+		argumentList, err := JSONToLiteralArgumentList(jsonArguments, parameterTypes, nil)
+		require.NoError(t, err)
+		require.Equal(t, literalArguments, argumentList)
+	})
+
+	t.Run("literal to JSON", func(t *testing.T) {
+
+		t.Parallel()
+
+		arguments, err := LiteralArgumentListToJSON(literalArguments, parameterTypes, nil)
+		require.NoError(t, err)
+		require.Len(t, arguments, len(jsonArguments))
+
+		for i, argument := range arguments {
+			require.JSONEq(t, string(jsonArguments[i]), string(argument))
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := JSONToLiteralArgumentList(
+			[][]byte{[]byte(`{"type":"Int"`)},
+			[]sema.Type{sema.IntType},
+			nil,
+		)
+		require.Error(t, err)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := JSONToLiteralArgumentList(
+			[][]byte{[]byte(`{"type":"Int","value":"1"}`)},
+			[]sema.Type{sema.UInt8Type},
+			nil,
+		)
+		require.ErrorIs(t, err, LiteralValueTypeError)
+	})
+
+	t.Run("wrong number of arguments", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := JSONToLiteralArgumentList(
+			[][]byte{[]byte(`{"type":"Int","value":"1"}`)},
+			nil,
+			nil,
+		)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,99 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package migrations
+
+import (
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// FieldMigration converts the values of a field of a composite type,
+// e.g. when the type of the field changed
+type FieldMigration struct {
+	typeID    common.TypeID
+	fieldName string
+	convert   func(inter *interpreter.Interpreter, value interpreter.Value) (interpreter.Value, error)
+}
+
+var _ ValueMigration = &FieldMigration{}
+
+// NewFieldMigration returns a migration which converts the values of the given field
+// of composite values of the given type using the given function.
+//
+// The function returns the converted value, or nil if the value does not have to be converted
+func NewFieldMigration(
+	typeID common.TypeID,
+	fieldName string,
+	convert func(inter *interpreter.Interpreter, value interpreter.Value) (interpreter.Value, error),
+) *FieldMigration {
+	return &FieldMigration{
+		typeID:    typeID,
+		fieldName: fieldName,
+		convert:   convert,
+	}
+}
+
+func (*FieldMigration) Name() string {
+	return "FieldMigration"
+}
+
+func (m *FieldMigration) CanMigrate(_ *interpreter.Interpreter, value interpreter.Value) bool {
+	compositeValue, ok := value.(*interpreter.CompositeValue)
+	return ok && compositeValue.TypeID() == m.typeID
+}
+
+func (m *FieldMigration) Migrate(inter *interpreter.Interpreter, value interpreter.Value) (interpreter.Value, error) {
+	compositeValue, ok := value.(*interpreter.CompositeValue)
+	if !ok || compositeValue.TypeID() != m.typeID {
+		return nil, nil
+	}
+
+	fields := compositeFields(inter, compositeValue)
+
+	var converted bool
+
+	for i, field := range fields {
+		if field.Name != m.fieldName {
+			continue
+		}
+
+		convertedValue, err := m.convert(inter, field.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		if convertedValue != nil {
+			fields[i].Value = convertedValue
+			converted = true
+		}
+	}
+
+	if !converted {
+		return nil, nil
+	}
+
+	return interpreter.NewCompositeValue(
+		inter,
+		compositeValue.Location,
+		compositeValue.QualifiedIdentifier,
+		compositeValue.Kind,
+		fields,
+		common.Address{},
+	), nil
+}
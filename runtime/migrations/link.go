@@ -0,0 +1,60 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package migrations
+
+import (
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// LinkMigration re-wraps links, e.g. to change their target path or their type
+type LinkMigration struct {
+	migrate func(inter *interpreter.Interpreter, link interpreter.LinkValue) (interpreter.Value, error)
+}
+
+var _ ValueMigration = &LinkMigration{}
+
+// NewLinkMigration returns a migration which migrates links using the given function.
+//
+// The function returns the value which replaces the link,
+// or nil if the link does not have to be migrated
+func NewLinkMigration(
+	migrate func(inter *interpreter.Interpreter, link interpreter.LinkValue) (interpreter.Value, error),
+) *LinkMigration {
+	return &LinkMigration{
+		migrate: migrate,
+	}
+}
+
+func (*LinkMigration) Name() string {
+	return "LinkMigration"
+}
+
+func (*LinkMigration) CanMigrate(_ *interpreter.Interpreter, value interpreter.Value) bool {
+	_, ok := value.(interpreter.LinkValue)
+	return ok
+}
+
+func (m *LinkMigration) Migrate(inter *interpreter.Interpreter, value interpreter.Value) (interpreter.Value, error) {
+	link, ok := value.(interpreter.LinkValue)
+	if !ok {
+		return nil, nil
+	}
+
+	return m.migrate(inter, link)
+}
@@ -0,0 +1,494 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package migrations
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// ValueMigration migrates stored values, e.g. values of a certain static type.
+//
+// Values are migrated bottom-up: When a value is migrated,
+// its nested values have already been migrated
+type ValueMigration interface {
+	// Name returns the name of the migration, which is used in reports
+	Name() string
+
+	// CanMigrate returns true if the given value might be migrated.
+	// The value must not be modified
+	CanMigrate(inter *interpreter.Interpreter, value interpreter.Value) bool
+
+	// Migrate returns the migrated value for the given value,
+	// or nil if the value does not have to be migrated.
+	//
+	// The given value is a temporary copy of the stored value,
+	// so the migrated value may reuse it, or its nested values
+	Migrate(inter *interpreter.Interpreter, value interpreter.Value) (interpreter.Value, error)
+}
+
+// StaticTypeMigration is implemented by value migrations which also migrate static types.
+//
+// When a container is rebuilt because its nested values were migrated,
+// its static type is migrated, so that the migrated nested values are valid elements
+type StaticTypeMigration interface {
+	ValueMigration
+
+	// MigrateStaticType returns the migrated static type for the given static type,
+	// or nil if the static type does not have to be migrated
+	MigrateStaticType(staticType interpreter.StaticType) interpreter.StaticType
+}
+
+// Change describes a stored value which was migrated
+type Change struct {
+	Address common.Address
+	Domain  string
+	Key     string
+	// Migrations are the names of the migrations which migrated the value, or its nested values
+	Migrations []string
+}
+
+// domains are the domains of account storage which are migrated
+var domains = []string{
+	common.PathDomainStorage.Identifier(),
+	common.PathDomainPublic.Identifier(),
+	common.PathDomainPrivate.Identifier(),
+	runtime.StorageDomainContract,
+}
+
+// StorageMigration migrates the values stored in accounts using value migrations.
+//
+// All storage maps of the accounts are walked,
+// and each stored value which contains values that might be migrated
+// is replaced by its migrated value.
+//
+// Changes are only written when the migration is committed,
+// so a dry-run can be performed by not committing.
+//
+// Stored values are copied and transferred, so the types of stored values
+// must still be loadable, e.g. a renamed type must still be declared until the migration is committed
+type StorageMigration struct {
+	storage     *runtime.Storage
+	interpreter *interpreter.Interpreter
+	migrations  []ValueMigration
+}
+
+func NewStorageMigration(
+	storage *runtime.Storage,
+	inter *interpreter.Interpreter,
+	migrations ...ValueMigration,
+) *StorageMigration {
+	return &StorageMigration{
+		storage:     storage,
+		interpreter: inter,
+		migrations:  migrations,
+	}
+}
+
+// MigrateStorage migrates the values stored in the given accounts using the given migrations,
+// and returns the changes.
+//
+// In dry-run mode the changes are only reported, but not written
+func MigrateStorage(
+	rt runtime.Runtime,
+	context runtime.Context,
+	addresses []common.Address,
+	dryRun bool,
+	migrations ...ValueMigration,
+) ([]Change, error) {
+
+	storage, inter, err := rt.Storage(context)
+	if err != nil {
+		return nil, err
+	}
+
+	migration := NewStorageMigration(storage, inter, migrations...)
+
+	changes, err := migration.Migrate(addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return changes, nil
+	}
+
+	err = migration.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// Migrate migrates the values stored in the given accounts, and returns the changes.
+//
+// The changes are not written until the migration is committed
+func (m *StorageMigration) Migrate(addresses []common.Address) (changes []Change, err error) {
+	for _, address := range addresses {
+		for _, domain := range domains {
+			storageMap := m.storage.GetStorageMap(address, domain, false)
+			if storageMap == nil {
+				continue
+			}
+
+			// The storage map is modified, so iterate over the keys first
+
+			var keys []string
+			iterator := storageMap.Iterator(m.interpreter)
+			for key := iterator.NextKey(); key != ""; key = iterator.NextKey() {
+				keys = append(keys, key)
+			}
+
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				migrations, err := m.migrateStoredValue(address, storageMap, key)
+				if err != nil {
+					return nil, fmt.Errorf(
+						"failed to migrate value /%s/%s of account %s: %w",
+						domain,
+						key,
+						address.HexWithPrefix(),
+						err,
+					)
+				}
+
+				if len(migrations) == 0 {
+					continue
+				}
+
+				changes = append(
+					changes,
+					Change{
+						Address:    address,
+						Domain:     domain,
+						Key:        key,
+						Migrations: migrations,
+					},
+				)
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// Commit writes the changes of the migration
+func (m *StorageMigration) Commit() error {
+	return m.storage.Commit(m.interpreter, false)
+}
+
+// migrateStoredValue migrates the value stored in the given storage map for the given key,
+// and returns the names of the migrations which migrated it
+func (m *StorageMigration) migrateStoredValue(
+	address common.Address,
+	storageMap *interpreter.StorageMap,
+	key string,
+) (
+	migrations []string,
+	err error,
+) {
+	inter := m.interpreter
+
+	defer inter.RecoverErrors(func(internalErr error) {
+		err = internalErr
+	})
+
+	value := storageMap.ReadValue(inter, key)
+
+	if !m.canMigrate(value) {
+		return nil, nil
+	}
+
+	// Migrate a temporary copy of the stored value,
+	// and replace the stored value with the migrated value.
+	//
+	// The temporary values are not stored in an account,
+	// so they are never written
+
+	temporaryValue := value.Transfer(
+		inter,
+		interpreter.ReturnEmptyLocationRange,
+		atree.Address{},
+		false,
+		nil,
+	)
+
+	migratedValue, err := m.migrateNestedValue(temporaryValue, func(name string) {
+		for _, migration := range migrations {
+			if migration == name {
+				return
+			}
+		}
+		migrations = append(migrations, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if migratedValue == nil {
+		return nil, nil
+	}
+
+	storageMap.SetValue(
+		inter,
+		key,
+		migratedValue.Transfer(
+			inter,
+			interpreter.ReturnEmptyLocationRange,
+			atree.Address(address),
+			true,
+			nil,
+		),
+	)
+
+	return migrations, nil
+}
+
+// canMigrate returns true if any of the migrations might migrate the given value, or one of its nested values
+func (m *StorageMigration) canMigrate(value interpreter.Value) bool {
+	var result bool
+
+	interpreter.InspectValue(
+		m.interpreter,
+		value,
+		func(value interpreter.Value) bool {
+			if result {
+				return false
+			}
+
+			for _, migration := range m.migrations {
+				if migration.CanMigrate(m.interpreter, value) {
+					result = true
+					return false
+				}
+			}
+
+			return true
+		},
+	)
+
+	return result
+}
+
+// migrateNestedValue migrates the nested values of the given value, and then the value itself.
+// It returns the migrated value, or nil if the value was not migrated.
+//
+// Containers with migrated nested values are rebuilt,
+// so the given value must be a temporary value
+func (m *StorageMigration) migrateNestedValue(
+	value interpreter.Value,
+	report func(name string),
+) (
+	migratedValue interpreter.Value,
+	err error,
+) {
+	inter := m.interpreter
+
+	switch value := value.(type) {
+	case *interpreter.SomeValue:
+		innerValue := value.InnerValue(inter, interpreter.ReturnEmptyLocationRange)
+
+		var migratedInnerValue interpreter.Value
+		migratedInnerValue, err = m.migrateNestedValue(innerValue, report)
+		if err != nil {
+			return nil, err
+		}
+
+		if migratedInnerValue != nil {
+			migratedValue = interpreter.NewSomeValueNonCopying(inter, migratedInnerValue)
+		}
+
+	case *interpreter.ArrayValue:
+		var elements []interpreter.Value
+		var migrated bool
+
+		value.Iterate(inter, func(element interpreter.Value) (resume bool) {
+			var migratedElement interpreter.Value
+			migratedElement, err = m.migrateNestedValue(element, report)
+			if err != nil {
+				return false
+			}
+
+			if migratedElement != nil {
+				element = migratedElement
+				migrated = true
+			}
+
+			elements = append(elements, element)
+
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if migrated {
+			migratedValue = interpreter.NewArrayValue(
+				inter,
+				m.migrateStaticType(value.Type).(interpreter.ArrayStaticType),
+				common.Address{},
+				elements...,
+			)
+		}
+
+	case *interpreter.DictionaryValue:
+		var keysAndValues []interpreter.Value
+		var migrated bool
+
+		value.Iterate(inter, func(key, value interpreter.Value) (resume bool) {
+			var migratedKey, migratedEntryValue interpreter.Value
+
+			migratedKey, err = m.migrateNestedValue(key, report)
+			if err != nil {
+				return false
+			}
+
+			if migratedKey != nil {
+				key = migratedKey
+				migrated = true
+			}
+
+			migratedEntryValue, err = m.migrateNestedValue(value, report)
+			if err != nil {
+				return false
+			}
+
+			if migratedEntryValue != nil {
+				value = migratedEntryValue
+				migrated = true
+			}
+
+			keysAndValues = append(keysAndValues, key, value)
+
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if migrated {
+			migratedValue = interpreter.NewDictionaryValueWithAddress(
+				inter,
+				m.migrateStaticType(value.Type).(interpreter.DictionaryStaticType),
+				common.Address{},
+				keysAndValues...,
+			)
+		}
+
+	case *interpreter.CompositeValue:
+		var fields []interpreter.CompositeField
+		var migrated bool
+
+		value.ForEachField(inter, func(name string, fieldValue interpreter.Value) {
+			if err != nil {
+				return
+			}
+
+			var migratedFieldValue interpreter.Value
+			migratedFieldValue, err = m.migrateNestedValue(fieldValue, report)
+			if err != nil {
+				return
+			}
+
+			if migratedFieldValue != nil {
+				fieldValue = migratedFieldValue
+				migrated = true
+			}
+
+			fields = append(fields, interpreter.NewCompositeField(inter, name, fieldValue))
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if migrated {
+			migratedValue = interpreter.NewCompositeValue(
+				inter,
+				value.Location,
+				value.QualifiedIdentifier,
+				value.Kind,
+				fields,
+				common.Address{},
+			)
+		}
+	}
+
+	// Migrate the value itself
+
+	current := value
+	if migratedValue != nil {
+		current = migratedValue
+	}
+
+	for _, migration := range m.migrations {
+		if !migration.CanMigrate(inter, current) {
+			continue
+		}
+
+		var result interpreter.Value
+		result, err = migration.Migrate(inter, current)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", migration.Name(), err)
+		}
+
+		if result == nil {
+			continue
+		}
+
+		current = result
+		migratedValue = result
+		report(migration.Name())
+	}
+
+	return migratedValue, nil
+}
+
+// migrateStaticType migrates the given static type using all static type migrations
+func (m *StorageMigration) migrateStaticType(staticType interpreter.StaticType) interpreter.StaticType {
+	for _, migration := range m.migrations {
+		staticTypeMigration, ok := migration.(StaticTypeMigration)
+		if !ok {
+			continue
+		}
+
+		migratedStaticType := staticTypeMigration.MigrateStaticType(staticType)
+		if migratedStaticType != nil {
+			staticType = migratedStaticType
+		}
+	}
+
+	return staticType
+}
+
+// compositeFields returns the fields of the given composite value
+func compositeFields(inter *interpreter.Interpreter, value *interpreter.CompositeValue) []interpreter.CompositeField {
+	var fields []interpreter.CompositeField
+	value.ForEachField(inter, func(name string, fieldValue interpreter.Value) {
+		fields = append(fields, interpreter.NewCompositeField(inter, name, fieldValue))
+	})
+	return fields
+}
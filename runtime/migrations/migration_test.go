@@ -0,0 +1,442 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package migrations
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence"
+	"github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/inmemory"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+type testEnvironment struct {
+	runtime          runtime.Runtime
+	runtimeInterface *inmemory.Interface
+	address          common.Address
+	executionCount   uint64
+}
+
+func newTestEnvironment(t *testing.T) *testEnvironment {
+	runtimeInterface := inmemory.NewInterface()
+
+	address, err := runtimeInterface.CreateAccount(common.Address{})
+	require.NoError(t, err)
+
+	return &testEnvironment{
+		runtime:          runtime.NewInterpreterRuntime(),
+		runtimeInterface: runtimeInterface,
+		address:          address,
+	}
+}
+
+func (e *testEnvironment) nextLocation() []byte {
+	e.executionCount++
+	var id [8]byte
+	binary.BigEndian.PutUint64(id[:], e.executionCount)
+	return id[:]
+}
+
+func (e *testEnvironment) executeTransaction(t *testing.T, code string) {
+	e.runtimeInterface.SetSigningAccounts(e.address)
+
+	err := e.runtime.ExecuteTransaction(
+		runtime.Script{
+			Source: []byte(code),
+		},
+		runtime.Context{
+			Interface: e.runtimeInterface,
+			Location:  common.TransactionLocation(e.nextLocation()),
+		},
+	)
+	require.NoError(t, err)
+}
+
+func (e *testEnvironment) executeScript(t *testing.T, code string) cadence.Value {
+	value, err := e.runtime.ExecuteScript(
+		runtime.Script{
+			Source: []byte(code),
+		},
+		runtime.Context{
+			Interface: e.runtimeInterface,
+			Location:  common.ScriptLocation(e.nextLocation()),
+		},
+	)
+	require.NoError(t, err)
+	return value
+}
+
+func (e *testEnvironment) deployContract(t *testing.T, code string) {
+	e.executeTransaction(
+		t,
+		fmt.Sprintf(
+			`
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      signer.contracts.add(name: "Test", code: "%s".decodeHex())
+                  }
+              }
+            `,
+			hex.EncodeToString([]byte(code)),
+		),
+	)
+}
+
+// updateContract updates the code of the test contract,
+// without validating the update or migrating the stored values
+func (e *testEnvironment) updateContract(t *testing.T, code string) {
+	err := e.runtimeInterface.UpdateAccountContractCode(e.address, "Test", []byte(code))
+	require.NoError(t, err)
+
+	location := common.AddressLocation{
+		Address: e.address,
+		Name:    "Test",
+	}
+	err = e.runtimeInterface.SetProgram(location, nil)
+	require.NoError(t, err)
+}
+
+func (e *testEnvironment) migrate(t *testing.T, dryRun bool, migrations ...ValueMigration) ([]Change, error) {
+	return MigrateStorage(
+		e.runtime,
+		runtime.Context{
+			Interface: e.runtimeInterface,
+			Location:  common.TransactionLocation(e.nextLocation()),
+		},
+		[]common.Address{e.address},
+		dryRun,
+		migrations...,
+	)
+}
+
+func TestTypeRenameMigration(t *testing.T) {
+
+	t.Parallel()
+
+	env := newTestEnvironment(t)
+
+	env.deployContract(t, `
+      pub contract Test {
+
+          pub struct Foo {
+              pub let x: Int
+
+              init(x: Int) {
+                  self.x = x
+              }
+          }
+
+          pub resource R {
+              pub let foos: [Foo]
+
+              init() {
+                  self.foos = [Foo(x: 1), Foo(x: 2)]
+              }
+          }
+
+          pub fun createR(): @R {
+              return <-create R()
+          }
+      }
+    `)
+
+	env.executeTransaction(t, `
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save(Test.Foo(x: 42), to: /storage/foo)
+              signer.save(<-Test.createR(), to: /storage/r)
+              signer.save({"a": Test.Foo(x: 3)}, to: /storage/dict)
+              signer.save(Type<Test.Foo>(), to: /storage/type)
+              signer.save(42, to: /storage/number)
+              signer.link<&Test.R>(/public/r, target: /storage/r)
+              signer.link<&Test.Foo>(/public/foo, target: /storage/foo)
+          }
+      }
+    `)
+
+	// The old type is kept until the stored values are migrated,
+	// as the types of stored containers must be loadable
+
+	env.updateContract(t, `
+      pub contract Test {
+
+          pub struct Foo {
+              pub let x: Int
+
+              init(x: Int) {
+                  self.x = x
+              }
+          }
+
+          pub struct Bar {
+              pub let x: Int
+
+              init(x: Int) {
+                  self.x = x
+              }
+          }
+
+          pub resource R {
+              pub let foos: [Bar]
+
+              init() {
+                  self.foos = [Bar(x: 1), Bar(x: 2)]
+              }
+          }
+
+          pub fun createR(): @R {
+              return <-create R()
+          }
+      }
+    `)
+
+	migration, err := NewTypeRenameMigration(map[common.TypeID]common.TypeID{
+		"A.0000000000000001.Test.Foo": "A.0000000000000001.Test.Bar",
+	})
+	require.NoError(t, err)
+
+	expectedChanges := []Change{
+		{Address: env.address, Domain: "storage", Key: "dict", Migrations: []string{"TypeRenameMigration"}},
+		{Address: env.address, Domain: "storage", Key: "foo", Migrations: []string{"TypeRenameMigration"}},
+		{Address: env.address, Domain: "storage", Key: "r", Migrations: []string{"TypeRenameMigration"}},
+		{Address: env.address, Domain: "storage", Key: "type", Migrations: []string{"TypeRenameMigration"}},
+		{Address: env.address, Domain: "public", Key: "foo", Migrations: []string{"TypeRenameMigration"}},
+	}
+
+	// A dry-run only reports the changes
+
+	changes, err := env.migrate(t, true, migration)
+	require.NoError(t, err)
+	assert.Equal(t, expectedChanges, changes)
+
+	changes, err = env.migrate(t, true, migration)
+	require.NoError(t, err)
+	assert.Equal(t, expectedChanges, changes)
+
+	// The migration writes the changes
+
+	changes, err = env.migrate(t, false, migration)
+	require.NoError(t, err)
+	assert.Equal(t, expectedChanges, changes)
+
+	changes, err = env.migrate(t, false, migration)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	value := env.executeScript(t, `
+      import Test from 0x1
+
+      pub fun main(): [AnyStruct] {
+          let account = getAuthAccount(0x1)
+          let r = account.borrow<&Test.R>(from: /storage/r)!
+          return [
+              account.borrow<&Test.Bar>(from: /storage/foo)!.x,
+              r.foos[1].x,
+              r.foos.getType() == Type<[Test.Bar]>(),
+              account.copy<{String: Test.Bar}>(from: /storage/dict)!["a"]!.x,
+              account.copy<Type>(from: /storage/type)! == Type<Test.Bar>(),
+              account.copy<Int>(from: /storage/number)!,
+              getAccount(0x1).getCapability<&Test.R>(/public/r).borrow()!.foos.length,
+              getAccount(0x1).getCapability<&Test.Bar>(/public/foo).borrow()!.x
+          ]
+      }
+    `)
+
+	assert.Equal(t,
+		cadence.NewArray([]cadence.Value{
+			cadence.NewInt(42),
+			cadence.NewInt(2),
+			cadence.NewBool(true),
+			cadence.NewInt(3),
+			cadence.NewBool(true),
+			cadence.NewInt(42),
+			cadence.NewInt(2),
+			cadence.NewInt(42),
+		}),
+		value,
+	)
+}
+
+func TestFieldMigration(t *testing.T) {
+
+	t.Parallel()
+
+	env := newTestEnvironment(t)
+
+	env.deployContract(t, `
+      pub contract Test {
+
+          pub struct S {
+              pub let value: Int
+
+              init(value: Int) {
+                  self.value = value
+              }
+          }
+      }
+    `)
+
+	env.executeTransaction(t, `
+      import Test from 0x1
+
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save([Test.S(value: 1), Test.S(value: 2)], to: /storage/s)
+          }
+      }
+    `)
+
+	env.updateContract(t, `
+      pub contract Test {
+
+          pub struct S {
+              pub let value: String
+
+              init(value: String) {
+                  self.value = value
+              }
+          }
+      }
+    `)
+
+	migration := NewFieldMigration(
+		"A.0000000000000001.Test.S",
+		"value",
+		func(inter *interpreter.Interpreter, value interpreter.Value) (interpreter.Value, error) {
+			intValue, ok := value.(interpreter.IntValue)
+			if !ok {
+				return nil, nil
+			}
+			return interpreter.NewUnmeteredStringValue(intValue.String()), nil
+		},
+	)
+
+	changes, err := env.migrate(t, false, migration)
+	require.NoError(t, err)
+	assert.Equal(t,
+		[]Change{
+			{Address: env.address, Domain: "storage", Key: "s", Migrations: []string{"FieldMigration"}},
+		},
+		changes,
+	)
+
+	value := env.executeScript(t, `
+      import Test from 0x1
+
+      pub fun main(): [String] {
+          let values = getAuthAccount(0x1).borrow<&[Test.S]>(from: /storage/s)!
+          return [values[0].value, values[1].value]
+      }
+    `)
+
+	assert.Equal(t,
+		cadence.NewArray([]cadence.Value{
+			cadence.String("1"),
+			cadence.String("2"),
+		}),
+		value,
+	)
+}
+
+func TestLinkMigration(t *testing.T) {
+
+	t.Parallel()
+
+	env := newTestEnvironment(t)
+
+	env.executeTransaction(t, `
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.save(1, to: /storage/old)
+              signer.save(2, to: /storage/new)
+              signer.link<&Int>(/public/number, target: /storage/old)
+          }
+      }
+    `)
+
+	migration := NewLinkMigration(
+		func(_ *interpreter.Interpreter, link interpreter.LinkValue) (interpreter.Value, error) {
+			if link.TargetPath.Identifier != "old" {
+				return nil, nil
+			}
+			return interpreter.LinkValue{
+				TargetPath: interpreter.PathValue{
+					Domain:     common.PathDomainStorage,
+					Identifier: "new",
+				},
+				Type: link.Type,
+			}, nil
+		},
+	)
+
+	changes, err := env.migrate(t, false, migration)
+	require.NoError(t, err)
+	assert.Equal(t,
+		[]Change{
+			{Address: env.address, Domain: "public", Key: "number", Migrations: []string{"LinkMigration"}},
+		},
+		changes,
+	)
+
+	value := env.executeScript(t, `
+      pub fun main(): String {
+          return getAccount(0x1).getCapability<&Int>(/public/number).borrow()!.toString()
+      }
+    `)
+
+	assert.Equal(t, cadence.String("2"), value)
+}
+
+func TestMigrationError(t *testing.T) {
+
+	t.Parallel()
+
+	env := newTestEnvironment(t)
+
+	env.executeTransaction(t, `
+      transaction {
+          prepare(signer: AuthAccount) {
+              signer.link<&Int>(/public/number, target: /storage/number)
+          }
+      }
+    `)
+
+	migrationErr := errors.New("broken")
+
+	migration := NewLinkMigration(
+		func(_ *interpreter.Interpreter, _ interpreter.LinkValue) (interpreter.Value, error) {
+			return nil, migrationErr
+		},
+	)
+
+	_, err := env.migrate(t, false, migration)
+	require.ErrorIs(t, err, migrationErr)
+	assert.Contains(t, err.Error(), "failed to migrate value /public/number of account 0x0000000000000001")
+}
@@ -0,0 +1,296 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package migrations
+
+import (
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+type typeName struct {
+	location            common.Location
+	qualifiedIdentifier string
+}
+
+// TypeRenameMigration renames composite and interface types.
+//
+// Composite values of renamed types are migrated to values of the new types,
+// and renamed types are replaced in the static types of arrays, dictionaries,
+// links, capabilities, and type values
+type TypeRenameMigration struct {
+	renames map[common.TypeID]typeName
+}
+
+var _ StaticTypeMigration = &TypeRenameMigration{}
+
+// NewTypeRenameMigration returns a migration which renames the types with the given type IDs
+// to the types with the given new type IDs
+func NewTypeRenameMigration(renames map[common.TypeID]common.TypeID) (*TypeRenameMigration, error) {
+	migration := &TypeRenameMigration{
+		renames: make(map[common.TypeID]typeName, len(renames)),
+	}
+
+	for oldTypeID, newTypeID := range renames { //nolint:maprangecheck
+		location, qualifiedIdentifier, err := common.DecodeTypeID(nil, string(newTypeID))
+		if err != nil {
+			return nil, err
+		}
+
+		migration.renames[oldTypeID] = typeName{
+			location:            location,
+			qualifiedIdentifier: qualifiedIdentifier,
+		}
+	}
+
+	return migration, nil
+}
+
+func (*TypeRenameMigration) Name() string {
+	return "TypeRenameMigration"
+}
+
+func (m *TypeRenameMigration) CanMigrate(_ *interpreter.Interpreter, value interpreter.Value) bool {
+	switch value := value.(type) {
+	case *interpreter.CompositeValue:
+		_, ok := m.renames[value.TypeID()]
+		return ok
+
+	case *interpreter.ArrayValue:
+		_, ok := m.rewriteStaticType(value.Type)
+		return ok
+
+	case *interpreter.DictionaryValue:
+		_, ok := m.rewriteStaticType(value.Type)
+		return ok
+
+	case interpreter.LinkValue:
+		_, ok := m.rewriteStaticType(value.Type)
+		return ok
+
+	case *interpreter.CapabilityValue:
+		_, ok := m.rewriteStaticType(value.BorrowType)
+		return ok
+
+	case interpreter.TypeValue:
+		_, ok := m.rewriteStaticType(value.Type)
+		return ok
+	}
+
+	return false
+}
+
+func (m *TypeRenameMigration) Migrate(inter *interpreter.Interpreter, value interpreter.Value) (interpreter.Value, error) {
+	switch value := value.(type) {
+	case *interpreter.CompositeValue:
+		name, ok := m.renames[value.TypeID()]
+		if !ok {
+			return nil, nil
+		}
+
+		return interpreter.NewCompositeValue(
+			inter,
+			name.location,
+			name.qualifiedIdentifier,
+			value.Kind,
+			compositeFields(inter, value),
+			common.Address{},
+		), nil
+
+	case *interpreter.ArrayValue:
+		staticType, ok := m.rewriteStaticType(value.Type)
+		if !ok {
+			return nil, nil
+		}
+
+		var elements []interpreter.Value
+		value.Iterate(inter, func(element interpreter.Value) (resume bool) {
+			elements = append(elements, element)
+			return true
+		})
+
+		return interpreter.NewArrayValue(
+			inter,
+			staticType.(interpreter.ArrayStaticType),
+			common.Address{},
+			elements...,
+		), nil
+
+	case *interpreter.DictionaryValue:
+		staticType, ok := m.rewriteStaticType(value.Type)
+		if !ok {
+			return nil, nil
+		}
+
+		var keysAndValues []interpreter.Value
+		value.Iterate(inter, func(key, value interpreter.Value) (resume bool) {
+			keysAndValues = append(keysAndValues, key, value)
+			return true
+		})
+
+		return interpreter.NewDictionaryValueWithAddress(
+			inter,
+			staticType.(interpreter.DictionaryStaticType),
+			common.Address{},
+			keysAndValues...,
+		), nil
+
+	case interpreter.LinkValue:
+		staticType, ok := m.rewriteStaticType(value.Type)
+		if !ok {
+			return nil, nil
+		}
+
+		return interpreter.LinkValue{
+			TargetPath: value.TargetPath,
+			Type:       staticType,
+		}, nil
+
+	case *interpreter.CapabilityValue:
+		staticType, ok := m.rewriteStaticType(value.BorrowType)
+		if !ok {
+			return nil, nil
+		}
+
+		return interpreter.NewCapabilityValue(
+			inter,
+			value.Address,
+			value.Path,
+			staticType,
+		), nil
+
+	case interpreter.TypeValue:
+		staticType, ok := m.rewriteStaticType(value.Type)
+		if !ok {
+			return nil, nil
+		}
+
+		return interpreter.NewTypeValue(inter, staticType), nil
+	}
+
+	return nil, nil
+}
+
+func (m *TypeRenameMigration) MigrateStaticType(staticType interpreter.StaticType) interpreter.StaticType {
+	rewrittenType, ok := m.rewriteStaticType(staticType)
+	if !ok {
+		return nil
+	}
+	return rewrittenType
+}
+
+// rewriteStaticType returns the given static type with all renamed types replaced,
+// and true if any type was renamed
+func (m *TypeRenameMigration) rewriteStaticType(staticType interpreter.StaticType) (interpreter.StaticType, bool) {
+	switch staticType := staticType.(type) {
+	case interpreter.CompositeStaticType:
+		name, ok := m.renames[staticType.TypeID]
+		if !ok {
+			return staticType, false
+		}
+
+		return interpreter.NewCompositeStaticTypeComputeTypeID(
+			nil,
+			name.location,
+			name.qualifiedIdentifier,
+		), true
+
+	case interpreter.InterfaceStaticType:
+		rewrittenType, ok := m.rewriteInterfaceStaticType(staticType)
+		return rewrittenType, ok
+
+	case interpreter.VariableSizedStaticType:
+		elementType, ok := m.rewriteStaticType(staticType.Type)
+		return interpreter.VariableSizedStaticType{
+			Type: elementType,
+		}, ok
+
+	case interpreter.ConstantSizedStaticType:
+		elementType, ok := m.rewriteStaticType(staticType.Type)
+		return interpreter.ConstantSizedStaticType{
+			Type: elementType,
+			Size: staticType.Size,
+		}, ok
+
+	case interpreter.DictionaryStaticType:
+		keyType, keyTypeRenamed := m.rewriteStaticType(staticType.KeyType)
+		valueType, valueTypeRenamed := m.rewriteStaticType(staticType.ValueType)
+		return interpreter.DictionaryStaticType{
+			KeyType:   keyType,
+			ValueType: valueType,
+		}, keyTypeRenamed || valueTypeRenamed
+
+	case interpreter.OptionalStaticType:
+		innerType, ok := m.rewriteStaticType(staticType.Type)
+		return interpreter.OptionalStaticType{
+			Type: innerType,
+		}, ok
+
+	case *interpreter.RestrictedStaticType:
+		restrictedType, renamed := m.rewriteStaticType(staticType.Type)
+
+		restrictions := make([]interpreter.InterfaceStaticType, len(staticType.Restrictions))
+		for i, restriction := range staticType.Restrictions {
+			var restrictionRenamed bool
+			restrictions[i], restrictionRenamed = m.rewriteInterfaceStaticType(restriction)
+			renamed = renamed || restrictionRenamed
+		}
+
+		return &interpreter.RestrictedStaticType{
+			Type:         restrictedType,
+			Restrictions: restrictions,
+		}, renamed
+
+	case interpreter.ReferenceStaticType:
+		borrowedType, borrowedTypeRenamed := m.rewriteStaticType(staticType.BorrowedType)
+		referencedType, referencedTypeRenamed := m.rewriteStaticType(staticType.ReferencedType)
+		return interpreter.ReferenceStaticType{
+			Authorized:     staticType.Authorized,
+			BorrowedType:   borrowedType,
+			ReferencedType: referencedType,
+		}, borrowedTypeRenamed || referencedTypeRenamed
+
+	case interpreter.CapabilityStaticType:
+		borrowType, ok := m.rewriteStaticType(staticType.BorrowType)
+		return interpreter.CapabilityStaticType{
+			BorrowType: borrowType,
+		}, ok
+	}
+
+	return staticType, false
+}
+
+func (m *TypeRenameMigration) rewriteInterfaceStaticType(
+	staticType interpreter.InterfaceStaticType,
+) (
+	interpreter.InterfaceStaticType,
+	bool,
+) {
+	typeID := common.NewTypeIDFromQualifiedName(nil, staticType.Location, staticType.QualifiedIdentifier)
+
+	name, ok := m.renames[typeID]
+	if !ok {
+		return staticType, false
+	}
+
+	return interpreter.NewInterfaceStaticType(
+		nil,
+		name.location,
+		name.qualifiedIdentifier,
+	), true
+}
@@ -77,7 +77,31 @@ func parseDeclaration(p *parser, docString string) ast.Declaration {
 				return parseVariableDeclaration(p, access, accessPos, docString)
 
 			case keywordFun:
-				return parseFunctionDeclaration(p, false, access, accessPos, docString)
+				return parseFunctionDeclaration(
+					p,
+					false,
+					access,
+					accessPos,
+					ast.FunctionPurityUnspecified,
+					nil,
+					docString,
+				)
+
+			case keywordView:
+				purity, purityPos := parsePurityAnnotation(p, keywordFun)
+				if purityPos == nil {
+					return nil
+				}
+
+				return parseFunctionDeclaration(
+					p,
+					false,
+					access,
+					accessPos,
+					purity,
+					purityPos,
+					docString,
+				)
 
 			case keywordImport:
 				return parseImportDeclaration(p)
@@ -88,6 +112,9 @@ func parseDeclaration(p *parser, docString string) ast.Declaration {
 			case keywordStruct, keywordResource, keywordContract, keywordEnum:
 				return parseCompositeOrInterfaceDeclaration(p, access, accessPos, docString)
 
+			case keywordTypeAlias:
+				return parseTypeAliasDeclaration(p, access, accessPos, docString)
+
 			case KeywordTransaction:
 				if access != ast.AccessNotSpecified {
 					panic(fmt.Errorf("invalid access modifier for transaction"))
@@ -659,7 +686,9 @@ func parseEventDeclaration(
 		ast.NewFunctionDeclaration(
 			p.memoryGauge,
 			ast.AccessNotSpecified,
+			ast.FunctionPurityUnspecified,
 			ast.NewEmptyIdentifier(p.memoryGauge, ast.EmptyPosition),
+			nil,
 			parameterList,
 			nil,
 			nil,
@@ -681,6 +710,7 @@ func parseEventDeclaration(
 		common.CompositeKindEvent,
 		identifier,
 		nil,
+		nil,
 		members,
 		docString,
 		ast.NewRange(
@@ -841,6 +871,8 @@ func parseCompositeOrInterfaceDeclaration(
 		}
 	}
 
+	typeParameterList := parseTypeParameterList(p)
+
 	p.skipSpaceAndComments(true)
 
 	var conformances []*ast.NominalType
@@ -882,6 +914,10 @@ func parseCompositeOrInterfaceDeclaration(
 			panic(fmt.Errorf("unexpected conformances"))
 		}
 
+		if typeParameterList != nil {
+			panic(fmt.Errorf("unexpected type parameters for interface"))
+		}
+
 		return ast.NewInterfaceDeclaration(
 			p.memoryGauge,
 			access,
@@ -897,6 +933,7 @@ func parseCompositeOrInterfaceDeclaration(
 			access,
 			compositeKind,
 			identifier,
+			typeParameterList,
 			conformances,
 			members,
 			docString,
@@ -950,6 +987,8 @@ func parseMembersAndNestedDeclarations(p *parser, endTokenType lexer.TokenType)
 //                               | compositeDeclaration
 //                               | eventDeclaration
 //                               | enumCase
+//                               | typeAliasDeclaration
+//                               | pragma
 //
 func parseMemberOrNestedDeclaration(p *parser, docString string) ast.Declaration {
 
@@ -964,6 +1003,12 @@ func parseMemberOrNestedDeclaration(p *parser, docString string) ast.Declaration
 		p.skipSpaceAndComments(true)
 
 		switch p.current.Type {
+		case lexer.TokenPragma:
+			if access != ast.AccessNotSpecified {
+				panic(fmt.Errorf("invalid access modifier for pragma"))
+			}
+			return parsePragmaDeclaration(p)
+
 		case lexer.TokenIdentifier:
 			switch p.current.Value {
 			case keywordLet, keywordVar:
@@ -973,7 +1018,15 @@ func parseMemberOrNestedDeclaration(p *parser, docString string) ast.Declaration
 				return parseEnumCase(p, access, accessPos, docString)
 
 			case keywordFun:
-				return parseFunctionDeclaration(p, functionBlockIsOptional, access, accessPos, docString)
+				return parseFunctionDeclaration(
+					p,
+					functionBlockIsOptional,
+					access,
+					accessPos,
+					ast.FunctionPurityUnspecified,
+					nil,
+					docString,
+				)
 
 			case keywordEvent:
 				return parseEventDeclaration(p, access, accessPos, docString)
@@ -981,6 +1034,9 @@ func parseMemberOrNestedDeclaration(p *parser, docString string) ast.Declaration
 			case keywordStruct, keywordResource, keywordContract, keywordEnum:
 				return parseCompositeOrInterfaceDeclaration(p, access, accessPos, docString)
 
+			case keywordTypeAlias:
+				return parseTypeAliasDeclaration(p, access, accessPos, docString)
+
 			case keywordPriv, keywordPub, keywordAccess:
 				if access != ast.AccessNotSpecified {
 					panic(fmt.Errorf("unexpected access modifier"))
@@ -990,6 +1046,51 @@ func parseMemberOrNestedDeclaration(p *parser, docString string) ast.Declaration
 				access = parseAccess(p)
 				continue
 
+			case keywordView:
+				if previousIdentifierToken != nil {
+					panic(fmt.Errorf("unexpected %s", p.current.Type))
+				}
+
+				// The `view` keyword is only a purity modifier
+				// if it is followed by the `fun` or `init` keyword.
+				// Otherwise, it is an identifier, e.g. the name of a field
+
+				purity, purityPos := parsePurityAnnotation(p, keywordFun, keywordInit)
+				if purityPos == nil {
+					t := p.current
+					previousIdentifierToken = &t
+					// Skip the identifier
+					p.next()
+					continue
+				}
+
+				if p.current.Value == keywordFun {
+					return parseFunctionDeclaration(
+						p,
+						functionBlockIsOptional,
+						access,
+						accessPos,
+						purity,
+						purityPos,
+						docString,
+					)
+				}
+
+				identifier := p.tokenToIdentifier(p.current)
+				// Skip the `init` keyword
+				p.next()
+				p.skipSpaceAndComments(true)
+
+				return parseSpecialFunctionDeclaration(
+					p,
+					functionBlockIsOptional,
+					access,
+					accessPos,
+					purity,
+					purityPos,
+					identifier,
+				)
+
 			default:
 				if previousIdentifierToken != nil {
 					panic(fmt.Errorf("unexpected %s", p.current.Type))
@@ -1016,7 +1117,15 @@ func parseMemberOrNestedDeclaration(p *parser, docString string) ast.Declaration
 			}
 
 			identifier := p.tokenToIdentifier(*previousIdentifierToken)
-			return parseSpecialFunctionDeclaration(p, functionBlockIsOptional, access, accessPos, identifier)
+			return parseSpecialFunctionDeclaration(
+				p,
+				functionBlockIsOptional,
+				access,
+				accessPos,
+				ast.FunctionPurityUnspecified,
+				nil,
+				identifier,
+			)
 		}
 
 		return nil
@@ -1062,12 +1171,16 @@ func parseSpecialFunctionDeclaration(
 	functionBlockIsOptional bool,
 	access ast.Access,
 	accessPos *ast.Position,
+	purity ast.FunctionPurity,
+	purityPos *ast.Position,
 	identifier ast.Identifier,
 ) *ast.SpecialFunctionDeclaration {
 
 	startPos := identifier.Pos
 	if accessPos != nil {
 		startPos = *accessPos
+	} else if purityPos != nil {
+		startPos = *purityPos
 	}
 
 	// TODO: switch to parseFunctionParameterListAndRest once old parser is deprecated:
@@ -1103,7 +1216,9 @@ func parseSpecialFunctionDeclaration(
 		ast.NewFunctionDeclaration(
 			p.memoryGauge,
 			access,
+			purity,
 			identifier,
+			nil,
 			parameterList,
 			nil,
 			functionBlock,
@@ -1152,3 +1267,54 @@ func parseEnumCase(
 		startPos,
 	)
 }
+
+// parseTypeAliasDeclaration parses a type alias declaration.
+//
+//     typeAliasDeclaration : 'typealias' identifier '=' type
+//
+func parseTypeAliasDeclaration(
+	p *parser,
+	access ast.Access,
+	accessPos *ast.Position,
+	docString string,
+) *ast.TypeAliasDeclaration {
+
+	startPos := p.current.StartPos
+	if accessPos != nil {
+		startPos = *accessPos
+	}
+
+	// Skip the `typealias` keyword
+	p.next()
+
+	p.skipSpaceAndComments(true)
+	if !p.current.Is(lexer.TokenIdentifier) {
+		panic(fmt.Errorf(
+			"expected identifier after start of type alias declaration, got %s",
+			p.current.Type,
+		))
+	}
+
+	identifier := p.tokenToIdentifier(p.current)
+	// Skip the identifier
+	p.next()
+
+	p.skipSpaceAndComments(true)
+	p.mustOne(lexer.TokenEqual)
+
+	p.skipSpaceAndComments(true)
+	ty := parseType(p, lowestBindingPower)
+
+	return ast.NewTypeAliasDeclaration(
+		p.memoryGauge,
+		access,
+		identifier,
+		ty,
+		docString,
+		ast.NewRange(
+			p.memoryGauge,
+			startPos,
+			ty.EndPosition(p.memoryGauge),
+		),
+	)
+}
@@ -521,6 +521,94 @@ func TestParseFunctionDeclaration(t *testing.T) {
 		)
 	})
 
+	t.Run("type parameters", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseDeclarations("fun foo<T, U: AnyStruct>() { }", nil)
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			[]ast.Declaration{
+				&ast.FunctionDeclaration{
+					Identifier: ast.Identifier{
+						Identifier: "foo",
+						Pos:        ast.Position{Line: 1, Column: 4, Offset: 4},
+					},
+					TypeParameterList: &ast.TypeParameterList{
+						TypeParameters: []*ast.TypeParameter{
+							{
+								Identifier: ast.Identifier{
+									Identifier: "T",
+									Pos:        ast.Position{Line: 1, Column: 8, Offset: 8},
+								},
+							},
+							{
+								Identifier: ast.Identifier{
+									Identifier: "U",
+									Pos:        ast.Position{Line: 1, Column: 11, Offset: 11},
+								},
+								TypeBound: &ast.NominalType{
+									Identifier: ast.Identifier{
+										Identifier: "AnyStruct",
+										Pos:        ast.Position{Line: 1, Column: 14, Offset: 14},
+									},
+								},
+							},
+						},
+						Range: ast.Range{
+							StartPos: ast.Position{Line: 1, Column: 7, Offset: 7},
+							EndPos:   ast.Position{Line: 1, Column: 23, Offset: 23},
+						},
+					},
+					ParameterList: &ast.ParameterList{
+						Parameters: nil,
+						Range: ast.Range{
+							StartPos: ast.Position{Line: 1, Column: 24, Offset: 24},
+							EndPos:   ast.Position{Line: 1, Column: 25, Offset: 25},
+						},
+					},
+					ReturnTypeAnnotation: &ast.TypeAnnotation{
+						IsResource: false,
+						Type: &ast.NominalType{
+							Identifier: ast.Identifier{
+								Identifier: "",
+								Pos:        ast.Position{Line: 1, Column: 25, Offset: 25},
+							},
+						},
+						StartPos: ast.Position{Line: 1, Column: 25, Offset: 25},
+					},
+					FunctionBlock: &ast.FunctionBlock{
+						Block: &ast.Block{
+							Range: ast.Range{
+								StartPos: ast.Position{Line: 1, Column: 27, Offset: 27},
+								EndPos:   ast.Position{Line: 1, Column: 29, Offset: 29},
+							},
+						},
+					},
+					StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("empty type parameter list", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, errs := ParseDeclarations("fun foo<>() { }", nil)
+		utils.AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: "expected at least one type parameter",
+					Pos:     ast.Position{Offset: 9, Line: 1, Column: 9},
+				},
+			},
+			errs,
+		)
+	})
+
 	t.Run("without return type, pub", func(t *testing.T) {
 
 		t.Parallel()
@@ -956,6 +1044,97 @@ func TestParseFunctionDeclaration(t *testing.T) {
 			result,
 		)
 	})
+
+	t.Run("view", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseDeclarations("pub view fun foo () { }", nil)
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			[]ast.Declaration{
+				&ast.FunctionDeclaration{
+					Access: ast.AccessPublic,
+					Purity: ast.FunctionPurityView,
+					Identifier: ast.Identifier{
+						Identifier: "foo",
+						Pos:        ast.Position{Line: 1, Column: 13, Offset: 13},
+					},
+					ParameterList: &ast.ParameterList{
+						Parameters: nil,
+						Range: ast.Range{
+							StartPos: ast.Position{Line: 1, Column: 17, Offset: 17},
+							EndPos:   ast.Position{Line: 1, Column: 18, Offset: 18},
+						},
+					},
+					ReturnTypeAnnotation: &ast.TypeAnnotation{
+						IsResource: false,
+						Type: &ast.NominalType{
+							Identifier: ast.Identifier{
+								Identifier: "",
+								Pos:        ast.Position{Line: 1, Column: 18, Offset: 18},
+							},
+						},
+						StartPos: ast.Position{Line: 1, Column: 18, Offset: 18},
+					},
+					FunctionBlock: &ast.FunctionBlock{
+						Block: &ast.Block{
+							Range: ast.Range{
+								StartPos: ast.Position{Line: 1, Column: 20, Offset: 20},
+								EndPos:   ast.Position{Line: 1, Column: 22, Offset: 22},
+							},
+						},
+					},
+					StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("view, without function", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, errs := ParseDeclarations("view let x = 1", nil)
+		require.NotEmpty(t, errs)
+	})
+
+	t.Run("view as identifier", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseDeclarations("let view = 1", nil)
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			[]ast.Declaration{
+				&ast.VariableDeclaration{
+					IsConstant: true,
+					Identifier: ast.Identifier{
+						Identifier: "view",
+						Pos:        ast.Position{Line: 1, Column: 4, Offset: 4},
+					},
+					Value: &ast.IntegerExpression{
+						PositiveLiteral: "1",
+						Value:           big.NewInt(1),
+						Base:            10,
+						Range: ast.Range{
+							StartPos: ast.Position{Line: 1, Column: 11, Offset: 11},
+							EndPos:   ast.Position{Line: 1, Column: 11, Offset: 11},
+						},
+					},
+					Transfer: &ast.Transfer{
+						Operation: ast.TransferOperationCopy,
+						Pos:       ast.Position{Line: 1, Column: 9, Offset: 9},
+					},
+					StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+				},
+			},
+			result,
+		)
+	})
 }
 
 func TestParseAccess(t *testing.T) {
@@ -1989,6 +2168,110 @@ func TestParseInterfaceDeclaration(t *testing.T) {
 
 	t.Parallel()
 
+	t.Run("struct, with view functions and view initializer", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseDeclarations("struct S { let view: Int view init() {} view fun foo() {} }", nil)
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			[]ast.Declaration{
+				&ast.CompositeDeclaration{
+					CompositeKind: common.CompositeKindStructure,
+					Identifier: ast.Identifier{
+						Identifier: "S",
+						Pos:        ast.Position{Offset: 7, Line: 1, Column: 7},
+					},
+					Members: ast.NewUnmeteredMembers(
+						[]ast.Declaration{
+							&ast.FieldDeclaration{
+								VariableKind: ast.VariableKindConstant,
+								Identifier: ast.Identifier{
+									Identifier: "view",
+									Pos:        ast.Position{Offset: 15, Line: 1, Column: 15},
+								},
+								TypeAnnotation: &ast.TypeAnnotation{
+									Type: &ast.NominalType{
+										Identifier: ast.Identifier{
+											Identifier: "Int",
+											Pos:        ast.Position{Offset: 21, Line: 1, Column: 21},
+										},
+									},
+									StartPos: ast.Position{Offset: 21, Line: 1, Column: 21},
+								},
+								Range: ast.Range{
+									StartPos: ast.Position{Offset: 11, Line: 1, Column: 11},
+									EndPos:   ast.Position{Offset: 23, Line: 1, Column: 23},
+								},
+							},
+							&ast.SpecialFunctionDeclaration{
+								Kind: common.DeclarationKindInitializer,
+								FunctionDeclaration: &ast.FunctionDeclaration{
+									Purity: ast.FunctionPurityView,
+									Identifier: ast.Identifier{
+										Identifier: "init",
+										Pos:        ast.Position{Offset: 30, Line: 1, Column: 30},
+									},
+									ParameterList: &ast.ParameterList{
+										Range: ast.Range{
+											StartPos: ast.Position{Offset: 34, Line: 1, Column: 34},
+											EndPos:   ast.Position{Offset: 35, Line: 1, Column: 35},
+										},
+									},
+									FunctionBlock: &ast.FunctionBlock{
+										Block: &ast.Block{
+											Range: ast.Range{
+												StartPos: ast.Position{Offset: 37, Line: 1, Column: 37},
+												EndPos:   ast.Position{Offset: 38, Line: 1, Column: 38},
+											},
+										},
+									},
+									StartPos: ast.Position{Offset: 25, Line: 1, Column: 25},
+								},
+							},
+							&ast.FunctionDeclaration{
+								Purity: ast.FunctionPurityView,
+								Identifier: ast.Identifier{
+									Identifier: "foo",
+									Pos:        ast.Position{Offset: 49, Line: 1, Column: 49},
+								},
+								ParameterList: &ast.ParameterList{
+									Range: ast.Range{
+										StartPos: ast.Position{Offset: 52, Line: 1, Column: 52},
+										EndPos:   ast.Position{Offset: 53, Line: 1, Column: 53},
+									},
+								},
+								ReturnTypeAnnotation: &ast.TypeAnnotation{
+									Type: &ast.NominalType{
+										Identifier: ast.Identifier{
+											Pos: ast.Position{Offset: 53, Line: 1, Column: 53},
+										},
+									},
+									StartPos: ast.Position{Offset: 53, Line: 1, Column: 53},
+								},
+								FunctionBlock: &ast.FunctionBlock{
+									Block: &ast.Block{
+										Range: ast.Range{
+											StartPos: ast.Position{Offset: 55, Line: 1, Column: 55},
+											EndPos:   ast.Position{Offset: 56, Line: 1, Column: 56},
+										},
+									},
+								},
+								StartPos: ast.Position{Offset: 40, Line: 1, Column: 40},
+							},
+						},
+					),
+					Range: ast.Range{
+						StartPos: ast.Position{Offset: 0, Line: 1, Column: 0},
+						EndPos:   ast.Position{Offset: 58, Line: 1, Column: 58},
+					},
+				},
+			},
+			result,
+		)
+	})
+
 	t.Run("struct, no conformances", func(t *testing.T) {
 
 		t.Parallel()
@@ -3926,6 +4209,61 @@ func TestParsePragmaArguments(t *testing.T) {
 	)
 }
 
+func TestParseMemberPragma(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		const code = `
+          struct S {
+              #removedField("a")
+
+              let b: Int
+          }
+	    `
+		actual, err := ParseProgram(code, nil)
+		require.NoError(t, err)
+
+		compositeDeclarations := actual.CompositeDeclarations()
+		require.Len(t, compositeDeclarations, 1)
+
+		members := compositeDeclarations[0].Members
+		require.Len(t, members.Fields(), 1)
+
+		pragmas := members.Pragmas()
+		require.Len(t, pragmas, 1)
+
+		invocation, ok := pragmas[0].Expression.(*ast.InvocationExpression)
+		require.True(t, ok)
+
+		require.Equal(t,
+			"removedField",
+			invocation.InvokedExpression.(*ast.IdentifierExpression).Identifier.Identifier,
+		)
+		require.Len(t, invocation.Arguments, 1)
+		require.Equal(t,
+			"a",
+			invocation.Arguments[0].Expression.(*ast.StringExpression).Value,
+		)
+	})
+
+	t.Run("access modifier", func(t *testing.T) {
+
+		t.Parallel()
+
+		const code = `
+          struct S {
+              pub #removedField("a")
+          }
+	    `
+		_, err := ParseProgram(code, nil)
+		require.Error(t, err)
+	})
+}
+
 func TestParseImportWithString(t *testing.T) {
 
 	t.Parallel()
@@ -5001,3 +5339,133 @@ func TestParseInvalidAccessModifiers(t *testing.T) {
 		)
 	})
 }
+
+func TestParseTypeAliasDeclaration(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("simple", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseDeclarations("typealias A = Int", nil)
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			[]ast.Declaration{
+				&ast.TypeAliasDeclaration{
+					Access: ast.AccessNotSpecified,
+					Identifier: ast.Identifier{
+						Identifier: "A",
+						Pos:        ast.Position{Offset: 10, Line: 1, Column: 10},
+					},
+					Type: &ast.NominalType{
+						Identifier: ast.Identifier{
+							Identifier: "Int",
+							Pos:        ast.Position{Offset: 14, Line: 1, Column: 14},
+						},
+					},
+					Range: ast.Range{
+						StartPos: ast.Position{Offset: 0, Line: 1, Column: 0},
+						EndPos:   ast.Position{Offset: 16, Line: 1, Column: 16},
+					},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("access modifier, doc string, optional type", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseDeclarations("/// Doc\npub typealias A = Int?", nil)
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			[]ast.Declaration{
+				&ast.TypeAliasDeclaration{
+					Access: ast.AccessPublic,
+					Identifier: ast.Identifier{
+						Identifier: "A",
+						Pos:        ast.Position{Offset: 22, Line: 2, Column: 14},
+					},
+					Type: &ast.OptionalType{
+						Type: &ast.NominalType{
+							Identifier: ast.Identifier{
+								Identifier: "Int",
+								Pos:        ast.Position{Offset: 26, Line: 2, Column: 18},
+							},
+						},
+						EndPos: ast.Position{Offset: 29, Line: 2, Column: 21},
+					},
+					DocString: " Doc",
+					Range: ast.Range{
+						StartPos: ast.Position{Offset: 8, Line: 2, Column: 0},
+						EndPos:   ast.Position{Offset: 29, Line: 2, Column: 21},
+					},
+				},
+			},
+			result,
+		)
+	})
+
+	t.Run("nested in composite", func(t *testing.T) {
+
+		t.Parallel()
+
+		const code = `
+          contract C {
+              typealias Balance = UFix64
+
+              let balance: Balance
+          }
+	    `
+		actual, err := ParseProgram(code, nil)
+		require.NoError(t, err)
+
+		compositeDeclarations := actual.CompositeDeclarations()
+		require.Len(t, compositeDeclarations, 1)
+
+		members := compositeDeclarations[0].Members
+		require.Len(t, members.Fields(), 1)
+
+		typeAliases := members.TypeAliases()
+		require.Len(t, typeAliases, 1)
+
+		require.Equal(t, "Balance", typeAliases[0].Identifier.Identifier)
+		require.Equal(t, "UFix64", typeAliases[0].Type.String())
+	})
+
+	t.Run("missing identifier", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, errs := ParseDeclarations("typealias = Int", nil)
+		utils.AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: "expected identifier after start of type alias declaration, got '='",
+					Pos:     ast.Position{Offset: 10, Line: 1, Column: 10},
+				},
+			},
+			errs,
+		)
+	})
+
+	t.Run("missing equal sign", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, errs := ParseDeclarations("typealias A Int", nil)
+		utils.AssertEqualWithDiff(t,
+			[]error{
+				&SyntaxError{
+					Message: "expected token '='",
+					Pos:     ast.Position{Offset: 12, Line: 1, Column: 12},
+				},
+			},
+			errs,
+		)
+	})
+}
@@ -523,6 +523,7 @@ func init() {
 	defineNestedExpression()
 	defineInvocationExpression()
 	defineArrayExpression()
+	defineStringTemplateExpression()
 	defineDictionaryExpression()
 	defineIndexExpression()
 	definePathExpression()
@@ -805,7 +806,36 @@ func defineIdentifierExpression() {
 				)
 
 			case keywordFun:
-				return parseFunctionExpression(p, token)
+				return parseFunctionExpression(p, token, ast.FunctionPurityUnspecified)
+
+			case keywordView:
+				// The `view` keyword is only a purity modifier
+				// if it is followed by the `fun` keyword.
+				// Otherwise, it is an identifier.
+				//
+				// NOTE: no lookahead at the end of the input,
+				// buffering is not possible at the EOF token
+
+				if !p.current.Is(lexer.TokenEOF) {
+					p.startBuffering()
+					p.skipSpaceAndComments(true)
+
+					if p.current.IsString(lexer.TokenIdentifier, keywordFun) {
+						p.acceptBuffered()
+
+						// Skip the `fun` keyword
+						p.next()
+
+						return parseFunctionExpression(p, token, ast.FunctionPurityView)
+					}
+
+					p.replayBuffered()
+				}
+
+				return ast.NewIdentifierExpression(
+					p.memoryGauge,
+					p.tokenToIdentifier(token),
+				)
 
 			default:
 				return ast.NewIdentifierExpression(
@@ -817,17 +847,22 @@ func defineIdentifierExpression() {
 	})
 }
 
-func parseFunctionExpression(p *parser, token lexer.Token) *ast.FunctionExpression {
+func parseFunctionExpression(
+	p *parser,
+	startToken lexer.Token,
+	purity ast.FunctionPurity,
+) *ast.FunctionExpression {
 
 	parameterList, returnTypeAnnotation, functionBlock :=
 		parseFunctionParameterListAndRest(p, false)
 
 	return ast.NewFunctionExpression(
 		p.memoryGauge,
+		purity,
 		parameterList,
 		returnTypeAnnotation,
 		functionBlock,
-		token.StartPos,
+		startToken.StartPos,
 	)
 }
 
@@ -1023,6 +1058,77 @@ func defineNestedExpression() {
 	)
 }
 
+func defineStringTemplateExpression() {
+	setExprNullDenotation(
+		lexer.TokenStringTemplateHead,
+		func(p *parser, startToken lexer.Token) ast.Expression {
+
+			// The head of the template starts with the opening quote,
+			// and ends with the start of the first embedded expression (`\(`)
+
+			head := startToken.Value.(string)
+			value, errs := parseStringLiteralContent(head[1 : len(head)-2])
+			p.report(errs...)
+
+			values := []string{value}
+			var expressions []ast.Expression
+
+			for {
+				expression := parseExpression(p, lowestBindingPower)
+				expressions = append(expressions, expression)
+
+				p.skipSpaceAndComments(true)
+
+				token := p.current
+
+				switch token.Type {
+				case lexer.TokenStringTemplateMiddle:
+
+					// The middle part of the template starts with the end of the previous embedded expression (`)`),
+					// and ends with the start of the next embedded expression (`\(`)
+
+					p.next()
+
+					middle := token.Value.(string)
+					value, errs := parseStringLiteralContent(middle[1 : len(middle)-2])
+					p.report(errs...)
+
+					values = append(values, value)
+
+				case lexer.TokenStringTemplateTail:
+
+					// The tail of the template starts with the end of the last embedded expression (`)`),
+					// and ends with the closing quote
+
+					p.next()
+
+					value, errs := parseStringTemplateTail(token.Value.(string))
+					p.report(errs...)
+
+					values = append(values, value)
+
+					return ast.NewStringTemplateExpression(
+						p.memoryGauge,
+						values,
+						expressions,
+						ast.NewRange(
+							p.memoryGauge,
+							startToken.StartPos,
+							token.EndPos,
+						),
+					)
+
+				default:
+					panic(fmt.Errorf(
+						"expected end of embedded expression in string template, got %s",
+						token.Type,
+					))
+				}
+			}
+		},
+	)
+}
+
 func defineArrayExpression() {
 	setExprNullDenotation(
 		lexer.TokenBracketOpen,
@@ -1425,6 +1531,26 @@ func parseStringLiteral(literal string) (result string, errs []error) {
 	return
 }
 
+// parseStringTemplateTail parses the tail of a string template,
+// i.e. the contents following the last embedded expression, including the closing parenthesis and the end quote
+//
+func parseStringTemplateTail(tail string) (result string, errs []error) {
+	endOffset := len(tail)
+	missingEnd := true
+	if endOffset >= 2 && tail[endOffset-1] == '"' {
+		endOffset--
+		missingEnd = false
+	}
+
+	result, errs = parseStringLiteralContent(tail[1:endOffset])
+
+	if missingEnd {
+		errs = append(errs, fmt.Errorf("invalid end of string literal: missing '\"'"))
+	}
+
+	return
+}
+
 // parseStringLiteralContent parses the string literalExpr contents, excluding start and end quotes
 //
 func parseStringLiteralContent(s string) (result string, errs []error) {
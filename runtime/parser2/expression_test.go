@@ -2406,6 +2406,43 @@ func TestParseCreate(t *testing.T) {
 			result,
 		)
 	})
+
+	t.Run("type arguments", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseExpression("create T<@R>()", nil)
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			&ast.CreateExpression{
+				InvocationExpression: &ast.InvocationExpression{
+					InvokedExpression: &ast.IdentifierExpression{
+						Identifier: ast.Identifier{
+							Identifier: "T",
+							Pos:        ast.Position{Line: 1, Column: 7, Offset: 7},
+						},
+					},
+					TypeArguments: []*ast.TypeAnnotation{
+						{
+							IsResource: true,
+							Type: &ast.NominalType{
+								Identifier: ast.Identifier{
+									Identifier: "R",
+									Pos:        ast.Position{Line: 1, Column: 10, Offset: 10},
+								},
+							},
+							StartPos: ast.Position{Line: 1, Column: 9, Offset: 9},
+						},
+					},
+					ArgumentsStartPos: ast.Position{Line: 1, Column: 12, Offset: 12},
+					EndPos:            ast.Position{Line: 1, Column: 13, Offset: 13},
+				},
+				StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+			},
+			result,
+		)
+	})
 }
 
 func TestParseNil(t *testing.T) {
@@ -2564,6 +2601,65 @@ func TestParseFunctionExpression(t *testing.T) {
 			result,
 		)
 	})
+
+	t.Run("view", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseExpression("view fun () { }", nil)
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			&ast.FunctionExpression{
+				Purity: ast.FunctionPurityView,
+				ParameterList: &ast.ParameterList{
+					Parameters: nil,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 9, Offset: 9},
+						EndPos:   ast.Position{Line: 1, Column: 10, Offset: 10},
+					},
+				},
+				ReturnTypeAnnotation: &ast.TypeAnnotation{
+					IsResource: false,
+					Type: &ast.NominalType{
+						Identifier: ast.Identifier{
+							Identifier: "",
+							Pos:        ast.Position{Line: 1, Column: 10, Offset: 10},
+						},
+					},
+					StartPos: ast.Position{Line: 1, Column: 10, Offset: 10},
+				},
+				FunctionBlock: &ast.FunctionBlock{
+					Block: &ast.Block{
+						Range: ast.Range{
+							StartPos: ast.Position{Line: 1, Column: 12, Offset: 12},
+							EndPos:   ast.Position{Line: 1, Column: 14, Offset: 14},
+						},
+					},
+				},
+				StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+			},
+			result,
+		)
+	})
+
+	t.Run("view as identifier", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseExpression("view", nil)
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			&ast.IdentifierExpression{
+				Identifier: ast.Identifier{
+					Identifier: "view",
+					Pos:        ast.Position{Line: 1, Column: 0, Offset: 0},
+				},
+			},
+			result,
+		)
+	})
 }
 
 func TestParseIntegerLiterals(t *testing.T) {
@@ -5202,6 +5298,115 @@ func TestParseStringWithUnicode(t *testing.T) {
 	utils.AssertEqualWithDiff(t, expected, actual)
 }
 
+func TestParseStringTemplate(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("simple", func(t *testing.T) {
+
+		t.Parallel()
+
+		actual, errs := ParseExpression(`"a\(b)c"`, nil)
+		require.Empty(t, errs)
+
+		expected := &ast.StringTemplateExpression{
+			Values: []string{"a", "c"},
+			Expressions: []ast.Expression{
+				&ast.IdentifierExpression{
+					Identifier: ast.Identifier{
+						Identifier: "b",
+						Pos:        ast.Position{Offset: 4, Line: 1, Column: 4},
+					},
+				},
+			},
+			Range: ast.Range{
+				StartPos: ast.Position{Offset: 0, Line: 1, Column: 0},
+				EndPos:   ast.Position{Offset: 7, Line: 1, Column: 7},
+			},
+		}
+
+		utils.AssertEqualWithDiff(t, expected, actual)
+	})
+
+	t.Run("multiple, with escapes and nested parentheses", func(t *testing.T) {
+
+		t.Parallel()
+
+		actual, errs := ParseExpression(`"\n\((1))\(x)\""`, nil)
+		require.Empty(t, errs)
+
+		expected := &ast.StringTemplateExpression{
+			Values: []string{"\n", "", "\""},
+			Expressions: []ast.Expression{
+				&ast.IntegerExpression{
+					PositiveLiteral: "1",
+					Value:           big.NewInt(1),
+					Base:            10,
+					Range: ast.Range{
+						StartPos: ast.Position{Offset: 6, Line: 1, Column: 6},
+						EndPos:   ast.Position{Offset: 6, Line: 1, Column: 6},
+					},
+				},
+				&ast.IdentifierExpression{
+					Identifier: ast.Identifier{
+						Identifier: "x",
+						Pos:        ast.Position{Offset: 11, Line: 1, Column: 11},
+					},
+				},
+			},
+			Range: ast.Range{
+				StartPos: ast.Position{Offset: 0, Line: 1, Column: 0},
+				EndPos:   ast.Position{Offset: 15, Line: 1, Column: 15},
+			},
+		}
+
+		utils.AssertEqualWithDiff(t, expected, actual)
+	})
+
+	t.Run("nested template", func(t *testing.T) {
+
+		t.Parallel()
+
+		actual, errs := ParseExpression(`"\("\(a)")"`, nil)
+		require.Empty(t, errs)
+
+		expected := &ast.StringTemplateExpression{
+			Values: []string{"", ""},
+			Expressions: []ast.Expression{
+				&ast.StringTemplateExpression{
+					Values: []string{"", ""},
+					Expressions: []ast.Expression{
+						&ast.IdentifierExpression{
+							Identifier: ast.Identifier{
+								Identifier: "a",
+								Pos:        ast.Position{Offset: 6, Line: 1, Column: 6},
+							},
+						},
+					},
+					Range: ast.Range{
+						StartPos: ast.Position{Offset: 3, Line: 1, Column: 3},
+						EndPos:   ast.Position{Offset: 8, Line: 1, Column: 8},
+					},
+				},
+			},
+			Range: ast.Range{
+				StartPos: ast.Position{Offset: 0, Line: 1, Column: 0},
+				EndPos:   ast.Position{Offset: 10, Line: 1, Column: 10},
+			},
+		}
+
+		utils.AssertEqualWithDiff(t, expected, actual)
+	})
+
+	t.Run("unterminated", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, errs := ParseExpression(`"a\(b`, nil)
+		require.NotEmpty(t, errs)
+	})
+}
+
 func TestParseNilCoalescing(t *testing.T) {
 
 	t.Parallel()
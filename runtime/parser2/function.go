@@ -109,6 +109,120 @@ func parseParameterList(p *parser) (parameterList *ast.ParameterList) {
 	)
 }
 
+// parseTypeParameterList parses an optional type parameter list,
+// e.g. `<T, U: AnyResource>`.
+// Returns nil if the current token does not start a type parameter list
+//
+func parseTypeParameterList(p *parser) *ast.TypeParameterList {
+	p.skipSpaceAndComments(true)
+
+	if !p.current.Is(lexer.TokenLess) {
+		return nil
+	}
+
+	var typeParameters []*ast.TypeParameter
+
+	startPos := p.current.StartPos
+	// Skip the opening less-than
+	p.next()
+
+	var endPos ast.Position
+
+	expectTypeParameter := true
+
+	atEnd := false
+	for !atEnd {
+		p.skipSpaceAndComments(true)
+		switch p.current.Type {
+		case lexer.TokenIdentifier:
+			if !expectTypeParameter {
+				panic(fmt.Errorf(
+					"expected comma or end of type parameter list, got %s",
+					p.current.Type,
+				))
+			}
+			typeParameter := parseTypeParameter(p)
+			typeParameters = append(typeParameters, typeParameter)
+			expectTypeParameter = false
+
+		case lexer.TokenComma:
+			if expectTypeParameter {
+				panic(fmt.Errorf(
+					"expected type parameter or end of type parameter list, got %s",
+					p.current.Type,
+				))
+			}
+			// Skip the comma
+			p.next()
+			expectTypeParameter = true
+
+		case lexer.TokenGreater:
+			endPos = p.current.EndPos
+			// Skip the closing greater-than
+			p.next()
+			atEnd = true
+
+		case lexer.TokenEOF:
+			panic(fmt.Errorf(
+				"missing %s at end of type parameter list",
+				lexer.TokenGreater,
+			))
+
+		default:
+			if expectTypeParameter {
+				panic(fmt.Errorf(
+					"expected type parameter or end of type parameter list, got %s",
+					p.current.Type,
+				))
+			} else {
+				panic(fmt.Errorf(
+					"expected comma or end of type parameter list, got %s",
+					p.current.Type,
+				))
+			}
+		}
+	}
+
+	if len(typeParameters) == 0 {
+		panic(fmt.Errorf("expected at least one type parameter"))
+	}
+
+	return ast.NewTypeParameterList(
+		p.memoryGauge,
+		typeParameters,
+		ast.NewRange(
+			p.memoryGauge,
+			startPos,
+			endPos,
+		),
+	)
+}
+
+func parseTypeParameter(p *parser) *ast.TypeParameter {
+	identifier := p.tokenToIdentifier(p.current)
+	// Skip the identifier
+	p.next()
+
+	// If a colon is provided, the type parameter has a type bound
+
+	var typeBound ast.Type
+
+	p.skipSpaceAndComments(true)
+	if p.current.Is(lexer.TokenColon) {
+		// Skip the colon
+		p.next()
+		p.skipSpaceAndComments(true)
+
+		typeBound = parseType(p, lowestBindingPower)
+	}
+
+	return ast.NewTypeParameter(
+		p.memoryGauge,
+		identifier,
+		typeBound,
+	)
+}
+
 func parseParameter(p *parser) *ast.Parameter {
 	p.skipSpaceAndComments(true)
 
@@ -189,12 +303,16 @@ func parseFunctionDeclaration(
 	functionBlockIsOptional bool,
 	access ast.Access,
 	accessPos *ast.Position,
+	purity ast.FunctionPurity,
+	purityPos *ast.Position,
 	docString string,
 ) *ast.FunctionDeclaration {
 
 	startPos := p.current.StartPos
 	if accessPos != nil {
 		startPos = *accessPos
+	} else if purityPos != nil {
+		startPos = *purityPos
 	}
 
 	// Skip the `fun` keyword
@@ -213,13 +331,17 @@ func parseFunctionDeclaration(
 	// Skip the identifier
 	p.next()
 
+	typeParameterList := parseTypeParameterList(p)
+
 	parameterList, returnTypeAnnotation, functionBlock :=
 		parseFunctionParameterListAndRest(p, functionBlockIsOptional)
 
 	return ast.NewFunctionDeclaration(
 		p.memoryGauge,
 		access,
+		purity,
 		identifier,
+		typeParameterList,
 		parameterList,
 		returnTypeAnnotation,
 		functionBlock,
@@ -272,3 +394,48 @@ func parseFunctionParameterListAndRest(
 	}
 	return
 }
+
+// parsePurityAnnotation parses a purity modifier, if the current token is the `view` keyword,
+// and it is followed by one of the given keywords.
+// Otherwise, the `view` keyword is an identifier, and no tokens are consumed.
+//
+//	purity : 'view'?
+func parsePurityAnnotation(p *parser, keywords ...string) (ast.FunctionPurity, *ast.Position) {
+	if !p.current.IsString(lexer.TokenIdentifier, keywordView) {
+		return ast.FunctionPurityUnspecified, nil
+	}
+
+	isPurityAnnotation := false
+	for _, keyword := range keywords {
+		if isNextTokenKeyword(p, keyword) {
+			isPurityAnnotation = true
+			break
+		}
+	}
+
+	if !isPurityAnnotation {
+		return ast.FunctionPurityUnspecified, nil
+	}
+
+	purityPos := p.current.StartPos
+
+	// Skip the `view` keyword
+	p.next()
+	p.skipSpaceAndComments(true)
+
+	return ast.FunctionPurityView, &purityPos
+}
+
+// isNextTokenKeyword checks whether the token to follow is the given keyword.
+// No tokens are consumed
+//
+func isNextTokenKeyword(p *parser, keyword string) bool {
+	p.startBuffering()
+	defer p.replayBuffered()
+
+	// Skip the current token
+	p.next()
+	p.skipSpaceAndComments(true)
+
+	return p.current.IsString(lexer.TokenIdentifier, keyword)
+}
@@ -31,6 +31,7 @@ const (
 	keywordLet         = "let"
 	keywordVar         = "var"
 	keywordFun         = "fun"
+	keywordView        = "view"
 	keywordAs          = "as"
 	keywordCreate      = "create"
 	keywordDestroy     = "destroy"
@@ -62,4 +63,5 @@ const (
 	keywordSwitch      = "switch"
 	keywordDefault     = "default"
 	keywordEnum        = "enum"
+	keywordTypeAlias   = "typealias"
 )
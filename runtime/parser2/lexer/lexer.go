@@ -70,6 +70,11 @@ type lexer struct {
 	tokenCount int
 	// memoryGauge is used for metering memory usage
 	memoryGauge common.MemoryGauge
+	// stringTemplateParenDepths contains, for each embedded expression of a string template
+	// that is currently being scanned, the number of open parentheses in the expression.
+	// When a closing parenthesis is scanned while no parentheses are open,
+	// the embedded expression ends and scanning of the string template continues
+	stringTemplateParenDepths []int
 }
 
 var _ TokenStream = &lexer{}
@@ -126,6 +131,7 @@ func (l *lexer) clear() {
 	l.cursor = 0
 	l.tokens = l.tokens[:0]
 	l.tokenCount = 0
+	l.stringTemplateParenDepths = l.stringTemplateParenDepths[:0]
 }
 
 func (l *lexer) Reclaim() {
@@ -398,25 +404,66 @@ func (l *lexer) acceptWhile(f func(rune) bool) {
 	}
 }
 
-func (l *lexer) scanString(quote rune) {
+// scanString scans the remainder of a string literal, up to and including the closing quote.
+// It returns true if the scanning stopped at the start of an embedded expression (`\(`),
+// i.e. if the string literal is a string template.
+//
+func (l *lexer) scanString(quote rune) (isTemplate bool) {
 	r := l.next()
 	for r != quote {
 		switch r {
 		case '\n', EOF:
 			// NOTE: invalid end of string handled by parser
 			l.backupOne()
-			return
+			return false
 		case '\\':
 			r = l.next()
 			switch r {
 			case '\n', EOF:
 				// NOTE: invalid end of string handled by parser
 				l.backupOne()
-				return
+				return false
+			case '(':
+				return true
 			}
 		}
 		r = l.next()
 	}
+	return false
+}
+
+// startStringTemplateExpression records the start of an embedded expression of a string template
+//
+func (l *lexer) startStringTemplateExpression() {
+	l.stringTemplateParenDepths = append(l.stringTemplateParenDepths, 0)
+}
+
+// openParen records an opening parenthesis
+//
+func (l *lexer) openParen() {
+	lastIndex := len(l.stringTemplateParenDepths) - 1
+	if lastIndex < 0 {
+		return
+	}
+	l.stringTemplateParenDepths[lastIndex]++
+}
+
+// closeParen records a closing parenthesis.
+// It returns true if the parenthesis ends an embedded expression of a string template.
+//
+func (l *lexer) closeParen() (endsStringTemplateExpression bool) {
+	lastIndex := len(l.stringTemplateParenDepths) - 1
+	if lastIndex < 0 {
+		return false
+	}
+
+	if l.stringTemplateParenDepths[lastIndex] == 0 {
+		l.stringTemplateParenDepths = l.stringTemplateParenDepths[:lastIndex]
+		return true
+	}
+
+	l.stringTemplateParenDepths[lastIndex]--
+	return false
 }
 
 func (l *lexer) scanBinaryRemainder() {
@@ -470,7 +517,12 @@ func (l *lexer) scanFixedPointRemainder() {
 func (l *lexer) tokenValueMemoryUsage(tokenType TokenType) common.MemoryUsage {
 	tokenLength := l.wordLength()
 
-	if tokenType == TokenString {
+	switch tokenType {
+	case TokenString,
+		TokenStringTemplateHead,
+		TokenStringTemplateMiddle,
+		TokenStringTemplateTail:
+
 		return common.NewStringMemoryUsage(tokenLength)
 	}
 
@@ -901,6 +901,216 @@ func TestLexString(t *testing.T) {
 			},
 		)
 	})
+
+	t.Run("template, one expression", func(t *testing.T) {
+		testLex(t,
+			`"a\(b)c"`,
+			[]Token{
+				{
+					Type:  TokenStringTemplateHead,
+					Value: `"a\(`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+						EndPos:   ast.Position{Line: 1, Column: 3, Offset: 3},
+					},
+				},
+				{
+					Type:  TokenIdentifier,
+					Value: `b`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 4, Offset: 4},
+						EndPos:   ast.Position{Line: 1, Column: 4, Offset: 4},
+					},
+				},
+				{
+					Type:  TokenStringTemplateTail,
+					Value: `)c"`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 5, Offset: 5},
+						EndPos:   ast.Position{Line: 1, Column: 7, Offset: 7},
+					},
+				},
+				{
+					Type: TokenEOF,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 8, Offset: 8},
+						EndPos:   ast.Position{Line: 1, Column: 8, Offset: 8},
+					},
+				},
+			},
+		)
+	})
+
+	t.Run("template, parentheses in expression", func(t *testing.T) {
+		testLex(t,
+			`"\(f(x))"`,
+			[]Token{
+				{
+					Type:  TokenStringTemplateHead,
+					Value: `"\(`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+						EndPos:   ast.Position{Line: 1, Column: 2, Offset: 2},
+					},
+				},
+				{
+					Type:  TokenIdentifier,
+					Value: `f`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 3, Offset: 3},
+						EndPos:   ast.Position{Line: 1, Column: 3, Offset: 3},
+					},
+				},
+				{
+					Type: TokenParenOpen,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 4, Offset: 4},
+						EndPos:   ast.Position{Line: 1, Column: 4, Offset: 4},
+					},
+				},
+				{
+					Type:  TokenIdentifier,
+					Value: `x`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 5, Offset: 5},
+						EndPos:   ast.Position{Line: 1, Column: 5, Offset: 5},
+					},
+				},
+				{
+					Type: TokenParenClose,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 6, Offset: 6},
+						EndPos:   ast.Position{Line: 1, Column: 6, Offset: 6},
+					},
+				},
+				{
+					Type:  TokenStringTemplateTail,
+					Value: `)"`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 7, Offset: 7},
+						EndPos:   ast.Position{Line: 1, Column: 8, Offset: 8},
+					},
+				},
+				{
+					Type: TokenEOF,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 9, Offset: 9},
+						EndPos:   ast.Position{Line: 1, Column: 9, Offset: 9},
+					},
+				},
+			},
+		)
+	})
+
+	t.Run("template, two expressions", func(t *testing.T) {
+		testLex(t,
+			`"\(a)-\(b)"`,
+			[]Token{
+				{
+					Type:  TokenStringTemplateHead,
+					Value: `"\(`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+						EndPos:   ast.Position{Line: 1, Column: 2, Offset: 2},
+					},
+				},
+				{
+					Type:  TokenIdentifier,
+					Value: `a`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 3, Offset: 3},
+						EndPos:   ast.Position{Line: 1, Column: 3, Offset: 3},
+					},
+				},
+				{
+					Type:  TokenStringTemplateMiddle,
+					Value: `)-\(`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 4, Offset: 4},
+						EndPos:   ast.Position{Line: 1, Column: 7, Offset: 7},
+					},
+				},
+				{
+					Type:  TokenIdentifier,
+					Value: `b`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 8, Offset: 8},
+						EndPos:   ast.Position{Line: 1, Column: 8, Offset: 8},
+					},
+				},
+				{
+					Type:  TokenStringTemplateTail,
+					Value: `)"`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 9, Offset: 9},
+						EndPos:   ast.Position{Line: 1, Column: 10, Offset: 10},
+					},
+				},
+				{
+					Type: TokenEOF,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 11, Offset: 11},
+						EndPos:   ast.Position{Line: 1, Column: 11, Offset: 11},
+					},
+				},
+			},
+		)
+	})
+
+	t.Run("template, nested template", func(t *testing.T) {
+		testLex(t,
+			`"\("\(a)")"`,
+			[]Token{
+				{
+					Type:  TokenStringTemplateHead,
+					Value: `"\(`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+						EndPos:   ast.Position{Line: 1, Column: 2, Offset: 2},
+					},
+				},
+				{
+					Type:  TokenStringTemplateHead,
+					Value: `"\(`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 3, Offset: 3},
+						EndPos:   ast.Position{Line: 1, Column: 5, Offset: 5},
+					},
+				},
+				{
+					Type:  TokenIdentifier,
+					Value: `a`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 6, Offset: 6},
+						EndPos:   ast.Position{Line: 1, Column: 6, Offset: 6},
+					},
+				},
+				{
+					Type:  TokenStringTemplateTail,
+					Value: `)"`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 7, Offset: 7},
+						EndPos:   ast.Position{Line: 1, Column: 8, Offset: 8},
+					},
+				},
+				{
+					Type:  TokenStringTemplateTail,
+					Value: `)"`,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 9, Offset: 9},
+						EndPos:   ast.Position{Line: 1, Column: 10, Offset: 10},
+					},
+				},
+				{
+					Type: TokenEOF,
+					Range: ast.Range{
+						StartPos: ast.Position{Line: 1, Column: 11, Offset: 11},
+						EndPos:   ast.Position{Line: 1, Column: 11, Offset: 11},
+					},
+				},
+			},
+		)
+	})
 }
 
 func TestLexBlockComment(t *testing.T) {
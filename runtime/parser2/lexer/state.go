@@ -49,8 +49,12 @@ func rootState(l *lexer) stateFn {
 		case '%':
 			l.emitType(TokenPercent)
 		case '(':
+			l.openParen()
 			l.emitType(TokenParenOpen)
 		case ')':
+			if l.closeParen() {
+				return stringTemplateContinuationState
+			}
 			l.emitType(TokenParenClose)
 		case '{':
 			l.emitType(TokenBraceOpen)
@@ -301,8 +305,25 @@ func identifierState(l *lexer) stateFn {
 }
 
 func stringState(l *lexer) stateFn {
-	l.scanString('"')
-	l.emitValue(TokenString)
+	if l.scanString('"') {
+		l.emitValue(TokenStringTemplateHead)
+		l.startStringTemplateExpression()
+	} else {
+		l.emitValue(TokenString)
+	}
+	return rootState
+}
+
+// stringTemplateContinuationState scans the remainder of a string template
+// after an embedded expression, starting with the closing parenthesis of the expression
+//
+func stringTemplateContinuationState(l *lexer) stateFn {
+	if l.scanString('"') {
+		l.emitValue(TokenStringTemplateMiddle)
+		l.startStringTemplateExpression()
+	} else {
+		l.emitValue(TokenStringTemplateTail)
+	}
 	return rootState
 }
 
@@ -38,6 +38,9 @@ const (
 	TokenFixedPointNumberLiteral
 	TokenIdentifier
 	TokenString
+	TokenStringTemplateHead
+	TokenStringTemplateMiddle
+	TokenStringTemplateTail
 	TokenPlus
 	TokenMinus
 	TokenStar
@@ -116,6 +119,12 @@ func (t TokenType) String() string {
 		return "identifier"
 	case TokenString:
 		return "string"
+	case TokenStringTemplateHead:
+		return "start of string template"
+	case TokenStringTemplateMiddle:
+		return "middle of string template"
+	case TokenStringTemplateTail:
+		return "end of string template"
 	case TokenPlus:
 		return `'+'`
 	case TokenMinus:
@@ -98,7 +98,24 @@ func parseStatement(p *parser) ast.Statement {
 		case keywordFun:
 			// The `fun` keyword is ambiguous: it either introduces a function expression
 			// or a function declaration, depending on if an identifier follows, or not.
-			return parseFunctionDeclarationOrFunctionExpressionStatement(p)
+			return parseFunctionDeclarationOrFunctionExpressionStatement(
+				p,
+				ast.FunctionPurityUnspecified,
+				nil,
+			)
+
+		case keywordView:
+			// The `view` keyword is only a purity modifier
+			// if it is followed by the `fun` keyword.
+			// Otherwise, it is an identifier
+			purity, purityPos := parsePurityAnnotation(p, keywordFun)
+			if purityPos != nil {
+				return parseFunctionDeclarationOrFunctionExpressionStatement(
+					p,
+					purity,
+					purityPos,
+				)
+			}
 		}
 	}
 
@@ -139,9 +156,16 @@ func parseStatement(p *parser) ast.Statement {
 	}
 }
 
-func parseFunctionDeclarationOrFunctionExpressionStatement(p *parser) ast.Statement {
+func parseFunctionDeclarationOrFunctionExpressionStatement(
+	p *parser,
+	purity ast.FunctionPurity,
+	purityPos *ast.Position,
+) ast.Statement {
 
 	startPos := p.current.StartPos
+	if purityPos != nil {
+		startPos = *purityPos
+	}
 
 	// Skip the `fun` keyword
 	p.next()
@@ -153,13 +177,17 @@ func parseFunctionDeclarationOrFunctionExpressionStatement(p *parser) ast.Statem
 
 		p.next()
 
+		typeParameterList := parseTypeParameterList(p)
+
 		parameterList, returnTypeAnnotation, functionBlock :=
 			parseFunctionParameterListAndRest(p, false)
 
 		return ast.NewFunctionDeclaration(
 			p.memoryGauge,
 			ast.AccessNotSpecified,
+			purity,
 			identifier,
+			typeParameterList,
 			parameterList,
 			returnTypeAnnotation,
 			functionBlock,
@@ -174,6 +202,7 @@ func parseFunctionDeclarationOrFunctionExpressionStatement(p *parser) ast.Statem
 			p.memoryGauge,
 			ast.NewFunctionExpression(
 				p.memoryGauge,
+				purity,
 				parameterList,
 				returnTypeAnnotation,
 				functionBlock,
@@ -77,7 +77,15 @@ func parseTransactionDeclaration(p *parser, docString string) *ast.TransactionDe
 			identifier := p.tokenToIdentifier(p.current)
 			// Skip the `prepare` keyword
 			p.next()
-			prepare = parseSpecialFunctionDeclaration(p, false, ast.AccessNotSpecified, nil, identifier)
+			prepare = parseSpecialFunctionDeclaration(
+				p,
+				false,
+				ast.AccessNotSpecified,
+				nil,
+				ast.FunctionPurityUnspecified,
+				nil,
+				identifier,
+			)
 
 		case keywordExecute:
 			execute = parseTransactionExecute(p)
@@ -223,9 +231,11 @@ func parseTransactionExecute(p *parser) *ast.SpecialFunctionDeclaration {
 		ast.NewFunctionDeclaration(
 			p.memoryGauge,
 			ast.AccessNotSpecified,
+			ast.FunctionPurityUnspecified,
 			identifier,
 			nil,
 			nil,
+			nil,
 			ast.NewFunctionBlock(
 				p.memoryGauge,
 				block,
@@ -602,6 +602,15 @@ func defineFunctionType() {
 		lexer.TokenParenOpen,
 		func(p *parser, startToken lexer.Token) ast.Type {
 
+			purity := ast.FunctionPurityUnspecified
+
+			p.skipSpaceAndComments(true)
+			if p.current.IsString(lexer.TokenIdentifier, keywordView) {
+				// Skip the `view` keyword
+				p.next()
+				purity = ast.FunctionPurityView
+			}
+
 			parameterTypeAnnotations := parseParameterTypeAnnotations(p)
 
 			p.skipSpaceAndComments(true)
@@ -615,6 +624,7 @@ func defineFunctionType() {
 
 			return ast.NewFunctionType(
 				p.memoryGauge,
+				purity,
 				parameterTypeAnnotations,
 				returnTypeAnnotation,
 				ast.NewRange(
@@ -796,7 +806,19 @@ func parseNominalTypeInvocationRemainder(p *parser) *ast.InvocationExpression {
 	identifier := p.mustOne(lexer.TokenIdentifier)
 	ty := parseNominalTypeRemainder(p, identifier)
 
+	// Generic composite types may be instantiated with explicit type arguments,
+	// e.g. `create Holder<R>()`
+
+	var typeArguments []*ast.TypeAnnotation
+
 	p.skipSpaceAndComments(true)
+	if p.current.Is(lexer.TokenLess) {
+		p.next()
+		typeArguments = parseCommaSeparatedTypeAnnotations(p, lexer.TokenGreater)
+		p.mustOne(lexer.TokenGreater)
+		p.skipSpaceAndComments(true)
+	}
+
 	parenOpenToken := p.mustOne(lexer.TokenParenOpen)
 	argumentsStartPos := parenOpenToken.EndPos
 	arguments, endPos := parseArgumentListRemainder(p)
@@ -819,7 +841,7 @@ func parseNominalTypeInvocationRemainder(p *parser) *ast.InvocationExpression {
 	return ast.NewInvocationExpression(
 		p.memoryGauge,
 		invokedExpression,
-		nil,
+		typeArguments,
 		arguments,
 		argumentsStartPos,
 		endPos,
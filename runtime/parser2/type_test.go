@@ -1095,6 +1095,47 @@ func TestParseFunctionType(t *testing.T) {
 			result,
 		)
 	})
+
+	t.Run("view, one parameter, Int return type", func(t *testing.T) {
+
+		t.Parallel()
+
+		result, errs := ParseType("(view (Int): Int)", nil)
+		require.Empty(t, errs)
+
+		utils.AssertEqualWithDiff(t,
+			&ast.FunctionType{
+				Purity: ast.FunctionPurityView,
+				ParameterTypeAnnotations: []*ast.TypeAnnotation{
+					{
+						IsResource: false,
+						Type: &ast.NominalType{
+							Identifier: ast.Identifier{
+								Identifier: "Int",
+								Pos:        ast.Position{Line: 1, Column: 7, Offset: 7},
+							},
+						},
+						StartPos: ast.Position{Line: 1, Column: 7, Offset: 7},
+					},
+				},
+				ReturnTypeAnnotation: &ast.TypeAnnotation{
+					IsResource: false,
+					Type: &ast.NominalType{
+						Identifier: ast.Identifier{
+							Identifier: "Int",
+							Pos:        ast.Position{Line: 1, Column: 13, Offset: 13},
+						},
+					},
+					StartPos: ast.Position{Line: 1, Column: 13, Offset: 13},
+				},
+				Range: ast.Range{
+					StartPos: ast.Position{Line: 1, Column: 0, Offset: 0},
+					EndPos:   ast.Position{Line: 1, Column: 16, Offset: 16},
+				},
+			},
+			result,
+		)
+	})
 }
 
 func TestParseInstantiationType(t *testing.T) {
@@ -110,6 +110,14 @@ type Runtime interface {
 	// SetDebugger configures interpreters with the given debugger.
 	//
 	SetDebugger(debugger *interpreter.Debugger)
+
+	// Storage returns the storage of the given context,
+	// and an interpreter which can be used to read and write stored values,
+	// e.g. to migrate them.
+	//
+	// Changes are only written when the storage is committed.
+	//
+	Storage(context Context) (*Storage, *interpreter.Interpreter, error)
 }
 
 var typeDeclarations = append(
@@ -123,6 +131,7 @@ var validTopLevelDeclarationsInTransaction = []common.DeclarationKind{
 	common.DeclarationKindImport,
 	common.DeclarationKindFunction,
 	common.DeclarationKindTransaction,
+	common.DeclarationKindTypeAlias,
 }
 
 var validTopLevelDeclarationsInAccountCode = []common.DeclarationKind{
@@ -130,6 +139,7 @@ var validTopLevelDeclarationsInAccountCode = []common.DeclarationKind{
 	common.DeclarationKindImport,
 	common.DeclarationKindContract,
 	common.DeclarationKindContractInterface,
+	common.DeclarationKindTypeAlias,
 }
 
 func validTopLevelDeclarations(location common.Location) []common.DeclarationKind {
@@ -1107,6 +1117,8 @@ func (r *interpreterRuntime) parseAndCheckProgram(
 		context.SetProgram(context.Location, parse)
 	}
 
+	r.inspectProgramCoverage(context.Location, parse)
+
 	// Check
 
 	elaboration, err := r.check(parse, context, functions, values, checkerOptions, checkedImports)
@@ -1316,6 +1328,12 @@ func (r *interpreterRuntime) newInterpreter(
 		interpreter.WithOnStatementHandler(
 			r.onStatementHandler(),
 		),
+		interpreter.WithOnFunctionEntryHandler(
+			r.onFunctionEntryHandler(),
+		),
+		interpreter.WithOnBranchHandler(
+			r.onBranchHandler(),
+		),
 		interpreter.WithPublicAccountHandler(
 			func(inter *interpreter.Interpreter, address interpreter.AddressValue) interpreter.Value {
 				return r.getPublicAccount(
@@ -1526,6 +1544,11 @@ func (r *interpreterRuntime) getProgram(
 
 	context.SetProgram(context.Location, program.Program)
 
+	// The program might have been loaded from the cache,
+	// in which case it was not inspected for coverage yet
+
+	r.inspectProgramCoverage(context.Location, program.Program)
+
 	return program, nil
 }
 
@@ -1623,6 +1646,7 @@ func (r *interpreterRuntime) meteringInterpreterOptions(runtimeInterface Interfa
 }
 
 var getAuthAccountFunctionType = &sema.FunctionType{
+	Purity: sema.FunctionPurityView,
 	Parameters: []*sema.Parameter{{
 		Label:          sema.ArgumentLabelNotRequired,
 		Identifier:     "address",
@@ -2640,6 +2664,11 @@ func (r *interpreterRuntime) newAuthAccountContractsChangeFunction(
 			var contractInterfaceTypes []*sema.InterfaceType
 
 			program.Elaboration.GlobalTypes.Foreach(func(_ string, variable *sema.Variable) {
+				// Type aliases do not declare types
+				if variable.DeclarationKind == common.DeclarationKindTypeAlias {
+					return
+				}
+
 				switch ty := variable.Type.(type) {
 				case *sema.CompositeType:
 					if ty.Kind == common.CompositeKindContract {
@@ -3102,6 +3131,34 @@ func (r *interpreterRuntime) onStatementHandler() interpreter.OnStatementFunc {
 	}
 }
 
+func (r *interpreterRuntime) onFunctionEntryHandler() interpreter.OnFunctionEntryFunc {
+	if r.coverageReport == nil {
+		return nil
+	}
+
+	return func(inter *interpreter.Interpreter, functionBlock *ast.FunctionBlock) {
+		r.coverageReport.AddFunctionHit(inter.Location, functionBlock.StartPosition())
+	}
+}
+
+func (r *interpreterRuntime) onBranchHandler() interpreter.OnBranchFunc {
+	if r.coverageReport == nil {
+		return nil
+	}
+
+	return func(inter *interpreter.Interpreter, element ast.Element, branch int) {
+		r.coverageReport.AddBranchHit(inter.Location, element, branch)
+	}
+}
+
+func (r *interpreterRuntime) inspectProgramCoverage(location common.Location, program *ast.Program) {
+	if r.coverageReport == nil || program == nil {
+		return
+	}
+
+	r.coverageReport.InspectProgram(location, program)
+}
+
 func (r *interpreterRuntime) executeNonProgram(interpret interpretFunc, context Context) (cadence.Value, error) {
 	context.InitializeCodesAndPrograms()
 
@@ -3137,6 +3194,34 @@ func (r *interpreterRuntime) executeNonProgram(interpret interpretFunc, context
 	return exportValue(value)
 }
 
+func (r *interpreterRuntime) Storage(context Context) (*Storage, *interpreter.Interpreter, error) {
+	context.InitializeCodesAndPrograms()
+
+	memoryGauge, _ := context.Interface.(common.MemoryGauge)
+
+	storage := NewStorage(context.Interface, memoryGauge)
+
+	var functions stdlib.StandardLibraryFunctions
+	var values stdlib.StandardLibraryValues
+	var interpreterOptions []interpreter.Option
+	var checkerOptions []sema.Option
+
+	inter, err := r.newInterpreter(
+		nil,
+		context,
+		functions,
+		values,
+		storage,
+		interpreterOptions,
+		checkerOptions,
+	)
+	if err != nil {
+		return nil, nil, newError(err, context)
+	}
+
+	return storage, inter, nil
+}
+
 func (r *interpreterRuntime) ReadStored(
 	address common.Address,
 	path cadence.Path,
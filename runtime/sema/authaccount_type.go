@@ -33,6 +33,9 @@ const AuthAccountRemovePublicKeyField = "removePublicKey"
 const AuthAccountSaveField = "save"
 const AuthAccountLoadField = "load"
 const AuthAccountTypeField = "type"
+const AuthAccountStorageSizeField = "storageSize"
+const AuthAccountCheckField = "check"
+const AuthAccountSwapField = "swap"
 const AuthAccountCopyField = "copy"
 const AuthAccountBorrowField = "borrow"
 const AuthAccountLinkField = "link"
@@ -41,6 +44,12 @@ const AuthAccountGetCapabilityField = "getCapability"
 const AuthAccountGetLinkTargetField = "getLinkTarget"
 const AuthAccountContractsField = "contracts"
 const AuthAccountKeysField = "keys"
+const AuthAccountStoragePathsField = "storagePaths"
+const AuthAccountPublicPathsField = "publicPaths"
+const AuthAccountPrivatePathsField = "privatePaths"
+const AuthAccountForEachStoredField = "forEachStored"
+const AuthAccountForEachPublicField = "forEachPublic"
+const AuthAccountForEachPrivateField = "forEachPrivate"
 
 // AuthAccountType represents the authorized access to an account.
 // Access to an AuthAccount means having full access to its storage, public keys, and code.
@@ -117,6 +126,24 @@ var AuthAccountType = func() *CompositeType {
 			AuthAccountTypeTypeFunctionType,
 			authAccountTypeTypeFunctionDocString,
 		),
+		NewUnmeteredPublicFunctionMember(
+			authAccountType,
+			AuthAccountStorageSizeField,
+			AuthAccountTypeStorageSizeFunctionType,
+			authAccountTypeStorageSizeFunctionDocString,
+		),
+		NewUnmeteredPublicFunctionMember(
+			authAccountType,
+			AuthAccountCheckField,
+			AuthAccountTypeCheckFunctionType,
+			authAccountTypeCheckFunctionDocString,
+		),
+		NewUnmeteredPublicFunctionMember(
+			authAccountType,
+			AuthAccountSwapField,
+			AuthAccountTypeSwapFunctionType,
+			authAccountTypeSwapFunctionDocString,
+		),
 		NewUnmeteredPublicFunctionMember(
 			authAccountType,
 			AuthAccountLoadField,
@@ -171,6 +198,42 @@ var AuthAccountType = func() *CompositeType {
 			AuthAccountKeysType,
 			accountTypeKeysFieldDocString,
 		),
+		NewUnmeteredPublicConstantFieldMember(
+			authAccountType,
+			AuthAccountStoragePathsField,
+			AuthAccountStoragePathsType,
+			authAccountTypeStoragePathsFieldDocString,
+		),
+		NewUnmeteredPublicConstantFieldMember(
+			authAccountType,
+			AuthAccountPublicPathsField,
+			AccountPublicPathsType,
+			accountTypePublicPathsFieldDocString,
+		),
+		NewUnmeteredPublicConstantFieldMember(
+			authAccountType,
+			AuthAccountPrivatePathsField,
+			AuthAccountPrivatePathsType,
+			authAccountTypePrivatePathsFieldDocString,
+		),
+		NewUnmeteredPublicFunctionMember(
+			authAccountType,
+			AuthAccountForEachStoredField,
+			AuthAccountForEachStoredFunctionType,
+			authAccountForEachStoredFunctionDocString,
+		),
+		NewUnmeteredPublicFunctionMember(
+			authAccountType,
+			AuthAccountForEachPublicField,
+			AccountForEachPublicFunctionType,
+			accountForEachPublicFunctionDocString,
+		),
+		NewUnmeteredPublicFunctionMember(
+			authAccountType,
+			AuthAccountForEachPrivateField,
+			AuthAccountForEachPrivateFunctionType,
+			authAccountForEachPrivateFunctionDocString,
+		),
 	}
 
 	authAccountType.Members = GetMembersAsMap(members)
@@ -256,6 +319,87 @@ If there is already an object stored under the given path, the program aborts.
 The path must be a storage path, i.e., only the domain ` + "`storage`" + ` is allowed
 `
 
+var AuthAccountTypeCheckFunctionType = func() *FunctionType {
+
+	typeParameter := &TypeParameter{
+		Name:      "T",
+		TypeBound: AnyType,
+	}
+
+	return &FunctionType{
+		Purity: FunctionPurityView,
+		TypeParameters: []*TypeParameter{
+			typeParameter,
+		},
+		Parameters: []*Parameter{
+			{
+				Label:          "from",
+				Identifier:     "path",
+				TypeAnnotation: NewTypeAnnotation(StoragePathType),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(BoolType),
+	}
+}()
+
+const authAccountTypeCheckFunctionDocString = `
+Returns true if an object is stored under the given path and the type of the object is a subtype of the given type, and false otherwise.
+
+The stored object is neither moved nor copied, only its type is read.
+
+The path must be a storage path, i.e., only the domain ` + "`storage`" + ` is allowed
+`
+
+var AuthAccountTypeSwapFunctionType = func() *FunctionType {
+
+	typeParameter := &TypeParameter{
+		Name:      "T",
+		TypeBound: StorableType,
+	}
+
+	return &FunctionType{
+		TypeParameters: []*TypeParameter{
+			typeParameter,
+		},
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "value",
+				TypeAnnotation: NewTypeAnnotation(
+					&GenericType{
+						TypeParameter: typeParameter,
+					},
+				),
+			},
+			{
+				Label:          "at",
+				Identifier:     "path",
+				TypeAnnotation: NewTypeAnnotation(StoragePathType),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(
+			&OptionalType{
+				Type: &GenericType{
+					TypeParameter: typeParameter,
+				},
+			},
+		),
+	}
+}()
+
+const authAccountTypeSwapFunctionDocString = `
+Saves the given object into the account's storage at the given path, replacing the object which is already stored under the path, if any.
+Resources are moved into storage, and structures are copied.
+
+Returns the replaced object as an optional, or nil if no object was stored under the given path.
+The replaced object is moved out of storage.
+
+The given type must be a supertype of the type of the replaced object.
+If it is not, the program aborts, and the storage is not modified.
+
+The path must be a storage path, i.e., only the domain ` + "`storage`" + ` is allowed
+`
+
 var AuthAccountTypeLoadFunctionType = func() *FunctionType {
 
 	typeParameter := &TypeParameter{
@@ -293,6 +437,7 @@ The path must be a storage path, i.e., only the domain ` + "`storage`" + ` is al
 `
 
 var AuthAccountTypeTypeFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
 	Parameters: []*Parameter{
 		{
 			Label:          "at",
@@ -307,6 +452,30 @@ var AuthAccountTypeTypeFunctionType = &FunctionType{
 	),
 }
 
+const authAccountTypeStorageSizeFunctionDocString = `
+Returns the number of bytes used to store the object which is stored under the given path, or nil if no object is stored under the given path.
+
+The size includes the storage used by all nested objects, e.g. the elements of an array, or the fields of a resource.
+
+The path must be a storage path, i.e., only the domain ` + "`storage`" + ` is allowed
+`
+
+var AuthAccountTypeStorageSizeFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
+	Parameters: []*Parameter{
+		{
+			Label:          "at",
+			Identifier:     "path",
+			TypeAnnotation: NewTypeAnnotation(StoragePathType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(
+		&OptionalType{
+			Type: UInt64Type,
+		},
+	),
+}
+
 const authAccountTypeLoadFunctionDocString = `
 Loads an object from the account's storage which is stored under the given path, or nil if no object is stored under the given path.
 
@@ -329,6 +498,7 @@ var AuthAccountTypeCopyFunctionType = func() *FunctionType {
 	}
 
 	return &FunctionType{
+		Purity: FunctionPurityView,
 		TypeParameters: []*TypeParameter{
 			typeParameter,
 		},
@@ -372,6 +542,7 @@ var AuthAccountTypeBorrowFunctionType = func() *FunctionType {
 	}
 
 	return &FunctionType{
+		Purity: FunctionPurityView,
 		TypeParameters: []*TypeParameter{
 			typeParameter,
 		},
@@ -482,6 +653,7 @@ var AuthAccountTypeGetCapabilityFunctionType = func() *FunctionType {
 	}
 
 	return &FunctionType{
+		Purity: FunctionPurityView,
 		TypeParameters: []*TypeParameter{
 			typeParameter,
 		},
@@ -507,6 +679,7 @@ Returns the capability at the given private or public path, or nil if it does no
 `
 
 var AccountTypeGetLinkTargetFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
 	Parameters: []*Parameter{
 		{
 			Label:          ArgumentLabelNotRequired,
@@ -521,6 +694,96 @@ var AccountTypeGetLinkTargetFunctionType = &FunctionType{
 	),
 }
 
+var AuthAccountStoragePathsType = &VariableSizedType{
+	Type: StoragePathType,
+}
+
+var AccountPublicPathsType = &VariableSizedType{
+	Type: PublicPathType,
+}
+
+var AuthAccountPrivatePathsType = &VariableSizedType{
+	Type: PrivatePathType,
+}
+
+const authAccountTypeStoragePathsFieldDocString = `
+All storage paths of the account which store an object
+`
+
+const accountTypePublicPathsFieldDocString = `
+All public paths of the account which store a capability
+`
+
+const authAccountTypePrivatePathsFieldDocString = `
+All private paths of the account which store a capability
+`
+
+// accountIterationFunctionType returns the type of a function
+// which iterates over the paths of the given type
+//
+func accountIterationFunctionType(pathType Type) *FunctionType {
+	return &FunctionType{
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "function",
+				TypeAnnotation: NewTypeAnnotation(
+					&FunctionType{
+						Parameters: []*Parameter{
+							{
+								Identifier:     "path",
+								TypeAnnotation: NewTypeAnnotation(pathType),
+							},
+							{
+								Identifier:     "type",
+								TypeAnnotation: NewTypeAnnotation(MetaType),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(BoolType),
+					},
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(VoidType),
+	}
+}
+
+var AuthAccountForEachStoredFunctionType = accountIterationFunctionType(StoragePathType)
+
+var AccountForEachPublicFunctionType = accountIterationFunctionType(PublicPathType)
+
+var AuthAccountForEachPrivateFunctionType = accountIterationFunctionType(PrivatePathType)
+
+const authAccountForEachStoredFunctionDocString = `
+Iterates over all objects in the account's storage,
+calling the given function with the path and the type of each object.
+
+Iteration stops when the function returns false.
+
+The account's storage must not be modified during iteration:
+If it is modified and the function returns true, the program aborts
+`
+
+const accountForEachPublicFunctionDocString = `
+Iterates over all capabilities stored at public paths of the account,
+calling the given function with the path and the type of each capability.
+
+Iteration stops when the function returns false.
+
+The account's storage must not be modified during iteration:
+If it is modified and the function returns true, the program aborts
+`
+
+const authAccountForEachPrivateFunctionDocString = `
+Iterates over all capabilities stored at private paths of the account,
+calling the given function with the path and the type of each capability.
+
+Iteration stops when the function returns false.
+
+The account's storage must not be modified during iteration:
+If it is modified and the function returns true, the program aborts
+`
+
 // AuthAccountKeysType represents the keys associated with an auth account.
 var AuthAccountKeysType = func() *CompositeType {
 
@@ -576,6 +839,7 @@ var AuthAccountKeysTypeAddFunctionType = &FunctionType{
 }
 
 var AccountKeysTypeGetFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
 	Parameters: []*Parameter{
 		{
 			Identifier:     AccountKeyKeyIndexField,
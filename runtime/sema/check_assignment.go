@@ -169,17 +169,23 @@ func (checker *Checker) visitAssignmentValueType(
 
 	switch target := targetExpression.(type) {
 	case *ast.IdentifierExpression:
-		return checker.visitIdentifierExpressionAssignment(target)
+		targetType = checker.visitIdentifierExpressionAssignment(target)
 
 	case *ast.IndexExpression:
-		return checker.visitIndexExpressionAssignment(target)
+		targetType = checker.visitIndexExpressionAssignment(target)
 
 	case *ast.MemberExpression:
-		return checker.visitMemberExpressionAssignment(target)
+		targetType = checker.visitMemberExpressionAssignment(target)
 
 	default:
 		panic(errors.NewUnreachableError())
 	}
+
+	// In view functions, only local state may be mutated
+
+	checker.checkAssignmentPurity(targetExpression)
+
+	return targetType
 }
 
 func (checker *Checker) visitIdentifierExpressionAssignment(
@@ -169,6 +169,10 @@ func (d *CheckCastVisitor) VisitStringExpression(_ *ast.StringExpression) ast.Re
 	return d.isTypeRedundant(StringType, d.targetType)
 }
 
+func (d *CheckCastVisitor) VisitStringTemplateExpression(_ *ast.StringTemplateExpression) ast.Repr {
+	return d.isTypeRedundant(StringType, d.targetType)
+}
+
 func (d *CheckCastVisitor) VisitCastingExpression(_ *ast.CastingExpression) ast.Repr {
 	// This is already covered under Case-I: where expected type is same as casted type.
 	// So skip checking it here to avid duplicate errors.
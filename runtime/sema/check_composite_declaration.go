@@ -64,6 +64,8 @@ func (checker *Checker) visitCompositeDeclaration(declaration *ast.CompositeDecl
 
 	checker.checkNestedIdentifiers(declaration.Members)
 
+	checker.checkMemberPragmas(declaration.Members)
+
 	// Activate new scopes for nested types
 
 	checker.typeActivations.Enter()
@@ -74,6 +76,12 @@ func (checker *Checker) visitCompositeDeclaration(declaration *ast.CompositeDecl
 		defer checker.leaveValueScope(declaration.EndPosition, false)
 	}
 
+	checker.declareTypeParameters(
+		declaration.TypeParameterList,
+		compositeType.TypeParameters(),
+		false,
+	)
+
 	checker.declareCompositeNestedTypes(declaration, kind, true)
 
 	var initializationInfo *InitializationInfo
@@ -197,6 +205,10 @@ func (checker *Checker) visitCompositeDeclaration(declaration *ast.CompositeDecl
 	for _, nestedComposite := range declaration.Members.Composites() {
 		nestedComposite.Accept(checker)
 	}
+
+	for _, nestedTypeAlias := range declaration.Members.TypeAliases() {
+		nestedTypeAlias.Accept(checker)
+	}
 }
 
 // declareCompositeNestedTypes declares the types nested in a composite,
@@ -455,6 +467,25 @@ func (checker *Checker) declareCompositeType(declaration *ast.CompositeDeclarati
 	checker.Elaboration.CompositeDeclarationTypes[declaration] = compositeType
 	checker.Elaboration.CompositeTypeDeclarations[compositeType] = declaration
 
+	// Only structures and resources may be generic
+
+	if !declaration.TypeParameterList.IsEmpty() {
+		switch declaration.CompositeKind {
+		case common.CompositeKindStructure,
+			common.CompositeKindResource:
+
+			compositeType.SetTypeParameters(
+				checker.typeParameters(declaration.TypeParameterList),
+			)
+
+		default:
+			checker.reportInvalidTypeParameters(
+				declaration.DeclarationKind(),
+				declaration.TypeParameterList,
+			)
+		}
+	}
+
 	// Activate new scope for nested declarations
 
 	checker.typeActivations.Enter()
@@ -475,6 +506,16 @@ func (checker *Checker) declareCompositeType(declaration *ast.CompositeDeclarati
 
 	checker.Elaboration.CompositeNestedDeclarations[declaration] = nestedDeclarations
 
+	// Only contracts support nested type aliases.
+	// They are declared separately, see `declareCompositeTypeAliases`
+
+	if declaration.CompositeKind != common.CompositeKindContract {
+		checker.reportInvalidNestedTypeAliases(
+			declaration.DeclarationKind(),
+			declaration.Members.TypeAliases(),
+		)
+	}
+
 	for _, nestedInterfaceType := range nestedInterfaceTypes {
 		compositeType.nestedTypes.Set(nestedInterfaceType.Identifier, nestedInterfaceType)
 		nestedInterfaceType.SetContainerType(compositeType)
@@ -515,6 +556,12 @@ func (checker *Checker) declareCompositeMembersAndValue(
 		checker.enterValueScope()
 		defer checker.leaveValueScope(declaration.EndPosition, false)
 
+		checker.declareTypeParameters(
+			declaration.TypeParameterList,
+			compositeType.TypeParameters(),
+			true,
+		)
+
 		checker.declareCompositeNestedTypes(declaration, kind, false)
 
 		// NOTE: determine initializer parameter types while nested types are in scope,
@@ -522,6 +569,7 @@ func (checker *Checker) declareCompositeMembersAndValue(
 
 		initializers := declaration.Members.Initializers()
 		compositeType.ConstructorParameters = checker.initializerParameters(initializers)
+		compositeType.ConstructorPurity = checker.initializerPurity(compositeType.Kind, initializers)
 
 		// Declare nested declarations' members
 
@@ -577,7 +625,14 @@ func (checker *Checker) declareCompositeMembersAndValue(
 		// in which case it is a type requirement,
 		// and this nested composite type implicitly conforms to it.
 
+		nestedDeclarations := checker.Elaboration.CompositeNestedDeclarations[declaration]
+
 		compositeType.GetNestedTypes().Foreach(func(nestedTypeIdentifier string, nestedType Type) {
+			// Type aliases never declare a composite type, even if they alias one
+			if _, ok := nestedDeclarations[nestedTypeIdentifier].(*ast.TypeAliasDeclaration); ok {
+				return
+			}
+
 			nestedCompositeType, ok := nestedType.(*CompositeType)
 			if !ok {
 				return
@@ -638,6 +693,7 @@ func (checker *Checker) declareCompositeMembersAndValue(
 
 		compositeType.Members = members
 		compositeType.Fields = fields
+		compositeType.RemovedFields = RemovedFieldNames(declaration.Members)
 		if checker.positionInfoEnabled {
 			checker.memberOrigins[compositeType] = origins
 		}
@@ -804,6 +860,7 @@ func (checker *Checker) declareEnumConstructor(
 
 func EnumConstructorType(compositeType *CompositeType) *FunctionType {
 	return &FunctionType{
+		Purity:        FunctionPurityView,
 		IsConstructor: true,
 		Parameters: []*Parameter{
 			{
@@ -842,6 +899,26 @@ func (checker *Checker) checkMemberStorability(members *StringMemberOrderedMap)
 	})
 }
 
+// initializerPurity returns the purity of the initializer.
+//
+// The constructor of a composite without an initializer is a view function,
+// as is the synthesized initializer of an event.
+//
+func (checker *Checker) initializerPurity(
+	compositeKind common.CompositeKind,
+	initializers []*ast.SpecialFunctionDeclaration,
+) FunctionPurity {
+	if compositeKind == common.CompositeKindEvent ||
+		len(initializers) == 0 {
+
+		return FunctionPurityView
+	}
+
+	// TODO: support multiple overloaded initializers
+
+	return PurityFromAnnotation(initializers[0].FunctionDeclaration.Purity)
+}
+
 func (checker *Checker) initializerParameters(initializers []*ast.SpecialFunctionDeclaration) []*Parameter {
 	// TODO: support multiple overloaded initializers
 	var parameters []*Parameter
@@ -1006,9 +1083,17 @@ func (checker *Checker) checkCompositeConformance(
 			ReturnTypeAnnotation: NewTypeAnnotation(VoidType),
 		}
 
+		// A view initializer satisfies any initializer requirement,
+		// but an impure initializer does not satisfy a view initializer requirement
+
+		purityMismatch := interfaceType.InitializerPurity == FunctionPurityView &&
+			compositeType.ConstructorPurity != FunctionPurityView
+
 		// TODO: subtype?
-		if !initializerType.Equal(interfaceInitializerType) {
+		if purityMismatch || !initializerType.Equal(interfaceInitializerType) {
 			initializerMismatch = &InitializerMismatch{
+				CompositePurity:     compositeType.ConstructorPurity,
+				InterfacePurity:     interfaceType.InitializerPurity,
 				CompositeParameters: compositeType.ConstructorParameters,
 				InterfaceParameters: interfaceType.InitializerParameters,
 			}
@@ -1046,6 +1131,8 @@ func (checker *Checker) checkCompositeConformance(
 
 	// Determine missing nested composite type definitions
 
+	nestedDeclarations := checker.Elaboration.CompositeNestedDeclarations[compositeDeclaration]
+
 	interfaceType.nestedTypes.Foreach(func(name string, typeRequirement Type) {
 
 		// Only nested composite declarations are type requirements of the interface
@@ -1055,8 +1142,11 @@ func (checker *Checker) checkCompositeConformance(
 			return
 		}
 
+		// NOTE: A type alias does not fulfill a type requirement, even if it aliases a composite type
+
 		nestedCompositeType, ok := compositeType.nestedTypes.Get(name)
-		if !ok {
+		_, isTypeAlias := nestedDeclarations[name].(*ast.TypeAliasDeclaration)
+		if !ok || isTypeAlias {
 			missingNestedCompositeTypes = append(missingNestedCompositeTypes, requiredCompositeType)
 			return
 		}
@@ -1131,6 +1221,15 @@ func (checker *Checker) memberSatisfied(compositeMember, interfaceMember *Member
 				return false
 			}
 
+			// A view function satisfies any function requirement,
+			// but an impure function does not satisfy a view function requirement
+
+			if interfaceMemberFunctionType.Purity == FunctionPurityView &&
+				compositeMemberFunctionType.Purity != FunctionPurityView {
+
+				return false
+			}
+
 			// Functions are invariant in their parameter types
 
 			for i, subParameter := range compositeMemberFunctionType.Parameters {
@@ -1313,8 +1412,14 @@ func (checker *Checker) compositeConstructorType(
 	argumentLabels []string,
 ) {
 
+	// The constructor of a generic composite type is generic,
+	// e.g. the constructor of `Box<T>` has the type parameter `T`,
+	// so the type arguments of the instantiation are inferred from the arguments
+
 	constructorFunctionType = &FunctionType{
+		Purity:               compositeType.ConstructorPurity,
 		IsConstructor:        true,
+		TypeParameters:       compositeType.TypeParameters(),
 		ReturnTypeAnnotation: NewTypeAnnotation(compositeType),
 	}
 
@@ -1336,6 +1441,7 @@ func (checker *Checker) compositeConstructorType(
 
 		checker.Elaboration.ConstructorFunctionTypes[firstInitializer] =
 			&FunctionType{
+				Purity:               compositeType.ConstructorPurity,
 				IsConstructor:        true,
 				Parameters:           constructorFunctionType.Parameters,
 				ReturnTypeAnnotation: NewTypeAnnotation(VoidType),
@@ -1480,7 +1586,19 @@ func (checker *Checker) defaultMembersAndOrigins(
 
 		identifier := function.Identifier.Identifier
 
-		functionType := checker.functionType(function.ParameterList, function.ReturnTypeAnnotation)
+		functionType := checker.functionType(
+			function.Purity,
+			function.TypeParameterList,
+			function.ParameterList,
+			function.ReturnTypeAnnotation,
+		)
+
+		// NOTE: record the function type of generic functions, so the function body is checked using it:
+		// The type parameters of the function body and of the member type must be the same
+
+		if !function.TypeParameterList.IsEmpty() {
+			checker.Elaboration.FunctionDeclarationFunctionTypes[function] = functionType
+		}
 
 		argumentLabels := function.ParameterList.EffectiveArgumentLabels()
 
@@ -1745,6 +1863,7 @@ func (checker *Checker) checkSpecialFunction(
 	checker.declareSelfValue(containerType, containerDocString)
 
 	functionType := &FunctionType{
+		Purity:               PurityFromAnnotation(specialFunction.FunctionDeclaration.Purity),
 		Parameters:           parameters,
 		ReturnTypeAnnotation: NewTypeAnnotation(VoidType),
 	}
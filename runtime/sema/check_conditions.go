@@ -32,6 +32,11 @@ func (checker *Checker) visitConditions(conditions []*ast.Condition) {
 		checker.inCondition = wasInCondition
 	}()
 
+	// conditions must be pure, i.e. they cannot have side effects
+
+	checker.pushPurityScope(true)
+	defer checker.popPurityScope()
+
 	// check all conditions: check the expression
 	// and ensure the result is boolean
 
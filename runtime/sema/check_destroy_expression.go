@@ -25,6 +25,11 @@ import (
 func (checker *Checker) VisitDestroyExpression(expression *ast.DestroyExpression) (resultType ast.Repr) {
 	resultType = VoidType
 
+	// Destroying a resource is an impure operation,
+	// as it invokes the destructor
+
+	checker.observeImpureOperation(expression)
+
 	valueType := checker.VisitExpression(expression.Expression, nil)
 
 	checker.recordResourceInvalidation(
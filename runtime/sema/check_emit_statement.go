@@ -26,6 +26,10 @@ import (
 func (checker *Checker) VisitEmitStatement(statement *ast.EmitStatement) ast.Repr {
 	invocation := statement.InvocationExpression
 
+	// Emitting an event is an impure operation
+
+	checker.observeImpureOperation(statement)
+
 	ty := checker.checkInvocationExpression(invocation)
 
 	if ty.IsInvalidType() {
@@ -230,6 +230,40 @@ func (checker *Checker) VisitStringExpression(expression *ast.StringExpression)
 	return actualType
 }
 
+func (checker *Checker) VisitStringTemplateExpression(expression *ast.StringTemplateExpression) ast.Repr {
+	for _, embeddedExpression := range expression.Expressions {
+		valueType := checker.VisitExpression(embeddedExpression, nil)
+
+		if !valueType.IsInvalidType() && !IsValidStringTemplateValueType(valueType) {
+			checker.report(
+				&InvalidStringTemplateValueTypeError{
+					Type:  valueType,
+					Range: ast.NewRangeFromPositioned(checker.memoryGauge, embeddedExpression),
+				},
+			)
+		}
+	}
+
+	return StringType
+}
+
+// IsValidStringTemplateValueType returns true if values of the given type
+// can be embedded in a string template.
+//
+func IsValidStringTemplateValueType(ty Type) bool {
+	switch ty {
+	case StringType, CharacterType, BoolType:
+		return true
+	}
+
+	if _, ok := ty.(*AddressType); ok {
+		return true
+	}
+
+	return IsSubType(ty, NumberType) ||
+		IsSubType(ty, PathType)
+}
+
 func (checker *Checker) VisitIndexExpression(expression *ast.IndexExpression) ast.Repr {
 	return checker.visitIndexExpression(expression, false)
 }
@@ -69,7 +69,12 @@ func (checker *Checker) visitFunctionDeclaration(
 
 	functionType := checker.Elaboration.FunctionDeclarationFunctionTypes[declaration]
 	if functionType == nil {
-		functionType = checker.functionType(declaration.ParameterList, declaration.ReturnTypeAnnotation)
+		functionType = checker.functionType(
+			declaration.Purity,
+			declaration.TypeParameterList,
+			declaration.ParameterList,
+			declaration.ReturnTypeAnnotation,
+		)
 
 		if options.declareFunction {
 			checker.declareFunctionDeclaration(declaration, functionType)
@@ -78,6 +83,15 @@ func (checker *Checker) visitFunctionDeclaration(
 
 	checker.Elaboration.FunctionDeclarationFunctionTypes[declaration] = functionType
 
+	// Declare the type parameters, if any, so they are in scope in the function body
+
+	if !declaration.TypeParameterList.IsEmpty() {
+		checker.typeActivations.Enter()
+		defer checker.typeActivations.Leave(declaration.EndPosition)
+
+		checker.declareTypeParameters(declaration.TypeParameterList, functionType.TypeParameters, false)
+	}
+
 	checker.checkFunction(
 		declaration.ParameterList,
 		declaration.ReturnTypeAnnotation,
@@ -156,6 +170,13 @@ func (checker *Checker) checkFunction(
 			//   variable declarations will have proper function activation
 			//   associated to it, and declare parameters in this new scope
 
+			// The body of a view function must be pure.
+			// The scope is entered before the function's value scope,
+			// so the parameters and variables of the function are local to it
+
+			checker.pushPurityScope(functionType.Purity == FunctionPurityView)
+			defer checker.popPurityScope()
+
 			var endPosGetter EndPositionGetter
 			if functionBlock != nil {
 				endPosGetter = functionBlock.EndPosition
@@ -340,7 +361,12 @@ func (checker *Checker) visitWithPostConditions(postConditions *ast.Conditions,
 
 		checker.Elaboration.PostConditionsRewrite[postConditions] = rewriteResult
 
-		checker.visitStatements(rewriteResult.BeforeStatements)
+		// The extracted `before` expressions are part of the post-conditions,
+		// so they must be pure
+
+		checker.withPurityScope(true, func() {
+			checker.visitStatements(rewriteResult.BeforeStatements)
+		})
 	}
 
 	body()
@@ -423,7 +449,12 @@ func (checker *Checker) declareBefore() {
 func (checker *Checker) VisitFunctionExpression(expression *ast.FunctionExpression) ast.Repr {
 
 	// TODO: infer
-	functionType := checker.functionType(expression.ParameterList, expression.ReturnTypeAnnotation)
+	functionType := checker.functionType(
+		expression.Purity,
+		nil,
+		expression.ParameterList,
+		expression.ReturnTypeAnnotation,
+	)
 
 	checker.Elaboration.FunctionExpressionFunctionType[expression] = functionType
 
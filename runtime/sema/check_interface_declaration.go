@@ -264,6 +264,11 @@ func (checker *Checker) declareInterfaceType(declaration *ast.InterfaceDeclarati
 
 	checker.Elaboration.InterfaceNestedDeclarations[declaration] = nestedDeclarations
 
+	checker.reportInvalidNestedTypeAliases(
+		declaration.DeclarationKind(),
+		declaration.Members.TypeAliases(),
+	)
+
 	for _, nestedInterfaceType := range nestedInterfaceTypes {
 		interfaceType.nestedTypes.Set(nestedInterfaceType.Identifier, nestedInterfaceType)
 		nestedInterfaceType.SetContainerType(interfaceType)
@@ -325,8 +330,9 @@ func (checker *Checker) declareInterfaceMembers(declaration *ast.InterfaceDeclar
 	// NOTE: determine initializer parameter types while nested types are in scope,
 	// and after declaring nested types as the initializer may use nested type in parameters
 
-	interfaceType.InitializerParameters =
-		checker.initializerParameters(declaration.Members.Initializers())
+	initializers := declaration.Members.Initializers()
+	interfaceType.InitializerParameters = checker.initializerParameters(initializers)
+	interfaceType.InitializerPurity = checker.initializerPurity(declaration.CompositeKind, initializers)
 
 	// Declare nested declarations' members
 
@@ -107,6 +107,13 @@ func (checker *Checker) checkInvocationExpression(invocationExpression *ast.Invo
 		return InvalidType
 	}
 
+	// Invoking a function which is not a view function is an impure operation
+
+	var isLocalContainerInvocation bool
+	if functionType.Purity != FunctionPurityView {
+		isLocalContainerInvocation = checker.checkInvocationPurity(invocationExpression)
+	}
+
 	// The invoked expression has a function type,
 	// check the invocation including all arguments.
 	//
@@ -131,6 +138,10 @@ func (checker *Checker) checkInvocationExpression(invocationExpression *ast.Invo
 		checkInvocation()
 	}
 
+	if isLocalContainerInvocation {
+		checker.checkLocalContainerInvocationArgumentsPurity(invocationExpression, argumentTypes)
+	}
+
 	arguments := invocationExpression.Arguments
 
 	if checker.positionInfoEnabled && len(arguments) > 0 {
@@ -474,11 +485,13 @@ func (checker *Checker) checkInvocation(
 
 	// Check all type parameters have been bound to a type.
 
-	checker.checkTypeParameterInference(
+	if !checker.checkTypeParameterInference(
 		functionType,
 		typeArguments,
 		invocationExpression,
-	)
+	) {
+		returnType = InvalidType
+	}
 
 	// Save types in the elaboration
 
@@ -490,13 +503,16 @@ func (checker *Checker) checkInvocation(
 }
 
 // checkTypeParameterInference checks that all type parameters
-// of the given generic function type have been assigned a type.
+// of the given generic function type have been assigned a type,
+// and returns false if any required type parameter was not assigned a type.
 //
 func (checker *Checker) checkTypeParameterInference(
 	functionType *FunctionType,
 	typeArguments *TypeParameterTypeOrderedMap,
 	invocationExpression *ast.InvocationExpression,
-) {
+) (inferred bool) {
+	inferred = true
+
 	for _, typeParameter := range functionType.TypeParameters {
 
 		if ty, ok := typeArguments.Get(typeParameter); ok && ty != nil {
@@ -515,7 +531,11 @@ func (checker *Checker) checkTypeParameterInference(
 				Range: ast.NewRangeFromPositioned(checker.memoryGauge, invocationExpression),
 			},
 		)
+
+		inferred = false
 	}
+
+	return
 }
 
 func (checker *Checker) checkInvocationRequiredArgument(
@@ -58,3 +58,103 @@ func (checker *Checker) VisitPragmaDeclaration(p *ast.PragmaDeclaration) ast.Rep
 
 	return nil
 }
+
+// RemovedFieldPragmaIdentifier is the identifier of the pragma which declares
+// that a field was removed from a composite, e.g. `#removedField("name")`.
+//
+// Fields of composites can only be removed using this pragma.
+// The stored data of the removed field is purged when the composite is written or moved,
+// and the field may not be declared again
+//
+const RemovedFieldPragmaIdentifier = "removedField"
+
+// RemovedFieldName returns the name of the field removed by the given pragma,
+// if the pragma is a valid `#removedField` pragma
+//
+func RemovedFieldName(pragma *ast.PragmaDeclaration) (string, bool) {
+	invocation := removedFieldPragmaInvocation(pragma)
+	if invocation == nil {
+		return "", false
+	}
+
+	return removedFieldPragmaName(invocation)
+}
+
+// removedFieldPragmaInvocation returns the invocation of the given pragma,
+// if the pragma is a `#removedField` pragma, which might be malformed
+//
+func removedFieldPragmaInvocation(pragma *ast.PragmaDeclaration) *ast.InvocationExpression {
+	invocation, ok := pragma.Expression.(*ast.InvocationExpression)
+	if !ok {
+		return nil
+	}
+
+	identifier, ok := invocation.InvokedExpression.(*ast.IdentifierExpression)
+	if !ok || identifier.Identifier.Identifier != RemovedFieldPragmaIdentifier {
+		return nil
+	}
+
+	return invocation
+}
+
+// removedFieldPragmaName returns the field name of the given `#removedField` pragma invocation,
+// if it is well-formed, i.e. it has exactly one string argument and no type arguments
+//
+func removedFieldPragmaName(invocation *ast.InvocationExpression) (string, bool) {
+	if len(invocation.TypeArguments) > 0 || len(invocation.Arguments) != 1 {
+		return "", false
+	}
+
+	name, ok := invocation.Arguments[0].Expression.(*ast.StringExpression)
+	if !ok {
+		return "", false
+	}
+
+	return name.Value, true
+}
+
+// RemovedFieldNames returns the names of the fields removed by the `#removedField` pragmas
+// of the given members, in declaration order
+//
+func RemovedFieldNames(members *ast.Members) (names []string) {
+	for _, pragma := range members.Pragmas() {
+		name, ok := RemovedFieldName(pragma)
+		if !ok {
+			continue
+		}
+		names = append(names, name)
+	}
+	return
+}
+
+// checkMemberPragmas checks the pragmas declared in a composite.
+// Removed fields must be well-formed and may not be declared again
+//
+func (checker *Checker) checkMemberPragmas(members *ast.Members) {
+	fields := members.FieldsByIdentifier()
+
+	for _, pragma := range members.Pragmas() {
+		checker.VisitPragmaDeclaration(pragma)
+
+		invocation := removedFieldPragmaInvocation(pragma)
+		if invocation == nil {
+			continue
+		}
+
+		name, ok := removedFieldPragmaName(invocation)
+		if !ok {
+			checker.report(&InvalidPragmaError{
+				Message: "`removedField` requires exactly one field name",
+				Range:   ast.NewRangeFromPositioned(checker.memoryGauge, invocation),
+			})
+			continue
+		}
+
+		if field, ok := fields[name]; ok {
+			checker.report(&RemovedFieldRedeclarationError{
+				Name:  name,
+				Range: ast.NewRangeFromPositioned(checker.memoryGauge, field.Identifier),
+			})
+		}
+	}
+}
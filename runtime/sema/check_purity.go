@@ -0,0 +1,222 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// PurityFromAnnotation returns the function purity for the given purity annotation
+//
+func PurityFromAnnotation(purity ast.FunctionPurity) FunctionPurity {
+	if purity == ast.FunctionPurityView {
+		return FunctionPurityView
+	}
+	return FunctionPurityImpure
+}
+
+// PurityCheckScope is a scope in which impure operations are observed,
+// e.g. the body of a function, or a condition.
+//
+type PurityCheckScope struct {
+	// EnforcePurity specifies if impure operations are reported as errors.
+	EnforcePurity bool
+	// ActivationDepth is the depth of the value activations when the scope was entered.
+	// Variables declared at a greater depth are local to the scope.
+	ActivationDepth int
+}
+
+func (checker *Checker) pushPurityScope(enforcePurity bool) {
+	checker.purityCheckScopes = append(
+		checker.purityCheckScopes,
+		PurityCheckScope{
+			EnforcePurity:   enforcePurity,
+			ActivationDepth: checker.valueActivations.Depth(),
+		},
+	)
+}
+
+func (checker *Checker) popPurityScope() {
+	lastIndex := len(checker.purityCheckScopes) - 1
+	checker.purityCheckScopes = checker.purityCheckScopes[:lastIndex]
+}
+
+func (checker *Checker) withPurityScope(enforcePurity bool, f func()) {
+	checker.pushPurityScope(enforcePurity)
+	defer checker.popPurityScope()
+
+	f()
+}
+
+func (checker *Checker) currentPurityScope() PurityCheckScope {
+	count := len(checker.purityCheckScopes)
+	if count == 0 {
+		return PurityCheckScope{}
+	}
+	return checker.purityCheckScopes[count-1]
+}
+
+// observeImpureOperation reports the given operation if the current scope must be pure
+//
+func (checker *Checker) observeImpureOperation(operation ast.HasPosition) {
+	if !checker.currentPurityScope().EnforcePurity {
+		return
+	}
+
+	checker.report(
+		&PurityError{
+			Range: ast.NewRangeFromPositioned(checker.memoryGauge, operation),
+		},
+	)
+}
+
+// checkInvocationPurity checks the invocation of a function which is not a view function.
+//
+// Such an invocation is an impure operation, unless it is the invocation
+// of a built-in function of a local array or dictionary, e.g. `append`,
+// which only mutates local state. In that case, true is returned,
+// and the arguments must be checked using checkLocalContainerInvocationArgumentsPurity.
+//
+func (checker *Checker) checkInvocationPurity(invocationExpression *ast.InvocationExpression) (isLocalContainerInvocation bool) {
+	scope := checker.currentPurityScope()
+	if !scope.EnforcePurity {
+		return false
+	}
+
+	if memberExpression, ok := invocationExpression.InvokedExpression.(*ast.MemberExpression); ok {
+		memberInfo, ok := checker.Elaboration.MemberExpressionMemberInfos[memberExpression]
+		if ok &&
+			isContainerType(memberInfo.AccessedType) &&
+			checker.isLocalAssignmentTarget(memberExpression.Expression, scope, false) {
+
+			return true
+		}
+	}
+
+	checker.observeImpureOperation(invocationExpression)
+
+	return false
+}
+
+// checkLocalContainerInvocationArgumentsPurity checks the arguments of the invocation
+// of a built-in function of a local array or dictionary.
+//
+// Built-in functions which call a given function, e.g. `forEach`,
+// are only pure if all given functions are view functions.
+//
+func (checker *Checker) checkLocalContainerInvocationArgumentsPurity(
+	invocationExpression *ast.InvocationExpression,
+	argumentTypes []Type,
+) {
+	for _, argumentType := range argumentTypes {
+		functionType, ok := argumentType.(*FunctionType)
+		if ok && functionType.Purity != FunctionPurityView {
+			checker.observeImpureOperation(invocationExpression)
+			return
+		}
+	}
+}
+
+// checkAssignmentPurity checks that the given assignment target only refers to local state,
+// if the current scope must be pure.
+//
+// Assigning to a variable is only pure if the variable was declared in the current scope.
+// Assigning to a member or an element is only pure if the accessed value is local,
+// and it is not accessed through a reference.
+//
+func (checker *Checker) checkAssignmentPurity(target ast.Expression) {
+	scope := checker.currentPurityScope()
+	if !scope.EnforcePurity {
+		return
+	}
+
+	if checker.isLocalAssignmentTarget(target, scope, false) {
+		return
+	}
+
+	checker.observeImpureOperation(target)
+}
+
+func (checker *Checker) isLocalAssignmentTarget(
+	expression ast.Expression,
+	scope PurityCheckScope,
+	isAccessed bool,
+) bool {
+	switch expression := expression.(type) {
+	case *ast.IdentifierExpression:
+		variable := checker.valueActivations.Find(expression.Identifier.Identifier)
+		if variable == nil {
+			// The undeclared variable is reported elsewhere
+			return true
+		}
+
+		if variable.ActivationDepth <= scope.ActivationDepth {
+			return false
+		}
+
+		// `self` is declared in the scope of each function of a composite,
+		// but it is only local in the initializer, where the value is constructed
+
+		if variable.DeclarationKind == common.DeclarationKindSelf &&
+			checker.functionActivations.Current().InitializationInfo == nil {
+
+			return false
+		}
+
+		return !isAccessed || !isReferenceType(variable.Type)
+
+	case *ast.MemberExpression:
+		memberInfo, ok := checker.Elaboration.MemberExpressionMemberInfos[expression]
+		if ok && isReferenceType(memberInfo.AccessedType) {
+			return false
+		}
+
+		return checker.isLocalAssignmentTarget(expression.Expression, scope, true)
+
+	case *ast.IndexExpression:
+		indexedType, ok := checker.Elaboration.IndexExpressionIndexedTypes[expression]
+		if ok && isReferenceType(indexedType) {
+			return false
+		}
+
+		return checker.isLocalAssignmentTarget(expression.TargetExpression, scope, true)
+
+	default:
+		return false
+	}
+}
+
+func isReferenceType(ty Type) bool {
+	if optionalType, ok := ty.(*OptionalType); ok {
+		return isReferenceType(optionalType.Type)
+	}
+
+	_, ok := ty.(*ReferenceType)
+	return ok
+}
+
+func isContainerType(ty Type) bool {
+	switch ty.(type) {
+	case *VariableSizedType, *ConstantSizedType, *DictionaryType:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,138 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// VisitTypeAliasDeclaration checks a previously declared type alias declaration.
+//
+// NOTE: The aliased type was already converted when the alias was declared,
+// using `declareTypeAlias`.
+//
+func (checker *Checker) VisitTypeAliasDeclaration(declaration *ast.TypeAliasDeclaration) ast.Repr {
+
+	checker.checkDeclarationAccessModifier(
+		declaration.Access,
+		declaration.DeclarationKind(),
+		declaration.StartPos,
+		true,
+	)
+
+	return nil
+}
+
+// declareTypeAlias converts the aliased type of the given type alias declaration,
+// declares the alias in the current type scope, and records the aliased type in the elaboration.
+//
+// Type aliases are resolved eagerly: the aliased type may only refer to types
+// which are already declared, which includes all composite and interface types,
+// and the type aliases declared before.
+//
+func (checker *Checker) declareTypeAlias(declaration *ast.TypeAliasDeclaration) Type {
+
+	ty := checker.typeAliasType(declaration)
+
+	variable, err := checker.typeActivations.DeclareType(typeDeclaration{
+		identifier:               declaration.Identifier,
+		ty:                       ty,
+		declarationKind:          declaration.DeclarationKind(),
+		access:                   declaration.Access,
+		docString:                declaration.DocString,
+		allowOuterScopeShadowing: false,
+	})
+	checker.report(err)
+
+	if checker.positionInfoEnabled {
+		checker.recordVariableDeclarationOccurrence(
+			declaration.Identifier.Identifier,
+			variable,
+		)
+	}
+
+	return ty
+}
+
+func (checker *Checker) typeAliasType(declaration *ast.TypeAliasDeclaration) Type {
+	ty := checker.ConvertType(declaration.Type)
+	checker.Elaboration.TypeAliasDeclarationTypes[declaration] = ty
+	return ty
+}
+
+// declareCompositeTypeAliases declares the type aliases nested in the given composite declaration,
+// and registers them as nested types of the composite type,
+// so they are in scope in the composite and can be referred to by their qualified name.
+//
+// NOTE: This function assumes that all composite and interface types were previously declared
+// using `declareCompositeType` and `declareInterfaceType`, as the aliased types may refer to them.
+//
+func (checker *Checker) declareCompositeTypeAliases(declaration *ast.CompositeDeclaration) {
+
+	typeAliases := declaration.Members.TypeAliases()
+	if len(typeAliases) == 0 ||
+		declaration.CompositeKind != common.CompositeKindContract {
+
+		return
+	}
+
+	compositeType := checker.Elaboration.CompositeDeclarationTypes[declaration]
+	nestedDeclarations := checker.Elaboration.CompositeNestedDeclarations[declaration]
+
+	checker.typeActivations.Enter()
+	defer checker.typeActivations.Leave(declaration.EndPosition)
+
+	checker.declareCompositeNestedTypes(declaration, ContainerKindComposite, false)
+
+	for _, typeAlias := range typeAliases {
+		name := typeAlias.Identifier.Identifier
+
+		// NOTE: Redeclarations of nested types are already reported
+		// when checking the nested identifiers (`checkNestedIdentifiers`)
+
+		if _, exists := nestedDeclarations[name]; exists {
+			checker.typeAliasType(typeAlias)
+			continue
+		}
+
+		ty := checker.declareTypeAlias(typeAlias)
+
+		nestedDeclarations[name] = typeAlias
+		compositeType.nestedTypes.Set(name, ty)
+	}
+}
+
+// reportInvalidNestedTypeAliases reports the given type alias declarations
+// as invalid nested declarations. Only contracts may declare type aliases.
+//
+func (checker *Checker) reportInvalidNestedTypeAliases(
+	containerDeclarationKind common.DeclarationKind,
+	typeAliases []*ast.TypeAliasDeclaration,
+) {
+	for _, typeAlias := range typeAliases {
+		checker.report(
+			&InvalidNestedDeclarationError{
+				NestedDeclarationKind:    typeAlias.DeclarationKind(),
+				ContainerDeclarationKind: containerDeclarationKind,
+				Range:                    ast.NewRangeFromPositioned(checker.memoryGauge, typeAlias.Identifier),
+			},
+		)
+	}
+}
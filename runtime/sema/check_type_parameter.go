@@ -0,0 +1,116 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sema
+
+import (
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+)
+
+// typeParameters converts the type parameters of a generic function or composite declaration.
+//
+// Type parameters without a type bound are bound by `AnyStruct`,
+// so it is known if a value of the generic type must be moved.
+//
+func (checker *Checker) typeParameters(typeParameterList *ast.TypeParameterList) []*TypeParameter {
+	if typeParameterList.IsEmpty() {
+		return nil
+	}
+
+	typeParameters := make([]*TypeParameter, 0, len(typeParameterList.TypeParameters))
+
+	for _, typeParameter := range typeParameterList.TypeParameters {
+
+		var typeBound Type = AnyStructType
+
+		if typeParameter.TypeBound != nil {
+			typeBound = checker.ConvertType(typeParameter.TypeBound)
+		}
+
+		typeParameters = append(
+			typeParameters,
+			&TypeParameter{
+				Name:      typeParameter.Identifier.Identifier,
+				TypeBound: typeBound,
+			},
+		)
+	}
+
+	return typeParameters
+}
+
+// declareTypeParameters declares the given type parameters as generic types in the current type scope.
+//
+// The type parameters of a declaration are declared multiple times,
+// e.g. when converting the function type and when checking the function body.
+// Errors and occurrences are only reported/recorded if `isFirstDeclaration` is true.
+//
+func (checker *Checker) declareTypeParameters(
+	typeParameterList *ast.TypeParameterList,
+	typeParameters []*TypeParameter,
+	isFirstDeclaration bool,
+) {
+	if typeParameterList.IsEmpty() {
+		return
+	}
+
+	for i, typeParameter := range typeParameters {
+		identifier := typeParameterList.TypeParameters[i].Identifier
+
+		variable, err := checker.typeActivations.DeclareType(typeDeclaration{
+			identifier: identifier,
+			ty: &GenericType{
+				TypeParameter: typeParameter,
+			},
+			declarationKind:          common.DeclarationKindTypeParameter,
+			access:                   ast.AccessNotSpecified,
+			allowOuterScopeShadowing: false,
+		})
+		if !isFirstDeclaration {
+			continue
+		}
+
+		checker.report(err)
+
+		if checker.positionInfoEnabled {
+			checker.recordVariableDeclarationOccurrence(
+				identifier.Identifier,
+				variable,
+			)
+		}
+	}
+}
+
+// reportInvalidTypeParameters reports type parameters of declarations which cannot be generic
+//
+func (checker *Checker) reportInvalidTypeParameters(
+	declarationKind common.DeclarationKind,
+	typeParameterList *ast.TypeParameterList,
+) {
+	if typeParameterList.IsEmpty() {
+		return
+	}
+
+	checker.report(
+		&InvalidTypeParametersError{
+			DeclarationKind: declarationKind,
+			Range:           typeParameterList.Range,
+		},
+	)
+}
@@ -49,6 +49,7 @@ var beforeType = func() *FunctionType {
 	)
 
 	return &FunctionType{
+		Purity: FunctionPurityView,
 		TypeParameters: []*TypeParameter{
 			typeParameter,
 		},
@@ -94,6 +95,7 @@ type Checker struct {
 	containerTypes                     map[Type]bool
 	functionActivations                *FunctionActivations
 	inCondition                        bool
+	purityCheckScopes                  []PurityCheckScope
 	positionInfoEnabled                bool
 	Occurrences                        *Occurrences
 	variableOrigins                    map[*Variable]*Origin
@@ -523,6 +525,17 @@ func (checker *Checker) VisitProgram(program *ast.Program) ast.Repr {
 		VisitThisAndNested(compositeType, registerInElaboration)
 	}
 
+	// Declare type aliases, after interface and composite types,
+	// as the aliased types may refer to them
+
+	for _, declaration := range program.TypeAliasDeclarations() {
+		checker.declareTypeAlias(declaration)
+	}
+
+	for _, declaration := range program.CompositeDeclarations() {
+		checker.declareCompositeTypeAliases(declaration)
+	}
+
 	// Declare interfaces' and composites' members
 
 	for _, declaration := range program.InterfaceDeclarations() {
@@ -609,7 +622,12 @@ func (checker *Checker) checkTopLevelDeclarationValidity(declarations []ast.Decl
 }
 
 func (checker *Checker) declareGlobalFunctionDeclaration(declaration *ast.FunctionDeclaration) {
-	functionType := checker.functionType(declaration.ParameterList, declaration.ReturnTypeAnnotation)
+	functionType := checker.functionType(
+		declaration.Purity,
+		declaration.TypeParameterList,
+		declaration.ParameterList,
+		declaration.ReturnTypeAnnotation,
+	)
 	checker.Elaboration.FunctionDeclarationFunctionTypes[declaration] = functionType
 	checker.declareFunctionDeclaration(declaration, functionType)
 }
@@ -955,7 +973,9 @@ func (checker *Checker) findAndCheckValueVariable(identifierExpression *ast.Iden
 func (checker *Checker) ConvertType(t ast.Type) Type {
 	switch t := t.(type) {
 	case *ast.NominalType:
-		return checker.convertNominalType(t)
+		ty := checker.convertNominalType(t)
+		checker.checkTypeArgumentsProvided(ty, t)
+		return ty
 
 	case *ast.VariableSizedType:
 		return checker.convertVariableSizedType(t)
@@ -1275,6 +1295,7 @@ func (checker *Checker) convertFunctionType(t *ast.FunctionType) Type {
 	returnTypeAnnotation := checker.ConvertTypeAnnotation(t.ReturnTypeAnnotation)
 
 	return &FunctionType{
+		Purity:               PurityFromAnnotation(t.Purity),
 		Parameters:           parameters,
 		ReturnTypeAnnotation: returnTypeAnnotation,
 	}
@@ -1423,15 +1444,33 @@ func (checker *Checker) ConvertTypeAnnotation(typeAnnotation *ast.TypeAnnotation
 }
 
 func (checker *Checker) functionType(
+	purity ast.FunctionPurity,
+	typeParameterList *ast.TypeParameterList,
 	parameterList *ast.ParameterList,
 	returnTypeAnnotation *ast.TypeAnnotation,
 ) *FunctionType {
+
+	// Declare the type parameters, if any,
+	// so they can be used in the parameter types and return type
+
+	var typeParameters []*TypeParameter
+
+	if !typeParameterList.IsEmpty() {
+		checker.typeActivations.Enter()
+		defer checker.typeActivations.Leave(typeParameterList.EndPosition)
+
+		typeParameters = checker.typeParameters(typeParameterList)
+		checker.declareTypeParameters(typeParameterList, typeParameters, true)
+	}
+
 	convertedParameters := checker.parameters(parameterList)
 
 	convertedReturnTypeAnnotation :=
 		checker.ConvertTypeAnnotation(returnTypeAnnotation)
 
 	return &FunctionType{
+		Purity:               PurityFromAnnotation(purity),
+		TypeParameters:       typeParameters,
 		Parameters:           convertedParameters,
 		ReturnTypeAnnotation: convertedReturnTypeAnnotation,
 	}
@@ -2341,9 +2380,36 @@ func (checker *Checker) effectiveCompositeMemberAccess(access ast.Access) ast.Ac
 	}
 }
 
+// checkTypeArgumentsProvided reports an error if the given type is a generic composite type,
+// i.e. it is referred to without type arguments
+//
+func (checker *Checker) checkTypeArgumentsProvided(ty Type, t *ast.NominalType) {
+	compositeType, ok := ty.(*CompositeType)
+	if !ok || !compositeType.IsGeneric() {
+		return
+	}
+
+	checker.report(
+		&MissingTypeArgumentsError{
+			Type:               compositeType,
+			TypeParameterCount: len(compositeType.TypeParameters()),
+			Range:              ast.NewRangeFromPositioned(checker.memoryGauge, t),
+		},
+	)
+}
+
 func (checker *Checker) convertInstantiationType(t *ast.InstantiationType) Type {
 
-	ty := checker.ConvertType(t.Type)
+	// NOTE: a nominal type is converted directly,
+	// as generic composite types may only be referred to without type arguments
+	// when they are instantiated
+
+	var ty Type
+	if nominalType, ok := t.Type.(*ast.NominalType); ok {
+		ty = checker.convertNominalType(nominalType)
+	} else {
+		ty = checker.ConvertType(t.Type)
+	}
 
 	// Always convert (check) the type arguments,
 	// even if the instantiated type
@@ -2358,7 +2424,7 @@ func (checker *Checker) convertInstantiationType(t *ast.InstantiationType) Type
 	}
 
 	parameterizedType, ok := ty.(ParameterizedType)
-	if !ok {
+	if !ok || len(parameterizedType.TypeParameters()) == 0 {
 
 		// The type is not parameterized,
 		// report an error for all type arguments
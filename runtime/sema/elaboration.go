@@ -65,6 +65,7 @@ type Elaboration struct {
 	StringExpressionType                map[*ast.StringExpression]Type
 	FixedPointExpression                map[*ast.FixedPointExpression]Type
 	TransactionDeclarationTypes         map[*ast.TransactionDeclaration]*TransactionType
+	TypeAliasDeclarationTypes           map[*ast.TypeAliasDeclaration]Type
 	SwapStatementLeftTypes              map[*ast.SwapStatement]Type
 	SwapStatementRightTypes             map[*ast.SwapStatement]Type
 	// IsNestedResourceMoveExpression indicates if the access the index or member expression
@@ -125,6 +126,7 @@ func NewElaboration(gauge common.MemoryGauge) *Elaboration {
 		StringExpressionType:                map[*ast.StringExpression]Type{},
 		FixedPointExpression:                map[*ast.FixedPointExpression]Type{},
 		TransactionDeclarationTypes:         map[*ast.TransactionDeclaration]*TransactionType{},
+		TypeAliasDeclarationTypes:           map[*ast.TypeAliasDeclaration]Type{},
 		SwapStatementLeftTypes:              map[*ast.SwapStatement]Type{},
 		SwapStatementRightTypes:             map[*ast.SwapStatement]Type{},
 		IsNestedResourceMoveExpression:      map[ast.Expression]struct{}{},
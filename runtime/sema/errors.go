@@ -68,6 +68,23 @@ func (e *InvalidPragmaError) Error() string {
 	return fmt.Sprintf("invalid pragma %s", e.Message)
 }
 
+// RemovedFieldRedeclarationError
+
+type RemovedFieldRedeclarationError struct {
+	Name string
+	ast.Range
+}
+
+func (e *RemovedFieldRedeclarationError) isSemanticError() {}
+
+func (e *RemovedFieldRedeclarationError) Error() string {
+	return fmt.Sprintf("cannot declare removed field `%s`", e.Name)
+}
+
+func (e *RemovedFieldRedeclarationError) SecondaryError() string {
+	return "the stored data of a removed field is purged, so the field cannot be declared again"
+}
+
 // MissingLocationError
 
 type MissingLocationError struct{}
@@ -824,6 +841,23 @@ func (e *FunctionExpressionInConditionError) Error() string {
 
 func (*FunctionExpressionInConditionError) isSemanticError() {}
 
+// PurityError
+
+type PurityError struct {
+	ast.Range
+}
+
+func (e *PurityError) Error() string {
+	return "impure operation performed in view context"
+}
+
+func (e *PurityError) SecondaryError() string {
+	return "view functions and conditions cannot mutate non-local state, emit events, " +
+		"or call functions which are not view functions"
+}
+
+func (*PurityError) isSemanticError() {}
+
 // MissingReturnValueError
 
 type MissingReturnValueError struct {
@@ -947,6 +981,8 @@ type MemberMismatch struct {
 }
 
 type InitializerMismatch struct {
+	CompositePurity     FunctionPurity
+	InterfacePurity     FunctionPurity
 	CompositeParameters []*Parameter
 	InterfaceParameters []*Parameter
 }
@@ -1763,6 +1799,26 @@ func (e *InvalidEventParameterTypeError) Error() string {
 
 func (*InvalidEventParameterTypeError) isSemanticError() {}
 
+// InvalidStringTemplateValueTypeError
+
+type InvalidStringTemplateValueTypeError struct {
+	Type Type
+	ast.Range
+}
+
+func (e *InvalidStringTemplateValueTypeError) Error() string {
+	return fmt.Sprintf(
+		"cannot embed value of type `%s` in string template",
+		e.Type.QualifiedString(),
+	)
+}
+
+func (*InvalidStringTemplateValueTypeError) isSemanticError() {}
+
+func (*InvalidStringTemplateValueTypeError) SecondaryError() string {
+	return "only strings, characters, booleans, numbers, addresses, and paths can be embedded"
+}
+
 // InvalidEventUsageError
 
 type InvalidEventUsageError struct {
@@ -2786,6 +2842,46 @@ func (e *TypeParameterTypeInferenceError) Error() string {
 
 func (e *TypeParameterTypeInferenceError) isSemanticError() {}
 
+// InvalidTypeParametersError
+
+type InvalidTypeParametersError struct {
+	DeclarationKind common.DeclarationKind
+	ast.Range
+}
+
+func (e *InvalidTypeParametersError) Error() string {
+	return fmt.Sprintf(
+		"%s declarations cannot have type parameters",
+		e.DeclarationKind.Name(),
+	)
+}
+
+func (e *InvalidTypeParametersError) isSemanticError() {}
+
+// MissingTypeArgumentsError
+
+type MissingTypeArgumentsError struct {
+	Type               Type
+	TypeParameterCount int
+	ast.Range
+}
+
+func (e *MissingTypeArgumentsError) Error() string {
+	return fmt.Sprintf(
+		"missing type arguments for generic type `%s`",
+		e.Type.QualifiedString(),
+	)
+}
+
+func (e *MissingTypeArgumentsError) SecondaryError() string {
+	return fmt.Sprintf(
+		"expected %d type arguments",
+		e.TypeParameterCount,
+	)
+}
+
+func (e *MissingTypeArgumentsError) isSemanticError() {}
+
 // InvalidConstantSizedTypeBaseError
 
 type InvalidConstantSizedTypeBaseError struct {
@@ -32,6 +32,8 @@ const PublicAccountGetCapabilityField = "getCapability"
 const PublicAccountGetTargetLinkField = "getLinkTarget"
 const PublicAccountKeysField = "keys"
 const PublicAccountContractsField = "contracts"
+const PublicAccountPublicPathsField = "publicPaths"
+const PublicAccountForEachPublicField = "forEachPublic"
 
 // PublicAccountType represents the publicly accessible portion of an account.
 //
@@ -106,6 +108,18 @@ var PublicAccountType = func() *CompositeType {
 			PublicAccountContractsType,
 			accountTypeContractsFieldDocString,
 		),
+		NewUnmeteredPublicConstantFieldMember(
+			publicAccountType,
+			PublicAccountPublicPathsField,
+			AccountPublicPathsType,
+			accountTypePublicPathsFieldDocString,
+		),
+		NewUnmeteredPublicFunctionMember(
+			publicAccountType,
+			PublicAccountForEachPublicField,
+			AccountForEachPublicFunctionType,
+			accountForEachPublicFunctionDocString,
+		),
 	}
 
 	publicAccountType.Members = GetMembersAsMap(members)
@@ -152,6 +166,7 @@ var PublicAccountTypeGetCapabilityFunctionType = func() *FunctionType {
 	}
 
 	return &FunctionType{
+		Purity: FunctionPurityView,
 		TypeParameters: []*TypeParameter{
 			typeParameter,
 		},
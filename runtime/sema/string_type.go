@@ -28,6 +28,22 @@ const StringTypeEncodeHexFunctionDocString = `
 Returns a hexadecimal string for the given byte array
 `
 
+const StringTypeJoinFunctionName = "join"
+const StringTypeJoinFunctionDocString = `
+Returns a string which contains the given strings, separated by the given separator
+`
+
+const StringTypeFromCharactersFunctionName = "fromCharacters"
+const StringTypeFromCharactersFunctionDocString = `
+Returns a string which contains the given characters
+`
+
+const StringTypeFromUTF8FunctionName = "fromUTF8"
+const StringTypeFromUTF8FunctionDocString = `
+Returns a string for the given UTF-8 encoded byte array.
+Returns nil if the byte array is not a valid UTF-8 encoding
+`
+
 // StringType represents the string type
 //
 var StringType = &SimpleType{
@@ -126,11 +142,84 @@ func init() {
 					)
 				},
 			},
+			"toUpper": {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(memoryGauge common.MemoryGauge, identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						memoryGauge,
+						t,
+						identifier,
+						StringTypeToUpperFunctionType,
+						stringTypeToUpperFunctionDocString,
+					)
+				},
+			},
+			"contains": {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(memoryGauge common.MemoryGauge, identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						memoryGauge,
+						t,
+						identifier,
+						StringTypeContainsFunctionType,
+						stringTypeContainsFunctionDocString,
+					)
+				},
+			},
+			"index": {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(memoryGauge common.MemoryGauge, identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						memoryGauge,
+						t,
+						identifier,
+						StringTypeIndexFunctionType,
+						stringTypeIndexFunctionDocString,
+					)
+				},
+			},
+			"split": {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(memoryGauge common.MemoryGauge, identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						memoryGauge,
+						t,
+						identifier,
+						StringTypeSplitFunctionType,
+						stringTypeSplitFunctionDocString,
+					)
+				},
+			},
+			"replaceAll": {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(memoryGauge common.MemoryGauge, identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						memoryGauge,
+						t,
+						identifier,
+						StringTypeReplaceAllFunctionType,
+						stringTypeReplaceAllFunctionDocString,
+					)
+				},
+			},
+			"trim": {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(memoryGauge common.MemoryGauge, identifier string, _ ast.Range, _ func(error)) *Member {
+					return NewPublicFunctionMember(
+						memoryGauge,
+						t,
+						identifier,
+						StringTypeTrimFunctionType,
+						stringTypeTrimFunctionDocString,
+					)
+				},
+			},
 		}
 	}
 }
 
 var StringTypeConcatFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
 	Parameters: []*Parameter{
 		{
 			Label:          ArgumentLabelNotRequired,
@@ -148,6 +237,7 @@ Returns a new string which contains the given string concatenated to the end of
 `
 
 var StringTypeSliceFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
 	Parameters: []*Parameter{
 		{
 			Identifier:     "from",
@@ -182,6 +272,7 @@ var ByteArrayArrayType = &VariableSizedType{
 }
 
 var StringTypeDecodeHexFunctionType = &FunctionType{
+	Purity:               FunctionPurityView,
 	ReturnTypeAnnotation: NewTypeAnnotation(ByteArrayType),
 }
 
@@ -201,9 +292,160 @@ The byte array of the UTF-8 encoding
 `
 
 var StringTypeToLowerFunctionType = &FunctionType{
+	Purity:               FunctionPurityView,
 	ReturnTypeAnnotation: NewTypeAnnotation(StringType),
 }
 
 const stringTypeToLowerFunctionDocString = `
 Returns the string with upper case letters replaced with lowercase
 `
+
+var StringTypeToUpperFunctionType = &FunctionType{
+	Purity:               FunctionPurityView,
+	ReturnTypeAnnotation: NewTypeAnnotation(StringType),
+}
+
+const stringTypeToUpperFunctionDocString = `
+Returns the string with lower case letters replaced with uppercase
+`
+
+var StringTypeContainsFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
+	Parameters: []*Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "other",
+			TypeAnnotation: NewTypeAnnotation(StringType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(BoolType),
+}
+
+const stringTypeContainsFunctionDocString = `
+Returns true if the string contains the given string.
+
+The given string only matches at character boundaries,
+e.g. a character with a combining mark does not contain the character without the combining mark
+`
+
+var StringTypeIndexFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
+	Parameters: []*Parameter{
+		{
+			Identifier:     "of",
+			TypeAnnotation: NewTypeAnnotation(StringType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(IntType),
+}
+
+const stringTypeIndexFunctionDocString = `
+Returns the index of the first character of the first occurrence of the given string, or -1 if the string does not contain the given string.
+
+The given string only matches at character boundaries
+`
+
+var StringTypeSplitFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
+	Parameters: []*Parameter{
+		{
+			Identifier:     "separator",
+			TypeAnnotation: NewTypeAnnotation(StringType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(
+		&VariableSizedType{
+			Type: StringType,
+		},
+	),
+}
+
+const stringTypeSplitFunctionDocString = `
+Returns the substrings of the string which are separated by the given separator.
+
+The separator only matches at character boundaries.
+If the separator is empty, the string is split into its characters
+`
+
+var StringTypeReplaceAllFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
+	Parameters: []*Parameter{
+		{
+			Identifier:     "of",
+			TypeAnnotation: NewTypeAnnotation(StringType),
+		},
+		{
+			Identifier:     "with",
+			TypeAnnotation: NewTypeAnnotation(StringType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(StringType),
+}
+
+const stringTypeReplaceAllFunctionDocString = `
+Returns a new string with all non-overlapping occurrences of ` + "`of`" + ` replaced with ` + "`with`" + `.
+
+The replaced string only matches at character boundaries.
+If ` + "`of`" + ` is empty, the original string is returned
+`
+
+var StringTypeTrimFunctionType = &FunctionType{
+	Purity:               FunctionPurityView,
+	ReturnTypeAnnotation: NewTypeAnnotation(StringType),
+}
+
+const stringTypeTrimFunctionDocString = `
+Returns the string without leading and trailing whitespace characters
+`
+
+var StringTypeJoinFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
+	Parameters: []*Parameter{
+		{
+			Label:      ArgumentLabelNotRequired,
+			Identifier: "strings",
+			TypeAnnotation: NewTypeAnnotation(
+				&VariableSizedType{
+					Type: StringType,
+				},
+			),
+		},
+		{
+			Identifier:     "separator",
+			TypeAnnotation: NewTypeAnnotation(StringType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(StringType),
+}
+
+var StringTypeFromCharactersFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
+	Parameters: []*Parameter{
+		{
+			Label:      ArgumentLabelNotRequired,
+			Identifier: "characters",
+			TypeAnnotation: NewTypeAnnotation(
+				&VariableSizedType{
+					Type: CharacterType,
+				},
+			),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(StringType),
+}
+
+var StringTypeFromUTF8FunctionType = &FunctionType{
+	Purity: FunctionPurityView,
+	Parameters: []*Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "bytes",
+			TypeAnnotation: NewTypeAnnotation(ByteArrayType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(
+		&OptionalType{
+			Type: StringType,
+		},
+	),
+}
@@ -315,6 +315,7 @@ func NewTypeAnnotation(ty Type) *TypeAnnotation {
 const IsInstanceFunctionName = "isInstance"
 
 var IsInstanceFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
 	Parameters: []*Parameter{
 		{
 			Label:      ArgumentLabelNotRequired,
@@ -338,6 +339,7 @@ Returns true if the object conforms to the given type at runtime
 const GetTypeFunctionName = "getType"
 
 var GetTypeFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
 	ReturnTypeAnnotation: NewTypeAnnotation(
 		MetaType,
 	),
@@ -352,6 +354,7 @@ Returns the type of the value
 const ToStringFunctionName = "toString"
 
 var ToStringFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
 	ReturnTypeAnnotation: NewTypeAnnotation(
 		StringType,
 	),
@@ -366,6 +369,7 @@ A textual representation of this object
 const ToBigEndianBytesFunctionName = "toBigEndianBytes"
 
 var toBigEndianBytesFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
 	ReturnTypeAnnotation: NewTypeAnnotation(
 		ByteArrayType,
 	),
@@ -680,24 +684,32 @@ func (t *GenericType) Equal(other Type) bool {
 	return t.TypeParameter == otherType.TypeParameter
 }
 
-func (*GenericType) IsResourceType() bool {
-	return false
+// NOTE: The properties of a generic type are determined by the type bound of its type parameter, if any.
+// For example, a generic type with the type bound `AnyResource` is a resource type,
+// as it can only be instantiated with resource types.
+
+func (t *GenericType) IsResourceType() bool {
+	typeBound := t.TypeParameter.TypeBound
+	return typeBound != nil && typeBound.IsResourceType()
 }
 
 func (*GenericType) IsInvalidType() bool {
 	return false
 }
 
-func (*GenericType) IsStorable(_ map[*Member]bool) bool {
-	return false
+func (t *GenericType) IsStorable(results map[*Member]bool) bool {
+	typeBound := t.TypeParameter.TypeBound
+	return typeBound != nil && typeBound.IsStorable(results)
 }
 
-func (*GenericType) IsExternallyReturnable(_ map[*Member]bool) bool {
-	return false
+func (t *GenericType) IsExternallyReturnable(results map[*Member]bool) bool {
+	typeBound := t.TypeParameter.TypeBound
+	return typeBound != nil && typeBound.IsExternallyReturnable(results)
 }
 
-func (t *GenericType) IsImportable(_ map[*Member]bool) bool {
-	return false
+func (t *GenericType) IsImportable(results map[*Member]bool) bool {
+	typeBound := t.TypeParameter.TypeBound
+	return typeBound != nil && typeBound.IsImportable(results)
 }
 
 func (*GenericType) IsEquatable() bool {
@@ -753,15 +765,29 @@ func (t *GenericType) Unify(
 	return true
 }
 
+// Resolve returns the type argument for the type parameter of the generic type.
+//
+// If the type parameter is not bound, for example because it is a type parameter
+// of an enclosing generic function or composite declaration, the generic type is left as-is.
+// Optional type parameters which are not bound resolve to nil.
+//
 func (t *GenericType) Resolve(typeArguments *TypeParameterTypeOrderedMap) Type {
 	ty, ok := typeArguments.Get(t.TypeParameter)
 	if !ok {
-		return nil
+		if t.TypeParameter.Optional {
+			return nil
+		}
+		return t
 	}
 	return ty
 }
 
 func (t *GenericType) GetMembers() map[string]MemberResolver {
+	// A value of a generic type has the members of the type bound, if any
+	typeBound := t.TypeParameter.TypeBound
+	if typeBound != nil {
+		return typeBound.GetMembers()
+	}
 	return withBuiltinMembers(t, nil)
 }
 
@@ -813,6 +839,7 @@ self / other, saturating at the numeric bounds instead of overflowing.
 func addSaturatingArithmeticFunctions(t SaturatingArithmeticType, members map[string]MemberResolver) {
 
 	arithmeticFunctionType := &FunctionType{
+		Purity: FunctionPurityView,
 		Parameters: []*Parameter{
 			{
 				Label:          ArgumentLabelNotRequired,
@@ -1620,6 +1647,53 @@ It does not modify the original array.
 If either of the parameters are out of the bounds of the array, or the indices are invalid (` + "`from > upTo`" + `), then the function will fail.
 `
 
+const arrayTypeForEachFunctionDocString = `
+Calls the given function for each element of the array, in order.
+
+Available if the array element type is not resource-kinded.
+The array must not be modified while it is iterated
+`
+
+const arrayTypeFilterFunctionDocString = `
+Returns a new variable-sized array containing the elements of the array for which the given function returns true, in order.
+
+Available if the array element type is not resource-kinded.
+It does not modify the original array
+`
+
+const arrayTypeMapFunctionDocString = `
+Returns a new array containing the results of calling the given function for each element of the array, in order.
+
+Available if the array element type is not resource-kinded.
+It does not modify the original array
+`
+
+const arrayTypeReverseFunctionDocString = `
+Returns a new array containing the elements of the array in reverse order.
+
+Available if the array element type is not resource-kinded.
+It does not modify the original array
+`
+
+const arrayTypeSortFunctionDocString = `
+Returns a new array containing the elements of the array, sorted using the given function.
+
+The given function must return true if its first argument should be ordered before its second argument.
+The sort is stable, i.e. elements which are equal keep their relative order.
+
+Available if the array element type is not resource-kinded.
+It does not modify the original array
+`
+
+const arrayTypeReduceFunctionDocString = `
+Returns the result of combining the elements of the array using the given function, starting with the given initial value.
+
+The given function is called for each element of the array, in order,
+with the current result and the element, and returns the next result.
+
+Available if the array element type is not resource-kinded
+`
+
 func getArrayMembers(arrayType ArrayType) map[string]MemberResolver {
 
 	members := map[string]MemberResolver{
@@ -1713,6 +1787,48 @@ func getArrayMembers(arrayType ArrayType) map[string]MemberResolver {
 				)
 			},
 		},
+		"forEach": arrayHigherOrderFunctionMemberResolver(
+			arrayType,
+			func(elementType Type) *FunctionType {
+				return ArrayForEachFunctionType(elementType)
+			},
+			arrayTypeForEachFunctionDocString,
+		),
+		"filter": arrayHigherOrderFunctionMemberResolver(
+			arrayType,
+			func(elementType Type) *FunctionType {
+				return ArrayFilterFunctionType(elementType)
+			},
+			arrayTypeFilterFunctionDocString,
+		),
+		"map": arrayHigherOrderFunctionMemberResolver(
+			arrayType,
+			func(_ Type) *FunctionType {
+				return ArrayMapFunctionType(arrayType)
+			},
+			arrayTypeMapFunctionDocString,
+		),
+		"reverse": arrayHigherOrderFunctionMemberResolver(
+			arrayType,
+			func(_ Type) *FunctionType {
+				return ArrayReverseFunctionType(arrayType)
+			},
+			arrayTypeReverseFunctionDocString,
+		),
+		"sort": arrayHigherOrderFunctionMemberResolver(
+			arrayType,
+			func(_ Type) *FunctionType {
+				return ArraySortFunctionType(arrayType)
+			},
+			arrayTypeSortFunctionDocString,
+		),
+		"reduce": arrayHigherOrderFunctionMemberResolver(
+			arrayType,
+			func(elementType Type) *FunctionType {
+				return ArrayReduceFunctionType(elementType)
+			},
+			arrayTypeReduceFunctionDocString,
+		),
 	}
 
 	// TODO: maybe still return members but report a helpful error?
@@ -1940,6 +2056,7 @@ func ArrayInsertFunctionType(elementType Type) *FunctionType {
 func ArrayConcatFunctionType(arrayType Type) *FunctionType {
 	typeAnnotation := NewTypeAnnotation(arrayType)
 	return &FunctionType{
+		Purity: FunctionPurityView,
 		Parameters: []*Parameter{
 			{
 				Label:          ArgumentLabelNotRequired,
@@ -1953,6 +2070,7 @@ func ArrayConcatFunctionType(arrayType Type) *FunctionType {
 
 func ArrayFirstIndexFunctionType(elementType Type) *FunctionType {
 	return &FunctionType{
+		Purity: FunctionPurityView,
 		Parameters: []*Parameter{
 			{
 				Identifier:     "of",
@@ -1966,6 +2084,7 @@ func ArrayFirstIndexFunctionType(elementType Type) *FunctionType {
 }
 func ArrayContainsFunctionType(elementType Type) *FunctionType {
 	return &FunctionType{
+		Purity: FunctionPurityView,
 		Parameters: []*Parameter{
 			{
 				Label:          ArgumentLabelNotRequired,
@@ -2009,6 +2128,7 @@ func ArrayAppendFunctionType(elementType Type) *FunctionType {
 
 func ArraySliceFunctionType(elementType Type) *FunctionType {
 	return &FunctionType{
+		Purity: FunctionPurityView,
 		Parameters: []*Parameter{
 			{
 				Identifier:     "from",
@@ -2025,6 +2145,238 @@ func ArraySliceFunctionType(elementType Type) *FunctionType {
 	}
 }
 
+// arrayHigherOrderFunctionMemberResolver returns the member resolver for a function of an array
+// which calls a given function for the elements of the array, e.g. `forEach`.
+//
+// The functions are not available for arrays of resources.
+// For such arrays the member has no function type,
+// so invocations only report the invalid member error.
+//
+func arrayHigherOrderFunctionMemberResolver(
+	arrayType ArrayType,
+	functionType func(elementType Type) *FunctionType,
+	docString string,
+) MemberResolver {
+	return MemberResolver{
+		Kind: common.DeclarationKindFunction,
+		Resolve: func(memoryGauge common.MemoryGauge, identifier string, targetRange ast.Range, report func(error)) *Member {
+
+			elementType := arrayType.ElementType(false)
+
+			if elementType.IsResourceType() {
+				report(
+					&InvalidResourceArrayMemberError{
+						Name:            identifier,
+						DeclarationKind: common.DeclarationKindFunction,
+						Range:           targetRange,
+					},
+				)
+
+				return newInvalidFunctionMember(
+					memoryGauge,
+					arrayType,
+					identifier,
+					docString,
+				)
+			}
+
+			return NewPublicFunctionMember(
+				memoryGauge,
+				arrayType,
+				identifier,
+				functionType(elementType),
+				docString,
+			)
+		},
+	}
+}
+
+func ArrayForEachFunctionType(elementType Type) *FunctionType {
+	return &FunctionType{
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "function",
+				TypeAnnotation: NewTypeAnnotation(
+					&FunctionType{
+						Parameters: []*Parameter{
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "element",
+								TypeAnnotation: NewTypeAnnotation(elementType),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(VoidType),
+					},
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(VoidType),
+	}
+}
+
+func ArrayFilterFunctionType(elementType Type) *FunctionType {
+	return &FunctionType{
+		Purity: FunctionPurityView,
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "predicate",
+				TypeAnnotation: NewTypeAnnotation(
+					&FunctionType{
+						Purity: FunctionPurityView,
+						Parameters: []*Parameter{
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "element",
+								TypeAnnotation: NewTypeAnnotation(elementType),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(BoolType),
+					},
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(
+			&VariableSizedType{
+				Type: elementType,
+			},
+		),
+	}
+}
+
+func ArrayMapFunctionType(arrayType ArrayType) *FunctionType {
+	typeParameter := &TypeParameter{
+		Name: "T",
+	}
+
+	resultElementType := &GenericType{
+		TypeParameter: typeParameter,
+	}
+
+	// The result of mapping a constant-sized array has the same size
+
+	var resultType ArrayType
+	if constantSizedType, ok := arrayType.(*ConstantSizedType); ok {
+		resultType = &ConstantSizedType{
+			Type: resultElementType,
+			Size: constantSizedType.Size,
+		}
+	} else {
+		resultType = &VariableSizedType{
+			Type: resultElementType,
+		}
+	}
+
+	return &FunctionType{
+		TypeParameters: []*TypeParameter{
+			typeParameter,
+		},
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "transform",
+				TypeAnnotation: NewTypeAnnotation(
+					&FunctionType{
+						Parameters: []*Parameter{
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "element",
+								TypeAnnotation: NewTypeAnnotation(arrayType.ElementType(false)),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(resultElementType),
+					},
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(resultType),
+	}
+}
+
+func ArrayReverseFunctionType(arrayType ArrayType) *FunctionType {
+	return &FunctionType{
+		Purity:               FunctionPurityView,
+		ReturnTypeAnnotation: NewTypeAnnotation(arrayType),
+	}
+}
+
+func ArraySortFunctionType(arrayType ArrayType) *FunctionType {
+	elementType := arrayType.ElementType(false)
+
+	return &FunctionType{
+		Purity: FunctionPurityView,
+		Parameters: []*Parameter{
+			{
+				Identifier: "by",
+				TypeAnnotation: NewTypeAnnotation(
+					&FunctionType{
+						Purity: FunctionPurityView,
+						Parameters: []*Parameter{
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "a",
+								TypeAnnotation: NewTypeAnnotation(elementType),
+							},
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "b",
+								TypeAnnotation: NewTypeAnnotation(elementType),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(BoolType),
+					},
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(arrayType),
+	}
+}
+
+func ArrayReduceFunctionType(elementType Type) *FunctionType {
+	typeParameter := &TypeParameter{
+		Name: "T",
+	}
+
+	resultType := &GenericType{
+		TypeParameter: typeParameter,
+	}
+
+	return &FunctionType{
+		TypeParameters: []*TypeParameter{
+			typeParameter,
+		},
+		Parameters: []*Parameter{
+			{
+				Identifier:     "initial",
+				TypeAnnotation: NewTypeAnnotation(resultType),
+			},
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "combine",
+				TypeAnnotation: NewTypeAnnotation(
+					&FunctionType{
+						Parameters: []*Parameter{
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "result",
+								TypeAnnotation: NewTypeAnnotation(resultType),
+							},
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "element",
+								TypeAnnotation: NewTypeAnnotation(elementType),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(resultType),
+					},
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(resultType),
+	}
+}
+
 // VariableSizedType is a variable sized array type
 type VariableSizedType struct {
 	Type                Type
@@ -2440,6 +2792,7 @@ func (p TypeParameter) checkTypeBound(ty Type, typeRange ast.Range) error {
 
 func formatFunctionType(
 	spaces bool,
+	purity FunctionPurity,
 	typeParameters []string,
 	parameters []string,
 	returnTypeAnnotation string,
@@ -2448,6 +2801,11 @@ func formatFunctionType(
 	var builder strings.Builder
 	builder.WriteRune('(')
 
+	if purity == FunctionPurityView {
+		builder.WriteString(purity.Keyword())
+		builder.WriteRune(' ')
+	}
+
 	if len(typeParameters) > 0 {
 		builder.WriteRune('<')
 		for i, typeParameter := range typeParameters {
@@ -2482,7 +2840,30 @@ func formatFunctionType(
 
 // FunctionType
 //
+// FunctionPurity is the purity of a function type.
+// View functions cannot have side effects, i.e. they cannot mutate state
+// which is not local to the function, and they can only call other view functions
+//
+type FunctionPurity int
+
+const (
+	FunctionPurityImpure FunctionPurity = iota
+	FunctionPurityView
+)
+
+func (p FunctionPurity) Keyword() string {
+	switch p {
+	case FunctionPurityImpure:
+		return ""
+	case FunctionPurityView:
+		return "view"
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
 type FunctionType struct {
+	Purity                   FunctionPurity
 	IsConstructor            bool
 	TypeParameters           []*TypeParameter
 	Parameters               []*Parameter
@@ -2531,6 +2912,7 @@ func (t *FunctionType) String() string {
 
 	return formatFunctionType(
 		true,
+		t.Purity,
 		typeParameters,
 		parameters,
 		returnTypeAnnotation,
@@ -2555,6 +2937,7 @@ func (t *FunctionType) QualifiedString() string {
 
 	return formatFunctionType(
 		true,
+		t.Purity,
 		typeParameters,
 		parameters,
 		returnTypeAnnotation,
@@ -2581,6 +2964,7 @@ func (t *FunctionType) ID() TypeID {
 	return TypeID(
 		formatFunctionType(
 			false,
+			t.Purity,
 			typeParameters,
 			parameters,
 			returnTypeAnnotation,
@@ -2608,6 +2992,12 @@ func (t *FunctionType) Equal(other Type) bool {
 		}
 	}
 
+	// purity
+
+	if t.Purity != otherFunction.Purity {
+		return false
+	}
+
 	// parameters
 
 	if len(t.Parameters) != len(otherFunction.Parameters) {
@@ -2793,6 +3183,7 @@ func (t *FunctionType) RewriteWithRestrictedTypes() (Type, bool) {
 		}
 
 		return &FunctionType{
+			Purity:                t.Purity,
 			TypeParameters:        rewrittenTypeParameters,
 			Parameters:            rewrittenParameters,
 			ReturnTypeAnnotation:  NewTypeAnnotation(rewrittenReturnType),
@@ -2875,7 +3266,9 @@ func (t *FunctionType) Unify(
 
 func (t *FunctionType) Resolve(typeArguments *TypeParameterTypeOrderedMap) Type {
 
-	// TODO: type parameters ?
+	// NOTE: the type parameters of the function type are kept as-is,
+	// as they may be referred to by the parameter and return types,
+	// e.g. for a generic function of an instantiated generic composite type
 
 	// parameters
 
@@ -2904,6 +3297,8 @@ func (t *FunctionType) Resolve(typeArguments *TypeParameterTypeOrderedMap) Type
 	}
 
 	return &FunctionType{
+		Purity:                t.Purity,
+		TypeParameters:        t.TypeParameters,
 		Parameters:            newParameters,
 		ReturnTypeAnnotation:  NewTypeAnnotation(newReturnType),
 		RequiredArgumentCount: t.RequiredArgumentCount,
@@ -3186,6 +3581,7 @@ func init() {
 
 func NumberConversionFunctionType(numberType Type) *FunctionType {
 	return &FunctionType{
+		Purity: FunctionPurityView,
 		Parameters: []*Parameter{
 			{
 				Label:          ArgumentLabelNotRequired,
@@ -3220,6 +3616,7 @@ func baseFunctionVariable(name string, ty *FunctionType, docString string) *Vari
 }
 
 var AddressConversionFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
 	Parameters: []*Parameter{
 		{
 			Label:          ArgumentLabelNotRequired,
@@ -3304,6 +3701,7 @@ func init() {
 	}
 
 	functionType := &FunctionType{
+		Purity:               FunctionPurityView,
 		ReturnTypeAnnotation: NewTypeAnnotation(StringType),
 	}
 
@@ -3326,6 +3724,27 @@ func init() {
 		StringTypeEncodeHexFunctionDocString,
 	))
 
+	addMember(NewUnmeteredPublicFunctionMember(
+		functionType,
+		StringTypeJoinFunctionName,
+		StringTypeJoinFunctionType,
+		StringTypeJoinFunctionDocString,
+	))
+
+	addMember(NewUnmeteredPublicFunctionMember(
+		functionType,
+		StringTypeFromCharactersFunctionName,
+		StringTypeFromCharactersFunctionType,
+		StringTypeFromCharactersFunctionDocString,
+	))
+
+	addMember(NewUnmeteredPublicFunctionMember(
+		functionType,
+		StringTypeFromUTF8FunctionName,
+		StringTypeFromUTF8FunctionType,
+		StringTypeFromUTF8FunctionDocString,
+	))
+
 	BaseValueActivation.Set(
 		typeName,
 		baseFunctionVariable(
@@ -3337,6 +3756,7 @@ func init() {
 }
 
 var StringTypeEncodeHexFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
 	Parameters: []*Parameter{
 		{
 			Label:      ArgumentLabelNotRequired,
@@ -3482,6 +3902,7 @@ func suggestFixedPointLiteralConversionReplacement(
 
 func pathConversionFunctionType(pathType Type) *FunctionType {
 	return &FunctionType{
+		Purity: FunctionPurityView,
 		Parameters: []*Parameter{
 			{
 				Identifier:     "identifier",
@@ -3517,6 +3938,7 @@ func init() {
 		baseFunctionVariable(
 			typeName,
 			&FunctionType{
+				Purity:               FunctionPurityView,
 				TypeParameters:       []*TypeParameter{{Name: "T"}},
 				ReturnTypeAnnotation: NewTypeAnnotation(MetaType),
 			},
@@ -3582,8 +4004,12 @@ type CompositeType struct {
 	memberResolvers                     map[string]MemberResolver
 	memberResolversOnce                 sync.Once
 	Fields                              []string
+	// RemovedFields are the names of the fields which were removed
+	// from the type using the `#removedField` pragma
+	RemovedFields []string
 	// TODO: add support for overloaded initializers
 	ConstructorParameters []*Parameter
+	ConstructorPurity     FunctionPurity
 	nestedTypes           *StringTypeOrderedMap
 	containerType         Type
 	EnumRawType           Type
@@ -3592,6 +4018,17 @@ type CompositeType struct {
 	// Only applicable for native composite types.
 	importable bool
 
+	// typeParameters are the type parameters of a generic composite type
+	typeParameters []*TypeParameter
+	// genericType is the generic composite type this composite type is an instantiation of.
+	// It is nil for composite types which are not instantiations.
+	genericType *CompositeType
+	// typeArguments are the type arguments of an instantiation of a generic composite type
+	typeArguments []Type
+	// The members and constructor parameters of an instantiation are resolved lazily,
+	// as the member types may refer to further instantiations, e.g. `fun wrap(): Box<Box<T>>`
+	instantiatedMembersOnce sync.Once
+
 	cachedIdentifiers *struct {
 		TypeID              TypeID
 		QualifiedIdentifier string
@@ -3599,6 +4036,8 @@ type CompositeType struct {
 	cachedIdentifiersLock sync.RWMutex
 }
 
+var _ ParameterizedType = &CompositeType{}
+
 func (t *CompositeType) Tag() TypeTag {
 	return CompositeTypeTag
 }
@@ -3628,11 +4067,35 @@ func (t *CompositeType) addImplicitTypeRequirementConformance(typeRequirement *C
 func (*CompositeType) IsType() {}
 
 func (t *CompositeType) String() string {
-	return t.Identifier
+	if t.genericType == nil {
+		return t.Identifier
+	}
+	return formatInstantiation(t.Identifier, t.typeArguments, func(ty Type) string {
+		return ty.String()
+	})
 }
 
 func (t *CompositeType) QualifiedString() string {
-	return t.QualifiedIdentifier()
+	if t.genericType == nil {
+		return t.QualifiedIdentifier()
+	}
+	return formatInstantiation(t.QualifiedIdentifier(), t.typeArguments, func(ty Type) string {
+		return ty.QualifiedString()
+	})
+}
+
+func formatInstantiation(identifier string, typeArguments []Type, typeFormatter func(Type) string) string {
+	var builder strings.Builder
+	builder.WriteString(identifier)
+	builder.WriteRune('<')
+	for i, typeArgument := range typeArguments {
+		if i > 0 {
+			builder.WriteRune(',')
+		}
+		builder.WriteString(typeFormatter(typeArgument))
+	}
+	builder.WriteRune('>')
+	return builder.String()
 }
 
 func (t *CompositeType) GetContainerType() Type {
@@ -3701,6 +4164,16 @@ func (t *CompositeType) initializeIdentifiers() {
 		typeID = t.Location.TypeID(nil, identifier)
 	}
 
+	// The type ID of an instantiation of a generic composite type
+	// includes the type IDs of the type arguments, e.g. `S.test.Box<Int>`.
+	// The qualified identifier is the one of the generic composite type
+
+	if t.genericType != nil {
+		typeID = TypeID(formatInstantiation(string(typeID), t.typeArguments, func(ty Type) string {
+			return string(ty.ID())
+		}))
+	}
+
 	t.cachedIdentifiers = &struct {
 		TypeID              TypeID
 		QualifiedIdentifier string
@@ -3716,12 +4189,219 @@ func (t *CompositeType) Equal(other Type) bool {
 		return false
 	}
 
-	return otherStructure.Kind == t.Kind &&
-		otherStructure.ID() == t.ID()
-}
+	if otherStructure.Kind != t.Kind {
+		return false
+	}
 
-func (t *CompositeType) GetMembers() map[string]MemberResolver {
-	t.initializeMemberResolvers()
+	// Instantiations of generic composite types are equal
+	// if they instantiate the same generic composite type with equal type arguments.
+	// NOTE: the type arguments are compared using Equal, not by their type ID,
+	// as the type ID of a generic type is not unique
+
+	if t.genericType != nil || otherStructure.genericType != nil {
+		if t.genericType == nil ||
+			otherStructure.genericType == nil ||
+			!t.genericType.Equal(otherStructure.genericType) {
+
+			return false
+		}
+
+		for i, typeArgument := range t.typeArguments {
+			if !typeArgument.Equal(otherStructure.typeArguments[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return otherStructure.ID() == t.ID()
+}
+
+// TypeParameters returns the type parameters of a generic composite type,
+// and of an instantiation of it
+//
+func (t *CompositeType) TypeParameters() []*TypeParameter {
+	return t.typeParameters
+}
+
+// SetTypeParameters sets the type parameters of a generic composite type.
+// It must be called before the type is instantiated.
+//
+func (t *CompositeType) SetTypeParameters(typeParameters []*TypeParameter) {
+	t.typeParameters = typeParameters
+}
+
+// IsGeneric returns true if the composite type is a generic composite type
+// which is not instantiated
+//
+func (t *CompositeType) IsGeneric() bool {
+	return len(t.typeParameters) > 0 && t.genericType == nil
+}
+
+// BaseType returns the generic composite type of an instantiation,
+// and nil for all other composite types
+//
+func (t *CompositeType) BaseType() Type {
+	if t.genericType == nil {
+		return nil
+	}
+	return t.genericType
+}
+
+// GenericType returns the generic composite type of an instantiation,
+// and nil for all other composite types
+//
+func (t *CompositeType) GenericType() *CompositeType {
+	return t.genericType
+}
+
+// TypeArguments returns the type arguments of an instantiation
+// of a generic composite type, and nil for all other composite types
+//
+func (t *CompositeType) TypeArguments() []Type {
+	return t.typeArguments
+}
+
+// effectiveTypeArguments returns the type arguments of an instantiation.
+// Within its own declaration, a generic composite type is an instantiation
+// with its own type parameters, e.g. `Box` is `Box<T>`.
+//
+func (t *CompositeType) effectiveTypeArguments() []Type {
+	if t.genericType != nil {
+		return t.typeArguments
+	}
+
+	typeArguments := make([]Type, len(t.typeParameters))
+	for i, typeParameter := range t.typeParameters {
+		typeArguments[i] = &GenericType{
+			TypeParameter: typeParameter,
+		}
+	}
+	return typeArguments
+}
+
+// Instantiate returns the instantiation of the generic composite type with the given type arguments.
+// The number of type arguments must match the number of type parameters,
+// and the type arguments must satisfy the type bounds of the type parameters.
+//
+func (t *CompositeType) Instantiate(typeArguments []Type, _ func(err error)) Type {
+	genericType := t
+	if t.genericType != nil {
+		genericType = t.genericType
+	}
+
+	if len(typeArguments) != len(genericType.typeParameters) {
+		panic(errors.NewUnreachableError())
+	}
+
+	// Instantiating a generic composite type with its own type parameters
+	// results in the generic composite type itself
+
+	isGenericType := true
+	for i, typeArgument := range typeArguments {
+		genericTypeArgument, ok := typeArgument.(*GenericType)
+		if !ok || genericTypeArgument.TypeParameter != genericType.typeParameters[i] {
+			isGenericType = false
+			break
+		}
+	}
+	if isGenericType {
+		return genericType
+	}
+
+	return &CompositeType{
+		Location:                            genericType.Location,
+		Identifier:                          genericType.Identifier,
+		Kind:                                genericType.Kind,
+		ExplicitInterfaceConformances:       genericType.ExplicitInterfaceConformances,
+		ImplicitTypeRequirementConformances: genericType.ImplicitTypeRequirementConformances,
+		Fields:                              genericType.Fields,
+		RemovedFields:                       genericType.RemovedFields,
+		nestedTypes:                         genericType.nestedTypes,
+		containerType:                       genericType.containerType,
+		hasComputedMembers:                  genericType.hasComputedMembers,
+		importable:                          genericType.importable,
+		typeParameters:                      genericType.typeParameters,
+		genericType:                         genericType,
+		typeArguments:                       typeArguments,
+	}
+}
+
+// typeArgumentsMap returns the type arguments of the instantiation,
+// keyed by the type parameters of the generic composite type
+//
+func (t *CompositeType) typeArgumentsMap() *TypeParameterTypeOrderedMap {
+	typeArguments := NewTypeParameterTypeOrderedMap()
+	for i, typeParameter := range t.typeParameters {
+		typeArguments.Set(typeParameter, t.typeArguments[i])
+	}
+	return typeArguments
+}
+
+func (t *CompositeType) initializeInstantiatedMembers() {
+	if t.genericType == nil {
+		return
+	}
+
+	t.instantiatedMembersOnce.Do(func() {
+		typeArguments := t.typeArgumentsMap()
+
+		resolveTypeAnnotation := func(typeAnnotation *TypeAnnotation) *TypeAnnotation {
+			resolvedType := typeAnnotation.Type.Resolve(typeArguments)
+			if resolvedType == nil {
+				resolvedType = InvalidType
+			}
+			return &TypeAnnotation{
+				IsResource: typeAnnotation.IsResource,
+				Type:       resolvedType,
+			}
+		}
+
+		members := NewStringMemberOrderedMap()
+		t.genericType.Members.Foreach(func(name string, member *Member) {
+			instantiatedMember := *member
+			instantiatedMember.ContainerType = t
+			instantiatedMember.TypeAnnotation = resolveTypeAnnotation(member.TypeAnnotation)
+			members.Set(name, &instantiatedMember)
+		})
+		t.Members = members
+
+		constructorParameters := make([]*Parameter, len(t.genericType.ConstructorParameters))
+		for i, parameter := range t.genericType.ConstructorParameters {
+			constructorParameters[i] = &Parameter{
+				Label:          parameter.Label,
+				Identifier:     parameter.Identifier,
+				TypeAnnotation: resolveTypeAnnotation(parameter.TypeAnnotation),
+			}
+		}
+		t.ConstructorParameters = constructorParameters
+		t.ConstructorPurity = t.genericType.ConstructorPurity
+	})
+}
+
+// ResolvedMembers returns the members of the composite type.
+//
+// The members of an instantiation of a generic composite type are resolved lazily,
+// so code which may encounter instantiations must use this function
+// instead of accessing the field Members directly.
+//
+func (t *CompositeType) ResolvedMembers() *StringMemberOrderedMap {
+	t.initializeInstantiatedMembers()
+	return t.Members
+}
+
+// ResolvedConstructorParameters returns the constructor parameters of the composite type.
+//
+// See ResolvedMembers
+//
+func (t *CompositeType) ResolvedConstructorParameters() []*Parameter {
+	t.initializeInstantiatedMembers()
+	return t.ConstructorParameters
+}
+
+func (t *CompositeType) GetMembers() map[string]MemberResolver {
+	t.initializeMemberResolvers()
 	return t.memberResolvers
 }
 
@@ -3734,6 +4414,8 @@ func (*CompositeType) IsInvalidType() bool {
 }
 
 func (t *CompositeType) IsStorable(results map[*Member]bool) bool {
+	t.initializeInstantiatedMembers()
+
 	if t.hasComputedMembers {
 		return false
 	}
@@ -3767,6 +4449,8 @@ func (t *CompositeType) IsStorable(results map[*Member]bool) bool {
 }
 
 func (t *CompositeType) IsImportable(results map[*Member]bool) bool {
+	t.initializeInstantiatedMembers()
+
 	// Use the pre-determined flag for native types
 	if t.Location == nil {
 		return t.importable
@@ -3795,6 +4479,8 @@ func (t *CompositeType) IsImportable(results map[*Member]bool) bool {
 }
 
 func (t *CompositeType) IsExternallyReturnable(results map[*Member]bool) bool {
+	t.initializeInstantiatedMembers()
+
 	// Only structures, resources, and enums can be stored
 
 	switch t.Kind {
@@ -3832,6 +4518,8 @@ func (t *CompositeType) RewriteWithRestrictedTypes() (result Type, rewritten boo
 }
 
 func (t *CompositeType) InterfaceType() *InterfaceType {
+	t.initializeInstantiatedMembers()
+
 	return &InterfaceType{
 		Location:              t.Location,
 		Identifier:            t.Identifier,
@@ -3839,6 +4527,7 @@ func (t *CompositeType) InterfaceType() *InterfaceType {
 		Members:               t.Members,
 		Fields:                t.Fields,
 		InitializerParameters: t.ConstructorParameters,
+		InitializerPurity:     t.ConstructorPurity,
 		containerType:         t.containerType,
 		nestedTypes:           t.nestedTypes,
 	}
@@ -3867,13 +4556,65 @@ func (t *CompositeType) TypeRequirements() []*CompositeType {
 	return typeRequirements
 }
 
-func (*CompositeType) Unify(_ Type, _ *TypeParameterTypeOrderedMap, _ func(err error), _ ast.Range) bool {
-	// TODO:
-	return false
+func (t *CompositeType) Unify(
+	other Type,
+	typeParameters *TypeParameterTypeOrderedMap,
+	report func(err error),
+	outerRange ast.Range,
+) bool {
+	if len(t.typeParameters) == 0 {
+		return false
+	}
+
+	otherComposite, ok := other.(*CompositeType)
+	if !ok || len(otherComposite.typeParameters) == 0 {
+		return false
+	}
+
+	genericType := t.genericType
+	if genericType == nil {
+		genericType = t
+	}
+
+	otherGenericType := otherComposite.genericType
+	if otherGenericType == nil {
+		otherGenericType = otherComposite
+	}
+
+	if !genericType.Equal(otherGenericType) {
+		return false
+	}
+
+	otherTypeArguments := otherComposite.effectiveTypeArguments()
+
+	result := false
+
+	for i, typeArgument := range t.effectiveTypeArguments() {
+		if typeArgument.Unify(otherTypeArguments[i], typeParameters, report, outerRange) {
+			result = true
+		}
+	}
+
+	return result
 }
 
-func (t *CompositeType) Resolve(_ *TypeParameterTypeOrderedMap) Type {
-	return t
+func (t *CompositeType) Resolve(typeArguments *TypeParameterTypeOrderedMap) Type {
+	if len(t.typeParameters) == 0 {
+		return t
+	}
+
+	currentTypeArguments := t.effectiveTypeArguments()
+	newTypeArguments := make([]Type, len(currentTypeArguments))
+
+	for i, typeArgument := range currentTypeArguments {
+		newTypeArgument := typeArgument.Resolve(typeArguments)
+		if newTypeArgument == nil {
+			return nil
+		}
+		newTypeArguments[i] = newTypeArgument
+	}
+
+	return t.Instantiate(newTypeArguments, nil)
 }
 
 func (t *CompositeType) IsContainerType() bool {
@@ -3886,6 +4627,8 @@ func (t *CompositeType) GetNestedTypes() *StringTypeOrderedMap {
 
 func (t *CompositeType) initializeMemberResolvers() {
 	t.memberResolversOnce.Do(func() {
+		t.initializeInstantiatedMembers()
+
 		members := make(map[string]MemberResolver, t.Members.Len())
 
 		t.Members.Foreach(func(name string, loopMember *Member) {
@@ -3989,6 +4732,30 @@ func NewPublicFunctionMember(
 	}
 }
 
+// newInvalidFunctionMember returns a function member which has an invalid type,
+// for a function which is not available, e.g. because of the type of the container
+//
+func newInvalidFunctionMember(
+	memoryGauge common.MemoryGauge,
+	containerType Type,
+	identifier string,
+	docString string,
+) *Member {
+	return &Member{
+		ContainerType: containerType,
+		Access:        ast.AccessPublic,
+		Identifier: ast.NewIdentifier(
+			memoryGauge,
+			identifier,
+			ast.EmptyPosition,
+		),
+		DeclarationKind: common.DeclarationKindFunction,
+		VariableKind:    ast.VariableKindConstant,
+		TypeAnnotation:  NewTypeAnnotation(InvalidType),
+		DocString:       docString,
+	}
+}
+
 func NewUnmeteredPublicConstantFieldMember(
 	containerType Type,
 	identifier string,
@@ -4111,6 +4878,7 @@ type InterfaceType struct {
 	Fields              []string
 	// TODO: add support for overloaded initializers
 	InitializerParameters []*Parameter
+	InitializerPurity     FunctionPurity
 	containerType         Type
 	nestedTypes           *StringTypeOrderedMap
 	cachedIdentifiers     *struct {
@@ -4485,6 +5253,26 @@ Removes the value for the given key from the dictionary.
 Returns the value as an optional if the dictionary contained the key, or nil if the dictionary did not contain the key
 `
 
+const dictionaryTypeForEachKeyFunctionDocString = `
+Calls the given function for each key of the dictionary, until the function returns false.
+
+The dictionary must not be modified while it is iterated
+`
+
+const dictionaryTypeFilterFunctionDocString = `
+Returns a new dictionary containing the entries of the dictionary for which the given function returns true.
+
+Available if the dictionary value type is not resource-kinded.
+It does not modify the original dictionary
+`
+
+const dictionaryTypeMapFunctionDocString = `
+Returns a new dictionary with the same keys, and the results of calling the given function for each entry of the dictionary as values.
+
+Available if the dictionary value type is not resource-kinded.
+It does not modify the original dictionary
+`
+
 func (t *DictionaryType) GetMembers() map[string]MemberResolver {
 	t.initializeMemberResolvers()
 	return t.memberResolvers
@@ -4593,12 +5381,94 @@ func (t *DictionaryType) initializeMemberResolvers() {
 					)
 				},
 			},
+			"forEachKey": {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(memoryGauge common.MemoryGauge, identifier string, targetRange ast.Range, report func(error)) *Member {
+
+					return NewPublicFunctionMember(
+						memoryGauge,
+						t,
+						identifier,
+						DictionaryForEachKeyFunctionType(t),
+						dictionaryTypeForEachKeyFunctionDocString,
+					)
+				},
+			},
+			"filter": {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(memoryGauge common.MemoryGauge, identifier string, targetRange ast.Range, report func(error)) *Member {
+
+					// The function is not available for dictionaries of resources.
+					// The member has no function type,
+					// so invocations only report the invalid member error
+
+					if t.ValueType.IsResourceType() {
+						report(
+							&InvalidResourceDictionaryMemberError{
+								Name:            identifier,
+								DeclarationKind: common.DeclarationKindFunction,
+								Range:           targetRange,
+							},
+						)
+
+						return newInvalidFunctionMember(
+							memoryGauge,
+							t,
+							identifier,
+							dictionaryTypeFilterFunctionDocString,
+						)
+					}
+
+					return NewPublicFunctionMember(
+						memoryGauge,
+						t,
+						identifier,
+						DictionaryFilterFunctionType(t),
+						dictionaryTypeFilterFunctionDocString,
+					)
+				},
+			},
+			"map": {
+				Kind: common.DeclarationKindFunction,
+				Resolve: func(memoryGauge common.MemoryGauge, identifier string, targetRange ast.Range, report func(error)) *Member {
+
+					// The function is not available for dictionaries of resources.
+					// The member has no function type,
+					// so invocations only report the invalid member error
+
+					if t.ValueType.IsResourceType() {
+						report(
+							&InvalidResourceDictionaryMemberError{
+								Name:            identifier,
+								DeclarationKind: common.DeclarationKindFunction,
+								Range:           targetRange,
+							},
+						)
+
+						return newInvalidFunctionMember(
+							memoryGauge,
+							t,
+							identifier,
+							dictionaryTypeMapFunctionDocString,
+						)
+					}
+
+					return NewPublicFunctionMember(
+						memoryGauge,
+						t,
+						identifier,
+						DictionaryMapFunctionType(t),
+						dictionaryTypeMapFunctionDocString,
+					)
+				},
+			},
 		})
 	})
 }
 
 func DictionaryContainsKeyFunctionType(t *DictionaryType) *FunctionType {
 	return &FunctionType{
+		Purity: FunctionPurityView,
 		Parameters: []*Parameter{
 			{
 				Label:          ArgumentLabelNotRequired,
@@ -4649,6 +5519,106 @@ func DictionaryRemoveFunctionType(t *DictionaryType) *FunctionType {
 	}
 }
 
+func DictionaryForEachKeyFunctionType(t *DictionaryType) *FunctionType {
+	return &FunctionType{
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "function",
+				TypeAnnotation: NewTypeAnnotation(
+					&FunctionType{
+						Parameters: []*Parameter{
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "key",
+								TypeAnnotation: NewTypeAnnotation(t.KeyType),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(BoolType),
+					},
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(VoidType),
+	}
+}
+
+func DictionaryFilterFunctionType(t *DictionaryType) *FunctionType {
+	return &FunctionType{
+		Purity: FunctionPurityView,
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "predicate",
+				TypeAnnotation: NewTypeAnnotation(
+					&FunctionType{
+						Purity: FunctionPurityView,
+						Parameters: []*Parameter{
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "key",
+								TypeAnnotation: NewTypeAnnotation(t.KeyType),
+							},
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "value",
+								TypeAnnotation: NewTypeAnnotation(t.ValueType),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(BoolType),
+					},
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(t),
+	}
+}
+
+func DictionaryMapFunctionType(t *DictionaryType) *FunctionType {
+	typeParameter := &TypeParameter{
+		Name: "T",
+	}
+
+	resultValueType := &GenericType{
+		TypeParameter: typeParameter,
+	}
+
+	return &FunctionType{
+		TypeParameters: []*TypeParameter{
+			typeParameter,
+		},
+		Parameters: []*Parameter{
+			{
+				Label:      ArgumentLabelNotRequired,
+				Identifier: "transform",
+				TypeAnnotation: NewTypeAnnotation(
+					&FunctionType{
+						Parameters: []*Parameter{
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "key",
+								TypeAnnotation: NewTypeAnnotation(t.KeyType),
+							},
+							{
+								Label:          ArgumentLabelNotRequired,
+								Identifier:     "value",
+								TypeAnnotation: NewTypeAnnotation(t.ValueType),
+							},
+						},
+						ReturnTypeAnnotation: NewTypeAnnotation(resultValueType),
+					},
+				),
+			},
+		},
+		ReturnTypeAnnotation: NewTypeAnnotation(
+			&DictionaryType{
+				KeyType:   t.KeyType,
+				ValueType: resultValueType,
+			},
+		),
+	}
+}
+
 func (*DictionaryType) isValueIndexableType() bool {
 	return true
 }
@@ -4851,9 +5821,16 @@ func (*ReferenceType) Unify(_ Type, _ *TypeParameterTypeOrderedMap, _ func(err e
 	return false
 }
 
-func (t *ReferenceType) Resolve(_ *TypeParameterTypeOrderedMap) Type {
-	// TODO:
-	return t
+func (t *ReferenceType) Resolve(typeArguments *TypeParameterTypeOrderedMap) Type {
+	newInnerType := t.Type.Resolve(typeArguments)
+	if newInnerType == nil {
+		return nil
+	}
+
+	return &ReferenceType{
+		Authorized: t.Authorized,
+		Type:       newInnerType,
+	}
 }
 
 const AddressTypeName = "Address"
@@ -4944,6 +5921,7 @@ func (t *AddressType) Resolve(_ *TypeParameterTypeOrderedMap) Type {
 const AddressTypeToBytesFunctionName = `toBytes`
 
 var AddressTypeToBytesFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
 	ReturnTypeAnnotation: NewTypeAnnotation(
 		ByteArrayType,
 	),
@@ -5043,6 +6021,15 @@ func checkSubTypeWithoutEquality(subType Type, superType Type) bool {
 		return true
 	}
 
+	// A generic type `T` is a subtype of a type `V`
+	// if the type bound of `T` is a subtype of `V`
+
+	if typedSubType, ok := subType.(*GenericType); ok {
+		if typeBound := typedSubType.TypeParameter.TypeBound; typeBound != nil {
+			return IsSubType(typeBound, superType)
+		}
+	}
+
 	switch superType {
 	case AnyType:
 		return true
@@ -5351,6 +6338,15 @@ func checkSubTypeWithoutEquality(subType Type, superType Type) bool {
 			return false
 		}
 
+		// View functions are subtypes of impure functions,
+		// but impure functions are not subtypes of view functions
+
+		if typedSuperType.Purity == FunctionPurityView &&
+			typedSubType.Purity != FunctionPurityView {
+
+			return false
+		}
+
 		if len(typedSubType.Parameters) != len(typedSuperType.Parameters) {
 			return false
 		}
@@ -6026,9 +7022,16 @@ func (*RestrictedType) Unify(_ Type, _ *TypeParameterTypeOrderedMap, _ func(err
 	return false
 }
 
-func (t *RestrictedType) Resolve(_ *TypeParameterTypeOrderedMap) Type {
-	// TODO:
-	return t
+func (t *RestrictedType) Resolve(typeArguments *TypeParameterTypeOrderedMap) Type {
+	newType := t.Type.Resolve(typeArguments)
+	if newType == nil {
+		return nil
+	}
+
+	return &RestrictedType{
+		Type:         newType,
+		Restrictions: t.Restrictions,
+	}
 }
 
 // CapabilityType
@@ -6226,6 +7229,7 @@ func CapabilityTypeBorrowFunctionType(borrowType Type) *FunctionType {
 	}
 
 	return &FunctionType{
+		Purity:         FunctionPurityView,
 		TypeParameters: typeParameters,
 		ReturnTypeAnnotation: NewTypeAnnotation(
 			&OptionalType{
@@ -6246,6 +7250,7 @@ func CapabilityTypeCheckFunctionType(borrowType Type) *FunctionType {
 	}
 
 	return &FunctionType{
+		Purity:               FunctionPurityView,
 		TypeParameters:       typeParameters,
 		ReturnTypeAnnotation: NewTypeAnnotation(BoolType),
 	}
@@ -6466,6 +7471,7 @@ var PublicKeyArrayType = &VariableSizedType{
 }
 
 var PublicKeyVerifyFunctionType = &FunctionType{
+	Purity:         FunctionPurityView,
 	TypeParameters: []*TypeParameter{},
 	Parameters: []*Parameter{
 		{
@@ -6493,6 +7499,7 @@ var PublicKeyVerifyFunctionType = &FunctionType{
 }
 
 var PublicKeyVerifyPoPFunctionType = &FunctionType{
+	Purity:         FunctionPurityView,
 	TypeParameters: []*TypeParameter{},
 	Parameters: []*Parameter{
 		{
@@ -113,6 +113,7 @@ func cryptoAlgorithmEnumConstructorType(
 	}
 
 	constructorType := &sema.FunctionType{
+		Purity:        sema.FunctionPurityView,
 		IsConstructor: true,
 		Parameters: []*sema.Parameter{
 			{
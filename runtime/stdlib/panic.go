@@ -42,6 +42,7 @@ Terminates the program unconditionally and reports a message which explains why
 var PanicFunction = NewStandardLibraryFunction(
 	"panic",
 	&sema.FunctionType{
+		Purity: sema.FunctionPurityView,
 		Parameters: []*sema.Parameter{
 			{
 				Label:          sema.ArgumentLabelNotRequired,
@@ -0,0 +1,857 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package stdlib
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/parser2"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/stdlib/contracts"
+)
+
+// TestContractLocation is the location of the Test standard library contract,
+// which is imported by test programs using `import Test`
+//
+const TestContractLocation = common.IdentifierLocation("Test")
+
+// TestFramework provides the host functionality of the Test contract
+//
+type TestFramework interface {
+	// NewEmulatorBackend returns a new, empty blockchain backend
+	NewEmulatorBackend() TestBlockchainBackend
+	// ReadFile returns the content of the file at the given path
+	ReadFile(path string) (string, error)
+}
+
+// TestBlockchainBackend executes scripts and transactions on behalf of a `Test.Blockchain`.
+//
+// Arguments are given and results are returned as values of the interpreter of the test program
+//
+type TestBlockchainBackend interface {
+	RunScript(inter *interpreter.Interpreter, code string, arguments []interpreter.Value) TestScriptResult
+	CreateAccount() (common.Address, error)
+	RunTransaction(
+		inter *interpreter.Interpreter,
+		code string,
+		authorizers []common.Address,
+		arguments []interpreter.Value,
+	) error
+	DeployContract(
+		inter *interpreter.Interpreter,
+		name string,
+		code string,
+		account common.Address,
+		arguments []interpreter.Value,
+	) error
+	// Events returns all events emitted so far, in emission order
+	Events(inter *interpreter.Interpreter) []TestEvent
+}
+
+// TestScriptResult is the result of a script execution.
+// Value is nil if the script failed
+//
+type TestScriptResult struct {
+	Value interpreter.Value
+	Error error
+}
+
+// TestEvent is an event emitted by a blockchain backend
+//
+type TestEvent struct {
+	TypeID common.TypeID
+	Fields []TestEventField
+}
+
+type TestEventField struct {
+	Name  string
+	Value interpreter.Value
+}
+
+var TestContractChecker = func() *sema.Checker {
+
+	program, err := parser2.ParseProgram(contracts.Test, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	var checker *sema.Checker
+	checker, err = sema.NewChecker(
+		program,
+		TestContractLocation,
+		nil,
+		sema.WithPredeclaredValues(BuiltinFunctions.ToSemaValueDeclarations()),
+		sema.WithPredeclaredTypes(BuiltinTypes.ToTypeDeclarations()),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	err = checker.Check()
+	if err != nil {
+		panic(err)
+	}
+
+	return checker
+}()
+
+var testContractType = func() *sema.CompositeType {
+	variable, ok := TestContractChecker.Elaboration.GlobalTypes.Get("Test")
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	return variable.Type.(*sema.CompositeType)
+}()
+
+func testContractNestedType(identifier string) sema.Type {
+	ty, ok := testContractType.GetNestedTypes().Get(identifier)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	return ty
+}
+
+var testBlockchainType = testContractNestedType("Blockchain").(*sema.CompositeType)
+var testBlockchainBackendType = testContractNestedType("BlockchainBackend").(*sema.InterfaceType)
+var testResultStatusType = testContractNestedType("ResultStatus").(*sema.CompositeType)
+var testScriptResultType = testContractNestedType("ScriptResult").(*sema.CompositeType)
+var testTransactionResultType = testContractNestedType("TransactionResult").(*sema.CompositeType)
+var testErrorType = testContractNestedType("Error").(*sema.CompositeType)
+var testAccountType = testContractNestedType("Account").(*sema.CompositeType)
+var testEventType = testContractNestedType("Event").(*sema.CompositeType)
+var testMatcherType = testContractNestedType("Matcher").(*sema.CompositeType)
+
+const (
+	testResultStatusSucceeded uint8 = iota
+	testResultStatusFailed
+)
+
+// testEmulatorBackendType is the type of the natively implemented blockchain backend.
+// It is not declared in the contract, so it can't be named by programs,
+// but it is registered in the elaboration of the contract, so values of it can be loaded
+//
+var testEmulatorBackendType = func() *sema.CompositeType {
+
+	ty := &sema.CompositeType{
+		Location:   TestContractLocation,
+		Identifier: "EmulatorBackend",
+		Kind:       common.CompositeKindStructure,
+		ExplicitInterfaceConformances: []*sema.InterfaceType{
+			testBlockchainBackendType,
+		},
+	}
+
+	var members []*sema.Member
+	testBlockchainBackendType.Members.Foreach(func(name string, member *sema.Member) {
+		members = append(
+			members,
+			sema.NewUnmeteredPublicFunctionMember(
+				ty,
+				name,
+				member.TypeAnnotation.Type.(*sema.FunctionType),
+				member.DocString,
+			),
+		)
+	})
+
+	ty.Members = sema.GetMembersAsMap(members)
+	ty.Fields = []string{}
+	ty.SetContainerType(testContractType)
+
+	return ty
+}()
+
+// Test.assert
+
+const testAssertFunctionName = "assert"
+
+const testAssertFunctionDocString = `
+Fails the test if the given condition is false, and reports a message which explains how the condition is false.
+
+The message argument is optional.
+`
+
+// Test.fail
+
+const testFailFunctionName = "fail"
+
+const testFailFunctionDocString = `
+Fails the test unconditionally, and reports a message which explains why the test failed.
+
+The message argument is optional.
+`
+
+var testFailFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Identifier:     "message",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.VoidType,
+	),
+	RequiredArgumentCount: sema.RequiredArgumentCount(0),
+}
+
+// Test.assertEqual
+
+const testAssertEqualFunctionName = "assertEqual"
+
+const testAssertEqualFunctionDocString = `
+Fails the test if the given actual value is not equal to the given expected value.
+`
+
+var testAssertEqualFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "expected",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.AnyStructType),
+		},
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "actual",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.AnyStructType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.VoidType,
+	),
+}
+
+// Test.equal
+
+const testEqualFunctionName = "equal"
+
+const testEqualFunctionDocString = `
+Returns a matcher which succeeds if the tested value is equal to the given value.
+`
+
+var testEqualFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "value",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.AnyStructType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		testMatcherType,
+	),
+}
+
+var testMatcherTestFunctionType = func() *sema.FunctionType {
+	member, ok := testMatcherType.Members.Get("test")
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	return member.TypeAnnotation.Type.(*sema.FunctionType)
+}()
+
+// Test.newEmulatorBlockchain
+
+const testNewEmulatorBlockchainFunctionName = "newEmulatorBlockchain"
+
+const testNewEmulatorBlockchainFunctionDocString = `
+Returns a new blockchain which is backed by a new, empty emulator.
+`
+
+var testNewEmulatorBlockchainFunctionType = &sema.FunctionType{
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		testBlockchainType,
+	),
+}
+
+// Test.readFile
+
+const testReadFileFunctionName = "readFile"
+
+const testReadFileFunctionDocString = `
+Returns the content of the file at the given path.
+Relative paths are resolved against the directory of the test program.
+`
+
+var testReadFileFunctionType = &sema.FunctionType{
+	Parameters: []*sema.Parameter{
+		{
+			Label:          sema.ArgumentLabelNotRequired,
+			Identifier:     "path",
+			TypeAnnotation: sema.NewTypeAnnotation(sema.StringType),
+		},
+	},
+	ReturnTypeAnnotation: sema.NewTypeAnnotation(
+		sema.StringType,
+	),
+}
+
+func init() {
+
+	// Declare the natively implemented functions of the contract.
+	// NOTE: The members must be added before the members of the contract type are resolved,
+	// i.e. before any program importing the contract is checked
+
+	members := []*sema.Member{
+		sema.NewUnmeteredPublicFunctionMember(
+			testContractType,
+			testAssertFunctionName,
+			assertFunctionType,
+			testAssertFunctionDocString,
+		),
+		sema.NewUnmeteredPublicFunctionMember(
+			testContractType,
+			testFailFunctionName,
+			testFailFunctionType,
+			testFailFunctionDocString,
+		),
+		sema.NewUnmeteredPublicFunctionMember(
+			testContractType,
+			testAssertEqualFunctionName,
+			testAssertEqualFunctionType,
+			testAssertEqualFunctionDocString,
+		),
+		sema.NewUnmeteredPublicFunctionMember(
+			testContractType,
+			testEqualFunctionName,
+			testEqualFunctionType,
+			testEqualFunctionDocString,
+		),
+		sema.NewUnmeteredPublicFunctionMember(
+			testContractType,
+			testNewEmulatorBlockchainFunctionName,
+			testNewEmulatorBlockchainFunctionType,
+			testNewEmulatorBlockchainFunctionDocString,
+		),
+		sema.NewUnmeteredPublicFunctionMember(
+			testContractType,
+			testReadFileFunctionName,
+			testReadFileFunctionType,
+			testReadFileFunctionDocString,
+		),
+	}
+
+	for _, member := range members {
+		testContractType.Members.Set(member.Identifier.Identifier, member)
+	}
+
+	TestContractChecker.Elaboration.CompositeTypes[testEmulatorBackendType.ID()] = testEmulatorBackendType
+}
+
+// NewTestContract returns the value of the Test contract.
+// The natively implemented functions use the given framework
+//
+func NewTestContract(
+	inter *interpreter.Interpreter,
+	constructor interpreter.FunctionValue,
+	invocationRange ast.Range,
+	framework TestFramework,
+) (
+	*interpreter.CompositeValue,
+	error,
+) {
+	value, err := inter.InvokeFunctionValue(
+		constructor,
+		nil,
+		nil,
+		nil,
+		invocationRange,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	compositeValue := value.(*interpreter.CompositeValue)
+
+	compositeValue.InitializeFunctions(inter)
+
+	functions := map[string]interpreter.FunctionValue{
+		testAssertFunctionName: interpreter.NewUnmeteredHostFunctionValue(
+			testAssertFunction,
+			assertFunctionType,
+		),
+		testFailFunctionName: interpreter.NewUnmeteredHostFunctionValue(
+			testFailFunction,
+			testFailFunctionType,
+		),
+		testAssertEqualFunctionName: interpreter.NewUnmeteredHostFunctionValue(
+			testAssertEqualFunction,
+			testAssertEqualFunctionType,
+		),
+		testEqualFunctionName: interpreter.NewUnmeteredHostFunctionValue(
+			testEqualFunction,
+			testEqualFunctionType,
+		),
+		testNewEmulatorBlockchainFunctionName: interpreter.NewUnmeteredHostFunctionValue(
+			func(invocation interpreter.Invocation) interpreter.Value {
+				backend := framework.NewEmulatorBackend()
+				return newTestBlockchainValue(invocation.Interpreter, backend)
+			},
+			testNewEmulatorBlockchainFunctionType,
+		),
+		testReadFileFunctionName: interpreter.NewUnmeteredHostFunctionValue(
+			func(invocation interpreter.Invocation) interpreter.Value {
+				path, ok := invocation.Arguments[0].(*interpreter.StringValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				content, err := framework.ReadFile(path.Str)
+				if err != nil {
+					panic(err)
+				}
+
+				return interpreter.NewUnmeteredStringValue(content)
+			},
+			testReadFileFunctionType,
+		),
+	}
+
+	for name, function := range compositeValue.Functions { //nolint:maprangecheck
+		functions[name] = function
+	}
+
+	compositeValue.Functions = functions
+
+	return compositeValue, nil
+}
+
+func testAssertFunction(invocation interpreter.Invocation) interpreter.Value {
+	condition, ok := invocation.Arguments[0].(interpreter.BoolValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	if !condition {
+		panic(AssertionError{
+			Message:       testOptionalMessage(invocation.Arguments, 1),
+			LocationRange: invocation.GetLocationRange(),
+		})
+	}
+
+	return interpreter.VoidValue{}
+}
+
+func testFailFunction(invocation interpreter.Invocation) interpreter.Value {
+	panic(AssertionError{
+		Message:       testOptionalMessage(invocation.Arguments, 0),
+		LocationRange: invocation.GetLocationRange(),
+	})
+}
+
+func testOptionalMessage(arguments []interpreter.Value, index int) string {
+	if len(arguments) <= index {
+		return ""
+	}
+
+	message, ok := arguments[index].(*interpreter.StringValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	return message.Str
+}
+
+func testAssertEqualFunction(invocation interpreter.Invocation) interpreter.Value {
+	expected := invocation.Arguments[0]
+	actual := invocation.Arguments[1]
+
+	if !testValuesEqual(invocation, expected, actual) {
+		panic(AssertionError{
+			Message: fmt.Sprintf(
+				"not equal: expected `%s`, got `%s`",
+				expected,
+				actual,
+			),
+			LocationRange: invocation.GetLocationRange(),
+		})
+	}
+
+	return interpreter.VoidValue{}
+}
+
+func testEqualFunction(invocation interpreter.Invocation) interpreter.Value {
+	expected := invocation.Arguments[0]
+
+	return interpreter.NewCompositeValue(
+		invocation.Interpreter,
+		TestContractLocation,
+		testMatcherType.QualifiedIdentifier(),
+		testMatcherType.Kind,
+		[]interpreter.CompositeField{
+			{
+				Name: "test",
+				Value: interpreter.NewUnmeteredHostFunctionValue(
+					func(invocation interpreter.Invocation) interpreter.Value {
+						actual := invocation.Arguments[0]
+						equal := testValuesEqual(invocation, expected, actual)
+						return interpreter.BoolValue(equal)
+					},
+					testMatcherTestFunctionType,
+				),
+			},
+		},
+		common.Address{},
+	)
+}
+
+func testValuesEqual(invocation interpreter.Invocation, expected, actual interpreter.Value) bool {
+	equatableValue, ok := expected.(interpreter.EquatableValue)
+	if !ok {
+		return false
+	}
+
+	return equatableValue.Equal(
+		invocation.Interpreter,
+		invocation.GetLocationRange,
+		actual,
+	)
+}
+
+func newTestBlockchainValue(
+	inter *interpreter.Interpreter,
+	backend TestBlockchainBackend,
+) *interpreter.CompositeValue {
+
+	// The backend is not storable, so its functions, which use the given backend,
+	// are still available after the value was stored in the blockchain value
+
+	backendValue := interpreter.NewSimpleCompositeValue(
+		inter,
+		testEmulatorBackendType.ID(),
+		interpreter.ConvertSemaToStaticType(inter, testEmulatorBackendType),
+		nil,
+		newTestEmulatorBackendFunctions(backend),
+		nil,
+		nil,
+		nil,
+	)
+
+	return interpreter.NewCompositeValue(
+		inter,
+		TestContractLocation,
+		testBlockchainType.QualifiedIdentifier(),
+		testBlockchainType.Kind,
+		[]interpreter.CompositeField{
+			{
+				Name:  "backend",
+				Value: backendValue,
+			},
+		},
+		common.Address{},
+	)
+}
+
+func newTestEmulatorBackendFunctions(backend TestBlockchainBackend) map[string]interpreter.Value {
+
+	functionType := func(name string) *sema.FunctionType {
+		member, ok := testEmulatorBackendType.Members.Get(name)
+		if !ok {
+			panic(errors.NewUnreachableError())
+		}
+		return member.TypeAnnotation.Type.(*sema.FunctionType)
+	}
+
+	return map[string]interpreter.Value{
+
+		"executeScript": interpreter.NewUnmeteredHostFunctionValue(
+			func(invocation interpreter.Invocation) interpreter.Value {
+				inter := invocation.Interpreter
+
+				code, ok := invocation.Arguments[0].(*interpreter.StringValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				arguments := testArrayElements(inter, invocation.Arguments[1])
+
+				result := backend.RunScript(inter, code.Str, arguments)
+
+				var returnValue interpreter.Value = interpreter.NilValue{}
+				if result.Error == nil && result.Value != nil {
+					returnValue = interpreter.NewUnmeteredSomeValueNonCopying(result.Value)
+				}
+
+				return interpreter.NewCompositeValue(
+					inter,
+					TestContractLocation,
+					testScriptResultType.QualifiedIdentifier(),
+					testScriptResultType.Kind,
+					[]interpreter.CompositeField{
+						{
+							Name:  "status",
+							Value: newTestResultStatusValue(inter, result.Error),
+						},
+						{
+							Name:  "returnValue",
+							Value: returnValue,
+						},
+						{
+							Name:  "error",
+							Value: newTestOptionalErrorValue(inter, result.Error),
+						},
+					},
+					common.Address{},
+				)
+			},
+			functionType("executeScript"),
+		),
+
+		"createAccount": interpreter.NewUnmeteredHostFunctionValue(
+			func(invocation interpreter.Invocation) interpreter.Value {
+				address, err := backend.CreateAccount()
+				if err != nil {
+					panic(err)
+				}
+
+				return interpreter.NewCompositeValue(
+					invocation.Interpreter,
+					TestContractLocation,
+					testAccountType.QualifiedIdentifier(),
+					testAccountType.Kind,
+					[]interpreter.CompositeField{
+						{
+							Name:  "address",
+							Value: interpreter.AddressValue(address),
+						},
+					},
+					common.Address{},
+				)
+			},
+			functionType("createAccount"),
+		),
+
+		"executeTransaction": interpreter.NewUnmeteredHostFunctionValue(
+			func(invocation interpreter.Invocation) interpreter.Value {
+				inter := invocation.Interpreter
+				getLocationRange := invocation.GetLocationRange
+
+				transaction, ok := invocation.Arguments[0].(*interpreter.CompositeValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				code, ok := transaction.GetField(inter, getLocationRange, "code").(*interpreter.StringValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				var authorizers []common.Address
+				for _, authorizer := range testArrayElements(inter, transaction.GetField(inter, getLocationRange, "authorizers")) {
+					address, ok := authorizer.(interpreter.AddressValue)
+					if !ok {
+						panic(errors.NewUnreachableError())
+					}
+					authorizers = append(authorizers, address.ToAddress())
+				}
+
+				arguments := testArrayElements(inter, transaction.GetField(inter, getLocationRange, "arguments"))
+
+				err := backend.RunTransaction(inter, code.Str, authorizers, arguments)
+
+				return interpreter.NewCompositeValue(
+					inter,
+					TestContractLocation,
+					testTransactionResultType.QualifiedIdentifier(),
+					testTransactionResultType.Kind,
+					[]interpreter.CompositeField{
+						{
+							Name:  "status",
+							Value: newTestResultStatusValue(inter, err),
+						},
+						{
+							Name:  "error",
+							Value: newTestOptionalErrorValue(inter, err),
+						},
+					},
+					common.Address{},
+				)
+			},
+			functionType("executeTransaction"),
+		),
+
+		"deployContract": interpreter.NewUnmeteredHostFunctionValue(
+			func(invocation interpreter.Invocation) interpreter.Value {
+				inter := invocation.Interpreter
+
+				name, ok := invocation.Arguments[0].(*interpreter.StringValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				code, ok := invocation.Arguments[1].(*interpreter.StringValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				account, ok := invocation.Arguments[2].(*interpreter.CompositeValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				address, ok := account.GetField(inter, invocation.GetLocationRange, "address").(interpreter.AddressValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				arguments := testArrayElements(inter, invocation.Arguments[3])
+
+				err := backend.DeployContract(inter, name.Str, code.Str, address.ToAddress(), arguments)
+
+				return newTestOptionalErrorValue(inter, err)
+			},
+			functionType("deployContract"),
+		),
+
+		"events": interpreter.NewUnmeteredHostFunctionValue(
+			func(invocation interpreter.Invocation) interpreter.Value {
+				inter := invocation.Interpreter
+
+				var typeID common.TypeID
+				if someValue, ok := invocation.Arguments[0].(*interpreter.SomeValue); ok {
+					typeIDValue, ok := someValue.InnerValue(inter, invocation.GetLocationRange).(*interpreter.StringValue)
+					if !ok {
+						panic(errors.NewUnreachableError())
+					}
+					typeID = common.TypeID(typeIDValue.Str)
+				}
+
+				var values []interpreter.Value
+				for _, event := range backend.Events(inter) {
+					if typeID != "" && event.TypeID != typeID {
+						continue
+					}
+					values = append(values, newTestEventValue(inter, event))
+				}
+
+				return interpreter.NewArrayValue(
+					inter,
+					interpreter.NewVariableSizedStaticType(
+						inter,
+						interpreter.ConvertSemaToStaticType(inter, testEventType),
+					),
+					common.Address{},
+					values...,
+				)
+			},
+			functionType("events"),
+		),
+	}
+}
+
+func testArrayElements(inter *interpreter.Interpreter, value interpreter.Value) []interpreter.Value {
+	array, ok := value.(*interpreter.ArrayValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	elements := make([]interpreter.Value, 0, array.Count())
+	array.Iterate(inter, func(element interpreter.Value) (resume bool) {
+		elements = append(elements, element)
+		return true
+	})
+
+	return elements
+}
+
+func newTestResultStatusValue(inter *interpreter.Interpreter, err error) *interpreter.CompositeValue {
+	status := testResultStatusSucceeded
+	if err != nil {
+		status = testResultStatusFailed
+	}
+
+	return interpreter.NewCompositeValue(
+		inter,
+		TestContractLocation,
+		testResultStatusType.QualifiedIdentifier(),
+		testResultStatusType.Kind,
+		[]interpreter.CompositeField{
+			{
+				Name:  sema.EnumRawValueFieldName,
+				Value: interpreter.NewUnmeteredUInt8Value(status),
+			},
+		},
+		common.Address{},
+	)
+}
+
+func newTestOptionalErrorValue(inter *interpreter.Interpreter, err error) interpreter.OptionalValue {
+	if err == nil {
+		return interpreter.NilValue{}
+	}
+
+	errorValue := interpreter.NewCompositeValue(
+		inter,
+		TestContractLocation,
+		testErrorType.QualifiedIdentifier(),
+		testErrorType.Kind,
+		[]interpreter.CompositeField{
+			{
+				Name:  "message",
+				Value: interpreter.NewUnmeteredStringValue(err.Error()),
+			},
+		},
+		common.Address{},
+	)
+
+	return interpreter.NewUnmeteredSomeValueNonCopying(errorValue)
+}
+
+func newTestEventValue(inter *interpreter.Interpreter, event TestEvent) *interpreter.CompositeValue {
+
+	keysAndValues := make([]interpreter.Value, 0, len(event.Fields)*2)
+	for _, field := range event.Fields {
+		keysAndValues = append(
+			keysAndValues,
+			interpreter.NewUnmeteredStringValue(field.Name),
+			field.Value,
+		)
+	}
+
+	fields := interpreter.NewDictionaryValue(
+		inter,
+		interpreter.NewDictionaryStaticType(
+			inter,
+			interpreter.PrimitiveStaticTypeString,
+			interpreter.PrimitiveStaticTypeAnyStruct,
+		),
+		keysAndValues...,
+	)
+
+	return interpreter.NewCompositeValue(
+		inter,
+		TestContractLocation,
+		testEventType.QualifiedIdentifier(),
+		testEventType.Kind,
+		[]interpreter.CompositeField{
+			{
+				Name:  "typeID",
+				Value: interpreter.NewUnmeteredStringValue(string(event.TypeID)),
+			},
+			{
+				Name:  "fields",
+				Value: fields,
+			},
+		},
+		common.Address{},
+	)
+}
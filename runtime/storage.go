@@ -136,6 +136,22 @@ func (s *Storage) GetStorageMap(
 	return storageMap
 }
 
+// StoredValueFootprint returns the number of bytes used to store the value
+// at the given path of the given account,
+// i.e. the size of the value and the sizes of all its child slabs.
+//
+// Returns false if no value is stored at the given path.
+// No computation is reported.
+//
+func (s *Storage) StoredValueFootprint(address common.Address, path interpreter.PathValue) (uint64, bool) {
+	storageMap := s.GetStorageMap(address, path.Domain.Identifier(), false)
+	if storageMap == nil {
+		return 0, false
+	}
+
+	return storageMap.ValueFootprint(nil, path.Identifier)
+}
+
 func (s *Storage) loadExistingStorageMap(address atree.Address, storageIndex atree.StorageIndex) *interpreter.StorageMap {
 
 	storageID := atree.StorageID{
@@ -1076,7 +1076,7 @@ func TestRuntimeBatchMintAndTransfer(t *testing.T) {
                   return <-collection
               }
 
-              pub fun getIDs(): [UInt64] {
+              pub view fun getIDs(): [UInt64] {
                   return self.ownedNFTs.keys
               }
 
@@ -3217,3 +3217,317 @@ func TestRuntimeStorageEnumCase(t *testing.T) {
 		loggedMessages,
 	)
 }
+
+func TestRuntimeStorageSize(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	var loggedMessages []string
+
+	// Computation is reported for each slab which is retrieved
+	// to determine the storage size
+
+	var footprintSlabs uint
+
+	ledger := newTestLedger(nil, nil)
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: ledger,
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		log: func(message string) {
+			loggedMessages = append(loggedMessages, message)
+		},
+		meterComputation: func(kind common.ComputationKind, intensity uint) error {
+			if kind == common.ComputationKindStorageFootprintSlab {
+				footprintSlabs += intensity
+			}
+			return nil
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	// Store a small and a large value, the large value is stored in multiple slabs
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: []byte(`
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      signer.save([1, 2, 3], to: /storage/small)
+
+                      let large: [String] = []
+                      var i = 0
+                      while i < 1000 {
+                          large.append("element ".concat(i.toString()))
+                          i = i + 1
+                      }
+                      signer.save(large, to: /storage/large)
+
+                      log(signer.storageSize(at: /storage/small))
+                      log(signer.storageSize(at: /storage/large))
+                      log(signer.storageSize(at: /storage/missing))
+                  }
+               }
+            `),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	// Read the sizes in a subsequent transaction, i.e. from the committed storage
+
+	footprintSlabs = 0
+
+	err = runtime.ExecuteTransaction(
+		Script{
+			Source: []byte(`
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      log(signer.storageSize(at: /storage/small))
+                      log(signer.storageSize(at: /storage/large))
+                  }
+               }
+            `),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	// The large array is stored in multiple slabs
+	require.Greater(t, footprintSlabs, uint(2))
+
+	storage := NewStorage(ledger, nil)
+
+	smallSize, ok := storage.StoredValueFootprint(
+		address,
+		interpreter.PathValue{
+			Domain:     common.PathDomainStorage,
+			Identifier: "small",
+		},
+	)
+	require.True(t, ok)
+
+	largeSize, ok := storage.StoredValueFootprint(
+		address,
+		interpreter.PathValue{
+			Domain:     common.PathDomainStorage,
+			Identifier: "large",
+		},
+	)
+	require.True(t, ok)
+
+	// The large array does not fit into a single slab,
+	// so the footprint must include the child slabs
+	require.Greater(t, smallSize, uint64(0))
+	require.Greater(t, largeSize, uint64(10_000))
+
+	_, ok = storage.StoredValueFootprint(
+		address,
+		interpreter.PathValue{
+			Domain:     common.PathDomainStorage,
+			Identifier: "missing",
+		},
+	)
+	require.False(t, ok)
+
+	require.Equal(t,
+		[]string{
+			fmt.Sprint(smallSize),
+			fmt.Sprint(largeSize),
+			"nil",
+			fmt.Sprint(smallSize),
+			fmt.Sprint(largeSize),
+		},
+		loggedMessages,
+	)
+}
+
+func TestRuntimeStorageGenericComposite(t *testing.T) {
+
+	t.Parallel()
+
+	runtime := newTestInterpreterRuntime()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	accountCodes := map[common.LocationID][]byte{}
+	var loggedMessages []string
+
+	runtimeInterface := &testRuntimeInterface{
+		storage: newTestLedger(nil, nil),
+		getSigningAccounts: func() ([]Address, error) {
+			return []Address{address}, nil
+		},
+		resolveLocation: singleIdentifierLocationResolver(t),
+		updateAccountContractCode: func(address Address, name string, code []byte) error {
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			accountCodes[location.ID()] = code
+			return nil
+		},
+		getAccountContractCode: func(address Address, name string) (code []byte, err error) {
+			location := common.AddressLocation{
+				Address: address,
+				Name:    name,
+			}
+			code = accountCodes[location.ID()]
+			return code, nil
+		},
+		emitEvent: func(event cadence.Event) error {
+			return nil
+		},
+		log: func(message string) {
+			loggedMessages = append(loggedMessages, message)
+		},
+	}
+
+	nextTransactionLocation := newTransactionLocationGenerator()
+
+	// Deploy contract
+
+	err := runtime.ExecuteTransaction(
+		Script{
+			Source: utils.DeploymentTransaction(
+				"C",
+				[]byte(`
+                  pub contract C {
+
+                    pub struct Box<T> {
+                        pub let value: T
+
+                        init(value: T) {
+                            self.value = value
+                        }
+
+                        pub fun get(): T {
+                            return self.value
+                        }
+                    }
+                  }
+                `),
+			),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	// Store instantiations of the generic composite type
+
+	err = runtime.ExecuteTransaction(
+		Script{
+			Source: []byte(`
+              import C from 0x1
+
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      signer.save(C.Box(value: [1, 2]), to: /storage/box)
+                      signer.save([C.Box<String>(value: "a")], to: /storage/boxes)
+                  }
+               }
+            `),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	// Load the instantiations of the generic composite type
+
+	err = runtime.ExecuteTransaction(
+		Script{
+			Source: []byte(`
+              import C from 0x1
+
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      let box = signer.borrow<&C.Box<[Int]>>(from: /storage/box)!
+                      log(box.get())
+                      log(box.getType().identifier)
+                      let any = signer.copy<AnyStruct>(from: /storage/box)!
+                      log(any.isInstance(Type<C.Box<[String]>>()))
+
+                      let boxes = signer.borrow<&[C.Box<String>]>(from: /storage/boxes)!
+                      log(boxes[0].get())
+                      log(boxes.getType().identifier)
+                  }
+               }
+            `),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		[]string{
+			"[1, 2]",
+			`"A.0000000000000001.C.Box<[Int]>"`,
+			"false",
+			`"a"`,
+			`"[A.0000000000000001.C.Box<String>]"`,
+		},
+		loggedMessages,
+	)
+
+	// Inspect and replace the stored instantiation of the generic composite type
+
+	loggedMessages = nil
+
+	err = runtime.ExecuteTransaction(
+		Script{
+			Source: []byte(`
+              import C from 0x1
+
+              transaction {
+                  prepare(signer: AuthAccount) {
+                      log(signer.type(at: /storage/box)!.identifier)
+                      log(signer.check<C.Box<[Int]>>(from: /storage/box))
+                      log(signer.check<C.Box<[String]>>(from: /storage/box))
+
+                      let old = signer.swap(C.Box<[Int]>(value: [3]), at: /storage/box)!
+                      log(old.get())
+                      log(signer.borrow<&C.Box<[Int]>>(from: /storage/box)!.get())
+                  }
+               }
+            `),
+		},
+		Context{
+			Interface: runtimeInterface,
+			Location:  nextTransactionLocation(),
+		},
+	)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		[]string{
+			`"A.0000000000000001.C.Box<[Int]>"`,
+			"true",
+			"false",
+			"[1, 2]",
+			"[3]",
+		},
+		loggedMessages,
+	)
+}
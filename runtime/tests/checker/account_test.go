@@ -405,6 +405,198 @@ func TestCheckAccount_typeAt(t *testing.T) {
 	}
 }
 
+func TestCheckAccount_storageSizeAt(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(domain common.PathDomain) {
+		t.Run(fmt.Sprintf("storageSize %s", domain.Identifier()), func(t *testing.T) {
+
+			t.Parallel()
+
+			checker, err := ParseAndCheckAccount(t,
+				fmt.Sprintf(
+					`
+						let size: UInt64? = authAccount.storageSize(at: /%s/r)
+					`,
+					domain.Identifier(),
+				),
+			)
+
+			if domain == common.PathDomainStorage {
+
+				require.NoError(t, err)
+
+				typ := RequireGlobalValue(t, checker.Elaboration, "size")
+
+				require.Equal(t,
+					&sema.OptionalType{
+						Type: sema.UInt64Type,
+					},
+					typ,
+				)
+
+			} else {
+				errs := ExpectCheckerErrors(t, err, 1)
+
+				require.IsType(t, &sema.TypeMismatchError{}, errs[0])
+			}
+		})
+	}
+
+	for _, domain := range common.AllPathDomainsByIdentifier {
+		test(domain)
+	}
+}
+
+func TestCheckAccount_check(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(domain common.PathDomain) {
+		t.Run(fmt.Sprintf("check %s", domain.Identifier()), func(t *testing.T) {
+
+			t.Parallel()
+
+			checker, err := ParseAndCheckAccount(t,
+				fmt.Sprintf(
+					`
+                      resource R {}
+
+                      let r = authAccount.check<@R>(from: /%[1]s/r)
+                      let s = authAccount.check<&R>(from: /%[1]s/r)
+                    `,
+					domain.Identifier(),
+				),
+			)
+
+			if domain == common.PathDomainStorage {
+
+				require.NoError(t, err)
+
+				assert.Equal(t,
+					sema.BoolType,
+					RequireGlobalValue(t, checker.Elaboration, "r"),
+				)
+
+			} else {
+				errs := ExpectCheckerErrors(t, err, 2)
+
+				require.IsType(t, &sema.TypeMismatchError{}, errs[0])
+				require.IsType(t, &sema.TypeMismatchError{}, errs[1])
+			}
+		})
+	}
+
+	for _, domain := range common.AllPathDomainsByIdentifier {
+		test(domain)
+	}
+
+	t.Run("missing type argument", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          let r = authAccount.check(from: /storage/r)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.TypeParameterTypeInferenceError{}, errs[0])
+	})
+}
+
+func TestCheckAccount_swap(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          resource R {}
+
+          fun test(): @R? {
+              return <-authAccount.swap(<-create R(), at: /storage/r)
+          }
+
+          fun testExplicit(): @R? {
+              return <-authAccount.swap<@R>(<-create R(), at: /storage/r)
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("struct", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckAccount(t, `
+          struct S {}
+
+          let s: S? = authAccount.swap(S(), at: /storage/s)
+        `)
+		require.NoError(t, err)
+
+		sType := RequireGlobalType(t, checker.Elaboration, "S")
+
+		assert.Equal(t,
+			&sema.OptionalType{Type: sType},
+			RequireGlobalValue(t, checker.Elaboration, "s"),
+		)
+	})
+
+	t.Run("resource, missing move", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          resource R {}
+
+          fun test() {
+              authAccount.swap(<-create R(), at: /storage/r)
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.ResourceLossError{}, errs[0])
+	})
+
+	t.Run("public path", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          struct S {}
+
+          let s = authAccount.swap(S(), at: /public/s)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("public account", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          struct S {}
+
+          let s = publicAccount.swap(S(), at: /storage/s)
+          let t = publicAccount.check<S>(from: /storage/s)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		require.IsType(t, &sema.NotDeclaredMemberError{}, errs[0])
+		require.IsType(t, &sema.NotDeclaredMemberError{}, errs[1])
+	})
+}
+
 func TestCheckAccount_load(t *testing.T) {
 
 	t.Parallel()
@@ -1393,6 +1585,88 @@ func TestCheckAccount_StorageFields(t *testing.T) {
 	}
 }
 
+func TestCheckAccount_StorageIteration(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("AuthAccount", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheckAccount(t, `
+          let storagePaths: [StoragePath] = authAccount.storagePaths
+          let publicPaths: [PublicPath] = authAccount.publicPaths
+          let privatePaths: [PrivatePath] = authAccount.privatePaths
+
+          fun test() {
+              authAccount.forEachStored(fun (path: StoragePath, type: Type): Bool {
+                  return true
+              })
+              authAccount.forEachPublic(fun (path: PublicPath, type: Type): Bool {
+                  return true
+              })
+              authAccount.forEachPrivate(fun (path: PrivatePath, type: Type): Bool {
+                  return true
+              })
+          }
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.VariableSizedType{Type: sema.StoragePathType},
+			RequireGlobalValue(t, checker.Elaboration, "storagePaths"),
+		)
+	})
+
+	t.Run("PublicAccount", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          let publicPaths: [PublicPath] = publicAccount.publicPaths
+
+          fun test() {
+              publicAccount.forEachPublic(fun (path: PublicPath, type: Type): Bool {
+                  return true
+              })
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("PublicAccount, storage paths", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          let storagePaths = publicAccount.storagePaths
+          let privatePaths = publicAccount.privatePaths
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		assert.IsType(t, &sema.NotDeclaredMemberError{}, errs[0])
+		assert.IsType(t, &sema.NotDeclaredMemberError{}, errs[1])
+	})
+
+	t.Run("invalid function", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheckAccount(t, `
+          fun test() {
+              authAccount.forEachStored(fun (path: PublicPath, type: Type): Bool {
+                  return true
+              })
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+}
+
 func TestAuthAccountContracts(t *testing.T) {
 
 	t.Parallel()
@@ -574,6 +574,200 @@ func TestCheckInvalidResourceArraySlice(t *testing.T) {
 	assert.IsType(t, &sema.ResourceLossError{}, errs[2])
 }
 
+func TestCheckArrayHigherOrderFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("forEach", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test(): Int {
+              var sum = 0
+              [1, 2, 3].forEach(fun (x: Int) {
+                  sum = sum + x
+              })
+              return sum
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let xs = [1, 2, 3].filter(view fun (x: Int): Bool {
+              return x > 1
+          })
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.VariableSizedType{Type: sema.IntType},
+			RequireGlobalValue(t, checker.Elaboration, "xs"),
+		)
+	})
+
+	t.Run("map", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let xs = [1, 2, 3].map(fun (x: Int): String {
+              return x.toString()
+          })
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.VariableSizedType{Type: sema.StringType},
+			RequireGlobalValue(t, checker.Elaboration, "xs"),
+		)
+	})
+
+	t.Run("map, constant-sized", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let ys: [Int; 3] = [1, 2, 3]
+          let xs = ys.map(fun (x: Int): Bool {
+              return x > 1
+          })
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.ConstantSizedType{Type: sema.BoolType, Size: 3},
+			RequireGlobalValue(t, checker.Elaboration, "xs"),
+		)
+	})
+
+	t.Run("reverse", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let ys: [Int; 3] = [1, 2, 3]
+          let xs = ys.reverse()
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.ConstantSizedType{Type: sema.IntType, Size: 3},
+			RequireGlobalValue(t, checker.Elaboration, "xs"),
+		)
+	})
+
+	t.Run("sort", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let xs = [3, 1, 2].sort(by: view fun (a: Int, b: Int): Bool {
+              return a < b
+          })
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.VariableSizedType{Type: sema.IntType},
+			RequireGlobalValue(t, checker.Elaboration, "xs"),
+		)
+	})
+
+	t.Run("reduce", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let x = [1, 2, 3].reduce(initial: "", fun (acc: String, x: Int): String {
+              return acc.concat(x.toString())
+          })
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			sema.StringType,
+			RequireGlobalValue(t, checker.Elaboration, "x"),
+		)
+	})
+}
+
+func TestCheckInvalidArrayHigherOrderFunctionImpureArgument(t *testing.T) {
+
+	t.Parallel()
+
+	for _, code := range []string{
+		`
+          let xs = [1, 2, 3].filter(fun (x: Int): Bool {
+              return x > 1
+          })
+        `,
+		`
+          let xs = [3, 1, 2].sort(by: fun (a: Int, b: Int): Bool {
+              return a < b
+          })
+        `,
+	} {
+		_, err := ParseAndCheck(t, code)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	}
+}
+
+func TestCheckInvalidResourceArrayHigherOrderFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	for member, invocation := range map[string]string{
+		"forEach": `xs.forEach(fun (x: &X) {})`,
+		"filter":  `xs.filter(fun (x: &X): Bool { return true })`,
+		"map":     `xs.map(fun (x: &X): Int { return 1 })`,
+		"reverse": `xs.reverse()`,
+		"sort":    `xs.sort(by: fun (a: &X, b: &X): Bool { return true })`,
+		"reduce":  `xs.reduce(initial: 0, fun (acc: Int, x: &X): Int { return acc })`,
+	} {
+
+		member := member
+		invocation := invocation
+
+		t.Run(member, func(t *testing.T) {
+
+			t.Parallel()
+
+			_, err := ParseAndCheck(t,
+				fmt.Sprintf(
+					`
+                      resource X {}
+
+                      fun test(xs: &[X]) {
+                          %s
+                      }
+                    `,
+					invocation,
+				),
+			)
+
+			errs := ExpectCheckerErrors(t, err, 1)
+
+			assert.IsType(t, &sema.InvalidResourceArrayMemberError{}, errs[0])
+		})
+	}
+}
+
 func TestCheckArrayInsert(t *testing.T) {
 
 	t.Parallel()
@@ -918,6 +1112,124 @@ func TestCheckInvalidDictionaryContainsKey(t *testing.T) {
 	assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
 }
 
+func TestCheckDictionaryHigherOrderFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("forEachKey", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test(): [String] {
+              let keys: [String] = []
+              {"a": 1, "b": 2}.forEachKey(fun (key: String): Bool {
+                  keys.append(key)
+                  return true
+              })
+              return keys
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("forEachKey, resource values", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource X {}
+
+          fun test(xs: &{String: X}) {
+              xs.forEachKey(fun (key: String): Bool {
+                  return true
+              })
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let xs = {"a": 1, "b": 2}.filter(view fun (key: String, value: Int): Bool {
+              return value > 1
+          })
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.DictionaryType{
+				KeyType:   sema.StringType,
+				ValueType: sema.IntType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "xs"),
+		)
+	})
+
+	t.Run("map", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let xs = {"a": 1, "b": 2}.map(fun (key: String, value: Int): Bool {
+              return value > 1
+          })
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.DictionaryType{
+				KeyType:   sema.StringType,
+				ValueType: sema.BoolType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "xs"),
+		)
+	})
+}
+
+func TestCheckInvalidResourceDictionaryHigherOrderFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	for member, invocation := range map[string]string{
+		"filter": `xs.filter(fun (key: String, value: &X): Bool { return true })`,
+		"map":    `xs.map(fun (key: String, value: &X): Int { return 1 })`,
+	} {
+
+		member := member
+		invocation := invocation
+
+		t.Run(member, func(t *testing.T) {
+
+			t.Parallel()
+
+			_, err := ParseAndCheck(t,
+				fmt.Sprintf(
+					`
+                      resource X {}
+
+                      fun test(xs: &{String: X}) {
+                          %s
+                      }
+                    `,
+					invocation,
+				),
+			)
+
+			errs := ExpectCheckerErrors(t, err, 1)
+
+			assert.IsType(t, &sema.InvalidResourceDictionaryMemberError{}, errs[0])
+		})
+	}
+}
+
 func TestCheckEmptyDictionary(t *testing.T) {
 
 	t.Parallel()
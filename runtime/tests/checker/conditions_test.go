@@ -375,9 +375,10 @@ func TestCheckInvalidFunctionPostConditionWithFunction(t *testing.T) {
       }
     `)
 
-	errs := ExpectCheckerErrors(t, err, 1)
+	errs := ExpectCheckerErrors(t, err, 2)
 
 	assert.IsType(t, &sema.FunctionExpressionInConditionError{}, errs[0])
+	assert.IsType(t, &sema.PurityError{}, errs[1])
 }
 
 func TestCheckFunctionPostConditionWithMessageUsingStringLiteral(t *testing.T) {
@@ -492,10 +493,11 @@ func TestCheckFunctionWithPostConditionAndResourceResult(t *testing.T) {
         }
     `)
 
-	errs := ExpectCheckerErrors(t, err, 2)
+	errs := ExpectCheckerErrors(t, err, 3)
 
-	require.IsType(t, &sema.InvalidMoveOperationError{}, errs[0])
-	require.IsType(t, &sema.TypeMismatchError{}, errs[1])
+	require.IsType(t, &sema.PurityError{}, errs[0])
+	require.IsType(t, &sema.InvalidMoveOperationError{}, errs[1])
+	require.IsType(t, &sema.TypeMismatchError{}, errs[2])
 }
 
 // TestCheckConditionCreateBefore tests if the AST expression extractor properly handles
@@ -0,0 +1,436 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func TestCheckGenericFunctionDeclaration(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("inferred type argument", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          fun identity<T>(_ value: T): T {
+              return value
+          }
+
+          let x = identity(1)
+          let y = identity("hello")
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t, sema.IntType, RequireGlobalValue(t, checker.Elaboration, "x"))
+		assert.Equal(t, sema.StringType, RequireGlobalValue(t, checker.Elaboration, "y"))
+	})
+
+	t.Run("explicit type argument", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          fun wrap<T>(_ value: T): [T] {
+              return [value]
+          }
+
+          let xs = wrap<String>("a")
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.VariableSizedType{
+				Type: sema.StringType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "xs"),
+		)
+	})
+
+	t.Run("type bound", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun double<T: Integer>(_ value: T): T {
+              return value
+          }
+
+          let x = double("hello")
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("members of type bound", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct interface HasID {
+              let id: Int
+          }
+
+          fun getID<T: AnyStruct{HasID}>(_ value: T): Int {
+              return value.id
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("resource type parameter", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {}
+
+          fun identity<T: AnyResource>(_ value: @T): @T {
+              return <-value
+          }
+
+          fun test() {
+              let r <- identity(<-create R())
+              destroy r
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("resource type parameter, loss", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun lose<T: AnyResource>(_ value: @T) {}
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.ResourceLossError{}, errs[0])
+	})
+
+	t.Run("resource type parameter, missing resource annotation", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test<T: AnyResource>(_ value: T) {
+              destroy value
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.MissingResourceAnnotationError{}, errs[0])
+	})
+
+	t.Run("type parameter not in scope outside of function", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test<T>() {}
+
+          let x: T = 1
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.NotDeclaredError{}, errs[0])
+	})
+
+	t.Run("type parameter is not a concrete type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test<T>(): T {
+              return 1
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("uninferrable type parameter", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun empty<T>(): [T] {
+              return []
+          }
+
+          let x = empty()
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeParameterTypeInferenceError{}, errs[0])
+	})
+
+	t.Run("nested generic function", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          fun pair<T>(_ value: T): [T] {
+              fun identity<U>(_ value: U): U {
+                  return value
+              }
+              return [identity(value), value]
+          }
+
+          let xs = pair(true)
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.VariableSizedType{
+				Type: sema.BoolType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "xs"),
+		)
+	})
+}
+
+func TestCheckGenericCompositeDeclaration(t *testing.T) {
+
+	t.Parallel()
+
+	const boxDeclaration = `
+      struct Box<T> {
+          let value: T
+
+          init(value: T) {
+              self.value = value
+          }
+
+          fun get(): T {
+              return self.value
+          }
+
+          fun map<U>(_ f: ((T): U)): Box<U> {
+              return Box(value: f(self.value))
+          }
+
+          fun wrap(): Box<Box<T>> {
+              return Box(value: self)
+          }
+      }
+    `
+
+	t.Run("inferred type argument", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, boxDeclaration+`
+          let box = Box(value: 1)
+          let value = box.get()
+        `)
+		require.NoError(t, err)
+
+		boxType := RequireGlobalValue(t, checker.Elaboration, "box")
+		assert.Equal(t, "Box<Int>", boxType.String())
+		assert.Equal(t, "S.test.Box<Int>", string(boxType.ID()))
+
+		assert.Equal(t, sema.IntType, RequireGlobalValue(t, checker.Elaboration, "value"))
+	})
+
+	t.Run("explicit type argument", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, boxDeclaration+`
+          let box: Box<String> = Box<String>(value: "a")
+          let value: String = box.value
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			"Box<String>",
+			RequireGlobalValue(t, checker.Elaboration, "box").String(),
+		)
+	})
+
+	t.Run("type argument mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, boxDeclaration+`
+          let box: Box<String> = Box(value: 1)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("missing type arguments", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, boxDeclaration+`
+          let box: Box = Box(value: 1)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.MissingTypeArgumentsError{}, errs[0])
+	})
+
+	t.Run("invalid type argument count", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, boxDeclaration+`
+          let box: Box<Int, String> = Box(value: 1)
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidTypeArgumentCountError{}, errs[0])
+	})
+
+	t.Run("generic function of generic composite", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, boxDeclaration+`
+          let box = Box(value: 1).map(fun (value: Int): String {
+              return value.toString()
+          })
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			"Box<String>",
+			RequireGlobalValue(t, checker.Elaboration, "box").String(),
+		)
+	})
+
+	t.Run("recursive instantiation", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, boxDeclaration+`
+          let box = Box(value: 1).wrap().wrap()
+          let value: Int = box.get().get().get()
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			"Box<Box<Box<Int>>>",
+			RequireGlobalValue(t, checker.Elaboration, "box").String(),
+		)
+	})
+
+	t.Run("inference from generic composite argument", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, boxDeclaration+`
+          fun unbox<T>(_ box: Box<T>): T {
+              return box.value
+          }
+
+          let value = unbox(Box(value: true))
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t, sema.BoolType, RequireGlobalValue(t, checker.Elaboration, "value"))
+	})
+
+	t.Run("resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {}
+
+          resource Holder<T: AnyResource> {
+              let value: @T
+
+              init(value: @T) {
+                  self.value <- value
+              }
+
+              destroy() {
+                  destroy self.value
+              }
+          }
+
+          fun test() {
+              let holder <- create Holder(value: <-create R())
+              destroy holder
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("resource field of struct type parameter", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {}
+
+          struct Box<T> {
+              let value: T
+
+              init(value: T) {
+                  self.value = value
+              }
+          }
+
+          fun test() {
+              let box = Box(value: <-create R())
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("invalid composite kind", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          contract C<T> {}
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidTypeParametersError{}, errs[0])
+	})
+}
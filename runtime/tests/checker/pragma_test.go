@@ -97,3 +97,87 @@ func TestCheckPragmaInvalidInvocationExprTypeArgs(t *testing.T) {
 	errs := ExpectCheckerErrors(t, err, 1)
 	assert.IsType(t, &sema.InvalidPragmaError{Message: "type arguments not supported"}, errs[0])
 }
+
+func TestCheckRemovedFieldPragma(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          struct S {
+              #removedField("a")
+
+              let b: Int
+
+              init() {
+                  self.b = 1
+              }
+          }
+        `)
+		require.NoError(t, err)
+
+		compositeType := RequireGlobalType(t, checker.Elaboration, "S").(*sema.CompositeType)
+		assert.Equal(t, []string{"a"}, compositeType.RemovedFields)
+		assert.Equal(t, []string{"b"}, compositeType.Fields)
+	})
+
+	t.Run("redeclaration", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct S {
+              #removedField("a")
+
+              let a: Int
+
+              init() {
+                  self.a = 1
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+		assert.IsType(t, &sema.RemovedFieldRedeclarationError{}, errs[0])
+	})
+
+	t.Run("missing field name", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct S {
+              #removedField
+          }
+        `)
+
+		require.NoError(t, err)
+
+		_, err = ParseAndCheck(t, `
+          struct S {
+              #removedField("a", "b")
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+		assert.IsType(t, &sema.InvalidPragmaError{}, errs[0])
+	})
+
+	t.Run("invalid argument", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct S {
+              #removedField(a)
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 2)
+		assert.IsType(t, &sema.InvalidPragmaError{}, errs[0])
+		assert.IsType(t, &sema.InvalidPragmaError{}, errs[1])
+	})
+}
@@ -0,0 +1,579 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+func TestCheckViewFunction(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("read state", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let xs = [1, 2, 3]
+
+          view fun test(): Int {
+              return xs[0] + xs.length
+          }
+        `)
+		require.NoError(t, err)
+
+		functionType := RequireGlobalValue(t, checker.Elaboration, "test")
+		require.IsType(t, &sema.FunctionType{}, functionType)
+		assert.Equal(t,
+			sema.FunctionPurityView,
+			functionType.(*sema.FunctionType).Purity,
+		)
+	})
+
+	t.Run("call view function", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          view fun double(_ x: Int): Int {
+              return x * 2
+          }
+
+          view fun test(): Int {
+              return double(1).toString().length
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("call impure function", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun impure() {}
+
+          view fun test() {
+              impure()
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.PurityError{}, errs[0])
+	})
+
+	t.Run("assign to global variable", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          var x = 0
+
+          view fun test() {
+              x = 1
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.PurityError{}, errs[0])
+	})
+
+	t.Run("assign to local variable", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          view fun test(_ y: Int): Int {
+              var x = 0
+              x = y
+              if true {
+                  x = x + 1
+              }
+              return x
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("mutate local array", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          view fun test(): [Int] {
+              let xs: [Int] = []
+              xs.append(1)
+              xs[0] = 2
+              return xs
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("mutate global array", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let xs: [Int] = []
+
+          view fun test() {
+              xs.append(1)
+              xs[0] = 2
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		assert.IsType(t, &sema.PurityError{}, errs[0])
+		assert.IsType(t, &sema.PurityError{}, errs[1])
+	})
+
+	t.Run("mutate through reference", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          view fun test(_ xs: &[Int]) {
+              xs[0] = 1
+              xs.append(1)
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		assert.IsType(t, &sema.PurityError{}, errs[0])
+		assert.IsType(t, &sema.PurityError{}, errs[1])
+	})
+
+	t.Run("assign to captured variable", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test() {
+              var x = 0
+              let f = view fun () {
+                  x = 1
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.PurityError{}, errs[0])
+	})
+
+	t.Run("impure function expression", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          var x = 0
+
+          view fun test() {
+              let f = fun () {
+                  x = 1
+              }
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("emit", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          event E()
+
+          view fun test() {
+              emit E()
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.PurityError{}, errs[0])
+	})
+
+	t.Run("destroy", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {}
+
+          view fun test(_ r: @R) {
+              destroy r
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.PurityError{}, errs[0])
+	})
+
+	t.Run("storage write", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          view fun test(_ account: AuthAccount) {
+              account.save(1, to: /storage/one)
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.PurityError{}, errs[0])
+	})
+
+	t.Run("storage read", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          view fun test(_ account: AuthAccount): &Int? {
+              return account.borrow<&Int>(from: /storage/one)
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("higher-order function of local array with view function", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          view fun test(): [Int] {
+              let xs = [1, 2, 3]
+              xs.forEach(view fun (x: Int) {})
+              return xs.map(view fun (x: Int): Int {
+                  return x * 2
+              })
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("higher-order function of local array with impure function", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          var sum = 0
+
+          view fun test() {
+              let xs = [1, 2, 3]
+              xs.forEach(fun (x: Int) {
+                  sum = sum + x
+              })
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.PurityError{}, errs[0])
+	})
+
+	t.Run("higher-order function of global array", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let xs = [1, 2, 3]
+
+          view fun test(): [Int] {
+              xs.forEach(view fun (x: Int) {})
+              return xs.filter(view fun (x: Int): Bool {
+                  return x > 1
+              })
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.PurityError{}, errs[0])
+	})
+}
+
+func TestCheckViewCompositeFunction(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("mutate field", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct S {
+              var x: Int
+              let xs: [Int]
+
+              init() {
+                  self.x = 0
+                  self.xs = []
+              }
+
+              view fun test() {
+                  self.x = 1
+                  self.xs.append(1)
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		assert.IsType(t, &sema.PurityError{}, errs[0])
+		assert.IsType(t, &sema.PurityError{}, errs[1])
+	})
+
+	t.Run("view initializer", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct S {
+              var x: Int
+
+              view init(x: Int) {
+                  self.x = x
+              }
+          }
+
+          view fun test(): S {
+              return S(x: 1)
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("impure initializer", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct S {
+              init() {}
+          }
+
+          view fun test(): S {
+              return S()
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.PurityError{}, errs[0])
+	})
+
+	t.Run("no initializer", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct S {}
+
+          view fun test(): S {
+              return S()
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("interface function requires view implementation", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct interface I {
+              view fun test(): Int
+          }
+
+          struct S: I {
+              fun test(): Int {
+                  return 1
+              }
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.ConformanceError{}, errs[0])
+	})
+
+	t.Run("view implementation of interface function", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct interface I {
+              fun test(): Int
+          }
+
+          struct S: I {
+              view fun test(): Int {
+                  return 1
+              }
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("interface initializer purity mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct interface I {
+              view init()
+          }
+
+          struct S: I {
+              init() {}
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.ConformanceError{}, errs[0])
+	})
+}
+
+func TestCheckViewFunctionType(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("view function is subtype of impure function type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let f: ((Int): Int) = view fun (x: Int): Int {
+              return x
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("impure function is not subtype of view function type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let f: (view (Int): Int) = fun (x: Int): Int {
+              return x
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("call view function parameter", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          view fun apply(_ f: (view (Int): Int), _ x: Int): Int {
+              return f(x)
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("call impure function parameter", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          view fun apply(_ f: ((Int): Int), _ x: Int): Int {
+              return f(x)
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.PurityError{}, errs[0])
+	})
+}
+
+func TestCheckConditionPurity(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("view function call", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          view fun isPositive(_ x: Int): Bool {
+              return x > 0
+          }
+
+          fun test(x: Int): Int {
+              pre {
+                  isPositive(x)
+              }
+              post {
+                  isPositive(result)
+                  before(x) == x
+              }
+              return x
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("impure function call", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun isPositive(_ x: Int): Bool {
+              return x > 0
+          }
+
+          fun test(x: Int): Int {
+              pre {
+                  isPositive(x)
+              }
+              post {
+                  isPositive(result)
+              }
+              return x
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 2)
+
+		assert.IsType(t, &sema.PurityError{}, errs[0])
+		assert.IsType(t, &sema.PurityError{}, errs[1])
+	})
+}
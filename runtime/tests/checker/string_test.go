@@ -324,3 +324,130 @@ func TestCheckStringToLower(t *testing.T) {
 		RequireGlobalValue(t, checker.Elaboration, "x"),
 	)
 }
+
+func TestCheckStringTemplate(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let a = 1
+          let b: Character = "b"
+          let x = "\(a) \(b) \("c") \(true) \(1.5) \(0x1) \(/storage/foo) \(a + 1)"
+	    `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			sema.StringType,
+			RequireGlobalValue(t, checker.Elaboration, "x"),
+		)
+	})
+
+	t.Run("invalid value type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let a = [1]
+          let x = "\(a)"
+	    `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidStringTemplateValueTypeError{}, errs[0])
+	})
+
+	t.Run("invalid optional value type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let a: Int? = 1
+          let x = "\(a)"
+	    `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidStringTemplateValueTypeError{}, errs[0])
+	})
+
+	t.Run("resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          resource R {}
+
+          fun test() {
+              let r <- create R()
+              let x = "\(r)"
+              destroy r
+          }
+	    `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidStringTemplateValueTypeError{}, errs[0])
+	})
+}
+
+func TestCheckStringFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+        let s = " a,b "
+        let upper = s.toUpper()
+        let trimmed = s.trim()
+        let contains = s.contains("a")
+        let index = s.index(of: "b")
+        let parts = s.split(separator: ",")
+        let replaced = s.replaceAll(of: "a", with: "c")
+        let joined = String.join(["a", "b"], separator: ",")
+        let fromCharacters = String.fromCharacters(["a", "b"])
+        let fromUTF8 = String.fromUTF8([0x61])
+	`)
+
+	require.NoError(t, err)
+
+	for name, expectedType := range map[string]sema.Type{
+		"upper":    sema.StringType,
+		"trimmed":  sema.StringType,
+		"contains": sema.BoolType,
+		"index":    sema.IntType,
+		"parts": &sema.VariableSizedType{
+			Type: sema.StringType,
+		},
+		"replaced":       sema.StringType,
+		"joined":         sema.StringType,
+		"fromCharacters": sema.StringType,
+		"fromUTF8": &sema.OptionalType{
+			Type: sema.StringType,
+		},
+	} {
+		assert.Equal(t,
+			expectedType,
+			RequireGlobalValue(t, checker.Elaboration, name),
+			name,
+		)
+	}
+}
+
+func TestCheckStringFunctionsInViewContext(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+        view fun test(_ s: String): Bool {
+            return s.trim().toUpper().contains("A")
+                && s.split(separator: ",").length > 1
+                && String.join([s], separator: ",") != ""
+        }
+	`)
+
+	require.NoError(t, err)
+}
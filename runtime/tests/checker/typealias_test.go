@@ -0,0 +1,414 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/sema"
+	"github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestCheckTypeAlias(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("top-level", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          typealias Balances = {Address: UFix64}
+
+          let balances: Balances = {0x1: 1.0}
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.DictionaryType{
+				KeyType:   &sema.AddressType{},
+				ValueType: sema.UFix64Type,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "balances"),
+		)
+	})
+
+	t.Run("alias of alias", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          typealias A = Int
+          typealias B = [A?]
+
+          let b: B = [1, nil]
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.VariableSizedType{
+				Type: &sema.OptionalType{
+					Type: sema.IntType,
+				},
+			},
+			RequireGlobalValue(t, checker.Elaboration, "b"),
+		)
+	})
+
+	t.Run("composite", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          typealias Ref = &R
+
+          resource R {
+              fun test(): Ref {
+                  return &self as &R
+              }
+          }
+
+          fun test(r: Ref): Ref {
+              return r
+          }
+        `)
+		require.NoError(t, err)
+
+		rType := RequireGlobalType(t, checker.Elaboration, "R")
+
+		assert.Equal(t,
+			&sema.ReferenceType{
+				Type: rType,
+			},
+			RequireGlobalType(t, checker.Elaboration, "Ref"),
+		)
+	})
+
+	t.Run("interchangeable", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          typealias Amount = UFix64
+
+          fun add(_ a: Amount, _ b: UFix64): UFix64 {
+              let c: Amount = a + b
+              return c
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          typealias Amount = UFix64
+
+          let a: Amount = "1.0"
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("forward reference", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          typealias A = B
+          typealias B = Int
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.NotDeclaredError{}, errs[0])
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          typealias A = [A]
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.NotDeclaredError{}, errs[0])
+	})
+
+	t.Run("redeclaration", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          struct S {}
+
+          typealias S = Int
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.RedeclarationError{}, errs[0])
+	})
+
+	t.Run("invalid access modifier", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          priv typealias A = Int
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidAccessModifierError{}, errs[0])
+	})
+}
+
+func TestCheckContractTypeAlias(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("nested", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          contract C {
+
+              typealias Vault = R
+
+              typealias Vaults = {UInt64: Vault}
+
+              resource R {
+                  fun merge(_ other: @Vault): @Vault {
+                      destroy other
+                      return <-create R()
+                  }
+              }
+
+              let vaults: @Vaults
+
+              fun deposit(_ vault: @Vault) {
+                  self.vaults[0] <-! vault
+              }
+
+              init() {
+                  self.vaults <- {}
+              }
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("qualified", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          contract C {
+              typealias Amount = UFix64
+          }
+
+          let amount: C.Amount = 1.0
+        `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			sema.UFix64Type,
+			RequireGlobalValue(t, checker.Elaboration, "amount"),
+		)
+	})
+
+	t.Run("top-level alias in contract", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          typealias Amount = UFix64
+
+          contract C {
+              typealias Amounts = [Amount]
+
+              let amounts: Amounts
+
+              init() {
+                  self.amounts = [1.0]
+              }
+          }
+        `)
+		require.NoError(t, err)
+	})
+
+	t.Run("not visible outside", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          contract C {
+              typealias Amount = UFix64
+          }
+
+          let amount: Amount = 1.0
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.NotDeclaredError{}, errs[0])
+	})
+
+	t.Run("redeclaration", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          contract C {
+              struct S {}
+
+              typealias S = Int
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.RedeclarationError{}, errs[0])
+	})
+
+	t.Run("not a type requirement", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          contract interface CI {
+              struct S {}
+          }
+
+          contract C: CI {
+              struct T {}
+
+              typealias S = T
+          }
+        `)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.ConformanceError{}, errs[0])
+	})
+}
+
+func TestCheckInvalidNestedTypeAlias(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(t *testing.T, code string, containerKind common.DeclarationKind) {
+		_, err := ParseAndCheck(t, code)
+
+		errs := ExpectCheckerErrors(t, err, 1)
+
+		require.IsType(t, &sema.InvalidNestedDeclarationError{}, errs[0])
+		nestedDeclarationError := errs[0].(*sema.InvalidNestedDeclarationError)
+
+		assert.Equal(t,
+			common.DeclarationKindTypeAlias,
+			nestedDeclarationError.NestedDeclarationKind,
+		)
+		assert.Equal(t,
+			containerKind,
+			nestedDeclarationError.ContainerDeclarationKind,
+		)
+	}
+
+	t.Run("struct", func(t *testing.T) {
+
+		t.Parallel()
+
+		test(t,
+			`
+              struct S {
+                  typealias A = Int
+              }
+            `,
+			common.DeclarationKindStructure,
+		)
+	})
+
+	t.Run("contract interface", func(t *testing.T) {
+
+		t.Parallel()
+
+		test(t,
+			`
+              contract interface CI {
+                  typealias A = Int
+              }
+            `,
+			common.DeclarationKindContractInterface,
+		)
+	})
+}
+
+func TestCheckImportTypeAlias(t *testing.T) {
+
+	t.Parallel()
+
+	importedChecker, err := ParseAndCheckWithOptions(t,
+		`
+          pub typealias Amount = UFix64
+        `,
+		ParseAndCheckOptions{
+			Location: utils.ImportedLocation,
+		},
+	)
+	require.NoError(t, err)
+
+	checker, err := ParseAndCheckWithOptions(t,
+		`
+          import Amount from "imported"
+
+          let amount: Amount = 1.0
+        `,
+		ParseAndCheckOptions{
+			Options: []sema.Option{
+				sema.WithImportHandler(
+					func(_ *sema.Checker, _ common.Location, _ ast.Range) (sema.Import, error) {
+						return sema.ElaborationImport{
+							Elaboration: importedChecker.Elaboration,
+						}, nil
+					},
+				),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		sema.UFix64Type,
+		RequireGlobalValue(t, checker.Elaboration, "amount"),
+	)
+}
@@ -323,6 +323,190 @@ func TestInterpretAuthAccount_type(t *testing.T) {
 	})
 }
 
+func TestInterpretAuthAccount_check(t *testing.T) {
+
+	t.Parallel()
+
+	address := interpreter.NewUnmeteredAddressValueFromBytes([]byte{42})
+
+	inter, getAccountValues := testAccount(
+		t,
+		address,
+		true,
+		`
+          resource interface I {}
+
+          resource R: I {}
+
+          struct S {}
+
+          fun save() {
+              account.save(<-create R(), to: /storage/r)
+          }
+
+          fun check(): [Bool] {
+              return [
+                  account.check<@R>(from: /storage/r),
+                  account.check<@{I}>(from: /storage/r),
+                  account.check<@AnyResource>(from: /storage/r),
+                  account.check<S>(from: /storage/r),
+                  account.check<@R>(from: /storage/other)
+              ]
+          }
+        `,
+	)
+
+	value, err := inter.Invoke("check")
+	require.NoError(t, err)
+	assert.Equal(t, "[false, false, false, false, false]", value.String())
+
+	_, err = inter.Invoke("save")
+	require.NoError(t, err)
+
+	value, err = inter.Invoke("check")
+	require.NoError(t, err)
+	assert.Equal(t, "[true, true, true, false, false]", value.String())
+
+	// The stored value is not modified
+
+	require.Len(t, getAccountValues(), 1)
+}
+
+func TestInterpretAuthAccount_swap(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		address := interpreter.NewUnmeteredAddressValueFromBytes([]byte{42})
+
+		inter, getAccountValues := testAccount(
+			t,
+			address,
+			true,
+			`
+              resource R {
+                  let id: Int
+
+                  init(id: Int) {
+                      self.id = id
+                  }
+              }
+
+              fun swap(id: Int): Int? {
+                  let old <- account.swap(<-create R(id: id), at: /storage/r)
+                  let oldID = old?.id
+                  destroy old
+                  return oldID
+              }
+
+              fun storedID(): Int {
+                  return account.borrow<&R>(from: /storage/r)!.id
+              }
+            `,
+		)
+
+		// Nothing is stored yet
+
+		value, err := inter.Invoke("swap", interpreter.NewUnmeteredIntValueFromInt64(1))
+		require.NoError(t, err)
+		assert.Equal(t, interpreter.NilValue{}, value)
+
+		require.Len(t, getAccountValues(), 1)
+
+		// The stored value is replaced and returned
+
+		value, err = inter.Invoke("swap", interpreter.NewUnmeteredIntValueFromInt64(2))
+		require.NoError(t, err)
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredSomeValueNonCopying(
+				interpreter.NewUnmeteredIntValueFromInt64(1),
+			),
+			value,
+		)
+
+		require.Len(t, getAccountValues(), 1)
+
+		value, err = inter.Invoke("storedID")
+		require.NoError(t, err)
+		AssertValuesEqual(t, inter, interpreter.NewUnmeteredIntValueFromInt64(2), value)
+	})
+
+	t.Run("struct", func(t *testing.T) {
+
+		t.Parallel()
+
+		address := interpreter.NewUnmeteredAddressValueFromBytes([]byte{42})
+
+		inter, _ := testAccount(
+			t,
+			address,
+			true,
+			`
+              fun test(): [Int?] {
+                  let first = account.swap([1, 2], at: /storage/s)
+                  let second = account.swap([3], at: /storage/s)
+                  return [first?.length, second?.length, account.copy<[Int]>(from: /storage/s)?.length]
+              }
+            `,
+		)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+		assert.Equal(t, "[nil, 2, 1]", value.String())
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		address := interpreter.NewUnmeteredAddressValueFromBytes([]byte{42})
+
+		inter, getAccountValues := testAccount(
+			t,
+			address,
+			true,
+			`
+              resource R {}
+
+              resource Other {}
+
+              fun save() {
+                  account.save(<-create R(), to: /storage/r)
+              }
+
+              fun swap() {
+                  destroy account.swap(<-create Other(), at: /storage/r)
+              }
+
+              fun check(): Bool {
+                  return account.check<@R>(from: /storage/r)
+              }
+            `,
+		)
+
+		_, err := inter.Invoke("save")
+		require.NoError(t, err)
+
+		_, err = inter.Invoke("swap")
+		require.Error(t, err)
+
+		require.ErrorAs(t, err, &interpreter.ForceCastTypeMismatchError{})
+
+		// The storage was not modified
+
+		require.Len(t, getAccountValues(), 1)
+
+		value, err := inter.Invoke("check")
+		require.NoError(t, err)
+		AssertValuesEqual(t, inter, interpreter.BoolValue(true), value)
+	})
+}
+
 func TestInterpretAuthAccount_load(t *testing.T) {
 
 	t.Parallel()
@@ -1891,3 +2075,273 @@ func TestInterpretAccount_StorageFields(t *testing.T) {
 		}
 	}
 }
+
+func TestInterpretAccount_StoragePaths(t *testing.T) {
+
+	t.Parallel()
+
+	address := interpreter.NewUnmeteredAddressValueFromBytes([]byte{42})
+
+	inter, _ := testAccount(
+		t,
+		address,
+		true,
+		`
+          resource R {}
+
+          struct S {}
+
+          fun setup() {
+              authAccount.save(<-create R(), to: /storage/r)
+              authAccount.save(S(), to: /storage/s)
+              authAccount.link<&R>(/public/r, target: /storage/r)
+              authAccount.link<&S>(/private/s, target: /storage/s)
+          }
+
+          fun storagePaths(): [StoragePath] {
+              return authAccount.storagePaths
+          }
+
+          fun publicPaths(): [PublicPath] {
+              return authAccount.publicPaths
+          }
+
+          fun privatePaths(): [PrivatePath] {
+              return authAccount.privatePaths
+          }
+
+          fun publicAccountPublicPaths(): [PublicPath] {
+              return pubAccount.publicPaths
+          }
+        `,
+	)
+
+	requirePaths := func(functionName string, expected ...string) {
+		value, err := inter.Invoke(functionName)
+		require.NoError(t, err)
+
+		require.IsType(t, &interpreter.ArrayValue{}, value)
+		array := value.(*interpreter.ArrayValue)
+
+		var paths []string
+		array.Iterate(inter, func(element interpreter.Value) (resume bool) {
+			paths = append(paths, element.String())
+			return true
+		})
+
+		assert.ElementsMatch(t, expected, paths, functionName)
+	}
+
+	// Initially, the account stores nothing
+
+	requirePaths("storagePaths")
+	requirePaths("publicPaths")
+	requirePaths("privatePaths")
+
+	_, err := inter.Invoke("setup")
+	require.NoError(t, err)
+
+	requirePaths("storagePaths", "/storage/r", "/storage/s")
+	requirePaths("publicPaths", "/public/r")
+	requirePaths("privatePaths", "/private/s")
+	requirePaths("publicAccountPublicPaths", "/public/r")
+}
+
+func TestInterpretAccount_forEach(t *testing.T) {
+
+	t.Parallel()
+
+	const setupCode = `
+      resource R {}
+
+      struct S {}
+
+      fun setup() {
+          authAccount.save(<-create R(), to: /storage/r)
+          authAccount.save(S(), to: /storage/s1)
+          authAccount.save(S(), to: /storage/s2)
+          authAccount.link<&R>(/public/r, target: /storage/r)
+          authAccount.link<&S>(/private/s, target: /storage/s1)
+      }
+    `
+
+	newInterpreter := func(t *testing.T, code string) *interpreter.Interpreter {
+		address := interpreter.NewUnmeteredAddressValueFromBytes([]byte{42})
+
+		inter, _ := testAccount(t, address, true, setupCode+code)
+
+		_, err := inter.Invoke("setup")
+		require.NoError(t, err)
+
+		return inter
+	}
+
+	t.Run("stored", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := newInterpreter(t, `
+          fun test(): [Int] {
+              var resources = 0
+              var structs = 0
+              authAccount.forEachStored(fun (path: StoragePath, type: Type): Bool {
+                  if type == Type<@R>() {
+                      resources = resources + 1
+                  } else if type == Type<S>() {
+                      structs = structs + 1
+                  }
+                  return true
+              })
+              return [resources, structs]
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t, "[1, 2]", value.String())
+	})
+
+	t.Run("capabilities", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := newInterpreter(t, `
+          fun test(): [Bool] {
+              var publicType: Type? = nil
+              authAccount.forEachPublic(fun (path: PublicPath, type: Type): Bool {
+                  publicType = type
+                  return true
+              })
+
+              var pubAccountType: Type? = nil
+              pubAccount.forEachPublic(fun (path: PublicPath, type: Type): Bool {
+                  pubAccountType = type
+                  return true
+              })
+
+              var privatePath: PrivatePath? = nil
+              var privateType: Type? = nil
+              authAccount.forEachPrivate(fun (path: PrivatePath, type: Type): Bool {
+                  privatePath = path
+                  privateType = type
+                  return true
+              })
+
+              return [
+                  publicType == Type<Capability<&R>>(),
+                  pubAccountType == Type<Capability<&R>>(),
+                  privatePath != nil,
+                  privateType == Type<Capability<&S>>()
+              ]
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t, "[true, true, true, true]", value.String())
+	})
+
+	t.Run("stop", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := newInterpreter(t, `
+          fun test(): Int {
+              var count = 0
+              authAccount.forEachStored(fun (path: StoragePath, type: Type): Bool {
+                  count = count + 1
+                  return false
+              })
+              return count
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(t, inter, interpreter.NewUnmeteredIntValueFromInt64(1), value)
+	})
+
+	t.Run("mutation, continue", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := newInterpreter(t, `
+          fun test() {
+              authAccount.forEachStored(fun (path: StoragePath, type: Type): Bool {
+                  authAccount.save(S(), to: /storage/new)
+                  return true
+              })
+          }
+        `)
+
+		_, err := inter.Invoke("test")
+		require.Error(t, err)
+
+		require.ErrorAs(t, err, &interpreter.StorageMutatedDuringIterationError{})
+	})
+
+	t.Run("mutation, stop", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := newInterpreter(t, `
+          fun test(): Bool {
+              authAccount.forEachStored(fun (path: StoragePath, type: Type): Bool {
+                  destroy authAccount.load<@R>(from: /storage/r)
+                  return false
+              })
+              return authAccount.type(at: /storage/r) == nil
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(t, inter, interpreter.BoolValue(true), value)
+	})
+
+	t.Run("mutation in nested iteration", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := newInterpreter(t, `
+          fun test() {
+              authAccount.forEachStored(fun (path: StoragePath, type: Type): Bool {
+                  authAccount.forEachPublic(fun (path: PublicPath, type: Type): Bool {
+                      authAccount.unlink(/public/r)
+                      return false
+                  })
+                  return true
+              })
+          }
+        `)
+
+		_, err := inter.Invoke("test")
+		require.Error(t, err)
+
+		require.ErrorAs(t, err, &interpreter.StorageMutatedDuringIterationError{})
+	})
+
+	t.Run("mutation after iteration", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := newInterpreter(t, `
+          fun test() {
+              authAccount.forEachStored(fun (path: StoragePath, type: Type): Bool {
+                  return true
+              })
+              authAccount.save(S(), to: /storage/new)
+              authAccount.forEachStored(fun (path: StoragePath, type: Type): Bool {
+                  return true
+              })
+          }
+        `)
+
+		_, err := inter.Invoke("test")
+		require.NoError(t, err)
+	})
+}
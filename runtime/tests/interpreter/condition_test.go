@@ -141,6 +141,45 @@ func TestInterpretFunctionWithoutResultAndPostConditionWithResult(t *testing.T)
 	)
 }
 
+func TestInterpretFunctionPreConditionWithViewFunction(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      view fun isPositive(_ x: Int): Bool {
+          var positive = false
+          if x > 0 {
+              positive = true
+          }
+          return positive
+      }
+
+      fun test(x: Int): Int {
+          pre {
+              isPositive(x)
+          }
+          return x
+      }
+    `)
+
+	_, err := inter.Invoke(
+		"test",
+		interpreter.NewUnmeteredIntValueFromInt64(0),
+	)
+	var conditionErr interpreter.ConditionError
+	require.ErrorAs(t, err, &conditionErr)
+
+	value, err := inter.Invoke("test", interpreter.NewUnmeteredIntValueFromInt64(42))
+	require.NoError(t, err)
+
+	AssertValuesEqual(
+		t,
+		inter,
+		interpreter.NewUnmeteredIntValueFromInt64(42),
+		value,
+	)
+}
+
 func TestInterpretFunctionPostConditionWithBefore(t *testing.T) {
 
 	t.Parallel()
@@ -1068,6 +1107,7 @@ func TestInterpretFunctionWithPostConditionAndResourceResult(t *testing.T) {
 	// and not a resource (composite value)
 
 	checkFunctionType := &sema.FunctionType{
+		Purity: sema.FunctionPurityView,
 		Parameters: []*sema.Parameter{
 			{
 				Label:      sema.ArgumentLabelNotRequired,
@@ -1125,7 +1165,7 @@ func TestInterpretFunctionWithPostConditionAndResourceResult(t *testing.T) {
                   return <- self.resources.remove(key: "original")!
               }
 
-              fun use(_ r: &R): Bool {
+              view fun use(_ r: &R): Bool {
                   check(r)
                   return true
               }
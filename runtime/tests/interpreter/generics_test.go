@@ -0,0 +1,385 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/interpreter"
+	. "github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestInterpretGenericFunction(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("identity", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun identity<T>(_ value: T): T {
+              return value
+          }
+
+          let a = identity(1)
+          let b = identity<String>("b")
+        `)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredIntValueFromInt64(1),
+			inter.Globals["a"].GetValue(),
+		)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredStringValue("b"),
+			inter.Globals["b"].GetValue(),
+		)
+	})
+
+	t.Run("array literal", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun pair<T>(_ first: T, _ second: T): [T] {
+              let values: [T] = [first, second]
+              return values
+          }
+
+          let values = pair("a", "b")
+        `)
+
+		value := inter.Globals["values"].GetValue()
+		require.IsType(t, &interpreter.ArrayValue{}, value)
+
+		assert.Equal(t,
+			interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeString,
+			},
+			value.(*interpreter.ArrayValue).Type,
+		)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewArrayValue(
+				inter,
+				interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeString,
+				},
+				common.Address{},
+				interpreter.NewUnmeteredStringValue("a"),
+				interpreter.NewUnmeteredStringValue("b"),
+			),
+			value,
+		)
+	})
+
+	t.Run("dynamic cast", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun cast<T>(_ value: AnyStruct): T? {
+              return value as? T
+          }
+
+          let a = cast<Int>(1)
+          let b = cast<String>(1)
+        `)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredSomeValueNonCopying(
+				interpreter.NewUnmeteredIntValueFromInt64(1),
+			),
+			inter.Globals["a"].GetValue(),
+		)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NilValue{},
+			inter.Globals["b"].GetValue(),
+		)
+	})
+
+	t.Run("nested generic invocation", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun single<T>(_ value: T): [T] {
+              return [value]
+          }
+
+          fun nested<U>(_ value: U): [[U]] {
+              return [single<U>(value)]
+          }
+
+          let type = nested(true).getType()
+        `)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.TypeValue{
+				Type: interpreter.VariableSizedStaticType{
+					Type: interpreter.VariableSizedStaticType{
+						Type: interpreter.PrimitiveStaticTypeBool,
+					},
+				},
+			},
+			inter.Globals["type"].GetValue(),
+		)
+	})
+
+	t.Run("closure", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun constant<T>(_ value: T): ((): [T]) {
+              return fun (): [T] {
+                  return [value]
+              }
+          }
+
+          let type = constant(1 as UInt8)().getType()
+        `)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.TypeValue{
+				Type: interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeUInt8,
+				},
+			},
+			inter.Globals["type"].GetValue(),
+		)
+	})
+}
+
+func TestInterpretGenericComposite(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("struct", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          struct Box<T> {
+              let value: T
+
+              init(value: T) {
+                  self.value = value
+              }
+
+              fun get(): T {
+                  return self.value
+              }
+
+              fun map<U>(_ f: ((T): U)): Box<U> {
+                  return Box<U>(value: f(self.value))
+              }
+
+              fun wrap(): [T] {
+                  return [self.value]
+              }
+          }
+
+          let box = Box(value: 1)
+          let value = box.get()
+          let mapped = box.map<String>(fun (_ value: Int): String {
+              return value.toString()
+          })
+          let mappedValue = mapped.get()
+          let wrappedType = box.wrap().getType()
+        `)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredIntValueFromInt64(1),
+			inter.Globals["value"].GetValue(),
+		)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredStringValue("1"),
+			inter.Globals["mappedValue"].GetValue(),
+		)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.TypeValue{
+				Type: interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeInt,
+				},
+			},
+			inter.Globals["wrappedType"].GetValue(),
+		)
+
+		box := inter.Globals["box"].GetValue()
+		require.IsType(t, &interpreter.CompositeValue{}, box)
+
+		boxStaticType := box.StaticType(inter)
+		require.IsType(t, interpreter.CompositeStaticType{}, boxStaticType)
+
+		assert.Equal(t,
+			common.TypeID("S.test.Box<Int>"),
+			boxStaticType.(interpreter.CompositeStaticType).TypeID,
+		)
+		assert.Equal(t,
+			[]interpreter.StaticType{
+				interpreter.PrimitiveStaticTypeInt,
+			},
+			boxStaticType.(interpreter.CompositeStaticType).TypeArguments(),
+		)
+
+		mappedStaticType := inter.Globals["mapped"].GetValue().StaticType(inter)
+		assert.Equal(t,
+			common.TypeID("S.test.Box<String>"),
+			mappedStaticType.(interpreter.CompositeStaticType).TypeID,
+		)
+	})
+
+	t.Run("run-time type", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          struct Box<T> {
+              let value: T
+
+              init(value: T) {
+                  self.value = value
+              }
+          }
+
+          let box: AnyStruct = Box(value: [1])
+          let identifier = box.getType().identifier
+          let isIntArrayBox = box.isInstance(Type<Box<[Int]>>())
+          let isStringBox = box.isInstance(Type<Box<String>>())
+          let castBox = box as? Box<[Int]>
+          let invalidCastBox = box as? Box<[String]>
+        `)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredStringValue("S.test.Box<[Int]>"),
+			inter.Globals["identifier"].GetValue(),
+		)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.BoolValue(true),
+			inter.Globals["isIntArrayBox"].GetValue(),
+		)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.BoolValue(false),
+			inter.Globals["isStringBox"].GetValue(),
+		)
+
+		require.IsType(t,
+			&interpreter.SomeValue{},
+			inter.Globals["castBox"].GetValue(),
+		)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NilValue{},
+			inter.Globals["invalidCastBox"].GetValue(),
+		)
+	})
+
+	t.Run("resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          resource R {
+              let id: Int
+
+              init(id: Int) {
+                  self.id = id
+              }
+          }
+
+          resource Holder<T: AnyResource> {
+              var contents: @[T]
+
+              init() {
+                  self.contents <- []
+              }
+
+              fun add(_ resource: @T) {
+                  self.contents.append(<-resource)
+              }
+
+              fun count(): Int {
+                  return self.contents.length
+              }
+
+              destroy() {
+                  destroy self.contents
+              }
+          }
+
+          fun test(): Int {
+              let holder <- create Holder<@R>()
+              holder.add(<-create R(id: 1))
+              holder.add(<-create R(id: 2))
+              let count = holder.count()
+              destroy holder
+              return count
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredIntValueFromInt64(2),
+			result,
+		)
+	})
+}
@@ -5608,6 +5608,502 @@ func TestInterpretDictionaryContainsKey(t *testing.T) {
 	)
 }
 
+func TestInterpretArrayHigherOrderFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	intValues := func(values ...int64) []interpreter.Value {
+		result := make([]interpreter.Value, len(values))
+		for i, value := range values {
+			result[i] = interpreter.NewUnmeteredIntValueFromInt64(value)
+		}
+		return result
+	}
+
+	t.Run("forEach", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): Int {
+              var sum = 0
+              [1, 2, 3].forEach(fun (x: Int) {
+                  sum = sum + x
+              })
+              return sum
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredIntValueFromInt64(6),
+			value,
+		)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [Int] {
+              return [1, 2, 3, 4].filter(view fun (x: Int): Bool {
+                  return x % 2 == 0
+              })
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			intValues(2, 4),
+			arrayElements(inter, value.(*interpreter.ArrayValue)),
+		)
+	})
+
+	t.Run("map", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [Int] {
+              return [1, 2, 3].map(fun (x: Int): Int {
+                  return x * 2
+              })
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			intValues(2, 4, 6),
+			arrayElements(inter, value.(*interpreter.ArrayValue)),
+		)
+	})
+
+	t.Run("map, boxing", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [Int?] {
+              let f: ((Int): Int?) = fun (x: Int): Int {
+                  return x
+              }
+              return [1, 2].map(f)
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]interpreter.Value{
+				interpreter.NewUnmeteredSomeValueNonCopying(
+					interpreter.NewUnmeteredIntValueFromInt64(1),
+				),
+				interpreter.NewUnmeteredSomeValueNonCopying(
+					interpreter.NewUnmeteredIntValueFromInt64(2),
+				),
+			},
+			arrayElements(inter, value.(*interpreter.ArrayValue)),
+		)
+	})
+
+	t.Run("map, constant-sized", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [Bool; 2] {
+              let xs: [Int; 2] = [1, 2]
+              return xs.map(fun (x: Int): Bool {
+                  return x > 1
+              })
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		arrayValue := value.(*interpreter.ArrayValue)
+
+		assert.Equal(t,
+			interpreter.ConstantSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeBool,
+				Size: 2,
+			},
+			arrayValue.Type,
+		)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]interpreter.Value{
+				interpreter.BoolValue(false),
+				interpreter.BoolValue(true),
+			},
+			arrayElements(inter, arrayValue),
+		)
+	})
+
+	t.Run("reverse", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          let xs = [1, 2, 3]
+          let ys = xs.reverse()
+        `)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			intValues(3, 2, 1),
+			arrayElements(inter, inter.Globals["ys"].GetValue().(*interpreter.ArrayValue)),
+		)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			intValues(1, 2, 3),
+			arrayElements(inter, inter.Globals["xs"].GetValue().(*interpreter.ArrayValue)),
+		)
+	})
+
+	t.Run("sort", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          let xs = ["bb", "a", "cc", "d"]
+          let ys = xs.sort(by: view fun (a: String, b: String): Bool {
+              return a.length < b.length
+          })
+        `)
+
+		// The sort is stable
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]interpreter.Value{
+				interpreter.NewUnmeteredStringValue("a"),
+				interpreter.NewUnmeteredStringValue("d"),
+				interpreter.NewUnmeteredStringValue("bb"),
+				interpreter.NewUnmeteredStringValue("cc"),
+			},
+			arrayElements(inter, inter.Globals["ys"].GetValue().(*interpreter.ArrayValue)),
+		)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]interpreter.Value{
+				interpreter.NewUnmeteredStringValue("bb"),
+				interpreter.NewUnmeteredStringValue("a"),
+				interpreter.NewUnmeteredStringValue("cc"),
+				interpreter.NewUnmeteredStringValue("d"),
+			},
+			arrayElements(inter, inter.Globals["xs"].GetValue().(*interpreter.ArrayValue)),
+		)
+	})
+
+	t.Run("reduce", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): String {
+              return [1, 2, 3].reduce(initial: "0", fun (acc: String, x: Int): String {
+                  return acc.concat(x.toString())
+              })
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredStringValue("0123"),
+			value,
+		)
+	})
+
+	t.Run("filter copies structs", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          struct S {
+              var x: Int
+
+              init(x: Int) {
+                  self.x = x
+              }
+          }
+
+          fun test(): Int {
+              let xs = [S(x: 1), S(x: 2)]
+              let ys = xs.filter(view fun (s: S): Bool {
+                  return s.x > 1
+              })
+              ys[0].x = 3
+              return xs[1].x
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredIntValueFromInt64(2),
+			value,
+		)
+	})
+
+	t.Run("mutation during iteration", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test() {
+              let xs = [1, 2, 3]
+              xs.forEach(fun (x: Int) {
+                  xs.append(x)
+              })
+          }
+        `)
+
+		_, err := inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.ContainerMutatedDuringIterationError{})
+	})
+
+	t.Run("mutation after iteration", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): Int {
+              let xs = [1, 2, 3]
+              xs.forEach(fun (x: Int) {})
+              xs.append(4)
+              return xs.length
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredIntValueFromInt64(4),
+			value,
+		)
+	})
+
+	t.Run("computation metering", func(t *testing.T) {
+
+		t.Parallel()
+
+		var loops uint
+
+		inter, err := parseCheckAndInterpretWithOptions(t,
+			`
+              fun test(): [Int] {
+                  return [1, 2, 3, 4].map(fun (x: Int): Int {
+                      return x
+                  })
+              }
+            `,
+			ParseCheckAndInterpretOptions{
+				Options: []interpreter.Option{
+					interpreter.WithOnMeterComputationFuncHandler(
+						func(compKind common.ComputationKind, intensity uint) {
+							if compKind == common.ComputationKindLoop {
+								loops += intensity
+							}
+						},
+					),
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		_, err = inter.Invoke("test")
+		require.NoError(t, err)
+
+		assert.Equal(t, uint(4), loops)
+	})
+}
+
+func TestInterpretDictionaryHigherOrderFunctions(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("forEachKey", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): Int {
+              var count = 0
+              {"a": 1, "b": 2, "c": 3}.forEachKey(fun (key: String): Bool {
+                  count = count + 1
+                  return count < 2
+              })
+              return count
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredIntValueFromInt64(2),
+			value,
+		)
+	})
+
+	t.Run("forEachKey, resource values", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          resource R {}
+
+          fun keys(_ rs: &{String: R}): [String] {
+              let keys: [String] = []
+              rs.forEachKey(fun (key: String): Bool {
+                  keys.append(key)
+                  return true
+              })
+              return keys
+          }
+
+          fun test(): [String] {
+              let rs <- {"a": <-create R()}
+              let keys = keys(&rs as &{String: R})
+              destroy rs
+              return keys
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]interpreter.Value{
+				interpreter.NewUnmeteredStringValue("a"),
+			},
+			arrayElements(inter, value.(*interpreter.ArrayValue)),
+		)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [AnyStruct] {
+              let xs = {"a": 1, "b": 2, "c": 3}
+              let ys = xs.filter(view fun (key: String, value: Int): Bool {
+                  return value > 1
+              })
+              return [ys.length, ys["a"], ys["b"], xs.length]
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]interpreter.Value{
+				interpreter.NewUnmeteredIntValueFromInt64(2),
+				interpreter.NilValue{},
+				interpreter.NewUnmeteredSomeValueNonCopying(
+					interpreter.NewUnmeteredIntValueFromInt64(2),
+				),
+				interpreter.NewUnmeteredIntValueFromInt64(3),
+			},
+			arrayElements(inter, value.(*interpreter.ArrayValue)),
+		)
+	})
+
+	t.Run("map", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [String?] {
+              let xs = {"a": 1, "b": 2}
+              let ys = xs.map(fun (key: String, value: Int): String {
+                  return key.concat(value.toString())
+              })
+              return [ys["a"], ys["b"]]
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValueSlicesEqual(
+			t,
+			inter,
+			[]interpreter.Value{
+				interpreter.NewUnmeteredSomeValueNonCopying(
+					interpreter.NewUnmeteredStringValue("a1"),
+				),
+				interpreter.NewUnmeteredSomeValueNonCopying(
+					interpreter.NewUnmeteredStringValue("b2"),
+				),
+			},
+			arrayElements(inter, value.(*interpreter.ArrayValue)),
+		)
+	})
+
+	t.Run("mutation during iteration", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test() {
+              let xs = {"a": 1, "b": 2}
+              xs.forEachKey(fun (key: String): Bool {
+                  xs.remove(key: key)
+                  return true
+              })
+          }
+        `)
+
+		_, err := inter.Invoke("test")
+		require.ErrorAs(t, err, &interpreter.ContainerMutatedDuringIterationError{})
+	})
+}
+
 func TestInterpretStringConcat(t *testing.T) {
 
 	t.Parallel()
@@ -9642,6 +10138,7 @@ func TestHostFunctionStaticType(t *testing.T) {
 			interpreter.ConvertSemaToStaticType(
 				nil,
 				&sema.FunctionType{
+					Purity:               sema.FunctionPurityView,
 					ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.MetaType),
 				},
 			),
@@ -703,8 +703,8 @@ func TestInterpretSimpleCompositeMetering(t *testing.T) {
 		require.NoError(t, err)
 
 		assert.Equal(t, uint64(1), meter.getMemory(common.MemoryKindSimpleCompositeValueBase))
-		// AuthAccount has 18 fields
-		assert.Equal(t, uint64(18), meter.getMemory(common.MemoryKindSimpleCompositeValue))
+		// AuthAccount has 27 fields
+		assert.Equal(t, uint64(27), meter.getMemory(common.MemoryKindSimpleCompositeValue))
 	})
 
 	t.Run("public account", func(t *testing.T) {
@@ -723,8 +723,8 @@ func TestInterpretSimpleCompositeMetering(t *testing.T) {
 		require.NoError(t, err)
 
 		assert.Equal(t, uint64(1), meter.getMemory(common.MemoryKindSimpleCompositeValueBase))
-		// PublicAccount has 9 fields
-		assert.Equal(t, uint64(9), meter.getMemory(common.MemoryKindSimpleCompositeValue))
+		// PublicAccount has 11 fields
+		assert.Equal(t, uint64(11), meter.getMemory(common.MemoryKindSimpleCompositeValue))
 	})
 }
 
@@ -7404,6 +7404,110 @@ func TestInterpretStringMetering(t *testing.T) {
 		// + result: 1 + 4 (max UTF8 encoding)
 		assert.Equal(t, uint64(10), meter.getMemory(common.MemoryKindStringValue))
 	})
+
+	t.Run("template", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+            pub fun main() {
+                let a = "b"
+                let x = "a\(a)c"
+            }
+        `
+		meter := newTestMemoryGauge()
+		inter := parseCheckAndInterpretWithMemoryMetering(t, script, meter)
+
+		_, err := inter.Invoke("main")
+		require.NoError(t, err)
+
+		// creation: 1 + 2 * " + 1 (b)
+		// + template head: 1 + " + 3 (a\()
+		// + template tail: 1 + " + 2 ()c)
+		// + result: 1 + 3 (abc)
+		assert.Equal(t, uint64(17), meter.getMemory(common.MemoryKindStringValue))
+	})
+
+	t.Run("replaceAll", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+            pub fun main() {
+                let x = "a-b".replaceAll(of: "-", with: "+")
+            }
+        `
+		meter := newTestMemoryGauge()
+		inter := parseCheckAndInterpretWithMemoryMetering(t, script, meter)
+
+		_, err := inter.Invoke("main")
+		require.NoError(t, err)
+
+		// creation: 1 + 2 * " + 3 (a-b)
+		// + creation: 1 + 2 * " + 1 (-)
+		// + creation: 1 + 2 * " + 1 (+)
+		// + result: 1 + 3 (a+b)
+		assert.Equal(t, uint64(18), meter.getMemory(common.MemoryKindStringValue))
+	})
+
+	t.Run("join", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+            pub fun main() {
+                let x = String.join(["a", "b"], separator: ",")
+            }
+        `
+		meter := newTestMemoryGauge()
+		inter := parseCheckAndInterpretWithMemoryMetering(t, script, meter)
+
+		_, err := inter.Invoke("main")
+		require.NoError(t, err)
+
+		// creation: 3 * (1 + 2 * " + 1)
+		// + result: 1 + 3 (a,b)
+		assert.Equal(t, uint64(16), meter.getMemory(common.MemoryKindStringValue))
+	})
+
+	t.Run("trim", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+            pub fun main() {
+                let x = " ab ".trim()
+            }
+        `
+		meter := newTestMemoryGauge()
+		inter := parseCheckAndInterpretWithMemoryMetering(t, script, meter)
+
+		_, err := inter.Invoke("main")
+		require.NoError(t, err)
+
+		// creation: 1 + 2 * " + 4 ( ab )
+		// + result: 1 + 2 (ab)
+		assert.Equal(t, uint64(10), meter.getMemory(common.MemoryKindStringValue))
+	})
+
+	t.Run("contains", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+            pub fun main() {
+                let x = "abc".contains("b")
+            }
+        `
+		meter := newTestMemoryGauge()
+		inter := parseCheckAndInterpretWithMemoryMetering(t, script, meter)
+
+		_, err := inter.Invoke("main")
+		require.NoError(t, err)
+
+		// boundaries: 3 (abc) + 1 (end)
+		assert.Equal(t, uint64(4), meter.getMemory(common.MemoryKindStringGraphemeBoundaries))
+	})
 }
 
 func TestInterpretCharacterMetering(t *testing.T) {
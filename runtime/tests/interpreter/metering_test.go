@@ -422,3 +422,109 @@ func TestInterpretFunctionInvocationHandler(t *testing.T) {
 		occurrences,
 	)
 }
+
+func TestInterpretFunctionEntryHandler(t *testing.T) {
+
+	t.Parallel()
+
+	var lines []int
+
+	inter, err := parseCheckAndInterpretWithOptions(t,
+		`
+          pub struct S {
+              init() {}
+
+              pub fun a() {}
+          }
+
+          pub fun b() {
+              let f = fun () {}
+              f()
+              S().a()
+          }
+        `,
+		ParseCheckAndInterpretOptions{
+			Options: []interpreter.Option{
+				interpreter.WithOnFunctionEntryHandler(
+					func(_ *interpreter.Interpreter, functionBlock *ast.FunctionBlock) {
+						lines = append(lines, functionBlock.StartPosition().Line)
+					},
+				),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	_, err = inter.Invoke("b")
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{8, 9, 3, 5}, lines)
+}
+
+func TestInterpretBranchHandler(t *testing.T) {
+
+	t.Parallel()
+
+	type occurrence struct {
+		elementType ast.ElementType
+		branch      int
+	}
+
+	var occurrences []occurrence
+
+	inter, err := parseCheckAndInterpretWithOptions(t,
+		`
+          pub fun test(_ x: Int?) {
+              if let y = x {}
+              if x == nil {} else {}
+              switch x ?? 0 {
+                  case 1:
+                      return
+              }
+              let z = x == nil ? 1 : 2
+          }
+        `,
+		ParseCheckAndInterpretOptions{
+			Options: []interpreter.Option{
+				interpreter.WithOnBranchHandler(
+					func(_ *interpreter.Interpreter, element ast.Element, branch int) {
+						occurrences = append(occurrences, occurrence{
+							elementType: element.ElementType(),
+							branch:      branch,
+						})
+					},
+				),
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	_, err = inter.Invoke("test", interpreter.NilValue{})
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		[]occurrence{
+			{ast.ElementTypeIfStatement, 1},
+			{ast.ElementTypeIfStatement, 0},
+			{ast.ElementTypeBinaryExpression, 1},
+			{ast.ElementTypeSwitchStatement, 1},
+			{ast.ElementTypeConditionalExpression, 0},
+		},
+		occurrences,
+	)
+
+	occurrences = nil
+
+	_, err = inter.Invoke("test", interpreter.NewUnmeteredSomeValueNonCopying(interpreter.NewUnmeteredIntValueFromInt64(1)))
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		[]occurrence{
+			{ast.ElementTypeIfStatement, 0},
+			{ast.ElementTypeIfStatement, 1},
+			{ast.ElementTypeBinaryExpression, 0},
+			{ast.ElementTypeSwitchStatement, 0},
+		},
+		occurrences,
+	)
+}
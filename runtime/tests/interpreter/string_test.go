@@ -19,6 +19,7 @@
 package interpreter_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -341,3 +342,407 @@ func TestInterpretCompareCharacters(t *testing.T) {
 		inter.Globals["z"].GetValue(),
 	)
 }
+
+func TestInterpretStringTemplate(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(): String {
+          let name = "World"
+          let c: Character = "!"
+          let n = 3
+          let address: Address = 0x1
+          return "Hello, \(name)\(c) \(n * 2) \(1.5) \(-1) \(address) \(/storage/foo) \(true) \("\(n)") \"\(n)\""
+      }
+    `)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.NewUnmeteredStringValue(
+			`Hello, World! 6 1.50000000 -1 0x0000000000000001 /storage/foo true 3 "3"`,
+		),
+		result,
+	)
+}
+
+func TestInterpretStringToUpper(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(): String {
+          return "Flowers é".toUpper()
+      }
+    `)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.NewUnmeteredStringValue("FLOWERS É"),
+		result,
+	)
+}
+
+func TestInterpretStringContains(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(): [Bool] {
+          return [
+              "abcdef".contains("cde"),
+              "abcdef".contains("x"),
+              "abc".contains(""),
+              // "e" with combining acute accent is a single character
+              "e\u{301}".contains("e"),
+              "e\u{301}".contains("e\u{301}")
+          ]
+      }
+    `)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	RequireValuesEqual(
+		t,
+		inter,
+		interpreter.NewArrayValue(
+			inter,
+			interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeBool,
+			},
+			common.Address{},
+			interpreter.BoolValue(true),
+			interpreter.BoolValue(false),
+			interpreter.BoolValue(true),
+			interpreter.BoolValue(false),
+			interpreter.BoolValue(true),
+		),
+		result,
+	)
+}
+
+func TestInterpretStringIndexOf(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(): [Int] {
+          return [
+              "abcabc".index(of: "ca"),
+              "abc".index(of: "x"),
+              "abc".index(of: ""),
+              // the first "e" is part of a character with a combining mark,
+              // so it does not match
+              "\u{1F490}e\u{301}e".index(of: "e")
+          ]
+      }
+    `)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	RequireValuesEqual(
+		t,
+		inter,
+		interpreter.NewArrayValue(
+			inter,
+			interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeInt,
+			},
+			common.Address{},
+			interpreter.NewUnmeteredIntValueFromInt64(2),
+			interpreter.NewUnmeteredIntValueFromInt64(-1),
+			interpreter.NewUnmeteredIntValueFromInt64(0),
+			interpreter.NewUnmeteredIntValueFromInt64(2),
+		),
+		result,
+	)
+}
+
+func TestInterpretStringSplit(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(t *testing.T, code string, expected ...string) {
+
+		inter := parseCheckAndInterpret(t, fmt.Sprintf(
+			`
+              fun test(): [String] {
+                  return %s
+              }
+            `,
+			code,
+		))
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		values := make([]interpreter.Value, len(expected))
+		for i, value := range expected {
+			values[i] = interpreter.NewUnmeteredStringValue(value)
+		}
+
+		RequireValuesEqual(
+			t,
+			inter,
+			interpreter.NewArrayValue(
+				inter,
+				interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeString,
+				},
+				common.Address{},
+				values...,
+			),
+			result,
+		)
+	}
+
+	t.Run("separator", func(t *testing.T) {
+		t.Parallel()
+
+		test(t, `"a, b,, c".split(separator: ", ")`, "a", "b,", "c")
+	})
+
+	t.Run("separator at start and end", func(t *testing.T) {
+		t.Parallel()
+
+		test(t, `",a,".split(separator: ",")`, "", "a", "")
+	})
+
+	t.Run("no occurrence", func(t *testing.T) {
+		t.Parallel()
+
+		test(t, `"abc".split(separator: ",")`, "abc")
+	})
+
+	t.Run("empty separator", func(t *testing.T) {
+		t.Parallel()
+
+		test(t, `"ae\u{301}\u{1F490}".split(separator: "")`, "a", "é", "\U0001F490")
+	})
+
+	t.Run("separator inside character", func(t *testing.T) {
+		t.Parallel()
+
+		test(t, `"ae\u{301}e".split(separator: "e")`, "aé", "")
+	})
+}
+
+func TestInterpretStringReplaceAll(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(): [String] {
+          return [
+              "a-b-c".replaceAll(of: "-", with: "--"),
+              "aaa".replaceAll(of: "aa", with: "b"),
+              "abc".replaceAll(of: "", with: "x"),
+              "e\u{301}e".replaceAll(of: "e", with: "x")
+          ]
+      }
+    `)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	RequireValuesEqual(
+		t,
+		inter,
+		interpreter.NewArrayValue(
+			inter,
+			interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeString,
+			},
+			common.Address{},
+			interpreter.NewUnmeteredStringValue("a--b--c"),
+			interpreter.NewUnmeteredStringValue("ba"),
+			interpreter.NewUnmeteredStringValue("abc"),
+			interpreter.NewUnmeteredStringValue("éx"),
+		),
+		result,
+	)
+}
+
+func TestInterpretStringTrim(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(): [String] {
+          return [
+              " \t abc def \n".trim(),
+              "abc".trim(),
+              "   ".trim(),
+              " \u{301}a ".trim()
+          ]
+      }
+    `)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	RequireValuesEqual(
+		t,
+		inter,
+		interpreter.NewArrayValue(
+			inter,
+			interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeString,
+			},
+			common.Address{},
+			interpreter.NewUnmeteredStringValue("abc def"),
+			interpreter.NewUnmeteredStringValue("abc"),
+			interpreter.NewUnmeteredStringValue(""),
+			// a space with a combining mark is not whitespace
+			interpreter.NewUnmeteredStringValue(" ́a"),
+		),
+		result,
+	)
+}
+
+func TestInterpretStringFunctionComputationMetering(t *testing.T) {
+
+	t.Parallel()
+
+	test := func(code string, expected uint) {
+
+		t.Run(code, func(t *testing.T) {
+
+			t.Parallel()
+
+			var scanned uint
+
+			inter, err := parseCheckAndInterpretWithOptions(t,
+				fmt.Sprintf(
+					`
+                      fun test() {
+                          %s
+                      }
+                    `,
+					code,
+				),
+				ParseCheckAndInterpretOptions{
+					Options: []interpreter.Option{
+						interpreter.WithOnMeterComputationFuncHandler(
+							func(compKind common.ComputationKind, intensity uint) {
+								if compKind == common.ComputationKindStringScan {
+									scanned += intensity
+								}
+							},
+						),
+					},
+				},
+			)
+			require.NoError(t, err)
+
+			_, err = inter.Invoke("test")
+			require.NoError(t, err)
+
+			require.Equal(t, expected, scanned)
+		})
+	}
+
+	// boundaries: 6, search: 6
+	test(`"abcabc".contains("c")`, 12)
+	// boundaries: 6, search: 6
+	test(`"abcabc".index(of: "c")`, 12)
+	// boundaries: 3, search: 3 + 1 (after the first occurrence)
+	test(`"a,b".split(separator: ",")`, 7)
+	// boundaries: 3, search: 3 + 1 (after the first occurrence)
+	test(`"a-b".replaceAll(of: "-", with: "+")`, 7)
+	// characters: 4
+	test(`" ab ".trim()`, 4)
+}
+
+func TestInterpretStringJoin(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(): [String] {
+          return [
+              String.join(["a", "b", "c"], separator: ", "),
+              String.join(["a"], separator: ", "),
+              String.join([], separator: ", ")
+          ]
+      }
+    `)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	RequireValuesEqual(
+		t,
+		inter,
+		interpreter.NewArrayValue(
+			inter,
+			interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeString,
+			},
+			common.Address{},
+			interpreter.NewUnmeteredStringValue("a, b, c"),
+			interpreter.NewUnmeteredStringValue("a"),
+			interpreter.NewUnmeteredStringValue(""),
+		),
+		result,
+	)
+}
+
+func TestInterpretStringFromCharacters(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(): String {
+          let characters: [Character] = ["a", "e\u{301}", "\u{1F490}"]
+          return String.fromCharacters(characters)
+      }
+    `)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.NewUnmeteredStringValue("aé\U0001F490"),
+		result,
+	)
+}
+
+func TestInterpretStringFromUTF8(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun testValid(): String? {
+          return String.fromUTF8("Flowers \u{1F490}".utf8)
+      }
+
+      fun testInvalid(): String? {
+          return String.fromUTF8([0xFF, 0xFE])
+      }
+    `)
+
+	result, err := inter.Invoke("testValid")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.NewUnmeteredSomeValueNonCopying(
+			interpreter.NewUnmeteredStringValue("Flowers \U0001F490"),
+		),
+		result,
+	)
+
+	result, err = inter.Invoke("testInvalid")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.NilValue{},
+		result,
+	)
+}
@@ -0,0 +1,121 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/interpreter"
+	. "github.com/onflow/cadence/runtime/tests/utils"
+)
+
+func TestInterpretTypeAlias(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("run-time type", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          typealias Balances = {Address: UFix64}
+
+          let result = Type<Balances>() == Type<{Address: UFix64}>()
+        `)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.BoolValue(true),
+			inter.Globals["result"].GetValue(),
+		)
+	})
+
+	t.Run("dynamic cast", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          typealias Amount = UFix64
+
+          let x: AnyStruct = 1.5
+          let result = (x as? Amount)! + 1.0
+        `)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredUFix64Value(250000000),
+			inter.Globals["result"].GetValue(),
+		)
+	})
+
+	t.Run("contract", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter, err := parseCheckAndInterpretWithOptions(t,
+			`
+              contract C {
+
+                  typealias Vault = R
+
+                  resource R {
+                      let balance: Int
+
+                      init(balance: Int) {
+                          self.balance = balance
+                      }
+                  }
+
+                  fun createVault(balance: Int): @Vault {
+                      return <-create R(balance: balance)
+                  }
+
+                  init() {}
+              }
+
+              fun test(): Int {
+                  let vault: @C.Vault <- C.createVault(balance: 42)
+                  let balance = vault.balance
+                  destroy vault
+                  return balance
+              }
+            `,
+			ParseCheckAndInterpretOptions{
+				Options: []interpreter.Option{
+					makeContractValueHandler(nil, nil, nil),
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredIntValueFromInt64(42),
+			result,
+		)
+	})
+}
@@ -0,0 +1,610 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/compiler/bytecode"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+// bytecodeVM is a stack-based VM which executes bytecode programs.
+//
+// Like the VMs for WebAssembly modules, it operates on interpreter values,
+// using the run-time functions, so programs behave like interpreted programs.
+//
+// Computation is metered using the computation metering of the interpreter:
+// loop iterations are reported for each jump to the start of a loop,
+// and function invocations are reported for each call.
+// Memory is metered by the interpreter, when values are created
+//
+type bytecodeVM struct {
+	program     *bytecode.Program
+	runtime     *runtimeFunctions
+	interpreter *interpreter.Interpreter
+	constants   []bytecodeConstant
+	exports     map[string]int
+	stack       []interpreter.Value
+	callDepth   int
+}
+
+// bytecodeConstant is a decoded constant of the program
+//
+type bytecodeConstant struct {
+	integer *big.Int
+	str     string
+	data    []byte
+	ty      sema.Type
+}
+
+var _ VM = &bytecodeVM{}
+
+// NewBytecodeVM returns a VM which executes the given bytecode program
+//
+func NewBytecodeVM(program *bytecode.Program, config *Config) (VM, error) {
+	m := &bytecodeVM{
+		program: program,
+		runtime: &runtimeFunctions{
+			config: config,
+		},
+		interpreter: config.Interpreter,
+		exports:     map[string]int{},
+	}
+
+	err := m.decodeConstants()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, function := range program.Functions {
+		m.exports[function.Name] = i
+	}
+
+	err = m.start()
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// start calls the start function of the program, if any,
+// which initializes the program, e.g. its global variables
+//
+func (m *bytecodeVM) start() (err error) {
+	functionIndex := m.program.StartFunctionIndex
+	if functionIndex == nil {
+		return nil
+	}
+
+	// Like in Invoke, the run-time functions report errors by panicking
+
+	defer m.interpreter.RecoverErrors(func(internalErr error) {
+		err = internalErr
+	})
+
+	m.stack = m.stack[:0]
+	m.callDepth = 0
+
+	_, err = m.call(m.program.Functions[*functionIndex])
+	return err
+}
+
+// decodeConstants decodes the constants of the program once,
+// so they do not have to be decoded each time they are used
+//
+func (m *bytecodeVM) decodeConstants() error {
+	m.constants = make([]bytecodeConstant, len(m.program.Constants))
+
+	for i, constant := range m.program.Constants {
+		decoded := &m.constants[i]
+
+		switch constant.Kind {
+		case bytecode.ConstantKindInt:
+			integer, err := bytecode.DecodeInteger(constant.Data)
+			if err != nil {
+				return err
+			}
+			decoded.integer = integer
+
+		case bytecode.ConstantKindString, bytecode.ConstantKindCharacter:
+			decoded.str = string(constant.Data)
+
+		case bytecode.ConstantKindAddress:
+			decoded.data = constant.Data
+
+		case bytecode.ConstantKindType:
+			ty, err := m.runtime.decodeType(constant.Data)
+			if err != nil {
+				return err
+			}
+			decoded.ty = ty
+
+		default:
+			return fmt.Errorf("invalid constant kind: %s", constant.Kind)
+		}
+	}
+
+	return nil
+}
+
+func (m *bytecodeVM) Invoke(name string, arguments ...interpreter.Value) (result interpreter.Value, err error) {
+
+	// Like the interpreter, the run-time functions report errors by panicking
+
+	defer m.interpreter.RecoverErrors(func(internalErr error) {
+		err = internalErr
+	})
+
+	functionIndex, ok := m.exports[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function: %s", name)
+	}
+
+	function := m.program.Functions[functionIndex]
+
+	if len(arguments) != int(function.ParameterCount) {
+		return nil, fmt.Errorf(
+			"invalid number of arguments for function %s: expected %d, got %d",
+			name,
+			function.ParameterCount,
+			len(arguments),
+		)
+	}
+
+	m.stack = append(m.stack[:0], arguments...)
+	m.callDepth = 0
+
+	result, err = m.call(function)
+	if err != nil {
+		return nil, err
+	}
+
+	if result == nil {
+		result = interpreter.NewVoidValue(m.interpreter)
+	}
+
+	return result, nil
+}
+
+// call calls the given function, the arguments of which are on the stack,
+// and returns the result, if any.
+//
+// Traps are returned as an error,
+// errors reported by run-time functions are propagated as panics
+//
+func (m *bytecodeVM) call(function *bytecode.Function) (result interpreter.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			trap, ok := r.(TrapError)
+			if !ok {
+				panic(r)
+			}
+			err = trap
+		}
+	}()
+
+	return m.callFunction(function), nil
+}
+
+// callFunction calls the given function.
+// The arguments are popped from the stack, and the result is returned, if any
+//
+func (m *bytecodeVM) callFunction(function *bytecode.Function) interpreter.Value {
+	m.callDepth++
+	if m.callDepth > maxCallDepth {
+		panic(TrapError{
+			Message: "call stack exhausted",
+		})
+	}
+
+	// The arguments become the first locals
+
+	locals := make([]interpreter.Value, function.LocalCount)
+	copy(locals, m.popValues(int(function.ParameterCount)))
+
+	result := m.execute(function, locals)
+
+	m.callDepth--
+
+	return result
+}
+
+func (m *bytecodeVM) push(value interpreter.Value) {
+	m.stack = append(m.stack, value)
+}
+
+func (m *bytecodeVM) pop() interpreter.Value {
+	lastIndex := len(m.stack) - 1
+	if lastIndex < 0 {
+		panic(errors.NewUnreachableError())
+	}
+	value := m.stack[lastIndex]
+	m.stack[lastIndex] = nil
+	m.stack = m.stack[:lastIndex]
+	return value
+}
+
+// popValues pops the given number of values.
+// The result is a new slice, so it can be retained
+//
+func (m *bytecodeVM) popValues(count int) []interpreter.Value {
+	stackHeight := len(m.stack) - count
+	if stackHeight < 0 {
+		panic(errors.NewUnreachableError())
+	}
+
+	values := make([]interpreter.Value, count)
+	copy(values, m.stack[stackHeight:])
+
+	for i := stackHeight; i < len(m.stack); i++ {
+		m.stack[i] = nil
+	}
+	m.stack = m.stack[:stackHeight]
+
+	return values
+}
+
+func operandAt(code []byte, offset int) int {
+	return int(code[offset])<<8 | int(code[offset+1])
+}
+
+// bytecodeBinaryOperations are the operations of the binary operation opcodes,
+// indexed by the offset of the opcode from OpcodeAdd
+//
+var bytecodeBinaryOperations = [...]ast.Operation{
+	bytecode.OpcodeAdd - bytecode.OpcodeAdd:               ast.OperationPlus,
+	bytecode.OpcodeSubtract - bytecode.OpcodeAdd:          ast.OperationMinus,
+	bytecode.OpcodeMultiply - bytecode.OpcodeAdd:          ast.OperationMul,
+	bytecode.OpcodeDivide - bytecode.OpcodeAdd:            ast.OperationDiv,
+	bytecode.OpcodeMod - bytecode.OpcodeAdd:               ast.OperationMod,
+	bytecode.OpcodeEqual - bytecode.OpcodeAdd:             ast.OperationEqual,
+	bytecode.OpcodeNotEqual - bytecode.OpcodeAdd:          ast.OperationNotEqual,
+	bytecode.OpcodeLess - bytecode.OpcodeAdd:              ast.OperationLess,
+	bytecode.OpcodeLessEqual - bytecode.OpcodeAdd:         ast.OperationLessEqual,
+	bytecode.OpcodeGreater - bytecode.OpcodeAdd:           ast.OperationGreater,
+	bytecode.OpcodeGreaterEqual - bytecode.OpcodeAdd:      ast.OperationGreaterEqual,
+	bytecode.OpcodeBitwiseOr - bytecode.OpcodeAdd:         ast.OperationBitwiseOr,
+	bytecode.OpcodeBitwiseXor - bytecode.OpcodeAdd:        ast.OperationBitwiseXor,
+	bytecode.OpcodeBitwiseAnd - bytecode.OpcodeAdd:        ast.OperationBitwiseAnd,
+	bytecode.OpcodeBitwiseLeftShift - bytecode.OpcodeAdd:  ast.OperationBitwiseLeftShift,
+	bytecode.OpcodeBitwiseRightShift - bytecode.OpcodeAdd: ast.OperationBitwiseRightShift,
+}
+
+// execute executes the code of the given function,
+// until the function returns
+//
+func (m *bytecodeVM) execute(function *bytecode.Function, locals []interpreter.Value) interpreter.Value {
+	code := function.Code
+	r := m.runtime
+	inter := m.interpreter
+
+	offset := 0
+
+	for {
+		if offset >= len(code) {
+			panic(TrapError{
+				Message: "end of code",
+			})
+		}
+
+		instructionOffset := offset
+		opcode := bytecode.Opcode(code[offset])
+		offset++
+
+		switch opcode {
+
+		// Control flow
+
+		case bytecode.OpcodeUnreachable:
+			panic(TrapError{
+				Message: "unreachable",
+			})
+
+		case bytecode.OpcodeReturn:
+			return nil
+
+		case bytecode.OpcodeReturnValue:
+			return m.pop()
+
+		case bytecode.OpcodeJump:
+			offset = m.jump(instructionOffset, operandAt(code, offset))
+
+		case bytecode.OpcodeJumpIfTrue:
+			target := operandAt(code, offset)
+			offset += bytecode.OperandSize
+			if r.truthyValue(m.pop()) {
+				offset = m.jump(instructionOffset, target)
+			}
+
+		case bytecode.OpcodeJumpIfFalse:
+			target := operandAt(code, offset)
+			offset += bytecode.OperandSize
+			if !r.truthyValue(m.pop()) {
+				offset = m.jump(instructionOffset, target)
+			}
+
+		// Stack and locals
+
+		case bytecode.OpcodeDrop:
+			m.pop()
+
+		case bytecode.OpcodeDup:
+			value := m.pop()
+			m.push(value)
+			m.push(value)
+
+		case bytecode.OpcodeGetLocal:
+			index := operandAt(code, offset)
+			offset += bytecode.OperandSize
+			m.push(locals[index])
+
+		case bytecode.OpcodeSetLocal:
+			index := operandAt(code, offset)
+			offset += bytecode.OperandSize
+			locals[index] = m.pop()
+
+		// Globals
+
+		case bytecode.OpcodeGetGlobal:
+			name := m.constants[operandAt(code, offset)].str
+			offset += bytecode.OperandSize
+			m.push(r.getGlobalValue(name))
+
+		case bytecode.OpcodeSetGlobal:
+			name := m.constants[operandAt(code, offset)].str
+			offset += bytecode.OperandSize
+			r.setGlobalValue(name, m.pop())
+
+		// Constants
+
+		case bytecode.OpcodeInt:
+			constant := m.constants[operandAt(code, offset)]
+			offset += bytecode.OperandSize
+			m.push(r.intValue(new(big.Int).Set(constant.integer)))
+
+		case bytecode.OpcodeString:
+			constant := m.constants[operandAt(code, offset)]
+			offset += bytecode.OperandSize
+			m.push(r.stringValue(constant.str))
+
+		case bytecode.OpcodeCharacter:
+			constant := m.constants[operandAt(code, offset)]
+			offset += bytecode.OperandSize
+			m.push(r.characterValue(constant.str))
+
+		case bytecode.OpcodeNumber:
+			typeConstant := m.constants[operandAt(code, offset)]
+			offset += bytecode.OperandSize
+			valueConstant := m.constants[operandAt(code, offset)]
+			offset += bytecode.OperandSize
+			m.push(r.numberValue(typeConstant.ty, new(big.Int).Set(valueConstant.integer)))
+
+		case bytecode.OpcodeAddress:
+			constant := m.constants[operandAt(code, offset)]
+			offset += bytecode.OperandSize
+			m.push(r.addressValue(constant.data))
+
+		case bytecode.OpcodePath:
+			domain := common.PathDomain(operandAt(code, offset))
+			offset += bytecode.OperandSize
+			constant := m.constants[operandAt(code, offset)]
+			offset += bytecode.OperandSize
+			m.push(r.pathValue(domain, constant.str))
+
+		case bytecode.OpcodeTrue:
+			m.push(interpreter.NewBoolValue(inter, true))
+
+		case bytecode.OpcodeFalse:
+			m.push(interpreter.NewBoolValue(inter, false))
+
+		case bytecode.OpcodeNil:
+			m.push(interpreter.NewNilValue(inter))
+
+		case bytecode.OpcodeVoid:
+			m.push(interpreter.NewVoidValue(inter))
+
+		// Unary operations
+
+		case bytecode.OpcodeNegate:
+			m.push(r.negateValue(m.pop()))
+
+		case bytecode.OpcodeNot:
+			m.push(r.notValue(m.pop()))
+
+		case bytecode.OpcodeForce:
+			m.push(r.forceValue(m.pop()))
+
+		case bytecode.OpcodeIsNil:
+			m.push(r.isNilValue(m.pop()))
+
+		case bytecode.OpcodeSome:
+			m.push(r.someValue(m.pop()))
+
+		case bytecode.OpcodeTransfer:
+			m.push(r.transferValue(m.pop()))
+
+		// Invocations
+
+		case bytecode.OpcodeCall:
+			callee := m.program.Functions[operandAt(code, offset)]
+			offset += bytecode.OperandSize
+
+			inter.ReportComputation(common.ComputationKindFunctionInvocation, 1)
+
+			result := m.callFunction(callee)
+			if callee.HasResult {
+				m.push(result)
+			}
+
+		case bytecode.OpcodeCallMember:
+			name := m.constants[operandAt(code, offset)].str
+			offset += bytecode.OperandSize
+			optional := operandAt(code, offset) != 0
+			offset += bytecode.OperandSize
+			argumentCount := operandAt(code, offset)
+			offset += bytecode.OperandSize
+
+			inter.ReportComputation(common.ComputationKindFunctionInvocation, 1)
+
+			arguments := m.popValues(argumentCount)
+			target := m.pop()
+			m.push(r.callMemberValue(target, name, optional, arguments))
+
+		case bytecode.OpcodeCallBuiltin:
+			name := m.constants[operandAt(code, offset)].str
+			offset += bytecode.OperandSize
+			argumentCount := operandAt(code, offset)
+			offset += bytecode.OperandSize
+
+			inter.ReportComputation(common.ComputationKindFunctionInvocation, 1)
+
+			arguments := m.popValues(argumentCount)
+			m.push(r.callBuiltinValue(name, arguments))
+
+		// Members and indexing
+
+		case bytecode.OpcodeGetMember:
+			name := m.constants[operandAt(code, offset)].str
+			offset += bytecode.OperandSize
+			optional := operandAt(code, offset) != 0
+			offset += bytecode.OperandSize
+
+			target := m.pop()
+			m.push(r.getMemberValue(target, name, optional))
+
+		case bytecode.OpcodeSetMember:
+			name := m.constants[operandAt(code, offset)].str
+			offset += bytecode.OperandSize
+
+			value := m.pop()
+			target := m.pop()
+			r.setMemberValue(target, name, value)
+
+		case bytecode.OpcodeGetIndex:
+			index := m.pop()
+			target := m.pop()
+			m.push(r.getIndexValue(target, index))
+
+		case bytecode.OpcodeSetIndex:
+			value := m.pop()
+			index := m.pop()
+			target := m.pop()
+			r.setIndexValue(target, index, value)
+
+		// Creation and destruction
+
+		case bytecode.OpcodeNewArray:
+			ty := m.constants[operandAt(code, offset)].ty
+			offset += bytecode.OperandSize
+			count := operandAt(code, offset)
+			offset += bytecode.OperandSize
+
+			m.push(r.newArrayValue(ty, m.popValues(count)))
+
+		case bytecode.OpcodeNewDictionary:
+			ty := m.constants[operandAt(code, offset)].ty
+			offset += bytecode.OperandSize
+			count := operandAt(code, offset)
+			offset += bytecode.OperandSize
+
+			m.push(r.newDictionaryValue(ty, m.popValues(count*2)))
+
+		case bytecode.OpcodeNewComposite:
+			ty := m.constants[operandAt(code, offset)].ty
+			offset += bytecode.OperandSize
+
+			m.push(r.newCompositeValue(ty))
+
+		case bytecode.OpcodeStringTemplate:
+			count := operandAt(code, offset)
+			offset += bytecode.OperandSize
+
+			m.push(r.stringTemplateValue(m.popValues(count*2 + 1)))
+
+		case bytecode.OpcodeCast:
+			ty := m.constants[operandAt(code, offset)].ty
+			offset += bytecode.OperandSize
+			force := operandAt(code, offset) != 0
+			offset += bytecode.OperandSize
+
+			m.push(r.castValue(m.pop(), ty, force))
+
+		case bytecode.OpcodeIsComposite:
+			ty := m.constants[operandAt(code, offset)].ty
+			offset += bytecode.OperandSize
+
+			m.push(r.isCompositeValue(m.pop(), ty))
+
+		case bytecode.OpcodeBox:
+			ty := m.constants[operandAt(code, offset)].ty
+			offset += bytecode.OperandSize
+
+			m.push(r.boxValue(m.pop(), ty))
+
+		case bytecode.OpcodeReference:
+			ty := m.constants[operandAt(code, offset)].ty
+			offset += bytecode.OperandSize
+
+			m.push(r.referenceValue(m.pop(), ty))
+
+		case bytecode.OpcodeDestroy:
+			m.push(r.destroyValue(m.pop()))
+
+		// Statements
+
+		case bytecode.OpcodeEmit:
+			r.emitValue(m.pop())
+
+		case bytecode.OpcodeFailCondition:
+			kind := ast.ConditionKind(operandAt(code, offset))
+			offset += bytecode.OperandSize
+
+			r.failConditionValue(kind, m.pop())
+
+		default:
+			if opcode < bytecode.OpcodeAdd || opcode > bytecode.OpcodeBitwiseRightShift {
+				panic(TrapError{
+					Message: fmt.Sprintf("invalid opcode: %s", opcode),
+				})
+			}
+
+			right := m.pop()
+			left := m.pop()
+			operation := bytecodeBinaryOperations[opcode-bytecode.OpcodeAdd]
+			m.push(r.binaryOperationValue(operation, left, right))
+		}
+	}
+}
+
+// jump returns the offset at which the execution continues after a jump to the given target.
+// Jumps backwards are jumps to the start of a loop, and are metered as loop iterations
+//
+func (m *bytecodeVM) jump(instructionOffset int, target int) int {
+	if target <= instructionOffset {
+		m.interpreter.ReportComputation(common.ComputationKindLoop, 1)
+	}
+	return target
+}
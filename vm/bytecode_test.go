@@ -0,0 +1,177 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/compiler"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/tests/checker"
+)
+
+func newTestBytecodeVM(t *testing.T, code string, options ...interpreter.Option) VM {
+	checker, err := checker.ParseAndCheck(t, code)
+	require.NoError(t, err)
+
+	funcs, err := compiler.Compile(checker)
+	require.NoError(t, err)
+
+	options = append(
+		[]interpreter.Option{
+			interpreter.WithStorage(interpreter.NewInMemoryStorage(nil)),
+		},
+		options...,
+	)
+
+	inter, err := interpreter.NewInterpreter(nil, common.StringLocation("test"), options...)
+	require.NoError(t, err)
+
+	m, err := NewBytecodeVM(compiler.GenerateBytecode(funcs), &Config{Interpreter: inter})
+	require.NoError(t, err)
+
+	return m
+}
+
+func TestBytecodeVMInvoke(t *testing.T) {
+
+	t.Parallel()
+
+	m := newTestBytecodeVM(t, `
+      fun fib(_ n: Int): Int {
+          if n < 2 {
+              return n
+          }
+          return fib(n - 1) + fib(n - 2)
+      }
+
+      fun test(): Int {
+          return fib(10)
+      }
+    `)
+
+	result, err := m.Invoke("test")
+	require.NoError(t, err)
+
+	assert.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(55), result)
+
+	result, err = m.Invoke("fib", interpreter.NewUnmeteredIntValueFromInt64(7))
+	require.NoError(t, err)
+
+	assert.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(13), result)
+
+	_, err = m.Invoke("fib")
+	require.Error(t, err)
+
+	_, err = m.Invoke("unknown")
+	require.Error(t, err)
+}
+
+func TestBytecodeVMLoop(t *testing.T) {
+
+	t.Parallel()
+
+	m := newTestBytecodeVM(t, `
+      fun test(): [Int] {
+          var i = 0
+          let values: [Int] = []
+          while i < 10 {
+              i = i + 1
+              if i % 2 == 0 {
+                  continue
+              }
+              values.append(i)
+              if i > 6 {
+                  break
+              }
+          }
+          return values
+      }
+    `)
+
+	result, err := m.Invoke("test")
+	require.NoError(t, err)
+
+	require.IsType(t, &interpreter.ArrayValue{}, result)
+	assert.Equal(t, "[1, 3, 5, 7]", result.String())
+}
+
+func TestBytecodeVMMetering(t *testing.T) {
+
+	t.Parallel()
+
+	computation := map[common.ComputationKind]uint{}
+
+	m := newTestBytecodeVM(t,
+		`
+          fun inc(_ n: Int): Int {
+              return n + 1
+          }
+
+          fun test(): Int {
+              var i = 0
+              while i < 5 {
+                  i = inc(i)
+              }
+              for x in [1, 2, 3] {
+                  i = inc(i)
+              }
+              return i
+          }
+        `,
+		interpreter.WithOnMeterComputationFuncHandler(
+			func(kind common.ComputationKind, intensity uint) {
+				computation[kind] += intensity
+			},
+		),
+	)
+
+	result, err := m.Invoke("test")
+	require.NoError(t, err)
+
+	assert.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(8), result)
+
+	// Like the interpreter, one loop iteration is reported for each execution of a loop body,
+	// and one function invocation is reported for each invocation
+
+	assert.Equal(t, uint(8), computation[common.ComputationKindLoop])
+	assert.Equal(t, uint(8), computation[common.ComputationKindFunctionInvocation])
+}
+
+func TestBytecodeVMTraps(t *testing.T) {
+
+	t.Parallel()
+
+	m := newTestBytecodeVM(t, `
+      fun test(): Int {
+          return test()
+      }
+    `)
+
+	_, err := m.Invoke("test")
+	require.Error(t, err)
+
+	var trapErr TrapError
+	require.ErrorAs(t, err, &trapErr)
+	assert.Equal(t, "call stack exhausted", trapErr.Message)
+}
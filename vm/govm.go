@@ -0,0 +1,434 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"fmt"
+
+	"github.com/onflow/cadence/runtime/compiler"
+	"github.com/onflow/cadence/runtime/compiler/wasm"
+	"github.com/onflow/cadence/runtime/interpreter"
+)
+
+// maxCallDepth is the maximum number of nested function calls
+//
+const maxCallDepth = 1024
+
+// goVM is a VM which executes WebAssembly modules in Go,
+// without a native WebAssembly engine.
+//
+// Modules are decoded using the WASMReader,
+// and the decoded instructions are executed directly
+//
+type goVM struct {
+	module      *wasm.Module
+	interpreter *interpreter.Interpreter
+	// functions is the function index space:
+	// the imported functions, followed by the functions defined in the module
+	functions []goFunction
+	exports   map[string]uint32
+	memory    []byte
+	stack     []value
+	callDepth int
+}
+
+// value is a WebAssembly value.
+// Numbers are stored in number, i32 values are zero-extended.
+// References are stored in ref: extern references are interpreter values,
+// function references are funcReference values, and nil is the null reference
+//
+type value struct {
+	number uint64
+	ref    any
+}
+
+// funcReference is a reference to a function, the index of the function
+//
+type funcReference uint32
+
+// goFunction is a function in the function index space,
+// either a run-time function imported by the module, or a function defined in the module
+//
+type goFunction struct {
+	functionType *wasm.FunctionType
+	// runtimeFunction is the imported run-time function, if any
+	runtimeFunction runtimeFunction
+	// code is the code of the function defined in the module, if any
+	code *wasm.Code
+}
+
+var _ VM = &goVM{}
+
+// NewGoVM returns a VM which executes the given WebAssembly module in Go.
+//
+// Unlike NewVM, which uses a native WebAssembly engine,
+// it does not require cgo and can be used in all builds
+//
+func NewGoVM(wasmCode []byte, config *Config) (VM, error) {
+
+	reader := wasm.NewWASMReader(wasm.NewBuffer(wasmCode))
+	err := reader.ReadModule()
+	if err != nil {
+		return nil, err
+	}
+
+	module := &reader.Module
+
+	m := &goVM{
+		module:      module,
+		interpreter: config.Interpreter,
+		exports:     map[string]uint32{},
+	}
+
+	err = m.link(newRuntimeFunctions(config))
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.initializeMemory()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, export := range module.Exports {
+		if descriptor, ok := export.Descriptor.(wasm.FunctionExport); ok {
+			m.exports[export.Name] = descriptor.FunctionIndex
+		}
+	}
+
+	err = m.start()
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// start calls the start function of the module, if any
+//
+func (m *goVM) start() (err error) {
+	functionIndex := m.module.StartFunctionIndex
+	if functionIndex == nil {
+		return nil
+	}
+
+	// Like in Invoke, the run-time functions report errors by panicking
+
+	defer m.interpreter.RecoverErrors(func(internalErr error) {
+		err = internalErr
+	})
+
+	m.stack = m.stack[:0]
+	m.callDepth = 0
+
+	_, err = m.call(*functionIndex, nil)
+	return err
+}
+
+// link builds the function index space,
+// resolving the imports of the module to the given run-time functions
+//
+func (m *goVM) link(runtimeFunctions map[string]runtimeFunction) error {
+	module := m.module
+
+	m.functions = make([]goFunction, 0, len(module.Imports)+len(module.Functions))
+
+	for _, imp := range module.Imports {
+		if imp.Module != compiler.RuntimeModuleName {
+			return fmt.Errorf("unsupported import: %s", imp.FullName())
+		}
+
+		runtimeFunction, ok := runtimeFunctions[imp.Name]
+		if !ok {
+			return fmt.Errorf("unknown run-time function: %s", imp.Name)
+		}
+
+		functionType, err := m.functionType(imp.TypeIndex)
+		if err != nil {
+			return err
+		}
+
+		m.functions = append(m.functions, goFunction{
+			functionType:    functionType,
+			runtimeFunction: runtimeFunction,
+		})
+	}
+
+	for _, function := range module.Functions {
+		functionType, err := m.functionType(function.TypeIndex)
+		if err != nil {
+			return err
+		}
+
+		if function.Code == nil {
+			return fmt.Errorf("missing code for function %s", function.Name)
+		}
+
+		m.functions = append(m.functions, goFunction{
+			functionType: functionType,
+			code:         function.Code,
+		})
+	}
+
+	return nil
+}
+
+func (m *goVM) functionType(typeIndex uint32) (*wasm.FunctionType, error) {
+	if int(typeIndex) >= len(m.module.Types) {
+		return nil, fmt.Errorf("invalid type index: %d", typeIndex)
+	}
+	return m.module.Types[typeIndex], nil
+}
+
+// initializeMemory allocates the memory of the module,
+// and initializes it with the data segments
+//
+func (m *goVM) initializeMemory() error {
+	module := m.module
+
+	switch len(module.Memories) {
+	case 0:
+		break
+	case 1:
+		m.memory = make([]byte, int(module.Memories[0].Min)*wasm.MemoryPageSize)
+	default:
+		return fmt.Errorf("unsupported number of memories: %d", len(module.Memories))
+	}
+
+	for _, data := range module.Data {
+		if data.MemoryIndex != 0 || m.memory == nil {
+			return fmt.Errorf("invalid data segment memory index: %d", data.MemoryIndex)
+		}
+
+		offset, err := m.evaluateConstant(data.Offset)
+		if err != nil {
+			return err
+		}
+
+		start := int(uint32(offset.number))
+		end := start + len(data.Init)
+		if end > len(m.memory) {
+			return InvalidMemoryAccessError{
+				Offset: start,
+				Length: len(data.Init),
+			}
+		}
+
+		copy(m.memory[start:end], data.Init)
+	}
+
+	return nil
+}
+
+// evaluateConstant evaluates a constant expression, e.g. the offset of a data segment
+//
+func (m *goVM) evaluateConstant(instructions []wasm.Instruction) (result value, err error) {
+	if len(instructions) != 1 {
+		return value{}, fmt.Errorf("invalid constant expression")
+	}
+
+	switch instruction := instructions[0].(type) {
+	case wasm.InstructionI32Const:
+		return value{number: uint64(uint32(instruction.Value))}, nil
+	case wasm.InstructionI64Const:
+		return value{number: uint64(instruction.Value)}, nil
+	case wasm.InstructionRefNull:
+		return value{}, nil
+	case wasm.InstructionRefFunc:
+		return value{ref: funcReference(instruction.FuncIndex)}, nil
+	default:
+		return value{}, fmt.Errorf("unsupported constant expression: %T", instruction)
+	}
+}
+
+func (m *goVM) Invoke(name string, arguments ...interpreter.Value) (result interpreter.Value, err error) {
+
+	// Like the interpreter, the run-time functions report errors by panicking.
+	// The panics are propagated through the executed WebAssembly functions
+
+	defer m.interpreter.RecoverErrors(func(internalErr error) {
+		err = internalErr
+	})
+
+	functionIndex, ok := m.exports[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function: %s", name)
+	}
+
+	functionType := m.functions[functionIndex].functionType
+
+	if len(arguments) != len(functionType.Params) {
+		return nil, fmt.Errorf(
+			"invalid number of arguments for function %s: expected %d, got %d",
+			name,
+			len(functionType.Params),
+			len(arguments),
+		)
+	}
+
+	values := make([]value, len(arguments))
+	for i, argument := range arguments {
+		if functionType.Params[i] != wasm.ValueTypeExternRef {
+			return nil, fmt.Errorf("unsupported parameter type for function %s: %d", name, i)
+		}
+		values[i] = value{ref: argument}
+	}
+
+	m.stack = m.stack[:0]
+	m.callDepth = 0
+
+	results, err := m.call(functionIndex, values)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 || results[0].ref == nil {
+		return interpreter.NewVoidValue(m.interpreter), nil
+	}
+
+	value, ok := results[0].ref.(interpreter.Value)
+	if !ok {
+		return nil, fmt.Errorf("unsupported result of function %s: %T", name, results[0].ref)
+	}
+
+	return value, nil
+}
+
+// call calls the function with the given index with the given arguments
+// and returns the results.
+//
+// Traps are returned as an error,
+// errors reported by run-time functions are propagated as panics
+//
+func (m *goVM) call(functionIndex uint32, arguments []value) (results []value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			trap, ok := r.(TrapError)
+			if !ok {
+				panic(r)
+			}
+			err = trap
+		}
+	}()
+
+	stackHeight := len(m.stack)
+	m.stack = append(m.stack, arguments...)
+
+	m.callFunction(functionIndex)
+
+	results = make([]value, len(m.stack)-stackHeight)
+	copy(results, m.stack[stackHeight:])
+	m.stack = m.stack[:stackHeight]
+
+	return results, nil
+}
+
+// callFunction calls the function with the given index.
+// The arguments are popped from the stack, and the results are pushed onto the stack
+//
+func (m *goVM) callFunction(functionIndex uint32) {
+	if int(functionIndex) >= len(m.functions) {
+		panic(TrapError{
+			Message: fmt.Sprintf("invalid function index: %d", functionIndex),
+		})
+	}
+
+	function := m.functions[functionIndex]
+	functionType := function.functionType
+
+	if function.runtimeFunction != nil {
+		m.callRuntimeFunction(function.runtimeFunction, functionType)
+		return
+	}
+
+	m.callDepth++
+	if m.callDepth > maxCallDepth {
+		panic(TrapError{
+			Message: "call stack exhausted",
+		})
+	}
+
+	// The arguments become the first locals,
+	// the remaining locals are initialized to zero values
+
+	parameterCount := len(functionType.Params)
+	arguments := m.popValues(parameterCount)
+
+	locals := make([]value, parameterCount+len(function.code.Locals))
+	copy(locals, arguments)
+
+	stackHeight := len(m.stack)
+
+	// The body of the function is a block, which returns the results.
+	// A return is a branch to this block
+
+	m.executeBlock(
+		&frame{locals: locals},
+		function.code.Instructions,
+		stackHeight,
+		len(functionType.Results),
+	)
+
+	m.callDepth--
+}
+
+// callRuntimeFunction calls a run-time function imported by the module.
+// The arguments are popped from the stack, and the result, if any, is pushed onto the stack
+//
+func (m *goVM) callRuntimeFunction(function runtimeFunction, functionType *wasm.FunctionType) {
+	parameters := m.popValues(len(functionType.Params))
+
+	arguments := make([]any, len(parameters))
+	for i, parameter := range parameters {
+		switch functionType.Params[i] {
+		case wasm.ValueTypeI32:
+			arguments[i] = int32(parameter.number)
+		case wasm.ValueTypeI64:
+			arguments[i] = int64(parameter.number)
+		default:
+			arguments[i] = parameter.ref
+		}
+	}
+
+	result := function(m.memory, arguments)
+
+	if len(functionType.Results) == 0 {
+		return
+	}
+
+	switch result := result.(type) {
+	case int32:
+		m.push(value{number: uint64(uint32(result))})
+	case int64:
+		m.push(value{number: uint64(result)})
+	default:
+		m.push(value{ref: result})
+	}
+}
+
+// TrapError is reported when the execution of an instruction traps,
+// e.g. when an `unreachable` instruction is executed, or an integer is divided by zero
+//
+type TrapError struct {
+	Message string
+}
+
+func (e TrapError) Error() string {
+	return fmt.Sprintf("trap: %s", e.Message)
+}
@@ -0,0 +1,628 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/onflow/cadence/runtime/compiler/wasm"
+)
+
+// frame is the activation of a function defined in the module
+//
+type frame struct {
+	locals []value
+}
+
+func (f *frame) checkLocalIndex(index uint32) {
+	if int(index) >= len(f.locals) {
+		panic(TrapError{
+			Message: fmt.Sprintf("invalid local index: %d", index),
+		})
+	}
+}
+
+// Executing a sequence of instructions results in a branch:
+// either no branch, i.e. execution continues after the sequence,
+// a branch to the label with the given index (0 is the innermost enclosing block),
+// or a return from the function
+//
+const (
+	branchNone   = -1
+	branchReturn = math.MaxInt32
+)
+
+// outerBranch returns the branch for the enclosing block,
+// given a branch out of a block
+//
+func outerBranch(branch int) int {
+	if branch == branchReturn {
+		return branchReturn
+	}
+	return branch - 1
+}
+
+func (m *goVM) push(v value) {
+	m.stack = append(m.stack, v)
+}
+
+func (m *goVM) pop() value {
+	last := len(m.stack) - 1
+	if last < 0 {
+		panic(TrapError{
+			Message: "stack underflow",
+		})
+	}
+	v := m.stack[last]
+	m.stack = m.stack[:last]
+	return v
+}
+
+// popValues pops the given number of values from the stack.
+// The returned slice is only valid until the next push
+//
+func (m *goVM) popValues(count int) []value {
+	height := len(m.stack) - count
+	if height < 0 {
+		panic(TrapError{
+			Message: "stack underflow",
+		})
+	}
+	values := m.stack[height:]
+	m.stack = m.stack[:height]
+	return values
+}
+
+// unwind removes the values pushed since the stack had the given height,
+// except for the given number of values on top of the stack,
+// e.g. the results of a block
+//
+func (m *goVM) unwind(height int, keep int) {
+	top := len(m.stack) - keep
+	if top < height {
+		panic(TrapError{
+			Message: "stack underflow",
+		})
+	}
+	copy(m.stack[height:], m.stack[top:])
+	m.stack = m.stack[:height+keep]
+}
+
+func (m *goVM) pushI32(v uint32) {
+	m.push(value{number: uint64(v)})
+}
+
+func (m *goVM) popI32() uint32 {
+	return uint32(m.pop().number)
+}
+
+func (m *goVM) pushI64(v uint64) {
+	m.push(value{number: v})
+}
+
+func (m *goVM) popI64() uint64 {
+	return m.pop().number
+}
+
+func (m *goVM) pushBool(b bool) {
+	if b {
+		m.pushI32(1)
+	} else {
+		m.pushI32(0)
+	}
+}
+
+// blockArity returns the number of parameters and results of a block with the given type
+//
+func (m *goVM) blockArity(blockType wasm.BlockType) (params int, results int) {
+	switch blockType := blockType.(type) {
+	case nil:
+		return 0, 0
+
+	case wasm.ValueType:
+		return 0, 1
+
+	case wasm.TypeIndexBlockType:
+		functionType, err := m.functionType(blockType.TypeIndex)
+		if err != nil {
+			panic(TrapError{
+				Message: err.Error(),
+			})
+		}
+		return len(functionType.Params), len(functionType.Results)
+
+	default:
+		panic(TrapError{
+			Message: fmt.Sprintf("unsupported block type: %T", blockType),
+		})
+	}
+}
+
+// executeBlock executes the instructions of a block.
+// When the block is exited, either normally or by a branch,
+// only its results are kept on the stack
+//
+func (m *goVM) executeBlock(f *frame, instructions []wasm.Instruction, height int, results int) int {
+	branch := m.execute(f, instructions)
+
+	switch branch {
+	case branchNone:
+		return branchNone
+
+	case 0:
+		m.unwind(height, results)
+		return branchNone
+
+	default:
+		return outerBranch(branch)
+	}
+}
+
+// execute executes the given instructions and returns the resulting branch
+//
+func (m *goVM) execute(f *frame, instructions []wasm.Instruction) int {
+	for _, instruction := range instructions {
+		switch instruction := instruction.(type) {
+
+		// Control instructions
+
+		case wasm.InstructionUnreachable:
+			panic(TrapError{
+				Message: "unreachable",
+			})
+
+		case wasm.InstructionNop, wasm.InstructionEnd:
+			break
+
+		case wasm.InstructionBlock:
+			params, results := m.blockArity(instruction.Block.BlockType)
+			height := len(m.stack) - params
+
+			branch := m.executeBlock(f, instruction.Block.Instructions1, height, results)
+			if branch != branchNone {
+				return branch
+			}
+
+		case wasm.InstructionLoop:
+			// A branch to a loop continues the loop,
+			// passing the parameters of the loop
+
+			params, _ := m.blockArity(instruction.Block.BlockType)
+			height := len(m.stack) - params
+
+			for {
+				branch := m.execute(f, instruction.Block.Instructions1)
+				if branch == 0 {
+					m.unwind(height, params)
+					continue
+				}
+				if branch != branchNone {
+					return outerBranch(branch)
+				}
+				break
+			}
+
+		case wasm.InstructionIf:
+			condition := m.popI32()
+
+			params, results := m.blockArity(instruction.Block.BlockType)
+			height := len(m.stack) - params
+
+			body := instruction.Block.Instructions1
+			if condition == 0 {
+				body = instruction.Block.Instructions2
+			}
+
+			branch := m.executeBlock(f, body, height, results)
+			if branch != branchNone {
+				return branch
+			}
+
+		case wasm.InstructionBr:
+			return int(instruction.LabelIndex)
+
+		case wasm.InstructionBrIf:
+			if m.popI32() != 0 {
+				return int(instruction.LabelIndex)
+			}
+
+		case wasm.InstructionBrTable:
+			index := m.popI32()
+			if int(index) < len(instruction.LabelIndices) {
+				return int(instruction.LabelIndices[index])
+			}
+			return int(instruction.DefaultLabelIndex)
+
+		case wasm.InstructionReturn:
+			return branchReturn
+
+		case wasm.InstructionCall:
+			m.callFunction(instruction.FuncIndex)
+
+		case wasm.InstructionCallIndirect:
+			// The module representation has no tables
+			panic(TrapError{
+				Message: fmt.Sprintf("unknown table: %d", instruction.TableIndex),
+			})
+
+		// Reference instructions
+
+		case wasm.InstructionRefNull:
+			m.push(value{})
+
+		case wasm.InstructionRefIsNull:
+			m.pushBool(m.pop().ref == nil)
+
+		case wasm.InstructionRefFunc:
+			m.push(value{ref: funcReference(instruction.FuncIndex)})
+
+		// Parametric instructions
+
+		case wasm.InstructionDrop:
+			m.pop()
+
+		case wasm.InstructionSelect:
+			condition := m.popI32()
+			second := m.pop()
+			first := m.pop()
+			if condition != 0 {
+				m.push(first)
+			} else {
+				m.push(second)
+			}
+
+		// Variable instructions
+
+		case wasm.InstructionLocalGet:
+			f.checkLocalIndex(instruction.LocalIndex)
+			m.push(f.locals[instruction.LocalIndex])
+
+		case wasm.InstructionLocalSet:
+			f.checkLocalIndex(instruction.LocalIndex)
+			f.locals[instruction.LocalIndex] = m.pop()
+
+		case wasm.InstructionLocalTee:
+			f.checkLocalIndex(instruction.LocalIndex)
+			v := m.pop()
+			f.locals[instruction.LocalIndex] = v
+			m.push(v)
+
+		case wasm.InstructionGlobalGet:
+			// The module representation has no globals
+			panic(TrapError{
+				Message: fmt.Sprintf("unknown global: %d", instruction.GlobalIndex),
+			})
+
+		case wasm.InstructionGlobalSet:
+			// The module representation has no globals
+			panic(TrapError{
+				Message: fmt.Sprintf("unknown global: %d", instruction.GlobalIndex),
+			})
+
+		// Numeric instructions
+
+		case wasm.InstructionI32Const:
+			m.pushI32(uint32(instruction.Value))
+
+		case wasm.InstructionI64Const:
+			m.pushI64(uint64(instruction.Value))
+
+		default:
+			if !m.executeNumeric(instruction) {
+				panic(TrapError{
+					Message: fmt.Sprintf("unsupported instruction: %T", instruction),
+				})
+			}
+		}
+	}
+
+	return branchNone
+}
+
+// executeNumeric executes the given numeric instruction.
+// It returns false if the instruction is not a numeric instruction
+//
+func (m *goVM) executeNumeric(instruction wasm.Instruction) bool {
+	switch instruction.(type) {
+
+	// i32 comparison instructions
+
+	case wasm.InstructionI32Eqz:
+		m.pushBool(m.popI32() == 0)
+
+	case wasm.InstructionI32Eq:
+		b, a := m.popI32(), m.popI32()
+		m.pushBool(a == b)
+
+	case wasm.InstructionI32Ne:
+		b, a := m.popI32(), m.popI32()
+		m.pushBool(a != b)
+
+	case wasm.InstructionI32LtS:
+		b, a := m.popI32(), m.popI32()
+		m.pushBool(int32(a) < int32(b))
+
+	case wasm.InstructionI32LtU:
+		b, a := m.popI32(), m.popI32()
+		m.pushBool(a < b)
+
+	case wasm.InstructionI32GtS:
+		b, a := m.popI32(), m.popI32()
+		m.pushBool(int32(a) > int32(b))
+
+	case wasm.InstructionI32GtU:
+		b, a := m.popI32(), m.popI32()
+		m.pushBool(a > b)
+
+	case wasm.InstructionI32LeS:
+		b, a := m.popI32(), m.popI32()
+		m.pushBool(int32(a) <= int32(b))
+
+	case wasm.InstructionI32LeU:
+		b, a := m.popI32(), m.popI32()
+		m.pushBool(a <= b)
+
+	case wasm.InstructionI32GeS:
+		b, a := m.popI32(), m.popI32()
+		m.pushBool(int32(a) >= int32(b))
+
+	case wasm.InstructionI32GeU:
+		b, a := m.popI32(), m.popI32()
+		m.pushBool(a >= b)
+
+	// i64 comparison instructions
+
+	case wasm.InstructionI64Eqz:
+		m.pushBool(m.popI64() == 0)
+
+	case wasm.InstructionI64Eq:
+		b, a := m.popI64(), m.popI64()
+		m.pushBool(a == b)
+
+	case wasm.InstructionI64Ne:
+		b, a := m.popI64(), m.popI64()
+		m.pushBool(a != b)
+
+	case wasm.InstructionI64LtS:
+		b, a := m.popI64(), m.popI64()
+		m.pushBool(int64(a) < int64(b))
+
+	case wasm.InstructionI64LtU:
+		b, a := m.popI64(), m.popI64()
+		m.pushBool(a < b)
+
+	case wasm.InstructionI64GtS:
+		b, a := m.popI64(), m.popI64()
+		m.pushBool(int64(a) > int64(b))
+
+	case wasm.InstructionI64GtU:
+		b, a := m.popI64(), m.popI64()
+		m.pushBool(a > b)
+
+	case wasm.InstructionI64LeS:
+		b, a := m.popI64(), m.popI64()
+		m.pushBool(int64(a) <= int64(b))
+
+	case wasm.InstructionI64LeU:
+		b, a := m.popI64(), m.popI64()
+		m.pushBool(a <= b)
+
+	case wasm.InstructionI64GeS:
+		b, a := m.popI64(), m.popI64()
+		m.pushBool(int64(a) >= int64(b))
+
+	case wasm.InstructionI64GeU:
+		b, a := m.popI64(), m.popI64()
+		m.pushBool(a >= b)
+
+	// i32 arithmetic instructions
+
+	case wasm.InstructionI32Clz:
+		m.pushI32(uint32(bits.LeadingZeros32(m.popI32())))
+
+	case wasm.InstructionI32Ctz:
+		m.pushI32(uint32(bits.TrailingZeros32(m.popI32())))
+
+	case wasm.InstructionI32Popcnt:
+		m.pushI32(uint32(bits.OnesCount32(m.popI32())))
+
+	case wasm.InstructionI32Add:
+		b, a := m.popI32(), m.popI32()
+		m.pushI32(a + b)
+
+	case wasm.InstructionI32Sub:
+		b, a := m.popI32(), m.popI32()
+		m.pushI32(a - b)
+
+	case wasm.InstructionI32Mul:
+		b, a := m.popI32(), m.popI32()
+		m.pushI32(a * b)
+
+	case wasm.InstructionI32DivS:
+		b, a := int32(m.popI32()), int32(m.popI32())
+		checkDivisor(b != 0)
+		checkOverflow(a != math.MinInt32 || b != -1)
+		m.pushI32(uint32(a / b))
+
+	case wasm.InstructionI32DivU:
+		b, a := m.popI32(), m.popI32()
+		checkDivisor(b != 0)
+		m.pushI32(a / b)
+
+	case wasm.InstructionI32RemS:
+		b, a := int32(m.popI32()), int32(m.popI32())
+		checkDivisor(b != 0)
+		if b == -1 {
+			m.pushI32(0)
+		} else {
+			m.pushI32(uint32(a % b))
+		}
+
+	case wasm.InstructionI32RemU:
+		b, a := m.popI32(), m.popI32()
+		checkDivisor(b != 0)
+		m.pushI32(a % b)
+
+	case wasm.InstructionI32And:
+		b, a := m.popI32(), m.popI32()
+		m.pushI32(a & b)
+
+	case wasm.InstructionI32Or:
+		b, a := m.popI32(), m.popI32()
+		m.pushI32(a | b)
+
+	case wasm.InstructionI32Xor:
+		b, a := m.popI32(), m.popI32()
+		m.pushI32(a ^ b)
+
+	case wasm.InstructionI32Shl:
+		b, a := m.popI32(), m.popI32()
+		m.pushI32(a << (b % 32))
+
+	case wasm.InstructionI32ShrS:
+		b, a := m.popI32(), m.popI32()
+		m.pushI32(uint32(int32(a) >> (b % 32)))
+
+	case wasm.InstructionI32ShrU:
+		b, a := m.popI32(), m.popI32()
+		m.pushI32(a >> (b % 32))
+
+	case wasm.InstructionI32Rotl:
+		b, a := m.popI32(), m.popI32()
+		m.pushI32(bits.RotateLeft32(a, int(b%32)))
+
+	case wasm.InstructionI32Rotr:
+		b, a := m.popI32(), m.popI32()
+		m.pushI32(bits.RotateLeft32(a, -int(b%32)))
+
+	// i64 arithmetic instructions
+
+	case wasm.InstructionI64Clz:
+		m.pushI64(uint64(bits.LeadingZeros64(m.popI64())))
+
+	case wasm.InstructionI64Ctz:
+		m.pushI64(uint64(bits.TrailingZeros64(m.popI64())))
+
+	case wasm.InstructionI64Popcnt:
+		m.pushI64(uint64(bits.OnesCount64(m.popI64())))
+
+	case wasm.InstructionI64Add:
+		b, a := m.popI64(), m.popI64()
+		m.pushI64(a + b)
+
+	case wasm.InstructionI64Sub:
+		b, a := m.popI64(), m.popI64()
+		m.pushI64(a - b)
+
+	case wasm.InstructionI64Mul:
+		b, a := m.popI64(), m.popI64()
+		m.pushI64(a * b)
+
+	case wasm.InstructionI64DivS:
+		b, a := int64(m.popI64()), int64(m.popI64())
+		checkDivisor(b != 0)
+		checkOverflow(a != math.MinInt64 || b != -1)
+		m.pushI64(uint64(a / b))
+
+	case wasm.InstructionI64DivU:
+		b, a := m.popI64(), m.popI64()
+		checkDivisor(b != 0)
+		m.pushI64(a / b)
+
+	case wasm.InstructionI64RemS:
+		b, a := int64(m.popI64()), int64(m.popI64())
+		checkDivisor(b != 0)
+		if b == -1 {
+			m.pushI64(0)
+		} else {
+			m.pushI64(uint64(a % b))
+		}
+
+	case wasm.InstructionI64RemU:
+		b, a := m.popI64(), m.popI64()
+		checkDivisor(b != 0)
+		m.pushI64(a % b)
+
+	case wasm.InstructionI64And:
+		b, a := m.popI64(), m.popI64()
+		m.pushI64(a & b)
+
+	case wasm.InstructionI64Or:
+		b, a := m.popI64(), m.popI64()
+		m.pushI64(a | b)
+
+	case wasm.InstructionI64Xor:
+		b, a := m.popI64(), m.popI64()
+		m.pushI64(a ^ b)
+
+	case wasm.InstructionI64Shl:
+		b, a := m.popI64(), m.popI64()
+		m.pushI64(a << (b % 64))
+
+	case wasm.InstructionI64ShrS:
+		b, a := m.popI64(), m.popI64()
+		m.pushI64(uint64(int64(a) >> (b % 64)))
+
+	case wasm.InstructionI64ShrU:
+		b, a := m.popI64(), m.popI64()
+		m.pushI64(a >> (b % 64))
+
+	case wasm.InstructionI64Rotl:
+		b, a := m.popI64(), m.popI64()
+		m.pushI64(bits.RotateLeft64(a, int(b%64)))
+
+	case wasm.InstructionI64Rotr:
+		b, a := m.popI64(), m.popI64()
+		m.pushI64(bits.RotateLeft64(a, -int(b%64)))
+
+	// Conversion instructions
+
+	case wasm.InstructionI32WrapI64:
+		m.pushI32(uint32(m.popI64()))
+
+	case wasm.InstructionI64ExtendI32S:
+		m.pushI64(uint64(int64(int32(m.popI32()))))
+
+	case wasm.InstructionI64ExtendI32U:
+		m.pushI64(uint64(m.popI32()))
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+func checkDivisor(valid bool) {
+	if !valid {
+		panic(TrapError{
+			Message: "integer divide by zero",
+		})
+	}
+}
+
+func checkOverflow(valid bool) {
+	if !valid {
+		panic(TrapError{
+			Message: "integer overflow",
+		})
+	}
+}
@@ -0,0 +1,351 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/compiler"
+	"github.com/onflow/cadence/runtime/compiler/wasm"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/tests/checker"
+)
+
+func newTestGoVM(t *testing.T, module *wasm.Module) *goVM {
+	var buf wasm.Buffer
+	err := wasm.NewWASMWriter(&buf).WriteModule(module)
+	require.NoError(t, err)
+
+	m, err := NewGoVM(buf.Bytes(), &Config{})
+	require.NoError(t, err)
+
+	return m.(*goVM)
+}
+
+func i32Values(values ...uint32) []value {
+	result := make([]value, len(values))
+	for i, v := range values {
+		result[i] = value{number: uint64(v)}
+	}
+	return result
+}
+
+var i32FunctionType = &wasm.FunctionType{
+	Params:  []wasm.ValueType{wasm.ValueTypeI32},
+	Results: []wasm.ValueType{wasm.ValueTypeI32},
+}
+
+func TestGoVMLoop(t *testing.T) {
+
+	t.Parallel()
+
+	// factorial, using a loop:
+	//
+	// (func (param $n i32) (result i32) (local $result i32)
+	//   (local.set $result (i32.const 1))
+	//   (block
+	//     (loop
+	//       (br_if 1 (i32.le_s (local.get $n) (i32.const 1)))
+	//       (local.set $result (i32.mul (local.get $result) (local.get $n)))
+	//       (local.set $n (i32.sub (local.get $n) (i32.const 1)))
+	//       (br 0)))
+	//   (local.get $result))
+
+	builder := &wasm.ModuleBuilder{}
+	builder.AddFunction(
+		"factorial",
+		i32FunctionType,
+		&wasm.Code{
+			Locals: []wasm.ValueType{wasm.ValueTypeI32},
+			Instructions: []wasm.Instruction{
+				wasm.InstructionI32Const{Value: 1},
+				wasm.InstructionLocalSet{LocalIndex: 1},
+				wasm.InstructionBlock{
+					Block: wasm.Block{
+						Instructions1: []wasm.Instruction{
+							wasm.InstructionLoop{
+								Block: wasm.Block{
+									Instructions1: []wasm.Instruction{
+										wasm.InstructionLocalGet{LocalIndex: 0},
+										wasm.InstructionI32Const{Value: 1},
+										wasm.InstructionI32LeS{},
+										wasm.InstructionBrIf{LabelIndex: 1},
+										wasm.InstructionLocalGet{LocalIndex: 1},
+										wasm.InstructionLocalGet{LocalIndex: 0},
+										wasm.InstructionI32Mul{},
+										wasm.InstructionLocalSet{LocalIndex: 1},
+										wasm.InstructionLocalGet{LocalIndex: 0},
+										wasm.InstructionI32Const{Value: 1},
+										wasm.InstructionI32Sub{},
+										wasm.InstructionLocalSet{LocalIndex: 0},
+										wasm.InstructionBr{LabelIndex: 0},
+									},
+								},
+							},
+						},
+					},
+				},
+				wasm.InstructionLocalGet{LocalIndex: 1},
+			},
+		},
+	)
+
+	m := newTestGoVM(t, builder.Build())
+
+	for n, expected := range map[uint32]uint32{
+		0:  1,
+		1:  1,
+		5:  120,
+		10: 3628800,
+	} {
+		results, err := m.call(0, i32Values(n))
+		require.NoError(t, err)
+		assert.Equal(t, i32Values(expected), results)
+	}
+}
+
+func TestGoVMBranchTable(t *testing.T) {
+
+	t.Parallel()
+
+	// (func (param $n i32) (result i32)
+	//   (block
+	//     (block
+	//       (block
+	//         (br_table 0 1 2 (local.get $n)))
+	//       (return (i32.const 10)))
+	//     (return (i32.const 20)))
+	//   (i32.const 30))
+
+	builder := &wasm.ModuleBuilder{}
+	builder.AddFunction(
+		"switch",
+		i32FunctionType,
+		&wasm.Code{
+			Instructions: []wasm.Instruction{
+				wasm.InstructionBlock{
+					Block: wasm.Block{
+						Instructions1: []wasm.Instruction{
+							wasm.InstructionBlock{
+								Block: wasm.Block{
+									Instructions1: []wasm.Instruction{
+										wasm.InstructionBlock{
+											Block: wasm.Block{
+												Instructions1: []wasm.Instruction{
+													wasm.InstructionLocalGet{LocalIndex: 0},
+													wasm.InstructionBrTable{
+														LabelIndices:      []uint32{0, 1},
+														DefaultLabelIndex: 2,
+													},
+												},
+											},
+										},
+										wasm.InstructionI32Const{Value: 10},
+										wasm.InstructionReturn{},
+									},
+								},
+							},
+							wasm.InstructionI32Const{Value: 20},
+							wasm.InstructionReturn{},
+						},
+					},
+				},
+				wasm.InstructionI32Const{Value: 30},
+			},
+		},
+	)
+
+	m := newTestGoVM(t, builder.Build())
+
+	for n, expected := range map[uint32]uint32{
+		0: 10,
+		1: 20,
+		2: 30,
+		7: 30,
+	} {
+		results, err := m.call(0, i32Values(n))
+		require.NoError(t, err)
+		assert.Equal(t, i32Values(expected), results)
+	}
+}
+
+func TestGoVMIf(t *testing.T) {
+
+	t.Parallel()
+
+	// (func (param $n i32) (result i32)
+	//   (if (result i32) (local.get $n)
+	//     (then (i32.const 1))
+	//     (else (i32.const 2))))
+
+	builder := &wasm.ModuleBuilder{}
+	builder.AddFunction(
+		"if",
+		i32FunctionType,
+		&wasm.Code{
+			Instructions: []wasm.Instruction{
+				wasm.InstructionLocalGet{LocalIndex: 0},
+				wasm.InstructionIf{
+					Block: wasm.Block{
+						BlockType: wasm.ValueTypeI32,
+						Instructions1: []wasm.Instruction{
+							wasm.InstructionI32Const{Value: 1},
+						},
+						Instructions2: []wasm.Instruction{
+							wasm.InstructionI32Const{Value: 2},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	m := newTestGoVM(t, builder.Build())
+
+	results, err := m.call(0, i32Values(1))
+	require.NoError(t, err)
+	assert.Equal(t, i32Values(1), results)
+
+	results, err = m.call(0, i32Values(0))
+	require.NoError(t, err)
+	assert.Equal(t, i32Values(2), results)
+}
+
+func TestGoVMTraps(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("division by zero", func(t *testing.T) {
+
+		t.Parallel()
+
+		builder := &wasm.ModuleBuilder{}
+		builder.AddFunction(
+			"div",
+			i32FunctionType,
+			&wasm.Code{
+				Instructions: []wasm.Instruction{
+					wasm.InstructionI32Const{Value: 1},
+					wasm.InstructionLocalGet{LocalIndex: 0},
+					wasm.InstructionI32DivS{},
+				},
+			},
+		)
+
+		m := newTestGoVM(t, builder.Build())
+
+		_, err := m.call(0, i32Values(0))
+		require.Equal(t,
+			TrapError{Message: "integer divide by zero"},
+			err,
+		)
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+
+		t.Parallel()
+
+		builder := &wasm.ModuleBuilder{}
+		builder.AddFunction(
+			"unreachable",
+			&wasm.FunctionType{},
+			&wasm.Code{
+				Instructions: []wasm.Instruction{
+					wasm.InstructionUnreachable{},
+				},
+			},
+		)
+
+		m := newTestGoVM(t, builder.Build())
+
+		_, err := m.call(0, nil)
+		require.Equal(t,
+			TrapError{Message: "unreachable"},
+			err,
+		)
+	})
+
+	t.Run("call stack exhausted", func(t *testing.T) {
+
+		t.Parallel()
+
+		builder := &wasm.ModuleBuilder{}
+		builder.AddFunction(
+			"recurse",
+			&wasm.FunctionType{},
+			&wasm.Code{
+				Instructions: []wasm.Instruction{
+					wasm.InstructionCall{FuncIndex: 0},
+				},
+			},
+		)
+
+		m := newTestGoVM(t, builder.Build())
+
+		_, err := m.call(0, nil)
+		require.Equal(t,
+			TrapError{Message: "call stack exhausted"},
+			err,
+		)
+	})
+}
+
+func TestGoVMInvoke(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := checker.ParseAndCheck(t, `
+      fun fib(_ n: Int): Int {
+          if n < 2 {
+              return n
+          }
+          return fib(n - 1) + fib(n - 2)
+      }
+
+      fun test(): Int {
+          return fib(10)
+      }
+    `)
+	require.NoError(t, err)
+
+	funcs, err := compiler.Compile(checker)
+	require.NoError(t, err)
+
+	var buf wasm.Buffer
+	err = wasm.NewWASMWriter(&buf).WriteModule(compiler.GenerateWasm(funcs))
+	require.NoError(t, err)
+
+	inter, err := interpreter.NewInterpreter(nil, common.StringLocation("test"))
+	require.NoError(t, err)
+
+	m, err := NewGoVM(buf.Bytes(), &Config{Interpreter: inter})
+	require.NoError(t, err)
+
+	result, err := m.Invoke("test")
+	require.NoError(t, err)
+
+	assert.Equal(t, interpreter.NewUnmeteredIntValueFromInt64(55), result)
+
+	_, err = m.Invoke("unknown")
+	require.Error(t, err)
+}
@@ -0,0 +1,1160 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright 2019-2022 Dapper Labs, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package vm
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/runtime/ast"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/onflow/cadence/runtime/errors"
+	"github.com/onflow/cadence/runtime/interpreter"
+	"github.com/onflow/cadence/runtime/sema"
+)
+
+type VM interface {
+	Invoke(name string, arguments ...interpreter.Value) (interpreter.Value, error)
+}
+
+// Config is the configuration of a VM
+//
+type Config struct {
+	// Interpreter is the interpreter of the compiled program.
+	// It is used by the run-time functions to operate on values
+	Interpreter *interpreter.Interpreter
+	// UUIDHandler is used to generate the UUIDs of created resources
+	UUIDHandler interpreter.UUIDHandlerFunc
+	// OnEventEmitted is called when an event is emitted
+	OnEventEmitted interpreter.OnEventEmittedFunc
+}
+
+// runtimeFunction is a function of the run-time module, which compiled programs import.
+//
+// The arguments and the result are either i32s, i.e. int32 values,
+// or externrefs, i.e. interpreter values or argument lists.
+// The function may read the memory of the calling instance.
+//
+// Like in the interpreter, errors are reported by panicking
+//
+type runtimeFunction func(memory []byte, arguments []any) any
+
+// argumentList is a list of values,
+// which is passed to run-time functions that have a variable number of parameters
+//
+type argumentList struct {
+	values []interpreter.Value
+}
+
+type runtimeFunctions struct {
+	config *Config
+	// globals are the values of the global variables of the program
+	globals map[string]interpreter.Value
+}
+
+// newRuntimeFunctions returns the run-time functions, by name
+//
+func newRuntimeFunctions(config *Config) map[string]runtimeFunction {
+	r := &runtimeFunctions{
+		config: config,
+	}
+
+	return map[string]runtimeFunction{
+		"Int":             r.int,
+		"String":          r.string,
+		"Character":       r.character,
+		"Number":          r.number,
+		"Address":         r.address,
+		"Path":            r.path,
+		"Bool":            r.bool,
+		"Nil":             r.nil,
+		"Void":            r.void,
+		"add":             r.binaryOperation(ast.OperationPlus),
+		"sub":             r.binaryOperation(ast.OperationMinus),
+		"mul":             r.binaryOperation(ast.OperationMul),
+		"div":             r.binaryOperation(ast.OperationDiv),
+		"mod":             r.binaryOperation(ast.OperationMod),
+		"eq":              r.binaryOperation(ast.OperationEqual),
+		"ne":              r.binaryOperation(ast.OperationNotEqual),
+		"lt":              r.binaryOperation(ast.OperationLess),
+		"le":              r.binaryOperation(ast.OperationLessEqual),
+		"gt":              r.binaryOperation(ast.OperationGreater),
+		"ge":              r.binaryOperation(ast.OperationGreaterEqual),
+		"bit_or":          r.binaryOperation(ast.OperationBitwiseOr),
+		"bit_xor":         r.binaryOperation(ast.OperationBitwiseXor),
+		"bit_and":         r.binaryOperation(ast.OperationBitwiseAnd),
+		"shl":             r.binaryOperation(ast.OperationBitwiseLeftShift),
+		"shr":             r.binaryOperation(ast.OperationBitwiseRightShift),
+		"neg":             r.negate,
+		"not":             r.not,
+		"force":           r.force,
+		"is_nil":          r.isNil,
+		"some":            r.some,
+		"transfer":        r.transfer,
+		"truthy":          r.truthy,
+		"get_global":      r.getGlobal,
+		"set_global":      r.setGlobal,
+		"get_member":      r.getMember,
+		"set_member":      r.setMember,
+		"get_index":       r.getIndex,
+		"set_index":       r.setIndex,
+		"args":            r.arguments,
+		"args_push":       r.argumentsPush,
+		"call_member":     r.callMember,
+		"call_builtin":    r.callBuiltin,
+		"new_array":       r.newArray,
+		"new_dictionary":  r.newDictionary,
+		"new_composite":   r.newComposite,
+		"string_template": r.stringTemplate,
+		"cast":            r.cast,
+		"is_composite":    r.isComposite,
+		"box":             r.box,
+		"reference":       r.reference,
+		"destroy":         r.destroy,
+		"emit":            r.emit,
+		"fail_condition":  r.failCondition,
+	}
+}
+
+func (r *runtimeFunctions) interpreter() *interpreter.Interpreter {
+	return r.config.Interpreter
+}
+
+// getLocationRange returns the location range for errors.
+// Compiled programs have no position information
+//
+func (r *runtimeFunctions) getLocationRange() interpreter.LocationRange {
+	return interpreter.LocationRange{
+		Location: r.interpreter().Location,
+	}
+}
+
+// Argument helpers
+
+func i32Argument(arguments []any, index int) int32 {
+	argument, ok := arguments[index].(int32)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	return argument
+}
+
+func valueArgument(arguments []any, index int) interpreter.Value {
+	argument, ok := arguments[index].(interpreter.Value)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	return argument
+}
+
+func boolArgument(arguments []any, index int) bool {
+	return i32Argument(arguments, index) != 0
+}
+
+func listArgument(arguments []any, index int) *argumentList {
+	argument, ok := arguments[index].(*argumentList)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	return argument
+}
+
+// bytesArgument returns the memory referenced by the two i32 arguments
+// starting at the given index, an offset and a length
+//
+func bytesArgument(memory []byte, arguments []any, index int) []byte {
+	offset := i32Argument(arguments, index)
+	length := i32Argument(arguments, index+1)
+
+	if offset < 0 || length < 0 || int(offset)+int(length) > len(memory) {
+		panic(InvalidMemoryAccessError{
+			Offset: int(offset),
+			Length: int(length),
+		})
+	}
+
+	return memory[offset : offset+length]
+}
+
+func stringArgument(memory []byte, arguments []any, index int) string {
+	return string(bytesArgument(memory, arguments, index))
+}
+
+func (r *runtimeFunctions) typeArgument(memory []byte, arguments []any, index int) sema.Type {
+	ty, err := r.decodeType(bytesArgument(memory, arguments, index))
+	if err != nil {
+		panic(err)
+	}
+	return ty
+}
+
+// decodeType decodes a static type encoded by the compiler
+//
+func (r *runtimeFunctions) decodeType(encoded []byte) (sema.Type, error) {
+	inter := r.interpreter()
+
+	decoder := interpreter.CBORDecMode.NewByteStreamDecoder(encoded)
+	staticType, err := interpreter.NewTypeDecoder(decoder, inter).DecodeStaticType()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode type: %w", err)
+	}
+
+	return inter.ConvertStaticToSemaType(staticType)
+}
+
+// decodeInteger decodes an integer encoded by the compiler,
+// a sign byte (0 for negative, 1 for positive),
+// followed by the big-endian bytes of the magnitude
+//
+func decodeInteger(encoded []byte) *big.Int {
+	if len(encoded) < 1 {
+		panic(fmt.Errorf("invalid integer encoding"))
+	}
+
+	value := new(big.Int).SetBytes(encoded[1:])
+	if encoded[0] == 0 {
+		value.Neg(value)
+	}
+	return value
+}
+
+// Constants
+
+func (r *runtimeFunctions) int(memory []byte, arguments []any) any {
+	return r.intValue(decodeInteger(bytesArgument(memory, arguments, 0)))
+}
+
+func (r *runtimeFunctions) intValue(value *big.Int) interpreter.Value {
+	return r.interpreter().NewIntegerValueFromBigInt(value, sema.IntType)
+}
+
+func (r *runtimeFunctions) string(memory []byte, arguments []any) any {
+	return r.stringValue(stringArgument(memory, arguments, 0))
+}
+
+func (r *runtimeFunctions) stringValue(value string) interpreter.Value {
+	// NOTE: like in the interpreter, string literals are not metered
+	return interpreter.NewUnmeteredStringValue(value)
+}
+
+func (r *runtimeFunctions) character(memory []byte, arguments []any) any {
+	return r.characterValue(stringArgument(memory, arguments, 0))
+}
+
+func (r *runtimeFunctions) characterValue(value string) interpreter.Value {
+	return interpreter.NewUnmeteredCharacterValue(value)
+}
+
+func (r *runtimeFunctions) number(memory []byte, arguments []any) any {
+	return r.numberValue(
+		r.typeArgument(memory, arguments, 0),
+		decodeInteger(bytesArgument(memory, arguments, 2)),
+	)
+}
+
+func (r *runtimeFunctions) numberValue(ty sema.Type, value *big.Int) interpreter.Value {
+	inter := r.interpreter()
+
+	switch ty {
+	case sema.Fix64Type:
+		return interpreter.NewFix64Value(inter, value.Int64)
+
+	case sema.UFix64Type:
+		return interpreter.NewUFix64Value(inter, value.Uint64)
+	}
+
+	return inter.NewIntegerValueFromBigInt(value, ty)
+}
+
+func (r *runtimeFunctions) address(memory []byte, arguments []any) any {
+	return r.addressValue(bytesArgument(memory, arguments, 0))
+}
+
+func (r *runtimeFunctions) addressValue(address []byte) interpreter.Value {
+	return interpreter.NewAddressValueFromBytes(
+		r.interpreter(),
+		func() []byte {
+			return address
+		},
+	)
+}
+
+func (r *runtimeFunctions) path(memory []byte, arguments []any) any {
+	return r.pathValue(
+		common.PathDomain(i32Argument(arguments, 0)),
+		stringArgument(memory, arguments, 1),
+	)
+}
+
+func (r *runtimeFunctions) pathValue(domain common.PathDomain, identifier string) interpreter.Value {
+	return interpreter.NewPathValue(
+		r.interpreter(),
+		domain,
+		identifier,
+	)
+}
+
+func (r *runtimeFunctions) bool(_ []byte, arguments []any) any {
+	return interpreter.NewBoolValue(r.interpreter(), boolArgument(arguments, 0))
+}
+
+func (r *runtimeFunctions) nil(_ []byte, _ []any) any {
+	return interpreter.NewNilValue(r.interpreter())
+}
+
+func (r *runtimeFunctions) void(_ []byte, _ []any) any {
+	return interpreter.NewVoidValue(r.interpreter())
+}
+
+// Operations
+
+func (r *runtimeFunctions) binaryOperation(operation ast.Operation) runtimeFunction {
+	return func(_ []byte, arguments []any) any {
+		return r.binaryOperationValue(
+			operation,
+			valueArgument(arguments, 0),
+			valueArgument(arguments, 1),
+		)
+	}
+}
+
+func (r *runtimeFunctions) binaryOperationValue(
+	operation ast.Operation,
+	left interpreter.Value,
+	right interpreter.Value,
+) interpreter.Value {
+	inter := r.interpreter()
+
+	switch operation {
+	case ast.OperationEqual:
+		return r.equal(left, right)
+
+	case ast.OperationNotEqual:
+		return !r.equal(left, right)
+	}
+
+	invalidOperands := func() {
+		panic(interpreter.InvalidOperandsError{
+			Operation:     operation,
+			LeftType:      left.StaticType(inter),
+			RightType:     right.StaticType(inter),
+			LocationRange: r.getLocationRange(),
+		})
+	}
+
+	switch operation {
+	case ast.OperationBitwiseOr,
+		ast.OperationBitwiseXor,
+		ast.OperationBitwiseAnd,
+		ast.OperationBitwiseLeftShift,
+		ast.OperationBitwiseRightShift:
+
+		leftInteger, leftOk := left.(interpreter.IntegerValue)
+		rightInteger, rightOk := right.(interpreter.IntegerValue)
+		if !leftOk || !rightOk {
+			invalidOperands()
+		}
+
+		switch operation {
+		case ast.OperationBitwiseOr:
+			return leftInteger.BitwiseOr(inter, rightInteger)
+		case ast.OperationBitwiseXor:
+			return leftInteger.BitwiseXor(inter, rightInteger)
+		case ast.OperationBitwiseAnd:
+			return leftInteger.BitwiseAnd(inter, rightInteger)
+		case ast.OperationBitwiseLeftShift:
+			return leftInteger.BitwiseLeftShift(inter, rightInteger)
+		case ast.OperationBitwiseRightShift:
+			return leftInteger.BitwiseRightShift(inter, rightInteger)
+		}
+	}
+
+	leftNumber, leftOk := left.(interpreter.NumberValue)
+	rightNumber, rightOk := right.(interpreter.NumberValue)
+	if !leftOk || !rightOk {
+		invalidOperands()
+	}
+
+	switch operation {
+	case ast.OperationPlus:
+		return leftNumber.Plus(inter, rightNumber)
+	case ast.OperationMinus:
+		return leftNumber.Minus(inter, rightNumber)
+	case ast.OperationMul:
+		return leftNumber.Mul(inter, rightNumber)
+	case ast.OperationDiv:
+		return leftNumber.Div(inter, rightNumber)
+	case ast.OperationMod:
+		return leftNumber.Mod(inter, rightNumber)
+	case ast.OperationLess:
+		return leftNumber.Less(inter, rightNumber)
+	case ast.OperationLessEqual:
+		return leftNumber.LessEqual(inter, rightNumber)
+	case ast.OperationGreater:
+		return leftNumber.Greater(inter, rightNumber)
+	case ast.OperationGreaterEqual:
+		return leftNumber.GreaterEqual(inter, rightNumber)
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+func (r *runtimeFunctions) equal(left, right interpreter.Value) interpreter.BoolValue {
+	inter := r.interpreter()
+
+	left = inter.Unbox(r.getLocationRange, left)
+	right = inter.Unbox(r.getLocationRange, right)
+
+	leftEquatable, ok := left.(interpreter.EquatableValue)
+	if !ok {
+		return false
+	}
+
+	return interpreter.NewBoolValue(
+		inter,
+		leftEquatable.Equal(inter, r.getLocationRange, right),
+	)
+}
+
+func (r *runtimeFunctions) negate(_ []byte, arguments []any) any {
+	return r.negateValue(valueArgument(arguments, 0))
+}
+
+func (r *runtimeFunctions) negateValue(value interpreter.Value) interpreter.Value {
+	number, ok := value.(interpreter.NumberValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	return number.Negate(r.interpreter())
+}
+
+func (r *runtimeFunctions) not(_ []byte, arguments []any) any {
+	return r.notValue(valueArgument(arguments, 0))
+}
+
+func (r *runtimeFunctions) notValue(value interpreter.Value) interpreter.Value {
+	boolValue, ok := value.(interpreter.BoolValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	return !boolValue
+}
+
+func (r *runtimeFunctions) force(_ []byte, arguments []any) any {
+	return r.forceValue(valueArgument(arguments, 0))
+}
+
+func (r *runtimeFunctions) forceValue(value interpreter.Value) interpreter.Value {
+	switch value := value.(type) {
+	case *interpreter.SomeValue:
+		return value.InnerValue(r.interpreter(), r.getLocationRange)
+
+	case interpreter.NilValue:
+		panic(interpreter.ForceNilError{
+			LocationRange: r.getLocationRange(),
+		})
+
+	default:
+		return value
+	}
+}
+
+func (r *runtimeFunctions) isNil(_ []byte, arguments []any) any {
+	return r.isNilValue(valueArgument(arguments, 0))
+}
+
+func (r *runtimeFunctions) isNilValue(value interpreter.Value) interpreter.Value {
+	_, ok := value.(interpreter.NilValue)
+	return interpreter.NewBoolValue(r.interpreter(), ok)
+}
+
+func (r *runtimeFunctions) some(_ []byte, arguments []any) any {
+	return r.someValue(valueArgument(arguments, 0))
+}
+
+func (r *runtimeFunctions) someValue(value interpreter.Value) interpreter.Value {
+	return interpreter.NewSomeValueNonCopying(r.interpreter(), value)
+}
+
+func (r *runtimeFunctions) transfer(_ []byte, arguments []any) any {
+	return r.transferValue(valueArgument(arguments, 0))
+}
+
+func (r *runtimeFunctions) transferValue(value interpreter.Value) interpreter.Value {
+	return value.Transfer(
+		r.interpreter(),
+		r.getLocationRange,
+		atree.Address{},
+		false,
+		nil,
+	)
+}
+
+func (r *runtimeFunctions) truthy(_ []byte, arguments []any) any {
+	if r.truthyValue(valueArgument(arguments, 0)) {
+		return int32(1)
+	}
+	return int32(0)
+}
+
+func (r *runtimeFunctions) truthyValue(value interpreter.Value) bool {
+	boolValue, ok := value.(interpreter.BoolValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+	return bool(boolValue)
+}
+
+// Globals
+
+func (r *runtimeFunctions) getGlobal(memory []byte, arguments []any) any {
+	return r.getGlobalValue(stringArgument(memory, arguments, 0))
+}
+
+func (r *runtimeFunctions) getGlobalValue(name string) interpreter.Value {
+	value, ok := r.globals[name]
+	if !ok {
+		// NOTE: the checker ensures global variables are declared,
+		// and the start function initializes all global variables
+		panic(errors.NewUnreachableError())
+	}
+	return value
+}
+
+func (r *runtimeFunctions) setGlobal(memory []byte, arguments []any) any {
+	r.setGlobalValue(
+		stringArgument(memory, arguments, 0),
+		valueArgument(arguments, 2),
+	)
+	return nil
+}
+
+func (r *runtimeFunctions) setGlobalValue(name string, value interpreter.Value) {
+	if r.globals == nil {
+		r.globals = map[string]interpreter.Value{}
+	}
+	r.globals[name] = value
+}
+
+// Members and indexing
+
+// optionalTarget returns the target of an optional chaining member access or invocation,
+// or nil if the target is nil
+//
+func (r *runtimeFunctions) optionalTarget(target interpreter.Value) interpreter.Value {
+	switch target := target.(type) {
+	case interpreter.NilValue:
+		return nil
+
+	case *interpreter.SomeValue:
+		return target.InnerValue(r.interpreter(), r.getLocationRange)
+
+	default:
+		panic(errors.NewUnreachableError())
+	}
+}
+
+func (r *runtimeFunctions) member(target interpreter.Value, name string) interpreter.Value {
+	inter := r.interpreter()
+
+	var result interpreter.Value
+
+	if memberAccessibleValue, ok := target.(interpreter.MemberAccessibleValue); ok {
+		result = memberAccessibleValue.GetMember(inter, r.getLocationRange, name)
+	}
+
+	if result == nil {
+		panic(interpreter.MissingMemberValueError{
+			Name:          name,
+			LocationRange: r.getLocationRange(),
+		})
+	}
+
+	return result
+}
+
+func (r *runtimeFunctions) getMember(memory []byte, arguments []any) any {
+	return r.getMemberValue(
+		valueArgument(arguments, 0),
+		stringArgument(memory, arguments, 1),
+		boolArgument(arguments, 3),
+	)
+}
+
+func (r *runtimeFunctions) getMemberValue(target interpreter.Value, name string, optional bool) interpreter.Value {
+	if optional {
+		target = r.optionalTarget(target)
+		if target == nil {
+			return interpreter.NewNilValue(r.interpreter())
+		}
+	}
+
+	result := r.member(target, name)
+
+	// Like in the interpreter, only wrap the result of optional chaining
+	// in an optional, if it is not already an optional value
+
+	if optional {
+		if _, ok := result.(interpreter.OptionalValue); !ok {
+			result = interpreter.NewSomeValueNonCopying(r.interpreter(), result)
+		}
+	}
+
+	return result
+}
+
+func (r *runtimeFunctions) setMember(memory []byte, arguments []any) any {
+	r.setMemberValue(
+		valueArgument(arguments, 0),
+		stringArgument(memory, arguments, 1),
+		valueArgument(arguments, 3),
+	)
+	return nil
+}
+
+func (r *runtimeFunctions) setMemberValue(target interpreter.Value, name string, value interpreter.Value) {
+	memberAccessibleValue, ok := target.(interpreter.MemberAccessibleValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	memberAccessibleValue.SetMember(r.interpreter(), r.getLocationRange, name, value)
+}
+
+func (r *runtimeFunctions) getIndex(_ []byte, arguments []any) any {
+	return r.getIndexValue(
+		valueArgument(arguments, 0),
+		valueArgument(arguments, 1),
+	)
+}
+
+func (r *runtimeFunctions) getIndexValue(target interpreter.Value, index interpreter.Value) interpreter.Value {
+	indexableValue, ok := target.(interpreter.ValueIndexableValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	return indexableValue.GetKey(r.interpreter(), r.getLocationRange, index)
+}
+
+func (r *runtimeFunctions) setIndex(_ []byte, arguments []any) any {
+	r.setIndexValue(
+		valueArgument(arguments, 0),
+		valueArgument(arguments, 1),
+		valueArgument(arguments, 2),
+	)
+	return nil
+}
+
+func (r *runtimeFunctions) setIndexValue(target interpreter.Value, index interpreter.Value, value interpreter.Value) {
+	indexableValue, ok := target.(interpreter.ValueIndexableValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	indexableValue.SetKey(r.interpreter(), r.getLocationRange, index, value)
+}
+
+// Invocations
+
+func (r *runtimeFunctions) arguments(_ []byte, _ []any) any {
+	return &argumentList{}
+}
+
+func (r *runtimeFunctions) argumentsPush(_ []byte, arguments []any) any {
+	list := listArgument(arguments, 0)
+	list.values = append(list.values, valueArgument(arguments, 1))
+	return list
+}
+
+func (r *runtimeFunctions) invoke(function interpreter.Value, arguments []interpreter.Value) interpreter.Value {
+	inter := r.interpreter()
+
+	functionValue, ok := function.(interpreter.FunctionValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	// NOTE: compiled programs have no static argument types,
+	// use the dynamic types of the arguments instead
+
+	argumentTypes := make([]sema.Type, len(arguments))
+	for i, argument := range arguments {
+		argumentTypes[i] = inter.MustConvertStaticToSemaType(argument.StaticType(inter))
+	}
+
+	invocation := interpreter.NewInvocation(
+		inter,
+		nil,
+		arguments,
+		argumentTypes,
+		nil,
+		r.getLocationRange,
+	)
+
+	result, err := inter.InvokeFunction(functionValue, invocation)
+	if err != nil {
+		panic(err)
+	}
+
+	// Every expression results in a value
+	if result == nil {
+		result = interpreter.NewVoidValue(inter)
+	}
+
+	return result
+}
+
+func (r *runtimeFunctions) callMember(memory []byte, arguments []any) any {
+	return r.callMemberValue(
+		valueArgument(arguments, 0),
+		stringArgument(memory, arguments, 1),
+		boolArgument(arguments, 3),
+		listArgument(arguments, 4).values,
+	)
+}
+
+func (r *runtimeFunctions) callMemberValue(
+	target interpreter.Value,
+	name string,
+	optional bool,
+	arguments []interpreter.Value,
+) interpreter.Value {
+	if optional {
+		target = r.optionalTarget(target)
+		if target == nil {
+			return interpreter.NewNilValue(r.interpreter())
+		}
+	}
+
+	result := r.invoke(r.member(target, name), arguments)
+
+	// Like in the interpreter, always wrap the result of optional chaining
+
+	if optional {
+		result = interpreter.NewSomeValueNonCopying(r.interpreter(), result)
+	}
+
+	return result
+}
+
+func (r *runtimeFunctions) callBuiltin(memory []byte, arguments []any) any {
+	return r.callBuiltinValue(
+		stringArgument(memory, arguments, 0),
+		listArgument(arguments, 2).values,
+	)
+}
+
+func (r *runtimeFunctions) callBuiltinValue(name string, arguments []interpreter.Value) interpreter.Value {
+	variable := r.interpreter().FindVariable(name)
+	if variable == nil {
+		panic(fmt.Errorf("unknown built-in function: %s", name))
+	}
+
+	return r.invoke(variable.GetValue(), arguments)
+}
+
+// Creation and destruction
+
+func (r *runtimeFunctions) newArray(memory []byte, arguments []any) any {
+	return r.newArrayValue(
+		r.typeArgument(memory, arguments, 0),
+		listArgument(arguments, 2).values,
+	)
+}
+
+func (r *runtimeFunctions) newArrayValue(ty sema.Type, elements []interpreter.Value) interpreter.Value {
+	inter := r.interpreter()
+
+	arrayType, ok := ty.(sema.ArrayType)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	return interpreter.NewArrayValue(
+		inter,
+		interpreter.ConvertSemaArrayTypeToStaticArrayType(inter, arrayType),
+		common.Address{},
+		elements...,
+	)
+}
+
+func (r *runtimeFunctions) newDictionary(memory []byte, arguments []any) any {
+	return r.newDictionaryValue(
+		r.typeArgument(memory, arguments, 0),
+		listArgument(arguments, 2).values,
+	)
+}
+
+// newDictionaryValue returns a new dictionary with the given entries,
+// given as alternating keys and values
+//
+func (r *runtimeFunctions) newDictionaryValue(ty sema.Type, keysAndValues []interpreter.Value) interpreter.Value {
+	inter := r.interpreter()
+
+	dictionaryType, ok := ty.(*sema.DictionaryType)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	return interpreter.NewDictionaryValue(
+		inter,
+		interpreter.ConvertSemaDictionaryTypeToStaticDictionaryType(inter, dictionaryType),
+		keysAndValues...,
+	)
+}
+
+func (r *runtimeFunctions) stringTemplate(_ []byte, arguments []any) any {
+	return r.stringTemplateValue(listArgument(arguments, 0).values)
+}
+
+// stringTemplateValue returns the string of a string template,
+// given the alternating literal parts and embedded values
+//
+func (r *runtimeFunctions) stringTemplateValue(literalsAndValues []interpreter.Value) interpreter.Value {
+	if len(literalsAndValues)%2 != 1 {
+		panic(errors.NewUnreachableError())
+	}
+
+	literals := make([]string, 0, len(literalsAndValues)/2+1)
+	values := make([]interpreter.Value, 0, len(literalsAndValues)/2)
+
+	for i, value := range literalsAndValues {
+		if i%2 == 1 {
+			values = append(values, value)
+			continue
+		}
+
+		literal, ok := value.(*interpreter.StringValue)
+		if !ok {
+			panic(errors.NewUnreachableError())
+		}
+		literals = append(literals, literal.Str)
+	}
+
+	return r.interpreter().NewStringTemplateValue(literals, values)
+}
+
+func (r *runtimeFunctions) newComposite(memory []byte, arguments []any) any {
+	return r.newCompositeValue(r.typeArgument(memory, arguments, 0))
+}
+
+func (r *runtimeFunctions) newCompositeValue(ty sema.Type) interpreter.Value {
+	inter := r.interpreter()
+
+	compositeType, ok := ty.(*sema.CompositeType)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	var fields []interpreter.CompositeField
+
+	// Like in the interpreter, resources get a unique ID
+
+	if compositeType.Kind == common.CompositeKindResource {
+		if r.config.UUIDHandler == nil {
+			panic(interpreter.UUIDUnavailableError{
+				LocationRange: r.getLocationRange(),
+			})
+		}
+
+		uuid, err := r.config.UUIDHandler()
+		if err != nil {
+			panic(err)
+		}
+
+		fields = append(
+			fields,
+			interpreter.NewCompositeField(
+				inter,
+				sema.ResourceUUIDFieldName,
+				interpreter.NewUInt64Value(
+					inter,
+					func() uint64 {
+						return uuid
+					},
+				),
+			),
+		)
+	}
+
+	// NOTE: the functions of the value are not set,
+	// compiled programs call the compiled functions directly.
+	// The compiled destructor is also called by the compiled program,
+	// so the destructor of the value must not call the interpreted destructor
+
+	value := interpreter.NewCompositeValue(
+		inter,
+		compositeType.Location,
+		compositeType.QualifiedIdentifier(),
+		compositeType.Kind,
+		fields,
+		common.Address{},
+	)
+	value.Destructor = compiledDestructor
+
+	return value
+}
+
+// compiledDestructor is the destructor of composite values created by compiled programs
+//
+var compiledDestructor = interpreter.NewUnmeteredHostFunctionValue(
+	func(invocation interpreter.Invocation) interpreter.Value {
+		return interpreter.NewVoidValue(invocation.Interpreter)
+	},
+	&sema.FunctionType{
+		ReturnTypeAnnotation: sema.NewTypeAnnotation(sema.VoidType),
+	},
+)
+
+func (r *runtimeFunctions) cast(memory []byte, arguments []any) any {
+	return r.castValue(
+		valueArgument(arguments, 0),
+		r.typeArgument(memory, arguments, 1),
+		boolArgument(arguments, 3),
+	)
+}
+
+func (r *runtimeFunctions) castValue(value interpreter.Value, expectedType sema.Type, force bool) interpreter.Value {
+	inter := r.interpreter()
+
+	isSubType := inter.IsSubTypeOfSemaType(value.StaticType(inter), expectedType)
+
+	if !force {
+		if !isSubType {
+			return interpreter.NewNilValue(inter)
+		}
+
+		// The failable cast may upcast to an optional type, e.g. `1 as? Int?`, so box
+		value = inter.BoxOptional(r.getLocationRange, value, expectedType)
+
+		return interpreter.NewSomeValueNonCopying(inter, value)
+	}
+
+	if !isSubType {
+		panic(interpreter.ForceCastTypeMismatchError{
+			ExpectedType:  expectedType,
+			LocationRange: r.getLocationRange(),
+		})
+	}
+
+	return inter.BoxOptional(r.getLocationRange, value, expectedType)
+}
+
+func (r *runtimeFunctions) isComposite(memory []byte, arguments []any) any {
+	return r.isCompositeValue(
+		valueArgument(arguments, 0),
+		r.typeArgument(memory, arguments, 1),
+	)
+}
+
+// isCompositeValue returns true if the value is a composite value of the given type,
+// or a reference to such a value
+//
+func (r *runtimeFunctions) isCompositeValue(value interpreter.Value, ty sema.Type) interpreter.Value {
+	inter := r.interpreter()
+
+	compositeType, ok := ty.(*sema.CompositeType)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	switch reference := value.(type) {
+	case *interpreter.EphemeralReferenceValue:
+		referencedValue := reference.ReferencedValue(inter, r.getLocationRange)
+		if referencedValue == nil {
+			panic(interpreter.DereferenceError{
+				LocationRange: r.getLocationRange(),
+			})
+		}
+		value = *referencedValue
+
+	case *interpreter.StorageReferenceValue:
+		referencedValue := reference.ReferencedValue(inter)
+		if referencedValue == nil {
+			panic(interpreter.DereferenceError{
+				LocationRange: r.getLocationRange(),
+			})
+		}
+		value = *referencedValue
+	}
+
+	composite, ok := value.(*interpreter.CompositeValue)
+
+	return interpreter.NewBoolValue(
+		inter,
+		ok && composite.TypeID() == compositeType.ID(),
+	)
+}
+
+func (r *runtimeFunctions) box(memory []byte, arguments []any) any {
+	return r.boxValue(
+		valueArgument(arguments, 0),
+		r.typeArgument(memory, arguments, 1),
+	)
+}
+
+func (r *runtimeFunctions) boxValue(value interpreter.Value, targetType sema.Type) interpreter.Value {
+	return r.interpreter().BoxOptional(r.getLocationRange, value, targetType)
+}
+
+func (r *runtimeFunctions) reference(memory []byte, arguments []any) any {
+	return r.referenceValue(
+		valueArgument(arguments, 0),
+		r.typeArgument(memory, arguments, 1),
+	)
+}
+
+func (r *runtimeFunctions) referenceValue(value interpreter.Value, ty sema.Type) interpreter.Value {
+	inter := r.interpreter()
+
+	switch ty := ty.(type) {
+	case *sema.ReferenceType:
+		return interpreter.NewEphemeralReferenceValue(
+			inter,
+			ty.Authorized,
+			value,
+			ty.Type,
+		)
+
+	case *sema.OptionalType:
+		// Like in the interpreter, references to optionals are optional references
+
+		referenceType, ok := ty.Type.(*sema.ReferenceType)
+		if !ok {
+			panic(errors.NewUnreachableError())
+		}
+
+		switch value := value.(type) {
+		case *interpreter.SomeValue:
+			return interpreter.NewSomeValueNonCopying(
+				inter,
+				interpreter.NewEphemeralReferenceValue(
+					inter,
+					referenceType.Authorized,
+					value.InnerValue(inter, r.getLocationRange),
+					referenceType.Type,
+				),
+			)
+
+		case interpreter.NilValue:
+			return interpreter.NewNilValue(inter)
+
+		default:
+			return inter.BoxOptional(
+				r.getLocationRange,
+				interpreter.NewEphemeralReferenceValue(
+					inter,
+					referenceType.Authorized,
+					value,
+					referenceType.Type,
+				),
+				ty,
+			)
+		}
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+func (r *runtimeFunctions) destroy(_ []byte, arguments []any) any {
+	return r.destroyValue(valueArgument(arguments, 0))
+}
+
+func (r *runtimeFunctions) destroyValue(value interpreter.Value) interpreter.Value {
+	resourceKindedValue, ok := value.(interpreter.ResourceKindedValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	inter := r.interpreter()
+	resourceKindedValue.Destroy(inter, r.getLocationRange)
+	return interpreter.NewVoidValue(inter)
+}
+
+// Statements
+
+func (r *runtimeFunctions) emit(_ []byte, arguments []any) any {
+	r.emitValue(valueArgument(arguments, 0))
+	return nil
+}
+
+func (r *runtimeFunctions) emitValue(value interpreter.Value) {
+	inter := r.interpreter()
+
+	event, ok := value.(*interpreter.CompositeValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	if r.config.OnEventEmitted == nil {
+		panic(interpreter.EventEmissionUnavailableError{
+			LocationRange: r.getLocationRange(),
+		})
+	}
+
+	eventType, ok := inter.MustConvertStaticToSemaType(event.StaticType(inter)).(*sema.CompositeType)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	err := r.config.OnEventEmitted(inter, r.getLocationRange, event, eventType)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (r *runtimeFunctions) failCondition(_ []byte, arguments []any) any {
+	r.failConditionValue(
+		ast.ConditionKind(i32Argument(arguments, 0)),
+		valueArgument(arguments, 1),
+	)
+	return nil
+}
+
+func (r *runtimeFunctions) failConditionValue(kind ast.ConditionKind, message interpreter.Value) {
+	messageValue, ok := message.(*interpreter.StringValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	panic(interpreter.ConditionError{
+		ConditionKind: kind,
+		Message:       messageValue.Str,
+		LocationRange: r.getLocationRange(),
+	})
+}
+
+// InvalidMemoryAccessError is reported when a run-time function
+// is called with a memory range outside of the memory of the instance
+//
+type InvalidMemoryAccessError struct {
+	Offset int
+	Length int
+}
+
+func (e InvalidMemoryAccessError) Error() string {
+	return fmt.Sprintf(
+		"invalid memory access: offset %d, length %d",
+		e.Offset,
+		e.Length,
+	)
+}
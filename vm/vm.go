@@ -23,49 +23,55 @@ package vm
 
 import (
 	"fmt"
-	"math/big"
-
-	"C"
 
 	"github.com/bytecodealliance/wasmtime-go"
 
+	"github.com/onflow/cadence/runtime/compiler"
 	"github.com/onflow/cadence/runtime/interpreter"
 )
 
-type VM interface {
-	Invoke(name string, arguments ...interpreter.Value) (interpreter.Value, error)
-}
-
 type vm struct {
-	instance *wasmtime.Instance
+	instance    *wasmtime.Instance
+	interpreter *interpreter.Interpreter
 }
 
-func (m *vm) Invoke(name string, arguments ...interpreter.Value) (interpreter.Value, error) {
-	f := m.instance.GetExport(name).Func()
+func (m *vm) Invoke(name string, arguments ...interpreter.Value) (result interpreter.Value, err error) {
+
+	// Like the interpreter, the run-time functions report errors by panicking.
+	// The panics are propagated through the WebAssembly frames
+
+	defer m.interpreter.RecoverErrors(func(internalErr error) {
+		err = internalErr
+	})
+
+	export := m.instance.GetExport(name)
+	if export == nil || export.Func() == nil {
+		return nil, fmt.Errorf("unknown function: %s", name)
+	}
 
 	rawArguments := make([]any, len(arguments))
 	for i, argument := range arguments {
 		rawArguments[i] = argument
 	}
 
-	res, err := f.Call(rawArguments...)
+	res, err := export.Func().Call(rawArguments...)
 	if err != nil {
 		return nil, err
 	}
 
 	if res == nil {
-		return nil, nil
+		return interpreter.NewVoidValue(m.interpreter), nil
 	}
 
 	return res.(interpreter.Value), nil
 }
 
-func NewVM(wasm []byte) (VM, error) {
+func NewVM(wasm []byte, config *Config) (_ VM, err error) {
 
-	config := wasmtime.NewConfig()
-	config.SetWasmReferenceTypes(true)
+	wasmtimeConfig := wasmtime.NewConfig()
+	wasmtimeConfig.SetWasmReferenceTypes(true)
 
-	engine := wasmtime.NewEngineWithConfig(config)
+	engine := wasmtime.NewEngineWithConfig(wasmtimeConfig)
 
 	store := wasmtime.NewStore(engine)
 
@@ -74,78 +80,85 @@ func NewVM(wasm []byte) (VM, error) {
 		return nil, err
 	}
 
-	intFunc := wasmtime.WrapFunc(
-		store,
-		func(caller *wasmtime.Caller, offset int32, length int32) (any, *wasmtime.Trap) {
-			if offset < 0 {
-				return nil, wasmtime.NewTrap(store, fmt.Sprintf("Int: invalid offset: %d", offset))
-			}
-
-			if length < 2 {
-				return nil, wasmtime.NewTrap(store, fmt.Sprintf("Int: invalid length: %d", length))
-			}
+	// Define the run-time functions imported by the module,
+	// using the function types declared by the imports
 
-			mem := caller.GetExport("mem").Memory()
+	runtimeFunctions := newRuntimeFunctions(config)
 
-			bytes := C.GoBytes(mem.Data(), C.int(length))
+	linker := wasmtime.NewLinker(store)
 
-			value := new(big.Int).SetBytes(bytes[1:])
-			if bytes[0] == 0 {
-				value = value.Neg(value)
-			}
-
-			return interpreter.NewIntValueFromBigInt(value), nil
-		},
-	)
-
-	stringFunc := wasmtime.WrapFunc(store, func(caller *wasmtime.Caller, offset int32, length int32) (any, *wasmtime.Trap) {
-		if offset < 0 {
-			return nil, wasmtime.NewTrap(store, fmt.Sprintf("String: invalid offset: %d", offset))
+	for _, importType := range module.Imports() {
+		moduleName := importType.Module()
+		namePtr := importType.Name()
+		funcType := importType.Type().FuncType()
+		if moduleName != compiler.RuntimeModuleName || namePtr == nil || funcType == nil {
+			return nil, fmt.Errorf("unsupported import: %s", moduleName)
 		}
+		name := *namePtr
 
-		if length < 0 {
-			return nil, wasmtime.NewTrap(store, fmt.Sprintf("String: invalid length: %d", length))
-		}
-
-		mem := caller.GetExport("mem").Memory()
-
-		bytes := C.GoBytes(mem.Data(), C.int(length))
-
-		return interpreter.NewStringValue(string(bytes)), nil
-	})
-
-	addFunc := wasmtime.WrapFunc(store, func(left, right any) (any, *wasmtime.Trap) {
-		leftNumber, ok := left.(interpreter.NumberValue)
+		runtimeFunction, ok := runtimeFunctions[name]
 		if !ok {
-			return nil, wasmtime.NewTrap(store, fmt.Sprintf("add: invalid left: %#+v", left))
+			return nil, fmt.Errorf("unknown run-time function: %s", name)
 		}
 
-		rightNumber, ok := right.(interpreter.NumberValue)
-		if !ok {
-			return nil, wasmtime.NewTrap(store, fmt.Sprintf("add: invalid right: %#+v", right))
+		err = linker.Define(
+			moduleName,
+			name,
+			newRuntimeFunc(store, funcType, runtimeFunction),
+		)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		return leftNumber.Plus(nil, rightNumber), nil
-	})
+	// The start function of the module, if any, is called when the module is instantiated.
+	// Like in Invoke, the run-time functions report errors by panicking
 
-	// NOTE: wasmtime currently does not support specifying imports by name,
-	// unlike other WebAssembly APIs like wasmer, JavaScript, etc.,
-	// i.e. imports are imported in the order they are given.
+	defer config.Interpreter.RecoverErrors(func(internalErr error) {
+		err = internalErr
+	})
 
-	instance, err := wasmtime.NewInstance(
-		store,
-		module,
-		[]*wasmtime.Extern{
-			intFunc.AsExtern(),
-			stringFunc.AsExtern(),
-			addFunc.AsExtern(),
-		},
-	)
+	instance, err := linker.Instantiate(module)
 	if err != nil {
 		return nil, err
 	}
 
 	return &vm{
-		instance: instance,
+		instance:    instance,
+		interpreter: config.Interpreter,
 	}, nil
 }
+
+func newRuntimeFunc(store *wasmtime.Store, funcType *wasmtime.FuncType, function runtimeFunction) *wasmtime.Func {
+	results := funcType.Results()
+
+	return wasmtime.NewFunc(
+		store,
+		funcType,
+		func(caller *wasmtime.Caller, params []wasmtime.Val) ([]wasmtime.Val, *wasmtime.Trap) {
+
+			var memory []byte
+			if export := caller.GetExport("mem"); export != nil && export.Memory() != nil {
+				memory = export.Memory().UnsafeData()
+			}
+
+			arguments := make([]any, len(params))
+			for i, param := range params {
+				arguments[i] = param.Get()
+			}
+
+			result := function(memory, arguments)
+
+			if len(results) == 0 {
+				return nil, nil
+			}
+
+			switch result := result.(type) {
+			case int32:
+				return []wasmtime.Val{wasmtime.ValI32(result)}, nil
+			default:
+				return []wasmtime.Val{wasmtime.ValExternref(result)}, nil
+			}
+		},
+	)
+}